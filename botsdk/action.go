@@ -0,0 +1,98 @@
+package botsdk
+
+import (
+	"errors"
+	"time"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/utils/rand"
+)
+
+var (
+	errNotBlocking = errors.New(`bot is not blocking the game`)
+)
+
+// buildAction asks the bot what to do about whatever is blocking it in the game
+func buildAction(bot Bot, pID model.PlayerID, g model.Game) (model.PlayerAction, error) {
+	b, ok := g.BlockingPlayers[pID]
+	if !ok {
+		return model.PlayerAction{}, errNotBlocking
+	}
+
+	pa := model.PlayerAction{
+		GameID:    g.ID,
+		ID:        pID,
+		Overcomes: b,
+	}
+	pa.SetTimeStamp(time.Now())
+
+	hand := g.Hands[pID]
+	switch b {
+	case model.DealCards:
+		pa.Action = model.DealAction{
+			NumShuffles: rand.Intn(10) + 1,
+		}
+	case model.CribCard:
+		cards, err := bot.ChooseCrib(g, hand, g.CurrentDealer == pID)
+		if err != nil {
+			return model.PlayerAction{}, err
+		}
+		pa.Action = model.BuildCribAction{
+			Cards: cards,
+		}
+	case model.CutCard:
+		pa.Action = model.CutDeckAction{
+			Percentage: rand.Float64(),
+		}
+	case model.PegCard:
+		c, sayGo, err := bot.ChoosePeg(g, getUnpeggedCards(hand, g.PeggedCards))
+		if err != nil {
+			return model.PlayerAction{}, err
+		}
+		pa.Action = model.PegAction{
+			Card:  c,
+			SayGo: sayGo,
+		}
+	case model.CountHand:
+		pts, err := bot.CountHand(g, hand)
+		if err != nil {
+			return model.PlayerAction{}, err
+		}
+		pa.Action = model.CountHandAction{
+			Pts: pts,
+		}
+	case model.CountCrib:
+		pts, err := bot.CountCrib(g, g.Crib)
+		if err != nil {
+			return model.PlayerAction{}, err
+		}
+		pa.Action = model.CountCribAction{
+			Pts: pts,
+		}
+	case model.ApproveTakeback:
+		// bots always let their opponents take back a misclick
+		pa.Action = model.ApproveTakebackAction{
+			Approve: true,
+		}
+	default:
+		return model.PlayerAction{}, errors.New(`unknown blocker`)
+	}
+
+	return pa, nil
+}
+
+func getUnpeggedCards(hand []model.Card, pc []model.PeggedCard) []model.Card {
+	pegged := make(map[model.Card]struct{}, len(pc))
+	for _, c := range pc {
+		pegged[c.Card] = struct{}{}
+	}
+
+	unpegged := make([]model.Card, 0, len(hand))
+	for _, c := range hand {
+		if _, ok := pegged[c]; ok {
+			continue
+		}
+		unpegged = append(unpegged, c)
+	}
+	return unpegged
+}
@@ -0,0 +1,28 @@
+// Package botsdk lets you write a cribbage player in Go that plays against
+// a cribbage server from anywhere. Implement Bot, and the Client takes care
+// of registering with the server, hearing when the bot needs to act, fetching
+// the game, and posting the bot's actions.
+package botsdk
+
+import (
+	"github.com/joshprzybyszewski/cribbage/model"
+)
+
+// Bot makes the decisions for a player. The game is only what the bot's player
+// is allowed to see: the other players' hands only have the cards they have pegged.
+// The Client deals and cuts the deck on the bot's behalf.
+type Bot interface {
+	// ChooseCrib returns the cards from the hand that the bot puts in the crib.
+	// It should return len(hand)-4 cards.
+	ChooseCrib(g model.Game, hand []model.Card, isDealer bool) ([]model.Card, error)
+
+	// ChoosePeg returns the card to peg from the cards the bot has not pegged yet.
+	// If none of those cards can be played, it should return sayGo.
+	ChoosePeg(g model.Game, unpegged []model.Card) (c model.Card, sayGo bool, err error)
+
+	// CountHand returns the points in the bot's hand with the cut card
+	CountHand(g model.Game, hand []model.Card) (int, error)
+
+	// CountCrib returns the points in the bot's crib with the cut card
+	CountCrib(g model.Game, crib []model.Card) (int, error)
+}
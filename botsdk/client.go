@@ -0,0 +1,292 @@
+package botsdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/network"
+)
+
+const (
+	defaultAttempts = 3
+	defaultBackoff  = 250 * time.Millisecond
+	defaultTimeout  = 10 * time.Second
+)
+
+var (
+	ErrMissingServerURL = errors.New(`botsdk: requires the server url`)
+	ErrMissingPlayerID  = errors.New(`botsdk: requires a player id`)
+	ErrMissingBot       = errors.New(`botsdk: requires a bot`)
+)
+
+// Config says how the bot talks to the server
+type Config struct {
+	// ServerURL is where the cribbage server is, like "http://localhost:8080"
+	ServerURL string
+	// PlayerID is who the bot plays as
+	PlayerID model.PlayerID
+	// Name is the display name of the bot. If it is set, Register creates
+	// the player when the server doesn't know about it yet.
+	Name string
+
+	// ListenAddr is the address that Run serves the webhook on, like ":9000"
+	ListenAddr string
+	// WebhookURL is the URL that the server POSTs notifications to. It needs
+	// to reach the webhook that the bot is serving.
+	WebhookURL string
+	// Secret is shared with the server so that the bot can trust its notifications
+	Secret string
+
+	// AcceptInvitations makes the bot accept every invitation to a game
+	AcceptInvitations bool
+
+	// Attempts is how many times a request is tried before giving up.
+	// Backoff is how long to wait before the first retry. It doubles each time.
+	Attempts int
+	Backoff  time.Duration
+}
+
+// Client plays a Bot against a cribbage server
+type Client struct {
+	cfg Config
+	bot Bot
+
+	server *http.Client
+}
+
+// New returns a Client which plays the bot as the configured player
+func New(cfg Config, bot Bot) (*Client, error) {
+	if cfg.ServerURL == `` {
+		return nil, ErrMissingServerURL
+	}
+	if cfg.PlayerID == model.InvalidPlayerID {
+		return nil, ErrMissingPlayerID
+	}
+	if bot == nil {
+		return nil, ErrMissingBot
+	}
+
+	cfg.ServerURL = strings.TrimSuffix(cfg.ServerURL, `/`)
+	if cfg.Attempts <= 0 {
+		cfg.Attempts = defaultAttempts
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = defaultBackoff
+	}
+
+	return &Client{
+		cfg: cfg,
+		bot: bot,
+		server: &http.Client{
+			Timeout: defaultTimeout,
+		},
+	}, nil
+}
+
+// Run registers the bot with the server and serves the webhook until the context is done
+func (c *Client) Run(ctx context.Context) error {
+	err := c.Register()
+	if err != nil {
+		return err
+	}
+
+	srv := &http.Server{
+		Addr:    c.cfg.ListenAddr,
+		Handler: c,
+	}
+	go func() {
+		<-ctx.Done()
+		_ = srv.Shutdown(context.Background())
+	}()
+
+	err = srv.ListenAndServe()
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+// Register creates the player if needed, and then tells the server
+// to send the player's notifications to the bot's webhook
+func (c *Client) Register() error {
+	err := network.ValidateWebhook(c.cfg.WebhookURL, c.cfg.Secret)
+	if err != nil {
+		return err
+	}
+
+	if c.cfg.Name != `` {
+		err = c.createPlayerIfNeeded()
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = c.makeJSONBodiedRequest(`POST`, `/create/interaction`, network.CreateInteractionRequest{
+		PlayerID:      c.cfg.PlayerID,
+		WebhookURL:    c.cfg.WebhookURL,
+		WebhookSecret: c.cfg.Secret,
+	})
+	return err
+}
+
+func (c *Client) createPlayerIfNeeded() error {
+	_, err := c.makeRequest(`GET`, fmt.Sprintf("/player/%s", c.cfg.PlayerID), nil)
+	if err == nil {
+		return nil
+	}
+	if se, ok := err.(*statusError); !ok || se.code != http.StatusNotFound {
+		return err
+	}
+
+	_, err = c.makeJSONBodiedRequest(`POST`, `/create/player`, network.CreatePlayerRequest{
+		Player: network.Player{
+			ID:   c.cfg.PlayerID,
+			Name: c.cfg.Name,
+		},
+	})
+	return err
+}
+
+// Act fetches the game and posts what the bot wants to do about it.
+// It does nothing if the game is not waiting on the bot.
+func (c *Client) Act(gID model.GameID) error {
+	pa, err := c.getAction(gID)
+	if err != nil {
+		if err == errNotBlocking {
+			return nil
+		}
+		return err
+	}
+
+	_, err = c.makeJSONBodiedRequest(`POST`, `/action`, pa)
+	return err
+}
+
+// getAction fetches the game and asks the bot what it wants to do. The server may
+// notify us before it has saved the game, so we check again if it isn't waiting on us yet.
+func (c *Client) getAction(gID model.GameID) (model.PlayerAction, error) {
+	backoff := c.cfg.Backoff
+	for attempt := 1; ; attempt++ {
+		g, err := c.getGame(gID)
+		if err != nil {
+			return model.PlayerAction{}, err
+		}
+
+		pa, err := buildAction(c.bot, c.cfg.PlayerID, g)
+		if err != errNotBlocking || attempt >= c.cfg.Attempts {
+			return pa, err
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (c *Client) getGame(gID model.GameID) (model.Game, error) {
+	respBytes, err := c.makeRequest(`GET`, fmt.Sprintf("/game/%d?player=%s", gID, c.cfg.PlayerID), nil)
+	if err != nil {
+		return model.Game{}, err
+	}
+
+	var ggr network.GetGameResponse
+	err = json.Unmarshal(respBytes, &ggr)
+	if err != nil {
+		return model.Game{}, err
+	}
+
+	return network.ConvertFromGetGameResponse(ggr), nil
+}
+
+func (c *Client) acceptInvitation(lID model.LobbyID) error {
+	_, err := c.makeJSONBodiedRequest(`POST`, fmt.Sprintf("/lobby/%d/accept", lID), network.LobbyPlayerRequest{
+		PlayerID: c.cfg.PlayerID,
+	})
+	return err
+}
+
+func (c *Client) makeJSONBodiedRequest(method, apiURL string, v interface{}) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return c.makeRequest(method, apiURL, b)
+}
+
+// makeRequest sends the request to the server. It retries with a growing backoff
+// when the server couldn't be reached or had an error of its own.
+func (c *Client) makeRequest(method, apiURL string, body []byte) ([]byte, error) {
+	backoff := c.cfg.Backoff
+	for attempt := 1; ; attempt++ {
+		respBytes, err := c.doRequest(method, apiURL, body)
+		if err == nil || !isRetryable(err) || attempt >= c.cfg.Attempts {
+			return respBytes, err
+		}
+
+		log.Printf("botsdk: retrying %s %s after error: %v\n", method, apiURL, err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (c *Client) doRequest(method, apiURL string, body []byte) ([]byte, error) {
+	var data io.Reader
+	if body != nil {
+		data = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, c.cfg.ServerURL+apiURL, data)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set(`Content-Type`, `application/json`)
+	}
+
+	response, err := c.server.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	resBytes, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, &statusError{
+			code: response.StatusCode,
+			msg:  string(resBytes),
+		}
+	}
+
+	return resBytes, nil
+}
+
+// statusError is returned when the server responds with something other than OK
+type statusError struct {
+	code int
+	msg  string
+}
+
+func (se *statusError) Error() string {
+	return fmt.Sprintf("bad response (%d): %q", se.code, se.msg)
+}
+
+func isRetryable(err error) bool {
+	se, ok := err.(*statusError)
+	if !ok {
+		// we couldn't reach the server
+		return true
+	}
+	return se.code == http.StatusTooManyRequests || se.code >= http.StatusInternalServerError
+}
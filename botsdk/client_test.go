@@ -0,0 +1,296 @@
+package botsdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshprzybyszewski/cribbage/jsonutils"
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/network"
+)
+
+const (
+	testBotID  model.PlayerID = `testbot`
+	testSecret                = `this is a test secret`
+)
+
+var _ Bot = (*stubBot)(nil)
+
+type stubBot struct {
+	crib     []model.Card
+	peg      model.Card
+	sayGo    bool
+	unpegged []model.Card
+	pts      int
+}
+
+func (sb *stubBot) ChooseCrib(_ model.Game, _ []model.Card, _ bool) ([]model.Card, error) {
+	return sb.crib, nil
+}
+
+func (sb *stubBot) ChoosePeg(_ model.Game, unpegged []model.Card) (model.Card, bool, error) {
+	sb.unpegged = unpegged
+	return sb.peg, sb.sayGo, nil
+}
+
+func (sb *stubBot) CountHand(_ model.Game, _ []model.Card) (int, error) {
+	return sb.pts, nil
+}
+
+func (sb *stubBot) CountCrib(_ model.Game, _ []model.Card) (int, error) {
+	return sb.pts, nil
+}
+
+func cards(strs ...string) []model.Card {
+	cs := make([]model.Card, len(strs))
+	for i, s := range strs {
+		cs[i] = model.NewCardFromString(s)
+	}
+	return cs
+}
+
+func newTestGame(b model.Blocker) model.Game {
+	return model.Game{
+		ID: model.GameID(42),
+		Players: []model.Player{
+			{ID: testBotID, Name: `bot`},
+			{ID: `alice`, Name: `alice`},
+		},
+		CurrentDealer: `alice`,
+		BlockingPlayers: map[model.PlayerID]model.Blocker{
+			testBotID: b,
+		},
+		Hands: map[model.PlayerID][]model.Card{
+			testBotID: cards(`AH`, `2H`, `3H`, `4H`, `5H`, `6H`),
+			`alice`:   cards(`AC`, `2C`, `3C`, `4C`, `5C`, `6C`),
+		},
+		CutCard: model.NewCardFromString(`JD`),
+	}
+}
+
+func TestBuildAction(t *testing.T) {
+	bot := &stubBot{
+		crib:  cards(`AH`, `2H`),
+		peg:   model.NewCardFromString(`4H`),
+		sayGo: false,
+		pts:   7,
+	}
+
+	g := newTestGame(model.CribCard)
+	pa, err := buildAction(bot, testBotID, g)
+	require.NoError(t, err)
+	assert.Equal(t, g.ID, pa.GameID)
+	assert.Equal(t, testBotID, pa.ID)
+	assert.Equal(t, model.CribCard, pa.Overcomes)
+	assert.Equal(t, model.BuildCribAction{Cards: bot.crib}, pa.Action)
+
+	g = newTestGame(model.PegCard)
+	g.PeggedCards = []model.PeggedCard{
+		model.NewPeggedCard(testBotID, model.NewCardFromString(`3H`), 0),
+		model.NewPeggedCard(`alice`, model.NewCardFromString(`3C`), 1),
+	}
+	pa, err = buildAction(bot, testBotID, g)
+	require.NoError(t, err)
+	assert.Equal(t, model.PegAction{Card: bot.peg}, pa.Action)
+	assert.Equal(t, cards(`AH`, `2H`, `4H`, `5H`, `6H`), bot.unpegged)
+
+	pa, err = buildAction(bot, testBotID, newTestGame(model.CountHand))
+	require.NoError(t, err)
+	assert.Equal(t, model.CountHandAction{Pts: 7}, pa.Action)
+
+	pa, err = buildAction(bot, testBotID, newTestGame(model.CountCrib))
+	require.NoError(t, err)
+	assert.Equal(t, model.CountCribAction{Pts: 7}, pa.Action)
+
+	pa, err = buildAction(bot, testBotID, newTestGame(model.DealCards))
+	require.NoError(t, err)
+	assert.IsType(t, model.DealAction{}, pa.Action)
+
+	pa, err = buildAction(bot, testBotID, newTestGame(model.CutCard))
+	require.NoError(t, err)
+	assert.IsType(t, model.CutDeckAction{}, pa.Action)
+
+	g = newTestGame(model.CribCard)
+	delete(g.BlockingPlayers, testBotID)
+	_, err = buildAction(bot, testBotID, g)
+	assert.Equal(t, errNotBlocking, err)
+}
+
+// fakeServer acts like the cribbage server for the endpoints that the bot uses
+type fakeServer struct {
+	t *testing.T
+
+	lock           sync.Mutex
+	game           model.Game
+	failActions    int
+	actionAttempts int
+	interactions   []network.CreateInteractionRequest
+	createdPlayers []network.CreatePlayerRequest
+
+	actions chan model.PlayerAction
+}
+
+func newFakeServer(t *testing.T, g model.Game) (*fakeServer, *httptest.Server) {
+	fs := &fakeServer{
+		t:       t,
+		game:    g,
+		actions: make(chan model.PlayerAction, 1),
+	}
+	ts := httptest.NewServer(fs)
+	t.Cleanup(ts.Close)
+	return fs, ts
+}
+
+func (fs *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	body, err := ioutil.ReadAll(r.Body)
+	require.NoError(fs.t, err)
+
+	switch {
+	case r.URL.Path == `/create/interaction`:
+		var cir network.CreateInteractionRequest
+		require.NoError(fs.t, json.Unmarshal(body, &cir))
+		fs.interactions = append(fs.interactions, cir)
+	case r.URL.Path == `/create/player`:
+		var cpr network.CreatePlayerRequest
+		require.NoError(fs.t, json.Unmarshal(body, &cpr))
+		fs.createdPlayers = append(fs.createdPlayers, cpr)
+	case strings.HasPrefix(r.URL.Path, `/player/`):
+		http.Error(w, `Player not found`, http.StatusNotFound)
+		return
+	case r.URL.Path == fmt.Sprintf("/game/%d", fs.game.ID):
+		resp, err := network.ConvertToGetGameResponseForPlayer(fs.game, model.PlayerID(r.URL.Query().Get(`player`)))
+		require.NoError(fs.t, err)
+		require.NoError(fs.t, json.NewEncoder(w).Encode(resp))
+		return
+	case r.URL.Path == `/action`:
+		fs.actionAttempts++
+		if fs.actionAttempts <= fs.failActions {
+			http.Error(w, `try again later`, http.StatusServiceUnavailable)
+			return
+		}
+		pa, err := jsonutils.UnmarshalPlayerAction(body)
+		require.NoError(fs.t, err)
+		fs.actions <- pa
+	default:
+		http.Error(w, `not found`, http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func newTestClient(t *testing.T, serverURL string, bot Bot) *Client {
+	c, err := New(Config{
+		ServerURL:  serverURL,
+		PlayerID:   testBotID,
+		Name:       `Test Bot`,
+		WebhookURL: `http://localhost:9000/`,
+		Secret:     testSecret,
+		Backoff:    time.Millisecond,
+	}, bot)
+	require.NoError(t, err)
+	return c
+}
+
+func TestNewRequiresConfig(t *testing.T) {
+	_, err := New(Config{PlayerID: testBotID}, &stubBot{})
+	assert.Equal(t, ErrMissingServerURL, err)
+
+	_, err = New(Config{ServerURL: `http://localhost:8080`}, &stubBot{})
+	assert.Equal(t, ErrMissingPlayerID, err)
+
+	_, err = New(Config{ServerURL: `http://localhost:8080`, PlayerID: testBotID}, nil)
+	assert.Equal(t, ErrMissingBot, err)
+}
+
+func TestRegister(t *testing.T) {
+	fs, ts := newFakeServer(t, newTestGame(model.CribCard))
+	c := newTestClient(t, ts.URL, &stubBot{})
+
+	require.NoError(t, c.Register())
+	assert.Equal(t, []network.CreatePlayerRequest{{
+		Player: network.Player{
+			ID:   testBotID,
+			Name: `Test Bot`,
+		},
+	}}, fs.createdPlayers)
+	assert.Equal(t, []network.CreateInteractionRequest{{
+		PlayerID:      testBotID,
+		WebhookURL:    `http://localhost:9000/`,
+		WebhookSecret: testSecret,
+	}}, fs.interactions)
+
+	c.cfg.Secret = `shh`
+	assert.Equal(t, network.ErrInvalidWebhookSecret, c.Register())
+}
+
+func postWebhook(t *testing.T, c *Client, secret string, p network.WebhookPayload) int {
+	body, err := json.Marshal(p)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(`POST`, `/`, strings.NewReader(string(body)))
+	req.Header.Set(network.WebhookSignatureHeader, network.SignWebhookPayload(secret, body))
+	w := httptest.NewRecorder()
+	c.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestWebhookRejectsBadSignatures(t *testing.T) {
+	_, ts := newFakeServer(t, newTestGame(model.CribCard))
+	c := newTestClient(t, ts.URL, &stubBot{})
+
+	code := postWebhook(t, c, `not the shared secret`, network.WebhookPayload{
+		Event:    network.BlockingEvent,
+		PlayerID: testBotID,
+	})
+	assert.Equal(t, http.StatusUnauthorized, code)
+}
+
+func TestWebhookBlockingPostsAction(t *testing.T) {
+	g := newTestGame(model.CribCard)
+	fs, ts := newFakeServer(t, g)
+	// the server has trouble the first time, so the client needs to retry
+	fs.failActions = 1
+
+	bot := &stubBot{
+		crib: cards(`5H`, `6H`),
+	}
+	c := newTestClient(t, ts.URL, bot)
+
+	view, err := network.ConvertToGetGameResponseForPlayer(g, testBotID)
+	require.NoError(t, err)
+	code := postWebhook(t, c, testSecret, network.WebhookPayload{
+		Event:    network.BlockingEvent,
+		PlayerID: testBotID,
+		Blocker:  model.CribCard.String(),
+		Game:     &view,
+	})
+	require.Equal(t, http.StatusOK, code)
+
+	select {
+	case pa := <-fs.actions:
+		assert.Equal(t, g.ID, pa.GameID)
+		assert.Equal(t, testBotID, pa.ID)
+		assert.Equal(t, model.CribCard, pa.Overcomes)
+		assert.Equal(t, model.BuildCribAction{Cards: bot.crib}, pa.Action)
+	case <-time.After(time.Second):
+		t.Fatal(`the bot never posted its action`)
+	}
+
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+	assert.Equal(t, 2, fs.actionAttempts)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+
+	"github.com/joshprzybyszewski/cribbage/botsdk"
+	"github.com/joshprzybyszewski/cribbage/logic/scorer"
+	"github.com/joshprzybyszewski/cribbage/logic/strategy"
+	"github.com/joshprzybyszewski/cribbage/model"
+)
+
+var (
+	serverURL  = flag.String(`server`, `http://localhost:8080`, `the url of the cribbage server`)
+	playerID   = flag.String(`id`, `examplebot`, `the username the bot plays as`)
+	name       = flag.String(`name`, `Example Bot`, `the display name of the bot`)
+	listenAddr = flag.String(`listen`, `:9000`, `the address to serve the webhook on`)
+	webhookURL = flag.String(`webhook`, `http://localhost:9000/`, `the url where the server can reach the webhook`)
+	secret     = flag.String(`secret`, `change-me-to-something-secret`, `the secret shared with the server`)
+)
+
+var _ botsdk.Bot = greedyBot{}
+
+// greedyBot keeps the hand with the most potential and pegs the most points it can right now
+type greedyBot struct{}
+
+func (greedyBot) ChooseCrib(_ model.Game, hand []model.Card, isDealer bool) ([]model.Card, error) {
+	if isDealer {
+		return strategy.GiveCribHighestPotential(len(hand)-4, hand)
+	}
+	return strategy.KeepHandHighestPotential(len(hand)-4, hand)
+}
+
+func (greedyBot) ChoosePeg(g model.Game, unpegged []model.Card) (model.Card, bool, error) {
+	c, sayGo := strategy.PegHighestCardNow(unpegged, g.PeggedCards, g.CurrentPeg())
+	return c, sayGo, nil
+}
+
+func (greedyBot) CountHand(g model.Game, hand []model.Card) (int, error) {
+	return scorer.HandPoints(g.CutCard, hand), nil
+}
+
+func (greedyBot) CountCrib(g model.Game, crib []model.Card) (int, error) {
+	return scorer.CribPoints(g.CutCard, crib), nil
+}
+
+func main() {
+	flag.Parse()
+
+	c, err := botsdk.New(botsdk.Config{
+		ServerURL:         *serverURL,
+		PlayerID:          model.PlayerID(*playerID),
+		Name:              *name,
+		ListenAddr:        *listenAddr,
+		WebhookURL:        *webhookURL,
+		Secret:            *secret,
+		AcceptInvitations: true,
+	}, greedyBot{})
+	if err != nil {
+		panic(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt)
+		<-sig
+		cancel()
+	}()
+
+	err = c.Run(ctx)
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,66 @@
+package botsdk
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+
+	"github.com/joshprzybyszewski/cribbage/network"
+)
+
+var _ http.Handler = (*Client)(nil)
+
+// ServeHTTP handles the notifications that the server POSTs to the bot's webhook.
+// It only trusts the payloads which were signed with the shared secret.
+func (c *Client) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, `webhook only accepts POST`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sig := r.Header.Get(network.WebhookSignatureHeader)
+	if !network.VerifyWebhookSignature(c.cfg.Secret, body, sig) {
+		http.Error(w, `invalid signature`, http.StatusUnauthorized)
+		return
+	}
+
+	var p network.WebhookPayload
+	err = json.Unmarshal(body, &p)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// The server is waiting on us to respond before it finishes handling
+	// the last action, so we act after we've told it that we heard it.
+	go c.handlePayload(p)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (c *Client) handlePayload(p network.WebhookPayload) {
+	var err error
+	switch p.Event {
+	case network.BlockingEvent:
+		if p.Game == nil {
+			return
+		}
+		err = c.Act(p.Game.ID)
+	case network.InvitationEvent:
+		if !c.cfg.AcceptInvitations {
+			return
+		}
+		err = c.acceptInvitation(p.LobbyID)
+	}
+
+	if err != nil {
+		log.Printf("botsdk: could not handle %s notification: %v\n", p.Event, err)
+	}
+}
@@ -7,8 +7,7 @@ import (
 )
 
 // UnmarshalGame takes in json marshaled bytes of a model.Game
-// The main advantage is that the list of actions can be deserialized
-// into the interface{} type.
+// The main advantage is that the maps the game needs are never nil.
 func UnmarshalGame(b []byte) (model.Game, error) {
 	game := model.Game{}
 
@@ -17,20 +16,6 @@ func UnmarshalGame(b []byte) (model.Game, error) {
 		return model.Game{}, err
 	}
 
-	for i := range game.Actions {
-		a := game.Actions[i]
-		b, err := json.Marshal(a)
-		if err != nil {
-			return model.Game{}, err
-		}
-
-		pa, err := UnmarshalPlayerAction(b)
-		if err != nil {
-			return model.Game{}, err
-		}
-		game.Actions[i] = pa
-	}
-
 	if game.Hands == nil {
 		game.Hands = make(map[model.PlayerID][]model.Card, len(game.Players))
 	}
@@ -1,6 +1,7 @@
 package jsonutils
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
@@ -124,7 +125,7 @@ func TestGameAtAllStages(t *testing.T) {
 	require.NoError(t, err)
 	checkMarshalUnmarshal(t, g, `after creation`)
 
-	require.NoError(t, play.HandleAction(&g, model.PlayerAction{
+	require.NoError(t, play.HandleAction(context.Background(), &g, model.PlayerAction{
 		ID:        alice.ID,
 		GameID:    g.ID,
 		Overcomes: model.DealCards,
@@ -150,7 +151,7 @@ func TestGameAtAllStages(t *testing.T) {
 	}
 	checkMarshalUnmarshal(t, g, `after deal`)
 
-	require.NoError(t, play.HandleAction(&g, model.PlayerAction{
+	require.NoError(t, play.HandleAction(context.Background(), &g, model.PlayerAction{
 		ID:        alice.ID,
 		GameID:    g.ID,
 		Overcomes: model.CribCard,
@@ -158,7 +159,7 @@ func TestGameAtAllStages(t *testing.T) {
 	}, pAPIs))
 	checkMarshalUnmarshal(t, g, `after crib from alice`)
 
-	require.NoError(t, play.HandleAction(&g, model.PlayerAction{
+	require.NoError(t, play.HandleAction(context.Background(), &g, model.PlayerAction{
 		ID:        bob.ID,
 		GameID:    g.ID,
 		Overcomes: model.CribCard,
@@ -166,7 +167,7 @@ func TestGameAtAllStages(t *testing.T) {
 	}, pAPIs))
 	checkMarshalUnmarshal(t, g, `after crib from bob`)
 
-	require.NoError(t, play.HandleAction(&g, model.PlayerAction{
+	require.NoError(t, play.HandleAction(context.Background(), &g, model.PlayerAction{
 		ID:        bob.ID,
 		GameID:    g.ID,
 		Overcomes: model.CutCard,
@@ -174,7 +175,7 @@ func TestGameAtAllStages(t *testing.T) {
 	}, pAPIs))
 	checkMarshalUnmarshal(t, g, `after cut from bob`)
 
-	require.NoError(t, play.HandleAction(&g, model.PlayerAction{
+	require.NoError(t, play.HandleAction(context.Background(), &g, model.PlayerAction{
 		ID:        bob.ID,
 		GameID:    g.ID,
 		Overcomes: model.PegCard,
@@ -182,7 +183,7 @@ func TestGameAtAllStages(t *testing.T) {
 	}, pAPIs))
 	checkMarshalUnmarshal(t, g, `after bob pegs`)
 
-	require.NoError(t, play.HandleAction(&g, model.PlayerAction{
+	require.NoError(t, play.HandleAction(context.Background(), &g, model.PlayerAction{
 		ID:        alice.ID,
 		GameID:    g.ID,
 		Overcomes: model.PegCard,
@@ -190,7 +191,7 @@ func TestGameAtAllStages(t *testing.T) {
 	}, pAPIs))
 	checkMarshalUnmarshal(t, g, `after alice pegs`)
 
-	require.NoError(t, play.HandleAction(&g, model.PlayerAction{
+	require.NoError(t, play.HandleAction(context.Background(), &g, model.PlayerAction{
 		ID:        bob.ID,
 		GameID:    g.ID,
 		Overcomes: model.PegCard,
@@ -198,7 +199,7 @@ func TestGameAtAllStages(t *testing.T) {
 	}, pAPIs))
 	checkMarshalUnmarshal(t, g, `after bob pegs`)
 
-	require.NoError(t, play.HandleAction(&g, model.PlayerAction{
+	require.NoError(t, play.HandleAction(context.Background(), &g, model.PlayerAction{
 		ID:        alice.ID,
 		GameID:    g.ID,
 		Overcomes: model.PegCard,
@@ -206,7 +207,7 @@ func TestGameAtAllStages(t *testing.T) {
 	}, pAPIs))
 	checkMarshalUnmarshal(t, g, `after alice pegs`)
 
-	require.NoError(t, play.HandleAction(&g, model.PlayerAction{
+	require.NoError(t, play.HandleAction(context.Background(), &g, model.PlayerAction{
 		ID:        bob.ID,
 		GameID:    g.ID,
 		Overcomes: model.PegCard,
@@ -214,7 +215,7 @@ func TestGameAtAllStages(t *testing.T) {
 	}, pAPIs))
 	checkMarshalUnmarshal(t, g, `after bob pegs`)
 
-	require.NoError(t, play.HandleAction(&g, model.PlayerAction{
+	require.NoError(t, play.HandleAction(context.Background(), &g, model.PlayerAction{
 		ID:        alice.ID,
 		GameID:    g.ID,
 		Overcomes: model.PegCard,
@@ -222,7 +223,7 @@ func TestGameAtAllStages(t *testing.T) {
 	}, pAPIs))
 	checkMarshalUnmarshal(t, g, `after alice pegs`)
 
-	require.NoError(t, play.HandleAction(&g, model.PlayerAction{
+	require.NoError(t, play.HandleAction(context.Background(), &g, model.PlayerAction{
 		ID:        bob.ID,
 		GameID:    g.ID,
 		Overcomes: model.PegCard,
@@ -230,7 +231,7 @@ func TestGameAtAllStages(t *testing.T) {
 	}, pAPIs))
 	checkMarshalUnmarshal(t, g, `after bob pegs`)
 
-	require.NoError(t, play.HandleAction(&g, model.PlayerAction{
+	require.NoError(t, play.HandleAction(context.Background(), &g, model.PlayerAction{
 		ID:        alice.ID,
 		GameID:    g.ID,
 		Overcomes: model.PegCard,
@@ -238,7 +239,7 @@ func TestGameAtAllStages(t *testing.T) {
 	}, pAPIs))
 	checkMarshalUnmarshal(t, g, `after alice pegs`)
 
-	require.NoError(t, play.HandleAction(&g, model.PlayerAction{
+	require.NoError(t, play.HandleAction(context.Background(), &g, model.PlayerAction{
 		ID:        bob.ID,
 		GameID:    g.ID,
 		Overcomes: model.CountHand,
@@ -246,7 +247,7 @@ func TestGameAtAllStages(t *testing.T) {
 	}, pAPIs))
 	checkMarshalUnmarshal(t, g, `after bob scores`)
 
-	require.NoError(t, play.HandleAction(&g, model.PlayerAction{
+	require.NoError(t, play.HandleAction(context.Background(), &g, model.PlayerAction{
 		ID:        alice.ID,
 		GameID:    g.ID,
 		Overcomes: model.CountHand,
@@ -254,7 +255,7 @@ func TestGameAtAllStages(t *testing.T) {
 	}, pAPIs))
 	checkMarshalUnmarshal(t, g, `after alice scores`)
 
-	require.NoError(t, play.HandleAction(&g, model.PlayerAction{
+	require.NoError(t, play.HandleAction(context.Background(), &g, model.PlayerAction{
 		ID:        alice.ID,
 		GameID:    g.ID,
 		Overcomes: model.CountCrib,
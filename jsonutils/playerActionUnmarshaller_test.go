@@ -88,6 +88,16 @@ func TestUnmarshalPlayerAction(t *testing.T) {
 				Pts: 12,
 			},
 		},
+	}, {
+		msg: `forfeit`,
+		pa: model.PlayerAction{
+			GameID:    model.GameID(4),
+			ID:        model.PlayerID(`harriet`),
+			Overcomes: model.Forfeit,
+			Action: model.ForfeitAction{
+				TimedOut: true,
+			},
+		},
 	}}
 
 	for _, tc := range testCases {
@@ -1,8 +1,6 @@
 package localclient
 
 import (
-	"bytes"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
@@ -10,7 +8,6 @@ import (
 	survey "github.com/AlecAivazis/survey/v2"
 
 	"github.com/joshprzybyszewski/cribbage/model"
-	"github.com/joshprzybyszewski/cribbage/utils/rand"
 )
 
 var (
@@ -33,15 +30,7 @@ func (tc *terminalClient) requestAndSendAction(gID model.GameID) error {
 		}
 		return err
 	}
-	b, err := json.Marshal(pa)
-	if err != nil {
-		return err
-	}
-	buf := bytes.NewBuffer(b)
-
-	url := fmt.Sprintf("/action/%d", g.ID)
-	_, err = tc.makeRequest(`POST`, url, buf, nil)
-	return err
+	return tc.server.SendAction(pa)
 }
 
 func (tc *terminalClient) getPlayerAction(g model.Game) (model.PlayerAction, error) {
@@ -66,6 +55,8 @@ func (tc *terminalClient) getPlayerAction(g model.Game) (model.PlayerAction, err
 		action = tc.getCountHandAction(g)
 	case model.CountCrib:
 		action = tc.getCountCribAction(g)
+	case model.ApproveTakeback:
+		action = tc.getApproveTakebackAction()
 	}
 
 	return model.PlayerAction{
@@ -137,13 +128,10 @@ func (tc *terminalClient) getBuildCribAction(g model.Game) model.BuildCribAction
 	}
 }
 func (tc *terminalClient) getCutDeckAction() model.CutDeckAction {
-	const thin = `thin`
-	const middle = `middle`
-	const thick = `thick`
 	cutChoice := ``
 	prompt := &survey.Select{
 		Message: "How would you like to cut?",
-		Options: []string{thin, middle, thick},
+		Options: cutChoices,
 		Filter:  func(filter string, value string, index int) bool { return true },
 	}
 	err := survey.AskOne(prompt, &cutChoice, survey.WithValidator(survey.Required))
@@ -152,18 +140,8 @@ func (tc *terminalClient) getCutDeckAction() model.CutDeckAction {
 		return model.CutDeckAction{}
 	}
 
-	perc := 0.500
-	switch cutChoice {
-	case thin:
-		perc = (rand.Float64() + 0) / 3
-	case middle:
-		perc = (rand.Float64() + 1) / 3
-	case thick:
-		perc = (rand.Float64() + 2) / 3
-	}
-
 	return model.CutDeckAction{
-		Percentage: perc,
+		Percentage: cutPercentage(cutChoice),
 	}
 }
 func (tc *terminalClient) getPegAction(g model.Game) model.PegAction {
@@ -281,6 +259,23 @@ func (tc *terminalClient) getCountCribAction(g model.Game) model.CountCribAction
 	}
 }
 
+func (tc *terminalClient) getApproveTakebackAction() model.ApproveTakebackAction {
+	approve := true
+	prompt := &survey.Confirm{
+		Message: `Let them take back their last action?`,
+		Default: true,
+	}
+	err := survey.AskOne(prompt, &approve)
+	if err != nil {
+		fmt.Printf("survey.AskOne error: %+v\n", err)
+		return model.ApproveTakebackAction{}
+	}
+
+	return model.ApproveTakebackAction{
+		Approve: approve,
+	}
+}
+
 func (tc *terminalClient) printCurrentScore() {
 	g := tc.myGames[tc.myCurrentGame]
 	fmt.Println(gameScoreMessage(g, tc.me.ID))
@@ -4,6 +4,22 @@ import (
 	"flag"
 
 	"github.com/joshprzybyszewski/cribbage/localclient"
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/interaction"
+)
+
+var (
+	serverURL = flag.String(`server`, `http://localhost:8080`,
+		`the URL of the cribbage server to play on`)
+	callback = flag.Bool(`callback`, false,
+		`have the server call this machine with updates instead of polling for them (only works when the server is on this machine)`) //nolint:lll
+
+	offline = flag.Bool(`offline`, false,
+		`play against an NPC without a server`)
+	npc = flag.String(`npc`, string(interaction.Calc),
+		`the NPC to play against offline: DumbNPC, SimpleNPC, or CalculatedNPC`)
+	saveFile = flag.String(`save`, ``,
+		`the file to save the offline game to, and to pick it back up from`)
 )
 
 func main() {
@@ -16,5 +32,15 @@ func main() {
 }
 
 func runClient() error {
-	return localclient.StartTerminalInteraction()
+	if *offline {
+		return localclient.StartOfflineGame(localclient.OfflineConfig{
+			Opponent: model.PlayerID(*npc),
+			SaveFile: *saveFile,
+		})
+	}
+
+	return localclient.StartTerminalInteraction(localclient.Config{
+		ServerURL: *serverURL,
+		Callback:  *callback,
+	})
 }
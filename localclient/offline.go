@@ -0,0 +1,384 @@
+package localclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/network"
+	"github.com/joshprzybyszewski/cribbage/server/interaction"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+	"github.com/joshprzybyszewski/cribbage/server/persistence/memory"
+	"github.com/joshprzybyszewski/cribbage/server/play"
+)
+
+const (
+	offlinePlayerID model.PlayerID = `you`
+)
+
+var (
+	errNoInvitationsOffline = errors.New(`there are no invitations when playing offline`)
+)
+
+// OfflineConfig says how to play a game without a server
+type OfflineConfig struct {
+	// Opponent is the NPC to play against, like interaction.Calc
+	Opponent model.PlayerID
+	// SaveFile is where the game is saved after every action. If it has a game that
+	// isn't over yet, we pick up where it left off. Empty means we don't save the game.
+	SaveFile string
+}
+
+// StartOfflineGame plays a game against an NPC entirely in this process, so that
+// there's no server or database to set up
+func StartOfflineGame(cfg OfflineConfig) error {
+	if !interaction.IsNPC(cfg.Opponent) {
+		return interaction.ErrUnknownNPCType
+	}
+
+	tc := terminalClient{
+		me: model.Player{
+			ID:   offlinePlayerID,
+			Name: `You`,
+		},
+		myGames: make(map[model.GameID]model.Game),
+		reqChan: make(chan terminalRequest, 5),
+	}
+
+	me := newOfflinePlayer(tc.me.ID)
+	go me.forward(tc.reqChan)
+
+	s := newOfflineServer(me, cfg.SaveFile)
+	g, err := s.start(tc.me, cfg.Opponent)
+	if err != nil {
+		return err
+	}
+	tc.server = s
+	tc.myGames[g.ID] = g
+	tc.myCurrentGame = g.ID
+
+	tc.reqChan <- terminalRequest{
+		game: g,
+		msg:  `Starting offline game with ` + gamePlayerNames(g),
+		req:  message,
+	}
+
+	tc.play()
+	return nil
+}
+
+var _ gameServer = (*offlineServer)(nil)
+var _ interaction.ActionHandler = (*offlineServer)(nil)
+
+// offlineServer handles the actions for a game the same way that the real server
+// does, but it keeps the game in memory (and, optionally, a file)
+type offlineServer struct {
+	lock sync.Mutex
+
+	db    persistence.DB
+	pAPIs map[model.PlayerID]interaction.Player
+	me    *offlinePlayer
+
+	saveFile string
+	// history is the actions from before the game was loaded from the save file
+	history []model.PlayerAction
+}
+
+func newOfflineServer(me *offlinePlayer, saveFile string) *offlineServer {
+	// the memory DB never fails to be made
+	db, _ := memory.NewFactory(play.Replay).New(context.Background())
+	return &offlineServer{
+		db:       db,
+		me:       me,
+		saveFile: saveFile,
+	}
+}
+
+// start loads the game from the save file, or starts a new one
+func (s *offlineServer) start(me model.Player, opponent model.PlayerID) (model.Game, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	g, ok, err := s.load()
+	if err != nil {
+		return model.Game{}, err
+	}
+	if ok {
+		return g, s.resume(g)
+	}
+
+	players := []model.Player{me, {
+		ID:   opponent,
+		Name: string(opponent),
+	}}
+	err = s.setPlayers(players)
+	if err != nil {
+		return model.Game{}, err
+	}
+
+	g, err = play.CreateGame(players, s.pAPIs)
+	if err != nil {
+		return model.Game{}, err
+	}
+
+	err = s.db.CreateGame(context.Background(), g)
+	if err != nil {
+		return model.Game{}, err
+	}
+	return g, s.save(g)
+}
+
+func (s *offlineServer) setPlayers(players []model.Player) error {
+	s.pAPIs = make(map[model.PlayerID]interaction.Player, len(players))
+	for _, p := range players {
+		err := s.db.CreatePlayer(context.Background(), p)
+		if err != nil {
+			return err
+		}
+
+		if p.ID == s.me.ID() {
+			s.pAPIs[p.ID] = s.me
+			continue
+		}
+		npc, err := interaction.NewNPCPlayer(p.ID, s)
+		if err != nil {
+			return err
+		}
+		s.pAPIs[p.ID] = npc
+	}
+	return nil
+}
+
+// resume picks up the game that was loaded from the save file
+func (s *offlineServer) resume(g model.Game) error {
+	err := s.setPlayers(g.Players)
+	if err != nil {
+		return err
+	}
+
+	// the memory DB only takes new games, so we hold on to the history
+	// ourselves and add it back when we save
+	s.history = g.Actions
+	g.Actions = nil
+	err = s.db.CreateGame(context.Background(), g)
+	if err != nil {
+		return err
+	}
+
+	for pID, b := range g.BlockingPlayers {
+		err = s.pAPIs[pID].NotifyBlocking(b, g, ``)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// load returns the game in the save file, if there is one that isn't over yet
+func (s *offlineServer) load() (model.Game, bool, error) {
+	if s.saveFile == `` {
+		return model.Game{}, false, nil
+	}
+
+	b, err := ioutil.ReadFile(s.saveFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return model.Game{}, false, nil
+		}
+		return model.Game{}, false, err
+	}
+
+	var g model.Game
+	err = json.Unmarshal(b, &g)
+	if err != nil {
+		return model.Game{}, false, fmt.Errorf("could not read the saved game in %s: %v", s.saveFile, err)
+	}
+	if g.IsOver() {
+		return model.Game{}, false, nil
+	}
+
+	for _, p := range g.Players {
+		if p.ID != s.me.ID() && !interaction.IsNPC(p.ID) {
+			return model.Game{}, false, fmt.Errorf("the saved game in %s is not an offline game", s.saveFile)
+		}
+	}
+	return g, true, nil
+}
+
+// save writes the game to the save file. It writes a new file and moves it into
+// place, so that quitting in the middle of a save doesn't lose the game.
+func (s *offlineServer) save(g model.Game) error {
+	if s.saveFile == `` {
+		return nil
+	}
+
+	g.Actions = append(append([]model.PlayerAction{}, s.history...), g.Actions...)
+	b, err := json.Marshal(g)
+	if err != nil {
+		return err
+	}
+
+	tmp := s.saveFile + `.tmp`
+	err = ioutil.WriteFile(tmp, b, 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.saveFile)
+}
+
+func (s *offlineServer) SendAction(pa model.PlayerAction) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	g, err := s.db.GetGame(context.Background(), pa.GameID)
+	if err != nil {
+		return err
+	}
+
+	pa.SetTimeStamp(time.Now())
+	err = play.HandleAction(context.Background(), &g, pa, s.pAPIs)
+	if err != nil {
+		return err
+	}
+
+	err = s.db.SaveGame(context.Background(), g)
+	if err != nil {
+		return err
+	}
+
+	return s.save(g)
+}
+
+func (s *offlineServer) GetGameForPlayer(gID model.GameID, pID model.PlayerID) (network.GetGameResponse, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	g, err := s.db.GetGame(context.Background(), gID)
+	if err != nil {
+		return network.GetGameResponse{}, err
+	}
+	return network.ConvertToGetGameResponseForPlayer(g, pID)
+}
+
+func (s *offlineServer) AcceptInvitation(model.LobbyID, model.PlayerID) (network.Lobby, error) {
+	return network.Lobby{}, errNoInvitationsOffline
+}
+
+func (s *offlineServer) DeclineInvitation(model.LobbyID, model.PlayerID) (network.Lobby, error) {
+	return network.Lobby{}, errNoInvitationsOffline
+}
+
+// Handle takes the NPC's actions
+func (s *offlineServer) Handle(pa model.PlayerAction) error {
+	return s.SendAction(pa)
+}
+
+// Chat passes along what the NPC says
+func (s *offlineServer) Chat(cm model.ChatMessage) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	g, err := s.db.GetGame(context.Background(), cm.GameID)
+	if err != nil {
+		return err
+	}
+	return s.me.NotifyChat(g, cm)
+}
+
+var _ interaction.Player = (*offlinePlayer)(nil)
+
+// offlinePlayer tells the terminal client about the offline game. The offline server
+// notifies it while the terminal client waits on its action, so it never blocks.
+type offlinePlayer struct {
+	pID model.PlayerID
+
+	lock    sync.Mutex
+	pending []terminalRequest
+	// added hears when there are pending requests
+	added chan struct{}
+}
+
+func newOfflinePlayer(pID model.PlayerID) *offlinePlayer {
+	return &offlinePlayer{
+		pID:   pID,
+		added: make(chan struct{}, 1),
+	}
+}
+
+// forward sends the pending requests to the terminal client, in order
+func (op *offlinePlayer) forward(reqChan chan<- terminalRequest) {
+	for range op.added {
+		op.lock.Lock()
+		reqs := op.pending
+		op.pending = nil
+		op.lock.Unlock()
+
+		for _, req := range reqs {
+			reqChan <- req
+		}
+	}
+}
+
+func (op *offlinePlayer) notify(req terminalRequest) error {
+	op.lock.Lock()
+	defer op.lock.Unlock()
+
+	op.pending = append(op.pending, req)
+	select {
+	case op.added <- struct{}{}:
+	default:
+		// forward hasn't gotten to the last ones yet
+	}
+	return nil
+}
+
+func (op *offlinePlayer) ID() model.PlayerID {
+	return op.pID
+}
+
+func (op *offlinePlayer) NotifyBlocking(b model.Blocker, g model.Game, s string) error {
+	if s == `` {
+		s = `Your turn: ` + b.String()
+	}
+	return op.notify(terminalRequest{
+		gameID: g.ID,
+		msg:    s,
+		req:    blocking,
+	})
+}
+
+func (op *offlinePlayer) NotifyMessage(g model.Game, msg string) error {
+	return op.notify(terminalRequest{
+		gameID: g.ID,
+		msg:    msg,
+		req:    message,
+	})
+}
+
+func (op *offlinePlayer) NotifyScoreUpdate(g model.Game, msgs ...string) error {
+	return op.notify(terminalRequest{
+		gameID: g.ID,
+		msg:    strings.Join(msgs, ` `),
+		req:    scoreUpdate,
+	})
+}
+
+// Nobody can invite us to a game offline
+func (op *offlinePlayer) NotifyInvitation(l model.Lobby, msg string) error {
+	return nil
+}
+
+func (op *offlinePlayer) NotifyChat(g model.Game, cm model.ChatMessage) error {
+	return op.notify(terminalRequest{
+		gameID: g.ID,
+		msg:    fmt.Sprintf("%s: %s", playerName(g, cm.PlayerID), cm.Message),
+		req:    chat,
+	})
+}
@@ -0,0 +1,108 @@
+package localclient
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/interaction"
+	"github.com/joshprzybyszewski/cribbage/server/persistence/memory"
+)
+
+func newTestOfflineServer(saveFile string) *offlineServer {
+	memory.Clear()
+	return newOfflineServer(newOfflinePlayer(offlinePlayerID), saveFile)
+}
+
+func readSavedGame(t *testing.T, saveFile string) model.Game {
+	b, err := ioutil.ReadFile(saveFile)
+	require.NoError(t, err)
+	var g model.Game
+	require.NoError(t, json.Unmarshal(b, &g))
+	return g
+}
+
+// waitForMyCrib waits until the NPC has built its crib, and we're the only one left to
+func waitForMyCrib(t *testing.T, saveFile string) model.Game {
+	var g model.Game
+	require.Eventually(t, func() bool {
+		g = readSavedGame(t, saveFile)
+		return len(g.BlockingPlayers) == 1 && g.BlockingPlayers[offlinePlayerID] == model.CribCard
+	}, 5*time.Second, 10*time.Millisecond)
+	return g
+}
+
+func TestOfflineGameIsSavedAndResumed(t *testing.T) {
+	saveFile := filepath.Join(t.TempDir(), `game.json`)
+	me := model.Player{ID: offlinePlayerID, Name: `You`}
+
+	s := newTestOfflineServer(saveFile)
+	g, err := s.start(me, interaction.Calc)
+	require.NoError(t, err)
+	assert.Equal(t, g.ID, readSavedGame(t, saveFile).ID)
+
+	if g.BlockingPlayers[offlinePlayerID] == model.DealCards {
+		require.NoError(t, s.SendAction(model.PlayerAction{
+			GameID:    g.ID,
+			ID:        offlinePlayerID,
+			Overcomes: model.DealCards,
+			Action:    model.DealAction{NumShuffles: 1},
+		}))
+	}
+	saved := waitForMyCrib(t, saveFile)
+	require.Len(t, saved.Actions, 2, `one deal and the NPC's crib`)
+
+	// pick the game back up like it's a new process
+	s = newTestOfflineServer(saveFile)
+	resumed, err := s.start(me, interaction.Calc)
+	require.NoError(t, err)
+	assert.Equal(t, saved.ID, resumed.ID)
+	assert.Equal(t, saved.Hands[offlinePlayerID], resumed.Hands[offlinePlayerID])
+	assert.Len(t, s.history, 2)
+
+	ggr, err := s.GetGameForPlayer(resumed.ID, offlinePlayerID)
+	require.NoError(t, err)
+	assert.Len(t, ggr.Hands[offlinePlayerID], 6)
+
+	require.NoError(t, s.SendAction(model.PlayerAction{
+		GameID:    resumed.ID,
+		ID:        offlinePlayerID,
+		Overcomes: model.CribCard,
+		Action: model.BuildCribAction{
+			Cards: resumed.Hands[offlinePlayerID][:2],
+		},
+	}))
+	afterCrib := readSavedGame(t, saveFile)
+	require.GreaterOrEqual(t, len(afterCrib.Actions), 3, `the history is saved with the new actions`)
+	assert.Equal(t, saved.Actions[0].ID, afterCrib.Actions[0].ID)
+	assert.Equal(t, model.CribCard, afterCrib.Actions[2].Overcomes)
+
+	// let the NPC finish its moves before the save file is cleaned up
+	require.Eventually(t, func() bool {
+		_, ok := readSavedGame(t, saveFile).BlockingPlayers[interaction.Calc]
+		return !ok
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestOfflineGameDoesNotResumeOthersGames(t *testing.T) {
+	saveFile := filepath.Join(t.TempDir(), `game.json`)
+	b, err := json.Marshal(model.Game{
+		ID: 5,
+		Players: []model.Player{
+			{ID: offlinePlayerID},
+			{ID: `alice`},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(saveFile, b, 0644))
+
+	s := newTestOfflineServer(saveFile)
+	_, err = s.start(model.Player{ID: offlinePlayerID, Name: `You`}, interaction.Calc)
+	assert.EqualError(t, err, `the saved game in `+saveFile+` is not an offline game`)
+}
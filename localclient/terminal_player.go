@@ -2,44 +2,65 @@ package localclient
 
 import (
 	"bufio"
-	"bytes"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"io/ioutil"
+	"log"
 	"net"
 	"net/http"
 	"os"
 	"strconv"
 	"strings"
 	"sync"
-
-	"github.com/joshprzybyszewski/cribbage/jsonutils"
+	"time"
 
 	survey "github.com/AlecAivazis/survey/v2"
 	"github.com/gin-gonic/gin"
 
 	"github.com/joshprzybyszewski/cribbage/model"
 	"github.com/joshprzybyszewski/cribbage/network"
+	"github.com/joshprzybyszewski/cribbage/network/client"
 )
 
 const (
-	serverDomain = `http://localhost:8080`
+	// pollRetryWait is how long we wait to poll again after the server didn't answer
+	pollRetryWait = 5 * time.Second
 )
 
 var (
 	errInvalidGameID error = errors.New(`invalid game id`)
 )
 
+// Config says where the terminal client plays
+type Config struct {
+	// ServerURL is the cribbage server, like "http://localhost:8080"
+	ServerURL string
+	// Callback has the server POST our notifications to a port on this machine,
+	// instead of us polling for them. It only works when the server is on this machine.
+	Callback bool
+}
+
+// gameServer is what the terminal client plays its games through. It is either
+// the remote cribbage server, or the offline one running in this process.
+type gameServer interface {
+	SendAction(model.PlayerAction) error
+	GetGameForPlayer(model.GameID, model.PlayerID) (network.GetGameResponse, error)
+	AcceptInvitation(model.LobbyID, model.PlayerID) (network.Lobby, error)
+	DeclineInvitation(model.LobbyID, model.PlayerID) (network.Lobby, error)
+}
+
 type terminalClient struct {
-	server *http.Client
+	server gameServer
+	// remote is the cribbage server that we're playing on. It is nil when we play offline.
+	remote *client.Client
 
 	reqChan chan terminalRequest
 
 	me            model.Player
 	myCurrentGame model.GameID
 	myGames       map[model.GameID]model.Game
+
+	view *view
 }
 
 type termReqType int
@@ -50,18 +71,23 @@ const (
 	message
 	info
 	switchGames
+	invitation
+	chat
 )
 
 type terminalRequest struct {
-	gameID model.GameID
-	game   model.Game
-	req    termReqType
-	msg    string
+	gameID  model.GameID
+	lobbyID model.LobbyID
+	game    model.Game
+	req     termReqType
+	msg     string
 }
 
-func StartTerminalInteraction() error {
+func StartTerminalInteraction(cfg Config) error {
+	remote := client.New(cfg.ServerURL, &http.Client{})
 	tc := terminalClient{
-		server:  &http.Client{},
+		server:  remote,
+		remote:  remote,
 		myGames: make(map[model.GameID]model.Game),
 		reqChan: make(chan terminalRequest, 5),
 	}
@@ -76,13 +102,24 @@ func StartTerminalInteraction() error {
 
 	var wg sync.WaitGroup
 
-	port, err := findOpenPort()
-	if err != nil {
-		return err
-	}
+	if cfg.Callback {
+		port, err := findOpenPort()
+		if err != nil {
+			return err
+		}
 
-	tc.startClientServer(&wg, port)
-	tc.tellAboutInteraction(&wg, port)
+		tc.startClientServer(&wg, port)
+		tc.tellAboutInteraction(&wg, network.CreateInteractionRequest{
+			PlayerID:      tc.me.ID,
+			LocalhostPort: strconv.Itoa(port),
+		})
+	} else {
+		tc.tellAboutInteraction(&wg, network.CreateInteractionRequest{
+			PlayerID: tc.me.ID,
+			Polling:  true,
+		})
+		tc.pollEvents(&wg)
+	}
 	tc.processUserInput(&wg)
 
 	// Block until forever...?
@@ -134,34 +171,92 @@ func (tc *terminalClient) startClientServer(wg *sync.WaitGroup, port int) {
 
 		playerServerFile := bufio.NewWriter(f)
 
+		// gin's debug output would draw over the full-screen UI
+		gin.SetMode(gin.ReleaseMode)
 		router := gin.New()
 		router.Use(gin.LoggerWithWriter(playerServerFile), gin.Recovery())
 
 		router.POST("/blocking/:gameID", handleBlocking(tc.reqChan))
 		router.POST("/message/:gameID", handleMessage(tc.reqChan))
 		router.POST("/score/:gameID", handleScoreUpdate(tc.reqChan))
+		router.POST("/invitation/:lobbyID", handleInvitation(tc.reqChan))
+		router.POST("/chat/:gameID", handleChat(tc.reqChan))
 
 		err = router.Run(fmt.Sprintf("0.0.0.0:%d", port)) // listen and serve on the addr
 		fmt.Printf("router.Run error: %+v\n", err)
 	}()
 }
 
-func (tc *terminalClient) tellAboutInteraction(wg *sync.WaitGroup, port int) {
+func (tc *terminalClient) tellAboutInteraction(wg *sync.WaitGroup, cir network.CreateInteractionRequest) {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		// Let the server know about where we're serving our listener
-		cir := network.CreateInteractionRequest{
-			PlayerID:      tc.me.ID,
-			LocalhostPort: strconv.Itoa(port),
-		}
-		_, err := tc.makeJSONBodiedRequest(`POST`, `create/interaction`, cir)
+		// Let the server know about how we want to hear from it
+		err := tc.remote.CreateInteraction(cir)
 		if err != nil {
 			fmt.Printf("Error telling server about interaction: %+v\n", err)
 		}
 	}()
 }
 
+// pollEvents keeps asking the server for our notifications, so that we can
+// play on a server that can't call us back
+func (tc *terminalClient) pollEvents(wg *sync.WaitGroup) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		var after uint64
+		for {
+			resp, err := tc.remote.GetPlayerEvents(tc.me.ID, after)
+			if err != nil {
+				// the server may be restarting, so give it a moment
+				log.Printf("Error polling for events: %+v\n", err)
+				time.Sleep(pollRetryWait)
+				continue
+			}
+			for _, e := range resp.Events {
+				tc.reqChan <- tc.eventToRequest(e)
+			}
+			after = resp.Next
+		}
+	}()
+}
+
+func (tc *terminalClient) eventToRequest(e network.PlayerEvent) terminalRequest {
+	req := terminalRequest{
+		gameID:  e.GameID,
+		lobbyID: e.LobbyID,
+		msg:     strings.Join(e.Messages, ` `),
+	}
+
+	defMsg := ``
+	switch network.WebhookEvent(e.Event) {
+	case network.BlockingEvent:
+		req.req = blocking
+		defMsg = `We heard you're blocking`
+	case network.ScoreUpdateEvent:
+		req.req = scoreUpdate
+		defMsg = `There was a score update`
+	case network.InvitationEvent:
+		req.req = invitation
+		defMsg = `You were invited to a game`
+	case network.ChatEvent:
+		req.req = chat
+		defMsg = `Someone said something`
+		if e.Chat != nil {
+			req.msg = fmt.Sprintf("%s: %s", playerName(tc.myGames[e.GameID], e.Chat.PlayerID), e.Chat.Message)
+		}
+	default:
+		req.req = message
+		defMsg = `Received a message`
+	}
+
+	if req.msg == `` {
+		req.msg = defMsg
+	}
+	return req
+}
+
 func (tc *terminalClient) processUserInput(wg *sync.WaitGroup) {
 	wg.Add(1)
 	go func() {
@@ -182,19 +277,33 @@ func (tc *terminalClient) processUserInput(wg *sync.WaitGroup) {
 			msg:  `Starting terminal player`,
 			req:  message,
 		}
-		for req := range tc.reqChan {
-			err := tc.processRequest(req)
-			if err != nil && err != errInvalidGameID {
-				tc.reqChan <- terminalRequest{
-					gameID: req.gameID,
-					game:   req.game,
-					msg:    fmt.Sprintf(`Problem doing action (%s). Try again?`, err.Error()),
-					req:    req.req,
-				}
 
+		tc.play()
+	}()
+}
+
+// play shows the player their games until they quit
+func (tc *terminalClient) play() {
+	tc.view = newView(tc.me.ID)
+	err := tc.runTUI()
+	if err == nil {
+		// the player quit
+		os.Exit(0)
+	}
+
+	// this terminal can't do the full-screen UI, so we ask with prompts instead
+	for req := range tc.reqChan {
+		err := tc.processRequest(req)
+		if err != nil && err != errInvalidGameID {
+			tc.reqChan <- terminalRequest{
+				gameID: req.gameID,
+				game:   req.game,
+				msg:    fmt.Sprintf(`Problem doing action (%s). Try again?`, err.Error()),
+				req:    req.req,
 			}
+
 		}
-	}()
+	}
 }
 
 func handleBlocking(reqChan chan terminalRequest) func(*gin.Context) {
@@ -232,6 +341,23 @@ func handleMessage(reqChan chan terminalRequest) func(*gin.Context) {
 	}
 }
 
+func handleChat(reqChan chan terminalRequest) func(*gin.Context) {
+	return func(c *gin.Context) {
+		gID, msg, err := getGameIDAndBody(c, `Someone said something`)
+		if err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		reqChan <- terminalRequest{
+			gameID: gID,
+			msg:    msg,
+			req:    chat,
+		}
+		c.String(http.StatusOK, `received`)
+	}
+}
+
 func handleScoreUpdate(reqChan chan terminalRequest) func(*gin.Context) {
 	return func(c *gin.Context) {
 		gID, msg, err := getGameIDAndBody(c, `There was a score update`)
@@ -249,6 +375,30 @@ func handleScoreUpdate(reqChan chan terminalRequest) func(*gin.Context) {
 	}
 }
 
+func handleInvitation(reqChan chan terminalRequest) func(*gin.Context) {
+	return func(c *gin.Context) {
+		lIDStr := c.Param("lobbyID")
+		n, err := strconv.Atoi(lIDStr)
+		if err != nil {
+			c.String(http.StatusBadRequest, fmt.Sprintf("Invalid LobbyID: %s", lIDStr))
+			return
+		}
+
+		msg := `You were invited to a game`
+		reqBody, err := ioutil.ReadAll(c.Request.Body)
+		if err == nil && len(reqBody) > 0 {
+			msg = string(reqBody)
+		}
+
+		reqChan <- terminalRequest{
+			lobbyID: model.LobbyID(n),
+			msg:     msg,
+			req:     invitation,
+		}
+		c.String(http.StatusOK, `received`)
+	}
+}
+
 func getGameIDAndBody(c *gin.Context, defBody string) (model.GameID, string, error) {
 	gIDStr := c.Param("gameID")
 	n, err := strconv.Atoi(gIDStr)
@@ -267,67 +417,21 @@ func getGameIDAndBody(c *gin.Context, defBody string) (model.GameID, string, err
 	return model.GameID(n), msg, nil
 }
 
-func (tc *terminalClient) makeJSONBodiedRequest(method, apiURL string, v interface{}) ([]byte, error) {
-	b, err := json.Marshal(v)
-	if err != nil {
-		return nil, err
-	}
-	header := http.Header{}
-	if len(b) > 0 {
-		header.Add(`Content-Type`, `application/json`)
-	}
-	return tc.makeRequest(method, apiURL, bytes.NewReader(b), header)
-}
-
-func (tc *terminalClient) makeRequest(method, apiURL string, data io.Reader, header http.Header) ([]byte, error) {
-	urlStr := serverDomain + apiURL
-	req, err := http.NewRequest(method, urlStr, data)
-	if err != nil {
-		return nil, err
-	}
-	if header != nil {
-		req.Header = header
-	}
-
-	response, err := tc.server.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer response.Body.Close()
-
-	resBytes, err := ioutil.ReadAll(response.Body)
-
-	if response.StatusCode != http.StatusOK {
-		// Keeping this here for debugging
-		fmt.Printf("full response: %+v\n%s\n%s\n", response, response.Body, string(resBytes))
-
-		contentType := response.Header.Get(`Content-Type`)
-		if strings.Contains(contentType, `text/plain`) {
-			return nil, fmt.Errorf("bad response: \"%s\"", string(resBytes))
-		}
-
-		return nil, fmt.Errorf(`bad response from server`)
-	} else if err != nil {
-		return nil, err
-	}
-
-	return resBytes, nil
-}
-
 func (tc *terminalClient) createPlayer() error {
 	username, name := tc.getName()
-	reqData := model.Player{
-		ID:   model.PlayerID(username),
-		Name: name,
-	}
-	respBytes, err := tc.makeJSONBodiedRequest(`POST`, `/create/player`, reqData)
+	resp, err := tc.remote.CreatePlayer(network.CreatePlayerRequest{
+		Player: network.Player{
+			ID:   model.PlayerID(username),
+			Name: name,
+		},
+	})
 	if err != nil {
 		return err
 	}
 
-	err = json.Unmarshal(respBytes, &tc.me)
-	if err != nil {
-		return err
+	tc.me = model.Player{
+		ID:   resp.Player.ID,
+		Name: resp.Player.Name,
 	}
 
 	fmt.Printf("Your player ID is: %v\n", tc.me.ID)
@@ -395,19 +499,27 @@ func (tc *terminalClient) shouldCreateGame() bool {
 
 func (tc *terminalClient) createGame() error {
 	opID := tc.getPlayerID(`What's your opponent's username?`)
-	gameReq := network.CreateGameRequest{
-		PlayerIDs: []model.PlayerID{
-			opID,
-			tc.me.ID,
-		},
+	invReq := network.CreateInvitationRequest{
+		Host:     tc.me.ID,
+		Invitees: []model.PlayerID{opID},
 	}
 
-	respBytes, err := tc.makeJSONBodiedRequest(`POST`, `/create/game`, gameReq)
+	l, err := tc.remote.CreateInvitation(invReq)
 	if err != nil {
 		return err
 	}
 
-	g, err := jsonutils.UnmarshalGame(respBytes)
+	if l.GameID == model.InvalidGameID {
+		// we'll hear about the game once they accept
+		fmt.Printf("Waiting for %s to accept the invitation.\n", opID)
+		return nil
+	}
+
+	return tc.joinedGame(l.GameID)
+}
+
+func (tc *terminalClient) joinedGame(gID model.GameID) error {
+	g, err := tc.getGame(gID)
 	if err != nil {
 		return err
 	}
@@ -449,24 +561,19 @@ func (tc *terminalClient) getPlayerID(msg string) model.PlayerID {
 }
 
 func (tc *terminalClient) updatePlayer() error {
-	url := fmt.Sprintf("/player/%s", tc.me.ID)
-
-	respBytes, err := tc.makeRequest(`GET`, url, nil, nil)
-	if err != nil {
-		return err
-	}
-
-	err = json.Unmarshal(respBytes, &tc.me)
+	resp, err := tc.remote.GetActiveGames(tc.me.ID)
 	if err != nil {
 		return err
 	}
+	tc.me.Name = resp.Player.Name
 
 	tc.reqChan <- terminalRequest{
-		msg: fmt.Sprintf(`Knows about %d games`, len(tc.me.Games)),
+		msg: fmt.Sprintf(`Knows about %d games`, len(resp.ActiveGames)),
 		req: info,
 	}
 
-	for gID := range tc.me.Games {
+	for _, ag := range resp.ActiveGames {
+		gID := ag.GameID
 		g, err := tc.requestGame(gID)
 		if err != nil {
 			return err
@@ -490,14 +597,7 @@ func (tc *terminalClient) updatePlayer() error {
 }
 
 func (tc *terminalClient) requestGame(gID model.GameID) (model.Game, error) {
-	url := fmt.Sprintf("/game/%v", gID)
-
-	respBytes, err := tc.makeRequest(`GET`, url, nil, nil)
-	if err != nil {
-		return model.Game{}, err
-	}
-
-	g, err := jsonutils.UnmarshalGame(respBytes)
+	g, err := tc.getGame(gID)
 	if err != nil {
 		return model.Game{}, err
 	}
@@ -513,6 +613,15 @@ func (tc *terminalClient) requestGame(gID model.GameID) (model.Game, error) {
 	return g, nil
 }
 
+// getGame returns what the server lets this player see of the game
+func (tc *terminalClient) getGame(gID model.GameID) (model.Game, error) {
+	ggr, err := tc.server.GetGameForPlayer(gID, tc.me.ID)
+	if err != nil {
+		return model.Game{}, err
+	}
+	return network.ConvertFromGetGameResponse(ggr), nil
+}
+
 func (tc *terminalClient) processRequest(req terminalRequest) error {
 	switch req.req {
 	case info:
@@ -520,6 +629,8 @@ func (tc *terminalClient) processRequest(req terminalRequest) error {
 		return nil
 	case switchGames:
 		return tc.askToSwitchGames(req.gameID)
+	case invitation:
+		return tc.respondToInvitation(req.lobbyID, req.msg)
 	}
 
 	gID := req.gameID
@@ -540,6 +651,8 @@ func (tc *terminalClient) processRequest(req terminalRequest) error {
 		}
 	case message:
 		fmt.Println(req.msg)
+	case chat:
+		fmt.Printf("(chat) %s\n", req.msg)
 	case scoreUpdate:
 		fmt.Println(req.msg)
 		tc.printCurrentScore()
@@ -549,6 +662,37 @@ func (tc *terminalClient) processRequest(req terminalRequest) error {
 	return nil
 }
 
+func (tc *terminalClient) respondToInvitation(lID model.LobbyID, msg string) error {
+	accept := true
+
+	prompt := &survey.Confirm{
+		Message: msg + `. Accept?`,
+		Default: true,
+	}
+
+	err := survey.AskOne(prompt, &accept)
+	if err != nil {
+		fmt.Printf("survey.AskOne error: %+v\n", err)
+		return err
+	}
+
+	respond := tc.server.DeclineInvitation
+	if accept {
+		respond = tc.server.AcceptInvitation
+	}
+	l, err := respond(lID, tc.me.ID)
+	if err != nil {
+		// the invitation may have been for a lobby that has already closed
+		fmt.Printf("Could not respond to invitation: %v\n", err)
+		return nil
+	}
+
+	if l.GameID == model.InvalidGameID {
+		return nil
+	}
+	return tc.joinedGame(l.GameID)
+}
+
 func (tc *terminalClient) askToSwitchGames(newGameID model.GameID) error {
 	should := true
 
@@ -0,0 +1,186 @@
+package localclient
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/AlecAivazis/survey/v2/terminal"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+)
+
+// runTUI takes over the terminal with the full-screen UI until the player quits.
+// It returns an error if the terminal can't be put into raw mode, so that the
+// caller can fall back to the prompts.
+func (tc *terminalClient) runTUI() error {
+	rr := terminal.NewRuneReader(terminal.Stdio{
+		In:  os.Stdin,
+		Out: os.Stdout,
+		Err: os.Stderr,
+	})
+	if err := rr.SetTermMode(); err != nil {
+		return err
+	}
+	defer func() {
+		_ = rr.RestoreTermMode()
+	}()
+
+	// anything logged would draw over the screen
+	log.SetOutput(ioutil.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	fmt.Print(enterFullScreen)
+	defer fmt.Print(exitFullScreen)
+
+	keys := make(chan rune)
+	go readKeys(rr, keys)
+
+	tc.view.setGame(tc.myGames[tc.myCurrentGame])
+	for {
+		fmt.Print(tc.view.render())
+
+		select {
+		case req := <-tc.reqChan:
+			tc.handleTUIRequest(req)
+		case r, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			res := tc.view.handleKey(r)
+			if res.quit {
+				return nil
+			}
+			tc.handleKeyResult(res)
+		}
+	}
+}
+
+func readKeys(rr *terminal.RuneReader, keys chan<- rune) {
+	defer close(keys)
+	for {
+		r, _, err := rr.ReadRune()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			// an escape sequence we don't know about
+			continue
+		}
+		keys <- r
+	}
+}
+
+func (tc *terminalClient) handleTUIRequest(req terminalRequest) {
+	switch req.req {
+	case info:
+		tc.view.addLog(req.msg)
+		return
+	case switchGames:
+		tc.askToSwitchGamesInTUI(req.gameID)
+		return
+	case invitation:
+		tc.view.addConfirmation(confirmation{
+			question: req.msg + `. Accept?`,
+			lobbyID:  req.lobbyID,
+		})
+		return
+	}
+
+	gID := req.gameID
+	if gID == model.InvalidGameID {
+		gID = req.game.ID
+	}
+
+	switch req.req {
+	case chat:
+		tc.view.addLog(`(chat) ` + req.msg)
+	case scoreUpdate:
+		tc.view.addLog(`(score) ` + req.msg)
+	default:
+		tc.view.addLog(req.msg)
+	}
+
+	if gID != model.InvalidGameID {
+		tc.refreshGame(gID)
+	}
+}
+
+// refreshGame gets the latest from the server, and shows it if it's the current game
+func (tc *terminalClient) refreshGame(gID model.GameID) {
+	g, err := tc.getGame(gID)
+	if err != nil {
+		tc.view.status = fmt.Sprintf(`Could not get the game: %v`, err)
+		return
+	}
+	tc.myGames[gID] = g
+
+	if tc.myCurrentGame == model.InvalidGameID {
+		tc.myCurrentGame = gID
+	}
+	if gID == tc.myCurrentGame {
+		tc.view.setGame(g)
+		return
+	}
+	if _, ok := g.BlockingPlayers[tc.me.ID]; ok {
+		tc.askToSwitchGamesInTUI(gID)
+	}
+}
+
+func (tc *terminalClient) askToSwitchGamesInTUI(gID model.GameID) {
+	for _, c := range tc.view.confirmations {
+		if c.gameID == gID {
+			// we've already asked
+			return
+		}
+	}
+	tc.view.addConfirmation(confirmation{
+		question: `Switch to the game with ` + gamePlayerNames(tc.myGames[gID]) + `?`,
+		gameID:   gID,
+	})
+}
+
+func (tc *terminalClient) handleKeyResult(res keyResult) {
+	if res.action != nil {
+		err := tc.server.SendAction(*res.action)
+		if err != nil {
+			tc.view.status = fmt.Sprintf(`Problem doing action (%s). Try again?`, err.Error())
+			return
+		}
+		tc.view.resetInput()
+		tc.refreshGame(res.action.GameID)
+		return
+	}
+
+	if res.confirmation == nil {
+		return
+	}
+	c := res.confirmation
+	if c.lobbyID != model.InvalidLobbyID {
+		tc.answerInvitationInTUI(c.lobbyID, res.answer)
+		return
+	}
+	if res.answer {
+		tc.myCurrentGame = c.gameID
+		tc.view.setGame(tc.myGames[c.gameID])
+	}
+}
+
+func (tc *terminalClient) answerInvitationInTUI(lID model.LobbyID, accept bool) {
+	respond := tc.server.DeclineInvitation
+	if accept {
+		respond = tc.server.AcceptInvitation
+	}
+	l, err := respond(lID, tc.me.ID)
+	if err != nil {
+		// the invitation may have been for a lobby that has already closed
+		tc.view.status = fmt.Sprintf(`Could not respond to invitation: %v`, err)
+		return
+	}
+
+	if l.GameID != model.InvalidGameID {
+		tc.refreshGame(l.GameID)
+	}
+}
@@ -0,0 +1,242 @@
+package localclient
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+)
+
+const (
+	// These escape sequences are understood by just about every terminal,
+	// which keeps the UI working over a plain SSH session
+	enterFullScreen = "\x1b[?1049h\x1b[?25l"
+	exitFullScreen  = "\x1b[?25h\x1b[?1049l"
+	clearScreen     = "\x1b[H\x1b[2J"
+
+	ansiReset   = "\x1b[0m"
+	ansiReverse = "\x1b[7m"
+	ansiBold    = "\x1b[1m"
+
+	// The board has a row for every 40 holes, like the streets of a real board
+	holesPerStreet = 40
+	holesPerGroup  = 5
+
+	maxLogLines = 8
+)
+
+func colorize(c model.PlayerColor, s string) string {
+	code := ``
+	switch c {
+	case model.Blue:
+		code = "\x1b[34m"
+	case model.Red:
+		code = "\x1b[31m"
+	case model.Green:
+		code = "\x1b[32m"
+	default:
+		return s
+	}
+	return code + s + ansiReset
+}
+
+// boardColors returns the colors playing in the game, in a stable order
+func boardColors(g model.Game) []model.PlayerColor {
+	var colors []model.PlayerColor
+	seen := map[model.PlayerColor]struct{}{}
+	for _, c := range g.PlayerColors {
+		if _, ok := seen[c]; ok {
+			continue
+		}
+		seen[c] = struct{}{}
+		colors = append(colors, c)
+	}
+	sort.Slice(colors, func(i, j int) bool {
+		return colors[i] < colors[j]
+	})
+	return colors
+}
+
+// renderBoard draws a cribbage board with a street of holes for every 40 points. Each
+// color has a front peg (its score) drawn with a capital letter, and a back peg (its
+// previous score) drawn with a lower case letter.
+func renderBoard(g model.Game, myID model.PlayerID) string {
+	var sb strings.Builder
+	myColor := g.PlayerColors[myID]
+	for _, c := range boardColors(g) {
+		label := c.String()
+		if c == myColor {
+			label += ` (you)`
+		}
+		front, back := g.CurrentScores[c], g.LagScores[c]
+
+		for street := 0; street*holesPerStreet < model.WinningScore-1; street++ {
+			if street == 0 {
+				fmt.Fprintf(&sb, "%-11s %3d ", label, front)
+				sb.WriteString(colorize(c, pegHole(c, 0, front, back)))
+			} else {
+				sb.WriteString(strings.Repeat(` `, 17))
+			}
+			sb.WriteString(`|`)
+
+			for h := 1; h <= holesPerStreet; h++ {
+				if h > 1 && (h-1)%holesPerGroup == 0 {
+					sb.WriteString(` `)
+				}
+				hole := street*holesPerStreet + h
+				sb.WriteString(colorize(c, pegHole(c, hole, front, back)))
+			}
+
+			sb.WriteString(`|`)
+			if (street+1)*holesPerStreet >= model.WinningScore-1 {
+				// the last hole is the finish line
+				sb.WriteString(colorize(c, pegHole(c, model.WinningScore, front, back)))
+			}
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+func pegHole(c model.PlayerColor, hole, front, back int) string {
+	if front >= model.WinningScore {
+		front = model.WinningScore
+	}
+	name := c.String()
+	switch hole {
+	case front:
+		return strings.ToUpper(name[:1])
+	case back:
+		return strings.ToLower(name[:1])
+	}
+	if hole == 0 || hole == model.WinningScore {
+		return ` `
+	}
+	return `.`
+}
+
+func cardString(c model.Card) string {
+	if c == model.InvalidCard {
+		// the server doesn't tell us about cards we can't see
+		return `[##]`
+	}
+	return fmt.Sprintf(`[%2s]`, c.String())
+}
+
+func cardsString(cs []model.Card) string {
+	strs := make([]string, len(cs))
+	for i, c := range cs {
+		strs[i] = cardString(c)
+	}
+	return strings.Join(strs, ` `)
+}
+
+func playerName(g model.Game, pID model.PlayerID) string {
+	for _, p := range g.Players {
+		if p.ID == pID && p.Name != `` {
+			return p.Name
+		}
+	}
+	return string(pID)
+}
+
+// render draws the whole screen
+func (v *view) render() string {
+	var sb strings.Builder
+	sb.WriteString(clearScreen)
+
+	if v.game.ID == model.InvalidGameID {
+		sb.WriteString(ansiBold + "Cribbage" + ansiReset + "\n\nWaiting for a game...\n\n")
+	} else {
+		v.renderGame(&sb)
+	}
+
+	sb.WriteString("---- messages ----\n")
+	for _, l := range v.log {
+		sb.WriteString(l + "\n")
+	}
+	for i := len(v.log); i < maxLogLines; i++ {
+		sb.WriteString("\n")
+	}
+
+	if v.status != `` {
+		sb.WriteString(ansiBold + v.status + ansiReset + "\n")
+	}
+	sb.WriteString(`> ` + v.prompt() + "\n")
+	return sb.String()
+}
+
+func (v *view) renderGame(sb *strings.Builder) {
+	g := v.game
+	fmt.Fprintf(sb, "%sCribbage with %s%s    (phase: %s)\n\n", ansiBold, gamePlayerNames(g), ansiReset, g.Phase)
+	sb.WriteString(renderBoard(g, v.me))
+	sb.WriteString("\n")
+
+	cut := `    `
+	if g.CutCard != model.InvalidCard {
+		cut = cardString(g.CutCard)
+	}
+	fmt.Fprintf(sb, "Cut: %s   %s's crib: %s\n", cut, playerName(g, g.CurrentDealer), cardsString(g.Crib))
+
+	fmt.Fprintf(sb, "Pegging count: %2d   pile: %s\n\n", g.CurrentPeg(), cardsString(v.peggingPile()))
+
+	for _, p := range g.Players {
+		if p.ID == v.me {
+			continue
+		}
+		fmt.Fprintf(sb, "%s: %s\n", colorize(g.PlayerColors[p.ID], playerName(g, p.ID)), cardsString(g.Hands[p.ID]))
+	}
+
+	sb.WriteString("\nYour hand: ")
+	sb.WriteString(v.renderMyHand())
+	sb.WriteString("\n\n")
+}
+
+// renderMyHand draws my cards, with the ones I've pegged dimmed out and the
+// one under the cursor (and any I've selected) highlighted
+func (v *view) renderMyHand() string {
+	selectable := v.selectable()
+	cursorCard := model.InvalidCard
+	if v.cursor < len(selectable) {
+		cursorCard = selectable[v.cursor]
+	}
+
+	strs := make([]string, 0, len(v.game.Hands[v.me]))
+	for _, c := range v.game.Hands[v.me] {
+		s := cardString(c)
+		if _, ok := v.selected[c]; ok {
+			s = `*` + s
+		} else {
+			s = ` ` + s
+		}
+		if v.isSelecting() && c == cursorCard {
+			s = ansiReverse + s + ansiReset
+		} else if hasPegged(v.game, c) {
+			s = `(` + s[1:] + `)`
+		}
+		strs = append(strs, s)
+	}
+	return strings.Join(strs, ` `)
+}
+
+// peggingPile returns the cards pegged since the count was last reset
+func (v *view) peggingPile() []model.Card {
+	var pile []model.Card
+	count := v.game.CurrentPeg()
+	pcs := v.game.PeggedCards
+	for i := len(pcs) - 1; i >= 0 && count > 0; i-- {
+		count -= pcs[i].PegValue()
+		pile = append([]model.Card{pcs[i].Card}, pile...)
+	}
+	return pile
+}
+
+func hasPegged(g model.Game, c model.Card) bool {
+	for _, pc := range g.PeggedCards {
+		if pc.Card == c {
+			return true
+		}
+	}
+	return false
+}
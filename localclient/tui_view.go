@@ -0,0 +1,335 @@
+package localclient
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/AlecAivazis/survey/v2/terminal"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/utils/rand"
+)
+
+var cutChoices = []string{`thin`, `middle`, `thick`}
+
+// confirmation is a yes or no question for the player. It is either an
+// invitation to a lobby, or asking to switch to another game.
+type confirmation struct {
+	question string
+	lobbyID  model.LobbyID
+	gameID   model.GameID
+}
+
+// keyResult is what the terminal client needs to do after a key press
+type keyResult struct {
+	action *model.PlayerAction
+
+	confirmation *confirmation
+	answer       bool
+
+	quit bool
+}
+
+// view is everything that the full-screen terminal UI shows. It doesn't
+// talk to the server, so the terminal client does that for it.
+type view struct {
+	me   model.PlayerID
+	game model.Game
+	log  []string
+
+	// status tells the player what went wrong with their last key press
+	status string
+
+	confirmations []confirmation
+
+	// cursor is the index of the selectable card (or cut choice) that the player is on
+	cursor   int
+	selected map[model.Card]struct{}
+	// typed is the number of points the player has typed so far
+	typed string
+}
+
+func newView(me model.PlayerID) *view {
+	return &view{
+		me:       me,
+		selected: map[model.Card]struct{}{},
+	}
+}
+
+func (v *view) addLog(msg string) {
+	v.log = append(v.log, msg)
+	if len(v.log) > maxLogLines {
+		v.log = v.log[len(v.log)-maxLogLines:]
+	}
+}
+
+func (v *view) addConfirmation(c confirmation) {
+	v.confirmations = append(v.confirmations, c)
+}
+
+// setGame shows the game, and forgets about any half-finished input if the
+// player is being asked for something new
+func (v *view) setGame(g model.Game) {
+	if g.ID != v.game.ID || g.BlockingPlayers[v.me] != v.game.BlockingPlayers[v.me] || g.Phase != v.game.Phase {
+		v.resetInput()
+	}
+	v.game = g
+	if v.cursor >= len(v.selectable()) {
+		v.cursor = 0
+	}
+}
+
+func (v *view) resetInput() {
+	v.cursor = 0
+	v.selected = map[model.Card]struct{}{}
+	v.typed = ``
+}
+
+func (v *view) blocker() (model.Blocker, bool) {
+	if v.game.IsOver() {
+		return 0, false
+	}
+	b, ok := v.game.BlockingPlayers[v.me]
+	return b, ok
+}
+
+// isSelecting is true when the player picks cards out of their hand
+func (v *view) isSelecting() bool {
+	if len(v.confirmations) > 0 {
+		return false
+	}
+	b, ok := v.blocker()
+	return ok && (b == model.CribCard || b == model.PegCard)
+}
+
+// selectable returns the cards the player can choose from right now
+func (v *view) selectable() []model.Card {
+	b, _ := v.blocker()
+	var cards []model.Card
+	for _, c := range v.game.Hands[v.me] {
+		if b == model.PegCard && hasPegged(v.game, c) {
+			continue
+		}
+		cards = append(cards, c)
+	}
+	return cards
+}
+
+func (v *view) numCribCards() int {
+	return len(v.game.Hands[v.me]) - 4
+}
+
+// prompt tells the player what we're waiting on, and which keys to press
+func (v *view) prompt() string { //nolint:gocyclo
+	if len(v.confirmations) > 0 {
+		return v.confirmations[0].question + ` (y/n)`
+	}
+	if v.game.IsOver() {
+		return `The game is over. q quits`
+	}
+
+	b, ok := v.blocker()
+	if !ok {
+		return `Waiting for the other players. q quits`
+	}
+
+	switch b {
+	case model.DealCards:
+		return `Your deal. enter shuffles and deals`
+	case model.CribCard:
+		return fmt.Sprintf(`Pick %d cards for the crib: left/right moves, space picks, enter sends`, v.numCribCards())
+	case model.CutCard:
+		choices := ``
+		for i, c := range cutChoices {
+			if i == v.cursor {
+				c = ansiReverse + c + ansiReset
+			}
+			choices += ` ` + c
+		}
+		return `How would you like to cut?` + choices + ` (left/right moves, enter cuts)`
+	case model.PegCard:
+		return `Your peg: left/right moves, enter pegs, g says go`
+	case model.CountHand:
+		return `How many points in your hand? ` + v.typed
+	case model.CountCrib:
+		return `How many points in the crib? ` + v.typed
+	case model.ApproveTakeback:
+		return `Let them take back their last action? (y/n)`
+	}
+	return `Waiting on ` + b.String()
+}
+
+// handleKey updates the view for the key press, and returns anything
+// that needs to be sent to the server
+func (v *view) handleKey(r rune) keyResult { //nolint:gocyclo
+	if r == terminal.KeyInterrupt || (r == 'q' && v.typed == ``) {
+		return keyResult{quit: true}
+	}
+	v.status = ``
+
+	if len(v.confirmations) > 0 {
+		c := v.confirmations[0]
+		switch r {
+		case 'y', 'Y', terminal.KeyEnter:
+			v.confirmations = v.confirmations[1:]
+			return keyResult{confirmation: &c, answer: true}
+		case 'n', 'N':
+			v.confirmations = v.confirmations[1:]
+			return keyResult{confirmation: &c, answer: false}
+		}
+		return keyResult{}
+	}
+
+	b, ok := v.blocker()
+	if !ok {
+		return keyResult{}
+	}
+
+	var action interface{}
+	switch b {
+	case model.DealCards:
+		if r == terminal.KeyEnter {
+			action = model.DealAction{
+				NumShuffles: rand.Intn(10) + 1,
+				Entropy:     rand.String(16),
+			}
+		}
+	case model.CribCard:
+		action = v.handleCribKey(r)
+	case model.CutCard:
+		v.moveCursor(r, len(cutChoices))
+		if r == terminal.KeyEnter {
+			action = model.CutDeckAction{
+				Percentage: cutPercentage(cutChoices[v.cursor]),
+			}
+		}
+	case model.PegCard:
+		action = v.handlePegKey(r)
+	case model.CountHand, model.CountCrib:
+		pts, done := v.handleTypedKey(r)
+		if done {
+			if b == model.CountHand {
+				action = model.CountHandAction{Pts: pts}
+			} else {
+				action = model.CountCribAction{Pts: pts}
+			}
+		}
+	case model.ApproveTakeback:
+		switch r {
+		case 'y', 'Y':
+			action = model.ApproveTakebackAction{Approve: true}
+		case 'n', 'N':
+			action = model.ApproveTakebackAction{Approve: false}
+		}
+	}
+
+	if action == nil {
+		return keyResult{}
+	}
+	return keyResult{
+		action: &model.PlayerAction{
+			GameID:    v.game.ID,
+			ID:        v.me,
+			Overcomes: b,
+			Action:    action,
+		},
+	}
+}
+
+func (v *view) moveCursor(r rune, n int) {
+	if n == 0 {
+		return
+	}
+	switch r {
+	case terminal.KeyArrowLeft, 'h':
+		v.cursor = (v.cursor + n - 1) % n
+	case terminal.KeyArrowRight, 'l':
+		v.cursor = (v.cursor + 1) % n
+	}
+}
+
+func (v *view) handleCribKey(r rune) interface{} {
+	cards := v.selectable()
+	v.moveCursor(r, len(cards))
+
+	switch r {
+	case terminal.KeySpace:
+		if v.cursor >= len(cards) {
+			return nil
+		}
+		c := cards[v.cursor]
+		if _, ok := v.selected[c]; ok {
+			delete(v.selected, c)
+		} else if len(v.selected) < v.numCribCards() {
+			v.selected[c] = struct{}{}
+		} else {
+			v.status = fmt.Sprintf(`You can only pick %d cards`, v.numCribCards())
+		}
+	case terminal.KeyEnter:
+		if len(v.selected) != v.numCribCards() {
+			v.status = fmt.Sprintf(`Pick %d cards first`, v.numCribCards())
+			return nil
+		}
+		var crib []model.Card
+		for _, c := range cards {
+			if _, ok := v.selected[c]; ok {
+				crib = append(crib, c)
+			}
+		}
+		return model.BuildCribAction{Cards: crib}
+	}
+	return nil
+}
+
+func (v *view) handlePegKey(r rune) interface{} {
+	cards := v.selectable()
+	v.moveCursor(r, len(cards))
+
+	switch r {
+	case 'g', 'G':
+		return model.PegAction{SayGo: true}
+	case terminal.KeyEnter:
+		if v.cursor >= len(cards) {
+			v.status = `You don't have any cards left to peg`
+			return nil
+		}
+		return model.PegAction{Card: cards[v.cursor]}
+	}
+	return nil
+}
+
+// handleTypedKey builds up the number that the player is typing. It returns
+// true once they press enter.
+func (v *view) handleTypedKey(r rune) (int, bool) {
+	switch {
+	case r >= '0' && r <= '9':
+		if len(v.typed) < 2 {
+			v.typed += string(r)
+		}
+	case r == terminal.KeyBackspace || r == terminal.KeyDelete:
+		if len(v.typed) > 0 {
+			v.typed = v.typed[:len(v.typed)-1]
+		}
+	case r == terminal.KeyEnter:
+		pts, err := strconv.Atoi(v.typed)
+		if err != nil {
+			v.status = `Type the number of points first`
+			return 0, false
+		}
+		return pts, true
+	}
+	return 0, false
+}
+
+// cutPercentage returns a random percentage for a thin, middle, or thick cut
+func cutPercentage(choice string) float64 {
+	switch choice {
+	case `thin`:
+		return (rand.Float64() + 0) / 3
+	case `middle`:
+		return (rand.Float64() + 1) / 3
+	case `thick`:
+		return (rand.Float64() + 2) / 3
+	}
+	return 0.500
+}
@@ -0,0 +1,219 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+)
+
+// ActionType tags the concrete type of PlayerAction.Action when it is encoded
+type ActionType string
+
+const (
+	DealActionType      ActionType = `deal`
+	BuildCribActionType ActionType = `crib`
+	CutDeckActionType   ActionType = `cut`
+	PegActionType       ActionType = `peg`
+	CountHandActionType ActionType = `countHand`
+	CountCribActionType ActionType = `countCrib`
+	ForfeitActionType   ActionType = `forfeit`
+
+	RequestTakebackActionType ActionType = `requestTakeback`
+	ApproveTakebackActionType ActionType = `approveTakeback`
+)
+
+const (
+	// CurrentActionVersion is the schema version that actions are encoded with.
+	// Version 0 is how actions were stored before they had a type tag; their
+	// type is inferred from the Blocker that they overcome.
+	CurrentActionVersion = 1
+)
+
+var (
+	ErrUnknownActionType    error = errors.New(`unknown action type`)
+	ErrUnknownActionVersion error = errors.New(`unknown action schema version`)
+)
+
+type actionTypeInfo struct {
+	overcomes Blocker
+	newAction func() interface{}
+}
+
+// actionTypes needs a new entry whenever a new kind of action is added. Once
+// an ActionType has been stored, it must never be renamed.
+var actionTypes = map[ActionType]actionTypeInfo{
+	DealActionType: {
+		overcomes: DealCards,
+		newAction: func() interface{} { return &DealAction{} },
+	},
+	BuildCribActionType: {
+		overcomes: CribCard,
+		newAction: func() interface{} { return &BuildCribAction{} },
+	},
+	CutDeckActionType: {
+		overcomes: CutCard,
+		newAction: func() interface{} { return &CutDeckAction{} },
+	},
+	PegActionType: {
+		overcomes: PegCard,
+		newAction: func() interface{} { return &PegAction{} },
+	},
+	CountHandActionType: {
+		overcomes: CountHand,
+		newAction: func() interface{} { return &CountHandAction{} },
+	},
+	CountCribActionType: {
+		overcomes: CountCrib,
+		newAction: func() interface{} { return &CountCribAction{} },
+	},
+	ForfeitActionType: {
+		overcomes: Forfeit,
+		newAction: func() interface{} { return &ForfeitAction{} },
+	},
+	RequestTakebackActionType: {
+		overcomes: RequestTakeback,
+		newAction: func() interface{} { return &RequestTakebackAction{} },
+	},
+	ApproveTakebackActionType: {
+		overcomes: ApproveTakeback,
+		newAction: func() interface{} { return &ApproveTakebackAction{} },
+	},
+}
+
+// ActionTypeOf returns the tag for the action, which may be a value or a pointer
+func ActionTypeOf(a interface{}) (ActionType, error) {
+	switch a.(type) {
+	case DealAction, *DealAction:
+		return DealActionType, nil
+	case BuildCribAction, *BuildCribAction:
+		return BuildCribActionType, nil
+	case CutDeckAction, *CutDeckAction:
+		return CutDeckActionType, nil
+	case PegAction, *PegAction:
+		return PegActionType, nil
+	case CountHandAction, *CountHandAction:
+		return CountHandActionType, nil
+	case CountCribAction, *CountCribAction:
+		return CountCribActionType, nil
+	case ForfeitAction, *ForfeitAction:
+		return ForfeitActionType, nil
+	case RequestTakebackAction, *RequestTakebackAction:
+		return RequestTakebackActionType, nil
+	case ApproveTakebackAction, *ApproveTakebackAction:
+		return ApproveTakebackActionType, nil
+	}
+	return ``, ErrUnknownActionType
+}
+
+// ResolveActionType returns the type of an encoded action. Actions from before
+// there was a schema version don't have a tag, so we use what they overcome.
+func ResolveActionType(version int, t ActionType, overcomes Blocker) (ActionType, error) {
+	if version < 0 || version > CurrentActionVersion {
+		return ``, ErrUnknownActionVersion
+	}
+
+	if version == 0 || t == `` {
+		for at, info := range actionTypes {
+			if info.overcomes == overcomes {
+				return at, nil
+			}
+		}
+		return ``, ErrUnknownActionType
+	}
+
+	if _, ok := actionTypes[t]; !ok {
+		return ``, ErrUnknownActionType
+	}
+	return t, nil
+}
+
+// NewActionOfType returns a pointer to an empty action of the given type, which
+// is ready to be decoded into. Pass the result to ActionValue once it's populated.
+func NewActionOfType(t ActionType) (interface{}, error) {
+	info, ok := actionTypes[t]
+	if !ok {
+		return nil, ErrUnknownActionType
+	}
+	return info.newAction(), nil
+}
+
+// ActionValue returns the action that ptr points to, since the rest of
+// the code expects PlayerAction.Action to hold a value
+func ActionValue(ptr interface{}) interface{} {
+	return reflect.ValueOf(ptr).Elem().Interface()
+}
+
+// jsonActionEnvelope is how a PlayerAction is encoded as JSON
+type jsonActionEnvelope struct {
+	Version   int             `json:"v,omitempty"`
+	GameID    GameID          `json:"gID"`
+	ID        PlayerID        `json:"pID"`
+	Overcomes Blocker         `json:"o"`
+	Type      ActionType      `json:"t,omitempty"`
+	Action    json.RawMessage `json:"a"`
+	Auto      bool            `json:"auto,omitempty"`
+
+	TimestampStr string `json:"timestamp,omitempty"`
+}
+
+// MarshalJSON encodes the action with its type tag and the current schema version
+func (pa PlayerAction) MarshalJSON() ([]byte, error) {
+	env := jsonActionEnvelope{
+		Version:      CurrentActionVersion,
+		GameID:       pa.GameID,
+		ID:           pa.ID,
+		Overcomes:    pa.Overcomes,
+		Auto:         pa.Auto,
+		TimestampStr: pa.TimestampStr,
+	}
+
+	if pa.Action != nil {
+		t, err := ActionTypeOf(pa.Action)
+		if err != nil {
+			return nil, err
+		}
+		env.Type = t
+	}
+
+	a, err := json.Marshal(pa.Action)
+	if err != nil {
+		return nil, err
+	}
+	env.Action = a
+
+	return json.Marshal(env)
+}
+
+// UnmarshalJSON decodes the action into its concrete type
+func (pa *PlayerAction) UnmarshalJSON(b []byte) error {
+	var env jsonActionEnvelope
+	err := json.Unmarshal(b, &env)
+	if err != nil {
+		return err
+	}
+
+	t, err := ResolveActionType(env.Version, env.Type, env.Overcomes)
+	if err != nil {
+		return err
+	}
+	a, err := NewActionOfType(t)
+	if err != nil {
+		return err
+	}
+	if len(env.Action) > 0 {
+		err = json.Unmarshal(env.Action, a)
+		if err != nil {
+			return err
+		}
+	}
+
+	*pa = PlayerAction{
+		GameID:       env.GameID,
+		ID:           env.ID,
+		Overcomes:    env.Overcomes,
+		Action:       ActionValue(a),
+		Auto:         env.Auto,
+		TimestampStr: env.TimestampStr,
+	}
+	return nil
+}
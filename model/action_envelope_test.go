@@ -0,0 +1,99 @@
+package model_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+)
+
+func TestPlayerActionMarshalJSON(t *testing.T) {
+	pa := model.PlayerAction{
+		GameID:    model.GameID(42),
+		ID:        model.PlayerID(`alice`),
+		Overcomes: model.CountHand,
+		Action:    model.CountHandAction{Pts: 12},
+	}
+
+	b, err := json.Marshal(pa)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"v":1,"gID":42,"pID":"alice","o":4,"t":"countHand","a":{"pts":12}}`, string(b))
+
+	pa.Action = &model.CountHandAction{Pts: 12}
+	b2, err := json.Marshal(pa)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(b), string(b2), `pointers encode like values`)
+
+	pa.Auto = true
+	b, err = json.Marshal(pa)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"v":1,"gID":42,"pID":"alice","o":4,"t":"countHand","a":{"pts":12},"auto":true}`, string(b))
+
+	pa.Action = `not an action`
+	_, err = json.Marshal(pa)
+	assert.Error(t, err)
+}
+
+func TestPlayerActionUnmarshalJSON(t *testing.T) {
+	testCases := []struct {
+		msg       string
+		input     string
+		expAction interface{}
+		expAuto   bool
+		expErr    error
+	}{{
+		msg:       `current version`,
+		input:     `{"v":1,"gID":42,"pID":"alice","o":3,"t":"peg","a":{"c":{"s":1,"v":5},"sg":false}}`,
+		expAction: model.PegAction{Card: model.NewCardFromString(`5c`)},
+	}, {
+		msg:       `before there was a version`,
+		input:     `{"gID":42,"pID":"alice","o":3,"a":{"c":{"s":1,"v":5},"sg":false}}`,
+		expAction: model.PegAction{Card: model.NewCardFromString(`5c`)},
+	}, {
+		msg:       `the tag wins over what it overcomes`,
+		input:     `{"v":1,"gID":42,"pID":"alice","o":6,"t":"deal","a":{"ns":3}}`,
+		expAction: model.DealAction{NumShuffles: 3},
+	}, {
+		msg:       `without an action`,
+		input:     `{"v":1,"gID":42,"pID":"alice","o":6,"t":"forfeit"}`,
+		expAction: model.ForfeitAction{},
+	}, {
+		msg:       `answering a takeback`,
+		input:     `{"v":1,"gID":42,"pID":"alice","o":8,"t":"approveTakeback","a":{"ok":true}}`,
+		expAction: model.ApproveTakebackAction{Approve: true},
+	}, {
+		msg:       `played by the server`,
+		input:     `{"v":1,"gID":42,"pID":"alice","o":3,"t":"peg","a":{"sg":true},"auto":true}`,
+		expAction: model.PegAction{SayGo: true},
+		expAuto:   true,
+	}, {
+		msg:    `from the future`,
+		input:  `{"v":2,"gID":42,"pID":"alice","o":3,"t":"peg","a":{}}`,
+		expErr: model.ErrUnknownActionVersion,
+	}, {
+		msg:    `unknown type`,
+		input:  `{"v":1,"gID":42,"pID":"alice","o":3,"t":"juggle","a":{}}`,
+		expErr: model.ErrUnknownActionType,
+	}, {
+		msg:    `unknown blocker`,
+		input:  `{"gID":42,"pID":"alice","o":17,"a":{}}`,
+		expErr: model.ErrUnknownActionType,
+	}}
+
+	for _, tc := range testCases {
+		var pa model.PlayerAction
+		err := json.Unmarshal([]byte(tc.input), &pa)
+		if tc.expErr != nil {
+			assert.Equal(t, tc.expErr, err, tc.msg)
+			continue
+		}
+		require.NoError(t, err, tc.msg)
+		assert.Equal(t, model.GameID(42), pa.GameID, tc.msg)
+		assert.Equal(t, model.PlayerID(`alice`), pa.ID, tc.msg)
+		assert.Equal(t, tc.expAction, pa.Action, tc.msg)
+		assert.Equal(t, tc.expAuto, pa.Auto, tc.msg)
+	}
+}
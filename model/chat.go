@@ -0,0 +1,37 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// MaxChatMessageLength is the most characters that one chat message can have
+	MaxChatMessageLength = 280
+)
+
+var (
+	ErrEmptyChatMessage   error = errors.New(`chat message is empty`)
+	ErrChatMessageTooLong error = errors.New(`chat message is too long`)
+)
+
+// ChatMessage is something a player said to the others in their game
+type ChatMessage struct {
+	GameID   GameID    `json:"gID" bson:"gID"`
+	PlayerID PlayerID  `json:"pID" bson:"pID"`
+	Message  string    `json:"msg" bson:"msg"`
+	Sent     time.Time `json:"sent" bson:"sent"`
+}
+
+// Validate returns an error if the message cannot be sent
+func (cm ChatMessage) Validate() error {
+	if len(strings.TrimSpace(cm.Message)) == 0 {
+		return ErrEmptyChatMessage
+	}
+	if utf8.RuneCountInString(cm.Message) > MaxChatMessageLength {
+		return ErrChatMessageTooLong
+	}
+	return nil
+}
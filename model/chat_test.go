@@ -0,0 +1,55 @@
+package model_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+)
+
+func TestChatMessageValidate(t *testing.T) {
+	testCases := []struct {
+		msg    string
+		cm     model.ChatMessage
+		expErr error
+	}{{
+		msg: `normal message`,
+		cm: model.ChatMessage{
+			Message: `good game`,
+		},
+	}, {
+		msg: `empty message`,
+		cm: model.ChatMessage{
+			Message: ``,
+		},
+		expErr: model.ErrEmptyChatMessage,
+	}, {
+		msg: `only whitespace`,
+		cm: model.ChatMessage{
+			Message: " \t\n",
+		},
+		expErr: model.ErrEmptyChatMessage,
+	}, {
+		msg: `as long as allowed`,
+		cm: model.ChatMessage{
+			Message: strings.Repeat(`a`, model.MaxChatMessageLength),
+		},
+	}, {
+		msg: `counts characters, not bytes`,
+		cm: model.ChatMessage{
+			Message: strings.Repeat(`♣`, model.MaxChatMessageLength),
+		},
+	}, {
+		msg: `too long`,
+		cm: model.ChatMessage{
+			Message: strings.Repeat(`a`, model.MaxChatMessageLength+1),
+		},
+		expErr: model.ErrChatMessageTooLong,
+	}}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expErr, tc.cm.Validate(), tc.msg)
+	}
+}
@@ -104,3 +104,63 @@ func (d *deck) CutDeck(p float64) (Card, error) {
 
 	return d.cards[cutCard], nil
 }
+
+// NewShuffledDeckOrder returns every card in a new deck, in the order they are in
+// after it has been shuffled the given number of times. The top card is first.
+func NewShuffledDeckOrder(numShuffles int) []Card {
+	d := newDeck()
+	for i := 0; i < numShuffles; i++ {
+		d.Shuffle()
+	}
+
+	return append(make([]Card, 0, NumCardsPerDeck), d.cards[:]...)
+}
+
+// stackedDeck is a deck in a known order, which deals from the top like a real one
+type stackedDeck struct {
+	cards    []Card
+	numDealt int
+}
+
+func newStackedDeck(order []Card, numDealt int) Deck {
+	return &stackedDeck{
+		cards:    order,
+		numDealt: numDealt,
+	}
+}
+
+func (d *stackedDeck) Deal() Card {
+	if d.numDealt >= len(d.cards) {
+		return Card{}
+	}
+
+	c := d.cards[d.numDealt]
+	d.numDealt++
+	return c
+}
+
+// Shuffle reorders the cards that have not been dealt yet, in place
+func (d *stackedDeck) Shuffle() {
+	rest := d.cards[d.numDealt:]
+	for i := len(rest) - 1; i > 0; i-- {
+		j := rand.Intn(i + 1)
+		rest[i], rest[j] = rest[j], rest[i]
+	}
+}
+
+// CutDeck returns the card that is the given fraction of the way through the
+// cards that have not been dealt yet
+func (d *stackedDeck) CutDeck(p float64) (Card, error) {
+	if d.numDealt >= len(d.cards) {
+		return Card{}, errors.New(`cannot cut deck with all cards dealt`)
+	}
+
+	rest := d.cards[d.numDealt:]
+	return rest[cutIndex(len(rest), p)], nil
+}
+
+// cutIndex returns the index of the card that is the given fraction of the way
+// through the numCards that are left in the deck
+func cutIndex(numCards int, p float64) int {
+	return int(float64(numCards-1) * p)
+}
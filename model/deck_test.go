@@ -221,3 +221,48 @@ func TestGetDeck(t *testing.T) {
 	}
 
 }
+
+func TestNewShuffledDeckOrder(t *testing.T) {
+	order := NewShuffledDeckOrder(3)
+	require.Len(t, order, NumCardsPerDeck)
+
+	seen := map[Card]struct{}{}
+	for _, c := range order {
+		seen[c] = struct{}{}
+	}
+	assert.Len(t, seen, NumCardsPerDeck)
+}
+
+func TestStackedDeck(t *testing.T) {
+	order := NewShuffledDeckOrder(1)
+	g := Game{
+		Hands: map[PlayerID][]Card{
+			PlayerID(`alice`): order[:4],
+			PlayerID(`bob`):   order[4:8],
+		},
+		Crib:      order[8:12],
+		DeckOrder: order,
+	}
+
+	d, err := g.GetDeck()
+	require.NoError(t, err)
+	_, ok := d.(*stackedDeck)
+	require.True(t, ok)
+
+	c, err := d.CutDeck(0)
+	require.NoError(t, err)
+	assert.Equal(t, order[12], c)
+	c, err = d.CutDeck(1)
+	require.NoError(t, err)
+	assert.Equal(t, order[51], c)
+	c, err = d.CutDeck(0.5)
+	require.NoError(t, err)
+	assert.Equal(t, order[12+19], c)
+
+	for i := 12; i < NumCardsPerDeck; i++ {
+		assert.Equal(t, order[i], d.Deal(), `deals from the top`)
+	}
+	assert.Equal(t, Card{}, d.Deal())
+	_, err = d.CutDeck(0.5)
+	assert.Error(t, err)
+}
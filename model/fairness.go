@@ -0,0 +1,164 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+
+	"github.com/joshprzybyszewski/cribbage/utils/rand"
+)
+
+// ShuffleProof lets the players of a provably fair game check that the server did not
+// stack the deck. Before the deal, the server commits to a secret seed and the deck order
+// that it makes. The dealer mixes in their own entropy, and once the hand is over the seed
+// is revealed so that anyone can recompute the shuffle.
+type ShuffleProof struct {
+	// Commitment is the hex encoded sha256 hash of the server seed and its deck order
+	Commitment string `protobuf:"-" json:"c" bson:"c"` //nolint:lll
+	// ServerSeed is kept secret until the hand is over
+	ServerSeed string `protobuf:"-" json:"ss,omitempty" bson:"ss"` //nolint:lll
+	// ClientEntropy is what the dealer added to the shuffle
+	ClientEntropy string `protobuf:"-" json:"ce,omitempty" bson:"ce"` //nolint:lll
+	// CutPercentage is how far through the undealt cards the deck was cut
+	CutPercentage float64 `protobuf:"-" json:"cp,omitempty" bson:"cp"` //nolint:lll
+}
+
+// NewShuffleProof picks a new server seed and commits to it
+func NewShuffleProof() ShuffleProof {
+	seed := rand.NewSeed()
+	return ShuffleProof{
+		Commitment: shuffleCommitment(seed, seededDeckOrder(seed)),
+		ServerSeed: seed,
+	}
+}
+
+func (sp ShuffleProof) IsRevealed() bool {
+	return sp.ServerSeed != ``
+}
+
+// DeckOrder returns the order of the deck, top card first, that the server
+// seed and the client entropy make together
+func (sp ShuffleProof) DeckOrder() []Card {
+	order := seededDeckOrder(sp.ServerSeed)
+	shuffleWithSeed(order, sp.ServerSeed+`:`+sp.ClientEntropy)
+	return order
+}
+
+// VerifyShuffle checks a revealed ShuffleProof against the commitment that was made
+// before the deal, and returns the order of the deck that it made
+func VerifyShuffle(sp ShuffleProof) ([]Card, error) {
+	if !sp.IsRevealed() {
+		return nil, errors.New(`the server seed has not been revealed`)
+	}
+
+	if shuffleCommitment(sp.ServerSeed, seededDeckOrder(sp.ServerSeed)) != sp.Commitment {
+		return nil, errors.New(`the server seed does not match its commitment`)
+	}
+
+	return sp.DeckOrder(), nil
+}
+
+// CutCardFrom returns the card that cutting the deck at the given percentage of the
+// way through the undealt cards turns up
+func CutCardFrom(order []Card, numPlayers int, p float64) (Card, error) {
+	rest := UndealtCards(order, numPlayers)
+	if len(rest) == 0 {
+		return Card{}, errors.New(`cannot cut deck with all cards dealt`)
+	}
+	return rest[cutIndex(len(rest), p)], nil
+}
+
+// HandSize returns how many cards each player is dealt
+func HandSize(numPlayers int) int {
+	switch numPlayers {
+	case 3, 4:
+		return 5
+	}
+	return 6
+}
+
+// DealtCards returns the cards that were dealt from the deck to the player in the given
+// seat. The first seat is left of the dealer, and the dealer has the last one.
+func DealtCards(order []Card, numPlayers, seat int) []Card {
+	var dealt []Card
+	for i := seat; i < HandSize(numPlayers)*numPlayers && i < len(order); i += numPlayers {
+		dealt = append(dealt, order[i])
+	}
+	return dealt
+}
+
+// UndealtCards returns the cards left in the deck after the deal, which the cut comes from
+func UndealtCards(order []Card, numPlayers int) []Card {
+	numDealt := HandSize(numPlayers) * numPlayers
+	if numPlayers == 3 {
+		// three player games deal one card to the crib
+		numDealt++
+	}
+	if numDealt > len(order) {
+		return nil
+	}
+	return order[numDealt:]
+}
+
+func seededDeckOrder(seed string) []Card {
+	order := make([]Card, NumCardsPerDeck)
+	for i := range order {
+		order[i] = NewCardFromNumber(i)
+	}
+	shuffleWithSeed(order, seed)
+	return order
+}
+
+func shuffleWithSeed(cards []Card, seed string) {
+	r := rand.NewSeeded(seed)
+	for i := len(cards) - 1; i > 0; i-- {
+		j := r.Intn(i + 1)
+		cards[i], cards[j] = cards[j], cards[i]
+	}
+}
+
+func shuffleCommitment(seed string, order []Card) string {
+	h := sha256.New()
+	_, _ = h.Write([]byte(seed))
+	for _, c := range order {
+		_, _ = h.Write([]byte{byte(c.ToTinyInt())})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ShuffleProvablyFairDeck starts the round with the deck that the round's committed
+// server seed and the dealer's entropy make. Its order is kept on the game.
+func (g *Game) ShuffleProvablyFairDeck(entropy string) (Deck, error) {
+	if len(g.Shuffles) == 0 {
+		return nil, errors.New(`the server has not committed to a shuffle`)
+	}
+
+	sp := &g.Shuffles[len(g.Shuffles)-1]
+	sp.ClientEntropy = entropy
+	g.DeckOrder = sp.DeckOrder()
+	return newStackedDeck(g.DeckOrder, 0), nil
+}
+
+// RecordCut keeps where the round's deck was cut on its shuffle proof, so that the
+// cut card can be checked too
+func (g *Game) RecordCut(p float64) {
+	if len(g.Shuffles) == 0 {
+		return
+	}
+	g.Shuffles[len(g.Shuffles)-1].CutPercentage = p
+}
+
+// RevealedShuffles returns the game's shuffle proofs, one for each round. The server seed
+// for the current round stays hidden until all of its cards have been played.
+func (g *Game) RevealedShuffles() []ShuffleProof {
+	if len(g.Shuffles) == 0 {
+		return nil
+	}
+
+	sps := make([]ShuffleProof, len(g.Shuffles))
+	_ = copy(sps, g.Shuffles)
+	if !g.IsOver() && g.Phase < Counting {
+		sps[len(sps)-1].ServerSeed = ``
+	}
+	return sps
+}
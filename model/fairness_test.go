@@ -0,0 +1,121 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyShuffle(t *testing.T) {
+	sp := NewShuffleProof()
+	require.NotEmpty(t, sp.Commitment)
+	require.True(t, sp.IsRevealed())
+	sp.ClientEntropy = `some entropy`
+
+	order, err := VerifyShuffle(sp)
+	require.NoError(t, err)
+	assert.Len(t, order, NumCardsPerDeck)
+	assert.Equal(t, sp.DeckOrder(), order)
+	seen := make(map[Card]struct{}, len(order))
+	for _, c := range order {
+		seen[c] = struct{}{}
+	}
+	assert.Len(t, seen, NumCardsPerDeck, `every card is in the deck once`)
+
+	otherEntropy := sp
+	otherEntropy.ClientEntropy = `other entropy`
+	assert.NotEqual(t, order, otherEntropy.DeckOrder(), `the dealer's entropy changes the shuffle`)
+
+	hidden := sp
+	hidden.ServerSeed = ``
+	_, err = VerifyShuffle(hidden)
+	assert.EqualError(t, err, `the server seed has not been revealed`)
+
+	swapped := sp
+	swapped.ServerSeed = NewShuffleProof().ServerSeed
+	_, err = VerifyShuffle(swapped)
+	assert.EqualError(t, err, `the server seed does not match its commitment`)
+}
+
+func TestDealtCards(t *testing.T) {
+	order := make([]Card, NumCardsPerDeck)
+	for i := range order {
+		order[i] = NewCardFromNumber(i)
+	}
+
+	assert.Equal(t, []Card{order[0], order[2], order[4], order[6], order[8], order[10]}, DealtCards(order, 2, 0))
+	assert.Equal(t, []Card{order[1], order[3], order[5], order[7], order[9], order[11]}, DealtCards(order, 2, 1))
+	assert.Equal(t, []Card{order[2], order[5], order[8], order[11], order[14]}, DealtCards(order, 3, 2))
+	assert.Equal(t, []Card{order[3], order[7], order[11], order[15], order[19]}, DealtCards(order, 4, 3))
+
+	assert.Equal(t, order[12:], UndealtCards(order, 2))
+	assert.Equal(t, order[16:], UndealtCards(order, 3))
+	assert.Equal(t, order[20:], UndealtCards(order, 4))
+	assert.Empty(t, UndealtCards(order[:10], 2))
+}
+
+func TestCutCardFrom(t *testing.T) {
+	order := make([]Card, NumCardsPerDeck)
+	for i := range order {
+		order[i] = NewCardFromNumber(i)
+	}
+
+	for _, numPlayers := range []int{2, 3, 4} {
+		for _, p := range []float64{0, 0.25, 0.5, 0.999, 1} {
+			deck := newStackedDeck(order, NumCardsPerDeck-len(UndealtCards(order, numPlayers)))
+			exp, err := deck.CutDeck(p)
+			require.NoError(t, err)
+
+			// the players find the same card that the server cut
+			c, err := CutCardFrom(order, numPlayers, p)
+			require.NoError(t, err)
+			assert.Equal(t, exp, c, `%d players, cut at %v`, numPlayers, p)
+		}
+	}
+
+	c, err := CutCardFrom(order, 2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, order[12], c, `the top of the undealt cards`)
+	c, err = CutCardFrom(order, 2, 1)
+	require.NoError(t, err)
+	assert.Equal(t, order[NumCardsPerDeck-1], c, `the bottom of the deck`)
+
+	_, err = CutCardFrom(order[:10], 2, 0.5)
+	assert.Error(t, err)
+}
+
+func TestRevealedShuffles(t *testing.T) {
+	g := Game{
+		Phase: Pegging,
+	}
+	assert.Nil(t, g.RevealedShuffles())
+
+	g.Shuffles = []ShuffleProof{NewShuffleProof(), NewShuffleProof()}
+	sps := g.RevealedShuffles()
+	require.Len(t, sps, 2)
+	assert.Equal(t, g.Shuffles[0], sps[0], `the previous round is over`)
+	assert.Equal(t, g.Shuffles[1].Commitment, sps[1].Commitment)
+	assert.Empty(t, sps[1].ServerSeed)
+	assert.NotEmpty(t, g.Shuffles[1].ServerSeed, `the game keeps its seed`)
+
+	g.Phase = Counting
+	assert.Equal(t, g.Shuffles, g.RevealedShuffles())
+
+	g.Phase = Deal
+	g.Outcome.Reason = Forfeited
+	assert.Equal(t, g.Shuffles, g.RevealedShuffles())
+}
+
+func TestShuffleProvablyFairDeck(t *testing.T) {
+	g := Game{}
+	_, err := g.ShuffleProvablyFairDeck(`entropy`)
+	assert.Error(t, err)
+
+	g.Shuffles = []ShuffleProof{NewShuffleProof()}
+	d, err := g.ShuffleProvablyFairDeck(`entropy`)
+	require.NoError(t, err)
+	assert.Equal(t, `entropy`, g.Shuffles[0].ClientEntropy)
+	assert.Equal(t, g.Shuffles[0].DeckOrder(), g.DeckOrder)
+	assert.Equal(t, g.DeckOrder[0], d.Deal())
+}
@@ -2,14 +2,26 @@ package model
 
 import (
 	"errors"
+	"sort"
 )
 
+// GetDeck returns the deck for the current round, with the cards that are in the
+// hands and the crib already dealt from it
 func (g *Game) GetDeck() (Deck, error) {
 	emptyCard := Card{}
 	if emptyCard != g.CutCard {
 		return nil, errors.New(`cannot get deck when there is a cut card`)
 	}
 
+	if len(g.DeckOrder) > 0 {
+		numDealt := len(g.Crib)
+		for _, hand := range g.Hands {
+			numDealt += len(hand)
+		}
+		return newStackedDeck(g.DeckOrder, numDealt), nil
+	}
+
+	// games from before we kept the order of the deck get a random one
 	allDealtCards := map[Card]struct{}{}
 	for _, hand := range g.Hands {
 		for _, c := range hand {
@@ -23,7 +35,17 @@ func (g *Game) GetDeck() (Deck, error) {
 	return newDeckWithDealt(allDealtCards), nil
 }
 
+// ShuffleDeck starts the round with a new deck, which has been shuffled the given
+// number of times. Its order is kept on the game, and it is ready to deal from.
+func (g *Game) ShuffleDeck(numShuffles int) Deck {
+	g.DeckOrder = NewShuffledDeckOrder(numShuffles)
+	return newStackedDeck(g.DeckOrder, 0)
+}
+
 func (g *Game) IsOver() bool {
+	if g.Outcome.Reason != NoOutcome {
+		return true
+	}
 	for _, score := range g.CurrentScores {
 		if score >= WinningScore {
 			return true
@@ -32,6 +54,81 @@ func (g *Game) IsOver() bool {
 	return false
 }
 
+// Winners returns the colors of the players who won the game.
+// If the game isn't over, nobody has won.
+func (g *Game) Winners() []PlayerColor {
+	if !g.IsOver() {
+		return nil
+	}
+
+	var winners []PlayerColor
+	for c, score := range g.CurrentScores {
+		if g.Outcome.Reason != NoOutcome {
+			// everyone who didn't forfeit wins
+			if c != g.PlayerColors[g.Outcome.PlayerID] {
+				winners = append(winners, c)
+			}
+		} else if score >= WinningScore {
+			winners = append(winners, c)
+		}
+	}
+	sort.Slice(winners, func(i, j int) bool {
+		return winners[i] < winners[j]
+	})
+	return winners
+}
+
+// IsHandRevealed returns true if everyone is allowed to see the player's hand.
+// Hands are shown as they are counted, which starts left of the dealer and ends
+// with the dealer. Once the crib is being counted, every hand has been shown.
+func (g *Game) IsHandRevealed(pID PlayerID) bool {
+	if g.IsOver() || g.Phase > Counting {
+		return true
+	}
+	if g.Phase < Counting {
+		return false
+	}
+
+	counting := InvalidPlayerID
+	for bpID, b := range g.BlockingPlayers {
+		if b == CountHand {
+			counting = bpID
+			break
+		}
+	}
+	if counting == InvalidPlayerID {
+		return true
+	}
+
+	for _, id := range g.countOrder() {
+		if id == pID {
+			// this player has counted (or is counting) their hand
+			return true
+		}
+		if id == counting {
+			return false
+		}
+	}
+	return false
+}
+
+// countOrder returns the players in the order that they count their hands
+func (g *Game) countOrder() []PlayerID {
+	dealerIndex := 0
+	for i, p := range g.Players {
+		if p.ID == g.CurrentDealer {
+			dealerIndex = i
+			break
+		}
+	}
+
+	order := make([]PlayerID, 0, len(g.Players))
+	for i := 1; i <= len(g.Players); i++ {
+		order = append(order, g.Players[(dealerIndex+i)%len(g.Players)].ID)
+	}
+	return order
+}
+
 func (g *Game) NumActions() int {
 	return len(g.Actions)
 }
@@ -40,29 +137,61 @@ func (g *Game) AddAction(a PlayerAction) {
 	g.Actions = append(g.Actions, a)
 }
 
+// CurrentPeg returns the count of the current pegging series
 func (g *Game) CurrentPeg() int {
-	if len(g.PeggedCards) == 0 {
-		return 0
-	}
 	if g.Phase > Pegging {
 		return 0
 	}
+	if g.Pegging.isEmpty() && len(g.PeggedCards) > 0 {
+		// the game was saved before we kept track of the pegging state
+		return g.derivedPegging().Count
+	}
+	return g.Pegging.Count
+}
+
+// FillLegacyPegging fills in the pegging state of a game that was saved before we
+// kept track of it, so that pegging carries on from the current series
+func (g *Game) FillLegacyPegging() {
+	if g.Phase != Pegging || !g.Pegging.isEmpty() || len(g.PeggedCards) == 0 {
+		return
+	}
+	g.Pegging = g.derivedPegging()
+}
+
+// derivedPegging works out the current series from the pegged cards and the actions
+func (g *Game) derivedPegging() PeggingState {
 	if g.goesAround() {
-		return 0
+		return PeggingState{
+			SeriesStart: len(g.PeggedCards),
+		}
 	}
 
-	cur := 0
-	for _, pc := range g.PeggedCards {
+	ps := PeggingState{}
+	for i, pc := range g.PeggedCards {
 		pv := pc.Card.PegValue()
-		cur += pv
-		if cur > MaxPeggingValue {
-			cur = pv
+		if ps.Count+pv > MaxPeggingValue {
+			ps.SeriesStart = i
+			ps.Count = 0
+		}
+		ps.Count += pv
+		if ps.Count == MaxPeggingValue {
+			ps.SeriesStart = i + 1
+			ps.Count = 0
 		}
 	}
-	if cur == MaxPeggingValue {
-		return 0
+	if ps.SeriesStart == len(g.PeggedCards) {
+		return ps
+	}
+
+	ps.LastPegger = g.PeggedCards[len(g.PeggedCards)-1].PlayerID
+	// the goes said since the first card of the series are still sitting out
+	for actIndex := g.PeggedCards[ps.SeriesStart].Action; actIndex < g.NumActions(); actIndex++ {
+		act := g.Actions[actIndex]
+		if pa, ok := act.Action.(PegAction); ok && pa.SayGo {
+			ps.Goes = append(ps.Goes, act.ID)
+		}
 	}
-	return cur
+	return ps
 }
 
 func (g *Game) goesAround() bool {
@@ -57,6 +57,19 @@ func TestIsOver(t *testing.T) {
 			},
 		},
 		expOver: true,
+	}, {
+		msg: `when someone forfeits`,
+		game: model.Game{
+			CurrentScores: map[model.PlayerColor]int{
+				model.Blue: 12,
+				model.Red:  3,
+			},
+			Outcome: model.GameOutcome{
+				Reason:   model.Forfeited,
+				PlayerID: model.PlayerID(`alice`),
+			},
+		},
+		expOver: true,
 	}}
 
 	for _, tc := range testCases {
@@ -64,6 +77,134 @@ func TestIsOver(t *testing.T) {
 	}
 }
 
+func TestWinners(t *testing.T) {
+	testCases := []struct {
+		msg        string
+		game       model.Game
+		expWinners []model.PlayerColor
+	}{{
+		msg: `not over`,
+		game: model.Game{
+			CurrentScores: map[model.PlayerColor]int{
+				model.Blue: 120,
+				model.Red:  12,
+			},
+		},
+		expWinners: nil,
+	}, {
+		msg: `reached the winning score`,
+		game: model.Game{
+			CurrentScores: map[model.PlayerColor]int{
+				model.Blue: 100,
+				model.Red:  121,
+			},
+		},
+		expWinners: []model.PlayerColor{model.Red},
+	}, {
+		msg: `everyone else wins on a forfeit`,
+		game: model.Game{
+			PlayerColors: map[model.PlayerID]model.PlayerColor{
+				model.PlayerID(`alice`):   model.Blue,
+				model.PlayerID(`bob`):     model.Red,
+				model.PlayerID(`charlie`): model.Green,
+			},
+			CurrentScores: map[model.PlayerColor]int{
+				model.Blue:  100,
+				model.Red:   12,
+				model.Green: 50,
+			},
+			Outcome: model.GameOutcome{
+				Reason:   model.TimedOut,
+				PlayerID: model.PlayerID(`alice`),
+			},
+		},
+		expWinners: []model.PlayerColor{model.Green, model.Red},
+	}}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expWinners, tc.game.Winners(), tc.msg)
+	}
+}
+
+func TestIsHandRevealed(t *testing.T) {
+	alice, bob, charlie, _ := testutils.AliceBobCharlieDiane()
+	players := []model.Player{alice, bob, charlie}
+
+	testCases := []struct {
+		msg         string
+		phase       model.Phase
+		blockers    map[model.PlayerID]model.Blocker
+		expRevealed map[model.PlayerID]bool
+	}{{
+		msg:   `pegging`,
+		phase: model.Pegging,
+		blockers: map[model.PlayerID]model.Blocker{
+			alice.ID: model.PegCard,
+		},
+		expRevealed: map[model.PlayerID]bool{
+			alice.ID:   false,
+			bob.ID:     false,
+			charlie.ID: false,
+		},
+	}, {
+		msg:   `the player left of the dealer counts first`,
+		phase: model.Counting,
+		blockers: map[model.PlayerID]model.Blocker{
+			charlie.ID: model.CountHand,
+		},
+		expRevealed: map[model.PlayerID]bool{
+			alice.ID:   false,
+			bob.ID:     false,
+			charlie.ID: true,
+		},
+	}, {
+		msg:   `the dealer counts last`,
+		phase: model.Counting,
+		blockers: map[model.PlayerID]model.Blocker{
+			alice.ID: model.CountHand,
+		},
+		expRevealed: map[model.PlayerID]bool{
+			alice.ID:   true,
+			bob.ID:     false,
+			charlie.ID: true,
+		},
+	}, {
+		msg:   `the dealer is counting`,
+		phase: model.Counting,
+		blockers: map[model.PlayerID]model.Blocker{
+			bob.ID: model.CountHand,
+		},
+		expRevealed: map[model.PlayerID]bool{
+			alice.ID:   true,
+			bob.ID:     true,
+			charlie.ID: true,
+		},
+	}, {
+		msg:   `counting the crib`,
+		phase: model.CribCounting,
+		blockers: map[model.PlayerID]model.Blocker{
+			bob.ID: model.CountCrib,
+		},
+		expRevealed: map[model.PlayerID]bool{
+			alice.ID:   true,
+			bob.ID:     true,
+			charlie.ID: true,
+		},
+	}}
+
+	for _, tc := range testCases {
+		g := model.Game{
+			Players:         players,
+			CurrentDealer:   bob.ID,
+			Phase:           tc.phase,
+			BlockingPlayers: tc.blockers,
+		}
+		for pID, exp := range tc.expRevealed {
+			assert.Equal(t, exp, g.IsHandRevealed(pID), tc.msg+` for `+string(pID))
+		}
+	}
+}
+
 func TestNumActions(t *testing.T) {
 	alice, bob, charlie, diane := testutils.AliceBobCharlieDiane()
 
@@ -448,6 +589,24 @@ func TestCurrentPeg(t *testing.T) {
 			}},
 		},
 		expPeg: 0,
+	}, {
+		msg: `in the middle of a series`,
+		game: model.Game{
+			Phase: model.Pegging,
+			Pegging: model.PeggingState{
+				Count: 21,
+			},
+		},
+		expPeg: 21,
+	}, {
+		msg: `after the pegging phase`,
+		game: model.Game{
+			Phase: model.Counting,
+			Pegging: model.PeggingState{
+				Count: 4,
+			},
+		},
+		expPeg: 0,
 	}}
 
 	for _, tc := range testCases {
@@ -455,6 +614,73 @@ func TestCurrentPeg(t *testing.T) {
 	}
 }
 
+func TestFillLegacyPegging(t *testing.T) {
+	alice, bob, _, _ := testutils.AliceBobCharlieDiane()
+
+	pegs := []model.PlayerAction{{
+		ID:        alice.ID,
+		Overcomes: model.PegCard,
+		Action:    model.PegAction{Card: model.NewCardFromString(`ks`)},
+	}, {
+		ID:        bob.ID,
+		Overcomes: model.PegCard,
+		Action:    model.PegAction{Card: model.NewCardFromString(`kh`)},
+	}, {
+		ID:        alice.ID,
+		Overcomes: model.PegCard,
+		Action:    model.PegAction{Card: model.NewCardFromString(`qs`)},
+	}, {
+		ID:        bob.ID,
+		Overcomes: model.PegCard,
+		Action:    model.PegAction{Card: model.NewCardFromString(`qh`)},
+	}, {
+		ID:        alice.ID,
+		Overcomes: model.PegCard,
+		Action:    model.PegAction{SayGo: true},
+	}}
+	g := model.Game{
+		Phase: model.Pegging,
+		PeggedCards: []model.PeggedCard{
+			model.NewPeggedCardFromString(alice.ID, `ks`, 1),
+			model.NewPeggedCardFromString(bob.ID, `kh`, 2),
+			model.NewPeggedCardFromString(alice.ID, `qs`, 3),
+			model.NewPeggedCardFromString(bob.ID, `qh`, 4),
+		},
+		Actions: pegs,
+	}
+
+	g.FillLegacyPegging()
+	assert.Equal(t, model.PeggingState{
+		Count:       10,
+		SeriesStart: 3,
+		Goes:        []model.PlayerID{alice.ID},
+		LastPegger:  bob.ID,
+	}, g.Pegging)
+
+	// a game that keeps its pegging state is left alone
+	g.Pegging = model.PeggingState{
+		Count:       20,
+		SeriesStart: 1,
+	}
+	g.FillLegacyPegging()
+	assert.Equal(t, model.PeggingState{
+		Count:       20,
+		SeriesStart: 1,
+	}, g.Pegging)
+}
+
+func TestPeggingStateHasSaidGo(t *testing.T) {
+	alice, bob, charlie, _ := testutils.AliceBobCharlieDiane()
+
+	ps := model.PeggingState{
+		Goes: []model.PlayerID{alice.ID, charlie.ID},
+	}
+	assert.True(t, ps.HasSaidGo(alice.ID))
+	assert.False(t, ps.HasSaidGo(bob.ID))
+	assert.True(t, ps.HasSaidGo(charlie.ID))
+	assert.False(t, model.PeggingState{}.HasSaidGo(alice.ID))
+}
+
 func TestPhaseString(t *testing.T) {
 	testCases := []struct {
 		input model.Phase
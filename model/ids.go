@@ -26,6 +26,21 @@ func NewGameID() GameID {
 	return gID
 }
 
+func NewLobbyID() LobbyID {
+	lID := InvalidLobbyID
+	for lID == InvalidLobbyID {
+		r, err := uuid.NewRandom()
+		if err != nil {
+			log.Printf("NewLobbyID.NewRandom failed\n")
+			return InvalidLobbyID
+		}
+
+		lID = LobbyID(r.ID())
+	}
+
+	return lID
+}
+
 func IsValidPlayerID(pID PlayerID) bool {
 	return validPIDRegex.MatchString(string(pID))
 }
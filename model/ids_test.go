@@ -14,6 +14,13 @@ func TestNewGameID(t *testing.T) {
 	}
 }
 
+func TestNewLobbyID(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		lID := NewLobbyID()
+		require.NotEqual(t, InvalidLobbyID, lID)
+	}
+}
+
 func TestIsValidPlayerID(t *testing.T) {
 	testCases := []struct {
 		msg     string
@@ -0,0 +1,205 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrLobbyNotOpen      error = errors.New(`lobby is not open`)
+	ErrLobbyFull         error = errors.New(`lobby is full`)
+	ErrAlreadyInLobby    error = errors.New(`player is already in the lobby`)
+	ErrNotInLobby        error = errors.New(`player is not in the lobby`)
+	ErrInvalidNumPlayers error = errors.New(`invalid number of players`)
+	ErrNotInvited        error = errors.New(`player was not invited`)
+)
+
+type LobbyID uint32
+
+const (
+	InvalidLobbyID LobbyID = 0
+)
+
+type LobbyStatus int
+
+const (
+	// LobbyOpen is waiting for players to fill its seats
+	LobbyOpen LobbyStatus = 0
+	// LobbyStarted has filled its seats and started a game
+	LobbyStarted LobbyStatus = 1
+	// LobbyClosed was abandoned by all of its players
+	LobbyClosed LobbyStatus = 2
+	// LobbyDeclined was an invitation that one of the invitees declined
+	LobbyDeclined      LobbyStatus = 3
+	unknownLobbyStatus LobbyStatus = -1
+)
+
+func (ls LobbyStatus) String() string {
+	switch ls {
+	case LobbyOpen:
+		return `open`
+	case LobbyStarted:
+		return `started`
+	case LobbyClosed:
+		return `closed`
+	case LobbyDeclined:
+		return `declined`
+	}
+	return `unknown`
+}
+
+func NewLobbyStatusFromString(ls string) LobbyStatus {
+	switch ls {
+	case `open`:
+		return LobbyOpen
+	case `started`:
+		return LobbyStarted
+	case `closed`:
+		return LobbyClosed
+	case `declined`:
+		return LobbyDeclined
+	}
+	return unknownLobbyStatus
+}
+
+// Lobby is where players wait for a game to start
+type Lobby struct {
+	ID LobbyID `json:"lobbyID" bson:"lobbyID"`
+	// The player who opened the lobby. If they leave, the next seated player becomes the host.
+	Host PlayerID `json:"host" bson:"host"`
+	// How many players the game will have
+	NumPlayers int `json:"np" bson:"np"`
+	// The players in the lobby, in the order that they will play
+	Seated []PlayerID  `json:"seated" bson:"seated"`
+	Status LobbyStatus `json:"status" bson:"status"`
+	// If set, the lobby is private and only these players may take a seat.
+	// They each need to accept their invitation before the game starts.
+	Invited []PlayerID `json:"invited,omitempty" bson:"invited"`
+
+	// If set, this type of NPC fills any empty seats once FillAt has passed
+	NPCFill PlayerID  `json:"npc,omitempty" bson:"npc"`
+	FillAt  time.Time `json:"fillAt,omitempty" bson:"fillAt"`
+
+	// The settings for the game that gets created
+	Settings GameSettings `json:"set" bson:"set"`
+
+	Created time.Time `json:"created" bson:"created"`
+	// The game which was created when the lobby filled
+	GameID GameID `json:"gID,omitempty" bson:"gID"`
+
+	// Version goes up every time the lobby is saved, so that two players
+	// cannot both change the same version of the lobby
+	Version uint64 `json:"v,omitempty" bson:"v"`
+}
+
+func (l *Lobby) IsFull() bool {
+	return len(l.Seated) >= l.NumPlayers
+}
+
+func (l *Lobby) IsSeated(pID PlayerID) bool {
+	for _, s := range l.Seated {
+		if s == pID {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPrivate returns true if only invited players can sit in the lobby
+func (l *Lobby) IsPrivate() bool {
+	return len(l.Invited) > 0
+}
+
+func (l *Lobby) IsInvited(pID PlayerID) bool {
+	for _, i := range l.Invited {
+		if i == pID {
+			return true
+		}
+	}
+	return false
+}
+
+// Pending returns the invited players who have not accepted yet
+func (l *Lobby) Pending() []PlayerID {
+	pending := make([]PlayerID, 0, len(l.Invited))
+	for _, i := range l.Invited {
+		if !l.IsSeated(i) {
+			pending = append(pending, i)
+		}
+	}
+	return pending
+}
+
+// ShouldFill returns true when the empty seats should be filled with NPCs
+func (l *Lobby) ShouldFill(now time.Time) bool {
+	return l.Status == LobbyOpen &&
+		l.NPCFill != InvalidPlayerID &&
+		!l.IsFull() &&
+		!now.Before(l.FillAt)
+}
+
+// Sit puts the player in the next open seat
+func (l *Lobby) Sit(pID PlayerID) error {
+	if l.Status != LobbyOpen {
+		return ErrLobbyNotOpen
+	}
+	if l.IsSeated(pID) {
+		return ErrAlreadyInLobby
+	}
+	if l.IsPrivate() && pID != l.Host && !l.IsInvited(pID) {
+		return ErrNotInvited
+	}
+	if l.IsFull() {
+		return ErrLobbyFull
+	}
+
+	l.Seated = append(l.Seated, pID)
+	return nil
+}
+
+// Decline turns down the invitation to the lobby. The game cannot
+// start without the invited player, so the lobby will never open again.
+func (l *Lobby) Decline(pID PlayerID) error {
+	if l.Status != LobbyOpen {
+		return ErrLobbyNotOpen
+	}
+	if !l.IsInvited(pID) {
+		return ErrNotInvited
+	}
+	if l.IsSeated(pID) {
+		return ErrAlreadyInLobby
+	}
+
+	l.Status = LobbyDeclined
+	return nil
+}
+
+// Leave removes the player from the lobby. If the host leaves, the next
+// player in line becomes the host, and when everyone leaves, the lobby closes.
+// A private lobby closes as soon as its host leaves.
+func (l *Lobby) Leave(pID PlayerID) error {
+	if l.Status != LobbyOpen {
+		return ErrLobbyNotOpen
+	}
+
+	seated := make([]PlayerID, 0, len(l.Seated))
+	for _, s := range l.Seated {
+		if s != pID {
+			seated = append(seated, s)
+		}
+	}
+	if len(seated) == len(l.Seated) {
+		return ErrNotInLobby
+	}
+	l.Seated = seated
+
+	if len(l.Seated) == 0 || (l.IsPrivate() && l.Host == pID) {
+		// nobody is left, or the host has taken back their invitations
+		l.Status = LobbyClosed
+		return nil
+	}
+	if l.Host == pID {
+		l.Host = l.Seated[0]
+	}
+	return nil
+}
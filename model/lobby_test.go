@@ -0,0 +1,304 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+)
+
+func TestLobbySit(t *testing.T) {
+	testCases := []struct {
+		msg       string
+		lobby     model.Lobby
+		pID       model.PlayerID
+		expErr    error
+		expSeated []model.PlayerID
+	}{{
+		msg: `takes the next seat`,
+		lobby: model.Lobby{
+			NumPlayers: 3,
+			Seated:     []model.PlayerID{`alice`},
+		},
+		pID:       `bob`,
+		expSeated: []model.PlayerID{`alice`, `bob`},
+	}, {
+		msg: `cannot sit twice`,
+		lobby: model.Lobby{
+			NumPlayers: 3,
+			Seated:     []model.PlayerID{`alice`},
+		},
+		pID:       `alice`,
+		expErr:    model.ErrAlreadyInLobby,
+		expSeated: []model.PlayerID{`alice`},
+	}, {
+		msg: `no empty seats`,
+		lobby: model.Lobby{
+			NumPlayers: 2,
+			Seated:     []model.PlayerID{`alice`, `bob`},
+		},
+		pID:       `charlie`,
+		expErr:    model.ErrLobbyFull,
+		expSeated: []model.PlayerID{`alice`, `bob`},
+	}, {
+		msg: `already started`,
+		lobby: model.Lobby{
+			NumPlayers: 2,
+			Seated:     []model.PlayerID{`alice`},
+			Status:     model.LobbyStarted,
+		},
+		pID:       `bob`,
+		expErr:    model.ErrLobbyNotOpen,
+		expSeated: []model.PlayerID{`alice`},
+	}, {
+		msg: `invited to a private lobby`,
+		lobby: model.Lobby{
+			Host:       `alice`,
+			NumPlayers: 3,
+			Seated:     []model.PlayerID{`alice`},
+			Invited:    []model.PlayerID{`bob`, `charlie`},
+		},
+		pID:       `charlie`,
+		expSeated: []model.PlayerID{`alice`, `charlie`},
+	}, {
+		msg: `not invited to a private lobby`,
+		lobby: model.Lobby{
+			Host:       `alice`,
+			NumPlayers: 2,
+			Seated:     []model.PlayerID{`alice`},
+			Invited:    []model.PlayerID{`bob`},
+		},
+		pID:       `charlie`,
+		expErr:    model.ErrNotInvited,
+		expSeated: []model.PlayerID{`alice`},
+	}}
+
+	for _, tc := range testCases {
+		err := tc.lobby.Sit(tc.pID)
+		assert.Equal(t, tc.expErr, err, tc.msg)
+		assert.Equal(t, tc.expSeated, tc.lobby.Seated, tc.msg)
+	}
+}
+
+func TestLobbyLeave(t *testing.T) {
+	testCases := []struct {
+		msg      string
+		lobby    model.Lobby
+		pID      model.PlayerID
+		expErr   error
+		expLobby model.Lobby
+	}{{
+		msg: `guest leaves`,
+		lobby: model.Lobby{
+			Host:       `alice`,
+			NumPlayers: 3,
+			Seated:     []model.PlayerID{`alice`, `bob`},
+		},
+		pID: `bob`,
+		expLobby: model.Lobby{
+			Host:       `alice`,
+			NumPlayers: 3,
+			Seated:     []model.PlayerID{`alice`},
+		},
+	}, {
+		msg: `host leaves`,
+		lobby: model.Lobby{
+			Host:       `alice`,
+			NumPlayers: 3,
+			Seated:     []model.PlayerID{`alice`, `bob`, `charlie`},
+		},
+		pID: `alice`,
+		expLobby: model.Lobby{
+			Host:       `bob`,
+			NumPlayers: 3,
+			Seated:     []model.PlayerID{`bob`, `charlie`},
+		},
+	}, {
+		msg: `last player leaves`,
+		lobby: model.Lobby{
+			Host:       `alice`,
+			NumPlayers: 2,
+			Seated:     []model.PlayerID{`alice`},
+		},
+		pID: `alice`,
+		expLobby: model.Lobby{
+			Host:       `alice`,
+			NumPlayers: 2,
+			Seated:     []model.PlayerID{},
+			Status:     model.LobbyClosed,
+		},
+	}, {
+		msg: `host of a private lobby leaves`,
+		lobby: model.Lobby{
+			Host:       `alice`,
+			NumPlayers: 3,
+			Seated:     []model.PlayerID{`alice`, `bob`},
+			Invited:    []model.PlayerID{`bob`, `charlie`},
+		},
+		pID: `alice`,
+		expLobby: model.Lobby{
+			Host:       `alice`,
+			NumPlayers: 3,
+			Seated:     []model.PlayerID{`bob`},
+			Invited:    []model.PlayerID{`bob`, `charlie`},
+			Status:     model.LobbyClosed,
+		},
+	}, {
+		msg: `not seated`,
+		lobby: model.Lobby{
+			Host:       `alice`,
+			NumPlayers: 2,
+			Seated:     []model.PlayerID{`alice`},
+		},
+		pID:    `bob`,
+		expErr: model.ErrNotInLobby,
+		expLobby: model.Lobby{
+			Host:       `alice`,
+			NumPlayers: 2,
+			Seated:     []model.PlayerID{`alice`},
+		},
+	}, {
+		msg: `already started`,
+		lobby: model.Lobby{
+			Host:       `alice`,
+			NumPlayers: 2,
+			Seated:     []model.PlayerID{`alice`, `bob`},
+			Status:     model.LobbyStarted,
+		},
+		pID:    `bob`,
+		expErr: model.ErrLobbyNotOpen,
+		expLobby: model.Lobby{
+			Host:       `alice`,
+			NumPlayers: 2,
+			Seated:     []model.PlayerID{`alice`, `bob`},
+			Status:     model.LobbyStarted,
+		},
+	}}
+
+	for _, tc := range testCases {
+		err := tc.lobby.Leave(tc.pID)
+		assert.Equal(t, tc.expErr, err, tc.msg)
+		assert.Equal(t, tc.expLobby, tc.lobby, tc.msg)
+	}
+}
+
+func TestLobbyShouldFill(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		msg   string
+		lobby model.Lobby
+		exp   bool
+	}{{
+		msg: `done waiting`,
+		lobby: model.Lobby{
+			NumPlayers: 2,
+			Seated:     []model.PlayerID{`alice`},
+			NPCFill:    `SimpleNPC`,
+			FillAt:     now.Add(-time.Second),
+		},
+		exp: true,
+	}, {
+		msg: `still waiting`,
+		lobby: model.Lobby{
+			NumPlayers: 2,
+			Seated:     []model.PlayerID{`alice`},
+			NPCFill:    `SimpleNPC`,
+			FillAt:     now.Add(time.Second),
+		},
+		exp: false,
+	}, {
+		msg: `never fills`,
+		lobby: model.Lobby{
+			NumPlayers: 2,
+			Seated:     []model.PlayerID{`alice`},
+		},
+		exp: false,
+	}, {
+		msg: `already full`,
+		lobby: model.Lobby{
+			NumPlayers: 2,
+			Seated:     []model.PlayerID{`alice`, `bob`},
+			NPCFill:    `SimpleNPC`,
+			FillAt:     now.Add(-time.Second),
+		},
+		exp: false,
+	}, {
+		msg: `closed`,
+		lobby: model.Lobby{
+			NumPlayers: 2,
+			Status:     model.LobbyClosed,
+			NPCFill:    `SimpleNPC`,
+			FillAt:     now.Add(-time.Second),
+		},
+		exp: false,
+	}}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.exp, tc.lobby.ShouldFill(now), tc.msg)
+	}
+}
+
+func TestLobbyDecline(t *testing.T) {
+	testCases := []struct {
+		msg       string
+		status    model.LobbyStatus
+		pID       model.PlayerID
+		expErr    error
+		expStatus model.LobbyStatus
+	}{{
+		msg:       `invitee declines`,
+		pID:       `charlie`,
+		expStatus: model.LobbyDeclined,
+	}, {
+		msg:       `already accepted`,
+		pID:       `bob`,
+		expErr:    model.ErrAlreadyInLobby,
+		expStatus: model.LobbyOpen,
+	}, {
+		msg:       `was not invited`,
+		pID:       `diane`,
+		expErr:    model.ErrNotInvited,
+		expStatus: model.LobbyOpen,
+	}, {
+		msg:       `already closed`,
+		status:    model.LobbyClosed,
+		pID:       `charlie`,
+		expErr:    model.ErrLobbyNotOpen,
+		expStatus: model.LobbyClosed,
+	}}
+
+	for _, tc := range testCases {
+		l := model.Lobby{
+			Host:       `alice`,
+			NumPlayers: 3,
+			Seated:     []model.PlayerID{`alice`, `bob`},
+			Invited:    []model.PlayerID{`bob`, `charlie`},
+			Status:     tc.status,
+		}
+		err := l.Decline(tc.pID)
+		assert.Equal(t, tc.expErr, err, tc.msg)
+		assert.Equal(t, tc.expStatus, l.Status, tc.msg)
+	}
+}
+
+func TestLobbyPending(t *testing.T) {
+	l := model.Lobby{
+		Host:       `alice`,
+		NumPlayers: 4,
+		Seated:     []model.PlayerID{`alice`, `charlie`},
+		Invited:    []model.PlayerID{`bob`, `charlie`, `diane`},
+	}
+	assert.True(t, l.IsPrivate())
+	assert.Equal(t, []model.PlayerID{`bob`, `diane`}, l.Pending())
+
+	l = model.Lobby{
+		Host:       `alice`,
+		NumPlayers: 2,
+		Seated:     []model.PlayerID{`alice`},
+	}
+	assert.False(t, l.IsPrivate())
+	assert.Empty(t, l.Pending())
+}
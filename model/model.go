@@ -96,13 +96,16 @@ type Player struct {
 type Blocker int
 
 const (
-	DealCards      Blocker = 0
-	CribCard       Blocker = 1
-	CutCard        Blocker = 2
-	PegCard        Blocker = 3
-	CountHand      Blocker = 4
-	CountCrib      Blocker = 5
-	unknownBlocker Blocker = -1
+	DealCards       Blocker = 0
+	CribCard        Blocker = 1
+	CutCard         Blocker = 2
+	PegCard         Blocker = 3
+	CountHand       Blocker = 4
+	CountCrib       Blocker = 5
+	Forfeit         Blocker = 6 // nobody is blocked by this, but anyone can forfeit
+	RequestTakeback Blocker = 7 // nobody is blocked by this, but a player can ask to undo their last action
+	ApproveTakeback Blocker = 8 // the opponents are blocked by this until they answer a takeback request
+	unknownBlocker  Blocker = -1
 )
 
 func (b Blocker) String() string {
@@ -119,6 +122,12 @@ func (b Blocker) String() string {
 		return `CountHand`
 	case CountCrib:
 		return `CountCrib`
+	case Forfeit:
+		return `Forfeit`
+	case RequestTakeback:
+		return `RequestTakeback`
+	case ApproveTakeback:
+		return `ApproveTakeback`
 	}
 	return `InvalidBlocker`
 }
@@ -137,6 +146,12 @@ func NewBlockerFromString(b string) Blocker {
 		return CountHand
 	case `CountCrib`:
 		return CountCrib
+	case `Forfeit`:
+		return Forfeit
+	case `RequestTakeback`:
+		return RequestTakeback
+	case `ApproveTakeback`:
+		return ApproveTakeback
 	}
 	return unknownBlocker
 }
@@ -152,6 +167,8 @@ type PlayerAction struct {
 	ID        PlayerID    `json:"pID" bson:"pID"`
 	Overcomes Blocker     `json:"o" bson:"o"`
 	Action    interface{} `json:"a" bson:"a"`
+	// Auto is true when the server played this forced move on the player's behalf
+	Auto bool `json:"auto,omitempty" bson:"auto,omitempty"`
 
 	TimestampStr string `json:"timestamp,omitempty" bson:"-"`
 }
@@ -166,6 +183,9 @@ func (pa *PlayerAction) SetTimeStamp(ts time.Time) {
 
 type DealAction struct {
 	NumShuffles int `json:"ns" bson:"ns"`
+	// Entropy is mixed into the shuffle of provably fair games, so that the server
+	// cannot choose the deck by itself
+	Entropy string `json:"e,omitempty" bson:"e,omitempty"`
 }
 
 type BuildCribAction struct {
@@ -189,6 +209,20 @@ type CountCribAction struct {
 	Pts int `json:"pts" bson:"pts"`
 }
 
+type ForfeitAction struct {
+	// TimedOut is set when the server forfeits for a player that took too long.
+	// The server rejects the actions from its clients that set it.
+	TimedOut bool `json:"to,omitempty" bson:"to"`
+}
+
+// RequestTakebackAction asks the opponents to let the player undo their last action
+type RequestTakebackAction struct{}
+
+// ApproveTakebackAction is an opponent's answer to a RequestTakebackAction
+type ApproveTakebackAction struct {
+	Approve bool `json:"ok" bson:"ok"`
+}
+
 type Phase int
 
 const (
@@ -303,13 +337,25 @@ type Game struct {
 	Hands map[PlayerID][]Card `protobuf:"-" json:"hs,omitempty" bson:"hs"` //nolint:lll
 	// The cards currently in the crib
 	Crib []Card `protobuf:"-" json:"c,omitempty" bson:"c"` //nolint:lll
+	// The order of this round's shuffled deck, top card first. The deal and the
+	// cut both draw from it, which lets anyone check them once the game is over.
+	DeckOrder []Card `protobuf:"-" json:"do,omitempty" bson:"do"` //nolint:lll
+	// The proofs that each round's deck was shuffled fairly, for provably fair games
+	Shuffles []ShuffleProof `protobuf:"-" json:"sh,omitempty" bson:"sh"` //nolint:lll
 
 	// The flipped card which acts as the lead
 	CutCard Card `protobuf:"-" json:"cc" bson:"cc"` //nolint:lll
 
 	// An ordered list of previously pegged cards (which includes who pegged them), most recent last
 	PeggedCards []PeggedCard `protobuf:"-" json:"pegged,omitempty" bson:"pegged"` //nolint:lll
+	// Where the pegging is within the current series
+	Pegging PeggingState `protobuf:"-" json:"peg" bson:"peg"` //nolint:lll
 
 	// An ordered list of player actions
 	Actions []PlayerAction `protobuf:"-" json:"as" bson:"as"` //nolint:lll
+
+	// The settings chosen when the game was created
+	Settings GameSettings `protobuf:"-" json:"set" bson:"set"` //nolint:lll
+	// How the game ended, if it ended without someone reaching the winning score
+	Outcome GameOutcome `protobuf:"-" json:"out" bson:"out"` //nolint:lll
 }
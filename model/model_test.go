@@ -30,15 +30,44 @@ func TestBlockerStringConversions(t *testing.T) {
 		PegCard,
 		CountHand,
 		CountCrib,
+		Forfeit,
+		RequestTakeback,
+		ApproveTakeback,
 	} {
 		assert.Equal(t, b, NewBlockerFromString(b.String()))
 	}
 
 	assert.Equal(t, `InvalidBlocker`, unknownBlocker.String())
-	assert.Equal(t, `InvalidBlocker`, (Blocker)(6).String())
+	assert.Equal(t, `InvalidBlocker`, (Blocker)(9).String())
 	assert.Equal(t, unknownBlocker, NewBlockerFromString(`other`))
 }
 
+func TestTimeoutActionStringConversions(t *testing.T) {
+	for _, ta := range []TimeoutAction{
+		NotifyOnTimeout,
+		AutoPlayOnTimeout,
+		ForfeitOnTimeout,
+	} {
+		assert.Equal(t, ta, NewTimeoutActionFromString(ta.String()))
+	}
+
+	assert.Equal(t, `unknown`, unknownTimeoutAction.String())
+	assert.Equal(t, unknownTimeoutAction, NewTimeoutActionFromString(`other`))
+}
+
+func TestOutcomeReasonStringConversions(t *testing.T) {
+	for _, or := range []OutcomeReason{
+		NoOutcome,
+		Forfeited,
+		TimedOut,
+	} {
+		assert.Equal(t, or, NewOutcomeReasonFromString(or.String()))
+	}
+
+	assert.Equal(t, `unknown`, unknownOutcomeReason.String())
+	assert.Equal(t, unknownOutcomeReason, NewOutcomeReasonFromString(`other`))
+}
+
 func TestPhaseStringConversions(t *testing.T) {
 	for _, p := range []Phase{
 		Deal,
@@ -69,3 +98,17 @@ func TestPlayerActionTimeStamp(t *testing.T) {
 
 	assert.Equal(t, t0.Format(time.RFC3339), pa.TimestampStr)
 }
+
+func TestLobbyStatusStringConversions(t *testing.T) {
+	for _, ls := range []LobbyStatus{
+		LobbyOpen,
+		LobbyStarted,
+		LobbyClosed,
+		LobbyDeclined,
+	} {
+		assert.Equal(t, ls, NewLobbyStatusFromString(ls.String()))
+	}
+
+	assert.Equal(t, `unknown`, unknownLobbyStatus.String())
+	assert.Equal(t, unknownLobbyStatus, NewLobbyStatusFromString(`other`))
+}
@@ -0,0 +1,45 @@
+package model
+
+// PeggingState is where the pegging is within the current series, which is the
+// run of cards that count up towards 31.
+//
+// A series starts at zero. In turn, each player pegs a card if they can do it
+// without going over 31, or says go if they can't. A player who says go sits out
+// for the rest of the series, and a player who has pegged all of their cards is
+// skipped. That means that the player before someone who said go keeps pegging
+// for as long as they can.
+//
+// The series ends when the count hits 31, or when nobody left in it can peg. The
+// player who pegged the last card of the series scores one for the go, unless it
+// made 31 (which already scored two). The next series is led by the player after
+// whoever pegged that last card. The very last card of all scores one for last
+// card, unless it made 31.
+type PeggingState struct {
+	// Count is the sum of the cards pegged in the current series
+	Count int `protobuf:"-" json:"n,omitempty" bson:"n"` //nolint:lll
+	// SeriesStart is the index into PeggedCards of the first card in the current series
+	SeriesStart int `protobuf:"-" json:"ss,omitempty" bson:"ss"` //nolint:lll
+	// Goes are the players who have said go during the current series, in the order they said it
+	Goes []PlayerID `protobuf:"-" json:"gs,omitempty" bson:"gs"` //nolint:lll
+	// LastPegger is the player who pegged the most recent card in the current series
+	LastPegger PlayerID `protobuf:"-" json:"lp,omitempty" bson:"lp"` //nolint:lll
+}
+
+// HasSaidGo returns true if the player is sitting out the rest of the current series
+func (ps PeggingState) HasSaidGo(pID PlayerID) bool {
+	for _, g := range ps.Goes {
+		if g == pID {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmpty returns true if nothing has been kept in the state. Once a card has
+// been pegged, the count or the start of the series is always set.
+func (ps PeggingState) isEmpty() bool {
+	return ps.Count == 0 &&
+		ps.SeriesStart == 0 &&
+		len(ps.Goes) == 0 &&
+		ps.LastPegger == ``
+}
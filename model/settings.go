@@ -0,0 +1,94 @@
+package model
+
+import "time"
+
+type TimeoutAction int
+
+const (
+	// NotifyOnTimeout reminds the blocking players that we're waiting on them
+	NotifyOnTimeout TimeoutAction = 0
+	// AutoPlayOnTimeout has an NPC take the action for the blocking player
+	AutoPlayOnTimeout TimeoutAction = 1
+	// ForfeitOnTimeout forfeits the game for the blocking player
+	ForfeitOnTimeout     TimeoutAction = 2
+	unknownTimeoutAction TimeoutAction = -1
+)
+
+func (ta TimeoutAction) String() string {
+	switch ta {
+	case NotifyOnTimeout:
+		return `notify`
+	case AutoPlayOnTimeout:
+		return `autoplay`
+	case ForfeitOnTimeout:
+		return `forfeit`
+	}
+	return `unknown`
+}
+
+func NewTimeoutActionFromString(ta string) TimeoutAction {
+	switch ta {
+	case `notify`:
+		return NotifyOnTimeout
+	case `autoplay`:
+		return AutoPlayOnTimeout
+	case `forfeit`:
+		return ForfeitOnTimeout
+	}
+	return unknownTimeoutAction
+}
+
+// GameSettings are chosen when a game is created and do not change
+type GameSettings struct {
+	// How long a player has to take their action. Zero means they can take forever.
+	MoveTimeout time.Duration `protobuf:"-" json:"mt,omitempty" bson:"mt"` //nolint:lll
+	// What happens when a player takes longer than the MoveTimeout
+	OnTimeout TimeoutAction `protobuf:"-" json:"ot,omitempty" bson:"ot"` //nolint:lll
+	// Which NPC plays for a player when OnTimeout is AutoPlayOnTimeout
+	AutoPlayNPC PlayerID `protobuf:"-" json:"apn,omitempty" bson:"apn"` //nolint:lll
+	// ProvablyFair has the server commit to each shuffle before the deal, and reveal it after the hand
+	ProvablyFair bool `protobuf:"-" json:"pf,omitempty" bson:"pf"` //nolint:lll
+}
+
+type OutcomeReason int
+
+const (
+	// NoOutcome means the game is still going, or it ended with a winner
+	NoOutcome OutcomeReason = 0
+	// Forfeited means a player gave up
+	Forfeited OutcomeReason = 1
+	// TimedOut means a player took too long and forfeited because of it
+	TimedOut             OutcomeReason = 2
+	unknownOutcomeReason OutcomeReason = -1
+)
+
+func (or OutcomeReason) String() string {
+	switch or {
+	case NoOutcome:
+		return `none`
+	case Forfeited:
+		return `forfeited`
+	case TimedOut:
+		return `timedOut`
+	}
+	return `unknown`
+}
+
+func NewOutcomeReasonFromString(or string) OutcomeReason {
+	switch or {
+	case `none`:
+		return NoOutcome
+	case `forfeited`:
+		return Forfeited
+	case `timedOut`:
+		return TimedOut
+	}
+	return unknownOutcomeReason
+}
+
+// GameOutcome describes how a game ended when nobody reached the WinningScore
+type GameOutcome struct {
+	Reason OutcomeReason `protobuf:"-" json:"r,omitempty" bson:"r"` //nolint:lll
+	// The player who forfeited (or timed out)
+	PlayerID PlayerID `protobuf:"-" json:"pID,omitempty" bson:"pID"` //nolint:lll
+}
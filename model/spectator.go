@@ -0,0 +1,10 @@
+package model
+
+// Spectator is a player who is watching a game that they are not playing in
+type Spectator struct {
+	GameID   GameID   `json:"gID" bson:"gID"`
+	PlayerID PlayerID `json:"pID" bson:"pID"`
+	// If set, the spectator can look over this player's shoulder and see their hand.
+	// Only that player can give a spectator this view.
+	Shoulder PlayerID `json:"shoulder,omitempty" bson:"shoulder"`
+}
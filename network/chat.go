@@ -0,0 +1,42 @@
+package network
+
+import (
+	"time"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+)
+
+type SendChatRequest struct {
+	PlayerID model.PlayerID `json:"playerID"`
+	Message  string         `json:"message"`
+}
+
+type ChatMessage struct {
+	PlayerID model.PlayerID `json:"playerID"`
+	Message  string         `json:"message"`
+	Sent     string         `json:"sent"`
+}
+
+type GetChatResponse struct {
+	GameID   model.GameID  `json:"gameID"`
+	Messages []ChatMessage `json:"messages"`
+}
+
+func ConvertToChatMessage(cm model.ChatMessage) ChatMessage {
+	return ChatMessage{
+		PlayerID: cm.PlayerID,
+		Message:  cm.Message,
+		Sent:     cm.Sent.Format(time.RFC3339Nano),
+	}
+}
+
+func ConvertToGetChatResponse(gID model.GameID, msgs []model.ChatMessage) GetChatResponse {
+	resp := GetChatResponse{
+		GameID:   gID,
+		Messages: make([]ChatMessage, len(msgs)),
+	}
+	for i, cm := range msgs {
+		resp.Messages[i] = ConvertToChatMessage(cm)
+	}
+	return resp
+}
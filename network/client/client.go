@@ -0,0 +1,291 @@
+// Package client is a typed Go client for the REST API of the cribbage server.
+// The routes that it calls are documented at /api/openapi.json.
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/network"
+)
+
+// Error is returned when the server responds with something other than OK
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("bad response (%d): %q", e.StatusCode, e.Message)
+}
+
+// Client makes requests to the cribbage server
+type Client struct {
+	serverURL string
+	http      *http.Client
+}
+
+// New returns a client for the server at serverURL, like "http://localhost:8080".
+// If hc is nil, it uses the default http client.
+func New(serverURL string, hc *http.Client) *Client {
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	return &Client{
+		serverURL: strings.TrimSuffix(serverURL, `/`),
+		http:      hc,
+	}
+}
+
+// Health returns an error if the server isn't healthy
+func (c *Client) Health() error {
+	return c.do(`GET`, `/api/health`, nil, nil)
+}
+
+func (c *Client) CreateGame(req network.CreateGameRequest) (network.CreateGameResponse, error) {
+	var resp network.CreateGameResponse
+	err := c.do(`POST`, `/create/game`, req, &resp)
+	return resp, err
+}
+
+func (c *Client) CreatePlayer(req network.CreatePlayerRequest) (network.CreatePlayerResponse, error) {
+	var resp network.CreatePlayerResponse
+	err := c.do(`POST`, `/create/player`, req, &resp)
+	return resp, err
+}
+
+func (c *Client) CreateInteraction(req network.CreateInteractionRequest) error {
+	return c.do(`POST`, `/create/interaction`, req, nil)
+}
+
+func (c *Client) CreateLobby(req network.CreateLobbyRequest) (network.Lobby, error) {
+	var resp network.Lobby
+	err := c.do(`POST`, `/create/lobby`, req, &resp)
+	return resp, err
+}
+
+func (c *Client) CreateInvitation(req network.CreateInvitationRequest) (network.Lobby, error) {
+	var resp network.Lobby
+	err := c.do(`POST`, `/create/invitation`, req, &resp)
+	return resp, err
+}
+
+func (c *Client) GetLobby(lID model.LobbyID) (network.Lobby, error) {
+	var resp network.Lobby
+	err := c.do(`GET`, fmt.Sprintf("/lobby/%d", lID), nil, &resp)
+	return resp, err
+}
+
+func (c *Client) JoinLobby(lID model.LobbyID, pID model.PlayerID) (network.Lobby, error) {
+	return c.lobbyPlayer(lID, `join`, pID)
+}
+
+func (c *Client) LeaveLobby(lID model.LobbyID, pID model.PlayerID) (network.Lobby, error) {
+	return c.lobbyPlayer(lID, `leave`, pID)
+}
+
+func (c *Client) AcceptInvitation(lID model.LobbyID, pID model.PlayerID) (network.Lobby, error) {
+	return c.lobbyPlayer(lID, `accept`, pID)
+}
+
+func (c *Client) DeclineInvitation(lID model.LobbyID, pID model.PlayerID) (network.Lobby, error) {
+	return c.lobbyPlayer(lID, `decline`, pID)
+}
+
+func (c *Client) lobbyPlayer(lID model.LobbyID, verb string, pID model.PlayerID) (network.Lobby, error) {
+	var resp network.Lobby
+	err := c.do(`POST`, fmt.Sprintf("/lobby/%d/%s", lID, verb), network.LobbyPlayerRequest{
+		PlayerID: pID,
+	}, &resp)
+	return resp, err
+}
+
+// GetOpenLobbies returns the public lobbies. If numPlayers is set, it only
+// returns the lobbies for that size of game.
+func (c *Client) GetOpenLobbies(numPlayers int) (network.GetOpenLobbiesResponse, error) {
+	q := url.Values{}
+	if numPlayers > 0 {
+		q.Set(`num_players`, strconv.Itoa(numPlayers))
+	}
+	var resp network.GetOpenLobbiesResponse
+	err := c.do(`GET`, withQuery(`/lobbies/open`, q), nil, &resp)
+	return resp, err
+}
+
+func (c *Client) MatchLobby(req network.MatchLobbyRequest) (network.Lobby, error) {
+	var resp network.Lobby
+	err := c.do(`POST`, `/lobbies/match`, req, &resp)
+	return resp, err
+}
+
+func (c *Client) GetInvitations(pID model.PlayerID) (network.GetOpenLobbiesResponse, error) {
+	q := url.Values{}
+	q.Set(`playerID`, string(pID))
+	var resp network.GetOpenLobbiesResponse
+	err := c.do(`GET`, withQuery(`/lobbies/invitations`, q), nil, &resp)
+	return resp, err
+}
+
+// GetGame returns the game without anyone's hand
+func (c *Client) GetGame(gID model.GameID) (network.GetGameResponse, error) {
+	return c.getGame(gID, url.Values{})
+}
+
+// GetGameForPlayer returns what the player is allowed to see of the game
+func (c *Client) GetGameForPlayer(gID model.GameID, pID model.PlayerID) (network.GetGameResponse, error) {
+	q := url.Values{}
+	q.Set(`player`, string(pID))
+	return c.getGame(gID, q)
+}
+
+// GetGameForSpectator returns what the spectator is allowed to see of the game
+func (c *Client) GetGameForSpectator(gID model.GameID, sID model.PlayerID) (network.GetGameResponse, error) {
+	q := url.Values{}
+	q.Set(`spectator`, string(sID))
+	return c.getGame(gID, q)
+}
+
+func (c *Client) getGame(gID model.GameID, q url.Values) (network.GetGameResponse, error) {
+	var resp network.GetGameResponse
+	err := c.do(`GET`, withQuery(fmt.Sprintf("/game/%d", gID), q), nil, &resp)
+	return resp, err
+}
+
+// GetShuffles returns the proofs for each round's shuffle. The server seed of
+// the hand in progress is not revealed.
+func (c *Client) GetShuffles(gID model.GameID) (network.GetShufflesResponse, error) {
+	var resp network.GetShufflesResponse
+	err := c.do(`GET`, fmt.Sprintf("/game/%d/shuffles", gID), nil, &resp)
+	return resp, err
+}
+
+func (c *Client) GetSpectators(gID model.GameID) (network.GetSpectatorsResponse, error) {
+	var resp network.GetSpectatorsResponse
+	err := c.do(`GET`, fmt.Sprintf("/game/%d/spectators", gID), nil, &resp)
+	return resp, err
+}
+
+func (c *Client) Spectate(gID model.GameID, req network.SpectateRequest) error {
+	return c.do(`POST`, fmt.Sprintf("/game/%d/spectate", gID), req, nil)
+}
+
+func (c *Client) Unspectate(gID model.GameID, req network.SpectateRequest) error {
+	return c.do(`POST`, fmt.Sprintf("/game/%d/unspectate", gID), req, nil)
+}
+
+func (c *Client) SetShoulder(gID model.GameID, req network.ShoulderRequest) error {
+	return c.do(`POST`, fmt.Sprintf("/game/%d/shoulder", gID), req, nil)
+}
+
+func (c *Client) GetChat(gID model.GameID) (network.GetChatResponse, error) {
+	var resp network.GetChatResponse
+	err := c.do(`GET`, fmt.Sprintf("/game/%d/chat", gID), nil, &resp)
+	return resp, err
+}
+
+func (c *Client) SendChat(gID model.GameID, req network.SendChatRequest) (network.ChatMessage, error) {
+	var resp network.ChatMessage
+	err := c.do(`POST`, fmt.Sprintf("/game/%d/chat", gID), req, &resp)
+	return resp, err
+}
+
+func (c *Client) GetActiveGames(pID model.PlayerID) (network.GetActiveGamesForPlayerResponse, error) {
+	q := url.Values{}
+	q.Set(`playerID`, string(pID))
+	var resp network.GetActiveGamesForPlayerResponse
+	err := c.do(`GET`, withQuery(`/games/active`, q), nil, &resp)
+	return resp, err
+}
+
+func (c *Client) GetPlayer(pID model.PlayerID) (network.GetPlayerResponse, error) {
+	var resp network.GetPlayerResponse
+	err := c.do(`GET`, fmt.Sprintf("/player/%s", url.PathEscape(string(pID))), nil, &resp)
+	return resp, err
+}
+
+// GetPlayerEvents waits for the player's events after the given ID. The server
+// responds with no events if none come before its poll times out.
+func (c *Client) GetPlayerEvents(pID model.PlayerID, after uint64) (network.GetPlayerEventsResponse, error) {
+	q := url.Values{}
+	q.Set(`after`, strconv.FormatUint(after, 10))
+	var resp network.GetPlayerEventsResponse
+	path := fmt.Sprintf("/player/%s/events", url.PathEscape(string(pID)))
+	err := c.do(`GET`, withQuery(path, q), nil, &resp)
+	return resp, err
+}
+
+func (c *Client) SendAction(pa model.PlayerAction) error {
+	return c.do(`POST`, `/action`, pa, nil)
+}
+
+func (c *Client) SuggestHand(dealt []model.Card) ([]network.GetSuggestHandResponse, error) {
+	cs := make([]string, len(dealt))
+	for i, d := range dealt {
+		cs[i] = d.String()
+	}
+	q := url.Values{}
+	q.Set(`dealt`, strings.Join(cs, `,`))
+
+	var resp []network.GetSuggestHandResponse
+	err := c.do(`GET`, withQuery(`/suggest/hand`, q), nil, &resp)
+	return resp, err
+}
+
+func withQuery(apiURL string, q url.Values) string {
+	if len(q) == 0 {
+		return apiURL
+	}
+	return apiURL + `?` + q.Encode()
+}
+
+// do sends the request body as JSON, and decodes the JSON response into resp.
+// The routes that respond with plain text pass a nil resp.
+func (c *Client) do(method, apiURL string, body, resp interface{}) error {
+	var data io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		data = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, c.serverURL+apiURL, data)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set(`Content-Type`, `application/json`)
+	}
+
+	response, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	respBytes, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return &Error{
+			StatusCode: response.StatusCode,
+			Message:    string(respBytes),
+		}
+	}
+
+	if resp == nil {
+		return nil
+	}
+	return json.Unmarshal(respBytes, resp)
+}
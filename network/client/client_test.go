@@ -0,0 +1,118 @@
+package client
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/network"
+)
+
+type seenRequest struct {
+	method string
+	url    string
+	body   string
+}
+
+func newTestClient(t *testing.T, status int, resp string) (*Client, *seenRequest) {
+	seen := &seenRequest{}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		seen.method = r.Method
+		seen.url = r.URL.String()
+		seen.body = string(b)
+
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(resp))
+	}))
+	t.Cleanup(s.Close)
+
+	return New(s.URL+`/`, s.Client()), seen
+}
+
+func TestCreatePlayer(t *testing.T) {
+	c, seen := newTestClient(t, http.StatusOK, `{"player":{"id":"alice","name":"Alice"}}`)
+
+	resp, err := c.CreatePlayer(network.CreatePlayerRequest{
+		Player: network.Player{
+			ID:   `alice`,
+			Name: `Alice`,
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, network.Player{ID: `alice`, Name: `Alice`}, resp.Player)
+	assert.Equal(t, `POST`, seen.method)
+	assert.Equal(t, `/create/player`, seen.url)
+	assert.JSONEq(t, `{"player":{"id":"alice","name":"Alice"}}`, seen.body)
+}
+
+func TestGetGameForPlayer(t *testing.T) {
+	exp := network.GetGameResponse{
+		ID:    model.GameID(42),
+		Phase: `Deal`,
+	}
+	b, err := json.Marshal(exp)
+	require.NoError(t, err)
+	c, seen := newTestClient(t, http.StatusOK, string(b))
+
+	resp, err := c.GetGameForPlayer(model.GameID(42), `alice`)
+	require.NoError(t, err)
+	assert.Equal(t, exp.ID, resp.ID)
+	assert.Equal(t, exp.Phase, resp.Phase)
+	assert.Equal(t, `GET`, seen.method)
+	assert.Equal(t, `/game/42?player=alice`, seen.url)
+	assert.Empty(t, seen.body)
+}
+
+func TestGetPlayerEvents(t *testing.T) {
+	c, seen := newTestClient(t, http.StatusOK, `{"events":[{"id":4,"event":"blocking","gameID":42}],"next":4}`)
+
+	resp, err := c.GetPlayerEvents(`alice`, 3)
+	require.NoError(t, err)
+	assert.Equal(t, network.GetPlayerEventsResponse{
+		Events: []network.PlayerEvent{{
+			ID:     4,
+			Event:  `blocking`,
+			GameID: 42,
+		}},
+		Next: 4,
+	}, resp)
+	assert.Equal(t, `GET`, seen.method)
+	assert.Equal(t, `/player/alice/events?after=3`, seen.url)
+}
+
+func TestSuggestHand(t *testing.T) {
+	c, seen := newTestClient(t, http.StatusOK, `[]`)
+
+	_, err := c.SuggestHand([]model.Card{
+		model.NewCardFromString(`AH`),
+		model.NewCardFromString(`10C`),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `/suggest/hand?dealt=AH%2C10C`, seen.url)
+}
+
+func TestSendActionError(t *testing.T) {
+	c, seen := newTestClient(t, http.StatusBadRequest, `Should overcome CutCard, but overcame DealCards`)
+
+	err := c.SendAction(model.PlayerAction{
+		GameID:    model.GameID(42),
+		ID:        `alice`,
+		Overcomes: model.DealCards,
+		Action:    model.DealAction{NumShuffles: 3},
+	})
+	require.Error(t, err)
+	assert.Equal(t, `/action`, seen.url)
+
+	cErr, ok := err.(*Error)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, cErr.StatusCode)
+	assert.Equal(t, `Should overcome CutCard, but overcame DealCards`, cErr.Message)
+}
@@ -0,0 +1,2798 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.26.0
+// 	protoc        v3.5.1
+// source: cribbage.proto
+
+package cribbagepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// The values match model.Blocker
+type Blocker int32
+
+const (
+	Blocker_DEAL_CARDS       Blocker = 0
+	Blocker_CRIB_CARD        Blocker = 1
+	Blocker_CUT_CARD         Blocker = 2
+	Blocker_PEG_CARD         Blocker = 3
+	Blocker_COUNT_HAND       Blocker = 4
+	Blocker_COUNT_CRIB       Blocker = 5
+	Blocker_FORFEIT          Blocker = 6
+	Blocker_REQUEST_TAKEBACK Blocker = 7
+	Blocker_APPROVE_TAKEBACK Blocker = 8
+)
+
+// Enum value maps for Blocker.
+var (
+	Blocker_name = map[int32]string{
+		0: "DEAL_CARDS",
+		1: "CRIB_CARD",
+		2: "CUT_CARD",
+		3: "PEG_CARD",
+		4: "COUNT_HAND",
+		5: "COUNT_CRIB",
+		6: "FORFEIT",
+		7: "REQUEST_TAKEBACK",
+		8: "APPROVE_TAKEBACK",
+	}
+	Blocker_value = map[string]int32{
+		"DEAL_CARDS":       0,
+		"CRIB_CARD":        1,
+		"CUT_CARD":         2,
+		"PEG_CARD":         3,
+		"COUNT_HAND":       4,
+		"COUNT_CRIB":       5,
+		"FORFEIT":          6,
+		"REQUEST_TAKEBACK": 7,
+		"APPROVE_TAKEBACK": 8,
+	}
+)
+
+func (x Blocker) Enum() *Blocker {
+	p := new(Blocker)
+	*p = x
+	return p
+}
+
+func (x Blocker) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Blocker) Descriptor() protoreflect.EnumDescriptor {
+	return file_cribbage_proto_enumTypes[0].Descriptor()
+}
+
+func (Blocker) Type() protoreflect.EnumType {
+	return &file_cribbage_proto_enumTypes[0]
+}
+
+func (x Blocker) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Blocker.Descriptor instead.
+func (Blocker) EnumDescriptor() ([]byte, []int) {
+	return file_cribbage_proto_rawDescGZIP(), []int{0}
+}
+
+// The values match model.Phase
+type Phase int32
+
+const (
+	Phase_DEAL                Phase = 0
+	Phase_BUILD_CRIB_READY    Phase = 1
+	Phase_BUILD_CRIB          Phase = 2
+	Phase_CUT_READY           Phase = 3
+	Phase_CUT                 Phase = 4
+	Phase_PEGGING_READY       Phase = 5
+	Phase_PEGGING             Phase = 6
+	Phase_COUNTING_READY      Phase = 7
+	Phase_COUNTING            Phase = 8
+	Phase_CRIB_COUNTING_READY Phase = 9
+	Phase_CRIB_COUNTING       Phase = 10
+	Phase_DEALING_READY       Phase = 11
+)
+
+// Enum value maps for Phase.
+var (
+	Phase_name = map[int32]string{
+		0:  "DEAL",
+		1:  "BUILD_CRIB_READY",
+		2:  "BUILD_CRIB",
+		3:  "CUT_READY",
+		4:  "CUT",
+		5:  "PEGGING_READY",
+		6:  "PEGGING",
+		7:  "COUNTING_READY",
+		8:  "COUNTING",
+		9:  "CRIB_COUNTING_READY",
+		10: "CRIB_COUNTING",
+		11: "DEALING_READY",
+	}
+	Phase_value = map[string]int32{
+		"DEAL":                0,
+		"BUILD_CRIB_READY":    1,
+		"BUILD_CRIB":          2,
+		"CUT_READY":           3,
+		"CUT":                 4,
+		"PEGGING_READY":       5,
+		"PEGGING":             6,
+		"COUNTING_READY":      7,
+		"COUNTING":            8,
+		"CRIB_COUNTING_READY": 9,
+		"CRIB_COUNTING":       10,
+		"DEALING_READY":       11,
+	}
+)
+
+func (x Phase) Enum() *Phase {
+	p := new(Phase)
+	*p = x
+	return p
+}
+
+func (x Phase) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Phase) Descriptor() protoreflect.EnumDescriptor {
+	return file_cribbage_proto_enumTypes[1].Descriptor()
+}
+
+func (Phase) Type() protoreflect.EnumType {
+	return &file_cribbage_proto_enumTypes[1]
+}
+
+func (x Phase) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Phase.Descriptor instead.
+func (Phase) EnumDescriptor() ([]byte, []int) {
+	return file_cribbage_proto_rawDescGZIP(), []int{1}
+}
+
+// The values match model.PlayerColor
+type PlayerColor int32
+
+const (
+	PlayerColor_UNSET_COLOR PlayerColor = 0
+	PlayerColor_GREEN       PlayerColor = 1
+	PlayerColor_BLUE        PlayerColor = 2
+	PlayerColor_RED         PlayerColor = 3
+)
+
+// Enum value maps for PlayerColor.
+var (
+	PlayerColor_name = map[int32]string{
+		0: "UNSET_COLOR",
+		1: "GREEN",
+		2: "BLUE",
+		3: "RED",
+	}
+	PlayerColor_value = map[string]int32{
+		"UNSET_COLOR": 0,
+		"GREEN":       1,
+		"BLUE":        2,
+		"RED":         3,
+	}
+)
+
+func (x PlayerColor) Enum() *PlayerColor {
+	p := new(PlayerColor)
+	*p = x
+	return p
+}
+
+func (x PlayerColor) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PlayerColor) Descriptor() protoreflect.EnumDescriptor {
+	return file_cribbage_proto_enumTypes[2].Descriptor()
+}
+
+func (PlayerColor) Type() protoreflect.EnumType {
+	return &file_cribbage_proto_enumTypes[2]
+}
+
+func (x PlayerColor) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PlayerColor.Descriptor instead.
+func (PlayerColor) EnumDescriptor() ([]byte, []int) {
+	return file_cribbage_proto_rawDescGZIP(), []int{2}
+}
+
+type Player struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id   string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *Player) Reset() {
+	*x = Player{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cribbage_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Player) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Player) ProtoMessage() {}
+
+func (x *Player) ProtoReflect() protoreflect.Message {
+	mi := &file_cribbage_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Player.ProtoReflect.Descriptor instead.
+func (*Player) Descriptor() ([]byte, []int) {
+	return file_cribbage_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Player) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Player) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// Card is a card like "AH" or "10C". Cards that the player cannot see are "unknown".
+type Card struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *Card) Reset() {
+	*x = Card{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cribbage_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Card) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Card) ProtoMessage() {}
+
+func (x *Card) ProtoReflect() protoreflect.Message {
+	mi := &file_cribbage_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Card.ProtoReflect.Descriptor instead.
+func (*Card) Descriptor() ([]byte, []int) {
+	return file_cribbage_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Card) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type PeggedCard struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Card     *Card  `protobuf:"bytes,1,opt,name=card,proto3" json:"card,omitempty"`
+	PlayerId string `protobuf:"bytes,2,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+}
+
+func (x *PeggedCard) Reset() {
+	*x = PeggedCard{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cribbage_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PeggedCard) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeggedCard) ProtoMessage() {}
+
+func (x *PeggedCard) ProtoReflect() protoreflect.Message {
+	mi := &file_cribbage_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeggedCard.ProtoReflect.Descriptor instead.
+func (*PeggedCard) Descriptor() ([]byte, []int) {
+	return file_cribbage_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PeggedCard) GetCard() *Card {
+	if x != nil {
+		return x.Card
+	}
+	return nil
+}
+
+func (x *PeggedCard) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+type Hand struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cards []*Card `protobuf:"bytes,1,rep,name=cards,proto3" json:"cards,omitempty"`
+}
+
+func (x *Hand) Reset() {
+	*x = Hand{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cribbage_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Hand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Hand) ProtoMessage() {}
+
+func (x *Hand) ProtoReflect() protoreflect.Message {
+	mi := &file_cribbage_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Hand.ProtoReflect.Descriptor instead.
+func (*Hand) Descriptor() ([]byte, []int) {
+	return file_cribbage_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Hand) GetCards() []*Card {
+	if x != nil {
+		return x.Cards
+	}
+	return nil
+}
+
+type Team struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Players      []*Player   `protobuf:"bytes,1,rep,name=players,proto3" json:"players,omitempty"`
+	Color        PlayerColor `protobuf:"varint,2,opt,name=color,proto3,enum=cribbage.PlayerColor" json:"color,omitempty"`
+	CurrentScore int32       `protobuf:"varint,3,opt,name=current_score,json=currentScore,proto3" json:"current_score,omitempty"`
+	LagScore     int32       `protobuf:"varint,4,opt,name=lag_score,json=lagScore,proto3" json:"lag_score,omitempty"`
+}
+
+func (x *Team) Reset() {
+	*x = Team{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cribbage_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Team) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Team) ProtoMessage() {}
+
+func (x *Team) ProtoReflect() protoreflect.Message {
+	mi := &file_cribbage_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Team.ProtoReflect.Descriptor instead.
+func (*Team) Descriptor() ([]byte, []int) {
+	return file_cribbage_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Team) GetPlayers() []*Player {
+	if x != nil {
+		return x.Players
+	}
+	return nil
+}
+
+func (x *Team) GetColor() PlayerColor {
+	if x != nil {
+		return x.Color
+	}
+	return PlayerColor_UNSET_COLOR
+}
+
+func (x *Team) GetCurrentScore() int32 {
+	if x != nil {
+		return x.CurrentScore
+	}
+	return 0
+}
+
+func (x *Team) GetLagScore() int32 {
+	if x != nil {
+		return x.LagScore
+	}
+	return 0
+}
+
+type GameSettings struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// move_timeout is a duration string, like "10m"
+	MoveTimeout string `protobuf:"bytes,1,opt,name=move_timeout,json=moveTimeout,proto3" json:"move_timeout,omitempty"`
+	OnTimeout   string `protobuf:"bytes,2,opt,name=on_timeout,json=onTimeout,proto3" json:"on_timeout,omitempty"`
+	AutoplayNpc string `protobuf:"bytes,3,opt,name=autoplay_npc,json=autoplayNpc,proto3" json:"autoplay_npc,omitempty"`
+	// provably_fair has the server commit to every shuffle before the deal
+	ProvablyFair bool `protobuf:"varint,4,opt,name=provably_fair,json=provablyFair,proto3" json:"provably_fair,omitempty"`
+}
+
+func (x *GameSettings) Reset() {
+	*x = GameSettings{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cribbage_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GameSettings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameSettings) ProtoMessage() {}
+
+func (x *GameSettings) ProtoReflect() protoreflect.Message {
+	mi := &file_cribbage_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameSettings.ProtoReflect.Descriptor instead.
+func (*GameSettings) Descriptor() ([]byte, []int) {
+	return file_cribbage_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GameSettings) GetMoveTimeout() string {
+	if x != nil {
+		return x.MoveTimeout
+	}
+	return ""
+}
+
+func (x *GameSettings) GetOnTimeout() string {
+	if x != nil {
+		return x.OnTimeout
+	}
+	return ""
+}
+
+func (x *GameSettings) GetAutoplayNpc() string {
+	if x != nil {
+		return x.AutoplayNpc
+	}
+	return ""
+}
+
+func (x *GameSettings) GetProvablyFair() bool {
+	if x != nil {
+		return x.ProvablyFair
+	}
+	return false
+}
+
+type GameOutcome struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// reason is only set when the game ended without someone reaching the winning score
+	Reason   string        `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	PlayerId string        `protobuf:"bytes,2,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Winners  []PlayerColor `protobuf:"varint,3,rep,packed,name=winners,proto3,enum=cribbage.PlayerColor" json:"winners,omitempty"`
+}
+
+func (x *GameOutcome) Reset() {
+	*x = GameOutcome{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cribbage_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GameOutcome) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameOutcome) ProtoMessage() {}
+
+func (x *GameOutcome) ProtoReflect() protoreflect.Message {
+	mi := &file_cribbage_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameOutcome.ProtoReflect.Descriptor instead.
+func (*GameOutcome) Descriptor() ([]byte, []int) {
+	return file_cribbage_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GameOutcome) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *GameOutcome) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *GameOutcome) GetWinners() []PlayerColor {
+	if x != nil {
+		return x.Winners
+	}
+	return nil
+}
+
+// Game is what the requester is allowed to see of the game
+type Game struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id              int64              `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Teams           []*Team            `protobuf:"bytes,2,rep,name=teams,proto3" json:"teams,omitempty"`
+	Phase           Phase              `protobuf:"varint,3,opt,name=phase,proto3,enum=cribbage.Phase" json:"phase,omitempty"`
+	CurrentPeg      int32              `protobuf:"varint,4,opt,name=current_peg,json=currentPeg,proto3" json:"current_peg,omitempty"`
+	BlockingPlayers map[string]Blocker `protobuf:"bytes,5,rep,name=blocking_players,json=blockingPlayers,proto3" json:"blocking_players,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3,enum=cribbage.Blocker"`
+	CurrentDealer   string             `protobuf:"bytes,6,opt,name=current_dealer,json=currentDealer,proto3" json:"current_dealer,omitempty"`
+	Hands           map[string]*Hand   `protobuf:"bytes,7,rep,name=hands,proto3" json:"hands,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Crib            []*Card            `protobuf:"bytes,8,rep,name=crib,proto3" json:"crib,omitempty"`
+	CutCard         *Card              `protobuf:"bytes,9,opt,name=cut_card,json=cutCard,proto3" json:"cut_card,omitempty"`
+	PeggedCards     []*PeggedCard      `protobuf:"bytes,10,rep,name=pegged_cards,json=peggedCards,proto3" json:"pegged_cards,omitempty"`
+	Settings        *GameSettings      `protobuf:"bytes,11,opt,name=settings,proto3" json:"settings,omitempty"`
+	Outcome         *GameOutcome       `protobuf:"bytes,12,opt,name=outcome,proto3" json:"outcome,omitempty"`
+	// deck is the order of the round's shuffled deck, which is only shown once the game is over
+	Deck []*Card `protobuf:"bytes,13,rep,name=deck,proto3" json:"deck,omitempty"`
+	// shuffle is the proof for this round's shuffle, if the game is provably fair
+	Shuffle *ShuffleProof `protobuf:"bytes,14,opt,name=shuffle,proto3" json:"shuffle,omitempty"`
+}
+
+func (x *Game) Reset() {
+	*x = Game{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cribbage_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Game) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Game) ProtoMessage() {}
+
+func (x *Game) ProtoReflect() protoreflect.Message {
+	mi := &file_cribbage_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Game.ProtoReflect.Descriptor instead.
+func (*Game) Descriptor() ([]byte, []int) {
+	return file_cribbage_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Game) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Game) GetTeams() []*Team {
+	if x != nil {
+		return x.Teams
+	}
+	return nil
+}
+
+func (x *Game) GetPhase() Phase {
+	if x != nil {
+		return x.Phase
+	}
+	return Phase_DEAL
+}
+
+func (x *Game) GetCurrentPeg() int32 {
+	if x != nil {
+		return x.CurrentPeg
+	}
+	return 0
+}
+
+func (x *Game) GetBlockingPlayers() map[string]Blocker {
+	if x != nil {
+		return x.BlockingPlayers
+	}
+	return nil
+}
+
+func (x *Game) GetCurrentDealer() string {
+	if x != nil {
+		return x.CurrentDealer
+	}
+	return ""
+}
+
+func (x *Game) GetHands() map[string]*Hand {
+	if x != nil {
+		return x.Hands
+	}
+	return nil
+}
+
+func (x *Game) GetCrib() []*Card {
+	if x != nil {
+		return x.Crib
+	}
+	return nil
+}
+
+func (x *Game) GetCutCard() *Card {
+	if x != nil {
+		return x.CutCard
+	}
+	return nil
+}
+
+func (x *Game) GetPeggedCards() []*PeggedCard {
+	if x != nil {
+		return x.PeggedCards
+	}
+	return nil
+}
+
+func (x *Game) GetSettings() *GameSettings {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+func (x *Game) GetOutcome() *GameOutcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return nil
+}
+
+func (x *Game) GetDeck() []*Card {
+	if x != nil {
+		return x.Deck
+	}
+	return nil
+}
+
+func (x *Game) GetShuffle() *ShuffleProof {
+	if x != nil {
+		return x.Shuffle
+	}
+	return nil
+}
+
+// ShuffleProof lets the players check that the deck was not stacked.
+// server_seed is only set once the hand is over.
+type ShuffleProof struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Commitment    string `protobuf:"bytes,1,opt,name=commitment,proto3" json:"commitment,omitempty"`
+	ServerSeed    string `protobuf:"bytes,2,opt,name=server_seed,json=serverSeed,proto3" json:"server_seed,omitempty"`
+	ClientEntropy string `protobuf:"bytes,3,opt,name=client_entropy,json=clientEntropy,proto3" json:"client_entropy,omitempty"`
+	// cut_percentage is how far into the deck the cut was made, once it has been made
+	CutPercentage float64 `protobuf:"fixed64,4,opt,name=cut_percentage,json=cutPercentage,proto3" json:"cut_percentage,omitempty"`
+}
+
+func (x *ShuffleProof) Reset() {
+	*x = ShuffleProof{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cribbage_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShuffleProof) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShuffleProof) ProtoMessage() {}
+
+func (x *ShuffleProof) ProtoReflect() protoreflect.Message {
+	mi := &file_cribbage_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShuffleProof.ProtoReflect.Descriptor instead.
+func (*ShuffleProof) Descriptor() ([]byte, []int) {
+	return file_cribbage_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ShuffleProof) GetCommitment() string {
+	if x != nil {
+		return x.Commitment
+	}
+	return ""
+}
+
+func (x *ShuffleProof) GetServerSeed() string {
+	if x != nil {
+		return x.ServerSeed
+	}
+	return ""
+}
+
+func (x *ShuffleProof) GetClientEntropy() string {
+	if x != nil {
+		return x.ClientEntropy
+	}
+	return ""
+}
+
+func (x *ShuffleProof) GetCutPercentage() float64 {
+	if x != nil {
+		return x.CutPercentage
+	}
+	return 0
+}
+
+type DealAction struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NumShuffles int32 `protobuf:"varint,1,opt,name=num_shuffles,json=numShuffles,proto3" json:"num_shuffles,omitempty"`
+	// entropy is mixed into the shuffle of provably fair games
+	Entropy string `protobuf:"bytes,2,opt,name=entropy,proto3" json:"entropy,omitempty"`
+}
+
+func (x *DealAction) Reset() {
+	*x = DealAction{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cribbage_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DealAction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DealAction) ProtoMessage() {}
+
+func (x *DealAction) ProtoReflect() protoreflect.Message {
+	mi := &file_cribbage_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DealAction.ProtoReflect.Descriptor instead.
+func (*DealAction) Descriptor() ([]byte, []int) {
+	return file_cribbage_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DealAction) GetNumShuffles() int32 {
+	if x != nil {
+		return x.NumShuffles
+	}
+	return 0
+}
+
+func (x *DealAction) GetEntropy() string {
+	if x != nil {
+		return x.Entropy
+	}
+	return ""
+}
+
+type BuildCribAction struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cards []*Card `protobuf:"bytes,1,rep,name=cards,proto3" json:"cards,omitempty"`
+}
+
+func (x *BuildCribAction) Reset() {
+	*x = BuildCribAction{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cribbage_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BuildCribAction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuildCribAction) ProtoMessage() {}
+
+func (x *BuildCribAction) ProtoReflect() protoreflect.Message {
+	mi := &file_cribbage_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuildCribAction.ProtoReflect.Descriptor instead.
+func (*BuildCribAction) Descriptor() ([]byte, []int) {
+	return file_cribbage_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BuildCribAction) GetCards() []*Card {
+	if x != nil {
+		return x.Cards
+	}
+	return nil
+}
+
+type CutDeckAction struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Percentage float64 `protobuf:"fixed64,1,opt,name=percentage,proto3" json:"percentage,omitempty"`
+}
+
+func (x *CutDeckAction) Reset() {
+	*x = CutDeckAction{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cribbage_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CutDeckAction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CutDeckAction) ProtoMessage() {}
+
+func (x *CutDeckAction) ProtoReflect() protoreflect.Message {
+	mi := &file_cribbage_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CutDeckAction.ProtoReflect.Descriptor instead.
+func (*CutDeckAction) Descriptor() ([]byte, []int) {
+	return file_cribbage_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CutDeckAction) GetPercentage() float64 {
+	if x != nil {
+		return x.Percentage
+	}
+	return 0
+}
+
+type PegAction struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Card  *Card `protobuf:"bytes,1,opt,name=card,proto3" json:"card,omitempty"`
+	SayGo bool  `protobuf:"varint,2,opt,name=say_go,json=sayGo,proto3" json:"say_go,omitempty"`
+}
+
+func (x *PegAction) Reset() {
+	*x = PegAction{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cribbage_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PegAction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PegAction) ProtoMessage() {}
+
+func (x *PegAction) ProtoReflect() protoreflect.Message {
+	mi := &file_cribbage_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PegAction.ProtoReflect.Descriptor instead.
+func (*PegAction) Descriptor() ([]byte, []int) {
+	return file_cribbage_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *PegAction) GetCard() *Card {
+	if x != nil {
+		return x.Card
+	}
+	return nil
+}
+
+func (x *PegAction) GetSayGo() bool {
+	if x != nil {
+		return x.SayGo
+	}
+	return false
+}
+
+type CountHandAction struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pts int32 `protobuf:"varint,1,opt,name=pts,proto3" json:"pts,omitempty"`
+}
+
+func (x *CountHandAction) Reset() {
+	*x = CountHandAction{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cribbage_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CountHandAction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountHandAction) ProtoMessage() {}
+
+func (x *CountHandAction) ProtoReflect() protoreflect.Message {
+	mi := &file_cribbage_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountHandAction.ProtoReflect.Descriptor instead.
+func (*CountHandAction) Descriptor() ([]byte, []int) {
+	return file_cribbage_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CountHandAction) GetPts() int32 {
+	if x != nil {
+		return x.Pts
+	}
+	return 0
+}
+
+type CountCribAction struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pts int32 `protobuf:"varint,1,opt,name=pts,proto3" json:"pts,omitempty"`
+}
+
+func (x *CountCribAction) Reset() {
+	*x = CountCribAction{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cribbage_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CountCribAction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountCribAction) ProtoMessage() {}
+
+func (x *CountCribAction) ProtoReflect() protoreflect.Message {
+	mi := &file_cribbage_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountCribAction.ProtoReflect.Descriptor instead.
+func (*CountCribAction) Descriptor() ([]byte, []int) {
+	return file_cribbage_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CountCribAction) GetPts() int32 {
+	if x != nil {
+		return x.Pts
+	}
+	return 0
+}
+
+type ForfeitAction struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ForfeitAction) Reset() {
+	*x = ForfeitAction{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cribbage_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ForfeitAction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForfeitAction) ProtoMessage() {}
+
+func (x *ForfeitAction) ProtoReflect() protoreflect.Message {
+	mi := &file_cribbage_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForfeitAction.ProtoReflect.Descriptor instead.
+func (*ForfeitAction) Descriptor() ([]byte, []int) {
+	return file_cribbage_proto_rawDescGZIP(), []int{15}
+}
+
+type RequestTakebackAction struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *RequestTakebackAction) Reset() {
+	*x = RequestTakebackAction{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cribbage_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RequestTakebackAction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestTakebackAction) ProtoMessage() {}
+
+func (x *RequestTakebackAction) ProtoReflect() protoreflect.Message {
+	mi := &file_cribbage_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestTakebackAction.ProtoReflect.Descriptor instead.
+func (*RequestTakebackAction) Descriptor() ([]byte, []int) {
+	return file_cribbage_proto_rawDescGZIP(), []int{16}
+}
+
+type ApproveTakebackAction struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Approve bool `protobuf:"varint,1,opt,name=approve,proto3" json:"approve,omitempty"`
+}
+
+func (x *ApproveTakebackAction) Reset() {
+	*x = ApproveTakebackAction{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cribbage_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ApproveTakebackAction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApproveTakebackAction) ProtoMessage() {}
+
+func (x *ApproveTakebackAction) ProtoReflect() protoreflect.Message {
+	mi := &file_cribbage_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApproveTakebackAction.ProtoReflect.Descriptor instead.
+func (*ApproveTakebackAction) Descriptor() ([]byte, []int) {
+	return file_cribbage_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ApproveTakebackAction) GetApprove() bool {
+	if x != nil {
+		return x.Approve
+	}
+	return false
+}
+
+type PlayerAction struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GameId    int64   `protobuf:"varint,1,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	PlayerId  string  `protobuf:"bytes,2,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Overcomes Blocker `protobuf:"varint,3,opt,name=overcomes,proto3,enum=cribbage.Blocker" json:"overcomes,omitempty"`
+	// Types that are assignable to Action:
+	//	*PlayerAction_Deal
+	//	*PlayerAction_BuildCrib
+	//	*PlayerAction_CutDeck
+	//	*PlayerAction_Peg
+	//	*PlayerAction_CountHand
+	//	*PlayerAction_CountCrib
+	//	*PlayerAction_Forfeit
+	//	*PlayerAction_RequestTakeback
+	//	*PlayerAction_ApproveTakeback
+	Action isPlayerAction_Action `protobuf_oneof:"action"`
+}
+
+func (x *PlayerAction) Reset() {
+	*x = PlayerAction{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cribbage_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlayerAction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerAction) ProtoMessage() {}
+
+func (x *PlayerAction) ProtoReflect() protoreflect.Message {
+	mi := &file_cribbage_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerAction.ProtoReflect.Descriptor instead.
+func (*PlayerAction) Descriptor() ([]byte, []int) {
+	return file_cribbage_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PlayerAction) GetGameId() int64 {
+	if x != nil {
+		return x.GameId
+	}
+	return 0
+}
+
+func (x *PlayerAction) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *PlayerAction) GetOvercomes() Blocker {
+	if x != nil {
+		return x.Overcomes
+	}
+	return Blocker_DEAL_CARDS
+}
+
+func (m *PlayerAction) GetAction() isPlayerAction_Action {
+	if m != nil {
+		return m.Action
+	}
+	return nil
+}
+
+func (x *PlayerAction) GetDeal() *DealAction {
+	if x, ok := x.GetAction().(*PlayerAction_Deal); ok {
+		return x.Deal
+	}
+	return nil
+}
+
+func (x *PlayerAction) GetBuildCrib() *BuildCribAction {
+	if x, ok := x.GetAction().(*PlayerAction_BuildCrib); ok {
+		return x.BuildCrib
+	}
+	return nil
+}
+
+func (x *PlayerAction) GetCutDeck() *CutDeckAction {
+	if x, ok := x.GetAction().(*PlayerAction_CutDeck); ok {
+		return x.CutDeck
+	}
+	return nil
+}
+
+func (x *PlayerAction) GetPeg() *PegAction {
+	if x, ok := x.GetAction().(*PlayerAction_Peg); ok {
+		return x.Peg
+	}
+	return nil
+}
+
+func (x *PlayerAction) GetCountHand() *CountHandAction {
+	if x, ok := x.GetAction().(*PlayerAction_CountHand); ok {
+		return x.CountHand
+	}
+	return nil
+}
+
+func (x *PlayerAction) GetCountCrib() *CountCribAction {
+	if x, ok := x.GetAction().(*PlayerAction_CountCrib); ok {
+		return x.CountCrib
+	}
+	return nil
+}
+
+func (x *PlayerAction) GetForfeit() *ForfeitAction {
+	if x, ok := x.GetAction().(*PlayerAction_Forfeit); ok {
+		return x.Forfeit
+	}
+	return nil
+}
+
+func (x *PlayerAction) GetRequestTakeback() *RequestTakebackAction {
+	if x, ok := x.GetAction().(*PlayerAction_RequestTakeback); ok {
+		return x.RequestTakeback
+	}
+	return nil
+}
+
+func (x *PlayerAction) GetApproveTakeback() *ApproveTakebackAction {
+	if x, ok := x.GetAction().(*PlayerAction_ApproveTakeback); ok {
+		return x.ApproveTakeback
+	}
+	return nil
+}
+
+type isPlayerAction_Action interface {
+	isPlayerAction_Action()
+}
+
+type PlayerAction_Deal struct {
+	Deal *DealAction `protobuf:"bytes,4,opt,name=deal,proto3,oneof"`
+}
+
+type PlayerAction_BuildCrib struct {
+	BuildCrib *BuildCribAction `protobuf:"bytes,5,opt,name=build_crib,json=buildCrib,proto3,oneof"`
+}
+
+type PlayerAction_CutDeck struct {
+	CutDeck *CutDeckAction `protobuf:"bytes,6,opt,name=cut_deck,json=cutDeck,proto3,oneof"`
+}
+
+type PlayerAction_Peg struct {
+	Peg *PegAction `protobuf:"bytes,7,opt,name=peg,proto3,oneof"`
+}
+
+type PlayerAction_CountHand struct {
+	CountHand *CountHandAction `protobuf:"bytes,8,opt,name=count_hand,json=countHand,proto3,oneof"`
+}
+
+type PlayerAction_CountCrib struct {
+	CountCrib *CountCribAction `protobuf:"bytes,9,opt,name=count_crib,json=countCrib,proto3,oneof"`
+}
+
+type PlayerAction_Forfeit struct {
+	Forfeit *ForfeitAction `protobuf:"bytes,10,opt,name=forfeit,proto3,oneof"`
+}
+
+type PlayerAction_RequestTakeback struct {
+	RequestTakeback *RequestTakebackAction `protobuf:"bytes,11,opt,name=request_takeback,json=requestTakeback,proto3,oneof"`
+}
+
+type PlayerAction_ApproveTakeback struct {
+	ApproveTakeback *ApproveTakebackAction `protobuf:"bytes,12,opt,name=approve_takeback,json=approveTakeback,proto3,oneof"`
+}
+
+func (*PlayerAction_Deal) isPlayerAction_Action() {}
+
+func (*PlayerAction_BuildCrib) isPlayerAction_Action() {}
+
+func (*PlayerAction_CutDeck) isPlayerAction_Action() {}
+
+func (*PlayerAction_Peg) isPlayerAction_Action() {}
+
+func (*PlayerAction_CountHand) isPlayerAction_Action() {}
+
+func (*PlayerAction_CountCrib) isPlayerAction_Action() {}
+
+func (*PlayerAction_Forfeit) isPlayerAction_Action() {}
+
+func (*PlayerAction_RequestTakeback) isPlayerAction_Action() {}
+
+func (*PlayerAction_ApproveTakeback) isPlayerAction_Action() {}
+
+type CreatePlayerRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Player *Player `protobuf:"bytes,1,opt,name=player,proto3" json:"player,omitempty"`
+}
+
+func (x *CreatePlayerRequest) Reset() {
+	*x = CreatePlayerRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cribbage_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreatePlayerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePlayerRequest) ProtoMessage() {}
+
+func (x *CreatePlayerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cribbage_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePlayerRequest.ProtoReflect.Descriptor instead.
+func (*CreatePlayerRequest) Descriptor() ([]byte, []int) {
+	return file_cribbage_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CreatePlayerRequest) GetPlayer() *Player {
+	if x != nil {
+		return x.Player
+	}
+	return nil
+}
+
+type CreateGameRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PlayerIds []string      `protobuf:"bytes,1,rep,name=player_ids,json=playerIds,proto3" json:"player_ids,omitempty"`
+	Settings  *GameSettings `protobuf:"bytes,2,opt,name=settings,proto3" json:"settings,omitempty"`
+}
+
+func (x *CreateGameRequest) Reset() {
+	*x = CreateGameRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cribbage_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateGameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateGameRequest) ProtoMessage() {}
+
+func (x *CreateGameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cribbage_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateGameRequest.ProtoReflect.Descriptor instead.
+func (*CreateGameRequest) Descriptor() ([]byte, []int) {
+	return file_cribbage_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CreateGameRequest) GetPlayerIds() []string {
+	if x != nil {
+		return x.PlayerIds
+	}
+	return nil
+}
+
+func (x *CreateGameRequest) GetSettings() *GameSettings {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+type GetGameRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GameId int64 `protobuf:"varint,1,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	// player_id is who is asking. Without it, nobody's hand is shown.
+	PlayerId string `protobuf:"bytes,2,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+}
+
+func (x *GetGameRequest) Reset() {
+	*x = GetGameRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cribbage_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetGameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetGameRequest) ProtoMessage() {}
+
+func (x *GetGameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cribbage_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetGameRequest.ProtoReflect.Descriptor instead.
+func (*GetGameRequest) Descriptor() ([]byte, []int) {
+	return file_cribbage_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetGameRequest) GetGameId() int64 {
+	if x != nil {
+		return x.GameId
+	}
+	return 0
+}
+
+func (x *GetGameRequest) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+type SubmitActionRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Action *PlayerAction `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`
+}
+
+func (x *SubmitActionRequest) Reset() {
+	*x = SubmitActionRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cribbage_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubmitActionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitActionRequest) ProtoMessage() {}
+
+func (x *SubmitActionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cribbage_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitActionRequest.ProtoReflect.Descriptor instead.
+func (*SubmitActionRequest) Descriptor() ([]byte, []int) {
+	return file_cribbage_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *SubmitActionRequest) GetAction() *PlayerAction {
+	if x != nil {
+		return x.Action
+	}
+	return nil
+}
+
+type SubmitActionResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *SubmitActionResponse) Reset() {
+	*x = SubmitActionResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cribbage_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubmitActionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitActionResponse) ProtoMessage() {}
+
+func (x *SubmitActionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cribbage_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitActionResponse.ProtoReflect.Descriptor instead.
+func (*SubmitActionResponse) Descriptor() ([]byte, []int) {
+	return file_cribbage_proto_rawDescGZIP(), []int{23}
+}
+
+type SuggestHandRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Dealt []*Card `protobuf:"bytes,1,rep,name=dealt,proto3" json:"dealt,omitempty"`
+}
+
+func (x *SuggestHandRequest) Reset() {
+	*x = SuggestHandRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cribbage_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SuggestHandRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestHandRequest) ProtoMessage() {}
+
+func (x *SuggestHandRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cribbage_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestHandRequest.ProtoReflect.Descriptor instead.
+func (*SuggestHandRequest) Descriptor() ([]byte, []int) {
+	return file_cribbage_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *SuggestHandRequest) GetDealt() []*Card {
+	if x != nil {
+		return x.Dealt
+	}
+	return nil
+}
+
+type PointStats struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Min    int32   `protobuf:"varint,1,opt,name=min,proto3" json:"min,omitempty"`
+	Median float64 `protobuf:"fixed64,2,opt,name=median,proto3" json:"median,omitempty"`
+	Avg    float64 `protobuf:"fixed64,3,opt,name=avg,proto3" json:"avg,omitempty"`
+	Max    int32   `protobuf:"varint,4,opt,name=max,proto3" json:"max,omitempty"`
+}
+
+func (x *PointStats) Reset() {
+	*x = PointStats{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cribbage_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PointStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PointStats) ProtoMessage() {}
+
+func (x *PointStats) ProtoReflect() protoreflect.Message {
+	mi := &file_cribbage_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PointStats.ProtoReflect.Descriptor instead.
+func (*PointStats) Descriptor() ([]byte, []int) {
+	return file_cribbage_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *PointStats) GetMin() int32 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *PointStats) GetMedian() float64 {
+	if x != nil {
+		return x.Median
+	}
+	return 0
+}
+
+func (x *PointStats) GetAvg() float64 {
+	if x != nil {
+		return x.Avg
+	}
+	return 0
+}
+
+func (x *PointStats) GetMax() int32 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+type TossSuggestion struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Hand    []*Card     `protobuf:"bytes,1,rep,name=hand,proto3" json:"hand,omitempty"`
+	Toss    []*Card     `protobuf:"bytes,2,rep,name=toss,proto3" json:"toss,omitempty"`
+	HandPts *PointStats `protobuf:"bytes,3,opt,name=hand_pts,json=handPts,proto3" json:"hand_pts,omitempty"`
+	CribPts *PointStats `protobuf:"bytes,4,opt,name=crib_pts,json=cribPts,proto3" json:"crib_pts,omitempty"`
+}
+
+func (x *TossSuggestion) Reset() {
+	*x = TossSuggestion{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cribbage_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TossSuggestion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TossSuggestion) ProtoMessage() {}
+
+func (x *TossSuggestion) ProtoReflect() protoreflect.Message {
+	mi := &file_cribbage_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TossSuggestion.ProtoReflect.Descriptor instead.
+func (*TossSuggestion) Descriptor() ([]byte, []int) {
+	return file_cribbage_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *TossSuggestion) GetHand() []*Card {
+	if x != nil {
+		return x.Hand
+	}
+	return nil
+}
+
+func (x *TossSuggestion) GetToss() []*Card {
+	if x != nil {
+		return x.Toss
+	}
+	return nil
+}
+
+func (x *TossSuggestion) GetHandPts() *PointStats {
+	if x != nil {
+		return x.HandPts
+	}
+	return nil
+}
+
+func (x *TossSuggestion) GetCribPts() *PointStats {
+	if x != nil {
+		return x.CribPts
+	}
+	return nil
+}
+
+type SuggestHandResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// suggestions are sorted by the best average points in the hand
+	Suggestions []*TossSuggestion `protobuf:"bytes,1,rep,name=suggestions,proto3" json:"suggestions,omitempty"`
+}
+
+func (x *SuggestHandResponse) Reset() {
+	*x = SuggestHandResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cribbage_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SuggestHandResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestHandResponse) ProtoMessage() {}
+
+func (x *SuggestHandResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cribbage_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestHandResponse.ProtoReflect.Descriptor instead.
+func (*SuggestHandResponse) Descriptor() ([]byte, []int) {
+	return file_cribbage_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *SuggestHandResponse) GetSuggestions() []*TossSuggestion {
+	if x != nil {
+		return x.Suggestions
+	}
+	return nil
+}
+
+type WatchGameRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GameId int64 `protobuf:"varint,1,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	// player_id is who is watching. Without it, nobody's hand is shown.
+	PlayerId string `protobuf:"bytes,2,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+}
+
+func (x *WatchGameRequest) Reset() {
+	*x = WatchGameRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cribbage_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchGameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchGameRequest) ProtoMessage() {}
+
+func (x *WatchGameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cribbage_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchGameRequest.ProtoReflect.Descriptor instead.
+func (*WatchGameRequest) Descriptor() ([]byte, []int) {
+	return file_cribbage_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *WatchGameRequest) GetGameId() int64 {
+	if x != nil {
+		return x.GameId
+	}
+	return 0
+}
+
+func (x *WatchGameRequest) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+var File_cribbage_proto protoreflect.FileDescriptor
+
+var file_cribbage_proto_rawDesc = []byte{
+	0x0a, 0x0e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x08, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x22, 0x2c, 0x0a, 0x06, 0x50, 0x6c,
+	0x61, 0x79, 0x65, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x1a, 0x0a, 0x04, 0x43, 0x61, 0x72, 0x64,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x22, 0x4d, 0x0a, 0x0a, 0x50, 0x65, 0x67, 0x67, 0x65, 0x64, 0x43, 0x61,
+	0x72, 0x64, 0x12, 0x22, 0x0a, 0x04, 0x63, 0x61, 0x72, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0e, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x43, 0x61, 0x72, 0x64,
+	0x52, 0x04, 0x63, 0x61, 0x72, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72,
+	0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x79, 0x65,
+	0x72, 0x49, 0x64, 0x22, 0x2c, 0x0a, 0x04, 0x48, 0x61, 0x6e, 0x64, 0x12, 0x24, 0x0a, 0x05, 0x63,
+	0x61, 0x72, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x72, 0x69,
+	0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x43, 0x61, 0x72, 0x64, 0x52, 0x05, 0x63, 0x61, 0x72, 0x64,
+	0x73, 0x22, 0xa1, 0x01, 0x0a, 0x04, 0x54, 0x65, 0x61, 0x6d, 0x12, 0x2a, 0x0a, 0x07, 0x70, 0x6c,
+	0x61, 0x79, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x63, 0x72,
+	0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x07, 0x70,
+	0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x12, 0x2b, 0x0a, 0x05, 0x63, 0x6f, 0x6c, 0x6f, 0x72, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65,
+	0x2e, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x43, 0x6f, 0x6c, 0x6f, 0x72, 0x52, 0x05, 0x63, 0x6f,
+	0x6c, 0x6f, 0x72, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x73,
+	0x63, 0x6f, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x63, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x74, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x61, 0x67, 0x5f,
+	0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x6c, 0x61, 0x67,
+	0x53, 0x63, 0x6f, 0x72, 0x65, 0x22, 0x98, 0x01, 0x0a, 0x0c, 0x47, 0x61, 0x6d, 0x65, 0x53, 0x65,
+	0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x6d, 0x6f, 0x76, 0x65, 0x5f, 0x74,
+	0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6d, 0x6f,
+	0x76, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x6f, 0x6e, 0x5f,
+	0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6f,
+	0x6e, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x75, 0x74, 0x6f,
+	0x70, 0x6c, 0x61, 0x79, 0x5f, 0x6e, 0x70, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x61, 0x75, 0x74, 0x6f, 0x70, 0x6c, 0x61, 0x79, 0x4e, 0x70, 0x63, 0x12, 0x23, 0x0a, 0x0d, 0x70,
+	0x72, 0x6f, 0x76, 0x61, 0x62, 0x6c, 0x79, 0x5f, 0x66, 0x61, 0x69, 0x72, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0c, 0x70, 0x72, 0x6f, 0x76, 0x61, 0x62, 0x6c, 0x79, 0x46, 0x61, 0x69, 0x72,
+	0x22, 0x73, 0x0a, 0x0b, 0x47, 0x61, 0x6d, 0x65, 0x4f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x6c, 0x61, 0x79, 0x65,
+	0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x79,
+	0x65, 0x72, 0x49, 0x64, 0x12, 0x2f, 0x0a, 0x07, 0x77, 0x69, 0x6e, 0x6e, 0x65, 0x72, 0x73, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65,
+	0x2e, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x43, 0x6f, 0x6c, 0x6f, 0x72, 0x52, 0x07, 0x77, 0x69,
+	0x6e, 0x6e, 0x65, 0x72, 0x73, 0x22, 0x90, 0x06, 0x0a, 0x04, 0x47, 0x61, 0x6d, 0x65, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12, 0x24,
+	0x0a, 0x05, 0x74, 0x65, 0x61, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e,
+	0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x54, 0x65, 0x61, 0x6d, 0x52, 0x05, 0x74,
+	0x65, 0x61, 0x6d, 0x73, 0x12, 0x25, 0x0a, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x0f, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x50,
+	0x68, 0x61, 0x73, 0x65, 0x52, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x63,
+	0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x70, 0x65, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0a, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x50, 0x65, 0x67, 0x12, 0x4e, 0x0a, 0x10,
+	0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x69, 0x6e, 0x67, 0x5f, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73,
+	0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67,
+	0x65, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x69, 0x6e, 0x67, 0x50,
+	0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0f, 0x62, 0x6c, 0x6f,
+	0x63, 0x6b, 0x69, 0x6e, 0x67, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x12, 0x25, 0x0a, 0x0e,
+	0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x64, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x44, 0x65, 0x61,
+	0x6c, 0x65, 0x72, 0x12, 0x2f, 0x0a, 0x05, 0x68, 0x61, 0x6e, 0x64, 0x73, 0x18, 0x07, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x47, 0x61,
+	0x6d, 0x65, 0x2e, 0x48, 0x61, 0x6e, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x68,
+	0x61, 0x6e, 0x64, 0x73, 0x12, 0x22, 0x0a, 0x04, 0x63, 0x72, 0x69, 0x62, 0x18, 0x08, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x43, 0x61,
+	0x72, 0x64, 0x52, 0x04, 0x63, 0x72, 0x69, 0x62, 0x12, 0x29, 0x0a, 0x08, 0x63, 0x75, 0x74, 0x5f,
+	0x63, 0x61, 0x72, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x72, 0x69,
+	0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x43, 0x61, 0x72, 0x64, 0x52, 0x07, 0x63, 0x75, 0x74, 0x43,
+	0x61, 0x72, 0x64, 0x12, 0x37, 0x0a, 0x0c, 0x70, 0x65, 0x67, 0x67, 0x65, 0x64, 0x5f, 0x63, 0x61,
+	0x72, 0x64, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x63, 0x72, 0x69, 0x62,
+	0x62, 0x61, 0x67, 0x65, 0x2e, 0x50, 0x65, 0x67, 0x67, 0x65, 0x64, 0x43, 0x61, 0x72, 0x64, 0x52,
+	0x0b, 0x70, 0x65, 0x67, 0x67, 0x65, 0x64, 0x43, 0x61, 0x72, 0x64, 0x73, 0x12, 0x32, 0x0a, 0x08,
+	0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16,
+	0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x53, 0x65,
+	0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x52, 0x08, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73,
+	0x12, 0x2f, 0x0a, 0x07, 0x6f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x15, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x47, 0x61, 0x6d,
+	0x65, 0x4f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x52, 0x07, 0x6f, 0x75, 0x74, 0x63, 0x6f, 0x6d,
+	0x65, 0x12, 0x22, 0x0a, 0x04, 0x64, 0x65, 0x63, 0x6b, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x0e, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x43, 0x61, 0x72, 0x64, 0x52,
+	0x04, 0x64, 0x65, 0x63, 0x6b, 0x12, 0x30, 0x0a, 0x07, 0x73, 0x68, 0x75, 0x66, 0x66, 0x6c, 0x65,
+	0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67,
+	0x65, 0x2e, 0x53, 0x68, 0x75, 0x66, 0x66, 0x6c, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x07,
+	0x73, 0x68, 0x75, 0x66, 0x66, 0x6c, 0x65, 0x1a, 0x55, 0x0a, 0x14, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x69, 0x6e, 0x67, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x27, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x11, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x42, 0x6c, 0x6f, 0x63,
+	0x6b, 0x65, 0x72, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x48,
+	0x0a, 0x0a, 0x48, 0x61, 0x6e, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x24,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e,
+	0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x48, 0x61, 0x6e, 0x64, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x9d, 0x01, 0x0a, 0x0c, 0x53, 0x68, 0x75,
+	0x66, 0x66, 0x6c, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6d,
+	0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63,
+	0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x65, 0x72,
+	0x76, 0x65, 0x72, 0x5f, 0x73, 0x65, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x53, 0x65, 0x65, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6c,
+	0x69, 0x65, 0x6e, 0x74, 0x5f, 0x65, 0x6e, 0x74, 0x72, 0x6f, 0x70, 0x79, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0d, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x6f, 0x70,
+	0x79, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x75, 0x74, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74,
+	0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0d, 0x63, 0x75, 0x74, 0x50, 0x65,
+	0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x22, 0x49, 0x0a, 0x0a, 0x44, 0x65, 0x61, 0x6c,
+	0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x21, 0x0a, 0x0c, 0x6e, 0x75, 0x6d, 0x5f, 0x73, 0x68,
+	0x75, 0x66, 0x66, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x6e, 0x75,
+	0x6d, 0x53, 0x68, 0x75, 0x66, 0x66, 0x6c, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x6e, 0x74,
+	0x72, 0x6f, 0x70, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72,
+	0x6f, 0x70, 0x79, 0x22, 0x37, 0x0a, 0x0f, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x43, 0x72, 0x69, 0x62,
+	0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x24, 0x0a, 0x05, 0x63, 0x61, 0x72, 0x64, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65,
+	0x2e, 0x43, 0x61, 0x72, 0x64, 0x52, 0x05, 0x63, 0x61, 0x72, 0x64, 0x73, 0x22, 0x2f, 0x0a, 0x0d,
+	0x43, 0x75, 0x74, 0x44, 0x65, 0x63, 0x6b, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1e, 0x0a,
+	0x0a, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x0a, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x61, 0x67, 0x65, 0x22, 0x46, 0x0a,
+	0x09, 0x50, 0x65, 0x67, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x22, 0x0a, 0x04, 0x63, 0x61,
+	0x72, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62,
+	0x61, 0x67, 0x65, 0x2e, 0x43, 0x61, 0x72, 0x64, 0x52, 0x04, 0x63, 0x61, 0x72, 0x64, 0x12, 0x15,
+	0x0a, 0x06, 0x73, 0x61, 0x79, 0x5f, 0x67, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05,
+	0x73, 0x61, 0x79, 0x47, 0x6f, 0x22, 0x23, 0x0a, 0x0f, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x48, 0x61,
+	0x6e, 0x64, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x70, 0x74, 0x73, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x70, 0x74, 0x73, 0x22, 0x23, 0x0a, 0x0f, 0x43, 0x6f,
+	0x75, 0x6e, 0x74, 0x43, 0x72, 0x69, 0x62, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x10, 0x0a,
+	0x03, 0x70, 0x74, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x70, 0x74, 0x73, 0x22,
+	0x0f, 0x0a, 0x0d, 0x46, 0x6f, 0x72, 0x66, 0x65, 0x69, 0x74, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x22, 0x17, 0x0a, 0x15, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x54, 0x61, 0x6b, 0x65, 0x62,
+	0x61, 0x63, 0x6b, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x31, 0x0a, 0x15, 0x41, 0x70, 0x70,
+	0x72, 0x6f, 0x76, 0x65, 0x54, 0x61, 0x6b, 0x65, 0x62, 0x61, 0x63, 0x6b, 0x41, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x07, 0x61, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x22, 0x8f, 0x05, 0x0a,
+	0x0c, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x17, 0x0a,
+	0x07, 0x67, 0x61, 0x6d, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06,
+	0x67, 0x61, 0x6d, 0x65, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72,
+	0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x79, 0x65,
+	0x72, 0x49, 0x64, 0x12, 0x2f, 0x0a, 0x09, 0x6f, 0x76, 0x65, 0x72, 0x63, 0x6f, 0x6d, 0x65, 0x73,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67,
+	0x65, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x52, 0x09, 0x6f, 0x76, 0x65, 0x72, 0x63,
+	0x6f, 0x6d, 0x65, 0x73, 0x12, 0x2a, 0x0a, 0x04, 0x64, 0x65, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x14, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x44, 0x65,
+	0x61, 0x6c, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x04, 0x64, 0x65, 0x61, 0x6c,
+	0x12, 0x3a, 0x0a, 0x0a, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x63, 0x72, 0x69, 0x62, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e,
+	0x42, 0x75, 0x69, 0x6c, 0x64, 0x43, 0x72, 0x69, 0x62, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x48,
+	0x00, 0x52, 0x09, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x43, 0x72, 0x69, 0x62, 0x12, 0x34, 0x0a, 0x08,
+	0x63, 0x75, 0x74, 0x5f, 0x64, 0x65, 0x63, 0x6b, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17,
+	0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x43, 0x75, 0x74, 0x44, 0x65, 0x63,
+	0x6b, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x07, 0x63, 0x75, 0x74, 0x44, 0x65,
+	0x63, 0x6b, 0x12, 0x27, 0x0a, 0x03, 0x70, 0x65, 0x67, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x13, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x50, 0x65, 0x67, 0x41, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x03, 0x70, 0x65, 0x67, 0x12, 0x3a, 0x0a, 0x0a, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x68, 0x61, 0x6e, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x19, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x48, 0x61, 0x6e, 0x64, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x09, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x48, 0x61, 0x6e, 0x64, 0x12, 0x3a, 0x0a, 0x0a, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x5f, 0x63, 0x72, 0x69, 0x62, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x63, 0x72,
+	0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x43, 0x72, 0x69, 0x62,
+	0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x09, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x43,
+	0x72, 0x69, 0x62, 0x12, 0x33, 0x0a, 0x07, 0x66, 0x6f, 0x72, 0x66, 0x65, 0x69, 0x74, 0x18, 0x0a,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e,
+	0x46, 0x6f, 0x72, 0x66, 0x65, 0x69, 0x74, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52,
+	0x07, 0x66, 0x6f, 0x72, 0x66, 0x65, 0x69, 0x74, 0x12, 0x4c, 0x0a, 0x10, 0x72, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x5f, 0x74, 0x61, 0x6b, 0x65, 0x62, 0x61, 0x63, 0x6b, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x54, 0x61, 0x6b, 0x65, 0x62, 0x61, 0x63, 0x6b, 0x41, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x0f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x54, 0x61,
+	0x6b, 0x65, 0x62, 0x61, 0x63, 0x6b, 0x12, 0x4c, 0x0a, 0x10, 0x61, 0x70, 0x70, 0x72, 0x6f, 0x76,
+	0x65, 0x5f, 0x74, 0x61, 0x6b, 0x65, 0x62, 0x61, 0x63, 0x6b, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1f, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x41, 0x70, 0x70, 0x72,
+	0x6f, 0x76, 0x65, 0x54, 0x61, 0x6b, 0x65, 0x62, 0x61, 0x63, 0x6b, 0x41, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x48, 0x00, 0x52, 0x0f, 0x61, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x54, 0x61, 0x6b, 0x65,
+	0x62, 0x61, 0x63, 0x6b, 0x42, 0x08, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x3f,
+	0x0a, 0x13, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x28, 0x0a, 0x06, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65,
+	0x2e, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x06, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x22,
+	0x66, 0x0a, 0x11, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x47, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x5f, 0x69,
+	0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72,
+	0x49, 0x64, 0x73, 0x12, 0x32, 0x0a, 0x08, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65,
+	0x2e, 0x47, 0x61, 0x6d, 0x65, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x52, 0x08, 0x73,
+	0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x22, 0x46, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x47, 0x61,
+	0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x67, 0x61, 0x6d,
+	0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x67, 0x61, 0x6d, 0x65,
+	0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x64, 0x22,
+	0x45, 0x0a, 0x13, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2e, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67,
+	0x65, 0x2e, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x16, 0x0a, 0x14, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74,
+	0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x3a,
+	0x0a, 0x12, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x48, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x05, 0x64, 0x65, 0x61, 0x6c, 0x74, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x43,
+	0x61, 0x72, 0x64, 0x52, 0x05, 0x64, 0x65, 0x61, 0x6c, 0x74, 0x22, 0x5a, 0x0a, 0x0a, 0x50, 0x6f,
+	0x69, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x69, 0x6e, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x6d, 0x69, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65,
+	0x64, 0x69, 0x61, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x6d, 0x65, 0x64, 0x69,
+	0x61, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x76, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x03, 0x61, 0x76, 0x67, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x61, 0x78, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x03, 0x6d, 0x61, 0x78, 0x22, 0xba, 0x01, 0x0a, 0x0e, 0x54, 0x6f, 0x73, 0x73, 0x53,
+	0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x22, 0x0a, 0x04, 0x68, 0x61, 0x6e,
+	0x64, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61,
+	0x67, 0x65, 0x2e, 0x43, 0x61, 0x72, 0x64, 0x52, 0x04, 0x68, 0x61, 0x6e, 0x64, 0x12, 0x22, 0x0a,
+	0x04, 0x74, 0x6f, 0x73, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x72,
+	0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x43, 0x61, 0x72, 0x64, 0x52, 0x04, 0x74, 0x6f, 0x73,
+	0x73, 0x12, 0x2f, 0x0a, 0x08, 0x68, 0x61, 0x6e, 0x64, 0x5f, 0x70, 0x74, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x50,
+	0x6f, 0x69, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x07, 0x68, 0x61, 0x6e, 0x64, 0x50,
+	0x74, 0x73, 0x12, 0x2f, 0x0a, 0x08, 0x63, 0x72, 0x69, 0x62, 0x5f, 0x70, 0x74, 0x73, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e,
+	0x50, 0x6f, 0x69, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x07, 0x63, 0x72, 0x69, 0x62,
+	0x50, 0x74, 0x73, 0x22, 0x51, 0x0a, 0x13, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x48, 0x61,
+	0x6e, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x0b, 0x73, 0x75,
+	0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x18, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x54, 0x6f, 0x73, 0x73, 0x53,
+	0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x73, 0x75, 0x67, 0x67, 0x65,
+	0x73, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x48, 0x0a, 0x10, 0x57, 0x61, 0x74, 0x63, 0x68, 0x47,
+	0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x67, 0x61,
+	0x6d, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x67, 0x61, 0x6d,
+	0x65, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x5f, 0x69, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x64,
+	0x2a, 0x9d, 0x01, 0x0a, 0x07, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x65, 0x72, 0x12, 0x0e, 0x0a, 0x0a,
+	0x44, 0x45, 0x41, 0x4c, 0x5f, 0x43, 0x41, 0x52, 0x44, 0x53, 0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09,
+	0x43, 0x52, 0x49, 0x42, 0x5f, 0x43, 0x41, 0x52, 0x44, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x43,
+	0x55, 0x54, 0x5f, 0x43, 0x41, 0x52, 0x44, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08, 0x50, 0x45, 0x47,
+	0x5f, 0x43, 0x41, 0x52, 0x44, 0x10, 0x03, 0x12, 0x0e, 0x0a, 0x0a, 0x43, 0x4f, 0x55, 0x4e, 0x54,
+	0x5f, 0x48, 0x41, 0x4e, 0x44, 0x10, 0x04, 0x12, 0x0e, 0x0a, 0x0a, 0x43, 0x4f, 0x55, 0x4e, 0x54,
+	0x5f, 0x43, 0x52, 0x49, 0x42, 0x10, 0x05, 0x12, 0x0b, 0x0a, 0x07, 0x46, 0x4f, 0x52, 0x46, 0x45,
+	0x49, 0x54, 0x10, 0x06, 0x12, 0x14, 0x0a, 0x10, 0x52, 0x45, 0x51, 0x55, 0x45, 0x53, 0x54, 0x5f,
+	0x54, 0x41, 0x4b, 0x45, 0x42, 0x41, 0x43, 0x4b, 0x10, 0x07, 0x12, 0x14, 0x0a, 0x10, 0x41, 0x50,
+	0x50, 0x52, 0x4f, 0x56, 0x45, 0x5f, 0x54, 0x41, 0x4b, 0x45, 0x42, 0x41, 0x43, 0x4b, 0x10, 0x08,
+	0x2a, 0xd0, 0x01, 0x0a, 0x05, 0x50, 0x68, 0x61, 0x73, 0x65, 0x12, 0x08, 0x0a, 0x04, 0x44, 0x45,
+	0x41, 0x4c, 0x10, 0x00, 0x12, 0x14, 0x0a, 0x10, 0x42, 0x55, 0x49, 0x4c, 0x44, 0x5f, 0x43, 0x52,
+	0x49, 0x42, 0x5f, 0x52, 0x45, 0x41, 0x44, 0x59, 0x10, 0x01, 0x12, 0x0e, 0x0a, 0x0a, 0x42, 0x55,
+	0x49, 0x4c, 0x44, 0x5f, 0x43, 0x52, 0x49, 0x42, 0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09, 0x43, 0x55,
+	0x54, 0x5f, 0x52, 0x45, 0x41, 0x44, 0x59, 0x10, 0x03, 0x12, 0x07, 0x0a, 0x03, 0x43, 0x55, 0x54,
+	0x10, 0x04, 0x12, 0x11, 0x0a, 0x0d, 0x50, 0x45, 0x47, 0x47, 0x49, 0x4e, 0x47, 0x5f, 0x52, 0x45,
+	0x41, 0x44, 0x59, 0x10, 0x05, 0x12, 0x0b, 0x0a, 0x07, 0x50, 0x45, 0x47, 0x47, 0x49, 0x4e, 0x47,
+	0x10, 0x06, 0x12, 0x12, 0x0a, 0x0e, 0x43, 0x4f, 0x55, 0x4e, 0x54, 0x49, 0x4e, 0x47, 0x5f, 0x52,
+	0x45, 0x41, 0x44, 0x59, 0x10, 0x07, 0x12, 0x0c, 0x0a, 0x08, 0x43, 0x4f, 0x55, 0x4e, 0x54, 0x49,
+	0x4e, 0x47, 0x10, 0x08, 0x12, 0x17, 0x0a, 0x13, 0x43, 0x52, 0x49, 0x42, 0x5f, 0x43, 0x4f, 0x55,
+	0x4e, 0x54, 0x49, 0x4e, 0x47, 0x5f, 0x52, 0x45, 0x41, 0x44, 0x59, 0x10, 0x09, 0x12, 0x11, 0x0a,
+	0x0d, 0x43, 0x52, 0x49, 0x42, 0x5f, 0x43, 0x4f, 0x55, 0x4e, 0x54, 0x49, 0x4e, 0x47, 0x10, 0x0a,
+	0x12, 0x11, 0x0a, 0x0d, 0x44, 0x45, 0x41, 0x4c, 0x49, 0x4e, 0x47, 0x5f, 0x52, 0x45, 0x41, 0x44,
+	0x59, 0x10, 0x0b, 0x2a, 0x3c, 0x0a, 0x0b, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x43, 0x6f, 0x6c,
+	0x6f, 0x72, 0x12, 0x0f, 0x0a, 0x0b, 0x55, 0x4e, 0x53, 0x45, 0x54, 0x5f, 0x43, 0x4f, 0x4c, 0x4f,
+	0x52, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x47, 0x52, 0x45, 0x45, 0x4e, 0x10, 0x01, 0x12, 0x08,
+	0x0a, 0x04, 0x42, 0x4c, 0x55, 0x45, 0x10, 0x02, 0x12, 0x07, 0x0a, 0x03, 0x52, 0x45, 0x44, 0x10,
+	0x03, 0x32, 0x91, 0x03, 0x0a, 0x08, 0x43, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x12, 0x3f,
+	0x0a, 0x0c, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x12, 0x1d,
+	0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e,
+	0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x12,
+	0x39, 0x0a, 0x0a, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x47, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x2e,
+	0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x47,
+	0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x63, 0x72, 0x69,
+	0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x12, 0x33, 0x0a, 0x07, 0x47, 0x65,
+	0x74, 0x47, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65,
+	0x2e, 0x47, 0x65, 0x74, 0x47, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x0e, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x12,
+	0x4d, 0x0a, 0x0c, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x1d, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69,
+	0x74, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e,
+	0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74,
+	0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4a,
+	0x0a, 0x0b, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x48, 0x61, 0x6e, 0x64, 0x12, 0x1c, 0x2e,
+	0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74,
+	0x48, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x63, 0x72,
+	0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x48, 0x61,
+	0x6e, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x39, 0x0a, 0x09, 0x57, 0x61,
+	0x74, 0x63, 0x68, 0x47, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61,
+	0x67, 0x65, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x47, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2e, 0x47,
+	0x61, 0x6d, 0x65, 0x30, 0x01, 0x42, 0x3a, 0x5a, 0x38, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2f, 0x6a, 0x6f, 0x73, 0x68, 0x70, 0x72, 0x7a, 0x79, 0x62, 0x79, 0x73, 0x7a,
+	0x65, 0x77, 0x73, 0x6b, 0x69, 0x2f, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x2f, 0x6e,
+	0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2f, 0x63, 0x72, 0x69, 0x62, 0x62, 0x61, 0x67, 0x65, 0x70,
+	0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_cribbage_proto_rawDescOnce sync.Once
+	file_cribbage_proto_rawDescData = file_cribbage_proto_rawDesc
+)
+
+func file_cribbage_proto_rawDescGZIP() []byte {
+	file_cribbage_proto_rawDescOnce.Do(func() {
+		file_cribbage_proto_rawDescData = protoimpl.X.CompressGZIP(file_cribbage_proto_rawDescData)
+	})
+	return file_cribbage_proto_rawDescData
+}
+
+var file_cribbage_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_cribbage_proto_msgTypes = make([]protoimpl.MessageInfo, 31)
+var file_cribbage_proto_goTypes = []interface{}{
+	(Blocker)(0),                  // 0: cribbage.Blocker
+	(Phase)(0),                    // 1: cribbage.Phase
+	(PlayerColor)(0),              // 2: cribbage.PlayerColor
+	(*Player)(nil),                // 3: cribbage.Player
+	(*Card)(nil),                  // 4: cribbage.Card
+	(*PeggedCard)(nil),            // 5: cribbage.PeggedCard
+	(*Hand)(nil),                  // 6: cribbage.Hand
+	(*Team)(nil),                  // 7: cribbage.Team
+	(*GameSettings)(nil),          // 8: cribbage.GameSettings
+	(*GameOutcome)(nil),           // 9: cribbage.GameOutcome
+	(*Game)(nil),                  // 10: cribbage.Game
+	(*ShuffleProof)(nil),          // 11: cribbage.ShuffleProof
+	(*DealAction)(nil),            // 12: cribbage.DealAction
+	(*BuildCribAction)(nil),       // 13: cribbage.BuildCribAction
+	(*CutDeckAction)(nil),         // 14: cribbage.CutDeckAction
+	(*PegAction)(nil),             // 15: cribbage.PegAction
+	(*CountHandAction)(nil),       // 16: cribbage.CountHandAction
+	(*CountCribAction)(nil),       // 17: cribbage.CountCribAction
+	(*ForfeitAction)(nil),         // 18: cribbage.ForfeitAction
+	(*RequestTakebackAction)(nil), // 19: cribbage.RequestTakebackAction
+	(*ApproveTakebackAction)(nil), // 20: cribbage.ApproveTakebackAction
+	(*PlayerAction)(nil),          // 21: cribbage.PlayerAction
+	(*CreatePlayerRequest)(nil),   // 22: cribbage.CreatePlayerRequest
+	(*CreateGameRequest)(nil),     // 23: cribbage.CreateGameRequest
+	(*GetGameRequest)(nil),        // 24: cribbage.GetGameRequest
+	(*SubmitActionRequest)(nil),   // 25: cribbage.SubmitActionRequest
+	(*SubmitActionResponse)(nil),  // 26: cribbage.SubmitActionResponse
+	(*SuggestHandRequest)(nil),    // 27: cribbage.SuggestHandRequest
+	(*PointStats)(nil),            // 28: cribbage.PointStats
+	(*TossSuggestion)(nil),        // 29: cribbage.TossSuggestion
+	(*SuggestHandResponse)(nil),   // 30: cribbage.SuggestHandResponse
+	(*WatchGameRequest)(nil),      // 31: cribbage.WatchGameRequest
+	nil,                           // 32: cribbage.Game.BlockingPlayersEntry
+	nil,                           // 33: cribbage.Game.HandsEntry
+}
+var file_cribbage_proto_depIdxs = []int32{
+	4,  // 0: cribbage.PeggedCard.card:type_name -> cribbage.Card
+	4,  // 1: cribbage.Hand.cards:type_name -> cribbage.Card
+	3,  // 2: cribbage.Team.players:type_name -> cribbage.Player
+	2,  // 3: cribbage.Team.color:type_name -> cribbage.PlayerColor
+	2,  // 4: cribbage.GameOutcome.winners:type_name -> cribbage.PlayerColor
+	7,  // 5: cribbage.Game.teams:type_name -> cribbage.Team
+	1,  // 6: cribbage.Game.phase:type_name -> cribbage.Phase
+	32, // 7: cribbage.Game.blocking_players:type_name -> cribbage.Game.BlockingPlayersEntry
+	33, // 8: cribbage.Game.hands:type_name -> cribbage.Game.HandsEntry
+	4,  // 9: cribbage.Game.crib:type_name -> cribbage.Card
+	4,  // 10: cribbage.Game.cut_card:type_name -> cribbage.Card
+	5,  // 11: cribbage.Game.pegged_cards:type_name -> cribbage.PeggedCard
+	8,  // 12: cribbage.Game.settings:type_name -> cribbage.GameSettings
+	9,  // 13: cribbage.Game.outcome:type_name -> cribbage.GameOutcome
+	4,  // 14: cribbage.Game.deck:type_name -> cribbage.Card
+	11, // 15: cribbage.Game.shuffle:type_name -> cribbage.ShuffleProof
+	4,  // 16: cribbage.BuildCribAction.cards:type_name -> cribbage.Card
+	4,  // 17: cribbage.PegAction.card:type_name -> cribbage.Card
+	0,  // 18: cribbage.PlayerAction.overcomes:type_name -> cribbage.Blocker
+	12, // 19: cribbage.PlayerAction.deal:type_name -> cribbage.DealAction
+	13, // 20: cribbage.PlayerAction.build_crib:type_name -> cribbage.BuildCribAction
+	14, // 21: cribbage.PlayerAction.cut_deck:type_name -> cribbage.CutDeckAction
+	15, // 22: cribbage.PlayerAction.peg:type_name -> cribbage.PegAction
+	16, // 23: cribbage.PlayerAction.count_hand:type_name -> cribbage.CountHandAction
+	17, // 24: cribbage.PlayerAction.count_crib:type_name -> cribbage.CountCribAction
+	18, // 25: cribbage.PlayerAction.forfeit:type_name -> cribbage.ForfeitAction
+	19, // 26: cribbage.PlayerAction.request_takeback:type_name -> cribbage.RequestTakebackAction
+	20, // 27: cribbage.PlayerAction.approve_takeback:type_name -> cribbage.ApproveTakebackAction
+	3,  // 28: cribbage.CreatePlayerRequest.player:type_name -> cribbage.Player
+	8,  // 29: cribbage.CreateGameRequest.settings:type_name -> cribbage.GameSettings
+	21, // 30: cribbage.SubmitActionRequest.action:type_name -> cribbage.PlayerAction
+	4,  // 31: cribbage.SuggestHandRequest.dealt:type_name -> cribbage.Card
+	4,  // 32: cribbage.TossSuggestion.hand:type_name -> cribbage.Card
+	4,  // 33: cribbage.TossSuggestion.toss:type_name -> cribbage.Card
+	28, // 34: cribbage.TossSuggestion.hand_pts:type_name -> cribbage.PointStats
+	28, // 35: cribbage.TossSuggestion.crib_pts:type_name -> cribbage.PointStats
+	29, // 36: cribbage.SuggestHandResponse.suggestions:type_name -> cribbage.TossSuggestion
+	0,  // 37: cribbage.Game.BlockingPlayersEntry.value:type_name -> cribbage.Blocker
+	6,  // 38: cribbage.Game.HandsEntry.value:type_name -> cribbage.Hand
+	22, // 39: cribbage.Cribbage.CreatePlayer:input_type -> cribbage.CreatePlayerRequest
+	23, // 40: cribbage.Cribbage.CreateGame:input_type -> cribbage.CreateGameRequest
+	24, // 41: cribbage.Cribbage.GetGame:input_type -> cribbage.GetGameRequest
+	25, // 42: cribbage.Cribbage.SubmitAction:input_type -> cribbage.SubmitActionRequest
+	27, // 43: cribbage.Cribbage.SuggestHand:input_type -> cribbage.SuggestHandRequest
+	31, // 44: cribbage.Cribbage.WatchGame:input_type -> cribbage.WatchGameRequest
+	3,  // 45: cribbage.Cribbage.CreatePlayer:output_type -> cribbage.Player
+	10, // 46: cribbage.Cribbage.CreateGame:output_type -> cribbage.Game
+	10, // 47: cribbage.Cribbage.GetGame:output_type -> cribbage.Game
+	26, // 48: cribbage.Cribbage.SubmitAction:output_type -> cribbage.SubmitActionResponse
+	30, // 49: cribbage.Cribbage.SuggestHand:output_type -> cribbage.SuggestHandResponse
+	10, // 50: cribbage.Cribbage.WatchGame:output_type -> cribbage.Game
+	45, // [45:51] is the sub-list for method output_type
+	39, // [39:45] is the sub-list for method input_type
+	39, // [39:39] is the sub-list for extension type_name
+	39, // [39:39] is the sub-list for extension extendee
+	0,  // [0:39] is the sub-list for field type_name
+}
+
+func init() { file_cribbage_proto_init() }
+func file_cribbage_proto_init() {
+	if File_cribbage_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_cribbage_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Player); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cribbage_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Card); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cribbage_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PeggedCard); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cribbage_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Hand); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cribbage_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Team); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cribbage_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GameSettings); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cribbage_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GameOutcome); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cribbage_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Game); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cribbage_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShuffleProof); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cribbage_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DealAction); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cribbage_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BuildCribAction); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cribbage_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CutDeckAction); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cribbage_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PegAction); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cribbage_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CountHandAction); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cribbage_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CountCribAction); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cribbage_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ForfeitAction); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cribbage_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RequestTakebackAction); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cribbage_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ApproveTakebackAction); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cribbage_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlayerAction); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cribbage_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreatePlayerRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cribbage_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateGameRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cribbage_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetGameRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cribbage_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubmitActionRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cribbage_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubmitActionResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cribbage_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SuggestHandRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cribbage_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PointStats); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cribbage_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TossSuggestion); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cribbage_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SuggestHandResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cribbage_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchGameRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_cribbage_proto_msgTypes[18].OneofWrappers = []interface{}{
+		(*PlayerAction_Deal)(nil),
+		(*PlayerAction_BuildCrib)(nil),
+		(*PlayerAction_CutDeck)(nil),
+		(*PlayerAction_Peg)(nil),
+		(*PlayerAction_CountHand)(nil),
+		(*PlayerAction_CountCrib)(nil),
+		(*PlayerAction_Forfeit)(nil),
+		(*PlayerAction_RequestTakeback)(nil),
+		(*PlayerAction_ApproveTakeback)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_cribbage_proto_rawDesc,
+			NumEnums:      3,
+			NumMessages:   31,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_cribbage_proto_goTypes,
+		DependencyIndexes: file_cribbage_proto_depIdxs,
+		EnumInfos:         file_cribbage_proto_enumTypes,
+		MessageInfos:      file_cribbage_proto_msgTypes,
+	}.Build()
+	File_cribbage_proto = out.File
+	file_cribbage_proto_rawDesc = nil
+	file_cribbage_proto_goTypes = nil
+	file_cribbage_proto_depIdxs = nil
+}
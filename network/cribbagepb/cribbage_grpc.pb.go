@@ -0,0 +1,309 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package cribbagepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion7
+
+// CribbageClient is the client API for Cribbage service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CribbageClient interface {
+	CreatePlayer(ctx context.Context, in *CreatePlayerRequest, opts ...grpc.CallOption) (*Player, error)
+	CreateGame(ctx context.Context, in *CreateGameRequest, opts ...grpc.CallOption) (*Game, error)
+	GetGame(ctx context.Context, in *GetGameRequest, opts ...grpc.CallOption) (*Game, error)
+	SubmitAction(ctx context.Context, in *SubmitActionRequest, opts ...grpc.CallOption) (*SubmitActionResponse, error)
+	SuggestHand(ctx context.Context, in *SuggestHandRequest, opts ...grpc.CallOption) (*SuggestHandResponse, error)
+	// WatchGame sends the game right away, and then again every time it changes.
+	// The stream ends once the game is over.
+	WatchGame(ctx context.Context, in *WatchGameRequest, opts ...grpc.CallOption) (Cribbage_WatchGameClient, error)
+}
+
+type cribbageClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCribbageClient(cc grpc.ClientConnInterface) CribbageClient {
+	return &cribbageClient{cc}
+}
+
+func (c *cribbageClient) CreatePlayer(ctx context.Context, in *CreatePlayerRequest, opts ...grpc.CallOption) (*Player, error) {
+	out := new(Player)
+	err := c.cc.Invoke(ctx, "/cribbage.Cribbage/CreatePlayer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cribbageClient) CreateGame(ctx context.Context, in *CreateGameRequest, opts ...grpc.CallOption) (*Game, error) {
+	out := new(Game)
+	err := c.cc.Invoke(ctx, "/cribbage.Cribbage/CreateGame", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cribbageClient) GetGame(ctx context.Context, in *GetGameRequest, opts ...grpc.CallOption) (*Game, error) {
+	out := new(Game)
+	err := c.cc.Invoke(ctx, "/cribbage.Cribbage/GetGame", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cribbageClient) SubmitAction(ctx context.Context, in *SubmitActionRequest, opts ...grpc.CallOption) (*SubmitActionResponse, error) {
+	out := new(SubmitActionResponse)
+	err := c.cc.Invoke(ctx, "/cribbage.Cribbage/SubmitAction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cribbageClient) SuggestHand(ctx context.Context, in *SuggestHandRequest, opts ...grpc.CallOption) (*SuggestHandResponse, error) {
+	out := new(SuggestHandResponse)
+	err := c.cc.Invoke(ctx, "/cribbage.Cribbage/SuggestHand", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cribbageClient) WatchGame(ctx context.Context, in *WatchGameRequest, opts ...grpc.CallOption) (Cribbage_WatchGameClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Cribbage_serviceDesc.Streams[0], "/cribbage.Cribbage/WatchGame", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &cribbageWatchGameClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Cribbage_WatchGameClient interface {
+	Recv() (*Game, error)
+	grpc.ClientStream
+}
+
+type cribbageWatchGameClient struct {
+	grpc.ClientStream
+}
+
+func (x *cribbageWatchGameClient) Recv() (*Game, error) {
+	m := new(Game)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// CribbageServer is the server API for Cribbage service.
+// All implementations must embed UnimplementedCribbageServer
+// for forward compatibility
+type CribbageServer interface {
+	CreatePlayer(context.Context, *CreatePlayerRequest) (*Player, error)
+	CreateGame(context.Context, *CreateGameRequest) (*Game, error)
+	GetGame(context.Context, *GetGameRequest) (*Game, error)
+	SubmitAction(context.Context, *SubmitActionRequest) (*SubmitActionResponse, error)
+	SuggestHand(context.Context, *SuggestHandRequest) (*SuggestHandResponse, error)
+	// WatchGame sends the game right away, and then again every time it changes.
+	// The stream ends once the game is over.
+	WatchGame(*WatchGameRequest, Cribbage_WatchGameServer) error
+	mustEmbedUnimplementedCribbageServer()
+}
+
+// UnimplementedCribbageServer must be embedded to have forward compatible implementations.
+type UnimplementedCribbageServer struct {
+}
+
+func (UnimplementedCribbageServer) CreatePlayer(context.Context, *CreatePlayerRequest) (*Player, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePlayer not implemented")
+}
+func (UnimplementedCribbageServer) CreateGame(context.Context, *CreateGameRequest) (*Game, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateGame not implemented")
+}
+func (UnimplementedCribbageServer) GetGame(context.Context, *GetGameRequest) (*Game, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGame not implemented")
+}
+func (UnimplementedCribbageServer) SubmitAction(context.Context, *SubmitActionRequest) (*SubmitActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitAction not implemented")
+}
+func (UnimplementedCribbageServer) SuggestHand(context.Context, *SuggestHandRequest) (*SuggestHandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SuggestHand not implemented")
+}
+func (UnimplementedCribbageServer) WatchGame(*WatchGameRequest, Cribbage_WatchGameServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchGame not implemented")
+}
+func (UnimplementedCribbageServer) mustEmbedUnimplementedCribbageServer() {}
+
+// UnsafeCribbageServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CribbageServer will
+// result in compilation errors.
+type UnsafeCribbageServer interface {
+	mustEmbedUnimplementedCribbageServer()
+}
+
+func RegisterCribbageServer(s grpc.ServiceRegistrar, srv CribbageServer) {
+	s.RegisterService(&_Cribbage_serviceDesc, srv)
+}
+
+func _Cribbage_CreatePlayer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePlayerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CribbageServer).CreatePlayer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cribbage.Cribbage/CreatePlayer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CribbageServer).CreatePlayer(ctx, req.(*CreatePlayerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cribbage_CreateGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateGameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CribbageServer).CreateGame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cribbage.Cribbage/CreateGame",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CribbageServer).CreateGame(ctx, req.(*CreateGameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cribbage_GetGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CribbageServer).GetGame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cribbage.Cribbage/GetGame",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CribbageServer).GetGame(ctx, req.(*GetGameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cribbage_SubmitAction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CribbageServer).SubmitAction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cribbage.Cribbage/SubmitAction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CribbageServer).SubmitAction(ctx, req.(*SubmitActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cribbage_SuggestHand_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuggestHandRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CribbageServer).SuggestHand(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cribbage.Cribbage/SuggestHand",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CribbageServer).SuggestHand(ctx, req.(*SuggestHandRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cribbage_WatchGame_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchGameRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CribbageServer).WatchGame(m, &cribbageWatchGameServer{stream})
+}
+
+type Cribbage_WatchGameServer interface {
+	Send(*Game) error
+	grpc.ServerStream
+}
+
+type cribbageWatchGameServer struct {
+	grpc.ServerStream
+}
+
+func (x *cribbageWatchGameServer) Send(m *Game) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Cribbage_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "cribbage.Cribbage",
+	HandlerType: (*CribbageServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreatePlayer",
+			Handler:    _Cribbage_CreatePlayer_Handler,
+		},
+		{
+			MethodName: "CreateGame",
+			Handler:    _Cribbage_CreateGame_Handler,
+		},
+		{
+			MethodName: "GetGame",
+			Handler:    _Cribbage_GetGame_Handler,
+		},
+		{
+			MethodName: "SubmitAction",
+			Handler:    _Cribbage_SubmitAction_Handler,
+		},
+		{
+			MethodName: "SuggestHand",
+			Handler:    _Cribbage_SuggestHand_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchGame",
+			Handler:       _Cribbage_WatchGame_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "cribbage.proto",
+}
@@ -0,0 +1,22 @@
+package network
+
+import "github.com/joshprzybyszewski/cribbage/model"
+
+// PlayerEvent is a notification for a player who polls the server for them,
+// instead of having the server call them back
+type PlayerEvent struct {
+	// ID increases with every event for the player
+	ID       uint64        `json:"id"`
+	Event    string        `json:"event"`
+	GameID   model.GameID  `json:"gameID,omitempty"`
+	LobbyID  model.LobbyID `json:"lobbyID,omitempty"`
+	Blocker  string        `json:"blocker,omitempty"`
+	Messages []string      `json:"messages,omitempty"`
+	Chat     *ChatMessage  `json:"chat,omitempty"`
+}
+
+type GetPlayerEventsResponse struct {
+	Events []PlayerEvent `json:"events"`
+	// Next is the ID to poll after to hear about the events that come after these
+	Next uint64 `json:"next"`
+}
@@ -2,12 +2,111 @@ package network
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/joshprzybyszewski/cribbage/model"
 )
 
 type CreateGameRequest struct {
 	PlayerIDs []model.PlayerID `json:"playerIDs"`
+	Settings  *GameSettings    `json:"settings,omitempty"`
+}
+
+type GameSettings struct {
+	// MoveTimeout is a duration string, like "10m"
+	MoveTimeout string         `json:"move_timeout,omitempty"`
+	OnTimeout   string         `json:"on_timeout,omitempty"`
+	AutoPlayNPC model.PlayerID `json:"autoplay_npc,omitempty"`
+	// ProvablyFair has the server commit to every shuffle before the deal
+	ProvablyFair bool `json:"provably_fair,omitempty"`
+}
+
+func ConvertFromGameSettings(gs *GameSettings) (model.GameSettings, error) {
+	if gs == nil {
+		return model.GameSettings{}, nil
+	}
+
+	mgs := model.GameSettings{
+		ProvablyFair: gs.ProvablyFair,
+	}
+	if gs.MoveTimeout != `` {
+		d, err := time.ParseDuration(gs.MoveTimeout)
+		if err != nil {
+			return model.GameSettings{}, err
+		}
+		if d < 0 {
+			return model.GameSettings{}, errors.New(`move timeout cannot be negative`)
+		}
+		mgs.MoveTimeout = d
+	}
+
+	if gs.OnTimeout != `` {
+		mgs.OnTimeout = model.NewTimeoutActionFromString(gs.OnTimeout)
+		switch mgs.OnTimeout {
+		case model.NotifyOnTimeout, model.ForfeitOnTimeout:
+		case model.AutoPlayOnTimeout:
+			if gs.AutoPlayNPC == `` {
+				return model.GameSettings{}, errors.New(`autoplay requires an NPC`)
+			}
+			mgs.AutoPlayNPC = gs.AutoPlayNPC
+		default:
+			return model.GameSettings{}, fmt.Errorf(`unknown timeout action: %q`, gs.OnTimeout)
+		}
+	}
+
+	return mgs, nil
+}
+
+func convertToGameSettings(mgs model.GameSettings) *GameSettings {
+	if mgs == (model.GameSettings{}) {
+		return nil
+	}
+
+	gs := &GameSettings{
+		OnTimeout:    mgs.OnTimeout.String(),
+		AutoPlayNPC:  mgs.AutoPlayNPC,
+		ProvablyFair: mgs.ProvablyFair,
+	}
+	if mgs.MoveTimeout > 0 {
+		gs.MoveTimeout = mgs.MoveTimeout.String()
+	}
+	return gs
+}
+
+type GameOutcome struct {
+	// Reason is only set when the game ended without someone reaching the winning score
+	Reason   string         `json:"reason,omitempty"`
+	PlayerID model.PlayerID `json:"playerID,omitempty"`
+	Winners  []string       `json:"winners"`
+}
+
+func convertToGameOutcome(g model.Game) *GameOutcome {
+	if !g.IsOver() {
+		return nil
+	}
+
+	out := &GameOutcome{
+		PlayerID: g.Outcome.PlayerID,
+	}
+	if g.Outcome.Reason != model.NoOutcome {
+		out.Reason = g.Outcome.Reason.String()
+	}
+	for _, c := range g.Winners() {
+		out.Winners = append(out.Winners, convertToColor(c))
+	}
+	return out
+}
+
+func convertFromGameOutcome(out *GameOutcome) model.GameOutcome {
+	if out == nil || out.Reason == `` {
+		return model.GameOutcome{}
+	}
+
+	return model.GameOutcome{
+		Reason:   model.NewOutcomeReasonFromString(out.Reason),
+		PlayerID: out.PlayerID,
+	}
 }
 
 type CreateGameResponse struct {
@@ -46,6 +145,13 @@ type GetGameResponse struct {
 	Crib            []Card                    `json:"crib,omitempty"`
 	CutCard         Card                      `json:"cut_card"`
 	PeggedCards     []PeggedCard              `json:"pegged_cards,omitempty"`
+	Settings        *GameSettings             `json:"settings,omitempty"`
+	Outcome         *GameOutcome              `json:"outcome,omitempty"`
+	// Deck is the order of the last round's shuffled deck, top card first. It stays
+	// hidden until the game is over, when anyone can use it to check the deal and cut.
+	Deck []Card `json:"deck,omitempty"`
+	// Shuffle is the proof for this round's shuffle, if the game is provably fair
+	Shuffle *ShuffleProof `json:"shuffle,omitempty"`
 }
 
 func ConvertToGetGameResponse(g model.Game) GetGameResponse {
@@ -58,6 +164,13 @@ func ConvertToGetGameResponse(g model.Game) GetGameResponse {
 		CurrentPeg:      g.CurrentPeg(),
 		CutCard:         convertToCard(g.CutCard),
 		PeggedCards:     convertToPeggedCards(g.PeggedCards),
+		Settings:        convertToGameSettings(g.Settings),
+		Outcome:         convertToGameOutcome(g),
+		Shuffle:         convertToCurrentShuffle(g),
+	}
+
+	if g.IsOver() {
+		ggr.Deck = convertToCards(g.DeckOrder)
 	}
 
 	if g.Phase >= model.CribCounting {
@@ -83,14 +196,30 @@ func ConvertToGetGameResponseForPlayer(g model.Game, pID model.PlayerID) (GetGam
 		return GetGameResponse{}, errors.New(`player does not exist in game`)
 	}
 	resp := ConvertToGetGameResponse(g)
-	resp.Hands = convertToRevealedHands(g, pID)
+	resp.Hands = convertToRevealedHands(g, func(id model.PlayerID) bool {
+		return id == pID
+	})
 
 	return resp, nil
 }
 
+// ConvertToGetGameResponseForSpectator shows the spectator the hands which have been
+// revealed while counting. If the spectator is looking over a player's shoulder, they
+// can also see that player's hand.
+func ConvertToGetGameResponseForSpectator(g model.Game, shoulder model.PlayerID) GetGameResponse {
+	resp := ConvertToGetGameResponse(g)
+	resp.Hands = convertToRevealedHands(g, func(id model.PlayerID) bool {
+		return id == shoulder || g.IsHandRevealed(id)
+	})
+
+	return resp
+}
+
 func ConvertFromGetGameResponse(g GetGameResponse) model.Game {
 	currentScores, lagScores := convertFromScores(g.Teams)
 	ps, pcs := convertTeamsToPlayersAndPlayerColors(g.Teams)
+	// we ignore the error because the server only gives us valid settings
+	settings, _ := ConvertFromGameSettings(g.Settings)
 	return model.Game{
 		ID:              g.ID,
 		Players:         ps,
@@ -102,24 +231,36 @@ func ConvertFromGetGameResponse(g GetGameResponse) model.Game {
 		CurrentDealer:   g.CurrentDealer,
 		CutCard:         convertFromCard(g.CutCard),
 		Crib:            convertFromCards(g.Crib),
+		DeckOrder:       convertFromCards(g.Deck),
+		Shuffles:        convertFromShuffleProof(g.Shuffle),
 		Hands:           convertFomRevealedHands(g.Hands),
 		PeggedCards:     convertFromPeggedCards(g.PeggedCards),
+		Pegging: model.PeggingState{
+			Count: g.CurrentPeg,
+		},
+		Settings: settings,
+		Outcome:  convertFromGameOutcome(g.Outcome),
 	}
 }
 
-func convertToRevealedHands(g model.Game, me model.PlayerID) map[model.PlayerID][]Card {
+// convertToRevealedHands shows the full hand of every player that canSee allows.
+// Everyone else only shows the cards they have pegged.
+func convertToRevealedHands(g model.Game, canSee func(model.PlayerID) bool) map[model.PlayerID][]Card {
 	rev := make(map[model.PlayerID][]Card, len(g.Players))
 	for pID, h := range g.Hands {
+		if canSee(pID) {
+			rev[pID] = convertToCards(h)
+			continue
+		}
 		// we don't know how many cards will be revealed, but we know how may are in their hand
 		rev[pID] = make([]Card, 0, len(h))
 	}
 	for _, c := range g.PeggedCards {
-		if c.PlayerID == me {
+		if canSee(c.PlayerID) {
 			continue
 		}
 		rev[c.PlayerID] = append(rev[c.PlayerID], convertToCard(c.Card))
 	}
-	rev[me] = convertToCards(g.Hands[me])
 
 	for pID := range rev {
 		for len(rev[pID]) < len(g.Hands[pID]) {
@@ -2,8 +2,10 @@ package network
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/joshprzybyszewski/cribbage/model"
 )
@@ -223,6 +225,203 @@ func TestConvertToGetGameResponse(t *testing.T) {
 	}
 }
 
+func TestConvertFromGameSettings(t *testing.T) {
+	tests := []struct {
+		desc        string
+		settings    *GameSettings
+		expSettings model.GameSettings
+		expErr      bool
+	}{{
+		desc:        `no settings`,
+		settings:    nil,
+		expSettings: model.GameSettings{},
+	}, {
+		desc: `forfeit after ten minutes`,
+		settings: &GameSettings{
+			MoveTimeout: `10m0s`,
+			OnTimeout:   `forfeit`,
+		},
+		expSettings: model.GameSettings{
+			MoveTimeout: 10 * time.Minute,
+			OnTimeout:   model.ForfeitOnTimeout,
+		},
+	}, {
+		desc: `autoplay`,
+		settings: &GameSettings{
+			MoveTimeout: `1h0m0s`,
+			OnTimeout:   `autoplay`,
+			AutoPlayNPC: `CalculatedNPC`,
+		},
+		expSettings: model.GameSettings{
+			MoveTimeout: time.Hour,
+			OnTimeout:   model.AutoPlayOnTimeout,
+			AutoPlayNPC: `CalculatedNPC`,
+		},
+	}, {
+		desc: `provably fair`,
+		settings: &GameSettings{
+			OnTimeout:    `notify`,
+			ProvablyFair: true,
+		},
+		expSettings: model.GameSettings{
+			ProvablyFair: true,
+		},
+	}, {
+		desc: `autoplay needs an NPC`,
+		settings: &GameSettings{
+			MoveTimeout: `1h`,
+			OnTimeout:   `autoplay`,
+		},
+		expErr: true,
+	}, {
+		desc: `bad duration`,
+		settings: &GameSettings{
+			MoveTimeout: `soon`,
+		},
+		expErr: true,
+	}, {
+		desc: `negative duration`,
+		settings: &GameSettings{
+			MoveTimeout: `-1m`,
+		},
+		expErr: true,
+	}, {
+		desc: `unknown timeout action`,
+		settings: &GameSettings{
+			MoveTimeout: `1m`,
+			OnTimeout:   `explode`,
+		},
+		expErr: true,
+	}}
+	for _, tc := range tests {
+		mgs, err := ConvertFromGameSettings(tc.settings)
+		if tc.expErr {
+			assert.Error(t, err, tc.desc)
+			continue
+		}
+		assert.NoError(t, err, tc.desc)
+		assert.Equal(t, tc.expSettings, mgs, tc.desc)
+		if tc.settings != nil {
+			assert.Equal(t, tc.settings, convertToGameSettings(mgs), tc.desc)
+		}
+	}
+}
+
+func TestConvertToGetGameResponseOutcome(t *testing.T) {
+	aliceID := model.PlayerID(`alice`)
+	bobID := model.PlayerID(`bob`)
+
+	tests := []struct {
+		desc       string
+		scores     map[model.PlayerColor]int
+		outcome    model.GameOutcome
+		expOutcome *GameOutcome
+	}{{
+		desc: `game is still going`,
+		scores: map[model.PlayerColor]int{
+			model.Blue: 11,
+			model.Red:  22,
+		},
+		expOutcome: nil,
+	}, {
+		desc: `someone won`,
+		scores: map[model.PlayerColor]int{
+			model.Blue: 121,
+			model.Red:  22,
+		},
+		expOutcome: &GameOutcome{
+			Winners: []string{`blue`},
+		},
+	}, {
+		desc: `someone timed out`,
+		scores: map[model.PlayerColor]int{
+			model.Blue: 11,
+			model.Red:  22,
+		},
+		outcome: model.GameOutcome{
+			Reason:   model.TimedOut,
+			PlayerID: bobID,
+		},
+		expOutcome: &GameOutcome{
+			Reason:   `timedOut`,
+			PlayerID: bobID,
+			Winners:  []string{`blue`},
+		},
+	}}
+	for _, tc := range tests {
+		g := model.Game{
+			PlayerColors: map[model.PlayerID]model.PlayerColor{
+				aliceID: model.Blue,
+				bobID:   model.Red,
+			},
+			CurrentScores: tc.scores,
+			Outcome:       tc.outcome,
+		}
+		resp := ConvertToGetGameResponse(g)
+		assert.Equal(t, tc.expOutcome, resp.Outcome, tc.desc)
+		assert.Equal(t, tc.outcome, ConvertFromGetGameResponse(resp).Outcome, tc.desc)
+	}
+}
+
+func TestConvertToGetGameResponseDeck(t *testing.T) {
+	g := model.Game{
+		PlayerColors: map[model.PlayerID]model.PlayerColor{
+			model.PlayerID(`alice`): model.Blue,
+			model.PlayerID(`bob`):   model.Red,
+		},
+		CurrentScores: map[model.PlayerColor]int{
+			model.Blue: 118,
+			model.Red:  22,
+		},
+		DeckOrder: model.NewShuffledDeckOrder(1),
+	}
+	phases := []model.Phase{model.Deal, model.BuildCrib, model.Cut, model.Pegging, model.Counting, model.CribCounting}
+	for _, p := range phases {
+		g.Phase = p
+		assert.Empty(t, ConvertToGetGameResponse(g).Deck, `nobody sees the deck during the game: %s`, p)
+	}
+
+	// once the game is over
+	g.Phase = model.Pegging
+	g.CurrentScores[model.Blue] = 121
+	resp := ConvertToGetGameResponse(g)
+	require.Len(t, resp.Deck, model.NumCardsPerDeck)
+	assert.Equal(t, g.DeckOrder, ConvertFromGetGameResponse(resp).DeckOrder)
+}
+
+func TestConvertToGetGameResponseShuffle(t *testing.T) {
+	g := model.Game{
+		ID: model.GameID(42),
+		PlayerColors: map[model.PlayerID]model.PlayerColor{
+			model.PlayerID(`alice`): model.Blue,
+			model.PlayerID(`bob`):   model.Red,
+		},
+		CurrentScores: map[model.PlayerColor]int{
+			model.Blue: 18,
+			model.Red:  22,
+		},
+		Phase:    model.Pegging,
+		Shuffles: []model.ShuffleProof{model.NewShuffleProof(), model.NewShuffleProof()},
+	}
+	assert.Nil(t, ConvertToGetGameResponse(model.Game{}).Shuffle)
+
+	resp := ConvertToGetGameResponse(g)
+	require.NotNil(t, resp.Shuffle)
+	assert.Equal(t, g.Shuffles[1].Commitment, resp.Shuffle.Commitment)
+	assert.Empty(t, resp.Shuffle.ServerSeed, `the seed stays secret until the hand is over`)
+
+	shuffles := ConvertToGetShufflesResponse(g)
+	assert.Equal(t, g.ID, shuffles.GameID)
+	require.Len(t, shuffles.Shuffles, 2)
+	assert.Equal(t, g.Shuffles[0].ServerSeed, shuffles.Shuffles[0].ServerSeed)
+	assert.Empty(t, shuffles.Shuffles[1].ServerSeed)
+
+	g.Phase = model.Counting
+	resp = ConvertToGetGameResponse(g)
+	assert.Equal(t, g.Shuffles[1].ServerSeed, resp.Shuffle.ServerSeed)
+	assert.Equal(t, g.Shuffles[1:], ConvertFromGetGameResponse(resp).Shuffles)
+}
+
 func TestConvertToGetGameResponseForPlayer(t *testing.T) {
 	aliceID := model.PlayerID(`alice`)
 	bobID := model.PlayerID(`bob`)
@@ -286,6 +485,9 @@ func TestConvertToGetGameResponseForPlayer(t *testing.T) {
 				Action:   1,
 				PlayerID: bobID,
 			}},
+			Pegging: model.PeggingState{
+				Count: 2,
+			},
 		},
 		expResp: GetGameResponse{
 			ID: model.GameID(123456),
@@ -378,6 +580,9 @@ func TestConvertToGetGameResponseForPlayer(t *testing.T) {
 				Action:   1,
 				PlayerID: bobID,
 			}},
+			Pegging: model.PeggingState{
+				Count: 2,
+			},
 		},
 		expResp: GetGameResponse{
 			ID: model.GameID(123456),
@@ -760,3 +965,80 @@ func TestConvertToGetGameResponseForPlayer(t *testing.T) {
 		assert.Equal(t, expGame, mg2, tc.desc)
 	}
 }
+
+func TestConvertToGetGameResponseForSpectator(t *testing.T) {
+	aliceID := model.PlayerID(`alice`)
+	bobID := model.PlayerID(`bob`)
+
+	g := model.Game{
+		ID: model.GameID(123456),
+		Players: []model.Player{{
+			ID:   aliceID,
+			Name: `alice`,
+		}, {
+			ID:   bobID,
+			Name: `bob`,
+		}},
+		PlayerColors: map[model.PlayerID]model.PlayerColor{
+			aliceID: model.Blue,
+			bobID:   model.Red,
+		},
+		Phase: model.Pegging,
+		BlockingPlayers: map[model.PlayerID]model.Blocker{
+			aliceID: model.PegCard,
+		},
+		CurrentDealer: bobID,
+		Hands: map[model.PlayerID][]model.Card{
+			aliceID: ModelCardsFromStrings(`ah`, `2h`, `3h`, `4h`),
+			bobID:   ModelCardsFromStrings(`as`, `2s`, `3s`, `4s`),
+		},
+		Crib:    ModelCardsFromStrings(`5h`, `6h`, `5s`, `6s`),
+		CutCard: model.NewCardFromString(`5c`),
+		PeggedCards: []model.PeggedCard{{
+			Card:     model.NewCardFromString(`ah`),
+			Action:   0,
+			PlayerID: aliceID,
+		}, {
+			Card:     model.NewCardFromString(`as`),
+			Action:   1,
+			PlayerID: bobID,
+		}},
+	}
+
+	tests := []struct {
+		desc     string
+		shoulder model.PlayerID
+		expHands map[model.PlayerID][]Card
+	}{{
+		desc: `only sees the pegged cards`,
+		expHands: map[model.PlayerID][]Card{
+			aliceID: cardsFromStrings(`AH`, ``, ``, ``),
+			bobID:   cardsFromStrings(`AS`, ``, ``, ``),
+		},
+	}, {
+		desc:     `looking over alice's shoulder`,
+		shoulder: aliceID,
+		expHands: map[model.PlayerID][]Card{
+			aliceID: cardsFromStrings(`AH`, `2H`, `3H`, `4H`),
+			bobID:   cardsFromStrings(`AS`, ``, ``, ``),
+		},
+	}}
+	for _, tc := range tests {
+		resp := ConvertToGetGameResponseForSpectator(g, tc.shoulder)
+		assert.Equal(t, tc.expHands, resp.Hands, tc.desc)
+		assert.Equal(t, cardsFromStrings(``, ``, ``, ``), resp.Crib, tc.desc)
+		assert.Equal(t, `5C`, resp.CutCard.Name, tc.desc)
+		assert.Len(t, resp.PeggedCards, 2, tc.desc)
+	}
+
+	g.Phase = model.CribCounting
+	g.BlockingPlayers = map[model.PlayerID]model.Blocker{
+		bobID: model.CountCrib,
+	}
+	resp := ConvertToGetGameResponseForSpectator(g, model.InvalidPlayerID)
+	assert.Equal(t, map[model.PlayerID][]Card{
+		aliceID: cardsFromStrings(`AH`, `2H`, `3H`, `4H`),
+		bobID:   cardsFromStrings(`AS`, `2S`, `3S`, `4S`),
+	}, resp.Hands)
+	assert.Equal(t, cardsFromStrings(`5H`, `6H`, `5S`, `6S`), resp.Crib)
+}
@@ -0,0 +1,108 @@
+package network
+
+import (
+	"errors"
+	"time"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+)
+
+type CreateLobbyRequest struct {
+	Host       model.PlayerID `json:"host"`
+	NumPlayers int            `json:"num_players"`
+	// NPCFill is the type of NPC which fills the empty seats after FillAfter
+	NPCFill model.PlayerID `json:"npc_fill,omitempty"`
+	// FillAfter is a duration string, like "2m"
+	FillAfter string        `json:"fill_after,omitempty"`
+	Settings  *GameSettings `json:"settings,omitempty"`
+}
+
+type CreateInvitationRequest struct {
+	Host     model.PlayerID   `json:"host"`
+	Invitees []model.PlayerID `json:"invitees"`
+	Settings *GameSettings    `json:"settings,omitempty"`
+}
+
+type MatchLobbyRequest struct {
+	PlayerID   model.PlayerID `json:"playerID"`
+	NumPlayers int            `json:"num_players"`
+	NPCFill    model.PlayerID `json:"npc_fill,omitempty"`
+	FillAfter  string         `json:"fill_after,omitempty"`
+}
+
+type LobbyPlayerRequest struct {
+	PlayerID model.PlayerID `json:"playerID"`
+}
+
+// ConvertFromFillAfter returns how long a lobby should wait to fill its seats with NPCs
+func ConvertFromFillAfter(npcFill model.PlayerID, fillAfter string) (time.Duration, error) {
+	if fillAfter == `` {
+		return 0, nil
+	}
+	if npcFill == model.InvalidPlayerID {
+		return 0, errors.New(`fill_after requires npc_fill`)
+	}
+
+	d, err := time.ParseDuration(fillAfter)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, errors.New(`fill_after cannot be negative`)
+	}
+	return d, nil
+}
+
+type Lobby struct {
+	ID         model.LobbyID    `json:"id"`
+	Host       model.PlayerID   `json:"host"`
+	NumPlayers int              `json:"num_players"`
+	Seated     []model.PlayerID `json:"seated"`
+	Status     string           `json:"status"`
+	Invited    []model.PlayerID `json:"invited,omitempty"`
+	Pending    []model.PlayerID `json:"pending,omitempty"`
+	NPCFill    model.PlayerID   `json:"npc_fill,omitempty"`
+	FillAt     string           `json:"fill_at,omitempty"`
+	Settings   *GameSettings    `json:"settings,omitempty"`
+	Created    string           `json:"created"`
+	GameID     model.GameID     `json:"gameID,omitempty"`
+}
+
+func ConvertToLobby(l model.Lobby) Lobby {
+	resp := Lobby{
+		ID:         l.ID,
+		Host:       l.Host,
+		NumPlayers: l.NumPlayers,
+		Seated:     l.Seated,
+		Status:     l.Status.String(),
+		Invited:    l.Invited,
+		NPCFill:    l.NPCFill,
+		Settings:   convertToGameSettings(l.Settings),
+		Created:    l.Created.Format(time.RFC3339),
+		GameID:     l.GameID,
+	}
+	if resp.Seated == nil {
+		resp.Seated = []model.PlayerID{}
+	}
+	if l.IsPrivate() && l.Status == model.LobbyOpen {
+		resp.Pending = l.Pending()
+	}
+	if l.NPCFill != model.InvalidPlayerID {
+		resp.FillAt = l.FillAt.Format(time.RFC3339)
+	}
+	return resp
+}
+
+type GetOpenLobbiesResponse struct {
+	Lobbies []Lobby `json:"lobbies"`
+}
+
+func ConvertToGetOpenLobbiesResponse(ls []model.Lobby) GetOpenLobbiesResponse {
+	resp := GetOpenLobbiesResponse{
+		Lobbies: make([]Lobby, len(ls)),
+	}
+	for i, l := range ls {
+		resp.Lobbies[i] = ConvertToLobby(l)
+	}
+	return resp
+}
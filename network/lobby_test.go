@@ -0,0 +1,134 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+)
+
+func TestConvertToLobby(t *testing.T) {
+	created := time.Date(2020, time.July, 4, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		desc    string
+		lobby   model.Lobby
+		expResp Lobby
+	}{{
+		desc: `empty lobby`,
+		lobby: model.Lobby{
+			ID:         model.LobbyID(42),
+			Host:       `alice`,
+			NumPlayers: 2,
+			Status:     model.LobbyClosed,
+			Created:    created,
+		},
+		expResp: Lobby{
+			ID:         model.LobbyID(42),
+			Host:       `alice`,
+			NumPlayers: 2,
+			Seated:     []model.PlayerID{},
+			Status:     `closed`,
+			Created:    `2020-07-04T12:00:00Z`,
+		},
+	}, {
+		desc: `filled with NPCs`,
+		lobby: model.Lobby{
+			ID:         model.LobbyID(42),
+			Host:       `alice`,
+			NumPlayers: 3,
+			Seated:     []model.PlayerID{`alice`, `bob`, `CalculatedNPC`},
+			Status:     model.LobbyStarted,
+			NPCFill:    `CalculatedNPC`,
+			FillAt:     created.Add(time.Minute),
+			Settings: model.GameSettings{
+				MoveTimeout: time.Hour,
+				OnTimeout:   model.NotifyOnTimeout,
+			},
+			Created: created,
+			GameID:  model.GameID(1234),
+		},
+		expResp: Lobby{
+			ID:         model.LobbyID(42),
+			Host:       `alice`,
+			NumPlayers: 3,
+			Seated:     []model.PlayerID{`alice`, `bob`, `CalculatedNPC`},
+			Status:     `started`,
+			NPCFill:    `CalculatedNPC`,
+			FillAt:     `2020-07-04T12:01:00Z`,
+			Settings: &GameSettings{
+				MoveTimeout: `1h0m0s`,
+				OnTimeout:   `notify`,
+			},
+			Created: `2020-07-04T12:00:00Z`,
+			GameID:  model.GameID(1234),
+		},
+	}, {
+		desc: `pending invitation`,
+		lobby: model.Lobby{
+			ID:         model.LobbyID(42),
+			Host:       `alice`,
+			NumPlayers: 3,
+			Seated:     []model.PlayerID{`alice`, `charlie`},
+			Invited:    []model.PlayerID{`bob`, `charlie`},
+			Status:     model.LobbyOpen,
+			Created:    created,
+		},
+		expResp: Lobby{
+			ID:         model.LobbyID(42),
+			Host:       `alice`,
+			NumPlayers: 3,
+			Seated:     []model.PlayerID{`alice`, `charlie`},
+			Status:     `open`,
+			Invited:    []model.PlayerID{`bob`, `charlie`},
+			Pending:    []model.PlayerID{`bob`},
+			Created:    `2020-07-04T12:00:00Z`,
+		},
+	}}
+	for _, tc := range tests {
+		resp := ConvertToLobby(tc.lobby)
+		assert.Equal(t, tc.expResp, resp, tc.desc)
+	}
+}
+
+func TestConvertFromFillAfter(t *testing.T) {
+	tests := []struct {
+		desc      string
+		npcFill   model.PlayerID
+		fillAfter string
+		exp       time.Duration
+		expErr    bool
+	}{{
+		desc: `never fill`,
+	}, {
+		desc:      `fill after a minute`,
+		npcFill:   `SimpleNPC`,
+		fillAfter: `1m`,
+		exp:       time.Minute,
+	}, {
+		desc:      `needs an npc`,
+		fillAfter: `1m`,
+		expErr:    true,
+	}, {
+		desc:      `bad duration`,
+		npcFill:   `SimpleNPC`,
+		fillAfter: `later`,
+		expErr:    true,
+	}, {
+		desc:      `negative duration`,
+		npcFill:   `SimpleNPC`,
+		fillAfter: `-1s`,
+		expErr:    true,
+	}}
+	for _, tc := range tests {
+		d, err := ConvertFromFillAfter(tc.npcFill, tc.fillAfter)
+		if tc.expErr {
+			assert.Error(t, err, tc.desc)
+			continue
+		}
+		assert.NoError(t, err, tc.desc)
+		assert.Equal(t, tc.exp, d, tc.desc)
+	}
+}
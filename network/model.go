@@ -6,4 +6,14 @@ type CreateInteractionRequest struct {
 	PlayerID      model.PlayerID `json:"playerID"`
 	LocalhostPort string         `json:"localhost_port,omitempty"`
 	NPCType       model.PlayerID `json:"npc_type,omitempty"`
+	// WebhookURL is where the server POSTs the player's notifications. Every
+	// request is signed with WebhookSecret so that the receiver can trust it.
+	WebhookURL    string `json:"webhook_url,omitempty"`
+	WebhookSecret string `json:"webhook_secret,omitempty"`
+	// Polling keeps the player's notifications on the server until they
+	// ask for them at /player/:username/events
+	Polling bool `json:"polling,omitempty"`
+	// AutoPlay asks the server to submit the player's forced moves for them, such
+	// as saying go when they have no playable card, or counting their hand.
+	AutoPlay bool `json:"auto_play,omitempty"`
 }
@@ -0,0 +1,64 @@
+package network
+
+import "github.com/joshprzybyszewski/cribbage/model"
+
+// ShuffleProof is what a provably fair game publishes about a round's shuffle.
+// The server seed is only set once the hand is over.
+type ShuffleProof struct {
+	Commitment    string  `json:"commitment"`
+	ServerSeed    string  `json:"server_seed,omitempty"`
+	ClientEntropy string  `json:"client_entropy,omitempty"`
+	CutPercentage float64 `json:"cut_percentage,omitempty"`
+}
+
+type GetShufflesResponse struct {
+	GameID   model.GameID   `json:"gameID"`
+	Shuffles []ShuffleProof `json:"shuffles"`
+}
+
+// ConvertToGetShufflesResponse lists the proofs for every round of the game, so that
+// they can be checked against the deals
+func ConvertToGetShufflesResponse(g model.Game) GetShufflesResponse {
+	sps := g.RevealedShuffles()
+	resp := GetShufflesResponse{
+		GameID:   g.ID,
+		Shuffles: make([]ShuffleProof, len(sps)),
+	}
+	for i, sp := range sps {
+		resp.Shuffles[i] = convertToShuffleProof(sp)
+	}
+	return resp
+}
+
+// convertToCurrentShuffle returns the proof for the round being played, if the game is provably fair
+func convertToCurrentShuffle(g model.Game) *ShuffleProof {
+	sps := g.RevealedShuffles()
+	if len(sps) == 0 {
+		return nil
+	}
+
+	sp := convertToShuffleProof(sps[len(sps)-1])
+	return &sp
+}
+
+func convertToShuffleProof(sp model.ShuffleProof) ShuffleProof {
+	return ShuffleProof{
+		Commitment:    sp.Commitment,
+		ServerSeed:    sp.ServerSeed,
+		ClientEntropy: sp.ClientEntropy,
+		CutPercentage: sp.CutPercentage,
+	}
+}
+
+func convertFromShuffleProof(sp *ShuffleProof) []model.ShuffleProof {
+	if sp == nil {
+		return nil
+	}
+
+	return []model.ShuffleProof{{
+		Commitment:    sp.Commitment,
+		ServerSeed:    sp.ServerSeed,
+		ClientEntropy: sp.ClientEntropy,
+		CutPercentage: sp.CutPercentage,
+	}}
+}
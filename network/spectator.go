@@ -0,0 +1,39 @@
+package network
+
+import "github.com/joshprzybyszewski/cribbage/model"
+
+type SpectateRequest struct {
+	PlayerID model.PlayerID `json:"playerID"`
+}
+
+// ShoulderRequest is sent by a player in the game to let a spectator
+// see their hand, or to stop them from seeing it
+type ShoulderRequest struct {
+	PlayerID    model.PlayerID `json:"playerID"`
+	SpectatorID model.PlayerID `json:"spectatorID"`
+	Allow       bool           `json:"allow"`
+}
+
+type Spectator struct {
+	PlayerID model.PlayerID `json:"playerID"`
+	Shoulder model.PlayerID `json:"shoulder,omitempty"`
+}
+
+type GetSpectatorsResponse struct {
+	GameID     model.GameID `json:"gameID"`
+	Spectators []Spectator  `json:"spectators"`
+}
+
+func ConvertToGetSpectatorsResponse(gID model.GameID, ss []model.Spectator) GetSpectatorsResponse {
+	resp := GetSpectatorsResponse{
+		GameID:     gID,
+		Spectators: make([]Spectator, len(ss)),
+	}
+	for i, s := range ss {
+		resp.Spectators[i] = Spectator{
+			PlayerID: s.PlayerID,
+			Shoulder: s.Shoulder,
+		}
+	}
+	return resp
+}
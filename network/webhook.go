@@ -0,0 +1,86 @@
+package network
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+)
+
+const (
+	// WebhookSignatureHeader has the HMAC-SHA256 of the request body, signed with the shared secret
+	WebhookSignatureHeader = `X-Cribbage-Signature`
+	// WebhookEventHeader says what kind of payload is in the request body
+	WebhookEventHeader = `X-Cribbage-Event`
+
+	webhookSignaturePrefix = `sha256=`
+	minWebhookSecretLength = 16
+)
+
+var (
+	ErrInvalidWebhookURL    = errors.New(`webhook url must be an absolute http(s) url`)
+	ErrInvalidWebhookSecret = errors.New(`webhook secret must be at least 16 characters`)
+)
+
+// ValidateWebhook returns an error if the server cannot send webhooks to the url
+// with the secret
+func ValidateWebhook(webhookURL, secret string) error {
+	u, err := url.Parse(webhookURL)
+	if err != nil || u.Host == `` {
+		return ErrInvalidWebhookURL
+	}
+	switch u.Scheme {
+	case `http`, `https`:
+	default:
+		return ErrInvalidWebhookURL
+	}
+
+	if len(secret) < minWebhookSecretLength {
+		return ErrInvalidWebhookSecret
+	}
+	return nil
+}
+
+// WebhookEvent is the kind of notification that the webhook is receiving
+type WebhookEvent string
+
+const (
+	BlockingEvent    WebhookEvent = `blocking`
+	MessageEvent     WebhookEvent = `message`
+	ScoreUpdateEvent WebhookEvent = `score`
+	InvitationEvent  WebhookEvent = `invitation`
+	ChatEvent        WebhookEvent = `chat`
+)
+
+// WebhookPayload is the JSON body that the server POSTs to a webhook
+type WebhookPayload struct {
+	Event    WebhookEvent   `json:"event"`
+	PlayerID model.PlayerID `json:"playerID"`
+	Blocker  string         `json:"blocker,omitempty"`
+	Messages []string       `json:"messages,omitempty"`
+	LobbyID  model.LobbyID  `json:"lobbyID,omitempty"`
+	Chat     *ChatMessage   `json:"chat,omitempty"`
+
+	// Game is what the player is allowed to see of the game
+	Game *GetGameResponse `json:"game,omitempty"`
+}
+
+// SignWebhookPayload returns the value of the WebhookSignatureHeader for the body
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	return webhookSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature returns true if the signature is for the body with the secret.
+// Webhook receivers should check this before they trust the payload.
+func VerifyWebhookSignature(secret string, body []byte, signature string) bool {
+	if !strings.HasPrefix(signature, webhookSignaturePrefix) {
+		return false
+	}
+	return hmac.Equal([]byte(SignWebhookPayload(secret, body)), []byte(signature))
+}
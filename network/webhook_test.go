@@ -0,0 +1,18 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	secret := `this is a test secret`
+	body := []byte(`{"event":"message"}`)
+	sig := SignWebhookPayload(secret, body)
+
+	assert.True(t, VerifyWebhookSignature(secret, body, sig))
+	assert.False(t, VerifyWebhookSignature(`some other secret!!`, body, sig))
+	assert.False(t, VerifyWebhookSignature(secret, []byte(`{"event":"chat"}`), sig))
+	assert.False(t, VerifyWebhookSignature(secret, body, sig[len(webhookSignaturePrefix):]))
+}
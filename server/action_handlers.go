@@ -6,6 +6,8 @@ import (
 
 	"github.com/joshprzybyszewski/cribbage/model"
 	"github.com/joshprzybyszewski/cribbage/server/interaction"
+	"github.com/joshprzybyszewski/cribbage/server/npcqueue"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
 )
 
 var _ interaction.ActionHandler = (*npcActionHandler)(nil)
@@ -20,3 +22,21 @@ func (ah *npcActionHandler) Handle(pa model.PlayerAction) error {
 
 	return HandleAction(context.Background(), pa)
 }
+
+func (ah *npcActionHandler) Chat(cm model.ChatMessage) error {
+	return SendChat(context.Background(), cm)
+}
+
+var _ interaction.ActionQueue = (*npcActionQueue)(nil)
+
+// npcActionQueue holds the NPCs' actions for the transaction on its db
+type npcActionQueue struct {
+	npcActionHandler
+
+	db persistence.DB
+}
+
+func (aq *npcActionQueue) Enqueue(pa model.PlayerAction, numActions int) error {
+	npcMoves.hold(aq.db, npcqueue.NewJob(pa, numActions))
+	return nil
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/joshprzybyszewski/cribbage/server/logging"
+	"github.com/joshprzybyszewski/cribbage/server/metrics"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+// activeGamesCounter is a background job which keeps the metric of active games
+// up to date. The games are counted in the database, so the count includes the
+// games from before a restart (or on another process).
+type activeGamesCounter struct {
+	dbFactory persistence.DBFactory
+	period    time.Duration
+}
+
+func newActiveGamesCounter(
+	dbFactory persistence.DBFactory,
+	period time.Duration,
+) *activeGamesCounter {
+	return &activeGamesCounter{
+		dbFactory: dbFactory,
+		period:    period,
+	}
+}
+
+// run counts the active games right away, and then periodically, until the
+// context is done.
+func (agc *activeGamesCounter) run(ctx context.Context) {
+	t := time.NewTicker(agc.period)
+	defer t.Stop()
+
+	for {
+		err := agc.count(ctx)
+		if err != nil {
+			logging.L().Error(`Could not count the active games`, zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (agc *activeGamesCounter) count(ctx context.Context) error {
+	db, err := agc.dbFactory.New(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	n, err := db.CountActiveGames(ctx)
+	if err != nil {
+		return err
+	}
+
+	metrics.SetActiveGames(n)
+	return nil
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/metrics"
+)
+
+func scrapeMetrics(t *testing.T) string {
+	req, err := http.NewRequest(`GET`, `/metrics`, nil)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(w, req)
+
+	bs, err := ioutil.ReadAll(w.Body)
+	require.NoError(t, err)
+	return string(bs)
+}
+
+func TestActiveGamesCounterCountsTheGamesInTheDB(t *testing.T) {
+	cs, _ := newServerAndRouter(t)
+	pIDs := seedPlayers(t, cs.dbFactory, 2)
+
+	ctx := context.Background()
+	db, err := cs.dbFactory.New(ctx)
+	require.NoError(t, err)
+	defer db.Close()
+
+	// these games were started before the counter, as if by another process
+	_, err = createGame(ctx, db, pIDs, model.GameSettings{})
+	require.NoError(t, err)
+	g, err := createGame(ctx, db, pIDs, model.GameSettings{})
+	require.NoError(t, err)
+
+	agc := newActiveGamesCounter(cs.dbFactory, time.Hour)
+	require.NoError(t, agc.count(ctx))
+	assert.Contains(t, scrapeMetrics(t), "cribbage_active_games 2\n")
+
+	require.NoError(t, handleAction(ctx, db, model.PlayerAction{
+		GameID:    g.ID,
+		ID:        pIDs[1],
+		Overcomes: model.Forfeit,
+		Action:    model.ForfeitAction{},
+	}))
+	require.NoError(t, agc.count(ctx))
+	assert.Contains(t, scrapeMetrics(t), "cribbage_active_games 1\n")
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+const (
+	// a player can send chatRateLimit messages in every chatRatePeriod
+	chatRateLimit  = 5
+	chatRatePeriod = 10 * time.Second
+)
+
+var (
+	errChatRateLimited = errors.New(`too many chat messages, slow down`)
+)
+
+// sendChat saves the message in the game's history and sends it to everyone else in the game
+func sendChat(ctx context.Context, db persistence.DB, cm model.ChatMessage) (err error) {
+	err = db.Start(ctx)
+	if err != nil {
+		return err
+	}
+	defer commitOrRollback(ctx, db, &err)
+
+	err = cm.Validate()
+	if err != nil {
+		return err
+	}
+
+	g, err := db.GetGame(ctx, cm.GameID)
+	if err != nil {
+		return err
+	}
+
+	if !isInGame(g, cm.PlayerID) {
+		err = errPlayerNotInGame
+		return err
+	}
+
+	history, err := db.GetChatMessages(ctx, cm.GameID)
+	if err != nil {
+		return err
+	}
+	if isChatRateLimited(history, cm) {
+		err = errChatRateLimited
+		return err
+	}
+
+	err = db.AddChatMessage(ctx, cm)
+	if err != nil {
+		return err
+	}
+
+	pAPIs, err := getPlayerAPIs(ctx, db, g.Players)
+	if err != nil {
+		return err
+	}
+	for _, p := range g.Players {
+		if p.ID == cm.PlayerID {
+			continue
+		}
+		_ = pAPIs[p.ID].NotifyChat(g, cm)
+	}
+
+	return nil
+}
+
+// isChatRateLimited returns true if the sender has already sent
+// too many messages recently
+func isChatRateLimited(history []model.ChatMessage, cm model.ChatMessage) bool {
+	since := cm.Sent.Add(-chatRatePeriod)
+	recent := 0
+	for _, prev := range history {
+		if prev.PlayerID == cm.PlayerID && prev.Sent.After(since) {
+			recent++
+		}
+	}
+	return recent >= chatRateLimit
+}
+
+func getChatHistory(ctx context.Context, db persistence.DB, gID model.GameID) ([]model.ChatMessage, error) {
+	_, err := db.GetGame(ctx, gID)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.GetChatMessages(ctx, gID)
+}
@@ -0,0 +1,94 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/network"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+// POST /game/:gameID/chat
+func (cs *cribbageServer) ginPostChat(c *gin.Context) {
+	gID, err := getGameIDFromContext(c)
+	if err != nil {
+		c.String(http.StatusBadRequest, `Invalid GameID: %v`, err)
+		return
+	}
+
+	var scr network.SendChatRequest
+	err = c.ShouldBindJSON(&scr)
+	if err != nil {
+		c.String(http.StatusBadRequest, `Error: %s`, err)
+		return
+	}
+	if scr.PlayerID == model.InvalidPlayerID {
+		c.String(http.StatusBadRequest, `Requires playerID`)
+		return
+	}
+
+	ctx := c.Request.Context()
+	db, err := cs.dbFactory.New(ctx)
+	if err != nil {
+		c.String(http.StatusInternalServerError, `dbFactory.New() error: %s`, err)
+		return
+	}
+	defer db.Close()
+
+	cm := model.ChatMessage{
+		GameID:   gID,
+		PlayerID: scr.PlayerID,
+		Message:  scr.Message,
+		Sent:     time.Now(),
+	}
+	err = sendChat(ctx, db, cm)
+	if err != nil {
+		writeChatError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, network.ConvertToChatMessage(cm))
+}
+
+// GET /game/:gameID/chat
+func (cs *cribbageServer) ginGetChat(c *gin.Context) {
+	gID, err := getGameIDFromContext(c)
+	if err != nil {
+		c.String(http.StatusBadRequest, `Invalid GameID: %v`, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	db, err := cs.dbFactory.New(ctx)
+	if err != nil {
+		c.String(http.StatusInternalServerError, `dbFactory.New() error: %s`, err)
+		return
+	}
+	defer db.Close()
+
+	msgs, err := getChatHistory(ctx, db, gID)
+	if err != nil {
+		writeChatError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, network.ConvertToGetChatResponse(gID, msgs))
+}
+
+func writeChatError(c *gin.Context, err error) {
+	switch err {
+	case persistence.ErrGameNotFound:
+		c.String(http.StatusNotFound, `Game not found`)
+	case model.ErrEmptyChatMessage,
+		model.ErrChatMessageTooLong,
+		errPlayerNotInGame:
+		c.String(http.StatusBadRequest, `Error: %s`, err)
+	case errChatRateLimited:
+		c.String(http.StatusTooManyRequests, `Error: %s`, err)
+	default:
+		c.String(http.StatusInternalServerError, `Error: %s`, err)
+	}
+}
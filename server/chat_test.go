@@ -0,0 +1,158 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/network"
+)
+
+func TestGinPostChat(t *testing.T) {
+	cs, router := newServerAndRouter(t)
+	pIDs := seedPlayers(t, cs.dbFactory, 3)
+	bobHeard := listenAsLocalhost(t, cs, pIDs[1])
+
+	ctx := context.Background()
+	db, err := cs.dbFactory.New(ctx)
+	require.NoError(t, err)
+	defer db.Close()
+	g, err := createGame(ctx, db, pIDs[:2], model.GameSettings{})
+	require.NoError(t, err)
+	chatURL := fmt.Sprintf(`/game/%d/chat`, g.ID)
+
+	testCases := []struct {
+		msg     string
+		url     string
+		req     network.SendChatRequest
+		expCode int
+		expErr  string
+	}{{
+		msg: `nonexistent game`,
+		url: `/game/123/chat`,
+		req: network.SendChatRequest{
+			PlayerID: pIDs[0],
+			Message:  `hello`,
+		},
+		expCode: http.StatusNotFound,
+		expErr:  `Game not found`,
+	}, {
+		msg: `missing player`,
+		url: chatURL,
+		req: network.SendChatRequest{
+			Message: `hello`,
+		},
+		expCode: http.StatusBadRequest,
+		expErr:  `Requires playerID`,
+	}, {
+		msg: `not in the game`,
+		url: chatURL,
+		req: network.SendChatRequest{
+			PlayerID: pIDs[2],
+			Message:  `hello`,
+		},
+		expCode: http.StatusBadRequest,
+		expErr:  `Error: player does not exist in game`,
+	}, {
+		msg: `empty message`,
+		url: chatURL,
+		req: network.SendChatRequest{
+			PlayerID: pIDs[0],
+			Message:  `   `,
+		},
+		expCode: http.StatusBadRequest,
+		expErr:  `Error: chat message is empty`,
+	}, {
+		msg: `too long`,
+		url: chatURL,
+		req: network.SendChatRequest{
+			PlayerID: pIDs[0],
+			Message:  strings.Repeat(`a`, model.MaxChatMessageLength+1),
+		},
+		expCode: http.StatusBadRequest,
+		expErr:  `Error: chat message is too long`,
+	}, {
+		msg: `good luck`,
+		url: chatURL,
+		req: network.SendChatRequest{
+			PlayerID: pIDs[0],
+			Message:  `good luck!`,
+		},
+		expCode: http.StatusOK,
+	}}
+
+	for _, tc := range testCases {
+		w, err := performRequest(router, `POST`, tc.url, prepareBody(t, tc.req))
+		require.NoError(t, err, tc.msg)
+		require.Equal(t, tc.expCode, w.Code, tc.msg)
+		if tc.expCode != http.StatusOK {
+			assert.Equal(t, tc.expErr, readError(t, w), tc.msg)
+			continue
+		}
+
+		var resp network.ChatMessage
+		readBody(t, w.Body, &resp)
+		assert.Equal(t, tc.req.PlayerID, resp.PlayerID, tc.msg)
+		assert.Equal(t, tc.req.Message, resp.Message, tc.msg)
+	}
+
+	// the other player heard it
+	assert.Equal(t, []notification{{
+		path: fmt.Sprintf(`/chat/%d`, g.ID),
+		msg:  `name: good luck!`,
+	}}, bobHeard())
+
+	w, err := performRequest(router, `GET`, chatURL, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, w.Code)
+	var history network.GetChatResponse
+	readBody(t, w.Body, &history)
+	assert.Equal(t, g.ID, history.GameID)
+	require.Len(t, history.Messages, 1)
+	assert.Equal(t, pIDs[0], history.Messages[0].PlayerID)
+	assert.Equal(t, `good luck!`, history.Messages[0].Message)
+}
+
+func TestChatRateLimit(t *testing.T) {
+	cs, _ := newServerAndRouter(t)
+	pIDs := seedPlayers(t, cs.dbFactory, 2)
+
+	ctx := context.Background()
+	db, err := cs.dbFactory.New(ctx)
+	require.NoError(t, err)
+	defer db.Close()
+	g, err := createGame(ctx, db, pIDs, model.GameSettings{})
+	require.NoError(t, err)
+
+	now := time.Now()
+	say := func(pID model.PlayerID, sent time.Time) error {
+		return sendChat(ctx, db, model.ChatMessage{
+			GameID:   g.ID,
+			PlayerID: pID,
+			Message:  `blah`,
+			Sent:     sent,
+		})
+	}
+
+	for i := 0; i < chatRateLimit; i++ {
+		require.NoError(t, say(pIDs[0], now))
+	}
+	assert.Equal(t, errChatRateLimited, say(pIDs[0], now))
+
+	// the limit is for each player
+	assert.NoError(t, say(pIDs[1], now))
+
+	// and it wears off
+	assert.NoError(t, say(pIDs[0], now.Add(chatRatePeriod)))
+
+	msgs, err := getChatHistory(ctx, db, g.ID)
+	require.NoError(t, err)
+	assert.Len(t, msgs, chatRateLimit+2)
+}
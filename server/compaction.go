@@ -0,0 +1,85 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/logging"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+// compactor is a background job which throws away the unneeded snapshots
+// of games once they are over
+type compactor struct {
+	dbFactory persistence.DBFactory
+	policy    persistence.RetentionPolicy
+	period    time.Duration
+}
+
+func newCompactor(
+	dbFactory persistence.DBFactory,
+	policy persistence.RetentionPolicy,
+	period time.Duration,
+) *compactor {
+	return &compactor{
+		dbFactory: dbFactory,
+		policy:    policy,
+		period:    period,
+	}
+}
+
+// run compacts the finished games right away, and then periodically, until the
+// context is done. The games are found in the database, so the ones that finished
+// before a restart (or in another process) get compacted too.
+func (c *compactor) run(ctx context.Context) {
+	t := time.NewTicker(c.period)
+	defer t.Stop()
+
+	for {
+		c.compactFinished(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (c *compactor) compactFinished(ctx context.Context) {
+	gIDs, err := c.finishedGames(ctx)
+	if err != nil {
+		logging.L().Error(`Could not find the games to compact`, zap.Error(err))
+		return
+	}
+
+	for _, gID := range gIDs {
+		err = c.compact(ctx, gID)
+		if err != nil {
+			logging.L().Error(`Could not compact game`, logging.GameID(gID), zap.Error(err))
+		}
+	}
+}
+
+func (c *compactor) finishedGames(ctx context.Context) ([]model.GameID, error) {
+	db, err := c.dbFactory.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	return db.GetGamesToCompact(ctx)
+}
+
+func (c *compactor) compact(ctx context.Context, gID model.GameID) error {
+	db, err := c.dbFactory.New(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return compactGame(ctx, db, gID, c.policy)
+}
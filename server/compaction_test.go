@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+func TestCompactorFindsFinishedGamesInTheDB(t *testing.T) {
+	cs, _ := newServerAndRouter(t)
+	pIDs := seedPlayers(t, cs.dbFactory, 2)
+
+	ctx := context.Background()
+	db, err := cs.dbFactory.New(ctx)
+	require.NoError(t, err)
+	defer db.Close()
+
+	g, err := createGame(ctx, db, pIDs, model.GameSettings{})
+	require.NoError(t, err)
+	require.NoError(t, handleAction(ctx, db, model.PlayerAction{
+		GameID:    g.ID,
+		ID:        pIDs[0],
+		Overcomes: model.DealCards,
+		Action:    model.DealAction{NumShuffles: 3},
+	}))
+	for _, pID := range pIDs {
+		cur, err := getGame(ctx, db, g.ID)
+		require.NoError(t, err)
+		require.NoError(t, handleAction(ctx, db, model.PlayerAction{
+			GameID:    g.ID,
+			ID:        pID,
+			Overcomes: model.CribCard,
+			Action:    model.BuildCribAction{Cards: cur.Hands[pID][:2]},
+		}))
+	}
+	require.NoError(t, handleAction(ctx, db, model.PlayerAction{
+		GameID:    g.ID,
+		ID:        pIDs[1],
+		Overcomes: model.Forfeit,
+		Action:    model.ForfeitAction{},
+	}))
+
+	before, err := db.GetGameAction(ctx, g.ID, 2)
+	require.NoError(t, err)
+
+	// this compactor was never told about the game, as if it finished before a restart
+	c := newCompactor(cs.dbFactory, persistence.RetentionPolicy{}, time.Hour)
+	toCompact, err := c.finishedGames(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, toCompact, g.ID)
+
+	c.compactFinished(ctx)
+
+	toCompact, err = c.finishedGames(ctx)
+	require.NoError(t, err)
+	assert.NotContains(t, toCompact, g.ID)
+
+	// the snapshot after the first crib card was thrown away, and gets replayed
+	after, err := db.GetGameAction(ctx, g.ID, 2)
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+}
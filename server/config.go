@@ -3,11 +3,13 @@ package server
 import (
 	"fmt"
 	"io/ioutil"
-	"log"
 	"os"
 
 	"github.com/rakyll/globalconf"
+	"go.uber.org/zap"
 	ini "gopkg.in/ini.v1"
+
+	"github.com/joshprzybyszewski/cribbage/server/logging"
 )
 
 // loadConfig will check the environment and the ini file for the config
@@ -29,9 +31,9 @@ func loadConfig() {
 
 func parseConfig(confFileName string) {
 	if confFileName == `` {
-		log.Println(`parseConfig from environment only`, confFileName)
+		logging.L().Info(`parseConfig from environment only`)
 	} else {
-		log.Printf("parseConfig from %q\n", confFileName)
+		logging.L().Info(`parseConfig`, zap.String(`file`, confFileName))
 	}
 
 	options := &globalconf.Options{
@@ -54,7 +56,7 @@ func getConfigFile() string {
 	}
 
 	iniPath := `inis/` + getEnvironment() + `/cribbage.ini`
-	log.Printf("ini.LooseLoad from %q\n", iniPath)
+	logging.L().Info(`ini.LooseLoad`, zap.String(`file`, iniPath))
 
 	f, err := ini.LooseLoad(iniPath)
 	if err != nil {
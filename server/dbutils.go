@@ -2,82 +2,261 @@ package server
 
 import (
 	"context"
-	"log"
+	"errors"
 	"time"
 
+	"go.uber.org/zap"
+
 	"github.com/joshprzybyszewski/cribbage/model"
 	"github.com/joshprzybyszewski/cribbage/server/interaction"
+	"github.com/joshprzybyszewski/cribbage/server/logging"
+	"github.com/joshprzybyszewski/cribbage/server/metrics"
 	"github.com/joshprzybyszewski/cribbage/server/persistence"
 	"github.com/joshprzybyszewski/cribbage/server/play"
+	"github.com/joshprzybyszewski/cribbage/server/tracing"
 )
 
-func commitOrRollback(db persistence.DB, err *error) {
+// commitOrRollback ends the transaction. It rolls back if there was an error, or if
+// the request was cancelled or timed out before we got to commit its changes.
+func commitOrRollback(ctx context.Context, db persistence.DB, err *error) {
+	if *err == nil {
+		*err = ctx.Err()
+	}
+
 	var err2 error
 	if *err != nil {
 		err2 = db.Rollback()
 	} else {
 		err2 = db.Commit()
 	}
+	if *err == nil && err2 == nil {
+		// now that the game is saved, the NPCs can make their moves on it
+		npcMoves.release(ctx, db)
+		notifications.release(ctx, db)
+	} else {
+		npcMoves.discard(db)
+		notifications.discard(db)
+	}
 	if err2 != nil {
-		log.Printf("Could not commit/rollback after %+v: %+v\n", err, err2)
+		logging.L().Error(`Could not commit/rollback`, zap.NamedError(`cause`, *err), zap.Error(err2))
+	}
+}
+
+var (
+	errTimedOutByPlayer = errors.New(`only the server can forfeit a player for taking too long`)
+)
+
+// handlePlayerAction handles an action that a client sent us. The players can't
+// claim that the server acted for them.
+func handlePlayerAction(ctx context.Context, db persistence.DB, action model.PlayerAction) error {
+	if fa, ok := action.Action.(model.ForfeitAction); ok && fa.TimedOut {
+		return rejectedActionError{errTimedOutByPlayer}
 	}
+	return handleAction(ctx, db, action)
+}
+
+func handleAction(ctx context.Context, db persistence.DB, action model.PlayerAction) error {
+	return handleActionWhen(ctx, db, action, nil)
 }
 
-func handleAction(_ context.Context, db persistence.DB, action model.PlayerAction) error {
-	err := db.Start()
+// handleActionWhen handles the action if ready returns true for the game as it is
+// loaded in the action's transaction, and does nothing otherwise. This lets the
+// server act on a game that it read earlier, but only if the game hasn't changed
+// in a way that matters since then. A nil ready always handles the action.
+func handleActionWhen(
+	ctx context.Context,
+	db persistence.DB,
+	action model.PlayerAction,
+	ready func(model.Game) (bool, error),
+) (err error) {
+	ctx, span := tracing.Start(ctx, `handleAction`,
+		tracing.GameID(action.GameID),
+		tracing.PlayerID(action.ID),
+		tracing.Blocker(action.Overcomes),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	err = db.Start(ctx)
 	if err != nil {
 		return err
 	}
-	defer commitOrRollback(db, &err)
+	skipped := false
+	defer func() {
+		// this runs after the commit, so that the watchers load the new game
+		if err == nil && !skipped {
+			gameWatchers.changed(action.GameID)
+		}
+	}()
+	defer commitOrRollback(ctx, db, &err)
 
-	g, err := db.GetGame(action.GameID)
+	g, err := db.GetGame(ctx, action.GameID)
 	if err != nil {
 		return err
 	}
 
-	pAPIs, err := getPlayerAPIs(db, g.Players)
+	if ready != nil {
+		var ok bool
+		ok, err = ready(g)
+		if err != nil || !ok {
+			skipped = true
+			return err
+		}
+	}
+
+	pAPIs, err := getPlayerAPIs(ctx, db, g.Players)
 	if err != nil {
 		return err
 	}
 
 	// Now that the server is handling the action, let's set the timestamp to now.
-	action.SetTimeStamp(time.Now())
+	now := time.Now()
+	action.SetTimeStamp(now)
+
+	var snapshotErr error
+	if play.IsTakeback(action) {
+		err = play.HandleTakeback(ctx, &g, action, pAPIs, func(numActions uint) (model.Game, error) {
+			var snapshot model.Game
+			snapshot, snapshotErr = db.GetGameAction(ctx, g.ID, numActions)
+			return snapshot, snapshotErr
+		})
+	} else {
+		err = play.HandleAction(ctx, &g, action, pAPIs)
+	}
+	if err != nil {
+		if snapshotErr != nil {
+			// the action may have been fine, but we couldn't load the game to take it back to
+			return err
+		}
+		metrics.ActionRejected(play.RejectionReason(err))
+		return rejectedActionError{err}
+	}
+	metrics.ActionHandled(action.Overcomes)
+
+	err = db.SaveGame(ctx, g)
+	if err != nil {
+		return err
+	}
 
-	err = play.HandleAction(&g, action, pAPIs)
+	err = notifySpectators(ctx, db, g, action)
 	if err != nil {
 		return err
 	}
-	return db.SaveGame(g)
+
+	err = playForcedMoves(ctx, db, &g, pAPIs, now)
+	if err != nil {
+		return err
+	}
+
+	return setMoveDeadline(ctx, db, g, time.Now())
+}
+
+// rejectedActionError is returned for an action that the rules of the game don't
+// allow, as opposed to one that we weren't able to handle
+type rejectedActionError struct {
+	error
+}
+
+func (e rejectedActionError) Unwrap() error {
+	return e.error
 }
 
-func createGame(_ context.Context, db persistence.DB, pIDs []model.PlayerID) (model.Game, error) {
-	err := db.Start()
+// playForcedMoves plays every forced move for the players who have opted into
+// auto-play. Each one is saved on its own, so that we keep a snapshot per action.
+// The players aren't told about the moves that we make for them, so this needs to
+// run whenever they could have been blocked.
+func playForcedMoves(
+	ctx context.Context,
+	db persistence.DB,
+	g *model.Game,
+	pAPIs map[model.PlayerID]interaction.Player,
+	now time.Time,
+) error {
+
+	for {
+		action, ok := play.ForcedAction(g, pAPIs)
+		if !ok {
+			return nil
+		}
+		// it happened as a result of whatever blocked the player
+		action.SetTimeStamp(now)
+
+		err := play.HandleAction(ctx, g, action, pAPIs)
+		if err != nil {
+			return err
+		}
+		metrics.ActionHandled(action.Overcomes)
+
+		err = db.SaveGame(ctx, *g)
+		if err != nil {
+			return err
+		}
+
+		err = notifySpectators(ctx, db, *g, action)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func createGame(
+	ctx context.Context,
+	db persistence.DB,
+	pIDs []model.PlayerID,
+	settings model.GameSettings,
+) (_ model.Game, err error) {
+	err = db.Start(ctx)
+	if err != nil {
+		return model.Game{}, err
+	}
+	defer commitOrRollback(ctx, db, &err)
+
+	mg, err := startGame(ctx, db, pIDs, settings)
 	if err != nil {
 		return model.Game{}, err
 	}
-	defer commitOrRollback(db, &err)
 
-	var p model.Player
+	return mg, nil
+}
+
+// startGame creates and persists a new game for the given players.
+// It expects that the caller has already started a transaction.
+func startGame(
+	ctx context.Context,
+	db persistence.DB,
+	pIDs []model.PlayerID,
+	settings model.GameSettings,
+) (model.Game, error) {
 	players := make([]model.Player, len(pIDs))
 	for i, id := range pIDs {
-		p, err = db.GetPlayer(id)
+		p, err := db.GetPlayer(ctx, id)
 		if err != nil {
 			return model.Game{}, err
 		}
 		players[i] = p
 	}
 
-	pAPIs, err := getPlayerAPIs(db, players)
+	pAPIs, err := getPlayerAPIs(ctx, db, players)
 	if err != nil {
 		return model.Game{}, err
 	}
 
-	mg, err := play.CreateGame(players, pAPIs)
+	mg, err := play.CreateGameWithSettings(ctx, players, pAPIs, settings)
 	if err != nil {
 		return model.Game{}, err
 	}
 
-	err = db.CreateGame(mg)
+	err = db.CreateGame(ctx, mg)
+	if err != nil {
+		return model.Game{}, err
+	}
+
+	now := time.Now()
+	err = playForcedMoves(ctx, db, &mg, pAPIs, now)
+	if err != nil {
+		return model.Game{}, err
+	}
+
+	err = setMoveDeadline(ctx, db, mg, now)
 	if err != nil {
 		return model.Game{}, err
 	}
@@ -85,30 +264,41 @@ func createGame(_ context.Context, db persistence.DB, pIDs []model.PlayerID) (mo
 	return mg, nil
 }
 
-func getGame(_ context.Context, db persistence.DB, gID model.GameID) (model.Game, error) {
-	return db.GetGame(gID)
+func getGame(ctx context.Context, db persistence.DB, gID model.GameID) (model.Game, error) {
+	return db.GetGame(ctx, gID)
+}
+
+func getPlayer(ctx context.Context, db persistence.DB, pID model.PlayerID) (model.Player, error) {
+	return db.GetPlayer(ctx, pID)
 }
 
-func getPlayer(_ context.Context, db persistence.DB, pID model.PlayerID) (model.Player, error) {
-	return db.GetPlayer(pID)
+func saveInteraction(ctx context.Context, db persistence.DB, pm interaction.PlayerMeans) (err error) {
+	err = db.Start(ctx)
+	if err != nil {
+		return err
+	}
+	defer commitOrRollback(ctx, db, &err)
+
+	return db.SaveInteraction(ctx, pm)
 }
 
-func saveInteraction(_ context.Context, db persistence.DB, pm interaction.PlayerMeans) error {
-	err := db.Start()
+func createPlayer(ctx context.Context, db persistence.DB, p model.Player) (err error) {
+	err = db.Start(ctx)
 	if err != nil {
 		return err
 	}
-	defer commitOrRollback(db, &err)
+	defer commitOrRollback(ctx, db, &err)
 
-	return db.SaveInteraction(pm)
+	return db.CreatePlayer(ctx, p)
 }
 
-func createPlayer(_ context.Context, db persistence.DB, p model.Player) error {
-	err := db.Start()
+func compactGame(ctx context.Context, db persistence.DB, gID model.GameID, rp persistence.RetentionPolicy) (err error) {
+	err = db.Start(ctx)
 	if err != nil {
 		return err
 	}
-	defer commitOrRollback(db, &err)
+	defer commitOrRollback(ctx, db, &err)
 
-	return db.CreatePlayer(p)
+	err = db.CompactGame(ctx, gID, rp)
+	return err
 }
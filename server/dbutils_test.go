@@ -0,0 +1,281 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/interaction"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+	"github.com/joshprzybyszewski/cribbage/server/play"
+)
+
+func TestHandleActionTakeback(t *testing.T) {
+	cs, _ := newServerAndRouter(t)
+	pIDs := seedPlayers(t, cs.dbFactory, 2)
+
+	ctx := context.Background()
+	db, err := cs.dbFactory.New(ctx)
+	require.NoError(t, err)
+	defer db.Close()
+
+	g, err := createGame(ctx, db, pIDs, model.GameSettings{})
+	require.NoError(t, err)
+
+	act := func(pID model.PlayerID, b model.Blocker, a interface{}) error {
+		return handleAction(ctx, db, model.PlayerAction{
+			GameID:    g.ID,
+			ID:        pID,
+			Overcomes: b,
+			Action:    a,
+		})
+	}
+	toss := func(pID model.PlayerID) {
+		cur, err := getGame(ctx, db, g.ID)
+		require.NoError(t, err)
+		require.NoError(t, act(pID, model.CribCard, model.BuildCribAction{
+			Cards: cur.Hands[pID][:2],
+		}))
+	}
+
+	require.NoError(t, act(g.CurrentDealer, model.DealCards, model.DealAction{NumShuffles: 3}))
+	toss(pIDs[1])
+	toss(pIDs[0])
+
+	beforeCut, err := getGame(ctx, db, g.ID)
+	require.NoError(t, err)
+	require.Equal(t, model.Cut, beforeCut.Phase)
+	require.Len(t, beforeCut.Crib, 4)
+
+	assert.Equal(t, rejectedActionError{play.ErrTakebackNotAllowed}, act(pIDs[1], model.RequestTakeback, model.RequestTakebackAction{}))
+	require.NoError(t, act(pIDs[0], model.RequestTakeback, model.RequestTakebackAction{}))
+	require.NoError(t, act(pIDs[1], model.ApproveTakeback, model.ApproveTakebackAction{Approve: true}))
+
+	actG, err := getGame(ctx, db, g.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.BuildCrib, actG.Phase)
+	assert.Equal(t, map[model.PlayerID]model.Blocker{pIDs[0]: model.CribCard}, actG.BlockingPlayers)
+	assert.Len(t, actG.Hands[pIDs[0]], 6)
+	assert.Len(t, actG.Crib, 2)
+	require.Equal(t, 5, actG.NumActions())
+	assert.Equal(t, model.RequestTakeback, actG.Actions[3].Overcomes)
+	assert.Equal(t, model.ApproveTakeback, actG.Actions[4].Overcomes)
+
+	// now they can toss the cards they meant to
+	toss(pIDs[0])
+	actG, err = getGame(ctx, db, g.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.Cut, actG.Phase)
+	assert.Len(t, actG.Crib, 4)
+
+	// replaying the actions goes back through the takeback just like the game did
+	replayed, err := db.GetGameAction(ctx, g.ID, 1)
+	require.NoError(t, err)
+	require.NoError(t, play.Replay(ctx, &replayed, actG.Actions[1:]))
+	assert.Equal(t, actG, replayed)
+}
+
+func TestHandleActionAutoPlay(t *testing.T) {
+	cs, _ := newServerAndRouter(t)
+	pIDs := seedPlayers(t, cs.dbFactory, 2)
+
+	ctx := context.Background()
+	db, err := cs.dbFactory.New(ctx)
+	require.NoError(t, err)
+	defer db.Close()
+
+	for _, pID := range pIDs {
+		pm := interaction.New(pID, interaction.Means{Mode: interaction.Unknown})
+		pm.AutoPlay = true
+		require.NoError(t, saveInteraction(ctx, db, pm))
+	}
+
+	g, err := createGame(ctx, db, pIDs, model.GameSettings{})
+	require.NoError(t, err)
+
+	act := func(pID model.PlayerID, b model.Blocker, a interface{}) {
+		require.NoError(t, handleAction(ctx, db, model.PlayerAction{
+			GameID:    g.ID,
+			ID:        pID,
+			Overcomes: b,
+			Action:    a,
+		}))
+	}
+
+	act(g.CurrentDealer, model.DealCards, model.DealAction{NumShuffles: 3})
+	for _, pID := range pIDs {
+		cur, err := getGame(ctx, db, g.ID)
+		require.NoError(t, err)
+		act(pID, model.CribCard, model.BuildCribAction{Cards: cur.Hands[pID][:2]})
+	}
+	act(pIDs[1], model.CutCard, model.CutDeckAction{Percentage: 0.5})
+
+	// the players only have to choose which card to peg when they have options
+	for {
+		cur, err := getGame(ctx, db, g.ID)
+		require.NoError(t, err)
+		if cur.Phase != model.Pegging {
+			break
+		}
+		for pID, b := range cur.BlockingPlayers {
+			require.Equal(t, model.PegCard, b)
+			act(pID, b, model.PegAction{Card: firstPlayableCard(cur, pID)})
+		}
+	}
+
+	actG, err := getGame(ctx, db, g.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.Deal, actG.Phase, `the hands and the crib were counted for them`)
+
+	numAuto := 0
+	for i, pa := range actG.Actions {
+		switch pa.Overcomes {
+		case model.CountHand, model.CountCrib:
+			assert.True(t, pa.Auto, `action %d`, i)
+		case model.DealCards, model.CribCard, model.CutCard:
+			assert.False(t, pa.Auto, `action %d`, i)
+		}
+		if pa.Auto {
+			numAuto++
+		}
+
+		// every action gets its own snapshot
+		snapshot, err := db.GetGameAction(context.Background(), g.ID, uint(i+1))
+		require.NoError(t, err)
+		assert.Equal(t, i+1, snapshot.NumActions())
+	}
+	assert.GreaterOrEqual(t, numAuto, 3)
+}
+
+// cancellingDB cancels the request once it has loaded the game, as if the
+// client went away while the server was handling its action
+type cancellingDB struct {
+	persistence.DB
+
+	cancel func()
+
+	committed  bool
+	rolledBack bool
+}
+
+func (db *cancellingDB) GetGame(ctx context.Context, id model.GameID) (model.Game, error) {
+	defer db.cancel()
+	return db.DB.GetGame(ctx, id)
+}
+
+func (db *cancellingDB) Commit() error {
+	db.committed = true
+	return db.DB.Commit()
+}
+
+func (db *cancellingDB) Rollback() error {
+	db.rolledBack = true
+	return db.DB.Rollback()
+}
+
+func TestHandleActionRollsBackCancelledRequests(t *testing.T) {
+	cs, _ := newServerAndRouter(t)
+	pIDs := seedPlayers(t, cs.dbFactory, 2)
+
+	db, err := cs.dbFactory.New(context.Background())
+	require.NoError(t, err)
+	defer db.Close()
+
+	g, err := createGame(context.Background(), db, pIDs, model.GameSettings{})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cdb := &cancellingDB{
+		DB:     db,
+		cancel: cancel,
+	}
+
+	err = handleAction(ctx, cdb, model.PlayerAction{
+		GameID:    g.ID,
+		ID:        g.CurrentDealer,
+		Overcomes: model.DealCards,
+		Action:    model.DealAction{NumShuffles: 3},
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.True(t, cdb.rolledBack)
+	assert.False(t, cdb.committed)
+
+	actG, err := getGame(context.Background(), db, g.ID)
+	require.NoError(t, err)
+	assert.Zero(t, actG.NumActions())
+	assert.Equal(t, model.Deal, actG.Phase)
+}
+
+func TestCommitOrRollbackWhenRequestIsDone(t *testing.T) {
+	testCases := []struct {
+		msg           string
+		cancel        bool
+		err           error
+		expErr        error
+		expCommitted  bool
+		expRolledBack bool
+	}{{
+		msg:          `success`,
+		expCommitted: true,
+	}, {
+		msg:           `failure`,
+		err:           persistence.ErrGameNotFound,
+		expErr:        persistence.ErrGameNotFound,
+		expRolledBack: true,
+	}, {
+		msg:           `cancelled after its work was done`,
+		cancel:        true,
+		expErr:        context.Canceled,
+		expRolledBack: true,
+	}, {
+		msg:           `failure and cancelled`,
+		cancel:        true,
+		err:           persistence.ErrGameNotFound,
+		expErr:        persistence.ErrGameNotFound,
+		expRolledBack: true,
+	}}
+
+	for _, tc := range testCases {
+		cs, _ := newServerAndRouter(t)
+		db, err := cs.dbFactory.New(context.Background())
+		require.NoError(t, err, tc.msg)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		require.NoError(t, db.Start(ctx), tc.msg)
+		if tc.cancel {
+			cancel()
+		}
+
+		cdb := &cancellingDB{
+			DB:     db,
+			cancel: cancel,
+		}
+		err = tc.err
+		commitOrRollback(ctx, cdb, &err)
+		cancel()
+
+		assert.Equal(t, tc.expErr, err, tc.msg)
+		assert.Equal(t, tc.expCommitted, cdb.committed, tc.msg)
+		assert.Equal(t, tc.expRolledBack, cdb.rolledBack, tc.msg)
+	}
+}
+
+func firstPlayableCard(g model.Game, pID model.PlayerID) model.Card {
+	for _, c := range g.Hands[pID] {
+		pegged := false
+		for _, pc := range g.PeggedCards {
+			if pc.Card == c {
+				pegged = true
+				break
+			}
+		}
+		if !pegged && g.CurrentPeg()+c.PegValue() <= model.MaxPeggingValue {
+			return c
+		}
+	}
+	return model.Card{}
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"sync"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+)
+
+// gameWatchers tells the streams that are watching a game when it has changed
+var gameWatchers = newWatchers()
+
+type watchers struct {
+	lock   sync.Mutex
+	byGame map[model.GameID]map[chan struct{}]struct{}
+}
+
+func newWatchers() *watchers {
+	return &watchers{
+		byGame: map[model.GameID]map[chan struct{}]struct{}{},
+	}
+}
+
+// watch returns a channel that hears when the game changes, and a func to stop watching
+func (w *watchers) watch(gID model.GameID) (<-chan struct{}, func()) {
+	// it only needs to know that something changed, not how many times
+	ch := make(chan struct{}, 1)
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if _, ok := w.byGame[gID]; !ok {
+		w.byGame[gID] = map[chan struct{}]struct{}{}
+	}
+	w.byGame[gID][ch] = struct{}{}
+
+	return ch, func() {
+		w.lock.Lock()
+		defer w.lock.Unlock()
+
+		delete(w.byGame[gID], ch)
+		if len(w.byGame[gID]) == 0 {
+			delete(w.byGame, gID)
+		}
+	}
+}
+
+// changed tells everyone watching the game that it has changed
+func (w *watchers) changed(gID model.GameID) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	for ch := range w.byGame[gID] {
+		select {
+		case ch <- struct{}{}:
+		default:
+			// they haven't gotten around to the last change yet
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package server
+
+import (
+	"errors"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/network"
+	pb "github.com/joshprzybyszewski/cribbage/network/cribbagepb"
+)
+
+var (
+	errMissingAction = errors.New(`missing action`)
+)
+
+// convertToPBGame converts the view of the game that the REST API would have
+// returned. That way, both APIs hide the same cards from the requester.
+func convertToPBGame(ggr network.GetGameResponse) *pb.Game {
+	g := &pb.Game{
+		Id:              int64(ggr.ID),
+		Phase:           pb.Phase(model.NewPhaseFromString(ggr.Phase)),
+		CurrentPeg:      int32(ggr.CurrentPeg),
+		BlockingPlayers: make(map[string]pb.Blocker, len(ggr.BlockingPlayers)),
+		CurrentDealer:   string(ggr.CurrentDealer),
+		Hands:           make(map[string]*pb.Hand, len(ggr.Hands)),
+		Crib:            convertToPBCards(ggr.Crib),
+		CutCard:         convertToPBCard(ggr.CutCard),
+		Deck:            convertToPBCards(ggr.Deck),
+	}
+
+	for _, t := range ggr.Teams {
+		pt := &pb.Team{
+			Color:        convertToPBColor(t.Color),
+			CurrentScore: int32(t.CurrentScore),
+			LagScore:     int32(t.LagScore),
+		}
+		for _, p := range t.Players {
+			pt.Players = append(pt.Players, &pb.Player{
+				Id:   string(p.ID),
+				Name: p.Name,
+			})
+		}
+		g.Teams = append(g.Teams, pt)
+	}
+
+	for pID, b := range ggr.BlockingPlayers {
+		g.BlockingPlayers[string(pID)] = pb.Blocker(model.NewBlockerFromString(b))
+	}
+
+	for pID, h := range ggr.Hands {
+		g.Hands[string(pID)] = &pb.Hand{
+			Cards: convertToPBCards(h),
+		}
+	}
+
+	for _, pc := range ggr.PeggedCards {
+		g.PeggedCards = append(g.PeggedCards, &pb.PeggedCard{
+			Card:     convertToPBCard(pc.Card),
+			PlayerId: string(pc.Player),
+		})
+	}
+
+	if ggr.Settings != nil {
+		g.Settings = &pb.GameSettings{
+			MoveTimeout:  ggr.Settings.MoveTimeout,
+			OnTimeout:    ggr.Settings.OnTimeout,
+			AutoplayNpc:  string(ggr.Settings.AutoPlayNPC),
+			ProvablyFair: ggr.Settings.ProvablyFair,
+		}
+	}
+
+	if ggr.Shuffle != nil {
+		g.Shuffle = &pb.ShuffleProof{
+			Commitment:    ggr.Shuffle.Commitment,
+			ServerSeed:    ggr.Shuffle.ServerSeed,
+			ClientEntropy: ggr.Shuffle.ClientEntropy,
+			CutPercentage: ggr.Shuffle.CutPercentage,
+		}
+	}
+
+	if ggr.Outcome != nil {
+		g.Outcome = &pb.GameOutcome{
+			Reason:   ggr.Outcome.Reason,
+			PlayerId: string(ggr.Outcome.PlayerID),
+		}
+		for _, c := range ggr.Outcome.Winners {
+			g.Outcome.Winners = append(g.Outcome.Winners, convertToPBColor(c))
+		}
+	}
+
+	return g
+}
+
+func convertToPBColor(c string) pb.PlayerColor {
+	return pb.PlayerColor(model.NewPlayerColorFromString(c))
+}
+
+func convertToPBCards(cs []network.Card) []*pb.Card {
+	if cs == nil {
+		return nil
+	}
+	pcs := make([]*pb.Card, len(cs))
+	for i, c := range cs {
+		pcs[i] = convertToPBCard(c)
+	}
+	return pcs
+}
+
+func convertToPBCard(c network.Card) *pb.Card {
+	return &pb.Card{
+		Name: c.Name,
+	}
+}
+
+func convertFromPBCards(pcs []*pb.Card) ([]model.Card, error) {
+	cs := make([]model.Card, len(pcs))
+	for i, pc := range pcs {
+		c, err := convertFromPBCard(pc)
+		if err != nil {
+			return nil, err
+		}
+		cs[i] = c
+	}
+	return cs, nil
+}
+
+func convertFromPBCard(pc *pb.Card) (model.Card, error) {
+	return model.NewCardFromExternalString(pc.GetName())
+}
+
+func convertFromPBGameSettings(ps *pb.GameSettings) *network.GameSettings {
+	if ps == nil {
+		return nil
+	}
+	return &network.GameSettings{
+		MoveTimeout:  ps.GetMoveTimeout(),
+		OnTimeout:    ps.GetOnTimeout(),
+		AutoPlayNPC:  model.PlayerID(ps.GetAutoplayNpc()),
+		ProvablyFair: ps.GetProvablyFair(),
+	}
+}
+
+func convertFromPBPlayerAction(ppa *pb.PlayerAction) (model.PlayerAction, error) {
+	if ppa == nil {
+		return model.PlayerAction{}, errMissingAction
+	}
+
+	pa := model.PlayerAction{
+		GameID:    model.GameID(ppa.GetGameId()),
+		ID:        model.PlayerID(ppa.GetPlayerId()),
+		Overcomes: model.Blocker(ppa.GetOvercomes()),
+	}
+
+	switch a := ppa.GetAction().(type) {
+	case *pb.PlayerAction_Deal:
+		pa.Action = model.DealAction{
+			NumShuffles: int(a.Deal.GetNumShuffles()),
+			Entropy:     a.Deal.GetEntropy(),
+		}
+	case *pb.PlayerAction_BuildCrib:
+		cs, err := convertFromPBCards(a.BuildCrib.GetCards())
+		if err != nil {
+			return model.PlayerAction{}, err
+		}
+		pa.Action = model.BuildCribAction{
+			Cards: cs,
+		}
+	case *pb.PlayerAction_CutDeck:
+		pa.Action = model.CutDeckAction{
+			Percentage: a.CutDeck.GetPercentage(),
+		}
+	case *pb.PlayerAction_Peg:
+		if a.Peg.GetSayGo() {
+			pa.Action = model.PegAction{
+				SayGo: true,
+			}
+			break
+		}
+		c, err := convertFromPBCard(a.Peg.GetCard())
+		if err != nil {
+			return model.PlayerAction{}, err
+		}
+		pa.Action = model.PegAction{
+			Card: c,
+		}
+	case *pb.PlayerAction_CountHand:
+		pa.Action = model.CountHandAction{
+			Pts: int(a.CountHand.GetPts()),
+		}
+	case *pb.PlayerAction_CountCrib:
+		pa.Action = model.CountCribAction{
+			Pts: int(a.CountCrib.GetPts()),
+		}
+	case *pb.PlayerAction_Forfeit:
+		pa.Action = model.ForfeitAction{}
+	case *pb.PlayerAction_RequestTakeback:
+		pa.Action = model.RequestTakebackAction{}
+	case *pb.PlayerAction_ApproveTakeback:
+		pa.Action = model.ApproveTakebackAction{
+			Approve: a.ApproveTakeback.GetApprove(),
+		}
+	default:
+		return model.PlayerAction{}, errMissingAction
+	}
+
+	return pa, nil
+}
+
+func convertToPBSuggestHandResponse(resp []network.GetSuggestHandResponse) *pb.SuggestHandResponse {
+	shr := &pb.SuggestHandResponse{
+		Suggestions: make([]*pb.TossSuggestion, len(resp)),
+	}
+	for i, s := range resp {
+		shr.Suggestions[i] = &pb.TossSuggestion{
+			Hand:    convertToPBCardNames(s.Hand),
+			Toss:    convertToPBCardNames(s.Toss),
+			HandPts: convertToPBPointStats(s.HandPts),
+			CribPts: convertToPBPointStats(s.CribPts),
+		}
+	}
+	return shr
+}
+
+func convertToPBCardNames(names []string) []*pb.Card {
+	pcs := make([]*pb.Card, len(names))
+	for i, n := range names {
+		pcs[i] = &pb.Card{
+			Name: n,
+		}
+	}
+	return pcs
+}
+
+func convertToPBPointStats(ps network.PointStats) *pb.PointStats {
+	return &pb.PointStats{
+		Min:    int32(ps.Min),
+		Median: ps.Median,
+		Avg:    ps.Avg,
+		Max:    int32(ps.Max),
+	}
+}
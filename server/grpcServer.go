@@ -0,0 +1,331 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/joshprzybyszewski/cribbage/logic/suggestions"
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/network"
+	pb "github.com/joshprzybyszewski/cribbage/network/cribbagepb"
+	"github.com/joshprzybyszewski/cribbage/server/interaction"
+	"github.com/joshprzybyszewski/cribbage/server/logging"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+	"github.com/joshprzybyszewski/cribbage/server/play"
+)
+
+const (
+	// watchPollPeriod is how often WatchGame reloads the game in case it was
+	// changed by another server, which our gameWatchers can't hear about
+	watchPollPeriod = 5 * time.Second
+)
+
+var _ pb.CribbageServer = (*grpcServer)(nil)
+
+// grpcServer serves the gRPC API. It shares the dbFactory and the game logic with the REST API.
+type grpcServer struct {
+	pb.UnimplementedCribbageServer
+
+	dbFactory  persistence.DBFactory
+	pollPeriod time.Duration
+
+	server *grpc.Server
+	// stopping is closed when the server starts shutting down
+	stopping chan struct{}
+}
+
+func newGRPCServer(dbFactory persistence.DBFactory) *grpcServer {
+	gs := &grpcServer{
+		dbFactory:  dbFactory,
+		pollPeriod: watchPollPeriod,
+		server:     grpc.NewServer(grpc.UnaryInterceptor(timeoutUnaryCalls(*requestTimeout))),
+		stopping:   make(chan struct{}),
+	}
+	pb.RegisterCribbageServer(gs.server, gs)
+	return gs
+}
+
+func (gs *grpcServer) serve(port int) error {
+	lis, err := net.Listen(`tcp`, fmt.Sprintf(":%d", port))
+	if err != nil {
+		return err
+	}
+
+	logging.L().Info(`Serving gRPC`, zap.Int(`port`, port))
+	return gs.server.Serve(lis)
+}
+
+// stop lets the calls that are in progress finish, unless the context is done first.
+// It ends the WatchGame streams, since they would last for as long as their clients want.
+func (gs *grpcServer) stop(ctx context.Context) {
+	close(gs.stopping)
+
+	stopped := make(chan struct{})
+	go func() {
+		gs.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		gs.server.Stop()
+	}
+}
+
+// timeoutUnaryCalls does for the gRPC calls what timeoutRequests does for the REST
+// requests. WatchGame streams for as long as the client wants, so it is not limited.
+func timeoutUnaryCalls(timeout time.Duration) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		_ *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if timeout <= 0 {
+			return handler(ctx, req)
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		return handler(ctx, req)
+	}
+}
+
+func (gs *grpcServer) CreatePlayer(ctx context.Context, req *pb.CreatePlayerRequest) (*pb.Player, error) {
+	p := model.Player{
+		ID:   model.PlayerID(req.GetPlayer().GetId()),
+		Name: req.GetPlayer().GetName(),
+	}
+	switch {
+	case p.ID == model.InvalidPlayerID:
+		return nil, status.Error(codes.InvalidArgument, `Username is required`)
+	case p.Name == ``:
+		return nil, status.Error(codes.InvalidArgument, `Display name is required`)
+	case !model.IsValidPlayerID(p.ID):
+		return nil, status.Error(codes.InvalidArgument, `Username must be alphanumeric`)
+	}
+
+	db, err := gs.dbFactory.New(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, `dbFactory.New() error: %s`, err)
+	}
+	defer db.Close()
+
+	err = createPlayer(ctx, db, p)
+	if err != nil {
+		if err == persistence.ErrPlayerAlreadyExists {
+			return nil, status.Error(codes.AlreadyExists, `Username already exists`)
+		}
+		return nil, toGRPCError(err)
+	}
+
+	return &pb.Player{
+		Id:   string(p.ID),
+		Name: p.Name,
+	}, nil
+}
+
+func (gs *grpcServer) CreateGame(ctx context.Context, req *pb.CreateGameRequest) (*pb.Game, error) {
+	pIDs := make([]model.PlayerID, len(req.GetPlayerIds()))
+	for i, pID := range req.GetPlayerIds() {
+		if pID == `` {
+			return nil, status.Errorf(codes.InvalidArgument, `Invalid player ID at index %d`, i)
+		}
+		pIDs[i] = model.PlayerID(pID)
+	}
+	if len(pIDs) < model.MinPlayerGame || len(pIDs) > model.MaxPlayerGame {
+		return nil, status.Errorf(codes.InvalidArgument, `Invalid num players: %d`, len(pIDs))
+	}
+
+	settings, err := network.ConvertFromGameSettings(convertFromPBGameSettings(req.GetSettings()))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, `Invalid settings: %s`, err)
+	}
+	if settings.OnTimeout == model.AutoPlayOnTimeout && !interaction.IsNPC(settings.AutoPlayNPC) {
+		return nil, status.Error(codes.InvalidArgument, `Invalid settings: unsupported autoplay NPC`)
+	}
+
+	db, err := gs.dbFactory.New(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, `dbFactory.New() error: %s`, err)
+	}
+	defer db.Close()
+
+	g, err := createGame(ctx, db, pIDs, settings)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return convertToPBGame(network.ConvertToGetGameResponse(g)), nil
+}
+
+func (gs *grpcServer) GetGame(ctx context.Context, req *pb.GetGameRequest) (*pb.Game, error) {
+	db, err := gs.dbFactory.New(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, `dbFactory.New() error: %s`, err)
+	}
+	defer db.Close()
+
+	g, err := getGame(ctx, db, model.GameID(req.GetGameId()))
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return getPBGameView(g, model.PlayerID(req.GetPlayerId()))
+}
+
+func (gs *grpcServer) SubmitAction(ctx context.Context, req *pb.SubmitActionRequest) (*pb.SubmitActionResponse, error) {
+	pa, err := convertFromPBPlayerAction(req.GetAction())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, `Error: %s`, err)
+	}
+
+	db, err := gs.dbFactory.New(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, `dbFactory.New() error: %s`, err)
+	}
+	defer db.Close()
+
+	err = handlePlayerAction(ctx, db, pa)
+	if err != nil {
+		return nil, toGRPCActionError(err)
+	}
+
+	return &pb.SubmitActionResponse{}, nil
+}
+
+func (gs *grpcServer) SuggestHand(_ context.Context, req *pb.SuggestHandRequest) (*pb.SuggestHandResponse, error) {
+	hand, err := convertFromPBCards(req.GetDealt())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, `Error: %s`, err)
+	}
+
+	summaries, err := suggestions.GetAllTosses(hand)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, `Error: %s`, err)
+	}
+
+	resp := network.ConvertToGetSuggestHandResponse(summaries)
+	sort.Slice(resp, func(i, j int) bool {
+		return resp[i].HandPts.Avg > resp[j].HandPts.Avg
+	})
+	return convertToPBSuggestHandResponse(resp), nil
+}
+
+func (gs *grpcServer) WatchGame(req *pb.WatchGameRequest, stream pb.Cribbage_WatchGameServer) error {
+	ctx := stream.Context()
+	gID := model.GameID(req.GetGameId())
+	pID := model.PlayerID(req.GetPlayerId())
+
+	changes, stop := gameWatchers.watch(gID)
+	defer stop()
+
+	t := time.NewTicker(gs.pollPeriod)
+	defer t.Stop()
+
+	numActions := -1
+	for {
+		g, err := gs.loadGame(ctx, gID)
+		if err != nil {
+			return err
+		}
+
+		if len(g.Actions) != numActions {
+			numActions = len(g.Actions)
+
+			var view *pb.Game
+			view, err = getPBGameView(g, pID)
+			if err != nil {
+				return err
+			}
+			err = stream.Send(view)
+			if err != nil {
+				return err
+			}
+		}
+
+		if g.IsOver() {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-gs.stopping:
+			return status.Error(codes.Unavailable, `The server is shutting down`)
+		case <-changes:
+		case <-t.C:
+		}
+	}
+}
+
+func (gs *grpcServer) loadGame(ctx context.Context, gID model.GameID) (model.Game, error) {
+	db, err := gs.dbFactory.New(ctx)
+	if err != nil {
+		return model.Game{}, status.Errorf(codes.Internal, `dbFactory.New() error: %s`, err)
+	}
+	defer db.Close()
+
+	g, err := getGame(ctx, db, gID)
+	if err != nil {
+		return model.Game{}, toGRPCError(err)
+	}
+	return g, nil
+}
+
+// getPBGameView returns what the player can see of the game. Without a player, no hands are shown.
+func getPBGameView(g model.Game, pID model.PlayerID) (*pb.Game, error) {
+	if pID == model.InvalidPlayerID {
+		return convertToPBGame(network.ConvertToGetGameResponse(g)), nil
+	}
+
+	view, err := network.ConvertToGetGameResponseForPlayer(g, pID)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return convertToPBGame(view), nil
+}
+
+// toGRPCActionError tells the client whether the action was against the rules of
+// the game, or whether we weren't able to handle it
+func toGRPCActionError(err error) error {
+	var re rejectedActionError
+	if !errors.As(err, &re) {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return status.FromContextError(err).Err()
+		}
+		return toGRPCError(err)
+	}
+
+	switch {
+	case errors.Is(err, play.ErrGameAlreadyOver),
+		errors.Is(err, play.ErrNotBlockedByPlayer),
+		errors.Is(err, play.ErrWrongBlocker),
+		errors.Is(err, play.ErrTakebackPending),
+		errors.Is(err, play.ErrTakebackNotAllowed):
+		// the action could be fine, but not for the game as it is now
+		return status.Errorf(codes.FailedPrecondition, `Error: %s`, err)
+	}
+	return status.Errorf(codes.InvalidArgument, `Error: %s`, err)
+}
+
+func toGRPCError(err error) error {
+	switch err {
+	case persistence.ErrGameNotFound:
+		return status.Error(codes.NotFound, `Game not found`)
+	case persistence.ErrPlayerNotFound:
+		return status.Error(codes.NotFound, `Player not found`)
+	}
+	return status.Errorf(codes.Internal, `Error: %s`, err)
+}
@@ -0,0 +1,389 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/network"
+	pb "github.com/joshprzybyszewski/cribbage/network/cribbagepb"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+	"github.com/joshprzybyszewski/cribbage/server/persistence/memory"
+	"github.com/joshprzybyszewski/cribbage/server/play"
+)
+
+func newGRPCClient(t *testing.T) pb.CribbageClient {
+	_, client := newGRPCServerAndClient(t)
+	return client
+}
+
+func newGRPCServerAndClient(t *testing.T) (*grpcServer, pb.CribbageClient) {
+	memory.Clear()
+	gs := newGRPCServer(memory.NewFactory(play.Replay))
+	// don't wait around for the poll when the watchers should have heard about the change
+	gs.pollPeriod = time.Hour
+
+	lis := bufconn.Listen(1024 * 1024)
+	go func() {
+		_ = gs.server.Serve(lis)
+	}()
+	t.Cleanup(gs.server.Stop)
+
+	conn, err := grpc.Dial(`bufnet`,
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+		grpc.WithInsecure(),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return gs, pb.NewCribbageClient(conn)
+}
+
+func createGRPCPlayers(t *testing.T, client pb.CribbageClient, ids ...string) {
+	for _, id := range ids {
+		p, err := client.CreatePlayer(context.Background(), &pb.CreatePlayerRequest{
+			Player: &pb.Player{
+				Id:   id,
+				Name: `name`,
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, id, p.GetId())
+	}
+}
+
+func TestGRPCCreatePlayer(t *testing.T) {
+	client := newGRPCClient(t)
+	createGRPCPlayers(t, client, `alice`)
+
+	_, err := client.CreatePlayer(context.Background(), &pb.CreatePlayerRequest{
+		Player: &pb.Player{
+			Id:   `alice`,
+			Name: `alice again`,
+		},
+	})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	_, err = client.CreatePlayer(context.Background(), &pb.CreatePlayerRequest{
+		Player: &pb.Player{
+			Id: `bob`,
+		},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPCCreateGame(t *testing.T) {
+	client := newGRPCClient(t)
+	createGRPCPlayers(t, client, `alice`, `bob`)
+
+	_, err := client.CreateGame(context.Background(), &pb.CreateGameRequest{
+		PlayerIds: []string{`alice`},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.CreateGame(context.Background(), &pb.CreateGameRequest{
+		PlayerIds: []string{`alice`, `charlie`},
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	g, err := client.CreateGame(context.Background(), &pb.CreateGameRequest{
+		PlayerIds: []string{`alice`, `bob`},
+		Settings: &pb.GameSettings{
+			MoveTimeout:  `10m0s`,
+			OnTimeout:    `notify`,
+			ProvablyFair: true,
+		},
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, g.GetId())
+	assert.Equal(t, pb.Phase_DEAL, g.GetPhase())
+	assert.Len(t, g.GetTeams(), 2)
+	assert.Equal(t, `10m0s`, g.GetSettings().GetMoveTimeout())
+	assert.True(t, g.GetSettings().GetProvablyFair())
+	assert.NotEmpty(t, g.GetShuffle().GetCommitment())
+	assert.Empty(t, g.GetShuffle().GetServerSeed())
+	require.Len(t, g.GetBlockingPlayers(), 1)
+	assert.Equal(t, pb.Blocker_DEAL_CARDS, g.GetBlockingPlayers()[g.GetCurrentDealer()])
+
+	_, err = client.GetGame(context.Background(), &pb.GetGameRequest{
+		GameId: int64(model.NewGameID()),
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestGRPCWatchGameAndSubmitAction(t *testing.T) {
+	client := newGRPCClient(t)
+	createGRPCPlayers(t, client, `alice`, `bob`)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	g, err := client.CreateGame(ctx, &pb.CreateGameRequest{
+		PlayerIds: []string{`alice`, `bob`},
+	})
+	require.NoError(t, err)
+	dealer := g.GetCurrentDealer()
+	other := `alice`
+	if dealer == other {
+		other = `bob`
+	}
+
+	stream, err := client.WatchGame(ctx, &pb.WatchGameRequest{
+		GameId:   g.GetId(),
+		PlayerId: dealer,
+	})
+	require.NoError(t, err)
+
+	first, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, pb.Phase_DEAL, first.GetPhase())
+
+	_, err = client.SubmitAction(ctx, &pb.SubmitActionRequest{
+		Action: &pb.PlayerAction{
+			GameId:    g.GetId(),
+			PlayerId:  other,
+			Overcomes: pb.Blocker_DEAL_CARDS,
+			Action: &pb.PlayerAction_Deal{
+				Deal: &pb.DealAction{NumShuffles: 3},
+			},
+		},
+	})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err), `only the dealer can deal`)
+
+	_, err = client.SubmitAction(ctx, &pb.SubmitActionRequest{
+		Action: &pb.PlayerAction{
+			GameId:    g.GetId(),
+			PlayerId:  dealer,
+			Overcomes: pb.Blocker_DEAL_CARDS,
+			Action: &pb.PlayerAction_Deal{
+				Deal: &pb.DealAction{NumShuffles: 3},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	dealt, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, pb.Phase_BUILD_CRIB, dealt.GetPhase())
+	assert.Equal(t, pb.Blocker_CRIB_CARD, dealt.GetBlockingPlayers()[dealer])
+
+	// the watcher can only see their own hand
+	require.Len(t, dealt.GetHands()[dealer].GetCards(), 6)
+	for _, c := range dealt.GetHands()[dealer].GetCards() {
+		assert.NotEqual(t, `unknown`, c.GetName())
+	}
+	require.Len(t, dealt.GetHands()[other].GetCards(), 6)
+	for _, c := range dealt.GetHands()[other].GetCards() {
+		assert.Equal(t, `unknown`, c.GetName())
+	}
+
+	fetched, err := client.GetGame(ctx, &pb.GetGameRequest{
+		GameId:   g.GetId(),
+		PlayerId: dealer,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, dealt.GetHands()[dealer].GetCards()[0].GetName(), fetched.GetHands()[dealer].GetCards()[0].GetName())
+}
+
+func TestToGRPCActionError(t *testing.T) {
+	testCases := []struct {
+		msg     string
+		err     error
+		expCode codes.Code
+	}{{
+		msg:     `game not found`,
+		err:     persistence.ErrGameNotFound,
+		expCode: codes.NotFound,
+	}, {
+		msg:     `not the player's turn`,
+		err:     rejectedActionError{play.ErrNotBlockedByPlayer},
+		expCode: codes.FailedPrecondition,
+	}, {
+		msg:     `against the rules`,
+		err:     rejectedActionError{errors.New(`Cannot peg card you don't have`)},
+		expCode: codes.InvalidArgument,
+	}, {
+		msg:     `database error`,
+		err:     errors.New(`connection refused`),
+		expCode: codes.Internal,
+	}, {
+		msg:     `request timed out`,
+		err:     context.DeadlineExceeded,
+		expCode: codes.DeadlineExceeded,
+	}}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expCode, status.Code(toGRPCActionError(tc.err)), tc.msg)
+	}
+}
+
+func TestGRPCStopEndsWatchGame(t *testing.T) {
+	gs, client := newGRPCServerAndClient(t)
+	createGRPCPlayers(t, client, `alice`, `bob`)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	g, err := client.CreateGame(ctx, &pb.CreateGameRequest{
+		PlayerIds: []string{`alice`, `bob`},
+	})
+	require.NoError(t, err)
+
+	stream, err := client.WatchGame(ctx, &pb.WatchGameRequest{
+		GameId: g.GetId(),
+	})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.NoError(t, err)
+
+	stopped := make(chan struct{})
+	go func() {
+		gs.stop(ctx)
+		close(stopped)
+	}()
+
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		assert.Fail(t, `the server should not wait for the stream`)
+	}
+}
+
+func TestGRPCSuggestHand(t *testing.T) {
+	client := newGRPCClient(t)
+
+	dealt := make([]*pb.Card, 0, 6)
+	for _, c := range []string{`5H`, `5S`, `5C`, `JD`, `2C`, `3S`} {
+		dealt = append(dealt, &pb.Card{Name: c})
+	}
+	resp, err := client.SuggestHand(context.Background(), &pb.SuggestHandRequest{
+		Dealt: dealt,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.GetSuggestions())
+	best := resp.GetSuggestions()[0]
+	assert.Len(t, best.GetHand(), 4)
+	assert.Len(t, best.GetToss(), 2)
+	for _, s := range resp.GetSuggestions() {
+		assert.LessOrEqual(t, s.GetHandPts().GetAvg(), best.GetHandPts().GetAvg())
+	}
+
+	_, err = client.SuggestHand(context.Background(), &pb.SuggestHandRequest{
+		Dealt: []*pb.Card{{Name: `not a card`}},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestConvertToPBGameShuffle(t *testing.T) {
+	g := convertToPBGame(network.GetGameResponse{
+		Shuffle: &network.ShuffleProof{
+			Commitment:    `commitment`,
+			ServerSeed:    `seed`,
+			ClientEntropy: `entropy`,
+			CutPercentage: 0.42,
+		},
+	})
+	assert.Equal(t, `commitment`, g.GetShuffle().GetCommitment())
+	assert.Equal(t, `seed`, g.GetShuffle().GetServerSeed())
+	assert.Equal(t, `entropy`, g.GetShuffle().GetClientEntropy())
+	assert.Equal(t, 0.42, g.GetShuffle().GetCutPercentage())
+
+	assert.Nil(t, convertToPBGame(network.GetGameResponse{}).GetShuffle())
+}
+
+func TestConvertFromPBPlayerAction(t *testing.T) {
+	tests := []struct {
+		desc   string
+		action *pb.PlayerAction
+		exp    interface{}
+		expErr bool
+	}{{
+		desc: `build crib`,
+		action: &pb.PlayerAction{
+			Overcomes: pb.Blocker_CRIB_CARD,
+			Action: &pb.PlayerAction_BuildCrib{
+				BuildCrib: &pb.BuildCribAction{
+					Cards: []*pb.Card{{Name: `AH`}, {Name: `10C`}},
+				},
+			},
+		},
+		exp: model.BuildCribAction{
+			Cards: []model.Card{model.NewCardFromString(`AH`), model.NewCardFromString(`10C`)},
+		},
+	}, {
+		desc: `peg`,
+		action: &pb.PlayerAction{
+			Overcomes: pb.Blocker_PEG_CARD,
+			Action: &pb.PlayerAction_Peg{
+				Peg: &pb.PegAction{Card: &pb.Card{Name: `KS`}},
+			},
+		},
+		exp: model.PegAction{
+			Card: model.NewCardFromString(`KS`),
+		},
+	}, {
+		desc: `say go`,
+		action: &pb.PlayerAction{
+			Overcomes: pb.Blocker_PEG_CARD,
+			Action: &pb.PlayerAction_Peg{
+				Peg: &pb.PegAction{SayGo: true},
+			},
+		},
+		exp: model.PegAction{
+			SayGo: true,
+		},
+	}, {
+		desc: `count crib`,
+		action: &pb.PlayerAction{
+			Overcomes: pb.Blocker_COUNT_CRIB,
+			Action: &pb.PlayerAction_CountCrib{
+				CountCrib: &pb.CountCribAction{Pts: 12},
+			},
+		},
+		exp: model.CountCribAction{
+			Pts: 12,
+		},
+	}, {
+		desc: `bad card`,
+		action: &pb.PlayerAction{
+			Overcomes: pb.Blocker_PEG_CARD,
+			Action: &pb.PlayerAction_Peg{
+				Peg: &pb.PegAction{Card: &pb.Card{Name: `unknown`}},
+			},
+		},
+		expErr: true,
+	}, {
+		desc: `missing action`,
+		action: &pb.PlayerAction{
+			Overcomes: pb.Blocker_COUNT_HAND,
+		},
+		expErr: true,
+	}}
+
+	for _, tc := range tests {
+		pa, err := convertFromPBPlayerAction(tc.action)
+		if tc.expErr {
+			assert.Error(t, err, tc.desc)
+			continue
+		}
+		require.NoError(t, err, tc.desc)
+		assert.Equal(t, model.Blocker(tc.action.GetOvercomes()), pa.Overcomes, tc.desc)
+		assert.Equal(t, tc.exp, pa.Action, tc.desc)
+	}
+}
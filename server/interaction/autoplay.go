@@ -0,0 +1,32 @@
+package interaction
+
+// AutoPlayer is a Player who has asked the server to submit their forced moves,
+// which are the ones where they only have a single legal action.
+type AutoPlayer interface {
+	Player
+
+	AutoPlaysForcedMoves() bool
+}
+
+var _ AutoPlayer = autoPlayer{}
+
+type autoPlayer struct {
+	Player
+}
+
+func (autoPlayer) AutoPlaysForcedMoves() bool {
+	return true
+}
+
+// WithAutoPlay returns the player, who now wants the server to play their forced moves
+func WithAutoPlay(p Player) Player {
+	return autoPlayer{
+		Player: p,
+	}
+}
+
+// AutoPlays returns true if the server should play the forced moves for the player
+func AutoPlays(p Player) bool {
+	ap, ok := p.(AutoPlayer)
+	return ok && ap.AutoPlaysForcedMoves()
+}
@@ -0,0 +1,33 @@
+package interaction
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+)
+
+func TestAutoPlays(t *testing.T) {
+	assert.False(t, AutoPlays(nil))
+	assert.False(t, AutoPlays(Empty(`alice`)))
+	assert.True(t, AutoPlays(WithAutoPlay(Empty(`alice`))))
+	assert.False(t, AutoPlays(WithSpans(context.Background(), Empty(`alice`))))
+	assert.True(t, AutoPlays(WithSpans(context.Background(), WithAutoPlay(Empty(`alice`)))))
+
+	pm := New(`alice`, Means{
+		Mode: Localhost,
+		Info: `8484`,
+	})
+	pAPI, err := FromPlayerMeans(pm)
+	require.NoError(t, err)
+	assert.False(t, AutoPlays(pAPI))
+
+	pm.AutoPlay = true
+	pAPI, err = FromPlayerMeans(pm)
+	require.NoError(t, err)
+	assert.True(t, AutoPlays(pAPI))
+	assert.Equal(t, model.PlayerID(`alice`), pAPI.ID())
+}
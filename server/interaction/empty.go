@@ -31,3 +31,9 @@ func (e *empty) NotifyMessage(g model.Game, s string) error {
 func (e *empty) NotifyScoreUpdate(g model.Game, msgs ...string) error {
 	return nil
 }
+func (e *empty) NotifyInvitation(l model.Lobby, s string) error {
+	return nil
+}
+func (e *empty) NotifyChat(g model.Game, cm model.ChatMessage) error {
+	return nil
+}
@@ -12,6 +12,8 @@ type Player interface {
 	NotifyBlocking(model.Blocker, model.Game, string) error
 	NotifyMessage(model.Game, string) error
 	NotifyScoreUpdate(g model.Game, msgs ...string) error
+	NotifyInvitation(model.Lobby, string) error
+	NotifyChat(model.Game, model.ChatMessage) error
 }
 
 func New(pID model.PlayerID, m Means) PlayerMeans {
@@ -23,6 +25,23 @@ func New(pID model.PlayerID, m Means) PlayerMeans {
 }
 
 func FromPlayerMeans(pm PlayerMeans) (Player, error) {
+	return FromPlayerMeansWithOutbox(pm, immediately{})
+}
+
+// FromPlayerMeansWithOutbox returns the Player for the means, which sends its
+// notifications through the outbox
+func FromPlayerMeansWithOutbox(pm PlayerMeans, o Outbox) (Player, error) {
+	p, err := fromPreferredMeans(pm, o)
+	if err != nil {
+		return nil, err
+	}
+	if pm.AutoPlay {
+		return WithAutoPlay(p), nil
+	}
+	return p, nil
+}
+
+func fromPreferredMeans(pm PlayerMeans, o Outbox) (Player, error) {
 	pID := pm.PlayerID
 	means := pm.getMeans(pm.PreferredMode)
 
@@ -35,6 +54,10 @@ func FromPlayerMeans(pm PlayerMeans) (Player, error) {
 			return nil, errors.New(`player means info should contain an action handler, but it doesn't`)
 		}
 		return NewNPCPlayer(pID, ah)
+	case Webhook:
+		return newWebhookPlayer(pID, means.Info, o)
+	case Polling:
+		return newPollingPlayer(pID, o), nil
 	default:
 		return newUnimplemented(pID), nil
 	}
@@ -42,4 +65,14 @@ func FromPlayerMeans(pm PlayerMeans) (Player, error) {
 
 type ActionHandler interface {
 	Handle(action model.PlayerAction) error
+	Chat(cm model.ChatMessage) error
+}
+
+// ActionQueue is an ActionHandler which holds on to the NPC's action, and takes it
+// once the game (which had numActions when the NPC decided on it) has been saved.
+// NPCs hand their actions to one instead of taking them straight away.
+type ActionQueue interface {
+	ActionHandler
+
+	Enqueue(action model.PlayerAction, numActions int) error
 }
@@ -54,6 +54,22 @@ func (lhp *localhostPlayer) NotifyScoreUpdate(g model.Game, msgs ...string) erro
 	return lhp.notify(fmt.Sprintf(`score/%d`, g.ID), rc)
 }
 
+func (lhp *localhostPlayer) NotifyInvitation(l model.Lobby, msg string) error {
+	return lhp.notify(fmt.Sprintf(`invitation/%d`, l.ID), ioutil.NopCloser(strings.NewReader(msg)))
+}
+
+func (lhp *localhostPlayer) NotifyChat(g model.Game, cm model.ChatMessage) error {
+	from := string(cm.PlayerID)
+	for _, p := range g.Players {
+		if p.ID == cm.PlayerID {
+			from = p.Name
+			break
+		}
+	}
+	msg := fmt.Sprintf("%s: %s", from, cm.Message)
+	return lhp.notify(fmt.Sprintf(`chat/%d`, g.ID), ioutil.NopCloser(strings.NewReader(msg)))
+}
+
 func (lhp *localhostPlayer) notify(endpoint string, data io.Reader) error {
 	urlStr := fmt.Sprintf("http://localhost:%d/%s", lhp.port, endpoint)
 	req, err := http.NewRequest(`POST`, urlStr, data)
@@ -30,3 +30,11 @@ func (m *Mock) NotifyScoreUpdate(g model.Game, msgs ...string) error {
 	args := m.Called(g, msgs)
 	return args.Error(0)
 }
+func (m *Mock) NotifyInvitation(l model.Lobby, s string) error {
+	args := m.Called(l, s)
+	return args.Error(0)
+}
+func (m *Mock) NotifyChat(g model.Game, cm model.ChatMessage) error {
+	args := m.Called(g, cm)
+	return args.Error(0)
+}
@@ -1,6 +1,7 @@
 package interaction
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -12,6 +13,8 @@ const (
 	Localhost Mode = 1
 	NPC       Mode = 2
 	Unknown   Mode = 3
+	Webhook   Mode = 4
+	Polling   Mode = 5
 )
 
 type Mode int
@@ -26,13 +29,24 @@ func (m *Means) AddSerializedInfo(serInfo []byte) error {
 	case UnsetMode, Unknown:
 		// nothing we know how to do for these
 		return nil
+	case Polling:
+		// the polling player's events are kept by the server, so there's nothing to remember
+		return nil
 	case Localhost:
 		// the local host player expects a string as the info to tell us which port to connect to
 		m.Info = string(serInfo)
 		return nil
 	case NPC:
 		// serInfo should represent an action handler for the NPC.
-		// It should be overwritten elsewhere to npcActionHandler
+		// It should be overwritten elsewhere to npcActionQueue
+		return nil
+	case Webhook:
+		var wi WebhookInfo
+		err := json.Unmarshal(serInfo, &wi)
+		if err != nil {
+			return err
+		}
+		m.Info = wi
 		return nil
 	default:
 		return fmt.Errorf(`unsupported Mode: %v`, m.Mode)
@@ -45,6 +59,8 @@ func (m *Means) GetSerializedInfo() ([]byte, error) {
 	case UnsetMode, Unknown:
 		// nothing we know how to do here either
 		return nil, nil
+	case Polling:
+		return nil, nil
 	case Localhost:
 		str, ok := m.Info.(string)
 		if !ok {
@@ -56,6 +72,12 @@ func (m *Means) GetSerializedInfo() ([]byte, error) {
 		// It should be a pointer to a struct that implements this interface
 		// so we can't serialize it.
 		return nil, nil
+	case Webhook:
+		wi, ok := m.Info.(WebhookInfo)
+		if !ok {
+			return nil, errors.New(`webhook player should have WebhookInfo as its info`)
+		}
+		return json.Marshal(wi)
 	default:
 		return nil, fmt.Errorf(`unsupported Mode: %v`, m.Mode)
 	}
@@ -65,6 +87,8 @@ type PlayerMeans struct {
 	PlayerID      model.PlayerID `protobuf:"-" json:"-" bson:"playerID"`
 	PreferredMode Mode           `protobuf:"-" json:"-" bson:"preferredMode"`
 	Interactions  []Means        `protobuf:"-" json:"-" bson:"interactions"`
+	// AutoPlay says the server should submit the player's forced moves for them
+	AutoPlay bool `protobuf:"-" json:"-" bson:"autoPlay"`
 }
 
 func (pm PlayerMeans) getMeans(m Mode) Means {
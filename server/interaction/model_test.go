@@ -33,6 +33,20 @@ func TestMeansSerializedInfo(t *testing.T) {
 		input: &Means{
 			Mode: NPC,
 		},
+	}, {
+		inputMode: Webhook,
+		input: &Means{
+			Mode: Webhook,
+			Info: WebhookInfo{
+				URL:    `https://example.com/cribbage`,
+				Secret: `shhhhhhhhhhhhhhhhhhh`,
+			},
+		},
+	}, {
+		inputMode: Polling,
+		input: &Means{
+			Mode: Polling,
+		},
 	}}
 
 	for _, tc := range testCases {
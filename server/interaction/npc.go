@@ -2,11 +2,14 @@ package interaction
 
 import (
 	"errors"
-	"fmt"
 	"time"
 
+	"go.uber.org/zap"
+
 	"github.com/joshprzybyszewski/cribbage/logic/scorer"
 	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/logging"
+	"github.com/joshprzybyszewski/cribbage/server/metrics"
 	"github.com/joshprzybyszewski/cribbage/utils/rand"
 )
 
@@ -26,6 +29,12 @@ var npcs = map[model.PlayerID]npc{
 	Calc:   &calculatedNPC{},
 }
 
+// IsNPC returns true if the player is one of the NPCs
+func IsNPC(pID model.PlayerID) bool {
+	_, ok := npcs[pID]
+	return ok
+}
+
 var _ Player = (*NPCPlayer)(nil)
 
 type NPCPlayer struct {
@@ -52,24 +61,79 @@ func (npc *NPCPlayer) ID() model.PlayerID {
 }
 
 func (npc *NPCPlayer) NotifyBlocking(b model.Blocker, g model.Game, s string) error {
+	start := time.Now()
 	pa, err := npc.buildAction(b, g)
 	if err != nil {
+		metrics.ObserveNPCAction(npc.id, b, time.Since(start), err)
 		return err
 	}
+	thinking := time.Since(start)
+
+	if aq, ok := npc.actionHandler.(ActionQueue); ok {
+		// the queue takes the action once the game has been saved, so we don't need to guess when that is
+		return aq.Enqueue(pa, g.NumActions())
+	}
+
 	go func() {
 		// This is an arbitrary amount of time to sleep. We just need to give
 		// the server a chance to increment the phase and get ready to handle
 		// our action
 		time.Sleep(time.Millisecond * 20)
+
+		handleStart := time.Now()
 		err := npc.actionHandler.Handle(pa)
-		// TODO do something better with the error...
+		metrics.ObserveNPCAction(npc.id, b, thinking+time.Since(handleStart), err)
+		if err != nil {
+			logging.L().Error(`NPC could not take its action`,
+				logging.GameID(g.ID),
+				logging.PlayerID(npc.id),
+				logging.Blocker(b),
+				zap.Error(err),
+			)
+			return
+		}
+
+		err = npc.chatAbout(pa)
 		if err != nil {
-			fmt.Printf("ope! %v\n", err)
+			logging.L().Warn(`NPC could not chat about its action`,
+				logging.GameID(g.ID),
+				logging.PlayerID(npc.id),
+				zap.Error(err),
+			)
 		}
 	}()
 	return nil
 }
 
+// chatAbout lets the NPC say something about the action it just took
+func (npc *NPCPlayer) chatAbout(pa model.PlayerAction) error {
+	cm, ok := ChatAbout(pa)
+	if !ok {
+		return nil
+	}
+	return npc.actionHandler.Chat(cm)
+}
+
+// ChatAbout returns what the NPC says about the action it just took, if anything
+func ChatAbout(pa model.PlayerAction) (model.ChatMessage, bool) {
+	cha, ok := pa.Action.(model.CountHandAction)
+	if !ok {
+		return model.ChatMessage{}, false
+	}
+
+	phrase, ok := getHandPhrase(pa.ID, cha.Pts)
+	if !ok {
+		return model.ChatMessage{}, false
+	}
+
+	return model.ChatMessage{
+		GameID:   pa.GameID,
+		PlayerID: pa.ID,
+		Message:  phrase,
+		Sent:     time.Now(),
+	}, true
+}
+
 // The NPC doesn't care about messages or score updates
 func (npc *NPCPlayer) NotifyMessage(g model.Game, s string) error {
 	return nil
@@ -78,6 +142,16 @@ func (npc *NPCPlayer) NotifyScoreUpdate(g model.Game, msgs ...string) error {
 	return nil
 }
 
+// NPCs accept their invitations as soon as they are invited, so they don't need to hear about them
+func (npc *NPCPlayer) NotifyInvitation(l model.Lobby, s string) error {
+	return nil
+}
+
+// NPCs only talk, they don't listen
+func (npc *NPCPlayer) NotifyChat(g model.Game, cm model.ChatMessage) error {
+	return nil
+}
+
 func getUnpeggedCards(hand []model.Card, pc []model.PeggedCard) []model.Card {
 	peggedMap := make(map[model.Card]struct{}, len(pc))
 	cardsLeft := make([]model.Card, 0, len(hand))
@@ -94,22 +168,48 @@ func getUnpeggedCards(hand []model.Card, pc []model.PeggedCard) []model.Card {
 }
 
 func (npc *NPCPlayer) buildAction(b model.Blocker, g model.Game) (model.PlayerAction, error) {
+	return buildNPCAction(npc.player, npc.ID(), b, g)
+}
+
+// BuildNPCAction returns the action that the given type of NPC would take for
+// the player with the given ID. This lets an NPC play on behalf of a human.
+func BuildNPCAction(
+	npcType model.PlayerID,
+	pID model.PlayerID,
+	b model.Blocker,
+	g model.Game,
+) (model.PlayerAction, error) {
+
+	p, ok := npcs[npcType]
+	if !ok {
+		return model.PlayerAction{}, ErrUnknownNPCType
+	}
+	return buildNPCAction(p, pID, b, g)
+}
+
+func buildNPCAction(
+	p npc,
+	pID model.PlayerID,
+	b model.Blocker,
+	g model.Game,
+) (model.PlayerAction, error) {
+
 	pa := model.PlayerAction{
 		GameID:    g.ID,
-		ID:        npc.ID(),
+		ID:        pID,
 		Overcomes: b,
 	}
 	// the NPC is building the action _now_
 	pa.SetTimeStamp(time.Now())
 
-	myHand := g.Hands[npc.ID()]
+	myHand := g.Hands[pID]
 	switch b {
 	case model.DealCards:
 		pa.Action = model.DealAction{
 			NumShuffles: rand.Intn(10) + 1,
 		}
 	case model.CribCard:
-		bca, err := npc.player.getBuildCribAction(myHand, g.CurrentDealer == npc.ID())
+		bca, err := p.getBuildCribAction(myHand, g.CurrentDealer == pID)
 		if err != nil {
 			return model.PlayerAction{}, err
 		}
@@ -120,7 +220,7 @@ func (npc *NPCPlayer) buildAction(b model.Blocker, g model.Game) (model.PlayerAc
 		}
 	case model.PegCard:
 		cardsLeft := getUnpeggedCards(myHand, g.PeggedCards)
-		pa.Action = npc.player.getPegAction(cardsLeft, g.PeggedCards, g.CurrentPeg())
+		pa.Action = p.getPegAction(cardsLeft, g.PeggedCards, g.CurrentPeg())
 	case model.CountHand:
 		pa.Action = model.CountHandAction{
 			Pts: scorer.HandPoints(g.CutCard, myHand),
@@ -129,6 +229,11 @@ func (npc *NPCPlayer) buildAction(b model.Blocker, g model.Game) (model.PlayerAc
 		pa.Action = model.CountCribAction{
 			Pts: scorer.CribPoints(g.CutCard, g.Crib),
 		}
+	case model.ApproveTakeback:
+		// NPCs are good sports about misclicks
+		pa.Action = model.ApproveTakebackAction{
+			Approve: true,
+		}
 	}
 	return pa, nil
 }
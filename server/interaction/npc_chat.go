@@ -0,0 +1,84 @@
+package interaction
+
+import (
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/utils/rand"
+)
+
+type handQuality int
+
+const (
+	ordinaryHand handQuality = iota
+	nineteenHand
+	greatHand
+	perfectHand
+)
+
+const (
+	greatHandPoints   = 16
+	perfectHandPoints = 29
+)
+
+func getHandQuality(pts int) handQuality {
+	switch {
+	case pts == 0:
+		// it's called a "nineteen" because you can't make 19 points with a hand
+		return nineteenHand
+	case pts == perfectHandPoints:
+		return perfectHand
+	case pts >= greatHandPoints:
+		return greatHand
+	}
+	return ordinaryHand
+}
+
+// handPhrases are the canned things that each NPC says after counting their hand.
+// NPCs don't have anything to say about ordinary hands.
+var handPhrases = map[model.PlayerID]map[handQuality][]string{
+	Dumb: {
+		nineteenHand: {
+			`Nineteen! Is that a lot?`,
+		},
+		greatHand: {
+			`I have no idea what I'm doing.`,
+			`Wait, all of these count?`,
+		},
+		perfectHand: {
+			`Did I win?`,
+		},
+	},
+	Simple: {
+		nineteenHand: {
+			`Nineteen. Oh well!`,
+		},
+		greatHand: {
+			`Look at all those points!`,
+			`Fifteen two, fifteen four, and the rest won't fit on the board.`,
+		},
+		perfectHand: {
+			`29!! I need to sit down.`,
+		},
+	},
+	Calc: {
+		nineteenHand: {
+			`Nineteen. Statistically, that had to happen eventually.`,
+		},
+		greatHand: {
+			`The numbers never lie.`,
+			`Expected value: exceeded.`,
+		},
+		perfectHand: {
+			`29. Exactly as I calculated.`,
+			`The odds of that were 1 in 216,580. Read 'em and weep.`,
+		},
+	},
+}
+
+// getHandPhrase returns what the NPC says about the points in its hand, if anything
+func getHandPhrase(npcID model.PlayerID, pts int) (string, bool) {
+	phrases := handPhrases[npcID][getHandQuality(pts)]
+	if len(phrases) == 0 {
+		return ``, false
+	}
+	return phrases[rand.Intn(len(phrases))], true
+}
@@ -0,0 +1,93 @@
+package interaction
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+)
+
+func TestGetHandPhrase(t *testing.T) {
+	tests := []struct {
+		desc       string
+		pts        int
+		expQuality handQuality
+	}{{
+		desc:       `nineteen`,
+		pts:        0,
+		expQuality: nineteenHand,
+	}, {
+		desc:       `ordinary`,
+		pts:        8,
+		expQuality: ordinaryHand,
+	}, {
+		desc:       `great`,
+		pts:        24,
+		expQuality: greatHand,
+	}, {
+		desc:       `perfect`,
+		pts:        29,
+		expQuality: perfectHand,
+	}}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.expQuality, getHandQuality(tc.pts), tc.desc)
+
+		for _, npcID := range []model.PlayerID{Dumb, Simple, Calc} {
+			phrase, ok := getHandPhrase(npcID, tc.pts)
+			if tc.expQuality == ordinaryHand {
+				assert.False(t, ok, tc.desc)
+				assert.Empty(t, phrase, tc.desc)
+				continue
+			}
+			assert.True(t, ok, tc.desc)
+			assert.Contains(t, handPhrases[npcID][tc.expQuality], phrase, tc.desc)
+		}
+	}
+}
+
+func TestNPCTauntsAfterPerfectHand(t *testing.T) {
+	var said []model.ChatMessage
+	ah := &mockActionHandler{
+		handleActionFunc: func(a model.PlayerAction) error {
+			return nil
+		},
+		chatFunc: func(cm model.ChatMessage) error {
+			said = append(said, cm)
+			return nil
+		},
+	}
+	p, err := NewNPCPlayer(Calc, ah)
+	require.NoError(t, err)
+	npc, ok := p.(*NPCPlayer)
+	require.True(t, ok)
+
+	g := model.Game{
+		ID:      model.GameID(5),
+		CutCard: model.NewCardFromString(`5h`),
+		Hands: map[model.PlayerID][]model.Card{
+			Calc: {
+				model.NewCardFromString(`5c`),
+				model.NewCardFromString(`5d`),
+				model.NewCardFromString(`5s`),
+				model.NewCardFromString(`jh`),
+			},
+		},
+	}
+	pa, err := npc.buildAction(model.CountHand, g)
+	require.NoError(t, err)
+	require.NoError(t, npc.chatAbout(pa))
+
+	require.Len(t, said, 1)
+	assert.Equal(t, g.ID, said[0].GameID)
+	assert.Equal(t, Calc, said[0].PlayerID)
+	assert.Contains(t, handPhrases[Calc][perfectHand], said[0].Message)
+
+	// it doesn't have anything to say about dealing
+	pa, err = npc.buildAction(model.DealCards, g)
+	require.NoError(t, err)
+	require.NoError(t, npc.chatAbout(pa))
+	assert.Len(t, said, 1)
+}
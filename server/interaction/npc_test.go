@@ -14,12 +14,20 @@ var _ ActionHandler = (*mockActionHandler)(nil)
 
 type mockActionHandler struct {
 	handleActionFunc func(a model.PlayerAction) error
+	chatFunc         func(cm model.ChatMessage) error
 }
 
 func (ah *mockActionHandler) Handle(pa model.PlayerAction) error {
 	return ah.handleActionFunc(pa)
 }
 
+func (ah *mockActionHandler) Chat(cm model.ChatMessage) error {
+	if ah.chatFunc == nil {
+		return nil
+	}
+	return ah.chatFunc(cm)
+}
+
 func NewNilHandler() ActionHandler {
 	return &mockActionHandler{
 		handleActionFunc: func(a model.PlayerAction) error {
@@ -59,17 +67,20 @@ func newGame(npcID model.PlayerID, nPlayers int, pegCards []model.Card) model.Ga
 	}
 
 	pegs := make([]model.PeggedCard, 0)
+	ps := model.PeggingState{}
 	for i, c := range pegCards {
 		pegs = append(pegs, model.PeggedCard{
 			Card:     c,
 			PlayerID: players[i%nPlayers].ID,
 		})
+		ps.Count += c.PegValue()
 	}
 	return model.Game{
 		ID:          5,
 		Players:     players,
 		Hands:       hands,
 		PeggedCards: pegs,
+		Pegging:     ps,
 	}
 }
 
@@ -463,6 +474,52 @@ func TestNotifyBlocking(t *testing.T) {
 		assert.Nil(t, err)
 	}
 }
+
+var _ ActionQueue = (*mockActionQueue)(nil)
+
+type mockActionQueue struct {
+	mockActionHandler
+
+	queued     []model.PlayerAction
+	numActions []int
+}
+
+func (aq *mockActionQueue) Enqueue(pa model.PlayerAction, numActions int) error {
+	aq.queued = append(aq.queued, pa)
+	aq.numActions = append(aq.numActions, numActions)
+	return nil
+}
+
+func TestNotifyBlockingEnqueues(t *testing.T) {
+	aq := &mockActionQueue{
+		mockActionHandler: mockActionHandler{
+			handleActionFunc: func(a model.PlayerAction) error {
+				assert.Fail(t, `the action should be queued instead of handled`)
+				return nil
+			},
+		},
+	}
+	p, err := NewNPCPlayer(Calc, aq)
+	require.NoError(t, err)
+
+	g := model.Game{
+		ID: model.GameID(7),
+		Actions: []model.PlayerAction{{
+			GameID:    model.GameID(7),
+			ID:        `p1`,
+			Overcomes: model.CutCard,
+			Action:    model.CutDeckAction{Percentage: 0.5},
+		}},
+	}
+	require.NoError(t, p.NotifyBlocking(model.DealCards, g, ``))
+
+	require.Len(t, aq.queued, 1)
+	assert.Equal(t, g.ID, aq.queued[0].GameID)
+	assert.Equal(t, Calc, aq.queued[0].ID)
+	assert.Equal(t, model.DealCards, aq.queued[0].Overcomes)
+	assert.Equal(t, []int{1}, aq.numActions)
+}
+
 func TestNotifyMessage(t *testing.T) {
 	tests := []struct {
 		desc string
@@ -548,3 +605,11 @@ func TestNewNPCPlayer(t *testing.T) {
 		}
 	}
 }
+
+func TestIsNPC(t *testing.T) {
+	for _, npc := range []model.PlayerID{Dumb, Simple, Calc} {
+		assert.True(t, IsNPC(npc), npc)
+	}
+	assert.False(t, IsNPC(`alice`))
+	assert.False(t, IsNPC(model.InvalidPlayerID))
+}
@@ -0,0 +1,19 @@
+package interaction
+
+// Outbox holds on to the notifications which are sent while a change is being
+// made, so that they can be delivered once the change has been saved. The
+// players which deliver their notifications over the network send them through one.
+type Outbox interface {
+	// Hold keeps the delivery until the change has been saved. An Outbox which
+	// delivers it right away returns the delivery's error.
+	Hold(deliver func() error) error
+}
+
+var _ Outbox = immediately{}
+
+// immediately is the Outbox that doesn't wait for anything
+type immediately struct{}
+
+func (immediately) Hold(deliver func() error) error {
+	return deliver()
+}
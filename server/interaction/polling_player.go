@@ -0,0 +1,185 @@
+package interaction
+
+import (
+	"context"
+	"sync"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/network"
+)
+
+const (
+	// maxPolledEvents is how many events we hold on to for a player who hasn't polled for them
+	maxPolledEvents = 100
+)
+
+var _ Player = (*pollingPlayer)(nil)
+
+// pollingPlayer keeps the player's notifications on the server until they poll for
+// them, so that clients behind a NAT or firewall don't need to be called back
+type pollingPlayer struct {
+	pID    model.PlayerID
+	outbox Outbox
+}
+
+func newPollingPlayer(pID model.PlayerID, o Outbox) *pollingPlayer {
+	return &pollingPlayer{
+		pID:    pID,
+		outbox: o,
+	}
+}
+
+func (pp *pollingPlayer) ID() model.PlayerID {
+	return pp.pID
+}
+
+func (pp *pollingPlayer) NotifyBlocking(b model.Blocker, g model.Game, s string) error {
+	e := network.PlayerEvent{
+		Event:   string(network.BlockingEvent),
+		GameID:  g.ID,
+		Blocker: b.String(),
+	}
+	if s != `` {
+		e.Messages = []string{s}
+	}
+	return pp.add(e)
+}
+
+func (pp *pollingPlayer) NotifyMessage(g model.Game, msg string) error {
+	return pp.add(network.PlayerEvent{
+		Event:    string(network.MessageEvent),
+		GameID:   g.ID,
+		Messages: []string{msg},
+	})
+}
+
+func (pp *pollingPlayer) NotifyScoreUpdate(g model.Game, msgs ...string) error {
+	return pp.add(network.PlayerEvent{
+		Event:    string(network.ScoreUpdateEvent),
+		GameID:   g.ID,
+		Messages: msgs,
+	})
+}
+
+func (pp *pollingPlayer) NotifyInvitation(l model.Lobby, msg string) error {
+	return pp.add(network.PlayerEvent{
+		Event:    string(network.InvitationEvent),
+		LobbyID:  l.ID,
+		Messages: []string{msg},
+	})
+}
+
+func (pp *pollingPlayer) NotifyChat(g model.Game, cm model.ChatMessage) error {
+	chat := network.ConvertToChatMessage(cm)
+	return pp.add(network.PlayerEvent{
+		Event:  string(network.ChatEvent),
+		GameID: g.ID,
+		Chat:   &chat,
+	})
+}
+
+// add holds the event in the outbox, so that the player can't poll for it until
+// the change that it's about has been saved
+func (pp *pollingPlayer) add(e network.PlayerEvent) error {
+	return pp.outbox.Hold(func() error {
+		playerEvents.add(pp.pID, e)
+		return nil
+	})
+}
+
+// playerEvents holds the events for polling players. They only live in this
+// server's memory, so every poll for a player needs to reach the same server.
+var playerEvents = newEventQueues()
+
+type eventQueue struct {
+	lastID uint64
+	events []network.PlayerEvent
+
+	// added is closed (and replaced) whenever an event is added
+	added chan struct{}
+}
+
+type eventQueues struct {
+	lock     sync.Mutex
+	byPlayer map[model.PlayerID]*eventQueue
+}
+
+func newEventQueues() *eventQueues {
+	return &eventQueues{
+		byPlayer: map[model.PlayerID]*eventQueue{},
+	}
+}
+
+// queue returns the player's queue. The caller must hold the lock.
+func (eqs *eventQueues) queue(pID model.PlayerID) *eventQueue {
+	q, ok := eqs.byPlayer[pID]
+	if !ok {
+		q = &eventQueue{
+			added: make(chan struct{}),
+		}
+		eqs.byPlayer[pID] = q
+	}
+	return q
+}
+
+func (eqs *eventQueues) add(pID model.PlayerID, e network.PlayerEvent) {
+	eqs.lock.Lock()
+	defer eqs.lock.Unlock()
+
+	q := eqs.queue(pID)
+	q.lastID++
+	e.ID = q.lastID
+	q.events = append(q.events, e)
+	if len(q.events) > maxPolledEvents {
+		// the player has stopped polling; they can get the latest from the game itself
+		q.events = q.events[len(q.events)-maxPolledEvents:]
+	}
+
+	close(q.added)
+	q.added = make(chan struct{})
+}
+
+// after returns the events since the given ID, and a channel that is closed when
+// another one is added
+func (eqs *eventQueues) after(pID model.PlayerID, after uint64) ([]network.PlayerEvent, uint64, <-chan struct{}) {
+	eqs.lock.Lock()
+	defer eqs.lock.Unlock()
+
+	q := eqs.queue(pID)
+	if after > q.lastID {
+		// this server has restarted since the player last polled
+		after = 0
+	}
+
+	var events []network.PlayerEvent
+	for _, e := range q.events {
+		if e.ID > after {
+			events = append(events, e)
+		}
+	}
+	return events, q.lastID, q.added
+}
+
+// PollEvents returns the player's events that came after the given ID. If there
+// aren't any yet, it waits for one until the context is done.
+func PollEvents(ctx context.Context, pID model.PlayerID, after uint64) network.GetPlayerEventsResponse {
+	for {
+		events, lastID, added := playerEvents.after(pID, after)
+		if len(events) > 0 {
+			return network.GetPlayerEventsResponse{
+				Events: events,
+				Next:   lastID,
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return network.GetPlayerEventsResponse{
+				Events: []network.PlayerEvent{},
+				Next:   lastID,
+			}
+		case <-added:
+			after = lastID
+		}
+	}
+}
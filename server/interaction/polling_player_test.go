@@ -0,0 +1,99 @@
+package interaction
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/network"
+)
+
+func TestPollingPlayerQueuesEvents(t *testing.T) {
+	pID := model.PlayerID(`pollingAlice`)
+	p, err := FromPlayerMeans(New(pID, Means{Mode: Polling}))
+	require.NoError(t, err)
+	g := model.Game{ID: 42}
+
+	require.NoError(t, p.NotifyBlocking(model.CribCard, g, `build the crib`))
+	require.NoError(t, p.NotifyInvitation(model.Lobby{ID: 7}, `join us`))
+	require.NoError(t, p.NotifyChat(g, model.ChatMessage{PlayerID: `bob`, Message: `hi`}))
+
+	resp := PollEvents(context.Background(), pID, 0)
+	require.Len(t, resp.Events, 3)
+	assert.Equal(t, uint64(3), resp.Next)
+	assert.Equal(t, network.PlayerEvent{
+		ID:       1,
+		Event:    `blocking`,
+		GameID:   42,
+		Blocker:  model.CribCard.String(),
+		Messages: []string{`build the crib`},
+	}, resp.Events[0])
+	assert.Equal(t, network.PlayerEvent{
+		ID:       2,
+		Event:    `invitation`,
+		LobbyID:  7,
+		Messages: []string{`join us`},
+	}, resp.Events[1])
+	assert.Equal(t, `hi`, resp.Events[2].Chat.Message)
+
+	resp = PollEvents(context.Background(), pID, 2)
+	require.Len(t, resp.Events, 1)
+	assert.Equal(t, uint64(3), resp.Events[0].ID)
+}
+
+func TestPollEventsWaitsForAnEvent(t *testing.T) {
+	pID := model.PlayerID(`pollingBob`)
+	p := newPollingPlayer(pID, immediately{})
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		_ = p.NotifyMessage(model.Game{ID: 3}, `hello`)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	resp := PollEvents(ctx, pID, 0)
+	require.Len(t, resp.Events, 1)
+	assert.Equal(t, []string{`hello`}, resp.Events[0].Messages)
+	assert.NoError(t, ctx.Err())
+}
+
+func TestPollEventsTimesOut(t *testing.T) {
+	pID := model.PlayerID(`pollingCharlie`)
+	p := newPollingPlayer(pID, immediately{})
+	require.NoError(t, p.NotifyMessage(model.Game{ID: 3}, `old news`))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	resp := PollEvents(ctx, pID, 1)
+	assert.Empty(t, resp.Events)
+	assert.Equal(t, uint64(1), resp.Next)
+}
+
+func TestPollEventsAfterRestart(t *testing.T) {
+	pID := model.PlayerID(`pollingDiane`)
+	p := newPollingPlayer(pID, immediately{})
+	require.NoError(t, p.NotifyMessage(model.Game{ID: 3}, `first`))
+
+	// the player had polled a different (or older) server up to 50
+	resp := PollEvents(context.Background(), pID, 50)
+	require.Len(t, resp.Events, 1)
+	assert.Equal(t, uint64(1), resp.Next)
+}
+
+func TestPollingDropsOldEvents(t *testing.T) {
+	pID := model.PlayerID(`pollingEve`)
+	p := newPollingPlayer(pID, immediately{})
+	for i := 0; i < maxPolledEvents+10; i++ {
+		require.NoError(t, p.NotifyMessage(model.Game{ID: 3}, `spam`))
+	}
+
+	resp := PollEvents(context.Background(), pID, 0)
+	require.Len(t, resp.Events, maxPolledEvents)
+	assert.Equal(t, uint64(11), resp.Events[0].ID)
+	assert.Equal(t, uint64(maxPolledEvents+10), resp.Next)
+}
@@ -0,0 +1,78 @@
+package interaction
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/tracing"
+)
+
+var _ AutoPlayer = spannedPlayer{}
+
+// spannedPlayer records a span for each notification that is sent to the player
+type spannedPlayer struct {
+	Player
+
+	ctx   context.Context
+	attrs []attribute.KeyValue
+}
+
+// WithSpans returns the player, whose notifications are now traced as children of
+// the span in the context. Each of the spans has the given attributes.
+func WithSpans(ctx context.Context, p Player, attrs ...attribute.KeyValue) Player {
+	return spannedPlayer{
+		Player: p,
+		ctx:    ctx,
+		attrs:  attrs,
+	}
+}
+
+func (sp spannedPlayer) start(name string, attrs ...attribute.KeyValue) trace.Span {
+	_, span := tracing.Start(sp.ctx, name, append(attrs, sp.attrs...)...)
+	return span
+}
+
+func (sp spannedPlayer) AutoPlaysForcedMoves() bool {
+	return AutoPlays(sp.Player)
+}
+
+func (sp spannedPlayer) NotifyBlocking(b model.Blocker, g model.Game, s string) error {
+	span := sp.start(`interaction.NotifyBlocking`,
+		tracing.GameID(g.ID),
+		tracing.Blocker(b),
+	)
+	err := sp.Player.NotifyBlocking(b, g, s)
+	tracing.End(span, err)
+	return err
+}
+
+func (sp spannedPlayer) NotifyMessage(g model.Game, s string) error {
+	span := sp.start(`interaction.NotifyMessage`, tracing.GameID(g.ID))
+	err := sp.Player.NotifyMessage(g, s)
+	tracing.End(span, err)
+	return err
+}
+
+func (sp spannedPlayer) NotifyScoreUpdate(g model.Game, msgs ...string) error {
+	span := sp.start(`interaction.NotifyScoreUpdate`, tracing.GameID(g.ID))
+	err := sp.Player.NotifyScoreUpdate(g, msgs...)
+	tracing.End(span, err)
+	return err
+}
+
+func (sp spannedPlayer) NotifyInvitation(l model.Lobby, s string) error {
+	span := sp.start(`interaction.NotifyInvitation`, tracing.LobbyID(l.ID))
+	err := sp.Player.NotifyInvitation(l, s)
+	tracing.End(span, err)
+	return err
+}
+
+func (sp spannedPlayer) NotifyChat(g model.Game, cm model.ChatMessage) error {
+	span := sp.start(`interaction.NotifyChat`, tracing.GameID(g.ID))
+	err := sp.Player.NotifyChat(g, cm)
+	tracing.End(span, err)
+	return err
+}
@@ -29,3 +29,11 @@ func (u unimplemented) NotifyMessage(model.Game, string) error {
 func (u unimplemented) NotifyScoreUpdate(g model.Game, msgs ...string) error {
 	return nil
 }
+
+func (u unimplemented) NotifyInvitation(model.Lobby, string) error {
+	return nil
+}
+
+func (u unimplemented) NotifyChat(model.Game, model.ChatMessage) error {
+	return nil
+}
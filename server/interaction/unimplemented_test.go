@@ -16,4 +16,5 @@ func TestUnimplemented(t *testing.T) {
 	assert.Nil(t, p.NotifyBlocking(model.DealCards, model.Game{}, ``))
 	assert.Nil(t, p.NotifyMessage(model.Game{}, ``))
 	assert.Nil(t, p.NotifyScoreUpdate(model.Game{}, ``, ``))
+	assert.Nil(t, p.NotifyChat(model.Game{}, model.ChatMessage{}))
 }
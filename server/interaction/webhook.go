@@ -0,0 +1,16 @@
+package interaction
+
+import (
+	"github.com/joshprzybyszewski/cribbage/network"
+)
+
+// WebhookInfo is the info for a player who interacts through the Webhook mode
+type WebhookInfo struct {
+	URL    string `json:"url" bson:"url"`
+	Secret string `json:"secret" bson:"secret"`
+}
+
+// Validate returns an error if the server cannot send webhooks with this info
+func (wi WebhookInfo) Validate() error {
+	return network.ValidateWebhook(wi.URL, wi.Secret)
+}
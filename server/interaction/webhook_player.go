@@ -0,0 +1,162 @@
+package interaction
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/network"
+)
+
+const (
+	webhookAttempts = 3
+	webhookBackoff  = 250 * time.Millisecond
+	webhookTimeout  = 5 * time.Second
+)
+
+var _ Player = (*webhookPlayer)(nil)
+
+// webhookPlayer POSTs signed JSON payloads to a URL so that bots and
+// services don't have to run on the same host as the server
+type webhookPlayer struct {
+	pID    model.PlayerID
+	info   WebhookInfo
+	outbox Outbox
+
+	client   *http.Client
+	attempts int
+	backoff  time.Duration
+}
+
+func newWebhookPlayer(pID model.PlayerID, info interface{}, o Outbox) (*webhookPlayer, error) {
+	wi, ok := info.(WebhookInfo)
+	if !ok {
+		return nil, errors.New(`webhook player should have WebhookInfo as its info`)
+	}
+	return &webhookPlayer{
+		pID:    pID,
+		info:   wi,
+		outbox: o,
+		client: &http.Client{
+			Timeout: webhookTimeout,
+		},
+		attempts: webhookAttempts,
+		backoff:  webhookBackoff,
+	}, nil
+}
+
+func (wp *webhookPlayer) ID() model.PlayerID {
+	return wp.pID
+}
+
+func (wp *webhookPlayer) NotifyBlocking(b model.Blocker, g model.Game, s string) error {
+	p := wp.newGamePayload(network.BlockingEvent, g)
+	p.Blocker = b.String()
+	if s != `` {
+		p.Messages = []string{s}
+	}
+	return wp.send(p)
+}
+
+func (wp *webhookPlayer) NotifyMessage(g model.Game, msg string) error {
+	p := wp.newGamePayload(network.MessageEvent, g)
+	p.Messages = []string{msg}
+	return wp.send(p)
+}
+
+func (wp *webhookPlayer) NotifyScoreUpdate(g model.Game, msgs ...string) error {
+	p := wp.newGamePayload(network.ScoreUpdateEvent, g)
+	p.Messages = msgs
+	return wp.send(p)
+}
+
+func (wp *webhookPlayer) NotifyInvitation(l model.Lobby, msg string) error {
+	return wp.send(network.WebhookPayload{
+		Event:    network.InvitationEvent,
+		PlayerID: wp.pID,
+		LobbyID:  l.ID,
+		Messages: []string{msg},
+	})
+}
+
+func (wp *webhookPlayer) NotifyChat(g model.Game, cm model.ChatMessage) error {
+	p := wp.newGamePayload(network.ChatEvent, g)
+	chat := network.ConvertToChatMessage(cm)
+	p.Chat = &chat
+	return wp.send(p)
+}
+
+func (wp *webhookPlayer) newGamePayload(e network.WebhookEvent, g model.Game) network.WebhookPayload {
+	p := network.WebhookPayload{
+		Event:    e,
+		PlayerID: wp.pID,
+	}
+	// only send the parts of the game that this player is allowed to see
+	view, err := network.ConvertToGetGameResponseForPlayer(g, wp.pID)
+	if err == nil {
+		p.Game = &view
+	}
+	return p
+}
+
+// send hands the payload to the outbox, which POSTs it to the webhook once the
+// change that it's about has been saved. The body is written now, because the
+// game keeps changing after we've been notified about it.
+func (wp *webhookPlayer) send(p network.WebhookPayload) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	sig := network.SignWebhookPayload(wp.info.Secret, body)
+
+	return wp.outbox.Hold(func() error {
+		return wp.deliver(p.Event, body, sig)
+	})
+}
+
+// deliver POSTs the body to the webhook. It retries with a growing backoff
+// when the webhook couldn't be reached or had a server error.
+func (wp *webhookPlayer) deliver(e network.WebhookEvent, body []byte, sig string) error {
+	var err error
+	backoff := wp.backoff
+	var retry bool
+	for attempt := 1; ; attempt++ {
+		retry, err = wp.post(e, body, sig)
+		if err == nil || !retry || attempt >= wp.attempts {
+			return err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (wp *webhookPlayer) post(e network.WebhookEvent, body []byte, sig string) (bool, error) {
+	req, err := http.NewRequest(`POST`, wp.info.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set(`Content-Type`, `application/json`)
+	req.Header.Set(network.WebhookEventHeader, string(e))
+	req.Header.Set(network.WebhookSignatureHeader, sig)
+
+	response, err := wp.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer response.Body.Close()
+	_, _ = ioutil.ReadAll(response.Body)
+
+	switch {
+	case response.StatusCode >= 200 && response.StatusCode < 300:
+		return false, nil
+	case response.StatusCode == http.StatusTooManyRequests,
+		response.StatusCode >= 500:
+		return true, fmt.Errorf("webhook responded with %d", response.StatusCode)
+	}
+	return false, fmt.Errorf("webhook responded with %d", response.StatusCode)
+}
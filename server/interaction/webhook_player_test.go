@@ -0,0 +1,182 @@
+package interaction
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/network"
+)
+
+const testWebhookSecret = `this is a test secret`
+
+func TestWebhookInfoValidate(t *testing.T) {
+	tests := []struct {
+		desc   string
+		info   WebhookInfo
+		expErr error
+	}{{
+		desc: `valid`,
+		info: WebhookInfo{
+			URL:    `https://example.com/hook`,
+			Secret: testWebhookSecret,
+		},
+	}, {
+		desc: `relative url`,
+		info: WebhookInfo{
+			URL:    `/hook`,
+			Secret: testWebhookSecret,
+		},
+		expErr: network.ErrInvalidWebhookURL,
+	}, {
+		desc: `not http`,
+		info: WebhookInfo{
+			URL:    `ftp://example.com/hook`,
+			Secret: testWebhookSecret,
+		},
+		expErr: network.ErrInvalidWebhookURL,
+	}, {
+		desc: `short secret`,
+		info: WebhookInfo{
+			URL:    `http://example.com/hook`,
+			Secret: `password`,
+		},
+		expErr: network.ErrInvalidWebhookSecret,
+	}}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.expErr, tc.info.Validate(), tc.desc)
+	}
+}
+
+type webhookRequest struct {
+	event   string
+	payload network.WebhookPayload
+}
+
+func newTestWebhook(t *testing.T, statuses ...int) (*webhookPlayer, func() []webhookRequest) {
+	var lock sync.Mutex
+	var received []webhookRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.True(t, network.VerifyWebhookSignature(testWebhookSecret, body, r.Header.Get(network.WebhookSignatureHeader)))
+
+		var p network.WebhookPayload
+		require.NoError(t, json.Unmarshal(body, &p))
+
+		lock.Lock()
+		defer lock.Unlock()
+		received = append(received, webhookRequest{
+			event:   r.Header.Get(network.WebhookEventHeader),
+			payload: p,
+		})
+		status := http.StatusOK
+		if len(received) <= len(statuses) {
+			status = statuses[len(received)-1]
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(ts.Close)
+
+	pAPI, err := FromPlayerMeans(New(`alice`, Means{
+		Mode: Webhook,
+		Info: WebhookInfo{
+			URL:    ts.URL,
+			Secret: testWebhookSecret,
+		},
+	}))
+	require.NoError(t, err)
+	wp, ok := pAPI.(*webhookPlayer)
+	require.True(t, ok)
+	wp.backoff = time.Millisecond
+
+	return wp, func() []webhookRequest {
+		lock.Lock()
+		defer lock.Unlock()
+		return append([]webhookRequest(nil), received...)
+	}
+}
+
+func TestWebhookPlayerOnlySendsWhatThePlayerCanSee(t *testing.T) {
+	wp, received := newTestWebhook(t)
+
+	g := model.Game{
+		ID: model.GameID(42),
+		Players: []model.Player{{
+			ID:   `alice`,
+			Name: `alice`,
+		}, {
+			ID:   `bob`,
+			Name: `bob`,
+		}},
+		Phase: model.BuildCrib,
+		Hands: map[model.PlayerID][]model.Card{
+			`alice`: {model.NewCardFromString(`ah`)},
+			`bob`:   {model.NewCardFromString(`as`)},
+		},
+	}
+	require.NoError(t, wp.NotifyBlocking(model.CribCard, g, `your turn`))
+
+	reqs := received()
+	require.Len(t, reqs, 1)
+	assert.Equal(t, `blocking`, reqs[0].event)
+	p := reqs[0].payload
+	assert.Equal(t, network.BlockingEvent, p.Event)
+	assert.Equal(t, model.PlayerID(`alice`), p.PlayerID)
+	assert.Equal(t, `AddToCrib`, p.Blocker)
+	assert.Equal(t, []string{`your turn`}, p.Messages)
+	require.NotNil(t, p.Game)
+	assert.Equal(t, `AH`, p.Game.Hands[`alice`][0].Name)
+	assert.Equal(t, `unknown`, p.Game.Hands[`bob`][0].Name)
+}
+
+func TestWebhookPlayerRetries(t *testing.T) {
+	tests := []struct {
+		desc        string
+		statuses    []int
+		expAttempts int
+		expErr      bool
+	}{{
+		desc:        `recovers from a server error`,
+		statuses:    []int{http.StatusBadGateway},
+		expAttempts: 2,
+	}, {
+		desc:        `waits when it is rate limited`,
+		statuses:    []int{http.StatusTooManyRequests, http.StatusServiceUnavailable},
+		expAttempts: 3,
+	}, {
+		desc:        `gives up eventually`,
+		statuses:    []int{500, 500, 500, 500},
+		expAttempts: webhookAttempts,
+		expErr:      true,
+	}, {
+		desc:        `does not retry a bad request`,
+		statuses:    []int{http.StatusBadRequest},
+		expAttempts: 1,
+		expErr:      true,
+	}}
+
+	for _, tc := range tests {
+		wp, received := newTestWebhook(t, tc.statuses...)
+		err := wp.NotifyInvitation(model.Lobby{ID: 7}, `bob invited you`)
+		if tc.expErr {
+			assert.Error(t, err, tc.desc)
+		} else {
+			assert.NoError(t, err, tc.desc)
+		}
+		reqs := received()
+		assert.Len(t, reqs, tc.expAttempts, tc.desc)
+		for _, r := range reqs {
+			assert.Equal(t, model.LobbyID(7), r.payload.LobbyID, tc.desc)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/interaction"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+// createInvitation opens a private lobby for the host and the players they invited.
+// The game does not start until every invitee has accepted. NPCs accept right away.
+func createInvitation(
+	ctx context.Context,
+	db persistence.DB,
+	host model.PlayerID,
+	invitees []model.PlayerID,
+	settings model.GameSettings,
+) (_ model.Lobby, err error) {
+	err = db.Start(ctx)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+	defer commitOrRollback(ctx, db, &err)
+
+	hp, err := db.GetPlayer(ctx, host)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+
+	l, err := newLobby(lobbyOptions{
+		host:       host,
+		numPlayers: len(invitees) + 1,
+		settings:   settings,
+	})
+	if err != nil {
+		return model.Lobby{}, err
+	}
+
+	invited := make([]model.Player, 0, len(invitees))
+	for _, pID := range invitees {
+		if pID == host || l.IsInvited(pID) {
+			err = model.ErrAlreadyInLobby
+			return model.Lobby{}, err
+		}
+
+		var p model.Player
+		p, err = db.GetPlayer(ctx, pID)
+		if err != nil {
+			if err == persistence.ErrPlayerNotFound {
+				// tell them which player doesn't exist so that they can fix the typo
+				err = fmt.Errorf("%w: %s", err, pID)
+			}
+			return model.Lobby{}, err
+		}
+
+		l.Invited = append(l.Invited, pID)
+		if interaction.IsNPC(pID) {
+			err = l.Sit(pID)
+			if err != nil {
+				return model.Lobby{}, err
+			}
+			continue
+		}
+		invited = append(invited, p)
+	}
+
+	err = db.CreateLobby(ctx, l)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+
+	err = startIfFull(ctx, db, &l)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+
+	pAPIs, err := getPlayerAPIs(ctx, db, invited)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+	msg := fmt.Sprintf("%s invited you to a %d player game", hp.Name, l.NumPlayers)
+	for _, p := range invited {
+		_ = pAPIs[p.ID].NotifyInvitation(l, msg)
+	}
+
+	return l, nil
+}
+
+// acceptInvitation seats the invited player. When the last invitee accepts,
+// the game starts and everyone is told about it.
+func acceptInvitation(
+	ctx context.Context,
+	db persistence.DB,
+	lID model.LobbyID,
+	pID model.PlayerID,
+) (model.Lobby, error) {
+	return retryLobbyConflicts(func() (model.Lobby, error) {
+		return tryAcceptInvitation(ctx, db, lID, pID)
+	})
+}
+
+func tryAcceptInvitation(
+	ctx context.Context,
+	db persistence.DB,
+	lID model.LobbyID,
+	pID model.PlayerID,
+) (_ model.Lobby, err error) {
+	err = db.Start(ctx)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+	defer commitOrRollback(ctx, db, &err)
+
+	l, err := db.GetLobby(ctx, lID)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+
+	if !l.IsInvited(pID) {
+		err = model.ErrNotInvited
+		return model.Lobby{}, err
+	}
+
+	err = l.Sit(pID)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+
+	err = saveAndStartIfFull(ctx, db, &l)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+
+	if l.Status == model.LobbyStarted {
+		var g model.Game
+		g, err = db.GetGame(ctx, l.GameID)
+		if err != nil {
+			return model.Lobby{}, err
+		}
+		err = notifyLobby(ctx, db, l, func(p interaction.Player) error {
+			return p.NotifyMessage(g, `Everyone accepted. The game has started!`)
+		})
+		if err != nil {
+			return model.Lobby{}, err
+		}
+	}
+
+	return l, nil
+}
+
+// declineInvitation cancels the invitation for everyone, since
+// the game cannot start without the player who declined.
+func declineInvitation(
+	ctx context.Context,
+	db persistence.DB,
+	lID model.LobbyID,
+	pID model.PlayerID,
+) (_ model.Lobby, err error) {
+	err = db.Start(ctx)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+	defer commitOrRollback(ctx, db, &err)
+
+	p, err := db.GetPlayer(ctx, pID)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+
+	l, err := db.GetLobby(ctx, lID)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+
+	err = l.Decline(pID)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+
+	err = saveLobby(ctx, db, &l)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+
+	msg := fmt.Sprintf("%s declined the invitation", p.Name)
+	err = notifyLobby(ctx, db, l, func(p interaction.Player) error {
+		return p.NotifyInvitation(l, msg)
+	})
+	if err != nil {
+		return model.Lobby{}, err
+	}
+
+	return l, nil
+}
+
+// getInvitations returns the open lobbies which are waiting on the player to respond
+func getInvitations(ctx context.Context, db persistence.DB, pID model.PlayerID) ([]model.Lobby, error) {
+	open, err := db.GetOpenLobbies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations := make([]model.Lobby, 0, len(open))
+	for _, l := range open {
+		if l.IsInvited(pID) && !l.IsSeated(pID) {
+			invitations = append(invitations, l)
+		}
+	}
+	return invitations, nil
+}
+
+// notifyLobby calls notify for every player who is seated in the lobby
+func notifyLobby(ctx context.Context, db persistence.DB, l model.Lobby, notify func(interaction.Player) error) error {
+	players := make([]model.Player, len(l.Seated))
+	for i, pID := range l.Seated {
+		p, err := db.GetPlayer(ctx, pID)
+		if err != nil {
+			return err
+		}
+		players[i] = p
+	}
+
+	pAPIs, err := getPlayerAPIs(ctx, db, players)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range players {
+		_ = notify(pAPIs[p.ID])
+	}
+	return nil
+}
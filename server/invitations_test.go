@@ -0,0 +1,281 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/network"
+	"github.com/joshprzybyszewski/cribbage/server/interaction"
+)
+
+type notification struct {
+	path string
+	msg  string
+}
+
+// listenAsLocalhost registers a localhost interaction for the player and returns
+// a func that gets all of the notifications the player has received
+func listenAsLocalhost(t *testing.T, cs *cribbageServer, pID model.PlayerID) func() []notification {
+	var lock sync.Mutex
+	var received []notification
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bs, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		lock.Lock()
+		defer lock.Unlock()
+		received = append(received, notification{
+			path: r.URL.Path,
+			msg:  string(bs),
+		})
+	}))
+	t.Cleanup(ts.Close)
+
+	u, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+
+	db, err := cs.dbFactory.New(context.Background())
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, db.SaveInteraction(context.Background(), interaction.New(pID, interaction.Means{
+		Mode: interaction.Localhost,
+		Info: u.Port(),
+	})))
+
+	return func() []notification {
+		lock.Lock()
+		defer lock.Unlock()
+		return append([]notification(nil), received...)
+	}
+}
+
+func postInvitation(t *testing.T, router http.Handler, req network.CreateInvitationRequest) network.Lobby {
+	w, err := performRequest(router, `POST`, `/create/invitation`, prepareBody(t, req))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, w.Code)
+	var l network.Lobby
+	readBody(t, w.Body, &l)
+	return l
+}
+
+func TestGinPostCreateInvitation(t *testing.T) {
+	testCases := []struct {
+		msg     string
+		req     network.CreateInvitationRequest
+		expCode int
+		expErr  string
+	}{{
+		msg: `no invitees`,
+		req: network.CreateInvitationRequest{
+			Host: `p1`,
+		},
+		expCode: http.StatusBadRequest,
+		expErr:  `Error: invalid number of players`,
+	}, {
+		msg: `too many invitees`,
+		req: network.CreateInvitationRequest{
+			Host:     `p1`,
+			Invitees: []model.PlayerID{`p2`, `p3`, `p4`, `p5`},
+		},
+		expCode: http.StatusBadRequest,
+		expErr:  `Error: invalid number of players`,
+	}, {
+		msg: `missing invitee`,
+		req: network.CreateInvitationRequest{
+			Host:     `p1`,
+			Invitees: []model.PlayerID{`p2`, ``},
+		},
+		expCode: http.StatusBadRequest,
+		expErr:  `Invalid player ID at index 1`,
+	}, {
+		msg: `invited twice`,
+		req: network.CreateInvitationRequest{
+			Host:     `p1`,
+			Invitees: []model.PlayerID{`p2`, `p2`},
+		},
+		expCode: http.StatusBadRequest,
+		expErr:  `Error: player is already in the lobby`,
+	}, {
+		msg: `a typo says which player is wrong`,
+		req: network.CreateInvitationRequest{
+			Host:     `p1`,
+			Invitees: []model.PlayerID{`p2`, `p22`},
+		},
+		expCode: http.StatusNotFound,
+		expErr:  `Error: player not found: p22`,
+	}, {
+		msg: `unknown host`,
+		req: network.CreateInvitationRequest{
+			Host:     `p9`,
+			Invitees: []model.PlayerID{`p2`},
+		},
+		expCode: http.StatusNotFound,
+		expErr:  `Player not found`,
+	}}
+
+	cs, router := newServerAndRouter(t)
+	seedPlayers(t, cs.dbFactory, 5)
+	for _, tc := range testCases {
+		w, err := performRequest(router, `POST`, `/create/invitation`, prepareBody(t, tc.req))
+		require.NoError(t, err, tc.msg)
+		assert.Equal(t, tc.expCode, w.Code, tc.msg)
+		assert.Equal(t, tc.expErr, readError(t, w), tc.msg)
+	}
+
+	// none of those should have left a lobby around
+	w, err := performRequest(router, `GET`, `/lobbies/invitations?playerID=p2`, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, w.Code)
+	var invs network.GetOpenLobbiesResponse
+	readBody(t, w.Body, &invs)
+	assert.Empty(t, invs.Lobbies)
+}
+
+func TestInvitationStartsAfterEveryoneAccepts(t *testing.T) {
+	cs, router := newServerAndRouter(t)
+	pIDs := seedPlayers(t, cs.dbFactory, 3)
+	bobHeard := listenAsLocalhost(t, cs, pIDs[1])
+	charlieHeard := listenAsLocalhost(t, cs, pIDs[2])
+
+	l := postInvitation(t, router, network.CreateInvitationRequest{
+		Host:     pIDs[0],
+		Invitees: []model.PlayerID{pIDs[1], pIDs[2]},
+	})
+	assert.Equal(t, `open`, l.Status)
+	assert.Equal(t, []model.PlayerID{pIDs[0]}, l.Seated)
+	assert.Equal(t, []model.PlayerID{pIDs[1], pIDs[2]}, l.Pending)
+
+	invitationPath := fmt.Sprintf(`/invitation/%d`, l.ID)
+	for _, heard := range [][]notification{bobHeard(), charlieHeard()} {
+		assert.Equal(t, []notification{{
+			path: invitationPath,
+			msg:  `name invited you to a 3 player game`,
+		}}, heard)
+	}
+
+	// invitations are private
+	w, err := performRequest(router, `GET`, `/lobbies/open`, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, w.Code)
+	var open network.GetOpenLobbiesResponse
+	readBody(t, w.Body, &open)
+	assert.Empty(t, open.Lobbies)
+
+	w, err = performRequest(router, `GET`, `/lobbies/invitations?playerID=p2`, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, w.Code)
+	var invs network.GetOpenLobbiesResponse
+	readBody(t, w.Body, &invs)
+	require.Len(t, invs.Lobbies, 1)
+	assert.Equal(t, l.ID, invs.Lobbies[0].ID)
+
+	accept := func(pID model.PlayerID) *httptest.ResponseRecorder {
+		w, err := performRequest(
+			router, `POST`, fmt.Sprintf(`/lobby/%d/accept`, l.ID),
+			prepareBody(t, network.LobbyPlayerRequest{PlayerID: pID}),
+		)
+		require.NoError(t, err)
+		return w
+	}
+
+	w = accept(`p4`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, `Error: player was not invited`, readError(t, w))
+
+	w = accept(pIDs[2])
+	require.Equal(t, http.StatusOK, w.Code)
+	readBody(t, w.Body, &l)
+	assert.Equal(t, `open`, l.Status)
+	assert.Equal(t, model.InvalidGameID, l.GameID)
+	assert.Equal(t, []model.PlayerID{pIDs[1]}, l.Pending)
+
+	w = accept(pIDs[1])
+	require.Equal(t, http.StatusOK, w.Code)
+	l = network.Lobby{}
+	readBody(t, w.Body, &l)
+	assert.Equal(t, `started`, l.Status)
+	require.NotEqual(t, model.InvalidGameID, l.GameID)
+	assert.Empty(t, l.Pending)
+
+	gameMsg := notification{
+		path: fmt.Sprintf(`/message/%d`, l.GameID),
+		msg:  `Everyone accepted. The game has started!`,
+	}
+	assert.Contains(t, bobHeard(), gameMsg)
+	assert.Contains(t, charlieHeard(), gameMsg)
+
+	db, err := cs.dbFactory.New(context.Background())
+	require.NoError(t, err)
+	defer db.Close()
+	g, err := getGame(context.Background(), db, l.GameID)
+	require.NoError(t, err)
+	require.Len(t, g.Players, 3)
+	// the players sit in the order that they accepted
+	assert.Equal(t, pIDs[0], g.Players[0].ID)
+	assert.Equal(t, pIDs[2], g.Players[1].ID)
+	assert.Equal(t, pIDs[1], g.Players[2].ID)
+}
+
+func TestInvitationDeclined(t *testing.T) {
+	cs, router := newServerAndRouter(t)
+	pIDs := seedPlayers(t, cs.dbFactory, 3)
+	aliceHeard := listenAsLocalhost(t, cs, pIDs[0])
+
+	l := postInvitation(t, router, network.CreateInvitationRequest{
+		Host:     pIDs[0],
+		Invitees: []model.PlayerID{pIDs[1], pIDs[2]},
+	})
+
+	w, err := performRequest(
+		router, `POST`, fmt.Sprintf(`/lobby/%d/decline`, l.ID),
+		prepareBody(t, network.LobbyPlayerRequest{PlayerID: pIDs[1]}),
+	)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, w.Code)
+	readBody(t, w.Body, &l)
+	assert.Equal(t, `declined`, l.Status)
+	assert.Equal(t, []notification{{
+		path: fmt.Sprintf(`/invitation/%d`, l.ID),
+		msg:  `name declined the invitation`,
+	}}, aliceHeard())
+
+	// nobody can accept an invitation that was declined
+	w, err = performRequest(
+		router, `POST`, fmt.Sprintf(`/lobby/%d/accept`, l.ID),
+		prepareBody(t, network.LobbyPlayerRequest{PlayerID: pIDs[2]}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, `Error: lobby is not open`, readError(t, w))
+}
+
+func TestInvitationNPCsAccept(t *testing.T) {
+	cs, router := newServerAndRouter(t)
+	pIDs := seedPlayers(t, cs.dbFactory, 2)
+	seedNPCPlayers(t, cs.dbFactory)
+
+	l := postInvitation(t, router, network.CreateInvitationRequest{
+		Host:     pIDs[0],
+		Invitees: []model.PlayerID{interaction.Calc},
+	})
+	assert.Equal(t, `started`, l.Status)
+	assert.NotEqual(t, model.InvalidGameID, l.GameID)
+
+	l = postInvitation(t, router, network.CreateInvitationRequest{
+		Host:     pIDs[0],
+		Invitees: []model.PlayerID{interaction.Simple, pIDs[1]},
+	})
+	assert.Equal(t, `open`, l.Status)
+	assert.Equal(t, []model.PlayerID{pIDs[0], interaction.Simple}, l.Seated)
+	assert.Equal(t, []model.PlayerID{pIDs[1]}, l.Pending)
+}
@@ -2,12 +2,38 @@ package server
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
 )
 
+var (
+	// lambdaDBFactory is shared by the invocations in a lambda's container, so that
+	// they reuse its connections to the database instead of opening more each time
+	lambdaDBFactoryLock sync.Mutex
+	lambdaDBFactory     persistence.DBFactory
+)
+
+// getLambdaDBFactory returns the container's factory. It connects to the database
+// the first time that it is needed, or again if that failed.
+func getLambdaDBFactory(ctx context.Context) (persistence.DBFactory, error) {
+	lambdaDBFactoryLock.Lock()
+	defer lambdaDBFactoryLock.Unlock()
+
+	if lambdaDBFactory == nil {
+		dbf, err := getDBFactory(ctx, factoryConfig{})
+		if err != nil {
+			return nil, err
+		}
+		lambdaDBFactory = dbf
+	}
+	return lambdaDBFactory, nil
+}
+
 func HandleAction(ctx context.Context, action model.PlayerAction) (err error) {
-	dbf, err := getDBFactory(ctx, factoryConfig{})
+	dbf, err := getLambdaDBFactory(ctx)
 	if err != nil {
 		return err
 	}
@@ -17,11 +43,11 @@ func HandleAction(ctx context.Context, action model.PlayerAction) (err error) {
 	}
 	defer db.Close()
 
-	return handleAction(ctx, db, action)
+	return handlePlayerAction(ctx, db, action)
 }
 
-func CreateGame(ctx context.Context, pIDs []model.PlayerID) (model.Game, error) {
-	dbf, err := getDBFactory(ctx, factoryConfig{})
+func CreateGame(ctx context.Context, pIDs []model.PlayerID, settings model.GameSettings) (model.Game, error) {
+	dbf, err := getLambdaDBFactory(ctx)
 	if err != nil {
 		return model.Game{}, err
 	}
@@ -31,11 +57,11 @@ func CreateGame(ctx context.Context, pIDs []model.PlayerID) (model.Game, error)
 	}
 	defer db.Close()
 
-	return createGame(ctx, db, pIDs)
+	return createGame(ctx, db, pIDs, settings)
 }
 
 func GetGame(ctx context.Context, gID model.GameID) (model.Game, error) {
-	dbf, err := getDBFactory(ctx, factoryConfig{})
+	dbf, err := getLambdaDBFactory(ctx)
 	if err != nil {
 		return model.Game{}, err
 	}
@@ -49,7 +75,7 @@ func GetGame(ctx context.Context, gID model.GameID) (model.Game, error) {
 }
 
 func GetPlayer(ctx context.Context, pID model.PlayerID) (model.Player, error) {
-	dbf, err := getDBFactory(ctx, factoryConfig{})
+	dbf, err := getLambdaDBFactory(ctx)
 	if err != nil {
 		return model.Player{}, err
 	}
@@ -61,3 +87,32 @@ func GetPlayer(ctx context.Context, pID model.PlayerID) (model.Player, error) {
 
 	return getPlayer(ctx, db, pID)
 }
+
+func SendChat(ctx context.Context, cm model.ChatMessage) error {
+	dbf, err := getLambdaDBFactory(ctx)
+	if err != nil {
+		return err
+	}
+	db, err := dbf.New(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return sendChat(ctx, db, cm)
+}
+
+// EnforceMoveTimeouts acts on every game whose players have taken longer than its
+// MoveTimeout. It is meant to be invoked on a schedule, where there's no server
+// running the background job.
+func EnforceMoveTimeouts(ctx context.Context) error {
+	dbf, err := getLambdaDBFactory(ctx)
+	if err != nil {
+		return err
+	}
+
+	newTimeoutScheduler(dbf, 0).handleExpired(ctx, time.Now())
+	// the lambda can be frozen once we return, so the reminders need to go out first
+	notifications.wait(ctx)
+	return nil
+}
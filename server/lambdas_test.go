@@ -0,0 +1,34 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+	"github.com/joshprzybyszewski/cribbage/server/persistence/memory"
+)
+
+func TestLambdasShareTheirDBFactory(t *testing.T) {
+	memory.Clear()
+	prevDatabase := *database
+	*database = `memory`
+	t.Cleanup(func() {
+		*database = prevDatabase
+		lambdaDBFactory = nil
+		memory.Clear()
+	})
+
+	ctx := context.Background()
+	_, err := GetPlayer(ctx, model.PlayerID(`alice`))
+	assert.Equal(t, persistence.ErrPlayerNotFound, err)
+	dbf := lambdaDBFactory
+	require.NotNil(t, dbf)
+
+	_, err = GetGame(ctx, model.GameID(5))
+	assert.Equal(t, persistence.ErrGameNotFound, err)
+	assert.Same(t, dbf, lambdaDBFactory, `the second invocation reuses the factory`)
+}
@@ -0,0 +1,423 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/interaction"
+	"github.com/joshprzybyszewski/cribbage/server/logging"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+type lobbyOptions struct {
+	host       model.PlayerID
+	numPlayers int
+	npcFill    model.PlayerID
+	fillAfter  time.Duration
+	settings   model.GameSettings
+}
+
+func newLobby(opts lobbyOptions) (model.Lobby, error) {
+	if opts.numPlayers < model.MinPlayerGame || opts.numPlayers > model.MaxPlayerGame {
+		return model.Lobby{}, model.ErrInvalidNumPlayers
+	}
+
+	now := time.Now()
+	l := model.Lobby{
+		ID:         model.NewLobbyID(),
+		Host:       opts.host,
+		NumPlayers: opts.numPlayers,
+		Status:     model.LobbyOpen,
+		NPCFill:    opts.npcFill,
+		Settings:   opts.settings,
+		Created:    now,
+	}
+	if opts.npcFill != model.InvalidPlayerID {
+		l.FillAt = now.Add(opts.fillAfter)
+	}
+
+	err := l.Sit(opts.host)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+
+	return l, nil
+}
+
+func createLobby(ctx context.Context, db persistence.DB, opts lobbyOptions) (_ model.Lobby, err error) {
+	err = db.Start(ctx)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+	defer commitOrRollback(ctx, db, &err)
+
+	_, err = db.GetPlayer(ctx, opts.host)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+
+	l, err := newLobby(opts)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+
+	err = db.CreateLobby(ctx, l)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+
+	return l, nil
+}
+
+func getLobby(ctx context.Context, db persistence.DB, lID model.LobbyID) (model.Lobby, error) {
+	return db.GetLobby(ctx, lID)
+}
+
+// getOpenLobbies returns the public lobbies that anyone can join. If numPlayers
+// is set, it only returns the lobbies for that size of game.
+func getOpenLobbies(ctx context.Context, db persistence.DB, numPlayers int) ([]model.Lobby, error) {
+	open, err := db.GetOpenLobbies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lobbies := make([]model.Lobby, 0, len(open))
+	for _, l := range open {
+		if l.IsPrivate() {
+			continue
+		}
+		if numPlayers == 0 || l.NumPlayers == numPlayers {
+			lobbies = append(lobbies, l)
+		}
+	}
+	return lobbies, nil
+}
+
+func joinLobby(
+	ctx context.Context,
+	db persistence.DB,
+	lID model.LobbyID,
+	pID model.PlayerID,
+) (model.Lobby, error) {
+	return retryLobbyConflicts(func() (model.Lobby, error) {
+		return tryJoinLobby(ctx, db, lID, pID)
+	})
+}
+
+func tryJoinLobby(
+	ctx context.Context,
+	db persistence.DB,
+	lID model.LobbyID,
+	pID model.PlayerID,
+) (_ model.Lobby, err error) {
+	err = db.Start(ctx)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+	defer commitOrRollback(ctx, db, &err)
+
+	_, err = db.GetPlayer(ctx, pID)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+
+	l, err := db.GetLobby(ctx, lID)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+
+	err = l.Sit(pID)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+
+	err = saveAndStartIfFull(ctx, db, &l)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+
+	return l, nil
+}
+
+func leaveLobby(
+	ctx context.Context,
+	db persistence.DB,
+	lID model.LobbyID,
+	pID model.PlayerID,
+) (model.Lobby, error) {
+	return retryLobbyConflicts(func() (model.Lobby, error) {
+		return tryLeaveLobby(ctx, db, lID, pID)
+	})
+}
+
+func tryLeaveLobby(
+	ctx context.Context,
+	db persistence.DB,
+	lID model.LobbyID,
+	pID model.PlayerID,
+) (_ model.Lobby, err error) {
+	err = db.Start(ctx)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+	defer commitOrRollback(ctx, db, &err)
+
+	l, err := db.GetLobby(ctx, lID)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+
+	err = l.Leave(pID)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+
+	err = saveLobby(ctx, db, &l)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+
+	return l, nil
+}
+
+// matchLobby seats the player in the oldest open lobby for the number of players
+// they want. If there isn't one, it opens a new lobby for others to join.
+func matchLobby(ctx context.Context, db persistence.DB, opts lobbyOptions) (model.Lobby, error) {
+	return retryLobbyConflicts(func() (model.Lobby, error) {
+		return tryMatchLobby(ctx, db, opts)
+	})
+}
+
+func tryMatchLobby(ctx context.Context, db persistence.DB, opts lobbyOptions) (_ model.Lobby, err error) {
+	err = db.Start(ctx)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+	defer commitOrRollback(ctx, db, &err)
+
+	_, err = db.GetPlayer(ctx, opts.host)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+
+	open, err := getOpenLobbies(ctx, db, opts.numPlayers)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+
+	for _, l := range open {
+		if l.IsFull() || l.IsSeated(opts.host) {
+			continue
+		}
+
+		err = l.Sit(opts.host)
+		if err != nil {
+			return model.Lobby{}, err
+		}
+
+		err = saveAndStartIfFull(ctx, db, &l)
+		if err != nil {
+			return model.Lobby{}, err
+		}
+		return l, nil
+	}
+
+	l, err := newLobby(opts)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+
+	err = db.CreateLobby(ctx, l)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+
+	return l, nil
+}
+
+// fillLobby seats NPCs in the empty seats of the lobby if it has waited long enough
+func fillLobby(ctx context.Context, db persistence.DB, lID model.LobbyID, now time.Time) (model.Lobby, error) {
+	return retryLobbyConflicts(func() (model.Lobby, error) {
+		return tryFillLobby(ctx, db, lID, now)
+	})
+}
+
+func tryFillLobby(ctx context.Context, db persistence.DB, lID model.LobbyID, now time.Time) (_ model.Lobby, err error) {
+	err = db.Start(ctx)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+	defer commitOrRollback(ctx, db, &err)
+
+	l, err := db.GetLobby(ctx, lID)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+
+	if !l.ShouldFill(now) {
+		return l, nil
+	}
+
+	// The same player cannot sit at a table twice, so if we need more than
+	// one NPC, we seat the other types of NPCs after the requested one.
+	npcs := []model.PlayerID{
+		l.NPCFill,
+		interaction.Calc,
+		interaction.Simple,
+		interaction.Dumb,
+	}
+	for _, npcID := range npcs {
+		if l.IsFull() {
+			break
+		}
+		if l.IsSeated(npcID) {
+			continue
+		}
+
+		err = l.Sit(npcID)
+		if err != nil {
+			return model.Lobby{}, err
+		}
+	}
+
+	err = saveAndStartIfFull(ctx, db, &l)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+
+	return l, nil
+}
+
+// saveAndStartIfFull saves the lobby, and then creates the game for it once all of
+// the seats are taken. Saving first means that only one of the players who
+// take the last seat at the same time gets to start the game.
+// It expects that the caller has already started a transaction.
+func saveAndStartIfFull(ctx context.Context, db persistence.DB, l *model.Lobby) error {
+	err := saveLobby(ctx, db, l)
+	if err != nil {
+		return err
+	}
+
+	return startIfFull(ctx, db, l)
+}
+
+// startIfFull creates the game for the saved lobby once all of the seats are taken.
+// It expects that the caller has already started a transaction.
+func startIfFull(ctx context.Context, db persistence.DB, l *model.Lobby) error {
+	if !l.IsFull() {
+		return nil
+	}
+
+	g, err := startGame(ctx, db, l.Seated, l.Settings)
+	if err != nil {
+		return err
+	}
+
+	l.Status = model.LobbyStarted
+	l.GameID = g.ID
+	return saveLobby(ctx, db, l)
+}
+
+// saveLobby saves the lobby over the version that was read. If somebody else saved
+// it in the meantime, it returns persistence.ErrLobbyConflict.
+func saveLobby(ctx context.Context, db persistence.DB, l *model.Lobby) error {
+	err := db.SaveLobby(ctx, *l)
+	if err != nil {
+		return err
+	}
+
+	l.Version++
+	return nil
+}
+
+const (
+	// maxLobbyAttempts is how many times we try a change to a lobby
+	// when other players keep changing it first
+	maxLobbyAttempts = 3
+)
+
+// retryLobbyConflicts reads and changes the lobby again when somebody else changed
+// it between us reading it and saving it.
+func retryLobbyConflicts(change func() (model.Lobby, error)) (model.Lobby, error) {
+	var l model.Lobby
+	var err error
+	for i := 0; i < maxLobbyAttempts; i++ {
+		l, err = change()
+		if err != persistence.ErrLobbyConflict {
+			return l, err
+		}
+	}
+	return l, err
+}
+
+// lobbyFiller is a background job which fills the empty seats of lobbies
+// with NPCs once they have waited long enough. It is nil unless it has been enabled.
+var lobbyFiller *filler
+
+type filler struct {
+	dbFactory persistence.DBFactory
+	period    time.Duration
+
+	lock sync.Mutex
+}
+
+func newFiller(
+	dbFactory persistence.DBFactory,
+	period time.Duration,
+) *filler {
+	return &filler{
+		dbFactory: dbFactory,
+		period:    period,
+	}
+}
+
+// run periodically fills the lobbies that are ready until the context is done.
+func (f *filler) run(ctx context.Context) {
+	t := time.NewTicker(f.period)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-t.C:
+			err := f.fillReady(ctx, now)
+			if err != nil {
+				logging.L().Error(`Could not fill lobbies`, zap.Error(err))
+			}
+		}
+	}
+}
+
+func (f *filler) fillReady(ctx context.Context, now time.Time) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	db, err := f.dbFactory.New(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	open, err := getOpenLobbies(ctx, db, 0)
+	if err != nil {
+		return err
+	}
+
+	for _, l := range open {
+		if !l.ShouldFill(now) {
+			continue
+		}
+
+		_, err = fillLobby(ctx, db, l.ID, now)
+		if err != nil {
+			logging.L().Error(`Could not fill lobby`, logging.LobbyID(l.ID), zap.Error(err))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,335 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/network"
+	"github.com/joshprzybyszewski/cribbage/server/interaction"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+// POST /create/lobby
+func (cs *cribbageServer) ginPostCreateLobby(c *gin.Context) {
+	var clr network.CreateLobbyRequest
+	err := c.ShouldBindJSON(&clr)
+	if err != nil {
+		c.String(http.StatusBadRequest, `Error: %s`, err)
+		return
+	}
+	if clr.Host == model.InvalidPlayerID {
+		c.String(http.StatusBadRequest, `Requires host`)
+		return
+	}
+
+	opts, err := getLobbyOptions(clr.Host, clr.NumPlayers, clr.NPCFill, clr.FillAfter)
+	if err != nil {
+		c.String(http.StatusBadRequest, `Error: %s`, err)
+		return
+	}
+	opts.settings, err = network.ConvertFromGameSettings(clr.Settings)
+	if err != nil {
+		c.String(http.StatusBadRequest, `Invalid settings: %s`, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	db, err := cs.dbFactory.New(ctx)
+	if err != nil {
+		c.String(http.StatusInternalServerError, `dbFactory.New() error: %s`, err)
+		return
+	}
+	defer db.Close()
+
+	l, err := createLobby(ctx, db, opts)
+	if err != nil {
+		writeLobbyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, network.ConvertToLobby(l))
+}
+
+// POST /create/invitation
+func (cs *cribbageServer) ginPostCreateInvitation(c *gin.Context) {
+	var cir network.CreateInvitationRequest
+	err := c.ShouldBindJSON(&cir)
+	if err != nil {
+		c.String(http.StatusBadRequest, `Error: %s`, err)
+		return
+	}
+	if cir.Host == model.InvalidPlayerID {
+		c.String(http.StatusBadRequest, `Requires host`)
+		return
+	}
+	for i, pID := range cir.Invitees {
+		if pID == model.InvalidPlayerID {
+			c.String(http.StatusBadRequest, `Invalid player ID at index %d`, i)
+			return
+		}
+	}
+
+	settings, err := network.ConvertFromGameSettings(cir.Settings)
+	if err != nil {
+		c.String(http.StatusBadRequest, `Invalid settings: %s`, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	db, err := cs.dbFactory.New(ctx)
+	if err != nil {
+		c.String(http.StatusInternalServerError, `dbFactory.New() error: %s`, err)
+		return
+	}
+	defer db.Close()
+
+	l, err := createInvitation(ctx, db, cir.Host, cir.Invitees, settings)
+	if err != nil {
+		writeLobbyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, network.ConvertToLobby(l))
+}
+
+// GET /lobby/:lobbyID
+func (cs *cribbageServer) ginGetLobby(c *gin.Context) {
+	lID, err := getLobbyIDFromContext(c)
+	if err != nil {
+		c.String(http.StatusBadRequest, `Invalid LobbyID: %v`, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	db, err := cs.dbFactory.New(ctx)
+	if err != nil {
+		c.String(http.StatusInternalServerError, `dbFactory.New() error: %s`, err)
+		return
+	}
+	defer db.Close()
+
+	l, err := getLobby(ctx, db, lID)
+	if err != nil {
+		writeLobbyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, network.ConvertToLobby(l))
+}
+
+// GET /lobbies/open?num_players=n
+func (cs *cribbageServer) ginGetOpenLobbies(c *gin.Context) {
+	numPlayers := 0
+	if np := c.Query(`num_players`); np != `` {
+		n, err := strconv.Atoi(np)
+		if err != nil {
+			c.String(http.StatusBadRequest, `Invalid num_players: %v`, err)
+			return
+		}
+		numPlayers = n
+	}
+
+	ctx := c.Request.Context()
+	db, err := cs.dbFactory.New(ctx)
+	if err != nil {
+		c.String(http.StatusInternalServerError, `dbFactory.New() error: %s`, err)
+		return
+	}
+	defer db.Close()
+
+	ls, err := getOpenLobbies(ctx, db, numPlayers)
+	if err != nil {
+		c.String(http.StatusInternalServerError, `Error: %s`, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, network.ConvertToGetOpenLobbiesResponse(ls))
+}
+
+// POST /lobby/:lobbyID/join
+func (cs *cribbageServer) ginPostJoinLobby(c *gin.Context) {
+	cs.handleLobbyPlayer(c, joinLobby)
+}
+
+// POST /lobby/:lobbyID/leave
+func (cs *cribbageServer) ginPostLeaveLobby(c *gin.Context) {
+	cs.handleLobbyPlayer(c, leaveLobby)
+}
+
+// POST /lobby/:lobbyID/accept
+func (cs *cribbageServer) ginPostAcceptInvitation(c *gin.Context) {
+	cs.handleLobbyPlayer(c, acceptInvitation)
+}
+
+// POST /lobby/:lobbyID/decline
+func (cs *cribbageServer) ginPostDeclineInvitation(c *gin.Context) {
+	cs.handleLobbyPlayer(c, declineInvitation)
+}
+
+// GET /lobbies/invitations?playerID=pID
+func (cs *cribbageServer) ginGetInvitations(c *gin.Context) {
+	pID := model.PlayerID(c.Query(`playerID`))
+	if len(pID) == 0 {
+		c.String(http.StatusBadRequest, `Requires playerID`)
+		return
+	}
+
+	ctx := c.Request.Context()
+	db, err := cs.dbFactory.New(ctx)
+	if err != nil {
+		c.String(http.StatusInternalServerError, `dbFactory.New() error: %s`, err)
+		return
+	}
+	defer db.Close()
+
+	ls, err := getInvitations(ctx, db, pID)
+	if err != nil {
+		c.String(http.StatusInternalServerError, `Error: %s`, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, network.ConvertToGetOpenLobbiesResponse(ls))
+}
+
+func (cs *cribbageServer) handleLobbyPlayer(
+	c *gin.Context,
+	fn func(context.Context, persistence.DB, model.LobbyID, model.PlayerID) (model.Lobby, error),
+) {
+	lID, err := getLobbyIDFromContext(c)
+	if err != nil {
+		c.String(http.StatusBadRequest, `Invalid LobbyID: %v`, err)
+		return
+	}
+
+	var lpr network.LobbyPlayerRequest
+	err = c.ShouldBindJSON(&lpr)
+	if err != nil {
+		c.String(http.StatusBadRequest, `Error: %s`, err)
+		return
+	}
+	if lpr.PlayerID == model.InvalidPlayerID {
+		c.String(http.StatusBadRequest, `Requires playerID`)
+		return
+	}
+
+	ctx := c.Request.Context()
+	db, err := cs.dbFactory.New(ctx)
+	if err != nil {
+		c.String(http.StatusInternalServerError, `dbFactory.New() error: %s`, err)
+		return
+	}
+	defer db.Close()
+
+	l, err := fn(ctx, db, lID, lpr.PlayerID)
+	if err != nil {
+		writeLobbyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, network.ConvertToLobby(l))
+}
+
+// POST /lobbies/match
+func (cs *cribbageServer) ginPostMatchLobby(c *gin.Context) {
+	var mlr network.MatchLobbyRequest
+	err := c.ShouldBindJSON(&mlr)
+	if err != nil {
+		c.String(http.StatusBadRequest, `Error: %s`, err)
+		return
+	}
+	if mlr.PlayerID == model.InvalidPlayerID {
+		c.String(http.StatusBadRequest, `Requires playerID`)
+		return
+	}
+
+	opts, err := getLobbyOptions(mlr.PlayerID, mlr.NumPlayers, mlr.NPCFill, mlr.FillAfter)
+	if err != nil {
+		c.String(http.StatusBadRequest, `Error: %s`, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	db, err := cs.dbFactory.New(ctx)
+	if err != nil {
+		c.String(http.StatusInternalServerError, `dbFactory.New() error: %s`, err)
+		return
+	}
+	defer db.Close()
+
+	l, err := matchLobby(ctx, db, opts)
+	if err != nil {
+		writeLobbyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, network.ConvertToLobby(l))
+}
+
+func getLobbyOptions(
+	pID model.PlayerID,
+	numPlayers int,
+	npcFill model.PlayerID,
+	fillAfter string,
+) (lobbyOptions, error) {
+	if numPlayers < model.MinPlayerGame || numPlayers > model.MaxPlayerGame {
+		return lobbyOptions{}, model.ErrInvalidNumPlayers
+	}
+
+	switch npcFill {
+	case model.InvalidPlayerID, interaction.Simple, interaction.Calc, interaction.Dumb:
+	default:
+		return lobbyOptions{}, interaction.ErrUnknownNPCType
+	}
+
+	d, err := network.ConvertFromFillAfter(npcFill, fillAfter)
+	if err != nil {
+		return lobbyOptions{}, err
+	}
+
+	return lobbyOptions{
+		host:       pID,
+		numPlayers: numPlayers,
+		npcFill:    npcFill,
+		fillAfter:  d,
+	}, nil
+}
+
+func getLobbyIDFromContext(c *gin.Context) (model.LobbyID, error) {
+	lIDStr := c.Param(`lobbyID`)
+	n, err := strconv.Atoi(lIDStr)
+	if err != nil {
+		return model.InvalidLobbyID, err
+	}
+	return model.LobbyID(n), nil
+}
+
+func writeLobbyError(c *gin.Context, err error) {
+	if err != persistence.ErrPlayerNotFound && errors.Is(err, persistence.ErrPlayerNotFound) {
+		// this says which player was not found
+		c.String(http.StatusNotFound, `Error: %s`, err)
+		return
+	}
+
+	switch err {
+	case persistence.ErrLobbyNotFound:
+		c.String(http.StatusNotFound, `Lobby not found`)
+	case persistence.ErrPlayerNotFound:
+		c.String(http.StatusNotFound, `Player not found`)
+	case persistence.ErrLobbyConflict:
+		c.String(http.StatusConflict, `Error: %s`, err)
+	case model.ErrLobbyNotOpen,
+		model.ErrLobbyFull,
+		model.ErrAlreadyInLobby,
+		model.ErrNotInLobby,
+		model.ErrNotInvited,
+		model.ErrInvalidNumPlayers:
+		c.String(http.StatusBadRequest, `Error: %s`, err)
+	default:
+		c.String(http.StatusInternalServerError, `Error: %s`, err)
+	}
+}
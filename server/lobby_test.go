@@ -0,0 +1,449 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/network"
+	"github.com/joshprzybyszewski/cribbage/server/interaction"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+func seedNPCPlayers(t *testing.T, dbf persistence.DBFactory) {
+	db, err := dbf.New(context.Background())
+	require.NoError(t, err)
+	defer db.Close()
+
+	// these NPCs don't have interactions, so they won't take any actions
+	for _, id := range []model.PlayerID{interaction.Dumb, interaction.Simple, interaction.Calc} {
+		err := db.CreatePlayer(context.Background(), model.Player{
+			ID:   id,
+			Name: string(id),
+		})
+		require.NoError(t, err)
+	}
+}
+
+func TestGinPostCreateLobby(t *testing.T) {
+	testCases := []struct {
+		msg     string
+		req     network.CreateLobbyRequest
+		expCode int
+		expErr  string
+	}{{
+		msg: `two player lobby`,
+		req: network.CreateLobbyRequest{
+			Host:       `p1`,
+			NumPlayers: 2,
+		},
+		expCode: http.StatusOK,
+	}, {
+		msg: `fills with NPCs`,
+		req: network.CreateLobbyRequest{
+			Host:       `p1`,
+			NumPlayers: 4,
+			NPCFill:    interaction.Calc,
+			FillAfter:  `1m`,
+		},
+		expCode: http.StatusOK,
+	}, {
+		msg: `missing host`,
+		req: network.CreateLobbyRequest{
+			NumPlayers: 2,
+		},
+		expCode: http.StatusBadRequest,
+		expErr:  `Requires host`,
+	}, {
+		msg: `too many players`,
+		req: network.CreateLobbyRequest{
+			Host:       `p1`,
+			NumPlayers: 5,
+		},
+		expCode: http.StatusBadRequest,
+		expErr:  `Error: invalid number of players`,
+	}, {
+		msg: `unknown npc`,
+		req: network.CreateLobbyRequest{
+			Host:       `p1`,
+			NumPlayers: 2,
+			NPCFill:    `p2`,
+			FillAfter:  `1m`,
+		},
+		expCode: http.StatusBadRequest,
+		expErr:  `Error: unknown NPC type`,
+	}, {
+		msg: `unknown host`,
+		req: network.CreateLobbyRequest{
+			Host:       `p9`,
+			NumPlayers: 2,
+		},
+		expCode: http.StatusNotFound,
+		expErr:  `Player not found`,
+	}}
+
+	cs, router := newServerAndRouter(t)
+	seedPlayers(t, cs.dbFactory, 2)
+	for _, tc := range testCases {
+		w, err := performRequest(router, `POST`, `/create/lobby`, prepareBody(t, tc.req))
+		require.NoError(t, err, tc.msg)
+		require.Equal(t, tc.expCode, w.Code, tc.msg)
+		if tc.expCode != http.StatusOK {
+			assert.Equal(t, tc.expErr, readError(t, w), tc.msg)
+			continue
+		}
+
+		var resp network.Lobby
+		readBody(t, w.Body, &resp)
+		assert.NotEqual(t, model.InvalidLobbyID, resp.ID, tc.msg)
+		assert.Equal(t, tc.req.Host, resp.Host, tc.msg)
+		assert.Equal(t, tc.req.NumPlayers, resp.NumPlayers, tc.msg)
+		assert.Equal(t, []model.PlayerID{tc.req.Host}, resp.Seated, tc.msg)
+		assert.Equal(t, `open`, resp.Status, tc.msg)
+		assert.Equal(t, tc.req.NPCFill, resp.NPCFill, tc.msg)
+	}
+}
+
+func TestGinPostJoinLobbyStartsGame(t *testing.T) {
+	cs, router := newServerAndRouter(t)
+	pIDs := seedPlayers(t, cs.dbFactory, 4)
+
+	w, err := performRequest(router, `POST`, `/create/lobby`, prepareBody(t, network.CreateLobbyRequest{
+		Host:       pIDs[0],
+		NumPlayers: 3,
+	}))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, w.Code)
+	var l network.Lobby
+	readBody(t, w.Body, &l)
+	joinURL := fmt.Sprintf(`/lobby/%d/join`, l.ID)
+
+	w, err = performRequest(router, `POST`, joinURL, prepareBody(t, network.LobbyPlayerRequest{
+		PlayerID: pIDs[1],
+	}))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, w.Code)
+	readBody(t, w.Body, &l)
+	assert.Equal(t, []model.PlayerID{pIDs[0], pIDs[1]}, l.Seated)
+	assert.Equal(t, `open`, l.Status)
+
+	// the same player cannot join twice
+	w, err = performRequest(router, `POST`, joinURL, prepareBody(t, network.LobbyPlayerRequest{
+		PlayerID: pIDs[1],
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, `Error: player is already in the lobby`, readError(t, w))
+
+	w, err = performRequest(router, `POST`, joinURL, prepareBody(t, network.LobbyPlayerRequest{
+		PlayerID: pIDs[2],
+	}))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, w.Code)
+	readBody(t, w.Body, &l)
+	assert.Equal(t, `started`, l.Status)
+	require.NotEqual(t, model.InvalidGameID, l.GameID)
+
+	db, err := cs.dbFactory.New(context.Background())
+	require.NoError(t, err)
+	defer db.Close()
+	g, err := getGame(context.Background(), db, l.GameID)
+	require.NoError(t, err)
+	require.Len(t, g.Players, 3)
+	for i, p := range g.Players {
+		assert.Equal(t, pIDs[i], p.ID)
+	}
+
+	// nobody can join a lobby which has started
+	w, err = performRequest(router, `POST`, joinURL, prepareBody(t, network.LobbyPlayerRequest{
+		PlayerID: pIDs[3],
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, `Error: lobby is not open`, readError(t, w))
+
+	w, err = performRequest(router, `GET`, `/lobbies/open`, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, w.Code)
+	var open network.GetOpenLobbiesResponse
+	readBody(t, w.Body, &open)
+	assert.Empty(t, open.Lobbies)
+}
+
+func TestGinPostLeaveLobby(t *testing.T) {
+	cs, router := newServerAndRouter(t)
+	pIDs := seedPlayers(t, cs.dbFactory, 2)
+
+	w, err := performRequest(router, `POST`, `/create/lobby`, prepareBody(t, network.CreateLobbyRequest{
+		Host:       pIDs[0],
+		NumPlayers: 3,
+	}))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, w.Code)
+	var l network.Lobby
+	readBody(t, w.Body, &l)
+
+	w, err = performRequest(router, `POST`, fmt.Sprintf(`/lobby/%d/join`, l.ID), prepareBody(t, network.LobbyPlayerRequest{
+		PlayerID: pIDs[1],
+	}))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	leaveURL := fmt.Sprintf(`/lobby/%d/leave`, l.ID)
+	w, err = performRequest(router, `POST`, leaveURL, prepareBody(t, network.LobbyPlayerRequest{
+		PlayerID: pIDs[0],
+	}))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, w.Code)
+	readBody(t, w.Body, &l)
+	assert.Equal(t, pIDs[1], l.Host)
+	assert.Equal(t, []model.PlayerID{pIDs[1]}, l.Seated)
+	assert.Equal(t, `open`, l.Status)
+
+	w, err = performRequest(router, `POST`, leaveURL, prepareBody(t, network.LobbyPlayerRequest{
+		PlayerID: pIDs[1],
+	}))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, w.Code)
+	readBody(t, w.Body, &l)
+	assert.Empty(t, l.Seated)
+	assert.Equal(t, `closed`, l.Status)
+
+	w, err = performRequest(router, `GET`, fmt.Sprintf(`/lobby/%d`, l.ID), nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, w.Code)
+	readBody(t, w.Body, &l)
+	assert.Equal(t, `closed`, l.Status)
+
+	w, err = performRequest(router, `GET`, `/lobby/1`, nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, `Lobby not found`, readError(t, w))
+}
+
+func TestGinPostMatchLobby(t *testing.T) {
+	cs, router := newServerAndRouter(t)
+	pIDs := seedPlayers(t, cs.dbFactory, 4)
+
+	match := func(pID model.PlayerID, numPlayers int) network.Lobby {
+		w, err := performRequest(router, `POST`, `/lobbies/match`, prepareBody(t, network.MatchLobbyRequest{
+			PlayerID:   pID,
+			NumPlayers: numPlayers,
+		}))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, w.Code)
+		var l network.Lobby
+		readBody(t, w.Body, &l)
+		return l
+	}
+
+	twoPlayer := match(pIDs[0], 2)
+	assert.Equal(t, []model.PlayerID{pIDs[0]}, twoPlayer.Seated)
+
+	// wants a different number of players, so it gets its own lobby
+	threePlayer := match(pIDs[1], 3)
+	assert.NotEqual(t, twoPlayer.ID, threePlayer.ID)
+
+	w, err := performRequest(router, `GET`, `/lobbies/open?num_players=3`, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, w.Code)
+	var open network.GetOpenLobbiesResponse
+	readBody(t, w.Body, &open)
+	require.Len(t, open.Lobbies, 1)
+	assert.Equal(t, threePlayer.ID, open.Lobbies[0].ID)
+
+	l := match(pIDs[2], 2)
+	assert.Equal(t, twoPlayer.ID, l.ID)
+	assert.Equal(t, `started`, l.Status)
+	assert.NotEqual(t, model.InvalidGameID, l.GameID)
+
+	// the first two player lobby has started, so this is a new one
+	l = match(pIDs[3], 2)
+	assert.NotEqual(t, twoPlayer.ID, l.ID)
+	assert.Equal(t, `open`, l.Status)
+}
+
+func TestFillLobby(t *testing.T) {
+	cs, _ := newServerAndRouter(t)
+	pIDs := seedPlayers(t, cs.dbFactory, 1)
+	seedNPCPlayers(t, cs.dbFactory)
+
+	ctx := context.Background()
+	db, err := cs.dbFactory.New(ctx)
+	require.NoError(t, err)
+	defer db.Close()
+
+	l, err := createLobby(ctx, db, lobbyOptions{
+		host:       pIDs[0],
+		numPlayers: 3,
+		npcFill:    interaction.Simple,
+		fillAfter:  time.Minute,
+	})
+	require.NoError(t, err)
+
+	lobbyFiller = newFiller(cs.dbFactory, time.Hour)
+	defer func() {
+		lobbyFiller = nil
+	}()
+
+	// it hasn't waited long enough
+	require.NoError(t, lobbyFiller.fillReady(ctx, time.Now()))
+	l, err = getLobby(ctx, db, l.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.LobbyOpen, l.Status)
+	assert.Equal(t, []model.PlayerID{pIDs[0]}, l.Seated)
+
+	require.NoError(t, lobbyFiller.fillReady(ctx, time.Now().Add(2*time.Minute)))
+	l, err = getLobby(ctx, db, l.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.LobbyStarted, l.Status)
+	assert.Equal(t, []model.PlayerID{pIDs[0], interaction.Simple, interaction.Calc}, l.Seated)
+
+	g, err := getGame(ctx, db, l.GameID)
+	require.NoError(t, err)
+	assert.Len(t, g.Players, 3)
+}
+
+// racingDB makes every player read the lobby before any of them can save it
+type racingDB struct {
+	persistence.DB
+
+	once    sync.Once
+	readers *sync.WaitGroup
+}
+
+func (rdb *racingDB) GetLobby(ctx context.Context, id model.LobbyID) (model.Lobby, error) {
+	l, err := rdb.DB.GetLobby(ctx, id)
+	rdb.once.Do(func() {
+		rdb.readers.Done()
+		rdb.readers.Wait()
+	})
+	return l, err
+}
+
+func TestConcurrentJoinsStartOneGame(t *testing.T) {
+	cs, _ := newServerAndRouter(t)
+	pIDs := seedPlayers(t, cs.dbFactory, 9)
+
+	ctx := context.Background()
+	db, err := cs.dbFactory.New(ctx)
+	require.NoError(t, err)
+	defer db.Close()
+
+	l, err := createLobby(ctx, db, lobbyOptions{
+		host:       pIDs[0],
+		numPlayers: 2,
+	})
+	require.NoError(t, err)
+
+	// everyone tries to take the last seat at the same time
+	joiners := pIDs[1:]
+	var readers sync.WaitGroup
+	readers.Add(len(joiners))
+	errs := make(chan error, len(joiners))
+	var wg sync.WaitGroup
+	for _, pID := range joiners {
+		wg.Add(1)
+		go func(pID model.PlayerID) {
+			defer wg.Done()
+			jdb, dbErr := cs.dbFactory.New(ctx)
+			if !assert.NoError(t, dbErr) {
+				readers.Done()
+				return
+			}
+			defer jdb.Close()
+
+			_, joinErr := joinLobby(ctx, &racingDB{
+				DB:      jdb,
+				readers: &readers,
+			}, l.ID, pID)
+			errs <- joinErr
+		}(pID)
+	}
+	wg.Wait()
+	close(errs)
+
+	numJoined := 0
+	for joinErr := range errs {
+		if joinErr == nil {
+			numJoined++
+			continue
+		}
+		assert.Contains(t, []error{model.ErrLobbyFull, model.ErrLobbyNotOpen}, joinErr)
+	}
+	assert.Equal(t, 1, numJoined)
+
+	l, err = getLobby(ctx, db, l.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.LobbyStarted, l.Status)
+	require.Len(t, l.Seated, 2)
+
+	// only the one game was started for the host
+	host, err := getPlayer(ctx, db, pIDs[0])
+	require.NoError(t, err)
+	assert.Len(t, host.Games, 1)
+	assert.Contains(t, host.Games, l.GameID)
+}
+
+func TestConcurrentLeavesAllLeave(t *testing.T) {
+	cs, _ := newServerAndRouter(t)
+	pIDs := seedPlayers(t, cs.dbFactory, 3)
+
+	ctx := context.Background()
+	db, err := cs.dbFactory.New(ctx)
+	require.NoError(t, err)
+	defer db.Close()
+
+	l, err := createLobby(ctx, db, lobbyOptions{
+		host:       pIDs[0],
+		numPlayers: 4,
+	})
+	require.NoError(t, err)
+	for _, pID := range pIDs[1:] {
+		_, err = joinLobby(ctx, db, l.ID, pID)
+		require.NoError(t, err)
+	}
+
+	// everyone but the host leaves at the same time
+	leavers := pIDs[1:]
+	var readers sync.WaitGroup
+	readers.Add(len(leavers))
+	errs := make(chan error, len(leavers))
+	var wg sync.WaitGroup
+	for _, pID := range leavers {
+		wg.Add(1)
+		go func(pID model.PlayerID) {
+			defer wg.Done()
+			ldb, dbErr := cs.dbFactory.New(ctx)
+			if !assert.NoError(t, dbErr) {
+				readers.Done()
+				return
+			}
+			defer ldb.Close()
+
+			_, leaveErr := leaveLobby(ctx, &racingDB{
+				DB:      ldb,
+				readers: &readers,
+			}, l.ID, pID)
+			errs <- leaveErr
+		}(pID)
+	}
+	wg.Wait()
+	close(errs)
+
+	for leaveErr := range errs {
+		assert.NoError(t, leaveErr)
+	}
+
+	l, err = getLobby(ctx, db, l.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.LobbyOpen, l.Status)
+	assert.Equal(t, []model.PlayerID{pIDs[0]}, l.Seated)
+}
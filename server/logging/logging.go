@@ -0,0 +1,92 @@
+// Package logging has the structured logger that the server writes its logs with.
+// Logs about a game or a player should include them as fields, so that they can
+// be searched for, instead of formatting them into the message.
+package logging
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+)
+
+var (
+	lock   sync.RWMutex
+	logger = newProductionLogger()
+)
+
+func newProductionLogger() *zap.Logger {
+	l, err := zap.NewProduction()
+	if err != nil {
+		return zap.NewNop()
+	}
+	return l
+}
+
+// UseDevelopment switches to a logger that writes readable lines instead of JSON
+func UseDevelopment() error {
+	l, err := zap.NewDevelopment()
+	if err != nil {
+		return err
+	}
+	Set(l)
+	return nil
+}
+
+// Set replaces the logger that the server uses
+func Set(l *zap.Logger) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	logger = l
+}
+
+// L returns the server's logger. Use FromContext while handling a request, so that
+// the logs include which request they were for.
+func L() *zap.Logger {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	return logger
+}
+
+type loggerKey struct{}
+
+// WithLogger returns a context which carries the logger
+func WithLogger(ctx context.Context, l *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext returns the logger that the context carries, or the server's logger
+func FromContext(ctx context.Context) *zap.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*zap.Logger); ok {
+		return l
+	}
+	return L()
+}
+
+func RequestID(id string) zap.Field {
+	return zap.String(`requestID`, id)
+}
+
+func TraceID(id string) zap.Field {
+	return zap.String(`traceID`, id)
+}
+
+func GameID(gID model.GameID) zap.Field {
+	return zap.Uint32(`gameID`, uint32(gID))
+}
+
+func PlayerID(pID model.PlayerID) zap.Field {
+	return zap.String(`playerID`, string(pID))
+}
+
+func LobbyID(lID model.LobbyID) zap.Field {
+	return zap.Uint32(`lobbyID`, uint32(lID))
+}
+
+func Blocker(b model.Blocker) zap.Field {
+	return zap.Stringer(`blocker`, b)
+}
@@ -0,0 +1,152 @@
+// Package metrics has the Prometheus metrics that the server exports at /metrics
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+)
+
+const namespace = `cribbage`
+
+var (
+	requestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: `http`,
+		Name:      `request_duration_seconds`,
+		Help:      `How long the server took to respond to requests, by route`,
+		Buckets:   prometheus.DefBuckets,
+	}, []string{`method`, `route`, `status`})
+
+	actionsHandled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      `actions_total`,
+		Help:      `The actions that were handled, by the blocker that they overcame`,
+	}, []string{`blocker`})
+	actionsRejected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      `actions_rejected_total`,
+		Help:      `The actions that the game would not take, by why it rejected them`,
+	}, []string{`reason`})
+
+	dbDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: `db`,
+		Name:      `operation_duration_seconds`,
+		Help:      `How long the database took for each operation, by backend and service`,
+		Buckets:   prometheus.DefBuckets,
+	}, []string{`backend`, `service`, `operation`})
+	dbErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: `db`,
+		Name:      `operation_errors_total`,
+		Help:      `The database operations which returned an error (including not found), by backend and service`,
+	}, []string{`backend`, `service`, `operation`})
+
+	npcDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: `npc`,
+		Name:      `action_duration_seconds`,
+		Help:      `How long the NPCs took to decide on and take their actions`,
+		Buckets:   prometheus.DefBuckets,
+	}, []string{`npc`, `blocker`})
+	npcFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: `npc`,
+		Name:      `action_failures_total`,
+		Help:      `The times that an NPC could not take its action`,
+	}, []string{`npc`, `blocker`})
+	npcAlerts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: `npc`,
+		Name:      `move_alerts_total`,
+		Help:      `The times that a queued NPC move failed again after failing too many times already`,
+	}, []string{`npc`, `blocker`})
+	npcDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: `npc`,
+		Name:      `moves_dropped_total`,
+		Help:      `The queued NPC moves which were given up on without being made, by why they were dropped`,
+	}, []string{`npc`, `blocker`, `reason`})
+
+	cacheLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: `cache`,
+		Name:      `lookups_total`,
+		Help:      `The lookups in the persistence cache, by the kind of entry and whether it was found`,
+	}, []string{`kind`, `result`})
+
+	activeGames = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      `active_games`,
+		Help: `The games that are not over yet, as last counted in the database. ` +
+			`Every server counts the same games, so don't sum it across servers.`,
+	})
+)
+
+// Handler serves the metrics for Prometheus to scrape
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// ObserveRequest records how long the server took to respond to a request
+func ObserveRequest(method, route string, status int, d time.Duration) {
+	requestDuration.WithLabelValues(method, route, strconv.Itoa(status)).Observe(d.Seconds())
+}
+
+// ActionHandled counts an action that the game took
+func ActionHandled(b model.Blocker) {
+	actionsHandled.WithLabelValues(b.String()).Inc()
+}
+
+// ActionRejected counts an action that the game would not take
+func ActionRejected(reason string) {
+	actionsRejected.WithLabelValues(reason).Inc()
+}
+
+// ObserveDBOperation records how long an operation on the database took, and if it failed
+func ObserveDBOperation(backend, service, operation string, d time.Duration, err error) {
+	dbDuration.WithLabelValues(backend, service, operation).Observe(d.Seconds())
+	if err != nil {
+		dbErrors.WithLabelValues(backend, service, operation).Inc()
+	}
+}
+
+// ObserveNPCAction records how long an NPC took to act, and if it failed
+func ObserveNPCAction(npc model.PlayerID, b model.Blocker, d time.Duration, err error) {
+	if err != nil {
+		npcFailures.WithLabelValues(string(npc), b.String()).Inc()
+		return
+	}
+	npcDuration.WithLabelValues(string(npc), b.String()).Observe(d.Seconds())
+}
+
+// NPCMoveAlert counts a failure of an NPC move which has failed too many times to be ignored
+func NPCMoveAlert(npc model.PlayerID, b model.Blocker) {
+	npcAlerts.WithLabelValues(string(npc), b.String()).Inc()
+}
+
+// NPCMoveDropped counts a queued NPC move which was given up on without being made
+func NPCMoveDropped(npc model.PlayerID, b model.Blocker, reason string) {
+	npcDropped.WithLabelValues(string(npc), b.String(), reason).Inc()
+}
+
+// CacheLookup counts a lookup of a kind of entry (such as "players") in the persistence cache
+func CacheLookup(kind string, hit bool) {
+	result := `miss`
+	if hit {
+		result = `hit`
+	}
+	cacheLookups.WithLabelValues(kind, result).Inc()
+}
+
+// SetActiveGames records how many games are not over yet
+func SetActiveGames(n int) {
+	activeGames.Set(float64(n))
+}
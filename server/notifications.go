@@ -0,0 +1,104 @@
+package server
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/joshprzybyszewski/cribbage/server/interaction"
+	"github.com/joshprzybyszewski/cribbage/server/logging"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+// notifications delivers the notifications that were sent during a transaction
+// once it has committed, so that players aren't told about changes which were
+// rolled back, and so that slow webhooks don't hold the transaction open.
+var notifications = newNotifier()
+
+type notifier struct {
+	// held are the deliveries which were made during a transaction which hasn't
+	// finished yet. A DB only has one transaction open at a time.
+	lock sync.Mutex
+	held map[persistence.DB][]func() error
+
+	delivering sync.WaitGroup
+}
+
+func newNotifier() *notifier {
+	return &notifier{
+		held: map[persistence.DB][]func() error{},
+	}
+}
+
+// outbox returns the interaction.Outbox which holds the deliveries for the db
+func (n *notifier) outbox(db persistence.DB) interaction.Outbox {
+	return heldOutbox{
+		n:  n,
+		db: db,
+	}
+}
+
+// release delivers the notifications which have been held for the db. They are
+// delivered in the order they were sent, off of the request's goroutine. Code
+// that notifies outside of a transaction needs to release them itself.
+func (n *notifier) release(ctx context.Context, db persistence.DB) {
+	deliveries := n.take(db)
+	if len(deliveries) == 0 {
+		return
+	}
+
+	log := logging.FromContext(ctx)
+	n.delivering.Add(1)
+	go func() {
+		defer n.delivering.Done()
+
+		for _, deliver := range deliveries {
+			if err := deliver(); err != nil {
+				log.Warn(`Could not deliver a notification`, zap.Error(err))
+			}
+		}
+	}()
+}
+
+// discard throws away the notifications which have been held for the db
+func (n *notifier) discard(db persistence.DB) {
+	_ = n.take(db)
+}
+
+func (n *notifier) take(db persistence.DB) []func() error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	deliveries := n.held[db]
+	delete(n.held, db)
+	return deliveries
+}
+
+// wait returns once the released notifications have been delivered, or when
+// the context is done
+func (n *notifier) wait(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		n.delivering.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+type heldOutbox struct {
+	n  *notifier
+	db persistence.DB
+}
+
+func (o heldOutbox) Hold(deliver func() error) error {
+	o.n.lock.Lock()
+	defer o.n.lock.Unlock()
+
+	o.n.held[o.db] = append(o.n.held[o.db], deliver)
+	return nil
+}
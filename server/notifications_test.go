@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/interaction"
+)
+
+func TestNotificationsAreDeliveredAfterCommit(t *testing.T) {
+	testCases := []struct {
+		msg          string
+		err          error
+		expDelivered int32
+	}{{
+		msg:          `commit`,
+		expDelivered: 1,
+	}, {
+		msg:          `rollback`,
+		err:          errors.New(`could not save`),
+		expDelivered: 0,
+	}}
+
+	for _, tc := range testCases {
+		var delivered int32
+		hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&delivered, 1)
+		}))
+
+		cs, _ := newServerAndRouter(t)
+		pID := seedPlayers(t, cs.dbFactory, 1)[0]
+
+		ctx := context.Background()
+		db, err := cs.dbFactory.New(ctx)
+		require.NoError(t, err, tc.msg)
+		require.NoError(t, db.SaveInteraction(ctx, interaction.New(pID, interaction.Means{
+			Mode: interaction.Webhook,
+			Info: interaction.WebhookInfo{
+				URL:    hook.URL,
+				Secret: `shh`,
+			},
+		})), tc.msg)
+
+		require.NoError(t, db.Start(ctx), tc.msg)
+		pAPIs, err := getPlayerAPIs(ctx, db, []model.Player{{ID: pID}})
+		require.NoError(t, err, tc.msg)
+		require.NoError(t, pAPIs[pID].NotifyMessage(model.Game{ID: model.GameID(5)}, `hi`), tc.msg)
+		assert.Zero(t, atomic.LoadInt32(&delivered), `the delivery waits for the transaction: %s`, tc.msg)
+
+		err = tc.err
+		commitOrRollback(ctx, db, &err)
+		notifications.wait(ctx)
+		assert.Equal(t, tc.expDelivered, atomic.LoadInt32(&delivered), tc.msg)
+		assert.Empty(t, notifications.take(db), tc.msg)
+
+		db.Close()
+		hook.Close()
+	}
+}
+
+func TestPollingEventsArePublishedAfterCommit(t *testing.T) {
+	testCases := []struct {
+		msg       string
+		err       error
+		expEvents int
+	}{{
+		msg:       `commit`,
+		expEvents: 1,
+	}, {
+		msg:       `rollback`,
+		err:       errors.New(`could not save`),
+		expEvents: 0,
+	}}
+
+	for _, tc := range testCases {
+		cs, _ := newServerAndRouter(t)
+		pID := seedPlayers(t, cs.dbFactory, 1)[0]
+
+		ctx := context.Background()
+		db, err := cs.dbFactory.New(ctx)
+		require.NoError(t, err, tc.msg)
+		require.NoError(t, db.SaveInteraction(ctx, interaction.New(pID, interaction.Means{
+			Mode: interaction.Polling,
+		})), tc.msg)
+
+		// the events for a player live as long as the server, so only look at new ones
+		done, cancel := context.WithCancel(ctx)
+		cancel()
+		after := interaction.PollEvents(done, pID, 0).Next
+
+		require.NoError(t, db.Start(ctx), tc.msg)
+		pAPIs, err := getPlayerAPIs(ctx, db, []model.Player{{ID: pID}})
+		require.NoError(t, err, tc.msg)
+		require.NoError(t, pAPIs[pID].NotifyMessage(model.Game{ID: model.GameID(5)}, `hi`), tc.msg)
+		assert.Empty(t, interaction.PollEvents(done, pID, after).Events, `the event waits for the transaction: %s`, tc.msg)
+
+		err = tc.err
+		commitOrRollback(ctx, db, &err)
+		notifications.wait(ctx)
+		assert.Len(t, interaction.PollEvents(done, pID, after).Events, tc.expEvents, tc.msg)
+
+		db.Close()
+	}
+}
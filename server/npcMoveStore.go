@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/joshprzybyszewski/cribbage/server/npcqueue"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+var _ npcqueue.Store = (*dbNPCMoveStore)(nil)
+
+// dbNPCMoveStore keeps the NPCs' moves in the database, so that they are still made
+// after the server restarts, and so that any server (or lambda) can make them
+type dbNPCMoveStore struct {
+	dbFactory persistence.DBFactory
+}
+
+func newDBNPCMoveStore(dbFactory persistence.DBFactory) npcqueue.Store {
+	return &dbNPCMoveStore{
+		dbFactory: dbFactory,
+	}
+}
+
+func (s *dbNPCMoveStore) Add(ctx context.Context, j npcqueue.Job) (bool, error) {
+	added := false
+	err := s.inTx(ctx, func(db persistence.DB) error {
+		var err error
+		added, err = db.AddNPCMove(ctx, j)
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	return added, nil
+}
+
+func (s *dbNPCMoveStore) Due(ctx context.Context, now time.Time) ([]npcqueue.Job, error) {
+	var due []npcqueue.Job
+	err := s.inTx(ctx, func(db persistence.DB) error {
+		var err error
+		due, err = db.GetDueNPCMoves(ctx, now)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return due, nil
+}
+
+func (s *dbNPCMoveStore) Update(ctx context.Context, j npcqueue.Job) error {
+	return s.inTx(ctx, func(db persistence.DB) error {
+		return db.UpdateNPCMove(ctx, j)
+	})
+}
+
+func (s *dbNPCMoveStore) Remove(ctx context.Context, k npcqueue.Key) error {
+	return s.inTx(ctx, func(db persistence.DB) error {
+		return db.RemoveNPCMove(ctx, k)
+	})
+}
+
+// inTx calls f in its own transaction, which is committed if f returns nil
+func (s *dbNPCMoveStore) inTx(ctx context.Context, f func(persistence.DB) error) (err error) {
+	db, err := s.dbFactory.New(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	err = db.Start(ctx)
+	if err != nil {
+		return err
+	}
+	defer commitOrRollback(ctx, db, &err)
+
+	return f(db)
+}
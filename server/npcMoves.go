@@ -0,0 +1,169 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/joshprzybyszewski/cribbage/server/interaction"
+	"github.com/joshprzybyszewski/cribbage/server/logging"
+	"github.com/joshprzybyszewski/cribbage/server/metrics"
+	"github.com/joshprzybyszewski/cribbage/server/npcqueue"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+var (
+	errNPCMoveNotSaved = errors.New(`the game that the NPC decided on its move for has not been saved yet`)
+)
+
+// npcMoves makes the moves that the NPCs decide on. It is nil until the server has been
+// set up, and NPCs don't move without it.
+var npcMoves *npcMover
+
+type npcMover struct {
+	dbFactory persistence.DBFactory
+	queue     *npcqueue.Queue
+
+	// held are the moves that were decided on during a transaction which hasn't
+	// finished yet. A DB only has one transaction open at a time.
+	lock sync.Mutex
+	held map[persistence.DB][]npcqueue.Job
+}
+
+func newNPCMover(
+	dbFactory persistence.DBFactory,
+	store npcqueue.Store,
+	cfg npcqueue.Config,
+) *npcMover {
+	m := &npcMover{
+		dbFactory: dbFactory,
+		held:      map[persistence.DB][]npcqueue.Job{},
+	}
+	m.queue = npcqueue.New(store, m.move, cfg)
+	return m
+}
+
+// hold keeps the job until the transaction on the db has finished. The moves are
+// queued when it commits, and thrown away when it rolls back. Code that asks the
+// NPCs to move outside of a transaction needs to release them itself.
+// It is safe to call on a nil npcMover.
+func (m *npcMover) hold(db persistence.DB, j npcqueue.Job) {
+	if m == nil {
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.held[db] = append(m.held[db], j)
+}
+
+// release queues the moves which have been held for the db.
+// It is safe to call on a nil npcMover.
+func (m *npcMover) release(ctx context.Context, db persistence.DB) {
+	jobs := m.take(db)
+	if len(jobs) == 0 {
+		return
+	}
+
+	err := m.queue.Add(ctx, jobs...)
+	if err != nil {
+		logging.FromContext(ctx).Error(`Could not queue the NPC moves`, zap.Error(err))
+	}
+}
+
+// discard throws away the moves which have been held for the db.
+// It is safe to call on a nil npcMover.
+func (m *npcMover) discard(db persistence.DB) {
+	_ = m.take(db)
+}
+
+func (m *npcMover) take(db persistence.DB) []npcqueue.Job {
+	if m == nil {
+		return nil
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	jobs := m.held[db]
+	delete(m.held, db)
+	return jobs
+}
+
+// run makes the queued moves until the context is done
+func (m *npcMover) run(ctx context.Context) {
+	m.queue.Run(ctx)
+}
+
+// drain makes the queued moves that are due before it returns.
+// It is safe to call on a nil npcMover.
+func (m *npcMover) drain(ctx context.Context) {
+	if m == nil {
+		return
+	}
+
+	m.queue.Drain(ctx)
+}
+
+// move makes the NPC's move, unless the game has moved on without it
+func (m *npcMover) move(ctx context.Context, j npcqueue.Job) error {
+	db, err := m.dbFactory.New(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	g, err := getGame(ctx, db, j.GameID)
+	if err != nil {
+		return err
+	}
+	if g.NumActions() < j.NumActions {
+		return errNPCMoveNotSaved
+	}
+	if b, ok := g.BlockingPlayers[j.PlayerID]; !ok || b != j.Action.Overcomes {
+		logging.L().Info(`Dropping an NPC move which is no longer needed`,
+			logging.GameID(j.GameID),
+			logging.PlayerID(j.PlayerID),
+			logging.Blocker(j.Action.Overcomes),
+		)
+		return nil
+	}
+
+	start := time.Now()
+	err = handleAction(ctx, db, j.Action)
+	metrics.ObserveNPCAction(j.PlayerID, j.Action.Overcomes, time.Since(start), err)
+	var re rejectedActionError
+	if errors.As(err, &re) {
+		// trying it again won't change the game's mind
+		metrics.NPCMoveDropped(j.PlayerID, j.Action.Overcomes, `rejected`)
+		logging.L().Error(`Dropping an NPC move which the game rejected`,
+			logging.GameID(j.GameID),
+			logging.PlayerID(j.PlayerID),
+			logging.Blocker(j.Action.Overcomes),
+			zap.Error(err),
+		)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	cm, ok := interaction.ChatAbout(j.Action)
+	if !ok {
+		return nil
+	}
+	err = sendChat(ctx, db, cm)
+	if err != nil {
+		// the move was made, so there's no reason to make it again
+		logging.L().Warn(`NPC could not chat about its action`,
+			logging.GameID(j.GameID),
+			logging.PlayerID(j.PlayerID),
+			zap.Error(err),
+		)
+	}
+	return nil
+}
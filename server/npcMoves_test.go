@@ -0,0 +1,217 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/interaction"
+	"github.com/joshprzybyszewski/cribbage/server/npcqueue"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+func useNPCMoves(t *testing.T, dbf persistence.DBFactory) npcqueue.Store {
+	return useNPCMoveStore(t, dbf, npcqueue.NewMemoryStore())
+}
+
+func useNPCMoveStore(t *testing.T, dbf persistence.DBFactory, store npcqueue.Store) npcqueue.Store {
+	npcMoves = newNPCMover(dbf, store, npcqueue.Config{
+		Period:     time.Hour,
+		Backoff:    time.Minute,
+		MaxBackoff: time.Minute,
+	})
+	t.Cleanup(func() {
+		npcMoves = nil
+	})
+	return store
+}
+
+func queuedNPCMoves(t *testing.T, store npcqueue.Store) []npcqueue.Job {
+	due, err := store.Due(context.Background(), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	return due
+}
+
+func TestNPCMovesAreMadeAfterTheGameIsSaved(t *testing.T) {
+	cs, _ := newServerAndRouter(t)
+	store := useNPCMoves(t, cs.dbFactory)
+	p1 := seedPlayers(t, cs.dbFactory, 1)[0]
+
+	ctx := context.Background()
+	require.NoError(t, seedNPCs(ctx, cs.dbFactory))
+	db, err := cs.dbFactory.New(ctx)
+	require.NoError(t, err)
+	defer db.Close()
+
+	g, err := createGame(ctx, db, []model.PlayerID{p1, interaction.Calc}, model.GameSettings{})
+	require.NoError(t, err)
+	// the first player deals, and then the NPC needs to toss into the crib
+	require.NoError(t, handleAction(ctx, db, model.PlayerAction{
+		GameID:    g.ID,
+		ID:        p1,
+		Overcomes: model.DealCards,
+		Action:    model.DealAction{NumShuffles: 3},
+	}))
+
+	g, err = getGame(ctx, db, g.ID)
+	require.NoError(t, err)
+	queued := queuedNPCMoves(t, store)
+	require.Len(t, queued, 1)
+	assert.Equal(t, g.ID, queued[0].GameID)
+	assert.Equal(t, interaction.Calc, queued[0].PlayerID)
+	assert.Equal(t, g.NumActions(), queued[0].NumActions)
+	assert.Equal(t, model.CribCard, queued[0].Action.Overcomes)
+
+	npcMoves.drain(ctx)
+
+	// the NPC has tossed, so we're waiting on p1
+	g, err = getGame(ctx, db, g.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.BuildCrib, g.Phase)
+	assert.Equal(t, map[model.PlayerID]model.Blocker{p1: model.CribCard}, g.BlockingPlayers)
+	assert.Empty(t, queuedNPCMoves(t, store))
+}
+
+func TestNPCMovesInTheDBAreMadeAfterARestart(t *testing.T) {
+	cs, _ := newServerAndRouter(t)
+	store := useNPCMoveStore(t, cs.dbFactory, newDBNPCMoveStore(cs.dbFactory))
+	p1 := seedPlayers(t, cs.dbFactory, 1)[0]
+
+	ctx := context.Background()
+	require.NoError(t, seedNPCs(ctx, cs.dbFactory))
+	db, err := cs.dbFactory.New(ctx)
+	require.NoError(t, err)
+	defer db.Close()
+
+	g, err := createGame(ctx, db, []model.PlayerID{p1, interaction.Calc}, model.GameSettings{})
+	require.NoError(t, err)
+	require.NoError(t, handleAction(ctx, db, model.PlayerAction{
+		GameID:    g.ID,
+		ID:        p1,
+		Overcomes: model.DealCards,
+		Action:    model.DealAction{NumShuffles: 3},
+	}))
+	require.Len(t, queuedNPCMoves(t, store), 1)
+
+	// the server restarts before the NPC moves, and only has the DB to go on
+	store = useNPCMoveStore(t, cs.dbFactory, newDBNPCMoveStore(cs.dbFactory))
+	npcMoves.drain(ctx)
+
+	g, err = getGame(ctx, db, g.ID)
+	require.NoError(t, err)
+	assert.Equal(t, map[model.PlayerID]model.Blocker{p1: model.CribCard}, g.BlockingPlayers)
+	assert.Empty(t, queuedNPCMoves(t, store))
+}
+
+func TestNPCMovesAreThrownAwayOnRollback(t *testing.T) {
+	testCases := []struct {
+		msg       string
+		err       error
+		expQueued int
+	}{{
+		msg:       `commit`,
+		expQueued: 1,
+	}, {
+		msg:       `rollback`,
+		err:       errors.New(`could not save`),
+		expQueued: 0,
+	}}
+
+	for _, tc := range testCases {
+		cs, _ := newServerAndRouter(t)
+		store := useNPCMoves(t, cs.dbFactory)
+
+		ctx := context.Background()
+		db, err := cs.dbFactory.New(ctx)
+		require.NoError(t, err)
+
+		require.NoError(t, db.Start(ctx), tc.msg)
+		aq := &npcActionQueue{db: db}
+		require.NoError(t, aq.Enqueue(model.PlayerAction{
+			GameID:    model.GameID(5),
+			ID:        interaction.Calc,
+			Overcomes: model.DealCards,
+			Action:    model.DealAction{NumShuffles: 3},
+		}, 0), tc.msg)
+		assert.Empty(t, queuedNPCMoves(t, store), `the move waits for the transaction: %s`, tc.msg)
+
+		err = tc.err
+		commitOrRollback(ctx, db, &err)
+		assert.Len(t, queuedNPCMoves(t, store), tc.expQueued, tc.msg)
+		assert.Empty(t, npcMoves.take(db), tc.msg)
+		db.Close()
+	}
+}
+
+func TestNPCMoveChecksTheGame(t *testing.T) {
+	testCases := []struct {
+		msg      string
+		modify   func(j *npcqueue.Job)
+		expErr   error
+		expMoved bool
+	}{{
+		msg:      `makes the move`,
+		modify:   func(*npcqueue.Job) {},
+		expMoved: true,
+	}, {
+		msg: `waits for the game to be saved`,
+		modify: func(j *npcqueue.Job) {
+			j.NumActions++
+		},
+		expErr: errNPCMoveNotSaved,
+	}, {
+		msg: `drops a move that is no longer needed`,
+		modify: func(j *npcqueue.Job) {
+			j.Action.Overcomes = model.CutCard
+		},
+	}, {
+		msg: `drops a move that the game rejects`,
+		modify: func(j *npcqueue.Job) {
+			j.Action.Action = model.DealAction{NumShuffles: 3}
+		},
+	}}
+
+	for _, tc := range testCases {
+		cs, _ := newServerAndRouter(t)
+		store := useNPCMoves(t, cs.dbFactory)
+		p1 := seedPlayers(t, cs.dbFactory, 1)[0]
+
+		ctx := context.Background()
+		require.NoError(t, seedNPCs(ctx, cs.dbFactory))
+		db, err := cs.dbFactory.New(ctx)
+		require.NoError(t, err)
+
+		g, err := createGame(ctx, db, []model.PlayerID{p1, interaction.Simple}, model.GameSettings{})
+		require.NoError(t, err, tc.msg)
+		// the first player deals, and then the NPC needs to toss into the crib
+		require.NoError(t, handleAction(ctx, db, model.PlayerAction{
+			GameID:    g.ID,
+			ID:        p1,
+			Overcomes: model.DealCards,
+			Action:    model.DealAction{NumShuffles: 3},
+		}), tc.msg)
+		before, err := getGame(ctx, db, g.ID)
+		require.NoError(t, err, tc.msg)
+
+		queued := queuedNPCMoves(t, store)
+		require.Len(t, queued, 1, tc.msg)
+		j := queued[0]
+		tc.modify(&j)
+		assert.Equal(t, tc.expErr, npcMoves.move(ctx, j), tc.msg)
+
+		after, err := getGame(ctx, db, g.ID)
+		require.NoError(t, err, tc.msg)
+		if tc.expMoved {
+			assert.Equal(t, before.NumActions()+1, after.NumActions(), tc.msg)
+			assert.NotContains(t, after.BlockingPlayers, interaction.Simple, tc.msg)
+		} else {
+			assert.Equal(t, before.NumActions(), after.NumActions(), tc.msg)
+		}
+		db.Close()
+	}
+}
@@ -0,0 +1,110 @@
+package npcqueue
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var _ Store = (*fileStore)(nil)
+
+// fileStore keeps the jobs in memory, and writes all of them to a file whenever they
+// change, so that the jobs which were waiting when the server stopped are still there
+// when it starts back up.
+type fileStore struct {
+	mem  *memoryStore
+	path string
+}
+
+// NewFileStore returns a Store which is saved to the file at path. It loads the jobs
+// that are already in the file.
+func NewFileStore(path string) (Store, error) {
+	fs := &fileStore{
+		mem:  newMemoryStore(),
+		path: path,
+	}
+
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fs, nil
+		}
+		return nil, err
+	}
+
+	var jobs []Job
+	err = json.Unmarshal(bs, &jobs)
+	if err != nil {
+		return nil, err
+	}
+	for _, j := range jobs {
+		fs.mem.add(j)
+	}
+
+	return fs, nil
+}
+
+func (fs *fileStore) Add(_ context.Context, j Job) (bool, error) {
+	fs.mem.lock.Lock()
+	defer fs.mem.lock.Unlock()
+
+	if !fs.mem.add(j) {
+		return false, nil
+	}
+	return true, fs.save()
+}
+
+func (fs *fileStore) Due(ctx context.Context, now time.Time) ([]Job, error) {
+	return fs.mem.Due(ctx, now)
+}
+
+func (fs *fileStore) Update(_ context.Context, j Job) error {
+	fs.mem.lock.Lock()
+	defer fs.mem.lock.Unlock()
+
+	if !fs.mem.update(j) {
+		return nil
+	}
+	return fs.save()
+}
+
+func (fs *fileStore) Remove(_ context.Context, k Key) error {
+	fs.mem.lock.Lock()
+	defer fs.mem.lock.Unlock()
+
+	if !fs.mem.remove(k) {
+		return nil
+	}
+	return fs.save()
+}
+
+// save writes every job to the file. It expects that the lock is held.
+func (fs *fileStore) save() error {
+	bs, err := json.Marshal(fs.mem.all())
+	if err != nil {
+		return err
+	}
+
+	// write to a temp file first, so that a crash can't leave half of the file behind
+	tmp, err := ioutil.TempFile(filepath.Dir(fs.path), filepath.Base(fs.path)+`.*`)
+	if err != nil {
+		return err
+	}
+	// cleans up the temp file if we do not get as far as renaming it
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(bs)
+	if err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), fs.path)
+}
@@ -0,0 +1,59 @@
+package npcqueue
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+)
+
+func TestFileStoreKeepsJobsAcrossRestarts(t *testing.T) {
+	dir, err := ioutil.TempDir(``, `npcqueue`)
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, `moves.json`)
+
+	s, err := NewFileStore(path)
+	require.NoError(t, err)
+
+	now := time.Now().Round(0)
+	kept := newTestJob(1, `npc`, 4)
+	kept.Queued = now
+	kept.NextAttempt = now.Add(time.Minute)
+	kept.Attempts = 2
+	kept.LastError = `db is down`
+	done := newTestJob(2, `npc`, 7)
+	done.Queued = now
+
+	for _, j := range []Job{kept, done} {
+		added, addErr := s.Add(context.Background(), j)
+		require.NoError(t, addErr)
+		assert.True(t, added)
+	}
+	require.NoError(t, s.Remove(context.Background(), done.Key))
+
+	// the server restarts
+	s, err = NewFileStore(path)
+	require.NoError(t, err)
+
+	due, err := s.Due(context.Background(), now.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, kept.Key, due[0].Key)
+	assert.Equal(t, 2, due[0].Attempts)
+	assert.Equal(t, `db is down`, due[0].LastError)
+	assert.True(t, kept.NextAttempt.Equal(due[0].NextAttempt))
+	assert.Equal(t, model.DealCards, due[0].Action.Overcomes)
+	assert.Equal(t, model.DealAction{NumShuffles: 3}, due[0].Action.Action)
+
+	added, err := s.Add(context.Background(), newTestJob(1, `npc`, 4))
+	require.NoError(t, err)
+	assert.False(t, added, `the job was loaded from the file`)
+}
@@ -0,0 +1,232 @@
+// Package npcqueue is a work queue for the moves that the NPCs decide on. The moves are
+// kept in a Store until they have been made, so that a move which fails (or a server
+// which stops) doesn't leave a game stuck waiting for an NPC forever.
+package npcqueue
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/logging"
+	"github.com/joshprzybyszewski/cribbage/server/metrics"
+)
+
+// Key identifies a move: the NPC makes one move for each state of the game that it is asked to
+type Key struct {
+	GameID     model.GameID   `json:"gID"`
+	PlayerID   model.PlayerID `json:"pID"`
+	NumActions int            `json:"n"`
+}
+
+// Job is a move that an NPC has decided on, but hasn't made yet
+type Job struct {
+	Key
+	Action model.PlayerAction `json:"a"`
+
+	Queued      time.Time `json:"q"`
+	Attempts    int       `json:"attempts,omitempty"`
+	NextAttempt time.Time `json:"next"`
+	LastError   string    `json:"err,omitempty"`
+}
+
+// NewJob returns the job for the NPC's action, when the game has numActions
+func NewJob(action model.PlayerAction, numActions int) Job {
+	return Job{
+		Key: Key{
+			GameID:     action.GameID,
+			PlayerID:   action.ID,
+			NumActions: numActions,
+		},
+		Action: action,
+	}
+}
+
+// Handler makes the move. The job is done once it returns nil, and it is retried otherwise.
+// A handler should return nil for a move that can never be made, so that it isn't retried.
+type Handler func(ctx context.Context, j Job) error
+
+// Config is how the queue retries the jobs that fail
+type Config struct {
+	// Period is how often the queue looks for jobs that are due to be retried
+	Period time.Duration
+	// Backoff is how long the queue waits to retry a job after its first failure.
+	// It doubles after each failure after that, up to MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// AlertAfter is how many times a job can fail before each of its failures raises an alert
+	AlertAfter int
+	// MaxAttempts is how many times a job can fail before it is dropped from the queue.
+	// Zero retries it until it is done.
+	MaxAttempts int
+}
+
+// Queue hands the jobs in its store to the handler, one at a time
+type Queue struct {
+	store  Store
+	handle Handler
+	cfg    Config
+	now    func() time.Time
+
+	// workLock makes sure that only one caller is working on the jobs
+	workLock sync.Mutex
+	wake     chan struct{}
+}
+
+func New(store Store, handle Handler, cfg Config) *Queue {
+	return &Queue{
+		store:  store,
+		handle: handle,
+		cfg:    cfg,
+		now:    time.Now,
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// Add stores the jobs, skipping the ones that are already queued, and wakes up the
+// queue to work on them
+func (q *Queue) Add(ctx context.Context, jobs ...Job) error {
+	for _, j := range jobs {
+		j.Queued = q.now()
+		j.NextAttempt = j.Queued
+		_, err := q.store.Add(ctx, j)
+		if err != nil {
+			return err
+		}
+	}
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+		// it has already been woken up
+	}
+	return nil
+}
+
+// Run works on the jobs as they are added or come due until the context is done
+func (q *Queue) Run(ctx context.Context) {
+	t := time.NewTicker(q.cfg.Period)
+	defer t.Stop()
+
+	for {
+		q.Drain(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.wake:
+		case <-t.C:
+		}
+	}
+}
+
+// Drain works on the jobs that are due until there are none left, or the context is done.
+// The jobs which fail are left for later.
+func (q *Queue) Drain(ctx context.Context) {
+	q.workLock.Lock()
+	defer q.workLock.Unlock()
+
+	for ctx.Err() == nil {
+		due, err := q.store.Due(ctx, q.now())
+		if err != nil {
+			logging.L().Error(`Could not load the NPC moves that are due`, zap.Error(err))
+			return
+		}
+
+		madeAny := false
+		for _, j := range due {
+			if ctx.Err() != nil {
+				return
+			}
+			if q.work(ctx, j) {
+				madeAny = true
+			}
+		}
+
+		if !madeAny {
+			// the rest of the jobs are waiting to be retried
+			return
+		}
+	}
+}
+
+// work has the handler try the job. It returns true if the job is done.
+func (q *Queue) work(ctx context.Context, j Job) bool {
+	err := q.handle(ctx, j)
+	if err == nil {
+		err = q.store.Remove(ctx, j.Key)
+		if err != nil {
+			// it'll be tried again, and the handler should see that it was already made
+			logging.L().Error(`Could not remove an NPC move from the queue`, jobFields(j, err)...)
+			return false
+		}
+		return true
+	}
+
+	j.Attempts++
+	j.NextAttempt = q.now().Add(q.backoff(j.Attempts))
+	j.LastError = err.Error()
+
+	if q.cfg.MaxAttempts > 0 && j.Attempts >= q.cfg.MaxAttempts {
+		q.drop(ctx, j, err)
+		return false
+	}
+
+	if q.cfg.AlertAfter > 0 && j.Attempts >= q.cfg.AlertAfter {
+		metrics.NPCMoveAlert(j.PlayerID, j.Action.Overcomes)
+		logging.L().Error(`NPC move keeps failing`, append(jobFields(j, err),
+			zap.Int(`attempts`, j.Attempts),
+			zap.Time(`queued`, j.Queued),
+		)...)
+	} else {
+		logging.L().Warn(`NPC move failed. It will be retried`, append(jobFields(j, err),
+			zap.Int(`attempts`, j.Attempts),
+			zap.Time(`nextAttempt`, j.NextAttempt),
+		)...)
+	}
+
+	err = q.store.Update(ctx, j)
+	if err != nil {
+		logging.L().Error(`Could not save the failed NPC move`, jobFields(j, err)...)
+	}
+	return false
+}
+
+// drop gives up on the job, which has failed too many times to keep trying it
+func (q *Queue) drop(ctx context.Context, j Job, err error) {
+	metrics.NPCMoveDropped(j.PlayerID, j.Action.Overcomes, `attempts`)
+	logging.L().Error(`Giving up on an NPC move that keeps failing`, append(jobFields(j, err),
+		zap.Int(`attempts`, j.Attempts),
+		zap.Time(`queued`, j.Queued),
+	)...)
+
+	err = q.store.Remove(ctx, j.Key)
+	if err != nil {
+		logging.L().Error(`Could not remove an NPC move from the queue`, jobFields(j, err)...)
+	}
+}
+
+// backoff is how long to wait before the next attempt, after the job has failed this many times
+func (q *Queue) backoff(attempts int) time.Duration {
+	d := q.cfg.Backoff
+	for i := 1; i < attempts && d < q.cfg.MaxBackoff; i++ {
+		d *= 2
+	}
+	if q.cfg.MaxBackoff > 0 && d > q.cfg.MaxBackoff {
+		return q.cfg.MaxBackoff
+	}
+	return d
+}
+
+func jobFields(j Job, err error) []zap.Field {
+	return []zap.Field{
+		logging.GameID(j.GameID),
+		logging.PlayerID(j.PlayerID),
+		logging.Blocker(j.Action.Overcomes),
+		zap.Int(`numActions`, j.NumActions),
+		zap.Error(err),
+	}
+}
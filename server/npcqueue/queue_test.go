@@ -0,0 +1,218 @@
+package npcqueue
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/metrics"
+)
+
+func scrapeMetrics(t *testing.T) string {
+	req, err := http.NewRequest(`GET`, `/metrics`, nil)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(w, req)
+
+	bs, err := ioutil.ReadAll(w.Body)
+	require.NoError(t, err)
+	return string(bs)
+}
+
+func newTestJob(gID model.GameID, pID model.PlayerID, numActions int) Job {
+	return NewJob(model.PlayerAction{
+		GameID:    gID,
+		ID:        pID,
+		Overcomes: model.DealCards,
+		Action:    model.DealAction{NumShuffles: 3},
+	}, numActions)
+}
+
+func newTestQueue(handle Handler, now *time.Time) (*Queue, Store) {
+	s := NewMemoryStore()
+	q := New(s, handle, Config{
+		Period:     time.Hour,
+		Backoff:    time.Second,
+		MaxBackoff: 5 * time.Second,
+		AlertAfter: 3,
+	})
+	q.now = func() time.Time { return *now }
+	return q, s
+}
+
+func TestQueueDedupes(t *testing.T) {
+	testCases := []struct {
+		msg    string
+		jobs   []Job
+		expKey []Key
+	}{{
+		msg: `same move twice`,
+		jobs: []Job{
+			newTestJob(1, `npc`, 4),
+			newTestJob(1, `npc`, 4),
+		},
+		expKey: []Key{{GameID: 1, PlayerID: `npc`, NumActions: 4}},
+	}, {
+		msg: `a later move replaces the earlier one`,
+		jobs: []Job{
+			newTestJob(1, `npc`, 4),
+			newTestJob(1, `npc`, 6),
+		},
+		expKey: []Key{{GameID: 1, PlayerID: `npc`, NumActions: 6}},
+	}, {
+		msg: `an earlier move does not replace a later one`,
+		jobs: []Job{
+			newTestJob(1, `npc`, 6),
+			newTestJob(1, `npc`, 4),
+		},
+		expKey: []Key{{GameID: 1, PlayerID: `npc`, NumActions: 6}},
+	}, {
+		msg: `different NPCs and games`,
+		jobs: []Job{
+			newTestJob(1, `npc`, 4),
+			newTestJob(1, `other`, 4),
+			newTestJob(2, `npc`, 4),
+		},
+		expKey: []Key{
+			{GameID: 1, PlayerID: `npc`, NumActions: 4},
+			{GameID: 1, PlayerID: `other`, NumActions: 4},
+			{GameID: 2, PlayerID: `npc`, NumActions: 4},
+		},
+	}}
+
+	for _, tc := range testCases {
+		now := time.Now()
+		q, s := newTestQueue(func(context.Context, Job) error { return nil }, &now)
+
+		require.NoError(t, q.Add(context.Background(), tc.jobs...), tc.msg)
+
+		due, err := s.Due(context.Background(), now)
+		require.NoError(t, err, tc.msg)
+		keys := make([]Key, len(due))
+		for i, j := range due {
+			keys[i] = j.Key
+		}
+		assert.Equal(t, tc.expKey, keys, tc.msg)
+	}
+}
+
+func TestQueueRetriesWithBackoff(t *testing.T) {
+	now := time.Now()
+	attempts := 0
+	q, s := newTestQueue(func(context.Context, Job) error {
+		attempts++
+		if attempts < 5 {
+			return errors.New(`db is down`)
+		}
+		return nil
+	}, &now)
+
+	require.NoError(t, q.Add(context.Background(), newTestJob(1, `retried`, 4)))
+
+	expBackoffs := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second}
+	for i, b := range expBackoffs {
+		q.Drain(context.Background())
+		require.Equal(t, i+1, attempts)
+
+		due, err := s.Due(context.Background(), now.Add(time.Hour))
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+		assert.Equal(t, i+1, due[0].Attempts)
+		assert.Equal(t, `db is down`, due[0].LastError)
+		assert.Equal(t, now.Add(b), due[0].NextAttempt)
+
+		// it isn't retried before it is due
+		now = now.Add(b - time.Millisecond)
+		q.Drain(context.Background())
+		assert.Equal(t, i+1, attempts)
+		now = now.Add(time.Millisecond)
+	}
+
+	q.Drain(context.Background())
+	assert.Equal(t, 5, attempts)
+	due, err := s.Due(context.Background(), now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, due)
+
+	// the third and fourth failures were alerts
+	assert.Contains(t, scrapeMetrics(t), `cribbage_npc_move_alerts_total{blocker="DealCards",npc="retried"} 2`)
+}
+
+func TestQueueDropsJobsAfterMaxAttempts(t *testing.T) {
+	now := time.Now()
+	attempts := 0
+	q, s := newTestQueue(func(context.Context, Job) error {
+		attempts++
+		return errors.New(`db is down`)
+	}, &now)
+	q.cfg.MaxAttempts = 3
+
+	require.NoError(t, q.Add(context.Background(), newTestJob(1, `dropped`, 4)))
+
+	for i := 1; i <= 3; i++ {
+		q.Drain(context.Background())
+		require.Equal(t, i, attempts)
+		now = now.Add(time.Hour)
+	}
+
+	due, err := s.Due(context.Background(), now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, due)
+
+	q.Drain(context.Background())
+	assert.Equal(t, 3, attempts, `a dropped job isn't tried again`)
+	assert.Contains(t, scrapeMetrics(t), `cribbage_npc_moves_dropped_total{blocker="DealCards",npc="dropped",reason="attempts"} 1`)
+}
+
+func TestQueueDrainMakesTheMovesThatAreAddedWhileDraining(t *testing.T) {
+	now := time.Now()
+	var q *Queue
+	var made []Key
+	q, _ = newTestQueue(func(ctx context.Context, j Job) error {
+		made = append(made, j.Key)
+		if j.NumActions < 3 {
+			// this move leads to another one
+			return q.Add(ctx, newTestJob(j.GameID, j.PlayerID, j.NumActions+1))
+		}
+		return nil
+	}, &now)
+
+	require.NoError(t, q.Add(context.Background(), newTestJob(1, `npc`, 1)))
+	q.Drain(context.Background())
+
+	assert.Equal(t, []Key{
+		{GameID: 1, PlayerID: `npc`, NumActions: 1},
+		{GameID: 1, PlayerID: `npc`, NumActions: 2},
+		{GameID: 1, PlayerID: `npc`, NumActions: 3},
+	}, made)
+}
+
+func TestQueueRunWakesUpForNewJobs(t *testing.T) {
+	made := make(chan Key, 1)
+	q := New(NewMemoryStore(), func(_ context.Context, j Job) error {
+		made <- j.Key
+		return nil
+	}, Config{
+		Period: time.Hour,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go q.Run(ctx)
+
+	require.NoError(t, q.Add(context.Background(), newTestJob(1, `npc`, 4)))
+	select {
+	case k := <-made:
+		assert.Equal(t, Key{GameID: 1, PlayerID: `npc`, NumActions: 4}, k)
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, `the queue did not make the move`)
+	}
+}
@@ -0,0 +1,141 @@
+package npcqueue
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+)
+
+// Store keeps the jobs until they are done. Each NPC has at most one job for each game:
+// a job for a later state of the game replaces the one that is stored.
+type Store interface {
+	// Add stores the job. It returns false, and stores nothing, when there is already
+	// a job for the NPC in that game at the same (or a later) number of actions.
+	Add(ctx context.Context, j Job) (bool, error)
+	// Due returns the jobs whose next attempt is at or before now, oldest first.
+	Due(ctx context.Context, now time.Time) ([]Job, error)
+	// Update replaces the job with the same key. It does nothing if that job is gone.
+	Update(ctx context.Context, j Job) error
+	// Remove drops the job with the key. It does nothing if that job is gone.
+	Remove(ctx context.Context, k Key) error
+}
+
+type gamePlayer struct {
+	gID model.GameID
+	pID model.PlayerID
+}
+
+func gamePlayerOf(k Key) gamePlayer {
+	return gamePlayer{
+		gID: k.GameID,
+		pID: k.PlayerID,
+	}
+}
+
+var _ Store = (*memoryStore)(nil)
+
+type memoryStore struct {
+	lock sync.Mutex
+	jobs map[gamePlayer]Job
+}
+
+// NewMemoryStore returns a Store which only lasts as long as the process does
+func NewMemoryStore() Store {
+	return newMemoryStore()
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		jobs: map[gamePlayer]Job{},
+	}
+}
+
+func (ms *memoryStore) Add(_ context.Context, j Job) (bool, error) {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+
+	return ms.add(j), nil
+}
+
+func (ms *memoryStore) add(j Job) bool {
+	gp := gamePlayerOf(j.Key)
+	if cur, ok := ms.jobs[gp]; ok && cur.NumActions >= j.NumActions {
+		return false
+	}
+	ms.jobs[gp] = j
+	return true
+}
+
+func (ms *memoryStore) Due(_ context.Context, now time.Time) ([]Job, error) {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+
+	var due []Job
+	for _, j := range ms.jobs {
+		if !j.NextAttempt.After(now) {
+			due = append(due, j)
+		}
+	}
+	SortJobs(due)
+	return due, nil
+}
+
+func (ms *memoryStore) Update(_ context.Context, j Job) error {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+
+	ms.update(j)
+	return nil
+}
+
+func (ms *memoryStore) update(j Job) bool {
+	gp := gamePlayerOf(j.Key)
+	if cur, ok := ms.jobs[gp]; !ok || cur.Key != j.Key {
+		return false
+	}
+	ms.jobs[gp] = j
+	return true
+}
+
+func (ms *memoryStore) Remove(_ context.Context, k Key) error {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+
+	ms.remove(k)
+	return nil
+}
+
+func (ms *memoryStore) remove(k Key) bool {
+	gp := gamePlayerOf(k)
+	if cur, ok := ms.jobs[gp]; !ok || cur.Key != k {
+		return false
+	}
+	delete(ms.jobs, gp)
+	return true
+}
+
+func (ms *memoryStore) all() []Job {
+	jobs := make([]Job, 0, len(ms.jobs))
+	for _, j := range ms.jobs {
+		jobs = append(jobs, j)
+	}
+	SortJobs(jobs)
+	return jobs
+}
+
+// SortJobs puts the jobs in the order that they were queued, so that the
+// NPCs move in the same order that they were asked to
+func SortJobs(jobs []Job) {
+	sort.Slice(jobs, func(i, j int) bool {
+		if !jobs[i].Queued.Equal(jobs[j].Queued) {
+			return jobs[i].Queued.Before(jobs[j].Queued)
+		}
+		if jobs[i].GameID != jobs[j].GameID {
+			return jobs[i].GameID < jobs[j].GameID
+		}
+		return jobs[i].PlayerID < jobs[j].PlayerID
+	})
+}
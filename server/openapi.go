@@ -0,0 +1,373 @@
+package server
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/network"
+)
+
+const (
+	openAPIVersion = `3.0.3`
+	apiVersion     = `1.0.0`
+)
+
+var (
+	pathParamRE = regexp.MustCompile(`:([a-zA-Z]+)`)
+
+	openAPISpec     jsonObject
+	openAPISpecOnce sync.Once
+)
+
+type paramDoc struct {
+	name        string
+	description string
+	schemaType  string
+	required    bool
+}
+
+// routeDoc documents one of the routes in addRESTRoutes. If resp is nil,
+// the route responds with plain text.
+type routeDoc struct {
+	method      string
+	path        string
+	summary     string
+	queryParams []paramDoc
+	body        interface{}
+	resp        interface{}
+}
+
+// restRouteDocs needs to be updated whenever a route is added to addRESTRoutes.
+// TestOpenAPIDocumentsEveryRoute makes sure that we don't forget.
+var restRouteDocs = []routeDoc{{
+	method:  `GET`,
+	path:    `/api/health`,
+	summary: `Checks that the server is up (liveness)`,
+}, {
+	method:  `GET`,
+	path:    `/api/health/ready`,
+	summary: `Checks that the server can reach its database and is not shutting down (readiness)`,
+}, {
+	method:  `GET`,
+	path:    `/api/openapi.json`,
+	summary: `Returns this OpenAPI specification`,
+	resp:    jsonObject{},
+}, {
+	method:  `GET`,
+	path:    `/debug/vars`,
+	summary: `Returns the exported variables, such as the hit rates of the persistence cache`,
+	resp:    jsonObject{},
+}, {
+	method:  `GET`,
+	path:    `/metrics`,
+	summary: `Returns the server's metrics in the Prometheus text format`,
+}, {
+	method:  `POST`,
+	path:    `/create/game`,
+	summary: `Creates a game for the players`,
+	body:    network.CreateGameRequest{},
+	resp:    network.CreateGameResponse{},
+}, {
+	method:  `POST`,
+	path:    `/create/player`,
+	summary: `Creates a player`,
+	body:    network.CreatePlayerRequest{},
+	resp:    network.CreatePlayerResponse{},
+}, {
+	method:  `POST`,
+	path:    `/create/interaction`,
+	summary: `Sets how the server notifies the player`,
+	body:    network.CreateInteractionRequest{},
+}, {
+	method:  `POST`,
+	path:    `/create/lobby`,
+	summary: `Opens a lobby for others to join`,
+	body:    network.CreateLobbyRequest{},
+	resp:    network.Lobby{},
+}, {
+	method:  `POST`,
+	path:    `/create/invitation`,
+	summary: `Invites players to a game which starts once they all accept`,
+	body:    network.CreateInvitationRequest{},
+	resp:    network.Lobby{},
+}, {
+	method:  `GET`,
+	path:    `/lobby/:lobbyID`,
+	summary: `Returns the lobby`,
+	resp:    network.Lobby{},
+}, {
+	method:  `POST`,
+	path:    `/lobby/:lobbyID/join`,
+	summary: `Seats the player in the lobby`,
+	body:    network.LobbyPlayerRequest{},
+	resp:    network.Lobby{},
+}, {
+	method:  `POST`,
+	path:    `/lobby/:lobbyID/leave`,
+	summary: `Removes the player from the lobby`,
+	body:    network.LobbyPlayerRequest{},
+	resp:    network.Lobby{},
+}, {
+	method:  `POST`,
+	path:    `/lobby/:lobbyID/accept`,
+	summary: `Accepts the invitation`,
+	body:    network.LobbyPlayerRequest{},
+	resp:    network.Lobby{},
+}, {
+	method:  `POST`,
+	path:    `/lobby/:lobbyID/decline`,
+	summary: `Declines the invitation, which cancels it for everyone`,
+	body:    network.LobbyPlayerRequest{},
+	resp:    network.Lobby{},
+}, {
+	method:  `GET`,
+	path:    `/lobbies/open`,
+	summary: `Returns the public lobbies that anyone can join`,
+	queryParams: []paramDoc{{
+		name:        `num_players`,
+		description: `only return the lobbies for this size of game`,
+		schemaType:  `integer`,
+	}},
+	resp: network.GetOpenLobbiesResponse{},
+}, {
+	method:  `POST`,
+	path:    `/lobbies/match`,
+	summary: `Seats the player in the oldest open lobby, or opens a new one`,
+	body:    network.MatchLobbyRequest{},
+	resp:    network.Lobby{},
+}, {
+	method:  `GET`,
+	path:    `/lobbies/invitations`,
+	summary: `Returns the invitations waiting on the player`,
+	queryParams: []paramDoc{{
+		name:       `playerID`,
+		schemaType: `string`,
+		required:   true,
+	}},
+	resp: network.GetOpenLobbiesResponse{},
+}, {
+	method:  `GET`,
+	path:    `/game/:gameID`,
+	summary: `Returns what the requester is allowed to see of the game`,
+	queryParams: []paramDoc{{
+		name:        `player`,
+		description: `the player who is asking, so that their hand is shown`,
+		schemaType:  `string`,
+	}, {
+		name:        `spectator`,
+		description: `the spectator who is asking, so that the hand they look over is shown`,
+		schemaType:  `string`,
+	}},
+	resp: network.GetGameResponse{},
+}, {
+	method:  `GET`,
+	path:    `/game/:gameID/shuffles`,
+	summary: `Returns the proofs that each round of a provably fair game was shuffled fairly`,
+	resp:    network.GetShufflesResponse{},
+}, {
+	method:  `GET`,
+	path:    `/game/:gameID/spectators`,
+	summary: `Returns the spectators of the game`,
+	resp:    network.GetSpectatorsResponse{},
+}, {
+	method:  `POST`,
+	path:    `/game/:gameID/spectate`,
+	summary: `Starts spectating the game`,
+	body:    network.SpectateRequest{},
+}, {
+	method:  `POST`,
+	path:    `/game/:gameID/unspectate`,
+	summary: `Stops spectating the game`,
+	body:    network.SpectateRequest{},
+}, {
+	method:  `POST`,
+	path:    `/game/:gameID/shoulder`,
+	summary: `Lets a spectator look over the player's shoulder, or stops them`,
+	body:    network.ShoulderRequest{},
+}, {
+	method:  `GET`,
+	path:    `/game/:gameID/chat`,
+	summary: `Returns the chat history of the game`,
+	resp:    network.GetChatResponse{},
+}, {
+	method:  `POST`,
+	path:    `/game/:gameID/chat`,
+	summary: `Sends a chat message to the other players in the game`,
+	body:    network.SendChatRequest{},
+	resp:    network.ChatMessage{},
+}, {
+	method:  `GET`,
+	path:    `/games/active`,
+	summary: `Returns the games that the player has not finished`,
+	queryParams: []paramDoc{{
+		name:       `playerID`,
+		schemaType: `string`,
+		required:   true,
+	}},
+	resp: network.GetActiveGamesForPlayerResponse{},
+}, {
+	method:  `GET`,
+	path:    `/player/:username`,
+	summary: `Returns the player`,
+	resp:    network.GetPlayerResponse{},
+}, {
+	method:  `GET`,
+	path:    `/player/:username/events`,
+	summary: `Waits for the notifications of a player who polls for them, and returns them`,
+	queryParams: []paramDoc{{
+		name:        `after`,
+		description: `the ID of the last event the player has seen`,
+		schemaType:  `integer`,
+	}},
+	resp: network.GetPlayerEventsResponse{},
+}, {
+	method:  `POST`,
+	path:    `/action`,
+	summary: `Takes an action in a game. The action depends on what it overcomes.`,
+	body:    model.PlayerAction{},
+}, {
+	method:  `GET`,
+	path:    `/suggest/hand`,
+	summary: `Suggests which cards to keep from the dealt hand`,
+	queryParams: []paramDoc{{
+		name:        `dealt`,
+		description: `the comma separated cards that were dealt, like "AH,10C,5D,5S,JH,2C"`,
+		schemaType:  `string`,
+		required:    true,
+	}},
+	resp: []network.GetSuggestHandResponse{},
+}}
+
+// GET /api/openapi.json
+func (cs *cribbageServer) ginGetOpenAPI(c *gin.Context) {
+	openAPISpecOnce.Do(func() {
+		openAPISpec = buildOpenAPISpec(restRouteDocs)
+	})
+	c.JSON(http.StatusOK, openAPISpec)
+}
+
+func buildOpenAPISpec(docs []routeDoc) jsonObject {
+	sr := newSchemaRegistry()
+	paths := jsonObject{}
+	for _, rd := range docs {
+		p := toOpenAPIPath(rd.path)
+		if _, ok := paths[p]; !ok {
+			paths[p] = jsonObject{}
+		}
+		paths[p].(jsonObject)[strings.ToLower(rd.method)] = buildOperation(sr, rd)
+	}
+
+	return jsonObject{
+		`openapi`: openAPIVersion,
+		`info`: jsonObject{
+			`title`:   `Cribbage`,
+			`version`: apiVersion,
+		},
+		`paths`: paths,
+		`components`: jsonObject{
+			`schemas`: sr.components,
+		},
+	}
+}
+
+func buildOperation(sr *schemaRegistry, rd routeDoc) jsonObject {
+	op := jsonObject{
+		`summary`: rd.summary,
+	}
+
+	var params []jsonObject
+	for _, m := range pathParamRE.FindAllStringSubmatch(rd.path, -1) {
+		params = append(params, buildParameter(`path`, paramDoc{
+			name:       m[1],
+			schemaType: pathParamType(m[1]),
+			required:   true,
+		}))
+	}
+	for _, qp := range rd.queryParams {
+		params = append(params, buildParameter(`query`, qp))
+	}
+	if len(params) > 0 {
+		op[`parameters`] = params
+	}
+
+	if rd.body != nil {
+		op[`requestBody`] = jsonObject{
+			`required`: true,
+			`content`: jsonObject{
+				`application/json`: jsonObject{
+					`schema`: sr.schemaFor(rd.body),
+				},
+			},
+		}
+	}
+
+	op[`responses`] = jsonObject{
+		`200`: buildResponse(sr, rd.resp),
+		`default`: jsonObject{
+			`description`: `The error`,
+			`content`:     textContent(),
+		},
+	}
+
+	return op
+}
+
+func buildParameter(in string, pd paramDoc) jsonObject {
+	p := jsonObject{
+		`name`:     pd.name,
+		`in`:       in,
+		`required`: pd.required,
+		`schema`:   jsonObject{`type`: pd.schemaType},
+	}
+	if pd.description != `` {
+		p[`description`] = pd.description
+	}
+	return p
+}
+
+func buildResponse(sr *schemaRegistry, resp interface{}) jsonObject {
+	if resp == nil {
+		return jsonObject{
+			`description`: `OK`,
+			`content`:     textContent(),
+		}
+	}
+
+	schema := jsonObject{`type`: `object`}
+	if _, ok := resp.(jsonObject); !ok {
+		schema = sr.schemaFor(resp)
+	}
+	return jsonObject{
+		`description`: `OK`,
+		`content`: jsonObject{
+			`application/json`: jsonObject{
+				`schema`: schema,
+			},
+		},
+	}
+}
+
+func textContent() jsonObject {
+	return jsonObject{
+		`text/plain`: jsonObject{
+			`schema`: jsonObject{`type`: `string`},
+		},
+	}
+}
+
+// toOpenAPIPath turns gin's "/game/:gameID" into "/game/{gameID}"
+func toOpenAPIPath(p string) string {
+	return pathParamRE.ReplaceAllString(p, `{$1}`)
+}
+
+func pathParamType(name string) string {
+	if strings.HasSuffix(name, `ID`) {
+		return `integer`
+	}
+	return `string`
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+type jsonObject = map[string]interface{}
+
+// schemaRegistry builds the OpenAPI schemas for the types that the REST API sends and
+// receives. Every named struct becomes a component that the other schemas refer to.
+type schemaRegistry struct {
+	components jsonObject
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{
+		components: jsonObject{},
+	}
+}
+
+// schemaFor returns the schema for the JSON encoding of v
+func (sr *schemaRegistry) schemaFor(v interface{}) jsonObject {
+	return sr.schemaForType(reflect.TypeOf(v))
+}
+
+func (sr *schemaRegistry) schemaForType(t reflect.Type) jsonObject {
+	if t == reflect.TypeOf(time.Time{}) {
+		return jsonObject{`type`: `string`, `format`: `date-time`}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return sr.schemaForType(t.Elem())
+	case reflect.Bool:
+		return jsonObject{`type`: `boolean`}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint8, reflect.Uint16:
+		return jsonObject{`type`: `integer`, `format`: `int32`}
+	case reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		// unsigned ints don't always fit in an int32
+		return jsonObject{`type`: `integer`, `format`: `int64`}
+	case reflect.Float32, reflect.Float64:
+		return jsonObject{`type`: `number`}
+	case reflect.String:
+		return jsonObject{`type`: `string`}
+	case reflect.Slice, reflect.Array:
+		return jsonObject{
+			`type`:  `array`,
+			`items`: sr.schemaForType(t.Elem()),
+		}
+	case reflect.Map:
+		return jsonObject{
+			`type`:                 `object`,
+			`additionalProperties`: sr.schemaForType(t.Elem()),
+		}
+	case reflect.Struct:
+		return sr.refTo(t)
+	}
+
+	// an interface{} could be anything
+	return jsonObject{}
+}
+
+// refTo adds the struct to the components (if it isn't there yet) and refers to it
+func (sr *schemaRegistry) refTo(t reflect.Type) jsonObject {
+	name := t.Name()
+	ref := jsonObject{`$ref`: `#/components/schemas/` + name}
+	if _, ok := sr.components[name]; ok {
+		return ref
+	}
+
+	// put a placeholder in first in case the struct refers to itself
+	sr.components[name] = jsonObject{}
+
+	props := jsonObject{}
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != `` {
+			// unexported fields aren't encoded
+			continue
+		}
+
+		name, omitEmpty, ok := jsonFieldName(f)
+		if !ok {
+			continue
+		}
+		props[name] = sr.schemaForType(f.Type)
+		if !omitEmpty {
+			required = append(required, name)
+		}
+	}
+
+	schema := jsonObject{
+		`type`:       `object`,
+		`properties`: props,
+	}
+	if len(required) > 0 {
+		schema[`required`] = required
+	}
+	sr.components[t.Name()] = schema
+
+	return ref
+}
+
+func jsonFieldName(f reflect.StructField) (string, bool, bool) {
+	tag := f.Tag.Get(`json`)
+	if tag == `-` {
+		return ``, false, false
+	}
+
+	parts := strings.Split(tag, `,`)
+	name := parts[0]
+	if name == `` {
+		name = f.Name
+	}
+
+	omitEmpty := false
+	for _, opt := range parts[1:] {
+		if opt == `omitempty` {
+			omitEmpty = true
+		}
+	}
+	return name, omitEmpty, true
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshprzybyszewski/cribbage/network"
+)
+
+func TestOpenAPIDocumentsEveryRoute(t *testing.T) {
+	_, router := newServerAndRouter(t)
+
+	routes := map[string]struct{}{}
+	for _, ri := range router.(*gin.Engine).Routes() {
+		routes[ri.Method+` `+ri.Path] = struct{}{}
+	}
+
+	documented := map[string]struct{}{}
+	for _, rd := range restRouteDocs {
+		key := rd.method + ` ` + rd.path
+		_, ok := routes[key]
+		assert.True(t, ok, `%q is documented but not served`, key)
+		documented[key] = struct{}{}
+	}
+
+	for key := range routes {
+		_, ok := documented[key]
+		assert.True(t, ok, `%q is served but not documented`, key)
+	}
+}
+
+func TestGinGetOpenAPI(t *testing.T) {
+	_, router := newServerAndRouter(t)
+
+	w, err := performRequest(router, `GET`, `/api/openapi.json`, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var spec struct {
+		OpenAPI    string                                       `json:"openapi"`
+		Paths      map[string]map[string]json.RawMessage        `json:"paths"`
+		Components map[string]map[string]map[string]interface{} `json:"components"`
+	}
+	body := w.Body.String()
+	require.NoError(t, json.Unmarshal([]byte(body), &spec))
+	assert.Equal(t, openAPIVersion, spec.OpenAPI)
+	assert.Contains(t, spec.Paths, `/game/{gameID}/chat`)
+	assert.Contains(t, spec.Paths[`/game/{gameID}/chat`], `get`)
+	assert.Contains(t, spec.Paths[`/game/{gameID}/chat`], `post`)
+
+	// every reference needs to point to a schema that we have
+	schemas := spec.Components[`schemas`]
+	refPrefix := `"$ref":"#/components/schemas/`
+	for _, part := range strings.Split(body, refPrefix)[1:] {
+		name := part[:strings.Index(part, `"`)]
+		assert.Contains(t, schemas, name)
+	}
+}
+
+func TestSchemaFor(t *testing.T) {
+	sr := newSchemaRegistry()
+
+	assert.Equal(t, jsonObject{
+		`$ref`: `#/components/schemas/GetChatResponse`,
+	}, sr.schemaFor(network.GetChatResponse{}))
+	assert.Equal(t, jsonObject{
+		`type`: `object`,
+		`properties`: jsonObject{
+			`gameID`: jsonObject{`type`: `integer`, `format`: `int64`},
+			`messages`: jsonObject{
+				`type`:  `array`,
+				`items`: jsonObject{`$ref`: `#/components/schemas/ChatMessage`},
+			},
+		},
+		`required`: []string{`gameID`, `messages`},
+	}, sr.components[`GetChatResponse`])
+	assert.Contains(t, sr.components, `ChatMessage`)
+
+	// optional fields are not required
+	sr.schemaFor(network.Spectator{})
+	assert.Equal(t, []string{`playerID`}, sr.components[`Spectator`].(jsonObject)[`required`])
+}
@@ -0,0 +1,260 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+var _ persistence.DBFactory = (*factory)(nil)
+
+// factory wraps another persistence.DBFactory and keeps a read-through cache
+// of players and of the latest game states that every DB it creates shares.
+type factory struct {
+	dbf persistence.DBFactory
+
+	c *cache
+}
+
+// NewFactory returns a DBFactory which caches the players and latest games read out
+// of the given factory's DBs. Entries are thrown away after the given ttl so that
+// writes from other processes are eventually seen.
+func NewFactory(dbf persistence.DBFactory, ttl time.Duration) persistence.DBFactory {
+	return &factory{
+		dbf: dbf,
+		c:   newCache(ttl),
+	}
+}
+
+func (f *factory) New(ctx context.Context) (persistence.DB, error) {
+	db, err := f.dbf.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return newCachedDB(db, f.c), nil
+}
+
+func (f *factory) Ping(ctx context.Context) error {
+	// the cache is no good if the database behind it can't be reached
+	return f.dbf.Ping(ctx)
+}
+
+func (f *factory) Close() error {
+	f.c.clear()
+
+	return f.dbf.Close()
+}
+
+type playerEntry struct {
+	player  model.Player
+	expires time.Time
+}
+
+type gameEntry struct {
+	game    model.Game
+	expires time.Time
+}
+
+// invalidation is the generation that a key was last invalidated at
+type invalidation struct {
+	generation uint64
+	at         time.Time
+}
+
+// cache holds the entries shared by all of the DBs of a factory.
+// Every invalidation of a key bumps its generation. A reader remembers the
+// generation before it goes to the underlying DB, and it only populates
+// the cache if nobody invalidated the key in the meantime. This keeps a slow
+// reader from putting a stale value back after a writer has committed.
+//
+// Once per ttl, we prune the expired entries and the invalidations older than
+// the ttl, so that the cache doesn't grow with every key it has ever seen. The
+// newest pruned generation becomes the floor: a reader which started before it
+// might have missed an invalidation, so it can't populate the cache.
+type cache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	lock sync.Mutex
+
+	generation uint64
+	floor      uint64
+	lastPrune  time.Time
+
+	players          map[model.PlayerID]playerEntry
+	playerGeneration map[model.PlayerID]invalidation
+
+	games          map[model.GameID]gameEntry
+	gameGeneration map[model.GameID]invalidation
+}
+
+func newCache(ttl time.Duration) *cache {
+	c := &cache{
+		ttl: ttl,
+		now: time.Now,
+	}
+	c.clear()
+	return c
+}
+
+func (c *cache) clear() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.players = map[model.PlayerID]playerEntry{}
+	c.playerGeneration = map[model.PlayerID]invalidation{}
+	c.games = map[model.GameID]gameEntry{}
+	c.gameGeneration = map[model.GameID]invalidation{}
+}
+
+// getPlayer returns the cached player (if there is one) and the generation to
+// use when putting the player after a miss.
+func (c *cache) getPlayer(id model.PlayerID) (model.Player, uint64, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	e, ok := c.players[id]
+	if !ok || c.now().After(e.expires) {
+		delete(c.players, id)
+		playerStats.miss()
+		return model.Player{}, c.generation, false
+	}
+
+	playerStats.hit()
+	return copyPlayer(e.player), c.generation, true
+}
+
+func (c *cache) putPlayer(p model.Player, gen uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.now()
+	c.prune(now)
+	if gen < c.floor || c.playerGeneration[p.ID].generation > gen {
+		// this player may have been invalidated since the reader started
+		return
+	}
+
+	c.players[p.ID] = playerEntry{
+		player:  copyPlayer(p),
+		expires: now.Add(c.ttl),
+	}
+}
+
+func (c *cache) invalidatePlayer(id model.PlayerID) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.now()
+	c.prune(now)
+	c.generation++
+	c.playerGeneration[id] = invalidation{
+		generation: c.generation,
+		at:         now,
+	}
+	delete(c.players, id)
+}
+
+// getGame returns the cached game (if there is one) and the generation to
+// use when putting the game after a miss.
+func (c *cache) getGame(id model.GameID) (model.Game, uint64, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	e, ok := c.games[id]
+	if !ok || c.now().After(e.expires) {
+		delete(c.games, id)
+		gameStats.miss()
+		return model.Game{}, c.generation, false
+	}
+
+	gameStats.hit()
+	return persistence.CopyGame(e.game), c.generation, true
+}
+
+func (c *cache) putGame(g model.Game, gen uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.now()
+	c.prune(now)
+	if gen < c.floor || c.gameGeneration[g.ID].generation > gen {
+		// this game may have been invalidated since the reader started
+		return
+	}
+
+	c.games[g.ID] = gameEntry{
+		game:    persistence.CopyGame(g),
+		expires: now.Add(c.ttl),
+	}
+}
+
+func (c *cache) invalidateGame(id model.GameID) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.now()
+	c.prune(now)
+	c.generation++
+	c.gameGeneration[id] = invalidation{
+		generation: c.generation,
+		at:         now,
+	}
+	delete(c.games, id)
+}
+
+// prune throws away the expired entries and the old invalidations, at most once
+// per ttl. It expects the caller to hold the lock.
+func (c *cache) prune(now time.Time) {
+	if now.Sub(c.lastPrune) < c.ttl {
+		return
+	}
+	c.lastPrune = now
+
+	for id, e := range c.players {
+		if now.After(e.expires) {
+			delete(c.players, id)
+		}
+	}
+	for id, e := range c.games {
+		if now.After(e.expires) {
+			delete(c.games, id)
+		}
+	}
+
+	for id, inv := range c.playerGeneration {
+		if now.Sub(inv.at) > c.ttl {
+			c.raiseFloor(inv.generation)
+			delete(c.playerGeneration, id)
+		}
+	}
+	for id, inv := range c.gameGeneration {
+		if now.Sub(inv.at) > c.ttl {
+			c.raiseFloor(inv.generation)
+			delete(c.gameGeneration, id)
+		}
+	}
+}
+
+func (c *cache) raiseFloor(gen uint64) {
+	if gen > c.floor {
+		c.floor = gen
+	}
+}
+
+func copyPlayer(p model.Player) model.Player {
+	if p.Games == nil {
+		return p
+	}
+
+	games := make(map[model.GameID]model.PlayerColor, len(p.Games))
+	for gID, c := range p.Games {
+		games[gID] = c
+	}
+	p.Games = games
+	return p
+}
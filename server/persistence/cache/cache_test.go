@@ -0,0 +1,272 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/interaction"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+	"github.com/joshprzybyszewski/cribbage/server/persistence/memory"
+	"github.com/joshprzybyszewski/cribbage/server/play"
+	"github.com/joshprzybyszewski/cribbage/utils/testutils"
+)
+
+func newTestFactory(t *testing.T) (cached, uncached persistence.DBFactory) {
+	memory.Clear()
+	t.Cleanup(memory.Clear)
+
+	dbf := memory.NewFactory(play.Replay)
+	return NewFactory(dbf, time.Minute), dbf
+}
+
+func newDB(t *testing.T, dbf persistence.DBFactory) persistence.DB {
+	db, err := dbf.New(context.Background())
+	require.NoError(t, err)
+	return db
+}
+
+func createGame(t *testing.T, db persistence.DB) (model.Game, model.Player, map[model.PlayerID]interaction.Player) {
+	alice, bob, abAPIs := testutils.EmptyAliceAndBob()
+	require.NoError(t, db.CreatePlayer(context.Background(), alice))
+	require.NoError(t, db.CreatePlayer(context.Background(), bob))
+
+	g, err := play.CreateGame([]model.Player{alice, bob}, abAPIs)
+	require.NoError(t, err)
+	require.NoError(t, db.CreateGame(context.Background(), g))
+
+	return g, alice, abAPIs
+}
+
+func dealAction(g model.Game) model.PlayerAction {
+	return model.PlayerAction{
+		GameID:    g.ID,
+		ID:        g.CurrentDealer,
+		Overcomes: model.DealCards,
+		Action:    model.DealAction{NumShuffles: 1},
+	}
+}
+
+func TestCacheHits(t *testing.T) {
+	dbf, _ := newTestFactory(t)
+	db := newDB(t, dbf)
+
+	g, alice, _ := createGame(t, db)
+
+	before := GetStats()
+
+	_, err := db.GetPlayer(context.Background(), alice.ID)
+	require.NoError(t, err)
+	_, err = db.GetPlayer(context.Background(), alice.ID)
+	require.NoError(t, err)
+
+	_, err = db.GetGame(context.Background(), g.ID)
+	require.NoError(t, err)
+	_, err = db.GetGame(context.Background(), g.ID)
+	require.NoError(t, err)
+
+	after := GetStats()
+	assert.Equal(t, uint64(1), after.Games.Misses-before.Games.Misses)
+	assert.Equal(t, uint64(1), after.Games.Hits-before.Games.Hits)
+	// The first lookups of alice and bob miss. Every other lookup (including
+	// the ones that GetGame does for the players in the game) are hits.
+	assert.Equal(t, uint64(2), after.Players.Misses-before.Players.Misses)
+	assert.Equal(t, uint64(4), after.Players.Hits-before.Players.Hits)
+}
+
+func TestCacheDoesNotShareMemory(t *testing.T) {
+	dbf, _ := newTestFactory(t)
+	db := newDB(t, dbf)
+
+	g, alice, _ := createGame(t, db)
+
+	g1, err := db.GetGame(context.Background(), g.ID)
+	require.NoError(t, err)
+	g1.Phase = model.Pegging
+	g1.BlockingPlayers[alice.ID] = model.CountCrib
+
+	g2, err := db.GetGame(context.Background(), g.ID)
+	require.NoError(t, err)
+	assert.Equal(t, g.Phase, g2.Phase)
+	assert.Equal(t, g.BlockingPlayers, g2.BlockingPlayers)
+}
+
+func TestCacheInvalidatesOnWrites(t *testing.T) {
+	dbf, _ := newTestFactory(t)
+	reader := newDB(t, dbf)
+	writer := newDB(t, dbf)
+
+	g, alice, abAPIs := createGame(t, writer)
+
+	// populate the cache
+	_, err := reader.GetGame(context.Background(), g.ID)
+	require.NoError(t, err)
+	_, err = reader.GetPlayer(context.Background(), alice.ID)
+	require.NoError(t, err)
+
+	require.NoError(t, writer.Start(context.Background()))
+	require.NoError(t, play.HandleAction(context.Background(), &g, dealAction(g), abAPIs))
+	require.NoError(t, writer.SaveGame(context.Background(), g))
+
+	// the writer sees its own write before it commits
+	wg, err := writer.GetGame(context.Background(), g.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, wg.NumActions())
+
+	require.NoError(t, writer.Commit())
+
+	rg, err := reader.GetGame(context.Background(), g.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, rg.NumActions())
+
+	otherGame := g.ID + 1
+	require.NoError(t, writer.AddPlayerColorToGame(context.Background(), alice.ID, model.Green, otherGame))
+
+	p, err := reader.GetPlayer(context.Background(), alice.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.Green, p.Games[otherGame])
+}
+
+func TestCacheRejectsStaleReads(t *testing.T) {
+	c := newCache(time.Minute)
+	p := model.Player{
+		ID:   model.PlayerID(`alice`),
+		Name: `alice`,
+	}
+
+	// a reader misses, then a writer invalidates before the reader puts
+	_, gen, ok := c.getPlayer(p.ID)
+	require.False(t, ok)
+	c.invalidatePlayer(p.ID)
+	c.putPlayer(p, gen)
+	_, _, ok = c.getPlayer(p.ID)
+	assert.False(t, ok, `a read that started before an invalidation must not be cached`)
+
+	// a reader that started after the invalidation can populate the cache
+	_, gen, ok = c.getPlayer(p.ID)
+	require.False(t, ok)
+	c.putPlayer(p, gen)
+	actP, _, ok := c.getPlayer(p.ID)
+	assert.True(t, ok)
+	assert.Equal(t, p, actP)
+}
+
+func TestCachePrunesOldGenerations(t *testing.T) {
+	now := time.Now()
+	c := newCache(time.Minute)
+	c.now = func() time.Time { return now }
+
+	for i := 0; i < 10; i++ {
+		c.invalidatePlayer(model.PlayerID(fmt.Sprintf(`player%d`, i)))
+		c.invalidateGame(model.GameID(i))
+	}
+	assert.Len(t, c.playerGeneration, 10)
+	assert.Len(t, c.gameGeneration, 10)
+
+	// a slow reader started before all of the invalidations
+	p := model.Player{
+		ID:   model.PlayerID(`player0`),
+		Name: `player0`,
+	}
+	g := model.Game{
+		ID: model.GameID(0),
+	}
+	staleGen := uint64(0)
+
+	// once the ttl has passed, the old invalidations are forgotten
+	now = now.Add(2 * time.Minute)
+	c.invalidatePlayer(model.PlayerID(`somebodyElse`))
+	assert.Len(t, c.playerGeneration, 1)
+	assert.Empty(t, c.gameGeneration)
+
+	// but the slow reader still can't put what it read
+	c.putPlayer(p, staleGen)
+	_, _, ok := c.getPlayer(p.ID)
+	assert.False(t, ok, `a read that started before a forgotten invalidation must not be cached`)
+	c.putGame(g, staleGen)
+	_, _, ok = c.getGame(g.ID)
+	assert.False(t, ok, `a read that started before a forgotten invalidation must not be cached`)
+
+	// a reader that starts now can populate the cache
+	_, gen, ok := c.getPlayer(p.ID)
+	require.False(t, ok)
+	c.putPlayer(p, gen)
+	actP, _, ok := c.getPlayer(p.ID)
+	assert.True(t, ok)
+	assert.Equal(t, p, actP)
+
+	// and the entries are dropped once they've expired
+	now = now.Add(2 * time.Minute)
+	c.invalidateGame(model.GameID(42))
+	assert.Empty(t, c.players)
+}
+
+func TestCacheConsistentWithConcurrentWriters(t *testing.T) {
+	dbf, uncached := newTestFactory(t)
+	setup := newDB(t, dbf)
+
+	g, alice, _ := createGame(t, setup)
+
+	const numWriters = 8
+	const numGamesPerWriter = 10
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+
+	// readers keep populating the cache the whole time
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			db := newDB(t, dbf)
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				_, _ = db.GetPlayer(context.Background(), alice.ID)
+				_, _ = db.GetGame(context.Background(), g.ID)
+			}
+		}()
+	}
+
+	var writers sync.WaitGroup
+	for w := 0; w < numWriters; w++ {
+		writers.Add(1)
+		go func(w int) {
+			defer writers.Done()
+			db := newDB(t, dbf)
+			for i := 0; i < numGamesPerWriter; i++ {
+				gID := model.GameID(1000000 + w*numGamesPerWriter + i)
+				assert.NoError(t, db.Start(context.Background()))
+				assert.NoError(t, db.AddPlayerColorToGame(context.Background(), alice.ID, model.Red, gID), fmt.Sprintf(`writer %d`, w))
+				assert.NoError(t, db.Commit())
+			}
+		}(w)
+	}
+
+	writers.Wait()
+	close(done)
+	wg.Wait()
+
+	exp, err := newDB(t, uncached).GetPlayer(context.Background(), alice.ID)
+	require.NoError(t, err)
+	require.Len(t, exp.Games, 1+numWriters*numGamesPerWriter)
+
+	act, err := newDB(t, dbf).GetPlayer(context.Background(), alice.ID)
+	require.NoError(t, err)
+	assert.Equal(t, exp, act)
+
+	expGame, err := newDB(t, uncached).GetGame(context.Background(), g.ID)
+	require.NoError(t, err)
+	actGame, err := newDB(t, dbf).GetGame(context.Background(), g.ID)
+	require.NoError(t, err)
+	assert.Equal(t, expGame, actGame)
+}
@@ -0,0 +1,164 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+var _ persistence.DB = (*cachedDB)(nil)
+
+// cachedDB answers GetPlayer and GetGame out of the shared cache when it can.
+// Everything it writes during a transaction is "dirty": we stop using the
+// cache for it until the transaction ends, and we invalidate it again after the
+// commit (or rollback) so that other DBs cannot hold onto the old value.
+type cachedDB struct {
+	persistence.DB
+
+	c *cache
+
+	inTx         bool
+	dirtyPlayers map[model.PlayerID]struct{}
+	dirtyGames   map[model.GameID]struct{}
+}
+
+func newCachedDB(db persistence.DB, c *cache) *cachedDB {
+	return &cachedDB{
+		DB:           db,
+		c:            c,
+		dirtyPlayers: map[model.PlayerID]struct{}{},
+		dirtyGames:   map[model.GameID]struct{}{},
+	}
+}
+
+func (cdb *cachedDB) Start(ctx context.Context) error {
+	err := cdb.DB.Start(ctx)
+	if err != nil {
+		return err
+	}
+
+	cdb.inTx = true
+	return nil
+}
+
+func (cdb *cachedDB) Commit() error {
+	defer cdb.endTx()
+
+	return cdb.DB.Commit()
+}
+
+func (cdb *cachedDB) Rollback() error {
+	defer cdb.endTx()
+
+	return cdb.DB.Rollback()
+}
+
+func (cdb *cachedDB) endTx() {
+	for pID := range cdb.dirtyPlayers {
+		cdb.c.invalidatePlayer(pID)
+	}
+	for gID := range cdb.dirtyGames {
+		cdb.c.invalidateGame(gID)
+	}
+
+	cdb.inTx = false
+	cdb.dirtyPlayers = map[model.PlayerID]struct{}{}
+	cdb.dirtyGames = map[model.GameID]struct{}{}
+}
+
+func (cdb *cachedDB) markPlayer(pID model.PlayerID) {
+	cdb.c.invalidatePlayer(pID)
+	if cdb.inTx {
+		cdb.dirtyPlayers[pID] = struct{}{}
+	}
+}
+
+func (cdb *cachedDB) markGame(gID model.GameID) {
+	cdb.c.invalidateGame(gID)
+	if cdb.inTx {
+		cdb.dirtyGames[gID] = struct{}{}
+	}
+}
+
+func (cdb *cachedDB) CreatePlayer(ctx context.Context, p model.Player) error {
+	defer cdb.markPlayer(p.ID)
+
+	return cdb.DB.CreatePlayer(ctx, p)
+}
+
+func (cdb *cachedDB) GetPlayer(ctx context.Context, id model.PlayerID) (model.Player, error) {
+	if _, ok := cdb.dirtyPlayers[id]; ok {
+		return cdb.DB.GetPlayer(ctx, id)
+	}
+
+	p, gen, ok := cdb.c.getPlayer(id)
+	if ok {
+		return p, nil
+	}
+
+	p, err := cdb.DB.GetPlayer(ctx, id)
+	if err != nil {
+		return model.Player{}, err
+	}
+
+	cdb.c.putPlayer(p, gen)
+	return p, nil
+}
+
+func (cdb *cachedDB) AddPlayerColorToGame(
+	ctx context.Context,
+	pID model.PlayerID,
+	color model.PlayerColor,
+	gID model.GameID,
+) error {
+	defer cdb.markPlayer(pID)
+	defer cdb.markGame(gID)
+
+	return cdb.DB.AddPlayerColorToGame(ctx, pID, color, gID)
+}
+
+func (cdb *cachedDB) CreateGame(ctx context.Context, g model.Game) error {
+	// creating a game adds it to each of the players
+	for _, p := range g.Players {
+		defer cdb.markPlayer(p.ID)
+	}
+	defer cdb.markGame(g.ID)
+
+	return cdb.DB.CreateGame(ctx, g)
+}
+
+func (cdb *cachedDB) GetGame(ctx context.Context, id model.GameID) (model.Game, error) {
+	if _, ok := cdb.dirtyGames[id]; ok {
+		return cdb.DB.GetGame(ctx, id)
+	}
+
+	g, gen, ok := cdb.c.getGame(id)
+	if !ok {
+		var err error
+		g, err = cdb.DB.GetGame(ctx, id)
+		if err != nil {
+			return model.Game{}, err
+		}
+
+		cdb.c.putGame(g, gen)
+	}
+
+	// The players in a cached game may have joined other games since we cached it,
+	// so we always give back the players that we know about now.
+	for i, player := range g.Players {
+		p, err := cdb.GetPlayer(ctx, player.ID)
+		if err != nil {
+			return model.Game{}, err
+		}
+		g.Players[i] = p
+	}
+
+	return g, nil
+}
+
+func (cdb *cachedDB) SaveGame(ctx context.Context, g model.Game) error {
+	defer cdb.markGame(g.ID)
+
+	return cdb.DB.SaveGame(ctx, g)
+}
@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"expvar"
+	"sync/atomic"
+
+	"github.com/joshprzybyszewski/cribbage/server/metrics"
+)
+
+var (
+	playerStats = &counter{kind: `players`}
+	gameStats   = &counter{kind: `games`}
+)
+
+func init() {
+	// publish the hit rates so they're visible at /debug/vars
+	expvar.Publish(`persistence_cache`, expvar.Func(func() interface{} {
+		return GetStats()
+	}))
+}
+
+// counter keeps the hits and misses for /debug/vars, and
+// counts them in the server's metrics too
+type counter struct {
+	kind string
+
+	hits   uint64
+	misses uint64
+}
+
+func (c *counter) hit() {
+	atomic.AddUint64(&c.hits, 1)
+	metrics.CacheLookup(c.kind, true)
+}
+
+func (c *counter) miss() {
+	atomic.AddUint64(&c.misses, 1)
+	metrics.CacheLookup(c.kind, false)
+}
+
+func (c *counter) stats() HitStats {
+	return HitStats{
+		Hits:   atomic.LoadUint64(&c.hits),
+		Misses: atomic.LoadUint64(&c.misses),
+	}
+}
+
+// HitStats describe how often the cache had an entry when asked for one
+type HitStats struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+}
+
+// HitRate returns the ratio of lookups that were found in the cache
+func (hs HitStats) HitRate() float64 {
+	total := hs.Hits + hs.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(hs.Hits) / float64(total)
+}
+
+// Stats are the hit statistics for each kind of entry in the cache
+type Stats struct {
+	Players HitStats `json:"players"`
+	Games   HitStats `json:"games"`
+}
+
+// GetStats returns the hit statistics of all caches in this process
+func GetStats() Stats {
+	return Stats{
+		Players: playerStats.stats(),
+		Games:   gameStats.stats(),
+	}
+}
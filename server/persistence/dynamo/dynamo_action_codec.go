@@ -0,0 +1,289 @@
+package dynamo
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+)
+
+const (
+	actionAttributeName = `action`
+
+	actionVersionKey   = `v`
+	actionGameIDKey    = `gID`
+	actionPlayerIDKey  = `pID`
+	actionOvercomesKey = `o`
+	actionTypeKey      = `t`
+	actionKey          = `a`
+	actionTimestampKey = `ts`
+	actionAutoKey      = `auto`
+)
+
+var (
+	errActionAttributeDecode = errors.New(`could not decode action attribute`)
+)
+
+// actionToAttributeValue encodes the player action as a map attribute with the
+// same versioned, type-tagged envelope that we use for JSON and BSON
+func actionToAttributeValue(pa model.PlayerAction) (types.AttributeValue, error) {
+	m := map[string]types.AttributeValue{
+		actionVersionKey:   numberAttribute(model.CurrentActionVersion),
+		actionGameIDKey:    numberAttribute(int(pa.GameID)),
+		actionPlayerIDKey:  &types.AttributeValueMemberS{Value: string(pa.ID)},
+		actionOvercomesKey: numberAttribute(int(pa.Overcomes)),
+	}
+	if pa.TimestampStr != `` {
+		m[actionTimestampKey] = &types.AttributeValueMemberS{Value: pa.TimestampStr}
+	}
+	if pa.Auto {
+		m[actionAutoKey] = &types.AttributeValueMemberBOOL{Value: true}
+	}
+
+	if pa.Action != nil {
+		t, err := model.ActionTypeOf(pa.Action)
+		if err != nil {
+			return nil, err
+		}
+		m[actionTypeKey] = &types.AttributeValueMemberS{Value: string(t)}
+
+		a, err := encodeAction(pa.Action)
+		if err != nil {
+			return nil, err
+		}
+		m[actionKey] = &types.AttributeValueMemberM{Value: a}
+	}
+
+	return &types.AttributeValueMemberM{Value: m}, nil
+}
+
+func encodeAction(a interface{}) (map[string]types.AttributeValue, error) { //nolint:gocyclo
+	switch ta := a.(type) {
+	case *model.DealAction:
+		return encodeAction(*ta)
+	case *model.BuildCribAction:
+		return encodeAction(*ta)
+	case *model.CutDeckAction:
+		return encodeAction(*ta)
+	case *model.PegAction:
+		return encodeAction(*ta)
+	case *model.CountHandAction:
+		return encodeAction(*ta)
+	case *model.CountCribAction:
+		return encodeAction(*ta)
+	case *model.ForfeitAction:
+		return encodeAction(*ta)
+	case *model.RequestTakebackAction:
+		return encodeAction(*ta)
+	case *model.ApproveTakebackAction:
+		return encodeAction(*ta)
+
+	case model.DealAction:
+		m := map[string]types.AttributeValue{
+			`ns`: numberAttribute(ta.NumShuffles),
+		}
+		if ta.Entropy != `` {
+			// only provably fair games use the entropy
+			m[`e`] = &types.AttributeValueMemberS{Value: ta.Entropy}
+		}
+		return m, nil
+	case model.BuildCribAction:
+		cs := make([]types.AttributeValue, len(ta.Cards))
+		for i, c := range ta.Cards {
+			cs[i] = numberAttribute(int(c.ToTinyInt()))
+		}
+		return map[string]types.AttributeValue{
+			`cs`: &types.AttributeValueMemberL{Value: cs},
+		}, nil
+	case model.CutDeckAction:
+		return map[string]types.AttributeValue{
+			`p`: &types.AttributeValueMemberN{Value: strconv.FormatFloat(ta.Percentage, 'g', -1, 64)},
+		}, nil
+	case model.PegAction:
+		m := map[string]types.AttributeValue{
+			`sg`: &types.AttributeValueMemberBOOL{Value: ta.SayGo},
+		}
+		if ta.Card != (model.Card{}) {
+			// saying go doesn't have a card
+			m[`c`] = numberAttribute(int(ta.Card.ToTinyInt()))
+		}
+		return m, nil
+	case model.CountHandAction:
+		return map[string]types.AttributeValue{
+			`pts`: numberAttribute(ta.Pts),
+		}, nil
+	case model.CountCribAction:
+		return map[string]types.AttributeValue{
+			`pts`: numberAttribute(ta.Pts),
+		}, nil
+	case model.ForfeitAction:
+		return map[string]types.AttributeValue{
+			`to`: &types.AttributeValueMemberBOOL{Value: ta.TimedOut},
+		}, nil
+	case model.RequestTakebackAction:
+		return map[string]types.AttributeValue{}, nil
+	case model.ApproveTakebackAction:
+		return map[string]types.AttributeValue{
+			`ok`: &types.AttributeValueMemberBOOL{Value: ta.Approve},
+		}, nil
+	}
+
+	return nil, model.ErrUnknownActionType
+}
+
+// actionFromAttributeValue is the inverse of actionToAttributeValue
+func actionFromAttributeValue(av types.AttributeValue) (model.PlayerAction, error) {
+	m, ok := av.(*types.AttributeValueMemberM)
+	if !ok {
+		return model.PlayerAction{}, errActionAttributeDecode
+	}
+
+	version, err := getIntAttribute(m.Value, actionVersionKey)
+	if err != nil {
+		// a missing version is from before there was one
+		version = 0
+	}
+	gID, err := getIntAttribute(m.Value, actionGameIDKey)
+	if err != nil {
+		return model.PlayerAction{}, err
+	}
+	o, err := getIntAttribute(m.Value, actionOvercomesKey)
+	if err != nil {
+		return model.PlayerAction{}, err
+	}
+
+	pa := model.PlayerAction{
+		GameID:       model.GameID(gID),
+		ID:           model.PlayerID(getStringAttribute(m.Value, actionPlayerIDKey)),
+		Overcomes:    model.Blocker(o),
+		Auto:         getBoolAttribute(m.Value, actionAutoKey),
+		TimestampStr: getStringAttribute(m.Value, actionTimestampKey),
+	}
+
+	t, err := model.ResolveActionType(
+		version,
+		model.ActionType(getStringAttribute(m.Value, actionTypeKey)),
+		pa.Overcomes,
+	)
+	if err != nil {
+		return model.PlayerAction{}, err
+	}
+
+	var a map[string]types.AttributeValue
+	if am, ok := m.Value[actionKey].(*types.AttributeValueMemberM); ok {
+		a = am.Value
+	}
+	pa.Action, err = decodeAction(t, a)
+	if err != nil {
+		return model.PlayerAction{}, err
+	}
+
+	return pa, nil
+}
+
+func decodeAction(t model.ActionType, a map[string]types.AttributeValue) (interface{}, error) { //nolint:gocyclo
+	switch t {
+	case model.DealActionType:
+		ns, err := getOptionalIntAttribute(a, `ns`)
+		return model.DealAction{
+			NumShuffles: ns,
+			Entropy:     getStringAttribute(a, `e`),
+		}, err
+	case model.BuildCribActionType:
+		var cards []model.Card
+		if l, ok := a[`cs`].(*types.AttributeValueMemberL); ok {
+			cards = make([]model.Card, len(l.Value))
+			for i, cav := range l.Value {
+				c, err := cardFromAttributeValue(cav)
+				if err != nil {
+					return nil, err
+				}
+				cards[i] = c
+			}
+		}
+		return model.BuildCribAction{Cards: cards}, nil
+	case model.CutDeckActionType:
+		p := 0.0
+		if n, ok := a[`p`].(*types.AttributeValueMemberN); ok {
+			var err error
+			p, err = strconv.ParseFloat(n.Value, 64)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return model.CutDeckAction{Percentage: p}, nil
+	case model.PegActionType:
+		pa := model.PegAction{
+			SayGo: getBoolAttribute(a, `sg`),
+		}
+		if cav, ok := a[`c`]; ok {
+			c, err := cardFromAttributeValue(cav)
+			if err != nil {
+				return nil, err
+			}
+			pa.Card = c
+		}
+		return pa, nil
+	case model.CountHandActionType:
+		pts, err := getOptionalIntAttribute(a, `pts`)
+		return model.CountHandAction{Pts: pts}, err
+	case model.CountCribActionType:
+		pts, err := getOptionalIntAttribute(a, `pts`)
+		return model.CountCribAction{Pts: pts}, err
+	case model.ForfeitActionType:
+		return model.ForfeitAction{TimedOut: getBoolAttribute(a, `to`)}, nil
+	case model.RequestTakebackActionType:
+		return model.RequestTakebackAction{}, nil
+	case model.ApproveTakebackActionType:
+		return model.ApproveTakebackAction{Approve: getBoolAttribute(a, `ok`)}, nil
+	}
+
+	return nil, model.ErrUnknownActionType
+}
+
+func numberAttribute(n int) types.AttributeValue {
+	return &types.AttributeValueMemberN{Value: strconv.Itoa(n)}
+}
+
+func getIntAttribute(m map[string]types.AttributeValue, key string) (int, error) {
+	n, ok := m[key].(*types.AttributeValueMemberN)
+	if !ok {
+		return 0, errActionAttributeDecode
+	}
+	return strconv.Atoi(n.Value)
+}
+
+func getOptionalIntAttribute(m map[string]types.AttributeValue, key string) (int, error) {
+	if _, ok := m[key]; !ok {
+		return 0, nil
+	}
+	return getIntAttribute(m, key)
+}
+
+func getStringAttribute(m map[string]types.AttributeValue, key string) string {
+	if s, ok := m[key].(*types.AttributeValueMemberS); ok {
+		return s.Value
+	}
+	return ``
+}
+
+func getBoolAttribute(m map[string]types.AttributeValue, key string) bool {
+	if b, ok := m[key].(*types.AttributeValueMemberBOOL); ok {
+		return b.Value
+	}
+	return false
+}
+
+func cardFromAttributeValue(av types.AttributeValue) (model.Card, error) {
+	n, ok := av.(*types.AttributeValueMemberN)
+	if !ok {
+		return model.Card{}, errActionAttributeDecode
+	}
+	i, err := strconv.Atoi(n.Value)
+	if err != nil {
+		return model.Card{}, err
+	}
+	return model.NewCardFromTinyInt(int8(i))
+}
@@ -0,0 +1,73 @@
+package dynamo
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+)
+
+func TestActionAttributeValueRoundTrip(t *testing.T) {
+	actions := []interface{}{
+		model.DealAction{NumShuffles: 7},
+		model.DealAction{NumShuffles: 2, Entropy: `some entropy`},
+		model.BuildCribAction{Cards: []model.Card{
+			model.NewCardFromString(`jh`),
+			model.NewCardFromString(`5d`),
+		}},
+		model.CutDeckAction{Percentage: 0.314},
+		model.PegAction{Card: model.NewCardFromString(`as`)},
+		model.PegAction{SayGo: true},
+		model.CountHandAction{Pts: 29},
+		model.CountCribAction{Pts: 4},
+		model.ForfeitAction{TimedOut: true},
+		model.RequestTakebackAction{},
+		model.ApproveTakebackAction{Approve: true},
+	}
+
+	for i, a := range actions {
+		at, err := model.ActionTypeOf(a)
+		require.NoError(t, err)
+		pa := model.PlayerAction{
+			GameID:       model.GameID(42),
+			ID:           model.PlayerID(`alice`),
+			Action:       a,
+			Auto:         i%2 == 0,
+			TimestampStr: `2021-01-02T03:04:05Z`,
+		}
+
+		av, err := actionToAttributeValue(pa)
+		require.NoError(t, err, at)
+		m := av.(*types.AttributeValueMemberM).Value
+		assert.Equal(t, string(at), m[actionTypeKey].(*types.AttributeValueMemberS).Value)
+
+		actPA, err := actionFromAttributeValue(av)
+		require.NoError(t, err, at)
+		assert.Equal(t, pa, actPA, at)
+	}
+}
+
+func TestActionFromAttributeValueWithoutVersion(t *testing.T) {
+	av := &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+		actionGameIDKey:    numberAttribute(42),
+		actionPlayerIDKey:  &types.AttributeValueMemberS{Value: `alice`},
+		actionOvercomesKey: numberAttribute(int(model.CountCrib)),
+		actionKey: &types.AttributeValueMemberM{Value: map[string]types.AttributeValue{
+			`pts`: numberAttribute(12),
+		}},
+	}}
+
+	pa, err := actionFromAttributeValue(av)
+	require.NoError(t, err)
+	assert.Equal(t, model.CountCribAction{Pts: 12}, pa.Action)
+
+	av.Value[actionVersionKey] = numberAttribute(model.CurrentActionVersion + 1)
+	_, err = actionFromAttributeValue(av)
+	assert.Equal(t, model.ErrUnknownActionVersion, err)
+
+	_, err = actionFromAttributeValue(&types.AttributeValueMemberS{Value: `nope`})
+	assert.Error(t, err)
+}
@@ -0,0 +1,126 @@
+package dynamo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+const (
+	chatMessageAttributeName = `msg`
+)
+
+var _ persistence.ChatService = (*chatService)(nil)
+
+// chatService keeps the chat messages in the same partition as their game.
+// The sort key has the time the message was sent, so they come back in order.
+type chatService struct {
+	svc *dynamodb.Client
+}
+
+func newChatService(
+	svc *dynamodb.Client,
+) persistence.ChatService {
+	return &chatService{
+		svc: svc,
+	}
+}
+
+func (cs *chatService) Get(ctx context.Context, gID model.GameID) ([]model.ChatMessage, error) {
+	pkName := `:gID`
+	skName := `:sk`
+	hp := hasPrefix{
+		pkName: pkName,
+		skName: skName,
+	}
+
+	createQuery := newQueryInputFactory(getQueryInputParams(
+		strconv.Itoa(int(gID)), pkName,
+		cs.getSpecForAllMessages(), skName,
+		hp.conditionExpression(),
+	))
+
+	items, err := fullQuery(ctx, cs.svc, createQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	msgs := make([]model.ChatMessage, 0, len(items))
+	var cm model.ChatMessage
+	for _, item := range items {
+		cm, err = cs.getMessageFromItem(gID, item)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, cm)
+	}
+
+	return msgs, nil
+}
+
+func (cs *chatService) getMessageFromItem(
+	gID model.GameID,
+	item map[string]types.AttributeValue,
+) (model.ChatMessage, error) {
+	sk, ok := item[sortKey].(*types.AttributeValueMemberS)
+	if !ok {
+		return model.ChatMessage{}, fmt.Errorf(`wrong %s type`, sortKey)
+	}
+	msg, ok := item[chatMessageAttributeName].(*types.AttributeValueMemberS)
+	if !ok {
+		return model.ChatMessage{}, fmt.Errorf(`wrong %s type`, chatMessageAttributeName)
+	}
+
+	// the sort key looks like chat@<sent>@<playerID>
+	parts := strings.SplitN(strings.TrimPrefix(sk.Value, cs.getSpecForAllMessages()), `@`, 2)
+	if len(parts) != 2 {
+		return model.ChatMessage{}, fmt.Errorf(`unexpected sort key: %q`, sk.Value)
+	}
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return model.ChatMessage{}, err
+	}
+
+	return model.ChatMessage{
+		GameID:   gID,
+		PlayerID: model.PlayerID(parts[1]),
+		Message:  msg.Value,
+		Sent:     time.Unix(0, nanos).UTC(),
+	}, nil
+}
+
+func (cs *chatService) Add(ctx context.Context, cm model.ChatMessage) error {
+	_, err := cs.svc.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(dbName),
+		Item: map[string]types.AttributeValue{
+			partitionKey: &types.AttributeValueMemberS{
+				Value: strconv.Itoa(int(cm.GameID)),
+			},
+			sortKey: &types.AttributeValueMemberS{
+				Value: cs.getSpecForMessage(cm),
+			},
+			chatMessageAttributeName: &types.AttributeValueMemberS{
+				Value: cm.Message,
+			},
+		},
+	})
+	return err
+}
+
+func (cs *chatService) getSpecForAllMessages() string {
+	return getSortKeyPrefix(cs) + `@`
+}
+
+func (cs *chatService) getSpecForMessage(cm model.ChatMessage) string {
+	// pad the time so that the sort keys are in the order the messages were sent
+	return fmt.Sprintf("%s%020d@%s", cs.getSpecForAllMessages(), cm.Sent.UnixNano(), cm.PlayerID)
+}
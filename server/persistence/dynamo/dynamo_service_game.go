@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
@@ -18,35 +19,45 @@ import (
 )
 
 const (
-	gameBytesAttributeName = `gameBytes`
+	gameBytesAttributeName     = `gameBytes`
+	gameCompactedAttributeName = `compacted`
+
+	// every finished game that hasn't been compacted yet has an item in the
+	// same partition, so that the compactor can query for them
+	gamesToCompactPartition = `gamesToCompact`
+
+	// every game that isn't over has an item in the same partition, so that
+	// we can count them. The games begun before we kept them there aren't counted.
+	activeGamesPartition = `activeGames`
+
+	// every game that is waiting on a player with a move timeout has an item
+	// in the same partition, so that we can query for the overdue ones
+	moveDeadlinesPartition    = `moveDeadlines`
+	moveDeadlineAttributeName = `moveDeadline`
 )
 
 var _ persistence.GameService = (*gameService)(nil)
 
 type gameService struct {
-	ctx context.Context
-
 	svc *dynamodb.Client
 }
 
 func newGameService(
-	ctx context.Context,
 	svc *dynamodb.Client,
 ) persistence.GameService {
 	return &gameService{
-		ctx: ctx,
 		svc: svc,
 	}
 }
 
-func (gs *gameService) Get(id model.GameID) (model.Game, error) {
-	return gs.getGame(id, getGameOptions{
+func (gs *gameService) Get(ctx context.Context, id model.GameID) (model.Game, error) {
+	return gs.getGame(ctx, id, getGameOptions{
 		latest: true,
 	})
 }
 
-func (gs *gameService) GetAt(id model.GameID, numActions uint) (model.Game, error) {
-	return gs.getGame(id, getGameOptions{
+func (gs *gameService) GetAt(ctx context.Context, id model.GameID, numActions uint) (model.Game, error) {
+	return gs.getGame(ctx, id, getGameOptions{
 		actionIndex: numActions,
 	})
 }
@@ -57,6 +68,7 @@ type getGameOptions struct {
 }
 
 func (gs *gameService) getGame(
+	ctx context.Context,
 	id model.GameID,
 	opts getGameOptions,
 ) (model.Game, error) {
@@ -87,7 +99,7 @@ func (gs *gameService) getGame(
 			},
 		},
 	}
-	qo, err := gs.svc.Query(gs.ctx, qi)
+	qo, err := gs.svc.Query(ctx, qi)
 	if err != nil {
 		return model.Game{}, err
 	}
@@ -113,13 +125,21 @@ func (gs *gameService) getGame(
 
 	gb, ok := item[gs.getSerGameKey()].(*types.AttributeValueMemberB)
 	if !ok {
+		if _, isCompacted := item[gameCompactedAttributeName]; isCompacted {
+			return model.Game{}, persistence.ErrGameSnapshotCompacted
+		}
 		return model.Game{}, persistence.ErrGameActionDecode
 	}
 	return jsonutils.UnmarshalGame(gb.Value)
 }
 
-func (gs *gameService) UpdatePlayerColor(gID model.GameID, pID model.PlayerID, color model.PlayerColor) error {
-	g, err := gs.Get(gID)
+func (gs *gameService) UpdatePlayerColor(
+	ctx context.Context,
+	gID model.GameID,
+	pID model.PlayerID,
+	color model.PlayerColor,
+) error {
+	g, err := gs.Get(ctx, gID)
 	if err != nil {
 		return err
 	}
@@ -138,28 +158,37 @@ func (gs *gameService) UpdatePlayerColor(gID model.GameID, pID model.PlayerID, c
 	}
 	g.PlayerColors[pID] = color
 
-	return gs.writeGame(writeGameOptions{
+	return gs.writeGame(ctx, writeGameOptions{
 		game:        g,
 		actionIndex: uint(len(g.Actions)),
 		overwrite:   true,
 	})
 }
 
-func (gs *gameService) Begin(g model.Game) error {
-	return gs.writeGame(writeGameOptions{
+func (gs *gameService) Begin(ctx context.Context, g model.Game) error {
+	err := gs.writeGame(ctx, writeGameOptions{
 		game:        g,
 		actionIndex: 0,
 	})
+	if err != nil {
+		return err
+	}
+
+	_, err = gs.svc.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(dbName),
+		Item:      gs.getPartitionKey(activeGamesPartition, g.ID),
+	})
+	return err
 }
 
-func (gs *gameService) Save(g model.Game) error {
+func (gs *gameService) Save(ctx context.Context, g model.Game) error {
 	err := persistence.ValidateLatestActionBelongs(g)
 	if err != nil {
 		return err
 	}
 
 	// validate that the actions on this game are known by the previous game.
-	sg, err := gs.getGame(g.ID, getGameOptions{
+	sg, err := gs.getGame(ctx, g.ID, getGameOptions{
 		latest: true,
 	})
 	if err != nil {
@@ -175,10 +204,203 @@ func (gs *gameService) Save(g model.Game) error {
 		}
 	}
 
-	return gs.writeGame(writeGameOptions{
+	err = gs.writeGame(ctx, writeGameOptions{
 		game:        g,
 		actionIndex: uint(len(sg.Actions) + 1),
 	})
+	if err != nil {
+		return err
+	}
+
+	if g.IsOver() {
+		return gs.markFinished(ctx, g.ID)
+	}
+	return nil
+}
+
+func (gs *gameService) Compact(ctx context.Context, id model.GameID, numActions []uint) error {
+	latest, err := gs.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	for _, na := range numActions {
+		if int(na) >= latest.NumActions() {
+			// never throw away the latest snapshot
+			continue
+		}
+
+		// We replace the snapshot with a tombstone so that we know
+		// the difference between a compacted snapshot and a missing one.
+		_, err = gs.svc.PutItem(ctx, &dynamodb.PutItemInput{
+			TableName: aws.String(dbName),
+			Item: map[string]types.AttributeValue{
+				partitionKey: &types.AttributeValueMemberS{
+					Value: strconv.Itoa(int(id)),
+				},
+				sortKey: &types.AttributeValueMemberS{
+					Value: gs.getSpecForGameActionIndex(na),
+				},
+				gameCompactedAttributeName: &types.AttributeValueMemberBOOL{
+					Value: true,
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = gs.svc.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(dbName),
+		Key:       gs.getToCompactKey(id),
+	})
+	return err
+}
+
+func (gs *gameService) GetUncompacted(ctx context.Context) ([]model.GameID, error) {
+	return gs.getGameIDsInPartition(ctx, gamesToCompactPartition, nil)
+}
+
+func (gs *gameService) CountActive(ctx context.Context) (int, error) {
+	gIDs, err := gs.getGameIDsInPartition(ctx, activeGamesPartition, nil)
+	if err != nil {
+		return 0, err
+	}
+	return len(gIDs), nil
+}
+
+func (gs *gameService) SetMoveDeadline(ctx context.Context, id model.GameID, deadline time.Time) error {
+	key := gs.getPartitionKey(moveDeadlinesPartition, id)
+	if deadline.IsZero() {
+		_, err := gs.svc.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+			TableName: aws.String(dbName),
+			Key:       key,
+		})
+		return err
+	}
+
+	key[moveDeadlineAttributeName] = &types.AttributeValueMemberN{
+		Value: strconv.FormatInt(deadline.UnixNano(), 10),
+	}
+	_, err := gs.svc.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(dbName),
+		Item:      key,
+	})
+	return err
+}
+
+func (gs *gameService) GetMoveDeadline(ctx context.Context, id model.GameID) (time.Time, error) {
+	gio, err := gs.svc.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(dbName),
+		Key:       gs.getPartitionKey(moveDeadlinesPartition, id),
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// the item is deleted when the game doesn't have a deadline
+	n, ok := gio.Item[moveDeadlineAttributeName].(*types.AttributeValueMemberN)
+	if !ok {
+		return time.Time{}, nil
+	}
+	nanos, err := strconv.ParseInt(n.Value, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(0, nanos), nil
+}
+
+func (gs *gameService) GetOverdue(ctx context.Context, now time.Time) ([]model.GameID, error) {
+	nowName := `:now`
+	return gs.getGameIDsInPartition(ctx, moveDeadlinesPartition, func(qi *dynamodb.QueryInput) {
+		qi.FilterExpression = aws.String(moveDeadlineAttributeName + ` < ` + nowName)
+		qi.ExpressionAttributeValues[nowName] = &types.AttributeValueMemberN{
+			Value: strconv.FormatInt(now.UnixNano(), 10),
+		}
+	})
+}
+
+// getGameIDsInPartition returns the IDs of the games which have an item in the given
+// partition. The filter may be nil, or it may add a FilterExpression to the query.
+func (gs *gameService) getGameIDsInPartition(
+	ctx context.Context,
+	partition string,
+	filter func(*dynamodb.QueryInput),
+) ([]model.GameID, error) {
+	pkName := `:pk`
+	skName := `:sk`
+	hp := hasPrefix{
+		pkName: pkName,
+		skName: skName,
+	}
+
+	createQuery := func() *dynamodb.QueryInput {
+		qi := newQueryInputFactory(getQueryInputParams(
+			partition, pkName,
+			gs.getSpecForAllGames(), skName,
+			hp.conditionExpression(),
+		))()
+		if filter != nil {
+			filter(qi)
+		}
+		return qi
+	}
+
+	items, err := fullQuery(ctx, gs.svc, createQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	gIDs := make([]model.GameID, 0, len(items))
+	var id int
+	for _, item := range items {
+		spec, ok := item[sortKey].(*types.AttributeValueMemberS)
+		if !ok {
+			return nil, fmt.Errorf(`wrong %s type`, sortKey)
+		}
+		id, err = strconv.Atoi(strings.TrimPrefix(spec.Value, gs.getSpecForAllGames()))
+		if err != nil {
+			return nil, err
+		}
+		gIDs = append(gIDs, model.GameID(id))
+	}
+
+	return gIDs, nil
+}
+
+// markFinished lets the compactor find the game, and stops counting it as active
+func (gs *gameService) markFinished(ctx context.Context, id model.GameID) error {
+	_, err := gs.svc.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(dbName),
+		Item:      gs.getToCompactKey(id),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = gs.svc.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(dbName),
+		Key:       gs.getPartitionKey(activeGamesPartition, id),
+	})
+	return err
+}
+
+func (gs *gameService) getToCompactKey(id model.GameID) map[string]types.AttributeValue {
+	return gs.getPartitionKey(gamesToCompactPartition, id)
+}
+
+// getPartitionKey returns the key of the game's item in one of the partitions
+// which keep an item per game
+func (gs *gameService) getPartitionKey(partition string, id model.GameID) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		partitionKey: &types.AttributeValueMemberS{
+			Value: partition,
+		},
+		sortKey: &types.AttributeValueMemberS{
+			Value: gs.getSpecForGame(id),
+		},
+	}
 }
 
 func actionsAreEqual(a, b model.PlayerAction) bool {
@@ -196,7 +418,7 @@ type writeGameOptions struct {
 
 // writeGame will write the given game and action
 // This method assumes you've already done game state validation.
-func (gs *gameService) writeGame(opts writeGameOptions) error {
+func (gs *gameService) writeGame(ctx context.Context, opts writeGameOptions) error {
 	obj, err := json.Marshal(opts.game)
 	if err != nil {
 		return err
@@ -217,6 +439,16 @@ func (gs *gameService) writeGame(opts writeGameOptions) error {
 		},
 	}
 
+	if n := len(opts.game.Actions); n > 0 {
+		// store the action this snapshot was written for on its own, so it can
+		// be read without decoding the whole game
+		av, err := actionToAttributeValue(opts.game.Actions[n-1])
+		if err != nil {
+			return err
+		}
+		pii.Item[actionAttributeName] = av
+	}
+
 	if opts.overwrite {
 		// we want to find out if we overwrote items, so specify ReturnValues
 		pii.ReturnValues = types.ReturnValueAllOld
@@ -228,7 +460,7 @@ func (gs *gameService) writeGame(opts writeGameOptions) error {
 		pii.ConditionExpression = notExists{}.conditionExpression()
 	}
 
-	pio, err := gs.svc.PutItem(gs.ctx, pii)
+	pio, err := gs.svc.PutItem(ctx, pii)
 	if err != nil {
 		if isConditionalError(err) {
 			return persistence.ErrGameActionsOutOfOrder
@@ -263,6 +495,14 @@ func (gs *gameService) getSpecForGameActionIndex(i uint) string {
 	return gs.getSpecForAllGameActions() + fmt.Sprintf(`%06d`, i)
 }
 
+func (gs *gameService) getSpecForAllGames() string {
+	return getSortKeyPrefix(gs) + `#`
+}
+
+func (gs *gameService) getSpecForGame(id model.GameID) string {
+	return gs.getSpecForAllGames() + fmt.Sprintf(`%010d`, id)
+}
+
 func (gs *gameService) getGameActionIndexFromSpec(s string) (int, error) {
 	s = strings.TrimPrefix(s, gs.getSpecForAllGameActions())
 	return strconv.Atoi(s)
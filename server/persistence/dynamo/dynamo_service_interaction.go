@@ -18,27 +18,25 @@ import (
 const (
 	interactionInfoAttributeName   = `info`
 	interactionPreferAttributeName = `prefer`
+	interactionAutoAttributeName   = `auto`
 )
 
 var _ persistence.InteractionService = (*interactionService)(nil)
 
 type interactionService struct {
-	ctx context.Context
-
 	svc *dynamodb.Client
 }
 
 func newInteractionService(
-	ctx context.Context,
 	svc *dynamodb.Client,
 ) persistence.InteractionService {
 	return &interactionService{
-		ctx: ctx,
 		svc: svc,
 	}
 }
 
 func (is *interactionService) Get(
+	ctx context.Context,
 	id model.PlayerID,
 ) (interaction.PlayerMeans, error) {
 	pkName := `:ipID`
@@ -53,7 +51,7 @@ func (is *interactionService) Get(
 	createQuery := newQueryInputFactory(getQueryInputParams(
 		pk, pkName, sk, skName, hp.conditionExpression(),
 	))
-	items, err := fullQuery(is.ctx, is.svc, createQuery)
+	items, err := fullQuery(ctx, is.svc, createQuery)
 	if err != nil {
 		return interaction.PlayerMeans{}, err
 	}
@@ -87,6 +85,7 @@ func (is *interactionService) buildPlayerMeansFromItems(
 			}
 
 			pm.PreferredMode = interaction.Mode(preferMode)
+			pm.AutoPlay = getBoolAttribute(item, is.getAutoKey())
 			continue
 		}
 
@@ -145,15 +144,15 @@ func (is *interactionService) getSerInfo(
 	return infoAVB.Value, nil
 }
 
-func (is *interactionService) Create(pm interaction.PlayerMeans) error {
-	return is.write(writePlayerMeansOptions{
+func (is *interactionService) Create(ctx context.Context, pm interaction.PlayerMeans) error {
+	return is.write(ctx, writePlayerMeansOptions{
 		pm:         pm,
 		isCreation: true,
 	})
 }
 
-func (is *interactionService) Update(pm interaction.PlayerMeans) error {
-	return is.write(writePlayerMeansOptions{
+func (is *interactionService) Update(ctx context.Context, pm interaction.PlayerMeans) error {
+	return is.write(ctx, writePlayerMeansOptions{
 		pm: pm,
 	})
 }
@@ -163,7 +162,7 @@ type writePlayerMeansOptions struct {
 	isCreation bool
 }
 
-func (is *interactionService) write(opts writePlayerMeansOptions) error {
+func (is *interactionService) write(ctx context.Context, opts writePlayerMeansOptions) error {
 	data := map[string]types.AttributeValue{
 		partitionKey: &types.AttributeValueMemberS{
 			Value: string(opts.pm.PlayerID),
@@ -174,6 +173,9 @@ func (is *interactionService) write(opts writePlayerMeansOptions) error {
 		is.getPreferKey(): &types.AttributeValueMemberN{
 			Value: strconv.Itoa(int(opts.pm.PreferredMode)),
 		},
+		is.getAutoKey(): &types.AttributeValueMemberBOOL{
+			Value: opts.pm.AutoPlay,
+		},
 	}
 
 	pii := &dynamodb.PutItemInput{
@@ -189,7 +191,7 @@ func (is *interactionService) write(opts writePlayerMeansOptions) error {
 		pii.ConditionExpression = notExists{}.conditionExpression()
 	}
 
-	_, err := is.svc.PutItem(is.ctx, pii)
+	_, err := is.svc.PutItem(ctx, pii)
 	if err != nil {
 		if isConditionalError(err) {
 			return persistence.ErrInteractionAlreadyExists
@@ -202,7 +204,7 @@ func (is *interactionService) write(opts writePlayerMeansOptions) error {
 		if err != nil {
 			return err
 		}
-		_, err = is.svc.PutItem(is.ctx, pii)
+		_, err = is.svc.PutItem(ctx, pii)
 		if err != nil {
 			return err
 		}
@@ -258,3 +260,7 @@ func (is *interactionService) getInfoKey() string {
 func (is *interactionService) getPreferKey() string {
 	return interactionPreferAttributeName
 }
+
+func (is *interactionService) getAutoKey() string {
+	return interactionAutoAttributeName
+}
@@ -0,0 +1,205 @@
+package dynamo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+const (
+	// every lobby lives in the same partition so that we can query for the open ones
+	lobbiesPartition = `lobbies`
+
+	lobbyBytesAttributeName   = `lobbyBytes`
+	lobbyStatusAttributeName  = `status`
+	lobbyVersionAttributeName = `version`
+)
+
+var _ persistence.LobbyService = (*lobbyService)(nil)
+
+type lobbyService struct {
+	svc *dynamodb.Client
+}
+
+func newLobbyService(
+	svc *dynamodb.Client,
+) persistence.LobbyService {
+	return &lobbyService{
+		svc: svc,
+	}
+}
+
+func (ls *lobbyService) Get(ctx context.Context, id model.LobbyID) (model.Lobby, error) {
+	gio, err := ls.svc.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(dbName),
+		Key: map[string]types.AttributeValue{
+			partitionKey: &types.AttributeValueMemberS{
+				Value: lobbiesPartition,
+			},
+			sortKey: &types.AttributeValueMemberS{
+				Value: ls.getSpecForLobby(id),
+			},
+		},
+	})
+	if err != nil {
+		return model.Lobby{}, err
+	}
+	if len(gio.Item) == 0 {
+		return model.Lobby{}, persistence.ErrLobbyNotFound
+	}
+
+	return ls.getLobbyFromItem(gio.Item)
+}
+
+func (ls *lobbyService) GetOpen(ctx context.Context) ([]model.Lobby, error) {
+	pkName := `:lp`
+	skName := `:sk`
+	statusName := `:st`
+	hp := hasPrefix{
+		pkName: pkName,
+		skName: skName,
+	}
+
+	createQuery := func() *dynamodb.QueryInput {
+		qi := newQueryInputFactory(getQueryInputParams(
+			lobbiesPartition, pkName,
+			ls.getSpecForAllLobbies(), skName,
+			hp.conditionExpression(),
+		))()
+		qi.FilterExpression = aws.String(lobbyStatusAttributeName + ` = ` + statusName)
+		qi.ExpressionAttributeValues[statusName] = &types.AttributeValueMemberN{
+			Value: strconv.Itoa(int(model.LobbyOpen)),
+		}
+		return qi
+	}
+
+	items, err := fullQuery(ctx, ls.svc, createQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	lobbies := make([]model.Lobby, 0, len(items))
+	var l model.Lobby
+	for _, item := range items {
+		l, err = ls.getLobbyFromItem(item)
+		if err != nil {
+			return nil, err
+		}
+		lobbies = append(lobbies, l)
+	}
+	sort.Slice(lobbies, func(i, j int) bool {
+		return lobbies[i].Created.Before(lobbies[j].Created)
+	})
+
+	return lobbies, nil
+}
+
+func (ls *lobbyService) getLobbyFromItem(
+	item map[string]types.AttributeValue,
+) (model.Lobby, error) {
+	lb, ok := item[lobbyBytesAttributeName].(*types.AttributeValueMemberB)
+	if !ok {
+		return model.Lobby{}, fmt.Errorf(`wrong %s type`, lobbyBytesAttributeName)
+	}
+
+	l := model.Lobby{}
+	err := json.Unmarshal(lb.Value, &l)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+	return l, nil
+}
+
+func (ls *lobbyService) Create(ctx context.Context, l model.Lobby) error {
+	return ls.write(ctx, l, true)
+}
+
+func (ls *lobbyService) Update(ctx context.Context, l model.Lobby) error {
+	return ls.write(ctx, l, false)
+}
+
+func (ls *lobbyService) write(ctx context.Context, l model.Lobby, isCreation bool) error {
+	prevVersion := l.Version
+	if !isCreation {
+		l.Version++
+	}
+
+	obj, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+
+	pii := &dynamodb.PutItemInput{
+		TableName: aws.String(dbName),
+		Item: map[string]types.AttributeValue{
+			partitionKey: &types.AttributeValueMemberS{
+				Value: lobbiesPartition,
+			},
+			sortKey: &types.AttributeValueMemberS{
+				Value: ls.getSpecForLobby(l.ID),
+			},
+			lobbyStatusAttributeName: &types.AttributeValueMemberN{
+				Value: strconv.Itoa(int(l.Status)),
+			},
+			lobbyVersionAttributeName: &types.AttributeValueMemberN{
+				Value: strconv.FormatUint(l.Version, 10),
+			},
+			lobbyBytesAttributeName: &types.AttributeValueMemberB{
+				Value: obj,
+			},
+		},
+	}
+
+	if isCreation {
+		pii.ConditionExpression = notExists{}.conditionExpression()
+	} else {
+		// only update the version of the lobby that we read
+		versionName := `:v`
+		cond := lobbyVersionAttributeName + ` = ` + versionName
+		if prevVersion == 0 {
+			// the lobbies saved before we had versions don't have one
+			cond = `attribute_not_exists(` + lobbyVersionAttributeName + `) or ` + cond
+		}
+		pii.ConditionExpression = aws.String(`attribute_exists(` + partitionKey + `) and (` + cond + `)`)
+		pii.ExpressionAttributeValues = map[string]types.AttributeValue{
+			versionName: &types.AttributeValueMemberN{
+				Value: strconv.FormatUint(prevVersion, 10),
+			},
+		}
+	}
+
+	_, err = ls.svc.PutItem(ctx, pii)
+	if err != nil {
+		if isConditionalError(err) {
+			if isCreation {
+				return persistence.ErrLobbyAlreadyExists
+			}
+			// either the lobby doesn't exist, or somebody else saved it first
+			_, err = ls.Get(ctx, l.ID)
+			if err != nil {
+				return err
+			}
+			return persistence.ErrLobbyConflict
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (ls *lobbyService) getSpecForAllLobbies() string {
+	return getSortKeyPrefix(ls) + `@`
+}
+
+func (ls *lobbyService) getSpecForLobby(id model.LobbyID) string {
+	return ls.getSpecForAllLobbies() + fmt.Sprintf(`%010d`, id)
+}
@@ -0,0 +1,176 @@
+package dynamo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/npcqueue"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+const (
+	// every NPC move lives in the same partition so that we can query for the due ones
+	npcMovesPartition = `npcMoves`
+
+	npcMoveJobAttributeName         = `job`
+	npcMoveNumActionsAttributeName  = `numActions`
+	npcMoveNextAttemptAttributeName = `next`
+)
+
+var _ persistence.NPCMoveService = (*npcMoveService)(nil)
+
+type npcMoveService struct {
+	svc *dynamodb.Client
+}
+
+func newNPCMoveService(
+	svc *dynamodb.Client,
+) persistence.NPCMoveService {
+	return &npcMoveService{
+		svc: svc,
+	}
+}
+
+func (ns *npcMoveService) Add(ctx context.Context, j npcqueue.Job) (bool, error) {
+	// an NPC has one move for each game, so we only replace the move that is
+	// there when the new one is for a later state of the game
+	err := ns.put(ctx, j,
+		`attribute_not_exists(`+partitionKey+`) or `+npcMoveNumActionsAttributeName+` < :n`,
+	)
+	if err != nil {
+		if isConditionalError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (ns *npcMoveService) GetDue(ctx context.Context, now time.Time) ([]npcqueue.Job, error) {
+	pkName := `:pk`
+	skName := `:sk`
+	nowName := `:now`
+	hp := hasPrefix{
+		pkName: pkName,
+		skName: skName,
+	}
+
+	createQuery := func() *dynamodb.QueryInput {
+		qi := newQueryInputFactory(getQueryInputParams(
+			npcMovesPartition, pkName,
+			ns.getSpecForAllMoves(), skName,
+			hp.conditionExpression(),
+		))()
+		qi.FilterExpression = aws.String(npcMoveNextAttemptAttributeName + ` <= ` + nowName)
+		qi.ExpressionAttributeValues[nowName] = &types.AttributeValueMemberN{
+			Value: strconv.FormatInt(now.UnixNano(), 10),
+		}
+		return qi
+	}
+
+	items, err := fullQuery(ctx, ns.svc, createQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]npcqueue.Job, 0, len(items))
+	for _, item := range items {
+		jb, ok := item[npcMoveJobAttributeName].(*types.AttributeValueMemberB)
+		if !ok {
+			return nil, fmt.Errorf(`wrong %s type`, npcMoveJobAttributeName)
+		}
+
+		var j npcqueue.Job
+		err = json.Unmarshal(jb.Value, &j)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, j)
+	}
+	npcqueue.SortJobs(jobs)
+
+	return jobs, nil
+}
+
+func (ns *npcMoveService) Update(ctx context.Context, j npcqueue.Job) error {
+	err := ns.put(ctx, j, npcMoveNumActionsAttributeName+` = :n`)
+	if isConditionalError(err) {
+		// the move is gone, or it has been replaced by a later one
+		return nil
+	}
+	return err
+}
+
+func (ns *npcMoveService) Remove(ctx context.Context, k npcqueue.Key) error {
+	_, err := ns.svc.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName:           aws.String(dbName),
+		Key:                 ns.getKey(k.GameID, k.PlayerID),
+		ConditionExpression: aws.String(npcMoveNumActionsAttributeName + ` = :n`),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			`:n`: &types.AttributeValueMemberN{
+				Value: strconv.Itoa(k.NumActions),
+			},
+		},
+	})
+	if isConditionalError(err) {
+		// the move is gone, or it has been replaced by a later one
+		return nil
+	}
+	return err
+}
+
+// put writes the job when the condition holds. The condition can use :n, which
+// is the number of actions of the job.
+func (ns *npcMoveService) put(ctx context.Context, j npcqueue.Job, cond string) error {
+	obj, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+
+	item := ns.getKey(j.GameID, j.PlayerID)
+	item[npcMoveNumActionsAttributeName] = &types.AttributeValueMemberN{
+		Value: strconv.Itoa(j.NumActions),
+	}
+	item[npcMoveNextAttemptAttributeName] = &types.AttributeValueMemberN{
+		Value: strconv.FormatInt(j.NextAttempt.UnixNano(), 10),
+	}
+	item[npcMoveJobAttributeName] = &types.AttributeValueMemberB{
+		Value: obj,
+	}
+
+	_, err = ns.svc.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(dbName),
+		Item:                item,
+		ConditionExpression: aws.String(cond),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			`:n`: &types.AttributeValueMemberN{
+				Value: strconv.Itoa(j.NumActions),
+			},
+		},
+	})
+	return err
+}
+
+func (ns *npcMoveService) getKey(gID model.GameID, pID model.PlayerID) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		partitionKey: &types.AttributeValueMemberS{
+			Value: npcMovesPartition,
+		},
+		sortKey: &types.AttributeValueMemberS{
+			Value: ns.getSpecForAllMoves() + strconv.Itoa(int(gID)) + `@` + string(pID),
+		},
+	}
+}
+
+func (ns *npcMoveService) getSpecForAllMoves() string {
+	return getSortKeyPrefix(ns) + `@`
+}
@@ -22,17 +22,13 @@ const (
 var _ persistence.PlayerService = (*playerService)(nil)
 
 type playerService struct {
-	ctx context.Context
-
 	svc *dynamodb.Client
 }
 
 func newPlayerService(
-	ctx context.Context,
 	svc *dynamodb.Client,
 ) persistence.PlayerService {
 	return &playerService{
-		ctx: ctx,
 		svc: svc,
 	}
 }
@@ -41,7 +37,7 @@ func (ps *playerService) getGameSortKey() string {
 	return getSortKeyPrefix(ps) + `Game`
 }
 
-func (ps *playerService) Get(id model.PlayerID) (model.Player, error) {
+func (ps *playerService) Get(ctx context.Context, id model.PlayerID) (model.Player, error) {
 	pkName := `:pID`
 	pk := string(id)
 	skName := `:sk`
@@ -54,7 +50,7 @@ func (ps *playerService) Get(id model.PlayerID) (model.Player, error) {
 	createQuery := newQueryInputFactory(getQueryInputParams(
 		pk, pkName, sk, skName, hp.conditionExpression(),
 	))
-	items, err := fullQuery(ps.ctx, ps.svc, createQuery)
+	items, err := fullQuery(ctx, ps.svc, createQuery)
 	if err != nil {
 		return model.Player{}, err
 	}
@@ -165,7 +161,7 @@ func (ps *playerService) getPlayerName(
 	return nameAVS.Value, true
 }
 
-func (ps *playerService) Create(p model.Player) error {
+func (ps *playerService) Create(ctx context.Context, p model.Player) error {
 	data := map[string]types.AttributeValue{
 		partitionKey: &types.AttributeValueMemberS{
 			Value: string(p.ID),
@@ -182,7 +178,7 @@ func (ps *playerService) Create(p model.Player) error {
 	// <HASH:RANGE> tuple doesn't already exist.
 	// See: https://docs.aws.amazon.com/amazondynamodb/latest/developerguide/Expressions.ConditionExpressions.html
 	// and https://docs.aws.amazon.com/amazondynamodb/latest/developerguide/Expressions.OperatorsAndFunctions.html
-	_, err := ps.svc.PutItem(ps.ctx, &dynamodb.PutItemInput{
+	_, err := ps.svc.PutItem(ctx, &dynamodb.PutItemInput{
 		TableName:           aws.String(dbName),
 		Item:                data,
 		ConditionExpression: notExists{}.conditionExpression(),
@@ -197,9 +193,9 @@ func (ps *playerService) Create(p model.Player) error {
 	return nil
 }
 
-func (ps *playerService) BeginGame(gID model.GameID, players []model.Player) error {
+func (ps *playerService) BeginGame(ctx context.Context, gID model.GameID, players []model.Player) error {
 	for _, p := range players {
-		err := ps.setPlayerGameColor(p.ID, gID, model.UnsetColor)
+		err := ps.setPlayerGameColor(ctx, p.ID, gID, model.UnsetColor)
 		if err != nil {
 			return err
 		}
@@ -209,12 +205,13 @@ func (ps *playerService) BeginGame(gID model.GameID, players []model.Player) err
 }
 
 func (ps *playerService) UpdateGameColor(
+	ctx context.Context,
 	pID model.PlayerID,
 	gID model.GameID,
 	color model.PlayerColor,
 ) error {
 
-	p, err := ps.Get(pID)
+	p, err := ps.Get(ctx, pID)
 	if err != nil {
 		return err
 	}
@@ -228,15 +225,16 @@ func (ps *playerService) UpdateGameColor(
 		return nil
 	}
 
-	return ps.setPlayerGameColor(pID, gID, color)
+	return ps.setPlayerGameColor(ctx, pID, gID, color)
 }
 
 func (ps *playerService) setPlayerGameColor(
+	ctx context.Context,
 	pID model.PlayerID,
 	gID model.GameID,
 	color model.PlayerColor,
 ) error {
-	_, err := ps.svc.PutItem(ps.ctx, &dynamodb.PutItemInput{
+	_, err := ps.svc.PutItem(ctx, &dynamodb.PutItemInput{
 		TableName: aws.String(dbName),
 		Item: map[string]types.AttributeValue{
 			partitionKey: &types.AttributeValueMemberS{
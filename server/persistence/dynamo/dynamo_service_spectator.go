@@ -0,0 +1,141 @@
+package dynamo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+const (
+	spectatorShoulderAttributeName = `shoulder`
+)
+
+var _ persistence.SpectatorService = (*spectatorService)(nil)
+
+// spectatorService keeps the spectators in the same partition as the game they are watching
+type spectatorService struct {
+	svc *dynamodb.Client
+}
+
+func newSpectatorService(
+	svc *dynamodb.Client,
+) persistence.SpectatorService {
+	return &spectatorService{
+		svc: svc,
+	}
+}
+
+func (ss *spectatorService) Get(ctx context.Context, gID model.GameID) ([]model.Spectator, error) {
+	pkName := `:gID`
+	skName := `:sk`
+	hp := hasPrefix{
+		pkName: pkName,
+		skName: skName,
+	}
+
+	createQuery := newQueryInputFactory(getQueryInputParams(
+		strconv.Itoa(int(gID)), pkName,
+		ss.getSpecForAllSpectators(), skName,
+		hp.conditionExpression(),
+	))
+
+	items, err := fullQuery(ctx, ss.svc, createQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	specs := make([]model.Spectator, 0, len(items))
+	var s model.Spectator
+	for _, item := range items {
+		s, err = ss.getSpectatorFromItem(gID, item)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, s)
+	}
+	sort.Slice(specs, func(i, j int) bool {
+		return specs[i].PlayerID < specs[j].PlayerID
+	})
+
+	return specs, nil
+}
+
+func (ss *spectatorService) getSpectatorFromItem(
+	gID model.GameID,
+	item map[string]types.AttributeValue,
+) (model.Spectator, error) {
+	sk, ok := item[sortKey].(*types.AttributeValueMemberS)
+	if !ok {
+		return model.Spectator{}, fmt.Errorf(`wrong %s type`, sortKey)
+	}
+
+	s := model.Spectator{
+		GameID:   gID,
+		PlayerID: model.PlayerID(strings.TrimPrefix(sk.Value, ss.getSpecForAllSpectators())),
+	}
+	if sh, ok := item[spectatorShoulderAttributeName].(*types.AttributeValueMemberS); ok {
+		s.Shoulder = model.PlayerID(sh.Value)
+	}
+	return s, nil
+}
+
+func (ss *spectatorService) Save(ctx context.Context, s model.Spectator) error {
+	item := map[string]types.AttributeValue{
+		partitionKey: &types.AttributeValueMemberS{
+			Value: strconv.Itoa(int(s.GameID)),
+		},
+		sortKey: &types.AttributeValueMemberS{
+			Value: ss.getSpecForSpectator(s.PlayerID),
+		},
+	}
+	if s.Shoulder != model.InvalidPlayerID {
+		item[spectatorShoulderAttributeName] = &types.AttributeValueMemberS{
+			Value: string(s.Shoulder),
+		}
+	}
+
+	_, err := ss.svc.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(dbName),
+		Item:      item,
+	})
+	return err
+}
+
+func (ss *spectatorService) Remove(ctx context.Context, gID model.GameID, pID model.PlayerID) error {
+	_, err := ss.svc.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(dbName),
+		Key: map[string]types.AttributeValue{
+			partitionKey: &types.AttributeValueMemberS{
+				Value: strconv.Itoa(int(gID)),
+			},
+			sortKey: &types.AttributeValueMemberS{
+				Value: ss.getSpecForSpectator(pID),
+			},
+		},
+		ConditionExpression: aws.String(`attribute_exists(` + partitionKey + `)`),
+	})
+	if err != nil {
+		if isConditionalError(err) {
+			return persistence.ErrSpectatorNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (ss *spectatorService) getSpecForAllSpectators() string {
+	return getSortKeyPrefix(ss) + `@`
+}
+
+func (ss *spectatorService) getSpecForSpectator(pID model.PlayerID) string {
+	return ss.getSpecForAllSpectators() + string(pID)
+}
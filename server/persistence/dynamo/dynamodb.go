@@ -3,12 +3,13 @@ package dynamo
 import (
 	"context"
 	"fmt"
-	"log"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"go.uber.org/zap"
 
+	"github.com/joshprzybyszewski/cribbage/server/logging"
 	"github.com/joshprzybyszewski/cribbage/server/persistence"
 )
 
@@ -16,11 +17,16 @@ var _ persistence.DBFactory = dynamoFactory{}
 
 type dynamoFactory struct {
 	endpoint string
+
+	replayer persistence.Replayer
 }
 
-func NewFactory(endpoint string) (persistence.DBFactory, error) {
+// NewFactory returns a factory for the DBs at the endpoint. The replayer rebuilds
+// the snapshots of games which have been compacted.
+func NewFactory(endpoint string, r persistence.Replayer) (persistence.DBFactory, error) {
 	return dynamoFactory{
 		endpoint: endpoint,
+		replayer: r,
 	}, nil
 }
 
@@ -34,19 +40,27 @@ func (df dynamoFactory) New(ctx context.Context) (persistence.DB, error) {
 		return nil, fmt.Errorf("DescribeTable ERROR: %v", err)
 	}
 
-	gs := newGameService(ctx, svc)
-	ps := newPlayerService(ctx, svc)
-	is := newInteractionService(ctx, svc)
+	gs := newGameService(svc)
+	ps := newPlayerService(svc)
+	is := newInteractionService(svc)
+	ls := newLobbyService(svc)
+	ss := newSpectatorService(svc)
+	cs := newChatService(svc)
+	ns := newNPCMoveService(svc)
 
 	sw := persistence.NewServicesWrapper(
 		gs,
 		ps,
 		is,
+		ls,
+		ss,
+		cs,
+		ns,
+		df.replayer,
 	)
 
 	dw := dynamoWrapper{
 		ServicesWrapper: sw,
-		ctx:             ctx,
 	}
 
 	return &dw, nil
@@ -63,7 +77,7 @@ func getDynamoService(
 		ctx,
 	)
 	if err != nil {
-		log.Fatalf("unable to load SDK config, %v", err)
+		logging.L().Fatal(`unable to load SDK config`, zap.Error(err))
 	}
 
 	opts := make([]func(o *dynamodb.Options), 0, 2)
@@ -89,6 +103,14 @@ func getDynamoService(
 	)
 }
 
+func (df dynamoFactory) Ping(ctx context.Context) error {
+	// dynamo is always up, so what we need to know is that we can reach our table
+	_, err := getDynamoService(ctx, df.endpoint).DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(dbName),
+	})
+	return err
+}
+
 func (df dynamoFactory) Close() error {
 	return nil
 }
@@ -97,8 +119,6 @@ var _ persistence.DB = (*dynamoWrapper)(nil)
 
 type dynamoWrapper struct {
 	persistence.ServicesWrapper
-
-	ctx context.Context
 }
 
 func (dw *dynamoWrapper) Close() error {
@@ -106,7 +126,7 @@ func (dw *dynamoWrapper) Close() error {
 	return nil
 }
 
-func (dw *dynamoWrapper) Start() error {
+func (dw *dynamoWrapper) Start(context.Context) error {
 	// TODO figure out transactionality in dynamodb
 	return nil
 }
@@ -9,13 +9,14 @@ import (
 
 	"github.com/joshprzybyszewski/cribbage/model"
 	"github.com/joshprzybyszewski/cribbage/server/persistence/dynamo"
+	"github.com/joshprzybyszewski/cribbage/server/play"
 )
 
 func main() {
 	os.Setenv(`AWS_ACCESS_KEY_ID`, `DUMMYIDEXAMPLE`)
 	os.Setenv(`AWS_SECRET_ACCESS_KEY`, `DUMMYEXAMPLEKEY`)
 
-	dbf, err := dynamo.NewFactory(`http://localhost:18079`)
+	dbf, err := dynamo.NewFactory(`http://localhost:18079`, play.Replay)
 	if err != nil {
 		log.Fatalf("dynamo.NewFactory err: %+v", err)
 	}
@@ -35,13 +36,13 @@ func main() {
 		Games: map[model.GameID]model.PlayerColor{},
 	}
 	fmt.Printf("calling dw.CreatePlayer(%+v)\n", p)
-	err = dw.CreatePlayer(p)
+	err = dw.CreatePlayer(ctx, p)
 	if err != nil {
 		log.Printf("dw.CreatePlayer err: %+v", err)
 	}
 
 	fmt.Printf("calling dw.GetPlayer(%+v)\n", pID)
-	p2, err := dw.GetPlayer(pID)
+	p2, err := dw.GetPlayer(ctx, pID)
 	if err != nil {
 		log.Fatalf("dw.GetPlayer err: %+v", err)
 	}
@@ -52,13 +53,13 @@ func main() {
 	}
 
 	fmt.Printf("calling dw.CreateGame(%+v)\n", g)
-	err = dw.CreateGame(g)
+	err = dw.CreateGame(ctx, g)
 	if err != nil {
 		log.Fatalf("dw.CreateGame err: %+v", err)
 	}
 
 	fmt.Printf("calling dw.CreateGame(%+v)\n", g)
-	gg, err := dw.GetGame(g.ID)
+	gg, err := dw.GetGame(ctx, g.ID)
 	if err != nil {
 		log.Fatalf("dw.GetGame err: %+v", err)
 	}
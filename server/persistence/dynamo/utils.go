@@ -23,8 +23,16 @@ func getSortKeyPrefix(service interface{}) string {
 		return `game`
 	case *interactionService:
 		return `interaction`
+	case *lobbyService:
+		return `lobby`
 	case *playerService:
 		return `player`
+	case *spectatorService:
+		return `spectator`
+	case *chatService:
+		return `chat`
+	case *npcMoveService:
+		return `npcMove`
 	}
 
 	return `garbage`
@@ -25,6 +25,18 @@ func TestGetSortKeyPrefix(t *testing.T) {
 	}, {
 		service:   (*playerService)(nil),
 		expPrefix: `player`,
+	}, {
+		service:   (*lobbyService)(nil),
+		expPrefix: `lobby`,
+	}, {
+		service:   (*spectatorService)(nil),
+		expPrefix: `spectator`,
+	}, {
+		service:   (*chatService)(nil),
+		expPrefix: `chat`,
+	}, {
+		service:   (*npcMoveService)(nil),
+		expPrefix: `npcMove`,
 	}, {
 		service:   (*model.Game)(nil),
 		expPrefix: `garbage`,
@@ -17,8 +17,17 @@ var (
 	ErrGameActionDecode      error = errors.New(`game actions get decode`)
 	ErrGameActionWrongGame   error = errors.New(`game action for wrong game`)
 	ErrGameActionWrongPlayer error = errors.New(`game action found for wrong player`)
+	ErrGameSnapshotCompacted error = errors.New(`game snapshot has been compacted`)
+	ErrGameNotOver           error = errors.New(`game is not over`)
 
 	ErrInteractionNotFound      error = errors.New(`interaction not found`)
 	ErrInteractionAlreadyExists error = errors.New(`interaction already exists`)
 	ErrInteractionUnexpected    error = errors.New(`unexpected interaction`)
+
+	ErrInvalidLobbyID     error = errors.New(`lobby id invalid`)
+	ErrLobbyNotFound      error = errors.New(`lobby not found`)
+	ErrLobbyAlreadyExists error = errors.New(`lobby already exists`)
+	ErrLobbyConflict      error = errors.New(`lobby was changed by someone else`)
+
+	ErrSpectatorNotFound error = errors.New(`spectator not found`)
 )
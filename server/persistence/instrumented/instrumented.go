@@ -0,0 +1,329 @@
+// Package instrumented wraps a persistence.DBFactory so that every operation on its
+// DBs is timed in the server's metrics, and traced as a child of the span in the
+// context that the operation was called with
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/semconv"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/interaction"
+	"github.com/joshprzybyszewski/cribbage/server/metrics"
+	"github.com/joshprzybyszewski/cribbage/server/npcqueue"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+	"github.com/joshprzybyszewski/cribbage/server/tracing"
+)
+
+const (
+	transactions = `transactions`
+	players      = `players`
+	games        = `games`
+	interactions = `interactions`
+	lobbies      = `lobbies`
+	spectators   = `spectators`
+	chats        = `chats`
+	npcMoves     = `npcMoves`
+)
+
+var _ persistence.DBFactory = (*factory)(nil)
+
+type factory struct {
+	dbf     persistence.DBFactory
+	backend string
+}
+
+// NewFactory returns a DBFactory whose DBs record how long the given backend
+// (such as "mysql") takes for each of their operations, and trace them
+func NewFactory(dbf persistence.DBFactory, backend string) persistence.DBFactory {
+	return &factory{
+		dbf:     dbf,
+		backend: backend,
+	}
+}
+
+func (f *factory) New(ctx context.Context) (persistence.DB, error) {
+	db, err := f.dbf.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &instrumentedDB{
+		db:      db,
+		backend: f.backend,
+		txCtx:   ctx,
+	}, nil
+}
+
+func (f *factory) Ping(ctx context.Context) error {
+	start := time.Now()
+	_, span := tracing.Start(ctx, `persistence.factory.Ping`,
+		semconv.DBSystemKey.String(f.backend),
+		semconv.DBOperationKey.String(`Ping`),
+	)
+
+	err := f.dbf.Ping(ctx)
+	metrics.ObserveDBOperation(f.backend, `factory`, `Ping`, time.Since(start), err)
+	tracing.End(span, err)
+	return err
+}
+
+func (f *factory) Close() error {
+	return f.dbf.Close()
+}
+
+var _ persistence.DB = (*instrumentedDB)(nil)
+
+type instrumentedDB struct {
+	db      persistence.DB
+	backend string
+
+	// txCtx is the context that the transaction was started with, so that
+	// its commit or rollback is traced under the same span
+	txCtx context.Context
+}
+
+// start times and traces the operation. Call the returned func with its error once it's done.
+func (idb *instrumentedDB) start(ctx context.Context, service, operation string) func(error) {
+	start := time.Now()
+	_, span := tracing.Start(ctx, `persistence.`+service+`.`+operation,
+		semconv.DBSystemKey.String(idb.backend),
+		semconv.DBOperationKey.String(operation),
+	)
+
+	return func(err error) {
+		metrics.ObserveDBOperation(idb.backend, service, operation, time.Since(start), err)
+		tracing.End(span, err)
+	}
+}
+
+func (idb *instrumentedDB) Close() error {
+	return idb.db.Close()
+}
+
+func (idb *instrumentedDB) Start(ctx context.Context) error {
+	done := idb.start(ctx, transactions, `Start`)
+	err := idb.db.Start(ctx)
+	done(err)
+	if err == nil {
+		idb.txCtx = ctx
+	}
+	return err
+}
+
+func (idb *instrumentedDB) Commit() error {
+	done := idb.start(idb.txCtx, transactions, `Commit`)
+	err := idb.db.Commit()
+	done(err)
+	return err
+}
+
+func (idb *instrumentedDB) Rollback() error {
+	done := idb.start(idb.txCtx, transactions, `Rollback`)
+	err := idb.db.Rollback()
+	done(err)
+	return err
+}
+
+func (idb *instrumentedDB) CreatePlayer(ctx context.Context, p model.Player) error {
+	done := idb.start(ctx, players, `CreatePlayer`)
+	err := idb.db.CreatePlayer(ctx, p)
+	done(err)
+	return err
+}
+
+func (idb *instrumentedDB) GetPlayer(ctx context.Context, id model.PlayerID) (model.Player, error) {
+	done := idb.start(ctx, players, `GetPlayer`)
+	p, err := idb.db.GetPlayer(ctx, id)
+	done(err)
+	return p, err
+}
+
+func (idb *instrumentedDB) AddPlayerColorToGame(
+	ctx context.Context,
+	id model.PlayerID,
+	color model.PlayerColor,
+	gID model.GameID,
+) error {
+	done := idb.start(ctx, players, `AddPlayerColorToGame`)
+	err := idb.db.AddPlayerColorToGame(ctx, id, color, gID)
+	done(err)
+	return err
+}
+
+func (idb *instrumentedDB) CreateGame(ctx context.Context, g model.Game) error {
+	done := idb.start(ctx, games, `CreateGame`)
+	err := idb.db.CreateGame(ctx, g)
+	done(err)
+	return err
+}
+
+func (idb *instrumentedDB) GetGame(ctx context.Context, id model.GameID) (model.Game, error) {
+	done := idb.start(ctx, games, `GetGame`)
+	g, err := idb.db.GetGame(ctx, id)
+	done(err)
+	return g, err
+}
+
+func (idb *instrumentedDB) GetGameAction(ctx context.Context, id model.GameID, numActions uint) (model.Game, error) {
+	done := idb.start(ctx, games, `GetGameAction`)
+	g, err := idb.db.GetGameAction(ctx, id, numActions)
+	done(err)
+	return g, err
+}
+
+func (idb *instrumentedDB) SaveGame(ctx context.Context, g model.Game) error {
+	done := idb.start(ctx, games, `SaveGame`)
+	err := idb.db.SaveGame(ctx, g)
+	done(err)
+	return err
+}
+
+func (idb *instrumentedDB) CompactGame(ctx context.Context, id model.GameID, rp persistence.RetentionPolicy) error {
+	done := idb.start(ctx, games, `CompactGame`)
+	err := idb.db.CompactGame(ctx, id, rp)
+	done(err)
+	return err
+}
+
+func (idb *instrumentedDB) GetGamesToCompact(ctx context.Context) ([]model.GameID, error) {
+	done := idb.start(ctx, games, `GetGamesToCompact`)
+	gIDs, err := idb.db.GetGamesToCompact(ctx)
+	done(err)
+	return gIDs, err
+}
+
+func (idb *instrumentedDB) CountActiveGames(ctx context.Context) (int, error) {
+	done := idb.start(ctx, games, `CountActiveGames`)
+	n, err := idb.db.CountActiveGames(ctx)
+	done(err)
+	return n, err
+}
+
+func (idb *instrumentedDB) SetMoveDeadline(ctx context.Context, id model.GameID, deadline time.Time) error {
+	done := idb.start(ctx, games, `SetMoveDeadline`)
+	err := idb.db.SetMoveDeadline(ctx, id, deadline)
+	done(err)
+	return err
+}
+
+func (idb *instrumentedDB) GetMoveDeadline(ctx context.Context, id model.GameID) (time.Time, error) {
+	done := idb.start(ctx, games, `GetMoveDeadline`)
+	deadline, err := idb.db.GetMoveDeadline(ctx, id)
+	done(err)
+	return deadline, err
+}
+
+func (idb *instrumentedDB) GetOverdueGames(ctx context.Context, now time.Time) ([]model.GameID, error) {
+	done := idb.start(ctx, games, `GetOverdueGames`)
+	gIDs, err := idb.db.GetOverdueGames(ctx, now)
+	done(err)
+	return gIDs, err
+}
+
+func (idb *instrumentedDB) GetInteraction(ctx context.Context, id model.PlayerID) (interaction.PlayerMeans, error) {
+	done := idb.start(ctx, interactions, `GetInteraction`)
+	pm, err := idb.db.GetInteraction(ctx, id)
+	done(err)
+	return pm, err
+}
+
+func (idb *instrumentedDB) SaveInteraction(ctx context.Context, pm interaction.PlayerMeans) error {
+	done := idb.start(ctx, interactions, `SaveInteraction`)
+	err := idb.db.SaveInteraction(ctx, pm)
+	done(err)
+	return err
+}
+
+func (idb *instrumentedDB) CreateLobby(ctx context.Context, l model.Lobby) error {
+	done := idb.start(ctx, lobbies, `CreateLobby`)
+	err := idb.db.CreateLobby(ctx, l)
+	done(err)
+	return err
+}
+
+func (idb *instrumentedDB) GetLobby(ctx context.Context, id model.LobbyID) (model.Lobby, error) {
+	done := idb.start(ctx, lobbies, `GetLobby`)
+	l, err := idb.db.GetLobby(ctx, id)
+	done(err)
+	return l, err
+}
+
+func (idb *instrumentedDB) GetOpenLobbies(ctx context.Context) ([]model.Lobby, error) {
+	done := idb.start(ctx, lobbies, `GetOpenLobbies`)
+	ls, err := idb.db.GetOpenLobbies(ctx)
+	done(err)
+	return ls, err
+}
+
+func (idb *instrumentedDB) SaveLobby(ctx context.Context, l model.Lobby) error {
+	done := idb.start(ctx, lobbies, `SaveLobby`)
+	err := idb.db.SaveLobby(ctx, l)
+	done(err)
+	return err
+}
+
+func (idb *instrumentedDB) GetSpectators(ctx context.Context, gID model.GameID) ([]model.Spectator, error) {
+	done := idb.start(ctx, spectators, `GetSpectators`)
+	ss, err := idb.db.GetSpectators(ctx, gID)
+	done(err)
+	return ss, err
+}
+
+func (idb *instrumentedDB) SaveSpectator(ctx context.Context, s model.Spectator) error {
+	done := idb.start(ctx, spectators, `SaveSpectator`)
+	err := idb.db.SaveSpectator(ctx, s)
+	done(err)
+	return err
+}
+
+func (idb *instrumentedDB) RemoveSpectator(ctx context.Context, gID model.GameID, pID model.PlayerID) error {
+	done := idb.start(ctx, spectators, `RemoveSpectator`)
+	err := idb.db.RemoveSpectator(ctx, gID, pID)
+	done(err)
+	return err
+}
+
+func (idb *instrumentedDB) GetChatMessages(ctx context.Context, gID model.GameID) ([]model.ChatMessage, error) {
+	done := idb.start(ctx, chats, `GetChatMessages`)
+	cms, err := idb.db.GetChatMessages(ctx, gID)
+	done(err)
+	return cms, err
+}
+
+func (idb *instrumentedDB) AddChatMessage(ctx context.Context, cm model.ChatMessage) error {
+	done := idb.start(ctx, chats, `AddChatMessage`)
+	err := idb.db.AddChatMessage(ctx, cm)
+	done(err)
+	return err
+}
+
+func (idb *instrumentedDB) AddNPCMove(ctx context.Context, j npcqueue.Job) (bool, error) {
+	done := idb.start(ctx, npcMoves, `AddNPCMove`)
+	added, err := idb.db.AddNPCMove(ctx, j)
+	done(err)
+	return added, err
+}
+
+func (idb *instrumentedDB) GetDueNPCMoves(ctx context.Context, now time.Time) ([]npcqueue.Job, error) {
+	done := idb.start(ctx, npcMoves, `GetDueNPCMoves`)
+	jobs, err := idb.db.GetDueNPCMoves(ctx, now)
+	done(err)
+	return jobs, err
+}
+
+func (idb *instrumentedDB) UpdateNPCMove(ctx context.Context, j npcqueue.Job) error {
+	done := idb.start(ctx, npcMoves, `UpdateNPCMove`)
+	err := idb.db.UpdateNPCMove(ctx, j)
+	done(err)
+	return err
+}
+
+func (idb *instrumentedDB) RemoveNPCMove(ctx context.Context, k npcqueue.Key) error {
+	done := idb.start(ctx, npcMoves, `RemoveNPCMove`)
+	err := idb.db.RemoveNPCMove(ctx, k)
+	done(err)
+	return err
+}
@@ -0,0 +1,65 @@
+package instrumented
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/metrics"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+	"github.com/joshprzybyszewski/cribbage/server/persistence/memory"
+	"github.com/joshprzybyszewski/cribbage/server/play"
+)
+
+func scrapeMetrics(t *testing.T) string {
+	req, err := http.NewRequest(`GET`, `/metrics`, nil)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(w, req)
+
+	bs, err := ioutil.ReadAll(w.Body)
+	require.NoError(t, err)
+	return string(bs)
+}
+
+func TestInstrumentedDBRecordsOperations(t *testing.T) {
+	memory.Clear()
+	t.Cleanup(memory.Clear)
+
+	dbf := NewFactory(memory.NewFactory(play.Replay), `test`)
+	require.NoError(t, dbf.Ping(context.Background()))
+	db, err := dbf.New(context.Background())
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.NoError(t, db.Start(context.Background()))
+	alice := model.Player{ID: `alice`, Name: `Alice`}
+	require.NoError(t, db.CreatePlayer(context.Background(), alice))
+	p, err := db.GetPlayer(context.Background(), alice.ID)
+	require.NoError(t, err)
+	assert.Equal(t, alice, p)
+
+	_, err = db.GetGame(context.Background(), model.GameID(5))
+	assert.Equal(t, persistence.ErrGameNotFound, err, `errors are passed through untouched`)
+	require.NoError(t, db.Commit())
+
+	m := scrapeMetrics(t)
+	for _, series := range []string{
+		`cribbage_db_operation_duration_seconds_count{backend="test",operation="Ping",service="factory"} 1`,
+		`cribbage_db_operation_duration_seconds_count{backend="test",operation="Start",service="transactions"} 1`,
+		`cribbage_db_operation_duration_seconds_count{backend="test",operation="CreatePlayer",service="players"} 1`,
+		`cribbage_db_operation_duration_seconds_count{backend="test",operation="GetPlayer",service="players"} 1`,
+		`cribbage_db_operation_duration_seconds_count{backend="test",operation="GetGame",service="games"} 1`,
+		`cribbage_db_operation_duration_seconds_count{backend="test",operation="Commit",service="transactions"} 1`,
+		`cribbage_db_operation_errors_total{backend="test",operation="GetGame",service="games"} 1`,
+	} {
+		assert.Contains(t, m, series)
+	}
+	assert.NotContains(t, m, `cribbage_db_operation_errors_total{backend="test",operation="GetPlayer"`)
+}
@@ -3,15 +3,20 @@ package persistence
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/joshprzybyszewski/cribbage/model"
 	"github.com/joshprzybyszewski/cribbage/server/interaction"
+	"github.com/joshprzybyszewski/cribbage/server/npcqueue"
 )
 
 type DBFactory interface {
 	// New returns a new db
 	New(context.Context) (DB, error)
 
+	// Ping checks that the database can be reached
+	Ping(context.Context) error
+
 	// Close should be called to close any connections needed on the database
 	Close() error
 }
@@ -21,7 +26,7 @@ type DB interface {
 	Close() error
 
 	// Start will start a transaction on the database
-	Start() error
+	Start(ctx context.Context) error
 	// Commit will commit a transaction, if one exists
 	Commit() error
 	// Rollback will rollback the transaction of changes on the database
@@ -31,59 +36,107 @@ type DB interface {
 }
 
 type ServicesWrapper interface {
-	CreatePlayer(p model.Player) error
-	GetPlayer(id model.PlayerID) (model.Player, error)
-	AddPlayerColorToGame(id model.PlayerID, color model.PlayerColor, gID model.GameID) error
-
-	CreateGame(g model.Game) error
-	GetGame(id model.GameID) (model.Game, error)
-	GetGameAction(id model.GameID, numActions uint) (model.Game, error)
-	SaveGame(g model.Game) error
-
-	GetInteraction(id model.PlayerID) (interaction.PlayerMeans, error)
-	SaveInteraction(pm interaction.PlayerMeans) error
+	CreatePlayer(ctx context.Context, p model.Player) error
+	GetPlayer(ctx context.Context, id model.PlayerID) (model.Player, error)
+	AddPlayerColorToGame(ctx context.Context, id model.PlayerID, color model.PlayerColor, gID model.GameID) error
+
+	CreateGame(ctx context.Context, g model.Game) error
+	GetGame(ctx context.Context, id model.GameID) (model.Game, error)
+	GetGameAction(ctx context.Context, id model.GameID, numActions uint) (model.Game, error)
+	SaveGame(ctx context.Context, g model.Game) error
+	CompactGame(ctx context.Context, id model.GameID, rp RetentionPolicy) error
+	GetGamesToCompact(ctx context.Context) ([]model.GameID, error)
+	CountActiveGames(ctx context.Context) (int, error)
+	SetMoveDeadline(ctx context.Context, id model.GameID, deadline time.Time) error
+	GetMoveDeadline(ctx context.Context, id model.GameID) (time.Time, error)
+	GetOverdueGames(ctx context.Context, now time.Time) ([]model.GameID, error)
+
+	GetInteraction(ctx context.Context, id model.PlayerID) (interaction.PlayerMeans, error)
+	SaveInteraction(ctx context.Context, pm interaction.PlayerMeans) error
+
+	CreateLobby(ctx context.Context, l model.Lobby) error
+	GetLobby(ctx context.Context, id model.LobbyID) (model.Lobby, error)
+	GetOpenLobbies(ctx context.Context) ([]model.Lobby, error)
+	SaveLobby(ctx context.Context, l model.Lobby) error
+
+	GetSpectators(ctx context.Context, gID model.GameID) ([]model.Spectator, error)
+	SaveSpectator(ctx context.Context, s model.Spectator) error
+	RemoveSpectator(ctx context.Context, gID model.GameID, pID model.PlayerID) error
+
+	GetChatMessages(ctx context.Context, gID model.GameID) ([]model.ChatMessage, error)
+	AddChatMessage(ctx context.Context, cm model.ChatMessage) error
+
+	AddNPCMove(ctx context.Context, j npcqueue.Job) (bool, error)
+	GetDueNPCMoves(ctx context.Context, now time.Time) ([]npcqueue.Job, error)
+	UpdateNPCMove(ctx context.Context, j npcqueue.Job) error
+	RemoveNPCMove(ctx context.Context, k npcqueue.Key) error
 }
 
 type services struct {
 	games        GameService
 	players      PlayerService
 	interactions InteractionService
+	lobbies      LobbyService
+	spectators   SpectatorService
+	chats        ChatService
+	npcMoves     NPCMoveService
+
+	replayer Replayer
 }
 
-func NewServicesWrapper(gs GameService, ps PlayerService, is InteractionService) ServicesWrapper {
+func NewServicesWrapper(
+	gs GameService,
+	ps PlayerService,
+	is InteractionService,
+	ls LobbyService,
+	ss SpectatorService,
+	cs ChatService,
+	ns NPCMoveService,
+	r Replayer,
+) ServicesWrapper {
 	return &services{
 		games:        gs,
 		players:      ps,
 		interactions: is,
+		lobbies:      ls,
+		spectators:   ss,
+		chats:        cs,
+		npcMoves:     ns,
+		replayer:     r,
 	}
 }
 
-func (d *services) CreatePlayer(p model.Player) error {
+func (d *services) CreatePlayer(ctx context.Context, p model.Player) error {
 	if !model.IsValidPlayerID(p.ID) {
 		return ErrInvalidPlayerID
 	}
-	return d.players.Create(p)
+	return d.players.Create(ctx, p)
 }
 
-func (d *services) GetPlayer(id model.PlayerID) (model.Player, error) {
-	return d.players.Get(id)
+func (d *services) GetPlayer(ctx context.Context, id model.PlayerID) (model.Player, error) {
+	return d.players.Get(ctx, id)
 }
 
-func (d *services) AddPlayerColorToGame(pID model.PlayerID, color model.PlayerColor, gID model.GameID) error {
-	err := d.games.UpdatePlayerColor(gID, pID, color)
+func (d *services) AddPlayerColorToGame(
+	ctx context.Context,
+	pID model.PlayerID,
+	color model.PlayerColor,
+	gID model.GameID,
+) error {
+	err := d.games.UpdatePlayerColor(ctx, gID, pID, color)
 	if err != nil {
 		return err
 	}
-	return d.players.UpdateGameColor(pID, gID, color)
+	return d.players.UpdateGameColor(ctx, pID, gID, color)
 }
 
-func (d *services) GetGame(id model.GameID) (model.Game, error) {
-	g, err := d.games.Get(id)
+func (d *services) GetGame(ctx context.Context, id model.GameID) (model.Game, error) {
+	g, err := d.games.Get(ctx, id)
 	if err != nil {
 		return model.Game{}, err
 	}
 
-	err = d.overwritePlayers(g)
+	err = d.overwritePlayers(ctx, g)
 	if err != nil {
 		return model.Game{}, err
 	}
@@ -91,11 +144,11 @@ func (d *services) GetGame(id model.GameID) (model.Game, error) {
 	return g, nil
 }
 
-func (d *services) overwritePlayers(g model.Game) error {
+func (d *services) overwritePlayers(ctx context.Context, g model.Game) error {
 	for i, player := range g.Players {
 		// overwrite the player that the game service knows
 		// about with the player that the players service knows about
-		p, err := d.GetPlayer(player.ID)
+		p, err := d.GetPlayer(ctx, player.ID)
 		if err != nil {
 			return err
 		}
@@ -105,13 +158,16 @@ func (d *services) overwritePlayers(g model.Game) error {
 	return nil
 }
 
-func (d *services) GetGameAction(id model.GameID, numActions uint) (model.Game, error) {
-	g, err := d.games.GetAt(id, numActions)
+func (d *services) GetGameAction(ctx context.Context, id model.GameID, numActions uint) (model.Game, error) {
+	g, err := d.games.GetAt(ctx, id, numActions)
+	if err == ErrGameSnapshotCompacted {
+		g, err = d.replayGameAction(ctx, id, numActions)
+	}
 	if err != nil {
 		return model.Game{}, err
 	}
 
-	err = d.overwritePlayers(g)
+	err = d.overwritePlayers(ctx, g)
 	if err != nil {
 		return model.Game{}, err
 	}
@@ -119,17 +175,44 @@ func (d *services) GetGameAction(id model.GameID, numActions uint) (model.Game,
 	return g, nil
 }
 
-func (d *services) CreateGame(g model.Game) error {
+// replayGameAction rebuilds a compacted snapshot by finding the closest checkpoint
+// before it and replaying the actions that came after the checkpoint.
+func (d *services) replayGameAction(ctx context.Context, id model.GameID, numActions uint) (model.Game, error) {
+	latest, err := d.games.Get(ctx, id)
+	if err != nil {
+		return model.Game{}, err
+	}
+	if int(numActions) >= latest.NumActions() {
+		return model.Game{}, ErrGameNotFound
+	}
+
+	var cp model.Game
+	for cpi := int(numActions) - 1; cpi >= 0; cpi-- {
+		cp, err = d.games.GetAt(ctx, id, uint(cpi))
+		if err == ErrGameSnapshotCompacted {
+			continue
+		}
+		if err != nil {
+			return model.Game{}, err
+		}
+
+		return d.replayer.replay(ctx, cp, latest.Actions[cpi:numActions])
+	}
+
+	return model.Game{}, ErrGameNotFound
+}
+
+func (d *services) CreateGame(ctx context.Context, g model.Game) error {
 	if g.NumActions() != 0 {
 		return errors.New(`cannot create game with actions`)
 	}
 
-	err := d.players.BeginGame(g.ID, g.Players)
+	err := d.players.BeginGame(ctx, g.ID, g.Players)
 	if err != nil {
 		return err
 	}
 
-	err = d.games.Begin(g)
+	err = d.games.Begin(ctx, g)
 	if err != nil {
 		return err
 	}
@@ -143,7 +226,7 @@ func (d *services) CreateGame(g model.Game) error {
 			continue
 		}
 
-		err = d.AddPlayerColorToGame(pID, c, g.ID)
+		err = d.AddPlayerColorToGame(ctx, pID, c, g.ID)
 		if err != nil {
 			return err
 		}
@@ -152,14 +235,125 @@ func (d *services) CreateGame(g model.Game) error {
 	return nil
 }
 
-func (d *services) SaveGame(g model.Game) error {
-	return d.games.Save(g)
+func (d *services) SaveGame(ctx context.Context, g model.Game) error {
+	return d.games.Save(ctx, g)
+}
+
+func (d *services) CompactGame(ctx context.Context, id model.GameID, rp RetentionPolicy) error {
+	g, err := d.games.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !g.IsOver() {
+		return ErrGameNotOver
+	}
+
+	// even when there's nothing to throw away, we compact the game so
+	// that we don't look at it again
+	return d.games.Compact(ctx, id, rp.SnapshotsToCompact(g))
+}
+
+func (d *services) GetGamesToCompact(ctx context.Context) ([]model.GameID, error) {
+	return d.games.GetUncompacted(ctx)
+}
+
+func (d *services) CountActiveGames(ctx context.Context) (int, error) {
+	return d.games.CountActive(ctx)
+}
+
+func (d *services) SetMoveDeadline(ctx context.Context, id model.GameID, deadline time.Time) error {
+	return d.games.SetMoveDeadline(ctx, id, deadline)
+}
+
+func (d *services) GetMoveDeadline(ctx context.Context, id model.GameID) (time.Time, error) {
+	return d.games.GetMoveDeadline(ctx, id)
+}
+
+func (d *services) GetOverdueGames(ctx context.Context, now time.Time) ([]model.GameID, error) {
+	return d.games.GetOverdue(ctx, now)
+}
+
+func (d *services) GetInteraction(ctx context.Context, id model.PlayerID) (interaction.PlayerMeans, error) {
+	return d.interactions.Get(ctx, id)
+}
+
+func (d *services) SaveInteraction(ctx context.Context, pm interaction.PlayerMeans) error {
+	return d.interactions.Update(ctx, pm)
+}
+
+func (d *services) CreateLobby(ctx context.Context, l model.Lobby) error {
+	if l.ID == model.InvalidLobbyID {
+		return ErrInvalidLobbyID
+	}
+	return d.lobbies.Create(ctx, l)
+}
+
+func (d *services) GetLobby(ctx context.Context, id model.LobbyID) (model.Lobby, error) {
+	return d.lobbies.Get(ctx, id)
+}
+
+func (d *services) GetOpenLobbies(ctx context.Context) ([]model.Lobby, error) {
+	return d.lobbies.GetOpen(ctx)
+}
+
+func (d *services) SaveLobby(ctx context.Context, l model.Lobby) error {
+	return d.lobbies.Update(ctx, l)
+}
+
+func (d *services) GetSpectators(ctx context.Context, gID model.GameID) ([]model.Spectator, error) {
+	return d.spectators.Get(ctx, gID)
+}
+
+func (d *services) SaveSpectator(ctx context.Context, s model.Spectator) error {
+	if s.GameID == model.InvalidGameID {
+		return ErrInvalidGameID
+	}
+	if !model.IsValidPlayerID(s.PlayerID) {
+		return ErrInvalidPlayerID
+	}
+	return d.spectators.Save(ctx, s)
+}
+
+func (d *services) RemoveSpectator(ctx context.Context, gID model.GameID, pID model.PlayerID) error {
+	return d.spectators.Remove(ctx, gID, pID)
+}
+
+func (d *services) GetChatMessages(ctx context.Context, gID model.GameID) ([]model.ChatMessage, error) {
+	return d.chats.Get(ctx, gID)
+}
+
+func (d *services) AddChatMessage(ctx context.Context, cm model.ChatMessage) error {
+	if cm.GameID == model.InvalidGameID {
+		return ErrInvalidGameID
+	}
+	if !model.IsValidPlayerID(cm.PlayerID) {
+		return ErrInvalidPlayerID
+	}
+	err := cm.Validate()
+	if err != nil {
+		return err
+	}
+	return d.chats.Add(ctx, cm)
+}
+
+func (d *services) AddNPCMove(ctx context.Context, j npcqueue.Job) (bool, error) {
+	if j.GameID == model.InvalidGameID {
+		return false, ErrInvalidGameID
+	}
+	if !model.IsValidPlayerID(j.PlayerID) {
+		return false, ErrInvalidPlayerID
+	}
+	return d.npcMoves.Add(ctx, j)
+}
+
+func (d *services) GetDueNPCMoves(ctx context.Context, now time.Time) ([]npcqueue.Job, error) {
+	return d.npcMoves.GetDue(ctx, now)
 }
 
-func (d *services) GetInteraction(id model.PlayerID) (interaction.PlayerMeans, error) {
-	return d.interactions.Get(id)
+func (d *services) UpdateNPCMove(ctx context.Context, j npcqueue.Job) error {
+	return d.npcMoves.Update(ctx, j)
 }
 
-func (d *services) SaveInteraction(pm interaction.PlayerMeans) error {
-	return d.interactions.Update(pm)
+func (d *services) RemoveNPCMove(ctx context.Context, k npcqueue.Key) error {
+	return d.npcMoves.Remove(ctx, k)
 }
@@ -11,10 +11,21 @@ var _ persistence.DBFactory = memDBF{}
 
 type memDBF struct {
 	db *memDB
+
+	replayer persistence.Replayer
 }
 
-func NewFactory() persistence.DBFactory {
-	return memDBF{}
+// NewFactory returns a factory for DBs which only last as long as the process does.
+// The replayer rebuilds the snapshots of games which have been compacted.
+func NewFactory(r persistence.Replayer) persistence.DBFactory {
+	return memDBF{
+		replayer: r,
+	}
+}
+
+func (dbf memDBF) Ping(ctx context.Context) error {
+	// memory is always there
+	return ctx.Err()
 }
 
 func (dbf memDBF) Close() error {
@@ -40,6 +51,11 @@ func (dbf memDBF) New(context.Context) (persistence.DB, error) {
 		getGameService(),
 		getPlayerService(),
 		getInteractionService(),
+		getLobbyService(),
+		getSpectatorService(),
+		getChatService(),
+		getNPCMoveService(),
+		dbf.replayer,
 	)
 
 	dbf.db = &memDB{
@@ -54,11 +70,13 @@ func (mdb *memDB) Close() error {
 	return nil
 }
 
-func (mdb *memDB) Start() error {
+func (mdb *memDB) Start(ctx context.Context) error {
 	mdb.lock.Lock()
 	defer mdb.lock.Unlock()
 
-	return nil
+	// there are no transactions in memory, but a request which is already
+	// done should not get to change anything
+	return ctx.Err()
 }
 
 func (mdb *memDB) Commit() error {
@@ -79,4 +97,8 @@ func Clear() {
 	gservice = nil
 	pservice = nil
 	iservice = nil
+	lservice = nil
+	sservice = nil
+	cservice = nil
+	nservice = nil
 }
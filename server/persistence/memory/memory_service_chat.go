@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+var cservice *chatService
+var _ persistence.ChatService = (*chatService)(nil)
+
+type chatService struct {
+	lock sync.Mutex
+
+	messages map[model.GameID][]model.ChatMessage
+}
+
+func getChatService() persistence.ChatService {
+	if cservice == nil {
+		cservice = &chatService{
+			messages: map[model.GameID][]model.ChatMessage{},
+		}
+	}
+	return cservice
+}
+
+func (cs *chatService) Get(ctx context.Context, gID model.GameID) ([]model.ChatMessage, error) {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+
+	msgs := make([]model.ChatMessage, len(cs.messages[gID]))
+	_ = copy(msgs, cs.messages[gID])
+	return msgs, nil
+}
+
+func (cs *chatService) Add(ctx context.Context, cm model.ChatMessage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+
+	cs.messages[cm.GameID] = append(cs.messages[cm.GameID], cm)
+	return nil
+}
@@ -1,8 +1,11 @@
 package memory
 
 import (
+	"context"
 	"errors"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/joshprzybyszewski/cribbage/model"
 	"github.com/joshprzybyszewski/cribbage/server/persistence"
@@ -14,30 +17,34 @@ var _ persistence.GameService = (*gameService)(nil)
 type gameService struct {
 	lock sync.Mutex
 
-	games map[model.GameID][]model.Game
+	games     map[model.GameID][]model.Game
+	compacted map[model.GameID]struct{}
+	deadlines map[model.GameID]time.Time
 }
 
 func getGameService() persistence.GameService {
 	if gservice == nil {
 		gservice = &gameService{
-			games: map[model.GameID][]model.Game{},
+			games:     map[model.GameID][]model.Game{},
+			compacted: map[model.GameID]struct{}{},
+			deadlines: map[model.GameID]time.Time{},
 		}
 	}
 	return gservice
 }
 
-func (gs *gameService) Get(id model.GameID) (model.Game, error) {
+func (gs *gameService) Get(ctx context.Context, id model.GameID) (model.Game, error) {
 	gs.lock.Lock()
 	defer gs.lock.Unlock()
 
 	if games, ok := gs.games[id]; ok {
 		g := games[len(games)-1]
-		return g, nil
+		return persistence.CopyGame(g), nil
 	}
 	return model.Game{}, persistence.ErrGameNotFound
 }
 
-func (gs *gameService) GetAt(id model.GameID, numActions uint) (model.Game, error) {
+func (gs *gameService) GetAt(ctx context.Context, id model.GameID, numActions uint) (model.Game, error) {
 	gs.lock.Lock()
 	defer gs.lock.Unlock()
 
@@ -46,12 +53,25 @@ func (gs *gameService) GetAt(id model.GameID, numActions uint) (model.Game, erro
 			return model.Game{}, persistence.ErrGameNotFound
 		}
 		g := games[numActions]
-		return g, nil
+		if g.ID == model.InvalidGameID {
+			// compacted snapshots are left as the zero value
+			return model.Game{}, persistence.ErrGameSnapshotCompacted
+		}
+		return persistence.CopyGame(g), nil
 	}
 	return model.Game{}, persistence.ErrGameNotFound
 }
 
-func (gs *gameService) UpdatePlayerColor(gID model.GameID, pID model.PlayerID, color model.PlayerColor) error {
+func (gs *gameService) UpdatePlayerColor(
+	ctx context.Context,
+	gID model.GameID,
+	pID model.PlayerID,
+	color model.PlayerColor,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	gs.lock.Lock()
 	defer gs.lock.Unlock()
 
@@ -73,11 +93,15 @@ func (gs *gameService) UpdatePlayerColor(gID model.GameID, pID model.PlayerID, c
 	return nil
 }
 
-func (gs *gameService) Begin(g model.Game) error {
-	return gs.Save(g)
+func (gs *gameService) Begin(ctx context.Context, g model.Game) error {
+	return gs.Save(ctx, g)
 }
 
-func (gs *gameService) Save(g model.Game) error {
+func (gs *gameService) Save(ctx context.Context, g model.Game) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	gs.lock.Lock()
 	defer gs.lock.Unlock()
 
@@ -94,11 +118,128 @@ func (gs *gameService) Save(g model.Game) error {
 		return err
 	}
 
-	gs.games[id] = append(gs.games[id], g)
+	// keep a copy so that later changes to the game do not rewrite history
+	gs.games[id] = append(gs.games[id], persistence.CopyGame(g))
+
+	return nil
+}
+
+func (gs *gameService) Compact(ctx context.Context, id model.GameID, numActions []uint) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	gs.lock.Lock()
+	defer gs.lock.Unlock()
+
+	games, ok := gs.games[id]
+	if !ok {
+		return persistence.ErrGameNotFound
+	}
+
+	for _, na := range numActions {
+		if int(na) >= len(games)-1 {
+			// never throw away the latest snapshot
+			continue
+		}
+		games[na] = model.Game{}
+	}
+	gs.compacted[id] = struct{}{}
+
+	return nil
+}
+
+func (gs *gameService) GetUncompacted(ctx context.Context) ([]model.GameID, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	gs.lock.Lock()
+	defer gs.lock.Unlock()
+
+	var gIDs []model.GameID
+	for id, games := range gs.games {
+		if _, ok := gs.compacted[id]; ok {
+			continue
+		}
+		if games[len(games)-1].IsOver() {
+			gIDs = append(gIDs, id)
+		}
+	}
+	sort.Slice(gIDs, func(i, j int) bool {
+		return gIDs[i] < gIDs[j]
+	})
+
+	return gIDs, nil
+}
+
+func (gs *gameService) CountActive(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	gs.lock.Lock()
+	defer gs.lock.Unlock()
+
+	n := 0
+	for _, games := range gs.games {
+		if !games[len(games)-1].IsOver() {
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+func (gs *gameService) SetMoveDeadline(ctx context.Context, id model.GameID, deadline time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	gs.lock.Lock()
+	defer gs.lock.Unlock()
+
+	if deadline.IsZero() {
+		delete(gs.deadlines, id)
+		return nil
+	}
+	gs.deadlines[id] = deadline
 
 	return nil
 }
 
+func (gs *gameService) GetMoveDeadline(ctx context.Context, id model.GameID) (time.Time, error) {
+	if err := ctx.Err(); err != nil {
+		return time.Time{}, err
+	}
+
+	gs.lock.Lock()
+	defer gs.lock.Unlock()
+
+	return gs.deadlines[id], nil
+}
+
+func (gs *gameService) GetOverdue(ctx context.Context, now time.Time) ([]model.GameID, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	gs.lock.Lock()
+	defer gs.lock.Unlock()
+
+	var gIDs []model.GameID
+	for id, deadline := range gs.deadlines {
+		if deadline.Before(now) {
+			gIDs = append(gIDs, id)
+		}
+	}
+	sort.Slice(gIDs, func(i, j int) bool {
+		return gIDs[i] < gIDs[j]
+	})
+
+	return gIDs, nil
+}
+
 func validateGameState(savedGames []model.Game, newGameState model.Game) error {
 	if len(savedGames) != newGameState.NumActions() {
 		return persistence.ErrGameActionsOutOfOrder
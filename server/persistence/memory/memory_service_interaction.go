@@ -1,6 +1,7 @@
 package memory
 
 import (
+	"context"
 	"sync"
 
 	"github.com/joshprzybyszewski/cribbage/model"
@@ -26,7 +27,7 @@ func getInteractionService() persistence.InteractionService {
 	return iservice
 }
 
-func (is *interactionService) Get(id model.PlayerID) (interaction.PlayerMeans, error) {
+func (is *interactionService) Get(ctx context.Context, id model.PlayerID) (interaction.PlayerMeans, error) {
 	is.lock.Lock()
 	defer is.lock.Unlock()
 
@@ -36,7 +37,11 @@ func (is *interactionService) Get(id model.PlayerID) (interaction.PlayerMeans, e
 	return interaction.PlayerMeans{}, persistence.ErrInteractionNotFound
 }
 
-func (is *interactionService) Create(pm interaction.PlayerMeans) error {
+func (is *interactionService) Create(ctx context.Context, pm interaction.PlayerMeans) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	is.lock.Lock()
 	defer is.lock.Unlock()
 
@@ -49,7 +54,11 @@ func (is *interactionService) Create(pm interaction.PlayerMeans) error {
 	return nil
 }
 
-func (is *interactionService) Update(pm interaction.PlayerMeans) error {
+func (is *interactionService) Update(ctx context.Context, pm interaction.PlayerMeans) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	is.lock.Lock()
 	defer is.lock.Unlock()
 
@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+var lservice *lobbyService
+var _ persistence.LobbyService = (*lobbyService)(nil)
+
+type lobbyService struct {
+	lock sync.Mutex
+
+	lobbies map[model.LobbyID]model.Lobby
+}
+
+func getLobbyService() persistence.LobbyService {
+	if lservice == nil {
+		lservice = &lobbyService{
+			lobbies: map[model.LobbyID]model.Lobby{},
+		}
+	}
+	return lservice
+}
+
+func (ls *lobbyService) Get(ctx context.Context, id model.LobbyID) (model.Lobby, error) {
+	ls.lock.Lock()
+	defer ls.lock.Unlock()
+
+	if l, ok := ls.lobbies[id]; ok {
+		return copyLobby(l), nil
+	}
+	return model.Lobby{}, persistence.ErrLobbyNotFound
+}
+
+func (ls *lobbyService) GetOpen(ctx context.Context) ([]model.Lobby, error) {
+	ls.lock.Lock()
+	defer ls.lock.Unlock()
+
+	var open []model.Lobby
+	for _, l := range ls.lobbies {
+		if l.Status == model.LobbyOpen {
+			open = append(open, copyLobby(l))
+		}
+	}
+	sort.Slice(open, func(i, j int) bool {
+		return open[i].Created.Before(open[j].Created)
+	})
+	return open, nil
+}
+
+func (ls *lobbyService) Create(ctx context.Context, l model.Lobby) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	ls.lock.Lock()
+	defer ls.lock.Unlock()
+
+	if _, ok := ls.lobbies[l.ID]; ok {
+		return persistence.ErrLobbyAlreadyExists
+	}
+
+	ls.lobbies[l.ID] = copyLobby(l)
+	return nil
+}
+
+func (ls *lobbyService) Update(ctx context.Context, l model.Lobby) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	ls.lock.Lock()
+	defer ls.lock.Unlock()
+
+	saved, ok := ls.lobbies[l.ID]
+	if !ok {
+		return persistence.ErrLobbyNotFound
+	}
+	if saved.Version != l.Version {
+		return persistence.ErrLobbyConflict
+	}
+
+	l.Version++
+	ls.lobbies[l.ID] = copyLobby(l)
+	return nil
+}
+
+func copyLobby(l model.Lobby) model.Lobby {
+	if l.Seated != nil {
+		l.Seated = append(make([]model.PlayerID, 0, len(l.Seated)), l.Seated...)
+	}
+	if l.Invited != nil {
+		l.Invited = append(make([]model.PlayerID, 0, len(l.Invited)), l.Invited...)
+	}
+	return l
+}
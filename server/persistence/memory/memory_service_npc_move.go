@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/joshprzybyszewski/cribbage/server/npcqueue"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+var nservice *npcMoveService
+var _ persistence.NPCMoveService = (*npcMoveService)(nil)
+
+// npcMoveService keeps the moves in the queue's own memory store, which
+// already keeps one move for each NPC in a game
+type npcMoveService struct {
+	store npcqueue.Store
+}
+
+func getNPCMoveService() persistence.NPCMoveService {
+	if nservice == nil {
+		nservice = &npcMoveService{
+			store: npcqueue.NewMemoryStore(),
+		}
+	}
+	return nservice
+}
+
+func (ns *npcMoveService) Add(ctx context.Context, j npcqueue.Job) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	return ns.store.Add(ctx, j)
+}
+
+func (ns *npcMoveService) GetDue(ctx context.Context, now time.Time) ([]npcqueue.Job, error) {
+	return ns.store.Due(ctx, now)
+}
+
+func (ns *npcMoveService) Update(ctx context.Context, j npcqueue.Job) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return ns.store.Update(ctx, j)
+}
+
+func (ns *npcMoveService) Remove(ctx context.Context, k npcqueue.Key) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return ns.store.Remove(ctx, k)
+}
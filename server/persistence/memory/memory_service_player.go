@@ -1,6 +1,7 @@
 package memory
 
 import (
+	"context"
 	"errors"
 	"sync"
 
@@ -26,18 +27,22 @@ func getPlayerService() persistence.PlayerService {
 	return pservice
 }
 
-func (ps *playerService) Get(id model.PlayerID) (model.Player, error) {
+func (ps *playerService) Get(ctx context.Context, id model.PlayerID) (model.Player, error) {
 	ps.lock.Lock()
 	defer ps.lock.Unlock()
 
-	if i, ok := ps.players[id]; ok {
-		return i, nil
+	if p, ok := ps.players[id]; ok {
+		return copyPlayer(p), nil
 	}
 
 	return model.Player{}, persistence.ErrPlayerNotFound
 }
 
-func (ps *playerService) Create(p model.Player) error {
+func (ps *playerService) Create(ctx context.Context, p model.Player) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	ps.lock.Lock()
 	defer ps.lock.Unlock()
 
@@ -46,15 +51,28 @@ func (ps *playerService) Create(p model.Player) error {
 		return persistence.ErrPlayerAlreadyExists
 	}
 
-	ps.players[id] = p
+	ps.players[id] = copyPlayer(p)
 	return nil
 }
 
-func (ps *playerService) BeginGame(gID model.GameID, players []model.Player) error {
+func (ps *playerService) BeginGame(ctx context.Context, gID model.GameID, players []model.Player) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
-func (ps *playerService) UpdateGameColor(pID model.PlayerID, gID model.GameID, color model.PlayerColor) error {
+func (ps *playerService) UpdateGameColor(
+	ctx context.Context,
+	pID model.PlayerID,
+	gID model.GameID,
+	color model.PlayerColor,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	ps.lock.Lock()
 	defer ps.lock.Unlock()
 
@@ -76,3 +94,16 @@ func (ps *playerService) UpdateGameColor(pID model.PlayerID, gID model.GameID, c
 	}
 	return nil
 }
+
+func copyPlayer(p model.Player) model.Player {
+	if p.Games == nil {
+		return p
+	}
+
+	games := make(map[model.GameID]model.PlayerColor, len(p.Games))
+	for gID, c := range p.Games {
+		games[gID] = c
+	}
+	p.Games = games
+	return p
+}
@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+var sservice *spectatorService
+var _ persistence.SpectatorService = (*spectatorService)(nil)
+
+type spectatorService struct {
+	lock sync.Mutex
+
+	spectators map[model.GameID]map[model.PlayerID]model.Spectator
+}
+
+func getSpectatorService() persistence.SpectatorService {
+	if sservice == nil {
+		sservice = &spectatorService{
+			spectators: map[model.GameID]map[model.PlayerID]model.Spectator{},
+		}
+	}
+	return sservice
+}
+
+func (ss *spectatorService) Get(ctx context.Context, gID model.GameID) ([]model.Spectator, error) {
+	ss.lock.Lock()
+	defer ss.lock.Unlock()
+
+	specs := make([]model.Spectator, 0, len(ss.spectators[gID]))
+	for _, s := range ss.spectators[gID] {
+		specs = append(specs, s)
+	}
+	sort.Slice(specs, func(i, j int) bool {
+		return specs[i].PlayerID < specs[j].PlayerID
+	})
+	return specs, nil
+}
+
+func (ss *spectatorService) Save(ctx context.Context, s model.Spectator) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	ss.lock.Lock()
+	defer ss.lock.Unlock()
+
+	if _, ok := ss.spectators[s.GameID]; !ok {
+		ss.spectators[s.GameID] = map[model.PlayerID]model.Spectator{}
+	}
+	ss.spectators[s.GameID][s.PlayerID] = s
+	return nil
+}
+
+func (ss *spectatorService) Remove(ctx context.Context, gID model.GameID, pID model.PlayerID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	ss.lock.Lock()
+	defer ss.lock.Unlock()
+
+	if _, ok := ss.spectators[gID][pID]; !ok {
+		return persistence.ErrSpectatorNotFound
+	}
+	delete(ss.spectators[gID], pID)
+	return nil
+}
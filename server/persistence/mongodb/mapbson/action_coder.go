@@ -0,0 +1,129 @@
+package mapbson
+
+import (
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+)
+
+var playerActionType = reflect.TypeOf(model.PlayerAction{})
+
+func registerPlayerAction(rb *bsoncodec.RegistryBuilder) {
+	c := playerActionCoder{}
+
+	rb.RegisterTypeEncoder(playerActionType, c)
+	rb.RegisterTypeDecoder(playerActionType, c)
+}
+
+// bsonActionEnvelope is how a model.PlayerAction is stored in mongo. It matches
+// the JSON encoding so that the type tag and schema version mean the same thing.
+type bsonActionEnvelope struct {
+	Version   int              `bson:"v,omitempty"`
+	GameID    model.GameID     `bson:"gID"`
+	ID        model.PlayerID   `bson:"pID"`
+	Overcomes model.Blocker    `bson:"o"`
+	Type      model.ActionType `bson:"t,omitempty"`
+	Action    bson.RawValue    `bson:"a"`
+	Auto      bool             `bson:"auto,omitempty"`
+}
+
+var _ bsoncodec.ValueEncoder = playerActionCoder{}
+var _ bsoncodec.ValueDecoder = playerActionCoder{}
+
+type playerActionCoder struct{}
+
+func (playerActionCoder) EncodeValue(
+	ectx bsoncodec.EncodeContext,
+	vw bsonrw.ValueWriter,
+	val reflect.Value,
+) error {
+
+	if !val.IsValid() || val.Type() != playerActionType {
+		return bsoncodec.ValueEncoderError{
+			Name:     "PlayerActionEncodeValue",
+			Types:    []reflect.Type{playerActionType},
+			Received: val,
+		}
+	}
+	pa := val.Interface().(model.PlayerAction)
+
+	env := bsonActionEnvelope{
+		Version:   model.CurrentActionVersion,
+		GameID:    pa.GameID,
+		ID:        pa.ID,
+		Overcomes: pa.Overcomes,
+		Auto:      pa.Auto,
+	}
+	if pa.Action != nil {
+		t, err := model.ActionTypeOf(pa.Action)
+		if err != nil {
+			return err
+		}
+		env.Type = t
+
+		bt, data, err := bson.MarshalValueWithRegistry(ectx.Registry, pa.Action)
+		if err != nil {
+			return err
+		}
+		env.Action = bson.RawValue{Type: bt, Value: data}
+	}
+
+	enc, err := ectx.LookupEncoder(reflect.TypeOf(env))
+	if err != nil {
+		return err
+	}
+	return enc.EncodeValue(ectx, vw, reflect.ValueOf(env))
+}
+
+func (playerActionCoder) DecodeValue(
+	dctx bsoncodec.DecodeContext,
+	vr bsonrw.ValueReader,
+	val reflect.Value,
+) error {
+
+	if !val.CanSet() || val.Type() != playerActionType {
+		return bsoncodec.ValueDecoderError{
+			Name:     "PlayerActionDecodeValue",
+			Types:    []reflect.Type{playerActionType},
+			Received: val,
+		}
+	}
+
+	var env bsonActionEnvelope
+	dec, err := dctx.LookupDecoder(reflect.TypeOf(env))
+	if err != nil {
+		return err
+	}
+	err = dec.DecodeValue(dctx, vr, reflect.ValueOf(&env).Elem())
+	if err != nil {
+		return err
+	}
+
+	t, err := model.ResolveActionType(env.Version, env.Type, env.Overcomes)
+	if err != nil {
+		return err
+	}
+	a, err := model.NewActionOfType(t)
+	if err != nil {
+		return err
+	}
+	if len(env.Action.Value) > 0 {
+		err = env.Action.UnmarshalWithRegistry(dctx.Registry, a)
+		if err != nil {
+			return err
+		}
+	}
+
+	val.Set(reflect.ValueOf(model.PlayerAction{
+		GameID:    env.GameID,
+		ID:        env.ID,
+		Overcomes: env.Overcomes,
+		Action:    model.ActionValue(a),
+		Auto:      env.Auto,
+	}))
+	return nil
+}
@@ -0,0 +1,84 @@
+package mapbson
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+)
+
+func TestPlayerActionCoder(t *testing.T) {
+	registry := CustomRegistry()
+
+	pa := model.PlayerAction{
+		GameID:    model.GameID(42),
+		ID:        model.PlayerID(`alice`),
+		Overcomes: model.CribCard,
+		Action: model.BuildCribAction{
+			Cards: []model.Card{
+				model.NewCardFromString(`jh`),
+				model.NewCardFromString(`5d`),
+			},
+		},
+	}
+
+	data, err := bson.MarshalWithRegistry(registry, pa)
+	require.NoError(t, err)
+
+	stored := bson.M{}
+	require.NoError(t, bson.Unmarshal(data, &stored))
+	assert.EqualValues(t, model.CurrentActionVersion, stored[`v`])
+	assert.Equal(t, string(model.BuildCribActionType), stored[`t`])
+
+	actOutput := model.PlayerAction{}
+	require.NoError(t, bson.UnmarshalWithRegistry(registry, data, &actOutput))
+	assert.Equal(t, pa, actOutput)
+	assert.NotContains(t, stored, `auto`)
+
+	pa.Overcomes = model.CountHand
+	pa.Action = model.CountHandAction{Pts: 8}
+	pa.Auto = true
+	data, err = bson.MarshalWithRegistry(registry, pa)
+	require.NoError(t, err)
+
+	actOutput = model.PlayerAction{}
+	require.NoError(t, bson.UnmarshalWithRegistry(registry, data, &actOutput))
+	assert.Equal(t, pa, actOutput)
+}
+
+func TestPlayerActionCoderDecodesUntaggedActions(t *testing.T) {
+	registry := CustomRegistry()
+
+	// this is how actions were stored before they had a version or type tag
+	data, err := bson.Marshal(bson.M{
+		`gID`: 42,
+		`pID`: `alice`,
+		`o`:   int(model.CutCard),
+		`a`:   bson.M{`p`: 0.25},
+	})
+	require.NoError(t, err)
+
+	actOutput := model.PlayerAction{}
+	require.NoError(t, bson.UnmarshalWithRegistry(registry, data, &actOutput))
+	assert.Equal(t, model.PlayerAction{
+		GameID:    model.GameID(42),
+		ID:        model.PlayerID(`alice`),
+		Overcomes: model.CutCard,
+		Action:    model.CutDeckAction{Percentage: 0.25},
+	}, actOutput)
+
+	data, err = bson.Marshal(bson.M{
+		`v`:   model.CurrentActionVersion + 1,
+		`gID`: 42,
+		`pID`: `alice`,
+		`o`:   int(model.CutCard),
+		`t`:   `cut`,
+		`a`:   bson.M{`p`: 0.25},
+	})
+	require.NoError(t, err)
+	err = bson.UnmarshalWithRegistry(registry, data, &actOutput)
+	assert.Equal(t, model.ErrUnknownActionVersion, err)
+}
@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"strconv"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 
 	"github.com/joshprzybyszewski/cribbage/model"
@@ -14,11 +15,13 @@ func CustomRegistry() *bsoncodec.Registry {
 
 	bsoncodec.DefaultValueEncoders{}.RegisterDefaultEncoders(rb)
 	bsoncodec.DefaultValueDecoders{}.RegisterDefaultDecoders(rb)
+	bson.PrimitiveCodecs{}.RegisterPrimitiveCodecs(rb)
 	registerPlayerGames(rb)
 	registerGameBlockingPlayers(rb)
 	registerGamePlayerColors(rb)
 	registerGameHands(rb)
 	registerGameScores(rb)
+	registerPlayerAction(rb)
 
 	return rb.Build()
 }
@@ -1,6 +1,7 @@
 package mapbson
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
@@ -185,10 +186,7 @@ func TestCustomRegistryModelGame(t *testing.T) {
 		actOutput := model.Game{}
 		err = bson.UnmarshalWithRegistry(registry, data1, &actOutput)
 		require.NoError(t, err, tc.msg)
-		actOutput.Actions = nil
-		expOutputNilled := tc.expOutput
-		expOutputNilled.Actions = nil
-		assert.Equal(t, expOutputNilled, actOutput, tc.msg)
+		assert.Equal(t, tc.expOutput, actOutput, tc.msg)
 
 		// Test deserialize from DB-BSON into model.Game
 		tempGame := bson.M{}
@@ -206,31 +204,31 @@ func gameAtPegging(t *testing.T, alice, bob model.Player, pAPIs map[model.Player
 	g, err := play.CreateGame([]model.Player{alice, bob}, pAPIs)
 	require.NoError(t, err)
 
-	require.NoError(t, play.HandleAction(&g, model.PlayerAction{
+	require.NoError(t, play.HandleAction(context.Background(), &g, model.PlayerAction{
 		ID:        alice.ID,
 		GameID:    g.ID,
 		Overcomes: model.DealCards,
 		Action:    model.DealAction{NumShuffles: 10},
 	}, pAPIs))
-	require.NoError(t, play.HandleAction(&g, model.PlayerAction{
+	require.NoError(t, play.HandleAction(context.Background(), &g, model.PlayerAction{
 		ID:        alice.ID,
 		GameID:    g.ID,
 		Overcomes: model.CribCard,
 		Action:    model.BuildCribAction{Cards: []model.Card{g.Hands[alice.ID][0], g.Hands[alice.ID][1]}},
 	}, pAPIs))
-	require.NoError(t, play.HandleAction(&g, model.PlayerAction{
+	require.NoError(t, play.HandleAction(context.Background(), &g, model.PlayerAction{
 		ID:        bob.ID,
 		GameID:    g.ID,
 		Overcomes: model.CribCard,
 		Action:    model.BuildCribAction{Cards: []model.Card{g.Hands[bob.ID][0], g.Hands[bob.ID][1]}},
 	}, pAPIs))
-	require.NoError(t, play.HandleAction(&g, model.PlayerAction{
+	require.NoError(t, play.HandleAction(context.Background(), &g, model.PlayerAction{
 		ID:        bob.ID,
 		GameID:    g.ID,
 		Overcomes: model.CutCard,
 		Action:    model.CutDeckAction{Percentage: 0.314},
 	}, pAPIs))
-	require.NoError(t, play.HandleAction(&g, model.PlayerAction{
+	require.NoError(t, play.HandleAction(context.Background(), &g, model.PlayerAction{
 		ID:        bob.ID,
 		GameID:    g.ID,
 		Overcomes: model.PegCard,
@@ -18,28 +18,32 @@ import (
 const (
 	dbName                     string = `cribbage`
 	gamesCollectionName        string = `games`
+	gameStatusesCollectionName string = `gameStatuses`
 	playersCollectionName      string = `players`
 	interactionsCollectionName string = `interactions`
+	lobbiesCollectionName      string = `lobbies`
+	spectatorsCollectionName   string = `spectators`
+	chatsCollectionName        string = `chats`
+	npcMovesCollectionName     string = `npcMoves`
 )
 
 const (
 	maxCommitTime time.Duration = 10 * time.Second // something very large for now -- this should be reduced
 )
 
-var _ persistence.DBFactory = mongoFactory{}
+var _ persistence.DBFactory = (*mongoFactory)(nil)
 
+// mongoFactory shares one client between all of its DBs. The client keeps a pool
+// of connections to the servers, so it is only disconnected when the factory closes.
 type mongoFactory struct {
-	uri string
-}
+	client *mongo.Client
 
-func NewFactory(uri string) (persistence.DBFactory, error) {
-	return mongoFactory{
-		uri: uri,
-	}, nil
+	replayer persistence.Replayer
 }
 
-func (mf mongoFactory) New(ctx context.Context) (persistence.DB, error) {
-	uri := mf.uri
+// NewFactory returns a factory for the DBs at the uri. The replayer rebuilds the
+// snapshots of games which have been compacted.
+func NewFactory(uri string, r persistence.Replayer) (persistence.DBFactory, error) {
 	if uri == `` {
 		// The default URI without replicas used to be:
 		// `mongodb://localhost:27017`
@@ -48,19 +52,39 @@ func (mf mongoFactory) New(ctx context.Context) (persistence.DB, error) {
 		uri = `mongodb://localhost:27017,localhost:27018,localhost:27019/?replicaSet=rs`
 	}
 
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	client, err := mongo.NewClient(options.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, err
+	}
+
+	// connecting starts the client's monitoring in the background. It doesn't wait
+	// to talk to the servers, so the context doesn't need a deadline.
+	err = client.Connect(context.Background())
 	if err != nil {
 		return nil, err
 	}
 
+	return &mongoFactory{
+		client:   client,
+		replayer: r,
+	}, nil
+}
+
+func (mf *mongoFactory) New(ctx context.Context) (_ persistence.DB, err error) {
 	// Always start a session so we can create a transaction if needed
-	sess, err := client.StartSession()
+	sess, err := mf.client.StartSession()
 	if err != nil {
 		return nil, err
 	}
+	defer func() {
+		if err != nil {
+			// give the session back to the client's pool
+			sess.EndSession(ctx)
+		}
+	}()
 
 	customRegistry := mapbson.CustomRegistry()
-	mdb := client.Database(dbName)
+	mdb := mf.client.Database(dbName)
 	gs, err := getGameService(ctx, sess, mdb, customRegistry)
 	if err != nil {
 		return nil, err
@@ -73,25 +97,51 @@ func (mf mongoFactory) New(ctx context.Context) (persistence.DB, error) {
 	if err != nil {
 		return nil, err
 	}
+	ls, err := getLobbyService(ctx, sess, mdb, customRegistry)
+	if err != nil {
+		return nil, err
+	}
+	ss, err := getSpectatorService(ctx, sess, mdb, customRegistry)
+	if err != nil {
+		return nil, err
+	}
+	cs, err := getChatService(ctx, sess, mdb, customRegistry)
+	if err != nil {
+		return nil, err
+	}
+	ns, err := getNPCMoveService(ctx, sess, mdb, customRegistry)
+	if err != nil {
+		return nil, err
+	}
 
 	sw := persistence.NewServicesWrapper(
 		gs,
 		ps,
 		is,
+		ls,
+		ss,
+		cs,
+		ns,
+		mf.replayer,
 	)
 
 	mw := mongoWrapper{
 		ServicesWrapper: sw,
 		ctx:             ctx,
-		client:          client,
 		session:         sess,
 	}
 
 	return &mw, nil
 }
 
-func (mf mongoFactory) Close() error {
-	return nil
+func (mf *mongoFactory) Ping(ctx context.Context) error {
+	// the client doesn't talk to the servers until it needs to, so we need to ask
+	// the primary (which takes the writes in our transactions) if it's there
+	return mf.client.Ping(ctx, readpref.Primary())
+}
+
+func (mf *mongoFactory) Close() error {
+	return mf.client.Disconnect(context.Background())
 }
 
 var _ persistence.DB = (*mongoWrapper)(nil)
@@ -100,18 +150,25 @@ type mongoWrapper struct {
 	persistence.ServicesWrapper
 
 	ctx     context.Context
-	client  *mongo.Client
 	session mongo.Session
 }
 
 func (mw *mongoWrapper) Close() error {
-	return mw.client.Disconnect(mw.ctx)
+	// the client is shared with the other DBs, so we only give back our session.
+	// This aborts the transaction if it was never committed or rolled back.
+	mw.session.EndSession(mw.ctx)
+	return nil
 }
 
-func (mw *mongoWrapper) Start() error {
+func (mw *mongoWrapper) Start(ctx context.Context) error {
 	if mw.session == nil {
 		return errors.New(`no session to use`)
 	}
+	// the session doesn't take a context to start its transaction, but each
+	// operation in it does, so a done ctx will fail them before the commit
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	txOpts := options.Transaction()
 	txOpts.SetReadConcern(readconcern.Local())
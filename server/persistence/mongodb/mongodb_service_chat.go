@@ -0,0 +1,81 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+const (
+	// needs to match model.ChatMessage.GameID
+	chatCollectionIndex string = `gID`
+)
+
+var _ persistence.ChatService = (*chatService)(nil)
+
+type chatService struct {
+	session mongo.Session
+	col     *mongo.Collection
+}
+
+func getChatService(
+	ctx context.Context,
+	session mongo.Session,
+	mdb *mongo.Database,
+	r *bsoncodec.Registry,
+) (persistence.ChatService, error) {
+
+	col := mdb.Collection(chatsCollectionName, &options.CollectionOptions{
+		Registry: r,
+	})
+
+	idxs := col.Indexes()
+	hasIndex, err := hasCollectionIndex(ctx, idxs, chatCollectionIndex)
+	if err != nil {
+		return nil, err
+	}
+	if !hasIndex {
+		err = createCollectionIndex(ctx, idxs, chatCollectionIndex)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &chatService{
+		session: session,
+		col:     col,
+	}, nil
+}
+
+func (cs *chatService) Get(ctx context.Context, gID model.GameID) ([]model.ChatMessage, error) {
+	msgs := []model.ChatMessage{}
+	// model.ChatMessage{GameID: gID}
+	filter := bson.M{`gID`: gID}
+	// the _id breaks ties between messages sent at the same time
+	opts := options.Find().SetSort(bson.D{{Key: `sent`, Value: 1}, {Key: `_id`, Value: 1}})
+	err := mongo.WithSession(ctx, cs.session, func(sc mongo.SessionContext) error {
+		cur, err := cs.col.Find(sc, filter, opts)
+		if err != nil {
+			return err
+		}
+		return cur.All(sc, &msgs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return msgs, nil
+}
+
+func (cs *chatService) Add(ctx context.Context, cm model.ChatMessage) error {
+	return mongo.WithSession(ctx, cs.session, func(sc mongo.SessionContext) error {
+		_, err := cs.col.InsertOne(sc, cm)
+		return err
+	})
+}
@@ -3,10 +3,9 @@ package mongodb
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
+	"time"
 
-	"github.com/joshprzybyszewski/cribbage/jsonutils"
 	"github.com/joshprzybyszewski/cribbage/model"
 	"github.com/joshprzybyszewski/cribbage/server/persistence"
 	"go.mongodb.org/mongo-driver/bson"
@@ -24,9 +23,11 @@ type gameList struct {
 	Games  []model.Game `bson:"games,omitempty"`
 }
 
+// persistedGameList is what we read back. Compacted snapshots are persisted as null,
+// so the games are pointers.
 type persistedGameList struct {
-	GameID    model.GameID `bson:"gameID"`
-	TempGames []bson.M     `bson:"games,omitempty"`
+	GameID    model.GameID  `bson:"gameID"`
+	TempGames []*model.Game `bson:"games,omitempty"`
 }
 
 func bsonGameIDFilter(id model.GameID) interface{} {
@@ -36,6 +37,15 @@ func bsonGameIDFilter(id model.GameID) interface{} {
 	return bson.M{gameCollectionIndex: id}
 }
 
+// gameStatus is what we need to know to find a game without loading it
+type gameStatus struct {
+	GameID    model.GameID `bson:"gameID"`
+	Finished  bool         `bson:"finished"`
+	Compacted bool         `bson:"compacted"`
+	// MoveDeadline is unset when the blocking players have all the time they want
+	MoveDeadline time.Time `bson:"moveDeadline,omitempty"`
+}
+
 type getGameOptions struct {
 	latest  bool
 	all     bool
@@ -45,9 +55,9 @@ type getGameOptions struct {
 var _ persistence.GameService = (*gameService)(nil)
 
 type gameService struct {
-	ctx     context.Context
-	session mongo.Session
-	col     *mongo.Collection
+	session  mongo.Session
+	col      *mongo.Collection
+	statuses *mongo.Collection
 }
 
 func getGameService(
@@ -73,10 +83,26 @@ func getGameService(
 		}
 	}
 
+	statuses := mdb.Collection(gameStatusesCollectionName, &options.CollectionOptions{
+		Registry: r,
+	})
+
+	idxs = statuses.Indexes()
+	hasIndex, err = hasGameCollectionIndex(ctx, idxs)
+	if err != nil {
+		return nil, err
+	}
+	if !hasIndex {
+		err = createGameCollectionIndex(ctx, idxs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &gameService{
-		ctx:     ctx,
-		session: session,
-		col:     col,
+		session:  session,
+		col:      col,
+		statuses: statuses,
 	}, nil
 }
 
@@ -88,20 +114,20 @@ func createGameCollectionIndex(ctx context.Context, idxs mongo.IndexView) error
 	return createCollectionIndex(ctx, idxs, gameCollectionIndex)
 }
 
-func (gs *gameService) Get(id model.GameID) (model.Game, error) {
-	return gs.getSingleGame(id, getGameOptions{
+func (gs *gameService) Get(ctx context.Context, id model.GameID) (model.Game, error) {
+	return gs.getSingleGame(ctx, id, getGameOptions{
 		latest: true,
 	})
 }
 
-func (gs *gameService) GetAt(id model.GameID, numActions uint) (model.Game, error) {
-	return gs.getSingleGame(id, getGameOptions{
+func (gs *gameService) GetAt(ctx context.Context, id model.GameID, numActions uint) (model.Game, error) {
+	return gs.getSingleGame(ctx, id, getGameOptions{
 		actions: map[int]struct{}{int(numActions): {}},
 	})
 }
 
-func (gs *gameService) getSingleGame(id model.GameID, opts getGameOptions) (model.Game, error) {
-	games, err := gs.getGameStates(id, opts)
+func (gs *gameService) getSingleGame(ctx context.Context, id model.GameID, opts getGameOptions) (model.Game, error) {
+	games, err := gs.getGameStates(ctx, id, opts)
 	if err != nil {
 		return model.Game{}, err
 	}
@@ -111,11 +137,15 @@ func (gs *gameService) getSingleGame(id model.GameID, opts getGameOptions) (mode
 	return games[0], nil
 }
 
-func (gs *gameService) getGameStates(id model.GameID, opts getGameOptions) ([]model.Game, error) { // nolint:gocyclo
+func (gs *gameService) getGameStates( // nolint:gocyclo
+	ctx context.Context,
+	id model.GameID,
+	opts getGameOptions,
+) ([]model.Game, error) {
 	pgl := persistedGameList{}
 	filter := bsonGameIDFilter(id)
 
-	err := mongo.WithSession(gs.ctx, gs.session, func(sc mongo.SessionContext) error {
+	err := mongo.WithSession(ctx, gs.session, func(sc mongo.SessionContext) error {
 		err := gs.col.FindOne(sc, filter).Decode(&pgl)
 		if err != nil {
 			if err == mongo.ErrNoDocuments {
@@ -147,24 +177,39 @@ func (gs *gameService) getGameStates(id model.GameID, opts getGameOptions) ([]mo
 			continue
 		}
 
-		obj, err := json.Marshal(tempGame)
-		if err != nil {
-			return nil, err
-		}
-
-		g, err := jsonutils.UnmarshalGame(obj)
-		if err != nil {
-			return nil, err
+		if tempGame == nil {
+			// compacted snapshots are persisted as null
+			return nil, persistence.ErrGameSnapshotCompacted
 		}
 
-		gl.Games = append(gl.Games, g)
+		gl.Games = append(gl.Games, withGameMaps(*tempGame))
 	}
 
 	return gl.Games, nil
 }
 
-func (gs *gameService) UpdatePlayerColor(gID model.GameID, pID model.PlayerID, color model.PlayerColor) error {
-	g, err := gs.Get(gID)
+// withGameMaps makes sure that the maps in the game aren't nil, since
+// a game saved without any entries in them decodes as nil
+func withGameMaps(g model.Game) model.Game {
+	if g.Hands == nil {
+		g.Hands = make(map[model.PlayerID][]model.Card, len(g.Players))
+	}
+	if g.BlockingPlayers == nil {
+		g.BlockingPlayers = make(map[model.PlayerID]model.Blocker, len(g.Players))
+	}
+	if g.PlayerColors == nil {
+		g.PlayerColors = make(map[model.PlayerID]model.PlayerColor, len(g.Players))
+	}
+	return g
+}
+
+func (gs *gameService) UpdatePlayerColor(
+	ctx context.Context,
+	gID model.GameID,
+	pID model.PlayerID,
+	color model.PlayerColor,
+) error {
+	g, err := gs.Get(ctx, gID)
 	if err != nil {
 		return err
 	}
@@ -178,7 +223,7 @@ func (gs *gameService) UpdatePlayerColor(gID model.GameID, pID model.PlayerID, c
 		return nil
 	}
 
-	games, err := gs.getGameStates(gID, getGameOptions{
+	games, err := gs.getGameStates(ctx, gID, getGameOptions{
 		all: true,
 	})
 	if err != nil {
@@ -197,18 +242,142 @@ func (gs *gameService) UpdatePlayerColor(gID model.GameID, pID model.PlayerID, c
 		Games:  games,
 	}
 
-	return gs.saveGameList(newGameList)
+	return gs.saveGameList(ctx, newGameList)
 }
 
-func (gs *gameService) Begin(g model.Game) error {
-	return gs.Save(g)
+func (gs *gameService) Compact(ctx context.Context, id model.GameID, numActions []uint) error {
+	pgl := persistedGameList{}
+	filter := bsonGameIDFilter(id)
+
+	err := mongo.WithSession(ctx, gs.session, func(sc mongo.SessionContext) error {
+		return gs.col.FindOne(sc, filter).Decode(&pgl)
+	})
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return persistence.ErrGameNotFound
+		}
+		return err
+	}
+
+	for _, na := range numActions {
+		if int(na) >= len(pgl.TempGames)-1 {
+			// never throw away the latest snapshot
+			continue
+		}
+		pgl.TempGames[na] = nil
+	}
+
+	err = mongo.WithSession(ctx, gs.session, func(sc mongo.SessionContext) error {
+		_, err := gs.col.ReplaceOne(sc, filter, pgl)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// gameStatus{Compacted: true}
+	return gs.updateStatus(ctx, id, bson.M{`compacted`: true})
 }
 
-func (gs *gameService) Save(g model.Game) error {
+func (gs *gameService) GetUncompacted(ctx context.Context) ([]model.GameID, error) {
+	// gameStatus{Finished: true, Compacted: false}
+	return gs.findStatuses(ctx, bson.M{
+		`finished`:  true,
+		`compacted`: bson.M{`$ne`: true},
+	})
+}
+
+func (gs *gameService) CountActive(ctx context.Context) (int, error) {
+	// every game has a game list, but only the finished ones are marked as finished
+	var started, finished int64
+	err := mongo.WithSession(ctx, gs.session, func(sc mongo.SessionContext) error {
+		var err error
+		started, err = gs.col.CountDocuments(sc, bson.M{})
+		if err != nil {
+			return err
+		}
+		// gameStatus{Finished: true}
+		finished, err = gs.statuses.CountDocuments(sc, bson.M{`finished`: true})
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(started - finished), nil
+}
+
+func (gs *gameService) SetMoveDeadline(ctx context.Context, id model.GameID, deadline time.Time) error {
+	if deadline.IsZero() {
+		return mongo.WithSession(ctx, gs.session, func(sc mongo.SessionContext) error {
+			// there's nothing to clear if the game doesn't have a status
+			_, err := gs.statuses.UpdateOne(sc, bsonGameIDFilter(id), bson.M{`$unset`: bson.M{`moveDeadline`: ``}})
+			return err
+		})
+	}
+
+	// gameStatus{MoveDeadline: deadline}
+	return gs.updateStatus(ctx, id, bson.M{`moveDeadline`: deadline})
+}
+
+func (gs *gameService) GetMoveDeadline(ctx context.Context, id model.GameID) (time.Time, error) {
+	var status gameStatus
+	err := mongo.WithSession(ctx, gs.session, func(sc mongo.SessionContext) error {
+		return gs.statuses.FindOne(sc, bsonGameIDFilter(id)).Decode(&status)
+	})
+	if err != nil && err != mongo.ErrNoDocuments {
+		return time.Time{}, err
+	}
+	// the games without a status, or without a deadline, don't have one
+	return status.MoveDeadline, nil
+}
+
+func (gs *gameService) GetOverdue(ctx context.Context, now time.Time) ([]model.GameID, error) {
+	// the games without a deadline don't match
+	return gs.findStatuses(ctx, bson.M{
+		`moveDeadline`: bson.M{`$lt`: now},
+	})
+}
+
+// findStatuses returns the IDs of the games whose status matches the filter
+func (gs *gameService) findStatuses(ctx context.Context, filter interface{}) ([]model.GameID, error) {
+	var statuses []gameStatus
+	opts := options.Find().SetSort(bson.M{gameCollectionIndex: 1})
+	err := mongo.WithSession(ctx, gs.session, func(sc mongo.SessionContext) error {
+		cur, err := gs.statuses.Find(sc, filter, opts)
+		if err != nil {
+			return err
+		}
+		return cur.All(sc, &statuses)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	gIDs := make([]model.GameID, len(statuses))
+	for i, s := range statuses {
+		gIDs[i] = s.GameID
+	}
+	return gIDs, nil
+}
+
+// updateStatus sets the given fields on the game's status, creating it if need be
+func (gs *gameService) updateStatus(ctx context.Context, id model.GameID, fields bson.M) error {
+	opts := options.Update().SetUpsert(true)
+	return mongo.WithSession(ctx, gs.session, func(sc mongo.SessionContext) error {
+		_, err := gs.statuses.UpdateOne(sc, bsonGameIDFilter(id), bson.M{`$set`: fields}, opts)
+		return err
+	})
+}
+
+func (gs *gameService) Begin(ctx context.Context, g model.Game) error {
+	return gs.Save(ctx, g)
+}
+
+func (gs *gameService) Save(ctx context.Context, g model.Game) error {
 	saved := gameList{}
 	filter := bsonGameIDFilter(g.ID)
 
-	err := mongo.WithSession(gs.ctx, gs.session, func(sc mongo.SessionContext) error {
+	err := mongo.WithSession(ctx, gs.session, func(sc mongo.SessionContext) error {
 		return gs.col.FindOne(sc, filter).Decode(&saved)
 	})
 
@@ -226,7 +395,7 @@ func (gs *gameService) Save(g model.Game) error {
 		saved.GameID = g.ID
 		saved.Games = []model.Game{g}
 
-		return mongo.WithSession(gs.ctx, gs.session, func(sc mongo.SessionContext) error {
+		return mongo.WithSession(ctx, gs.session, func(sc mongo.SessionContext) error {
 			var ior *mongo.InsertOneResult
 			ior, err = gs.col.InsertOne(sc, saved)
 			if err != nil {
@@ -256,7 +425,17 @@ func (gs *gameService) Save(g model.Game) error {
 
 	saved.Games = append(saved.Games, g)
 
-	return gs.saveGameList(saved)
+	err = gs.saveGameList(ctx, saved)
+	if err != nil {
+		return err
+	}
+
+	if g.IsOver() {
+		// so that the compactor can find it
+		// gameStatus{Finished: true}
+		return gs.updateStatus(ctx, g.ID, bson.M{`finished`: true})
+	}
+	return nil
 }
 
 func validateGameState(savedGames []model.Game, newGameState model.Game) error {
@@ -279,9 +458,9 @@ func validateGameState(savedGames []model.Game, newGameState model.Game) error {
 	return nil
 }
 
-func (gs *gameService) saveGameList(saved gameList) error {
+func (gs *gameService) saveGameList(ctx context.Context, saved gameList) error {
 	filter := bsonGameIDFilter(saved.GameID)
-	return mongo.WithSession(gs.ctx, gs.session, func(sc mongo.SessionContext) error {
+	return mongo.WithSession(ctx, gs.session, func(sc mongo.SessionContext) error {
 		ur, err := gs.col.ReplaceOne(sc, filter, saved)
 		if err != nil {
 			return err
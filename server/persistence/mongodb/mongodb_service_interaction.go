@@ -22,7 +22,6 @@ const (
 var _ persistence.InteractionService = (*interactionService)(nil)
 
 type interactionService struct {
-	ctx     context.Context
 	session mongo.Session
 	col     *mongo.Collection
 }
@@ -51,7 +50,6 @@ func getInteractionService(
 	}
 
 	return &interactionService{
-		ctx:     ctx,
 		session: session,
 		col:     col,
 	}, nil
@@ -70,10 +68,10 @@ func bsonInteractionFilter(id model.PlayerID) interface{} {
 	return bson.M{`playerID`: id}
 }
 
-func (s *interactionService) Get(id model.PlayerID) (interaction.PlayerMeans, error) {
+func (s *interactionService) Get(ctx context.Context, id model.PlayerID) (interaction.PlayerMeans, error) {
 	result := interaction.PlayerMeans{}
 	filter := bsonInteractionFilter(id)
-	err := mongo.WithSession(s.ctx, s.session, func(sc mongo.SessionContext) error {
+	err := mongo.WithSession(ctx, s.session, func(sc mongo.SessionContext) error {
 		err := s.col.FindOne(sc, filter).Decode(&result)
 		if err != nil {
 			if err == mongo.ErrNoDocuments {
@@ -87,16 +85,40 @@ func (s *interactionService) Get(id model.PlayerID) (interaction.PlayerMeans, er
 		return interaction.PlayerMeans{}, err
 	}
 
+	for i, m := range result.Interactions {
+		if m.Mode != interaction.Webhook {
+			continue
+		}
+		// the info is decoded as a bson document, so we need to turn it back into its type
+		result.Interactions[i].Info, err = decodeWebhookInfo(m.Info)
+		if err != nil {
+			return interaction.PlayerMeans{}, err
+		}
+	}
+
 	return result, nil
 }
 
-func (s *interactionService) Create(pm interaction.PlayerMeans) error {
-	_, err := s.Get(pm.PlayerID)
+func decodeWebhookInfo(info interface{}) (interaction.WebhookInfo, error) {
+	var wi interaction.WebhookInfo
+	raw, err := bson.Marshal(info)
+	if err != nil {
+		return interaction.WebhookInfo{}, err
+	}
+	err = bson.Unmarshal(raw, &wi)
+	if err != nil {
+		return interaction.WebhookInfo{}, err
+	}
+	return wi, nil
+}
+
+func (s *interactionService) Create(ctx context.Context, pm interaction.PlayerMeans) error {
+	_, err := s.Get(ctx, pm.PlayerID)
 	if err != nil && err != persistence.ErrInteractionNotFound {
 		return err
 	}
 
-	return mongo.WithSession(s.ctx, s.session, func(sc mongo.SessionContext) error {
+	return mongo.WithSession(ctx, s.session, func(sc mongo.SessionContext) error {
 		ior, err := s.col.InsertOne(sc, pm)
 		if err != nil {
 			return err
@@ -110,9 +132,9 @@ func (s *interactionService) Create(pm interaction.PlayerMeans) error {
 	})
 }
 
-func (s *interactionService) Update(pm interaction.PlayerMeans) error {
-	if _, err := s.Get(pm.PlayerID); err == persistence.ErrInteractionNotFound {
-		return mongo.WithSession(s.ctx, s.session, func(sc mongo.SessionContext) error {
+func (s *interactionService) Update(ctx context.Context, pm interaction.PlayerMeans) error {
+	if _, err := s.Get(ctx, pm.PlayerID); err == persistence.ErrInteractionNotFound {
+		return mongo.WithSession(ctx, s.session, func(sc mongo.SessionContext) error {
 			ior, err := s.col.InsertOne(sc, pm)
 			if err != nil {
 				return err
@@ -129,7 +151,7 @@ func (s *interactionService) Update(pm interaction.PlayerMeans) error {
 	opt := &options.ReplaceOptions{}
 	opt.SetUpsert(true)
 
-	return mongo.WithSession(s.ctx, s.session, func(sc mongo.SessionContext) error {
+	return mongo.WithSession(ctx, s.session, func(sc mongo.SessionContext) error {
 		ur, err := s.col.ReplaceOne(sc, pm, opt)
 		if err != nil {
 			return err
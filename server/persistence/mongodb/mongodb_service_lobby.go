@@ -0,0 +1,174 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+const (
+	// needs to match model.Lobby.ID
+	lobbyCollectionIndex string = `lobbyID`
+
+	// writeConflictCode is the code of the error for a write which conflicts with another transaction
+	writeConflictCode int32 = 112
+)
+
+var _ persistence.LobbyService = (*lobbyService)(nil)
+
+type lobbyService struct {
+	session mongo.Session
+	col     *mongo.Collection
+}
+
+func getLobbyService(
+	ctx context.Context,
+	session mongo.Session,
+	mdb *mongo.Database,
+	r *bsoncodec.Registry,
+) (persistence.LobbyService, error) {
+
+	col := mdb.Collection(lobbiesCollectionName, &options.CollectionOptions{
+		Registry: r,
+	})
+
+	idxs := col.Indexes()
+	hasIndex, err := hasCollectionIndex(ctx, idxs, lobbyCollectionIndex)
+	if err != nil {
+		return nil, err
+	}
+	if !hasIndex {
+		err = createCollectionIndex(ctx, idxs, lobbyCollectionIndex)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &lobbyService{
+		session: session,
+		col:     col,
+	}, nil
+}
+
+func bsonLobbyFilter(id model.LobbyID) interface{} {
+	// model.Lobby{ID: id}
+	return bson.M{`lobbyID`: id}
+}
+
+func (ls *lobbyService) Get(ctx context.Context, id model.LobbyID) (model.Lobby, error) {
+	result := model.Lobby{}
+	filter := bsonLobbyFilter(id)
+	err := mongo.WithSession(ctx, ls.session, func(sc mongo.SessionContext) error {
+		err := ls.col.FindOne(sc, filter).Decode(&result)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return persistence.ErrLobbyNotFound
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return model.Lobby{}, err
+	}
+
+	return result, nil
+}
+
+func (ls *lobbyService) GetOpen(ctx context.Context) ([]model.Lobby, error) {
+	var lobbies []model.Lobby
+	// model.Lobby{Status: model.LobbyOpen}
+	filter := bson.M{`status`: model.LobbyOpen}
+	opts := options.Find().SetSort(bson.M{`created`: 1})
+	err := mongo.WithSession(ctx, ls.session, func(sc mongo.SessionContext) error {
+		cur, err := ls.col.Find(sc, filter, opts)
+		if err != nil {
+			return err
+		}
+		return cur.All(sc, &lobbies)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return lobbies, nil
+}
+
+func (ls *lobbyService) Create(ctx context.Context, l model.Lobby) error {
+	_, err := ls.Get(ctx, l.ID)
+	if err == nil {
+		return persistence.ErrLobbyAlreadyExists
+	} else if err != persistence.ErrLobbyNotFound {
+		return err
+	}
+
+	return mongo.WithSession(ctx, ls.session, func(sc mongo.SessionContext) error {
+		ior, err := ls.col.InsertOne(sc, l)
+		if err != nil {
+			return err
+		}
+		if ior.InsertedID == nil {
+			return errors.New(`lobby not created`)
+		}
+
+		return nil
+	})
+}
+
+func (ls *lobbyService) Update(ctx context.Context, l model.Lobby) error {
+	// model.Lobby{ID: l.ID, Version: l.Version}
+	filter := bson.M{
+		`lobbyID`: l.ID,
+		`v`:       l.Version,
+	}
+	if l.Version == 0 {
+		// the lobbies saved before we had versions don't have one
+		filter[`v`] = bson.M{`$in`: bson.A{0, nil}}
+	}
+	l.Version++
+
+	var matched int64
+	err := mongo.WithSession(ctx, ls.session, func(sc mongo.SessionContext) error {
+		ur, err := ls.col.ReplaceOne(sc, filter, l)
+		if err != nil {
+			if isWriteConflict(err) {
+				// somebody else's transaction is saving it right now
+				return persistence.ErrLobbyConflict
+			}
+			return err
+		}
+		matched = ur.MatchedCount
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case matched == 1:
+		return nil
+	case matched > 1:
+		return errors.New(`matched more than one lobby`)
+	}
+
+	// either the lobby doesn't exist, or somebody else saved it first
+	_, err = ls.Get(ctx, l.ID)
+	if err != nil {
+		return err
+	}
+	return persistence.ErrLobbyConflict
+}
+
+// isWriteConflict returns true if the error is because another transaction wrote
+// the same document
+func isWriteConflict(err error) bool {
+	var ce mongo.CommandError
+	return errors.As(err, &ce) && ce.Code == writeConflictCode
+}
@@ -0,0 +1,187 @@
+package mongodb
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/npcqueue"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+const (
+	// needs to match npcMove.NextAttempt
+	npcMoveCollectionIndex string = `next`
+)
+
+var _ persistence.NPCMoveService = (*npcMoveService)(nil)
+
+type npcMoveService struct {
+	session mongo.Session
+	col     *mongo.Collection
+}
+
+// npcMoveID is the _id of an NPC's move, because the NPC only has one move for each game
+type npcMoveID struct {
+	GameID   model.GameID   `bson:"gID"`
+	PlayerID model.PlayerID `bson:"pID"`
+}
+
+type npcMove struct {
+	ID          npcMoveID `bson:"_id"`
+	NumActions  int       `bson:"n"`
+	Queued      time.Time `bson:"queued"`
+	NextAttempt time.Time `bson:"next"`
+	// Job is the JSON of the npcqueue.Job, which has the action and its failures
+	Job []byte `bson:"job"`
+}
+
+func getNPCMoveService(
+	ctx context.Context,
+	session mongo.Session,
+	mdb *mongo.Database,
+	r *bsoncodec.Registry,
+) (persistence.NPCMoveService, error) {
+
+	col := mdb.Collection(npcMovesCollectionName, &options.CollectionOptions{
+		Registry: r,
+	})
+
+	idxs := col.Indexes()
+	hasIndex, err := hasCollectionIndex(ctx, idxs, npcMoveCollectionIndex)
+	if err != nil {
+		return nil, err
+	}
+	if !hasIndex {
+		err = createCollectionIndex(ctx, idxs, npcMoveCollectionIndex)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &npcMoveService{
+		session: session,
+		col:     col,
+	}, nil
+}
+
+func newNPCMove(j npcqueue.Job) (npcMove, error) {
+	job, err := json.Marshal(j)
+	if err != nil {
+		return npcMove{}, err
+	}
+
+	return npcMove{
+		ID: npcMoveID{
+			GameID:   j.GameID,
+			PlayerID: j.PlayerID,
+		},
+		NumActions:  j.NumActions,
+		Queued:      j.Queued,
+		NextAttempt: j.NextAttempt,
+		Job:         job,
+	}, nil
+}
+
+func bsonNPCMoveFilter(k npcqueue.Key) interface{} {
+	// npcMove{ID: npcMoveID{GameID: k.GameID, PlayerID: k.PlayerID}, NumActions: k.NumActions}
+	return bson.M{
+		`_id`: npcMoveID{
+			GameID:   k.GameID,
+			PlayerID: k.PlayerID,
+		},
+		`n`: k.NumActions,
+	}
+}
+
+func (ns *npcMoveService) Add(ctx context.Context, j npcqueue.Job) (bool, error) {
+	nm, err := newNPCMove(j)
+	if err != nil {
+		return false, err
+	}
+
+	added := false
+	err = mongo.WithSession(ctx, ns.session, func(sc mongo.SessionContext) error {
+		var cur npcMove
+		findErr := ns.col.FindOne(sc, bson.M{`_id`: nm.ID}).Decode(&cur)
+		if findErr != nil && findErr != mongo.ErrNoDocuments {
+			return findErr
+		}
+		if findErr == nil && cur.NumActions >= nm.NumActions {
+			return nil
+		}
+
+		opts := options.Replace().SetUpsert(true)
+		_, replaceErr := ns.col.ReplaceOne(sc, bson.M{`_id`: nm.ID}, nm, opts)
+		if replaceErr != nil {
+			return replaceErr
+		}
+		added = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return added, nil
+}
+
+func (ns *npcMoveService) GetDue(ctx context.Context, now time.Time) ([]npcqueue.Job, error) {
+	var nms []npcMove
+	// npcMove{NextAttempt: <= now}
+	filter := bson.M{`next`: bson.M{`$lte`: now}}
+	opts := options.Find().SetSort(bson.D{
+		{Key: `queued`, Value: 1},
+		{Key: `_id.gID`, Value: 1},
+		{Key: `_id.pID`, Value: 1},
+	})
+	err := mongo.WithSession(ctx, ns.session, func(sc mongo.SessionContext) error {
+		cur, err := ns.col.Find(sc, filter, opts)
+		if err != nil {
+			return err
+		}
+		return cur.All(sc, &nms)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]npcqueue.Job, 0, len(nms))
+	for _, nm := range nms {
+		var j npcqueue.Job
+		err = json.Unmarshal(nm.Job, &j)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, j)
+	}
+
+	return jobs, nil
+}
+
+func (ns *npcMoveService) Update(ctx context.Context, j npcqueue.Job) error {
+	nm, err := newNPCMove(j)
+	if err != nil {
+		return err
+	}
+
+	filter := bsonNPCMoveFilter(j.Key)
+	return mongo.WithSession(ctx, ns.session, func(sc mongo.SessionContext) error {
+		_, replaceErr := ns.col.ReplaceOne(sc, filter, nm)
+		return replaceErr
+	})
+}
+
+func (ns *npcMoveService) Remove(ctx context.Context, k npcqueue.Key) error {
+	filter := bsonNPCMoveFilter(k)
+	return mongo.WithSession(ctx, ns.session, func(sc mongo.SessionContext) error {
+		_, err := ns.col.DeleteOne(sc, filter)
+		return err
+	})
+}
@@ -21,7 +21,6 @@ const (
 var _ persistence.PlayerService = (*playerService)(nil)
 
 type playerService struct {
-	ctx     context.Context
 	session mongo.Session
 	col     *mongo.Collection
 }
@@ -50,7 +49,6 @@ func getPlayerService(
 	}
 
 	return &playerService{
-		ctx:     ctx,
 		session: session,
 		col:     col,
 	}, nil
@@ -68,10 +66,10 @@ func bsonPlayerIDFilter(id model.PlayerID) interface{} {
 	return bson.M{playerCollectionIndex: id} // model.Player.ID
 }
 
-func (ps *playerService) Get(id model.PlayerID) (model.Player, error) {
+func (ps *playerService) Get(ctx context.Context, id model.PlayerID) (model.Player, error) {
 	result := model.Player{}
 	filter := bsonPlayerIDFilter(id)
-	err := mongo.WithSession(ps.ctx, ps.session, func(sc mongo.SessionContext) error {
+	err := mongo.WithSession(ctx, ps.session, func(sc mongo.SessionContext) error {
 		return ps.col.FindOne(sc, filter).Decode(&result)
 	})
 
@@ -84,10 +82,10 @@ func (ps *playerService) Get(id model.PlayerID) (model.Player, error) {
 	return result, nil
 }
 
-func (ps *playerService) Create(p model.Player) error {
+func (ps *playerService) Create(ctx context.Context, p model.Player) error {
 	// check if the player already exists
 	filter := bsonPlayerIDFilter(p.ID)
-	err := mongo.WithSession(ps.ctx, ps.session, func(sc mongo.SessionContext) error {
+	err := mongo.WithSession(ctx, ps.session, func(sc mongo.SessionContext) error {
 		c, err := ps.col.Find(sc, filter)
 		if err != nil {
 			return err
@@ -101,7 +99,7 @@ func (ps *playerService) Create(p model.Player) error {
 		return err
 	}
 
-	return mongo.WithSession(ps.ctx, ps.session, func(sc mongo.SessionContext) error {
+	return mongo.WithSession(ctx, ps.session, func(sc mongo.SessionContext) error {
 		ior, err := ps.col.InsertOne(sc, p)
 		if err != nil {
 			return err
@@ -115,12 +113,17 @@ func (ps *playerService) Create(p model.Player) error {
 	})
 }
 
-func (ps *playerService) BeginGame(gID model.GameID, players []model.Player) error {
+func (ps *playerService) BeginGame(ctx context.Context, gID model.GameID, players []model.Player) error {
 	return nil
 }
 
-func (ps *playerService) UpdateGameColor(pID model.PlayerID, gID model.GameID, color model.PlayerColor) error {
-	p, err := ps.Get(pID)
+func (ps *playerService) UpdateGameColor(
+	ctx context.Context,
+	pID model.PlayerID,
+	gID model.GameID,
+	color model.PlayerColor,
+) error {
+	p, err := ps.Get(ctx, pID)
 	if err != nil {
 		return err
 	}
@@ -143,7 +146,7 @@ func (ps *playerService) UpdateGameColor(pID model.PlayerID, gID model.GameID, c
 	filter := bsonPlayerIDFilter(pID)
 	opt := &options.FindOneAndReplaceOptions{}
 	opt.SetUpsert(true)
-	return mongo.WithSession(ps.ctx, ps.session, func(sc mongo.SessionContext) error {
+	return mongo.WithSession(ctx, ps.session, func(sc mongo.SessionContext) error {
 		sr := ps.col.FindOneAndReplace(sc, filter, p)
 		return sr.Err()
 	})
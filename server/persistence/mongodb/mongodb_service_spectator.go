@@ -0,0 +1,101 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+const (
+	// needs to match model.Spectator.GameID
+	spectatorCollectionIndex string = `gID`
+)
+
+var _ persistence.SpectatorService = (*spectatorService)(nil)
+
+type spectatorService struct {
+	session mongo.Session
+	col     *mongo.Collection
+}
+
+func getSpectatorService(
+	ctx context.Context,
+	session mongo.Session,
+	mdb *mongo.Database,
+	r *bsoncodec.Registry,
+) (persistence.SpectatorService, error) {
+
+	col := mdb.Collection(spectatorsCollectionName, &options.CollectionOptions{
+		Registry: r,
+	})
+
+	idxs := col.Indexes()
+	hasIndex, err := hasCollectionIndex(ctx, idxs, spectatorCollectionIndex)
+	if err != nil {
+		return nil, err
+	}
+	if !hasIndex {
+		err = createCollectionIndex(ctx, idxs, spectatorCollectionIndex)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &spectatorService{
+		session: session,
+		col:     col,
+	}, nil
+}
+
+func bsonSpectatorFilter(gID model.GameID, pID model.PlayerID) interface{} {
+	// model.Spectator{GameID: gID, PlayerID: pID}
+	return bson.M{`gID`: gID, `pID`: pID}
+}
+
+func (ss *spectatorService) Get(ctx context.Context, gID model.GameID) ([]model.Spectator, error) {
+	specs := []model.Spectator{}
+	// model.Spectator{GameID: gID}
+	filter := bson.M{`gID`: gID}
+	opts := options.Find().SetSort(bson.M{`pID`: 1})
+	err := mongo.WithSession(ctx, ss.session, func(sc mongo.SessionContext) error {
+		cur, err := ss.col.Find(sc, filter, opts)
+		if err != nil {
+			return err
+		}
+		return cur.All(sc, &specs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return specs, nil
+}
+
+func (ss *spectatorService) Save(ctx context.Context, s model.Spectator) error {
+	filter := bsonSpectatorFilter(s.GameID, s.PlayerID)
+	opts := options.Replace().SetUpsert(true)
+	return mongo.WithSession(ctx, ss.session, func(sc mongo.SessionContext) error {
+		_, err := ss.col.ReplaceOne(sc, filter, s, opts)
+		return err
+	})
+}
+
+func (ss *spectatorService) Remove(ctx context.Context, gID model.GameID, pID model.PlayerID) error {
+	filter := bsonSpectatorFilter(gID, pID)
+	return mongo.WithSession(ctx, ss.session, func(sc mongo.SessionContext) error {
+		dr, err := ss.col.DeleteOne(sc, filter)
+		if err != nil {
+			return err
+		}
+		if dr.DeletedCount == 0 {
+			return persistence.ErrSpectatorNotFound
+		}
+		return nil
+	})
+}
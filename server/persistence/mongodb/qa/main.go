@@ -4,9 +4,10 @@ import (
 	"fmt"
 
 	"github.com/joshprzybyszewski/cribbage/server/persistence/mongodb"
+	"github.com/joshprzybyszewski/cribbage/server/play"
 )
 
 func main() {
-	dbf, err := mongodb.NewFactory(``)
+	dbf, err := mongodb.NewFactory(``, play.Replay)
 	fmt.Printf("dbf, err := %+v, %+v", dbf, err)
 }
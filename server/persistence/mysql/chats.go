@@ -0,0 +1,102 @@
+package mysql
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+const (
+	// ChatMessages stores what the players have said in their games.
+	// The columns act as follows:
+	// MessageID keeps messages that were sent at the same time in order
+	// GameID is the game that the message was sent in
+	// Sent is when the message was sent, so that we can return the history in order
+	// Message is the json encoded model.ChatMessage
+	createChatMessagesTable = `CREATE TABLE IF NOT EXISTS ChatMessages (
+		MessageID BIGINT UNSIGNED AUTO_INCREMENT,
+		GameID INT UNSIGNED,
+		Sent TIMESTAMP(6) DEFAULT CURRENT_TIMESTAMP(6),
+		Message BLOB,
+		PRIMARY KEY (MessageID),
+		INDEX (GameID)
+	) ENGINE = INNODB;`
+
+	getChatMessagesForGame = `SELECT
+		Message
+	FROM ChatMessages
+	WHERE GameID = ?
+	ORDER BY
+		Sent ASC,
+		MessageID ASC
+	;`
+
+	addChatMessage = `INSERT INTO ChatMessages
+		(GameID, Sent, Message)
+	VALUES
+		(?, ?, ?)
+	;`
+)
+
+var (
+	chatsCreateStmts = []string{
+		createChatMessagesTable,
+	}
+)
+
+var _ persistence.ChatService = (*chatService)(nil)
+
+type chatService struct {
+	db *txWrapper
+}
+
+func getChatService(
+	db *txWrapper,
+) persistence.ChatService {
+
+	return &chatService{
+		db: db,
+	}
+}
+
+func (s *chatService) Get(ctx context.Context, gID model.GameID) ([]model.ChatMessage, error) {
+	rows, err := s.db.QueryContext(ctx, getChatMessagesForGame, gID)
+	if err != nil {
+		return nil, err
+	}
+
+	msgs := []model.ChatMessage{}
+	var ser []byte
+	var cm model.ChatMessage
+	for rows.Next() {
+		err = rows.Scan(&ser)
+		if err != nil {
+			return nil, err
+		}
+
+		cm = model.ChatMessage{}
+		err = json.Unmarshal(ser, &cm)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, cm)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return msgs, nil
+}
+
+func (s *chatService) Add(ctx context.Context, cm model.ChatMessage) error {
+	ser, err := json.Marshal(cm)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, addChatMessage, cm.GameID, cm.Sent, ser)
+	return err
+}
@@ -1,6 +1,7 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
@@ -23,11 +24,18 @@ const (
 	// CutCard is a number representation of the card that's been cut
 	// Crib is a 4-byte int of the (up to 4) cards in the crib where every byte is each crib card.
 	//   If this weren't just a fun project, I wouldn't try to be this tricky.
+	// DeckOrder is the shuffled deck for the round, where every byte is the next card from the top
+	// Shuffles is the json encoded slice of model.ShuffleProofs for provably fair games
 	// CurrentDealer is the PlayerID for the dealer
 	// BlockingPlayers is a json encoded map of who's blocking and why
 	// Hands is a json encoded map of slices for player hands
 	// PeggedCards is the json-encoded slice of previously pegged cards
+	// Pegging is the json encoded model.PeggingState
 	// Action is the json encoded model.PlayerAction
+	// Outcome is the json encoded model.GameOutcome
+	// When a finished game is compacted, we keep the Action of every row, but we
+	//   set BlockingPlayers, Hands, DeckOrder, Shuffles, PeggedCards, and Pegging to NULL for the thrown
+	//   away snapshots.
 	createGameTable = `CREATE TABLE IF NOT EXISTS Games (
 		GameID INT UNSIGNED,
 		NumActions INT UNSIGNED,
@@ -41,31 +49,58 @@ const (
 		Phase TINYINT UNSIGNED,
 		CutCard SMALLINT,
 		Crib INT,
+		DeckOrder BLOB,
+		Shuffles BLOB,
 		CurrentDealer VARCHAR(` + maxPlayerUUIDLenStr + `) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_as_cs,
 		BlockingPlayers BLOB,
 		Hands BLOB,
 		PeggedCards BLOB,
+		Pegging BLOB,
 		Action BLOB,
+		Outcome BLOB,
 		PRIMARY KEY (GameID, NumActions)
 	) ENGINE = INNODB;`
 
+	// GamePlayers stores the parts of a game that do not change after it is created.
+	// The columns act as follows:
+	// GameID is a UUID to identify a game
+	// Player1ID through Player4ID are the players in the game, in order
+	// Settings is the json encoded model.GameSettings
 	createGamePlayersTable = `CREATE TABLE IF NOT EXISTS GamePlayers (
 		GameID INT UNSIGNED,
 		Player1ID VARCHAR(` + maxPlayerUUIDLenStr + `) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_as_cs NOT NULL,
 		Player2ID VARCHAR(` + maxPlayerUUIDLenStr + `) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_as_cs NOT NULL,
 		Player3ID VARCHAR(` + maxPlayerUUIDLenStr + `) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_as_cs,
 		Player4ID VARCHAR(` + maxPlayerUUIDLenStr + `) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_as_cs,
+		Settings BLOB,
 		PRIMARY KEY (GameID)
 	) ENGINE = INNODB;`
 
+	// GameStatuses keeps what we need to know to find a game without loading it.
+	// The columns act as follows:
+	// GameID is a UUID to identify a game
+	// Finished is whether the game is over
+	// Compacted is whether the snapshots of the finished game have been compacted
+	// MoveDeadline is when the blocking players run out of time to act, or NULL if they have all the time they want
+	createGameStatusesTable = `CREATE TABLE IF NOT EXISTS GameStatuses (
+		GameID INT UNSIGNED,
+		Finished BOOL DEFAULT FALSE,
+		Compacted BOOL DEFAULT FALSE,
+		MoveDeadline TIMESTAMP(6) NULL DEFAULT NULL,
+		PRIMARY KEY (GameID),
+		INDEX (Finished, Compacted),
+		INDEX (MoveDeadline)
+	) ENGINE = INNODB;`
+
 	queryLatestGame = `SELECT 
 		gp.Player1ID, gp.Player2ID, gp.Player3ID, gp.Player4ID,
 		g.ScoreBlue, g.ScoreRed, g.ScoreGreen,
 		g.ScoreBlueLag, g.ScoreRedLag, g.ScoreGreenLag,
 		g.Phase, g.BlockingPlayers, g.CurrentDealer,
-		g.Hands, g.Crib, g.CutCard,
-		g.PeggedCards,
-		g.NumActions, g.Action
+		g.Hands, g.Crib, g.DeckOrder, g.Shuffles, g.CutCard,
+		g.PeggedCards, g.Pegging,
+		g.NumActions, g.Action,
+		gp.Settings, g.Outcome
 	FROM Games g
 	INNER JOIN GamePlayers gp
 		ON g.GameID = gp.GameID
@@ -79,9 +114,10 @@ const (
 		g.ScoreBlue, g.ScoreRed, g.ScoreGreen,
 		g.ScoreBlueLag, g.ScoreRedLag, g.ScoreGreenLag,
 		g.Phase, g.BlockingPlayers, g.CurrentDealer,
-		g.Hands, g.Crib, g.CutCard,
-		g.PeggedCards,
-		g.NumActions, g.Action
+		g.Hands, g.Crib, g.DeckOrder, g.Shuffles, g.CutCard,
+		g.PeggedCards, g.Pegging,
+		g.NumActions, g.Action,
+		gp.Settings, g.Outcome
 	FROM Games g
 	INNER JOIN GamePlayers gp
 		ON g.GameID = gp.GameID
@@ -96,15 +132,86 @@ const (
 		NumActions <= ?
 	;`
 
+	compactGameAt = `UPDATE Games
+	SET
+		BlockingPlayers = NULL,
+		Hands = NULL,
+		DeckOrder = NULL,
+		Shuffles = NULL,
+		PeggedCards = NULL,
+		Pegging = NULL
+	WHERE GameID = ? AND
+		NumActions = ?
+	;`
+
+	queryUncompactedGames = `SELECT 
+		GameID
+	FROM GameStatuses
+	WHERE Finished AND
+		NOT Compacted
+	ORDER BY
+		GameID
+	;`
+
+	// every game has one row in GamePlayers, but only some have a status
+	queryActiveGamesCount = `SELECT
+		COUNT(*)
+	FROM GamePlayers gp
+	LEFT JOIN GameStatuses gs
+		ON gs.GameID = gp.GameID
+	WHERE gs.Finished IS NULL OR
+		NOT gs.Finished
+	;`
+
+	markGameFinished = `INSERT INTO GameStatuses
+		(GameID, Finished)
+	VALUES
+		(?, TRUE)
+	ON DUPLICATE KEY UPDATE
+		Finished = TRUE
+	;`
+
+	queryOverdueGames = `SELECT 
+		GameID
+	FROM GameStatuses
+	WHERE MoveDeadline < ?
+	ORDER BY
+		GameID
+	;`
+
+	queryGameMoveDeadline = `SELECT
+		MoveDeadline
+	FROM GameStatuses
+	WHERE GameID = ?
+	;`
+
+	setGameMoveDeadline = `INSERT INTO GameStatuses
+		(GameID, MoveDeadline)
+	VALUES
+		(?, ?)
+	ON DUPLICATE KEY UPDATE
+		MoveDeadline = VALUES(MoveDeadline)
+	;`
+
+	markGameCompacted = `INSERT INTO GameStatuses
+		(GameID, Compacted)
+	VALUES
+		(?, TRUE)
+	ON DUPLICATE KEY UPDATE
+		Compacted = TRUE
+	;`
+
 	addPlayersToGamePlayers = `INSERT INTO GamePlayers
 		(
 			GameID, 
-			Player1ID, Player2ID, Player3ID, Player4ID
+			Player1ID, Player2ID, Player3ID, Player4ID,
+			Settings
 		)
 	VALUES
 		(
 			?,
-			?, ?, ?, ?
+			?, ?, ?, ?,
+			?
 		)
 	;`
 
@@ -113,18 +220,20 @@ const (
 			GameID, NumActions, 
 			ScoreBlue, ScoreRed, ScoreGreen,
 			ScoreBlueLag, ScoreRedLag, ScoreGreenLag,
-			Phase, CutCard, Crib,
+			Phase, CutCard, Crib, DeckOrder, Shuffles,
 			CurrentDealer,
-			BlockingPlayers, Hands, PeggedCards, Action
+			BlockingPlayers, Hands, PeggedCards, Pegging, Action,
+			Outcome
 		)
 	VALUES
 		(
 			?, ?,
 			?, ?, ?,
 			?, ?, ?,
-			?, ?, ?,
+			?, ?, ?, ?, ?,
 			?,
-			?, ?, ?, ?
+			?, ?, ?, ?, ?,
+			?
 		)
 	;`
 )
@@ -133,6 +242,7 @@ var (
 	gamesCreateStmts = []string{
 		createGameTable,
 		createGamePlayersTable,
+		createGameStatusesTable,
 	}
 )
 
@@ -151,17 +261,18 @@ func getGameService(
 	}
 }
 
-func (g *gameService) Get(id model.GameID) (model.Game, error) {
-	r := g.db.QueryRow(queryLatestGame, id)
-	return g.populateGameFromRow(id, r)
+func (g *gameService) Get(ctx context.Context, id model.GameID) (model.Game, error) {
+	r := g.db.QueryRowContext(ctx, queryLatestGame, id)
+	return g.populateGameFromRow(ctx, id, r)
 }
 
-func (g *gameService) GetAt(id model.GameID, numActions uint) (model.Game, error) {
-	r := g.db.QueryRow(queryGameAtNumActions, id, numActions)
-	return g.populateGameFromRow(id, r)
+func (g *gameService) GetAt(ctx context.Context, id model.GameID, numActions uint) (model.Game, error) {
+	r := g.db.QueryRowContext(ctx, queryGameAtNumActions, id, numActions)
+	return g.populateGameFromRow(ctx, id, r)
 }
 
 func (g *gameService) populateGameFromRow(
+	ctx context.Context,
 	gID model.GameID,
 	r *sql.Row,
 ) (model.Game, error) {
@@ -174,16 +285,18 @@ func (g *gameService) populateGameFromRow(
 	var phase model.Phase
 	var cribCardInts int32
 	var cutCardInt int8
-	var blockingPlayers, hands, peggedCards, action []byte
+	var blockingPlayers, hands, deckOrder, shuffles, peggedCards, pegging, action []byte
+	var settings, outcome []byte
 	var numActions uint32
 	err := r.Scan(
 		&p1ID, &p2ID, &p3ID, &p4ID,
 		&scoreBlue, &scoreRed, &scoreGreen,
 		&lagScoreBlue, &lagScoreRed, &lagScoreGreen,
 		&phase, &blockingPlayers, &curDealerID,
-		&hands, &cribCardInts, &cutCardInt,
-		&peggedCards,
+		&hands, &cribCardInts, &deckOrder, &shuffles, &cutCardInt,
+		&peggedCards, &pegging,
 		&numActions, &action,
+		&settings, &outcome,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -192,6 +305,11 @@ func (g *gameService) populateGameFromRow(
 		return model.Game{}, err
 	}
 
+	if hands == nil {
+		// a nil hands column means this snapshot has been compacted
+		return model.Game{}, persistence.ErrGameSnapshotCompacted
+	}
+
 	curScores, lagScores := populateScores(
 		scoreBlue, scoreRed, scoreGreen,
 		lagScoreBlue, lagScoreRed, lagScoreGreen,
@@ -202,7 +320,7 @@ func (g *gameService) populateGameFromRow(
 		return model.Game{}, err
 	}
 
-	pc, err := g.getPlayerColors(gID)
+	pc, err := g.getPlayerColors(ctx, gID)
 	if err != nil {
 		return model.Game{}, err
 	}
@@ -217,6 +335,16 @@ func (g *gameService) populateGameFromRow(
 
 	cribCards := getCribCards(cribCardInts)
 
+	deck, err := getDeckOrder(deckOrder)
+	if err != nil {
+		return model.Game{}, err
+	}
+
+	sps, err := getShuffles(shuffles)
+	if err != nil {
+		return model.Game{}, err
+	}
+
 	bp, err := getBlockingPlayers(blockingPlayers)
 	if err != nil {
 		return model.Game{}, err
@@ -232,7 +360,22 @@ func (g *gameService) populateGameFromRow(
 		return model.Game{}, err
 	}
 
-	pas, err := g.getActions(gID, int(numActions))
+	ps, err := getPegging(pegging)
+	if err != nil {
+		return model.Game{}, err
+	}
+
+	pas, err := g.getActions(ctx, gID, int(numActions))
+	if err != nil {
+		return model.Game{}, err
+	}
+
+	set, err := getSettings(settings)
+	if err != nil {
+		return model.Game{}, err
+	}
+
+	out, err := getOutcome(outcome)
 	if err != nil {
 		return model.Game{}, err
 	}
@@ -247,10 +390,15 @@ func (g *gameService) populateGameFromRow(
 		CurrentDealer:   curDealerID,
 		CutCard:         cutCard,
 		Crib:            cribCards,
+		DeckOrder:       deck,
+		Shuffles:        sps,
 		BlockingPlayers: bp,
 		Hands:           h,
 		PeggedCards:     p,
+		Pegging:         ps,
 		Actions:         pas,
+		Settings:        set,
+		Outcome:         out,
 	}
 
 	return game, nil
@@ -321,6 +469,35 @@ func serializeCribCards(crib []model.Card) int32 {
 	return val
 }
 
+func getDeckOrder(ser []byte) ([]model.Card, error) {
+	if len(ser) == 0 {
+		// games from before we kept the deck order don't have one
+		return nil, nil
+	}
+
+	deck := make([]model.Card, len(ser))
+	for i, b := range ser {
+		c, err := model.NewCardFromTinyInt(int8(b))
+		if err != nil {
+			return nil, err
+		}
+		deck[i] = c
+	}
+	return deck, nil
+}
+
+func serializeDeckOrder(deck []model.Card) []byte {
+	if len(deck) == 0 {
+		return nil
+	}
+
+	ser := make([]byte, len(deck))
+	for i, c := range deck {
+		ser[i] = byte(c.ToTinyInt())
+	}
+	return ser
+}
+
 func getBlockingPlayers(ser []byte) (map[model.PlayerID]model.Blocker, error) {
 	blockers := map[model.PlayerID]model.Blocker{}
 
@@ -396,13 +573,14 @@ func (g *gameService) getPlayersForGame(
 }
 
 func (g *gameService) getPlayerColors(
+	ctx context.Context,
 	gID model.GameID,
 ) (map[model.PlayerID]model.PlayerColor, error) {
 
 	// populate pc with the colors for each player
 	pc := make(map[model.PlayerID]model.PlayerColor, 4)
 
-	rows, err := g.db.Query(getPlayerColorsForGame, gID)
+	rows, err := g.db.QueryContext(ctx, getPlayerColorsForGame, gID)
 	if err != nil {
 		return nil, err
 	}
@@ -425,11 +603,12 @@ func (g *gameService) getPlayerColors(
 }
 
 func (g *gameService) getActions(
+	ctx context.Context,
 	gID model.GameID,
 	maxNumActions int,
 ) ([]model.PlayerAction, error) {
 
-	rows, err := g.db.Query(queryPlayerActionsBefore, gID, maxNumActions)
+	rows, err := g.db.QueryContext(ctx, queryPlayerActionsBefore, gID, maxNumActions)
 	if err != nil {
 		return nil, err
 	}
@@ -480,23 +659,182 @@ func (g *gameService) getActions(
 	return pas, nil
 }
 
+func getSettings(ser []byte) (model.GameSettings, error) {
+	settings := model.GameSettings{}
+	if ser == nil {
+		// games created before we had settings have none
+		return settings, nil
+	}
+
+	err := json.Unmarshal(ser, &settings)
+	if err != nil {
+		return model.GameSettings{}, err
+	}
+
+	return settings, nil
+}
+
+func serializeSettings(input model.GameSettings) ([]byte, error) {
+	return json.Marshal(input)
+}
+
+func getOutcome(ser []byte) (model.GameOutcome, error) {
+	outcome := model.GameOutcome{}
+	if ser == nil {
+		return outcome, nil
+	}
+
+	err := json.Unmarshal(ser, &outcome)
+	if err != nil {
+		return model.GameOutcome{}, err
+	}
+
+	return outcome, nil
+}
+
+func serializeOutcome(input model.GameOutcome) ([]byte, error) {
+	return json.Marshal(input)
+}
+
+func getPegging(ser []byte) (model.PeggingState, error) {
+	ps := model.PeggingState{}
+	if ser == nil {
+		return ps, nil
+	}
+
+	err := json.Unmarshal(ser, &ps)
+	if err != nil {
+		return model.PeggingState{}, err
+	}
+
+	return ps, nil
+}
+
+func serializePegging(input model.PeggingState) ([]byte, error) {
+	return json.Marshal(input)
+}
+
+func getShuffles(ser []byte) ([]model.ShuffleProof, error) {
+	if ser == nil {
+		return nil, nil
+	}
+
+	var sps []model.ShuffleProof
+	err := json.Unmarshal(ser, &sps)
+	if err != nil {
+		return nil, err
+	}
+
+	return sps, nil
+}
+
+func serializeShuffles(input []model.ShuffleProof) ([]byte, error) {
+	if len(input) == 0 {
+		// only provably fair games have shuffle proofs
+		return nil, nil
+	}
+	return json.Marshal(input)
+}
+
 func getPlayerAction(ser []byte) (model.PlayerAction, error) {
 	return jsonutils.UnmarshalPlayerAction(ser)
 }
 
 func serializePlayerAction(input model.PlayerAction) ([]byte, error) {
-	// Remember: this is complemented by jsonutils.UnmarshalPlayerAction
-	// because we have to unmarshal into an interface
+	// the action is stored in its versioned envelope, so the blobs
+	// saved before the envelope existed still decode
 	return json.Marshal(input)
 }
 
-func (g *gameService) UpdatePlayerColor(id model.GameID, pID model.PlayerID, color model.PlayerColor) error {
+func (g *gameService) UpdatePlayerColor(
+	ctx context.Context,
+	id model.GameID,
+	pID model.PlayerID,
+	color model.PlayerColor,
+) error {
 	// There should be nothing to do here because the player service should take care
 	// of all of the persistence that needs to happen
 	return nil
 }
 
-func (g *gameService) Begin(mg model.Game) error {
+func (g *gameService) Compact(ctx context.Context, id model.GameID, numActions []uint) error {
+	for _, na := range numActions {
+		_, err := g.db.ExecContext(ctx, compactGameAt, id, na)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := g.db.ExecContext(ctx, markGameCompacted, id)
+	return err
+}
+
+func (g *gameService) GetUncompacted(ctx context.Context) ([]model.GameID, error) {
+	rows, err := g.db.QueryContext(ctx, queryUncompactedGames)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanGameIDs(rows)
+}
+
+func (g *gameService) CountActive(ctx context.Context) (int, error) {
+	var n int
+	err := g.db.QueryRowContext(ctx, queryActiveGamesCount).Scan(&n)
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (g *gameService) SetMoveDeadline(ctx context.Context, id model.GameID, deadline time.Time) error {
+	// the zero time is saved as NULL, which is never overdue
+	_, err := g.db.ExecContext(ctx, setGameMoveDeadline, id, sql.NullTime{
+		Time:  deadline,
+		Valid: !deadline.IsZero(),
+	})
+	return err
+}
+
+func (g *gameService) GetMoveDeadline(ctx context.Context, id model.GameID) (time.Time, error) {
+	// the games without a status, or with a NULL deadline, don't have one
+	var deadline sql.NullTime
+	err := g.db.QueryRowContext(ctx, queryGameMoveDeadline, id).Scan(&deadline)
+	if err != nil && err != sql.ErrNoRows {
+		return time.Time{}, err
+	}
+	return deadline.Time, nil
+}
+
+func (g *gameService) GetOverdue(ctx context.Context, now time.Time) ([]model.GameID, error) {
+	rows, err := g.db.QueryContext(ctx, queryOverdueGames, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanGameIDs(rows)
+}
+
+func scanGameIDs(rows *sql.Rows) ([]model.GameID, error) {
+	defer rows.Close()
+
+	var gIDs []model.GameID
+	for rows.Next() {
+		var gID model.GameID
+		err := rows.Scan(&gID)
+		if err != nil {
+			return nil, err
+		}
+		gIDs = append(gIDs, gID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return gIDs, nil
+}
+
+func (g *gameService) Begin(ctx context.Context, mg model.Game) error {
 	ifs := []interface{}{
 		mg.ID,
 	}
@@ -513,15 +851,21 @@ func (g *gameService) Begin(mg model.Game) error {
 		ifs = append(ifs, nil)
 	}
 
-	_, err := g.db.Exec(addPlayersToGamePlayers, ifs...)
+	set, err := serializeSettings(mg.Settings)
+	if err != nil {
+		return err
+	}
+	ifs = append(ifs, set)
+
+	_, err = g.db.ExecContext(ctx, addPlayersToGamePlayers, ifs...)
 	if err != nil {
 		return err
 	}
 
-	return g.Save(mg)
+	return g.Save(ctx, mg)
 }
 
-func (g *gameService) Save(mg model.Game) error {
+func (g *gameService) Save(ctx context.Context, mg model.Game) error {
 	if mg.ID > maxGameID {
 		return persistence.ErrInvalidGameID
 	}
@@ -536,7 +880,12 @@ func (g *gameService) Save(mg model.Game) error {
 
 	cut := mg.CutCard.ToTinyInt()
 	crib := serializeCribCards(mg.Crib)
+	deck := serializeDeckOrder(mg.DeckOrder)
 
+	sps, err := serializeShuffles(mg.Shuffles)
+	if err != nil {
+		return err
+	}
 	bp, err := serializeBlockingPlayers(mg.BlockingPlayers)
 	if err != nil {
 		return err
@@ -549,6 +898,10 @@ func (g *gameService) Save(mg model.Game) error {
 	if err != nil {
 		return err
 	}
+	ps, err := serializePegging(mg.Pegging)
+	if err != nil {
+		return err
+	}
 	var a []byte
 	if ai := mg.NumActions() - 1; ai >= 0 {
 		// get the last action in the slice of actions. Serialize it for saving
@@ -558,18 +911,32 @@ func (g *gameService) Save(mg model.Game) error {
 		}
 	}
 
+	out, err := serializeOutcome(mg.Outcome)
+	if err != nil {
+		return err
+	}
+
 	ifs := []interface{}{
 		mg.ID, mg.NumActions(),
 		uint8(mg.CurrentScores[model.Blue]), uint8(mg.CurrentScores[model.Red]), uint8(mg.CurrentScores[model.Green]),
 		uint8(mg.LagScores[model.Blue]), uint8(mg.LagScores[model.Red]), uint8(mg.LagScores[model.Green]),
-		mg.Phase, cut, crib,
+		mg.Phase, cut, crib, deck, sps,
 		mg.CurrentDealer,
-		bp, h, pegged, a,
+		bp, h, pegged, ps, a,
+		out,
 	}
-	_, err = g.db.Exec(insertGameAt, ifs...)
+	_, err = g.db.ExecContext(ctx, insertGameAt, ifs...)
 	if err != nil {
 		return err
 	}
 
+	if mg.IsOver() {
+		// so that the compactor can find it
+		_, err = g.db.ExecContext(ctx, markGameFinished, mg.ID)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
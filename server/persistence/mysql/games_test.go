@@ -101,6 +101,25 @@ func TestSerializeAndDeserializeAllTheThings(t *testing.T) {
 	}
 	assert.Equal(t, cribCopy, getCribCards(serializeCribCards(g.Crib)))
 
+	deck := model.NewShuffledDeckOrder(3)
+	actDeck, err := getDeckOrder(serializeDeckOrder(deck))
+	require.NoError(t, err)
+	assert.Equal(t, deck, actDeck)
+	actDeck, err = getDeckOrder(serializeDeckOrder(nil))
+	require.NoError(t, err)
+	assert.Empty(t, actDeck)
+
+	sps := []model.ShuffleProof{model.NewShuffleProof()}
+	sps[0].ClientEntropy = `lucky`
+	serSps, err := serializeShuffles(sps)
+	require.NoError(t, err)
+	actSps, err := getShuffles(serSps)
+	require.NoError(t, err)
+	assert.Equal(t, sps, actSps)
+	serSps, err = serializeShuffles(nil)
+	require.NoError(t, err)
+	assert.Nil(t, serSps)
+
 	bpCpy := make(map[model.PlayerID]model.Blocker, len(g.BlockingPlayers))
 	for k, v := range g.BlockingPlayers {
 		bpCpy[k] = v
@@ -1,6 +1,7 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/joshprzybyszewski/cribbage/model"
@@ -18,7 +19,7 @@ const (
 	) ENGINE = INNODB;`
 
 	getPreferredPlayerMeans = `SELECT
-		PreferredInteractionMode
+		PreferredInteractionMode, AutoPlay
 	FROM Players
 		WHERE PlayerID = ?
 	;`
@@ -40,6 +41,7 @@ const (
 	VALUES
 		(?, ?, ?)
 	ON DUPLICATE KEY UPDATE
+		Mode = ?,
 		Means = ?
 	;`
 )
@@ -65,15 +67,18 @@ func getInteractionService(
 	}
 }
 
-func (s *interactionService) Get(id model.PlayerID) (interaction.PlayerMeans, error) {
+func (s *interactionService) Get(ctx context.Context, id model.PlayerID) (interaction.PlayerMeans, error) {
 	result := interaction.PlayerMeans{
 		PlayerID: id,
 	}
 
-	r := s.db.QueryRow(getPreferredPlayerMeans, id)
+	r := s.db.QueryRowContext(ctx, getPreferredPlayerMeans, id)
 	var preference int
+	// players from before we had auto play have a NULL
+	var autoPlay sql.NullBool
 	err := r.Scan(
 		&preference,
+		&autoPlay,
 	)
 	if err != nil {
 		if err != sql.ErrNoRows {
@@ -84,8 +89,9 @@ func (s *interactionService) Get(id model.PlayerID) (interaction.PlayerMeans, er
 		preference = int(interaction.Unknown)
 	}
 	result.PreferredMode = interaction.Mode(preference)
+	result.AutoPlay = autoPlay.Bool
 
-	rows, err := s.db.Query(getPlayerMeans, id)
+	rows, err := s.db.QueryContext(ctx, getPlayerMeans, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return interaction.PlayerMeans{}, persistence.ErrInteractionNotFound
@@ -117,7 +123,7 @@ func (s *interactionService) Get(id model.PlayerID) (interaction.PlayerMeans, er
 	return result, nil
 }
 
-func (s *interactionService) Create(pm interaction.PlayerMeans) error {
+func (s *interactionService) Create(ctx context.Context, pm interaction.PlayerMeans) error {
 	var serMeans []byte
 	var err error
 	for _, means := range pm.Interactions {
@@ -125,7 +131,8 @@ func (s *interactionService) Create(pm interaction.PlayerMeans) error {
 		if err != nil {
 			return err
 		}
-		_, err = s.db.Exec(
+		_, err = s.db.ExecContext(
+			ctx,
 			createPlayerMeans,
 			pm.PlayerID,
 			means.Mode,
@@ -139,10 +146,10 @@ func (s *interactionService) Create(pm interaction.PlayerMeans) error {
 			return err
 		}
 	}
-	return s.updatePlayerPreferredMode(pm)
+	return s.updatePlayerPreferredMode(ctx, pm)
 }
 
-func (s *interactionService) Update(pm interaction.PlayerMeans) error {
+func (s *interactionService) Update(ctx context.Context, pm interaction.PlayerMeans) error {
 	var serMeans []byte
 	var err error
 	for _, means := range pm.Interactions {
@@ -150,11 +157,13 @@ func (s *interactionService) Update(pm interaction.PlayerMeans) error {
 		if err != nil {
 			return err
 		}
-		_, err = s.db.Exec(
+		_, err = s.db.ExecContext(
+			ctx,
 			updatePlayerMeans,
 			pm.PlayerID,
 			means.Mode,
 			serMeans,
+			means.Mode,
 			serMeans,
 		)
 		err = convertMysqlError(err)
@@ -163,15 +172,27 @@ func (s *interactionService) Update(pm interaction.PlayerMeans) error {
 		}
 	}
 
-	return s.updatePlayerPreferredMode(pm)
+	return s.updatePlayerPreferredMode(ctx, pm)
 }
 
-func (s *interactionService) updatePlayerPreferredMode(pm interaction.PlayerMeans) error {
+func (s *interactionService) updatePlayerPreferredMode(ctx context.Context, pm interaction.PlayerMeans) error {
+	_, err := s.db.ExecContext(
+		ctx,
+		updateAutoPlay,
+		pm.AutoPlay,
+		pm.PlayerID,
+	)
+	err = convertMysqlError(err)
+	if err != nil {
+		return err
+	}
+
 	switch preferred := pm.PreferredMode; preferred {
 	case interaction.Unknown, interaction.UnsetMode:
 		// do nothing
 	default:
-		_, err := s.db.Exec(
+		_, err = s.db.ExecContext(
+			ctx,
 			updatePreferredInteractionMode,
 			preferred,
 			pm.PlayerID,
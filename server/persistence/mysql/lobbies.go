@@ -0,0 +1,178 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+const (
+	// Lobbies stores the players waiting for a game to start.
+	// The columns act as follows:
+	// LobbyID is a UUID to identify a lobby
+	// Status is the model.LobbyStatus, which we query for open lobbies
+	// Created is when the lobby was opened, so that the oldest lobbies get filled first
+	// Lobby is the json encoded model.Lobby
+	// Version is the model.Lobby's Version, which we only update from the version we read
+	createLobbiesTable = `CREATE TABLE IF NOT EXISTS Lobbies (
+		LobbyID INT UNSIGNED,
+		Status TINYINT UNSIGNED,
+		Created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		Lobby BLOB,
+		Version BIGINT UNSIGNED DEFAULT 0,
+		PRIMARY KEY (LobbyID),
+		INDEX (Status)
+	) ENGINE = INNODB;`
+
+	getLobby = `SELECT
+		Lobby
+	FROM Lobbies
+	WHERE LobbyID = ?
+	;`
+
+	getLobbiesWithStatus = `SELECT
+		Lobby
+	FROM Lobbies
+	WHERE Status = ?
+	ORDER BY
+		Created ASC
+	;`
+
+	createLobby = `INSERT INTO Lobbies
+		(LobbyID, Status, Created, Lobby, Version)
+	VALUES
+		(?, ?, ?, ?, ?)
+	;`
+
+	updateLobby = `UPDATE Lobbies
+	SET
+		Status = ?,
+		Lobby = ?,
+		Version = ?
+	WHERE
+		LobbyID = ? AND
+		Version = ?
+	;`
+)
+
+var (
+	lobbiesCreateStmts = []string{
+		createLobbiesTable,
+	}
+)
+
+var _ persistence.LobbyService = (*lobbyService)(nil)
+
+type lobbyService struct {
+	db *txWrapper
+}
+
+func getLobbyService(
+	db *txWrapper,
+) persistence.LobbyService {
+
+	return &lobbyService{
+		db: db,
+	}
+}
+
+func (s *lobbyService) Get(ctx context.Context, id model.LobbyID) (model.Lobby, error) {
+	var ser []byte
+	err := s.db.QueryRowContext(ctx, getLobby, id).Scan(&ser)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.Lobby{}, persistence.ErrLobbyNotFound
+		}
+		return model.Lobby{}, err
+	}
+
+	return getLobbyFromBytes(ser)
+}
+
+func (s *lobbyService) GetOpen(ctx context.Context) ([]model.Lobby, error) {
+	rows, err := s.db.QueryContext(ctx, getLobbiesWithStatus, model.LobbyOpen)
+	if err != nil {
+		return nil, err
+	}
+
+	var lobbies []model.Lobby
+	var ser []byte
+	var l model.Lobby
+	for rows.Next() {
+		err = rows.Scan(&ser)
+		if err != nil {
+			return nil, err
+		}
+
+		l, err = getLobbyFromBytes(ser)
+		if err != nil {
+			return nil, err
+		}
+		lobbies = append(lobbies, l)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return lobbies, nil
+}
+
+func (s *lobbyService) Create(ctx context.Context, l model.Lobby) error {
+	ser, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, createLobby, l.ID, l.Status, l.Created, ser, l.Version)
+	err = convertMysqlError(err)
+	if err != nil {
+		if err == errDuplicateEntry {
+			return persistence.ErrLobbyAlreadyExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *lobbyService) Update(ctx context.Context, l model.Lobby) error {
+	prevVersion := l.Version
+	l.Version++
+	ser, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+
+	res, err := s.db.ExecContext(ctx, updateLobby, l.Status, ser, l.Version, l.ID, prevVersion)
+	err = convertMysqlError(err)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return nil
+	}
+
+	// either the lobby doesn't exist, or somebody else saved it first
+	_, err = s.Get(ctx, l.ID)
+	if err != nil {
+		return err
+	}
+	return persistence.ErrLobbyConflict
+}
+
+func getLobbyFromBytes(ser []byte) (model.Lobby, error) {
+	l := model.Lobby{}
+	err := json.Unmarshal(ser, &l)
+	if err != nil {
+		return model.Lobby{}, err
+	}
+	return l, nil
+}
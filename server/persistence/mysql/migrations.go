@@ -0,0 +1,104 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"go.uber.org/zap"
+
+	"github.com/joshprzybyszewski/cribbage/server/logging"
+)
+
+const (
+	// MySQL 8 doesn't have ADD COLUMN IF NOT EXISTS, so we look for the column first
+	queryColumnExists = `SELECT
+		COUNT(*)
+	FROM information_schema.COLUMNS
+	WHERE TABLE_SCHEMA = DATABASE() AND
+		TABLE_NAME = ? AND
+		COLUMN_NAME = ?
+	;`
+)
+
+// addedColumn is a column that was added to a table after it was first created.
+// CREATE TABLE IF NOT EXISTS leaves the existing tables alone, so these need to
+// be added to the tables that were created before them.
+type addedColumn struct {
+	table      string
+	column     string
+	definition string
+}
+
+var (
+	// addedColumns should be kept in the order they were added, and every one
+	// should also be in its table's CREATE statement
+	addedColumns = []addedColumn{{
+		table:      `Games`,
+		column:     `DeckOrder`,
+		definition: `BLOB`,
+	}, {
+		table:      `Games`,
+		column:     `Shuffles`,
+		definition: `BLOB`,
+	}, {
+		table:      `Games`,
+		column:     `Pegging`,
+		definition: `BLOB`,
+	}, {
+		table:      `Games`,
+		column:     `Outcome`,
+		definition: `BLOB`,
+	}, {
+		table:      `GamePlayers`,
+		column:     `Settings`,
+		definition: `BLOB`,
+	}, {
+		table:      `Players`,
+		column:     `AutoPlay`,
+		definition: `BOOL DEFAULT FALSE`,
+	}, {
+		table:      `GameStatuses`,
+		column:     `MoveDeadline`,
+		definition: `TIMESTAMP(6) NULL DEFAULT NULL`,
+	}, {
+		table:      `Lobbies`,
+		column:     `Version`,
+		definition: `BIGINT UNSIGNED DEFAULT 0`,
+	}}
+)
+
+func (ac addedColumn) alterStmt() string {
+	return `ALTER TABLE ` + ac.table + ` ADD COLUMN ` + ac.column + ` ` + ac.definition + `;`
+}
+
+// runMigrations adds the columns that are missing from the existing tables. It
+// is safe to run every time we start up.
+func runMigrations(ctx context.Context, db *sql.DB, config Config) error {
+	for _, ac := range addedColumns {
+		var numFound int
+		err := db.QueryRowContext(ctx, queryColumnExists, ac.table, ac.column).Scan(&numFound)
+		if err == nil && numFound > 0 {
+			continue
+		}
+		if err == nil {
+			_, err = db.ExecContext(ctx, ac.alterStmt())
+		}
+		if err != nil {
+			if config.CreateErrorIsOk {
+				logging.L().Warn(`error adding column`,
+					zap.String(`table`, ac.table),
+					zap.String(`column`, ac.column),
+					zap.Error(err),
+				)
+				continue
+			}
+			return err
+		}
+		logging.L().Info(`added column`,
+			zap.String(`table`, ac.table),
+			zap.String(`column`, ac.column),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package mysql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddedColumnsAreInTheirTables(t *testing.T) {
+	allCreateStmts := make([]string, 0)
+	allCreateStmts = append(allCreateStmts, gamesCreateStmts...)
+	allCreateStmts = append(allCreateStmts, playersCreateStmts...)
+	allCreateStmts = append(allCreateStmts, lobbiesCreateStmts...)
+
+	for _, ac := range addedColumns {
+		found := false
+		for _, stmt := range allCreateStmts {
+			if !strings.Contains(stmt, `CREATE TABLE IF NOT EXISTS `+ac.table+` (`) {
+				continue
+			}
+			found = true
+			assert.Contains(t, stmt, "\t"+ac.column+` `+ac.definition+",\n", ac.column)
+		}
+		assert.True(t, found, `missing the CREATE for %s`, ac.table)
+	}
+}
+
+func TestAddedColumnAlterStmt(t *testing.T) {
+	ac := addedColumn{
+		table:      `Players`,
+		column:     `AutoPlay`,
+		definition: `BOOL DEFAULT FALSE`,
+	}
+	assert.Equal(t, `ALTER TABLE Players ADD COLUMN AutoPlay BOOL DEFAULT FALSE;`, ac.alterStmt())
+}
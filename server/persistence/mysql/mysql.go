@@ -5,9 +5,11 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"log"
 
 	_ "github.com/go-sql-driver/mysql" // nolint:golint
+	"go.uber.org/zap"
+
+	"github.com/joshprzybyszewski/cribbage/server/logging"
 	"github.com/joshprzybyszewski/cribbage/server/persistence"
 )
 
@@ -15,9 +17,13 @@ var _ persistence.DBFactory = (*mysqlDBFactory)(nil)
 
 type mysqlDBFactory struct {
 	db *sql.DB
+
+	replayer persistence.Replayer
 }
 
-func NewFactory(ctx context.Context, config Config) (persistence.DBFactory, error) {
+// NewFactory connects to the database in the config. The replayer rebuilds the
+// snapshots of games which have been compacted.
+func NewFactory(ctx context.Context, config Config, r persistence.Replayer) (persistence.DBFactory, error) {
 	dsn := fmt.Sprintf(`%s:%s@tcp(%s:%d)`,
 		config.DSNUser,
 		config.DSNPassword,
@@ -36,33 +42,51 @@ func NewFactory(ctx context.Context, config Config) (persistence.DBFactory, erro
 	}
 
 	if config.RunCreateStmts {
-		allCreateStmts := make([]string, 0, len(gamesCreateStmts)+len(playersCreateStmts)+len(interactionCreateStmts))
+		allCreateStmts := make([]string, 0,
+			len(gamesCreateStmts)+len(playersCreateStmts)+len(interactionCreateStmts)+
+				len(lobbiesCreateStmts)+len(spectatorsCreateStmts)+len(chatsCreateStmts)+
+				len(npcMovesCreateStmts),
+		)
 		allCreateStmts = append(allCreateStmts, gamesCreateStmts...)
 		allCreateStmts = append(allCreateStmts, playersCreateStmts...)
 		allCreateStmts = append(allCreateStmts, interactionCreateStmts...)
+		allCreateStmts = append(allCreateStmts, lobbiesCreateStmts...)
+		allCreateStmts = append(allCreateStmts, spectatorsCreateStmts...)
+		allCreateStmts = append(allCreateStmts, chatsCreateStmts...)
+		allCreateStmts = append(allCreateStmts, npcMovesCreateStmts...)
 
 		for _, createStmt := range allCreateStmts {
 			_, err := db.ExecContext(ctx, createStmt)
 			if err != nil {
 				if config.CreateErrorIsOk {
-					log.Printf("error running CREATE: %q = %v", createStmt, err)
+					logging.L().Warn(`error running CREATE`, zap.String(`stmt`, createStmt), zap.Error(err))
 					continue
 				}
 				return nil, err
 			}
 		}
+
+		err = runMigrations(ctx, db, config)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return &mysqlDBFactory{
-		db: db,
+		db:       db,
+		replayer: r,
 	}, nil
 }
 
+func (dbf *mysqlDBFactory) Ping(ctx context.Context) error {
+	return dbf.db.PingContext(ctx)
+}
+
 func (dbf *mysqlDBFactory) Close() error {
 	return dbf.db.Close()
 }
 
-func (dbf *mysqlDBFactory) New(ctx context.Context) (persistence.DB, error) {
+func (dbf *mysqlDBFactory) New(context.Context) (persistence.DB, error) {
 	dbWrapper := txWrapper{
 		db: dbf.db,
 	}
@@ -71,12 +95,16 @@ func (dbf *mysqlDBFactory) New(ctx context.Context) (persistence.DB, error) {
 		getGameService(&dbWrapper),
 		getPlayerService(&dbWrapper),
 		getInteractionService(&dbWrapper),
+		getLobbyService(&dbWrapper),
+		getSpectatorService(&dbWrapper),
+		getChatService(&dbWrapper),
+		getNPCMoveService(&dbWrapper),
+		dbf.replayer,
 	)
 
 	mw := mysqlWrapper{
 		ServicesWrapper: sw,
 		txWrapper:       &dbWrapper,
-		ctx:             ctx,
 	}
 
 	return &mw, nil
@@ -101,8 +129,6 @@ type mysqlWrapper struct {
 	persistence.ServicesWrapper
 
 	txWrapper *txWrapper
-
-	ctx context.Context
 }
 
 func (mw *mysqlWrapper) Close() error {
@@ -112,8 +138,9 @@ func (mw *mysqlWrapper) Close() error {
 	return nil
 }
 
-func (mw *mysqlWrapper) Start() error {
-	return mw.txWrapper.start(mw.ctx)
+func (mw *mysqlWrapper) Start(ctx context.Context) error {
+	// the transaction is rolled back by the driver if ctx is done before it commits
+	return mw.txWrapper.start(ctx)
 }
 
 func (mw *mysqlWrapper) Commit() error {
@@ -0,0 +1,168 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/joshprzybyszewski/cribbage/server/npcqueue"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+const (
+	// NPCMoves stores the moves that the NPCs have decided on, but haven't made yet.
+	// The columns act as follows:
+	// GameID is the game that the move is for
+	// PlayerID is the NPC making the move
+	// NumActions is how many actions the game had when the NPC decided on the move
+	// Queued is when the move was queued, so that they're made in order
+	// NextAttempt is when the move should next be tried
+	// Job is the JSON of the npcqueue.Job, which has the action and its failures
+	createNPCMovesTable = `CREATE TABLE IF NOT EXISTS NPCMoves (
+		GameID INT UNSIGNED,
+		PlayerID VARCHAR(` + maxPlayerUUIDLenStr + `) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_as_cs,
+		NumActions INT UNSIGNED,
+		Queued TIMESTAMP(6),
+		NextAttempt TIMESTAMP(6),
+		Job BLOB,
+		PRIMARY KEY (GameID, PlayerID),
+		INDEX (NextAttempt)
+	) ENGINE = INNODB;`
+
+	// an NPC has one move for each game, so this only replaces the move that is
+	// there when the new one is for a later state of the game. NumActions has to
+	// be updated last, because the other columns compare against it.
+	addNPCMove = `INSERT INTO NPCMoves
+		(GameID, PlayerID, NumActions, Queued, NextAttempt, Job)
+	VALUES
+		(?, ?, ?, ?, ?, ?)
+	ON DUPLICATE KEY UPDATE
+		Queued = IF(VALUES(NumActions) > NumActions, VALUES(Queued), Queued),
+		NextAttempt = IF(VALUES(NumActions) > NumActions, VALUES(NextAttempt), NextAttempt),
+		Job = IF(VALUES(NumActions) > NumActions, VALUES(Job), Job),
+		NumActions = GREATEST(NumActions, VALUES(NumActions))
+	;`
+
+	getDueNPCMoves = `SELECT
+		Job
+	FROM NPCMoves
+	WHERE NextAttempt <= ?
+	ORDER BY
+		Queued ASC,
+		GameID ASC,
+		PlayerID ASC
+	;`
+
+	updateNPCMove = `UPDATE NPCMoves
+	SET
+		NextAttempt = ?,
+		Job = ?
+	WHERE GameID = ? AND
+		PlayerID = ? AND
+		NumActions = ?
+	;`
+
+	removeNPCMove = `DELETE FROM NPCMoves
+	WHERE GameID = ? AND
+		PlayerID = ? AND
+		NumActions = ?
+	;`
+)
+
+var (
+	npcMovesCreateStmts = []string{
+		createNPCMovesTable,
+	}
+)
+
+var _ persistence.NPCMoveService = (*npcMoveService)(nil)
+
+type npcMoveService struct {
+	db *txWrapper
+}
+
+func getNPCMoveService(
+	db *txWrapper,
+) persistence.NPCMoveService {
+
+	return &npcMoveService{
+		db: db,
+	}
+}
+
+func (ns *npcMoveService) Add(ctx context.Context, j npcqueue.Job) (bool, error) {
+	if len(j.PlayerID) > maxPlayerUUIDLen {
+		return false, persistence.ErrInvalidPlayerID
+	}
+
+	job, err := json.Marshal(j)
+	if err != nil {
+		return false, err
+	}
+
+	res, err := ns.db.ExecContext(ctx, addNPCMove,
+		j.GameID, j.PlayerID, j.NumActions, j.Queued, j.NextAttempt, job,
+	)
+	if err != nil {
+		return false, convertMysqlError(err)
+	}
+
+	// this is 1 for an insert, 2 for an update, and 0 when the move that's there was kept
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (ns *npcMoveService) GetDue(ctx context.Context, now time.Time) ([]npcqueue.Job, error) {
+	rows, err := ns.db.QueryContext(ctx, getDueNPCMoves, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanNPCMoves(rows)
+}
+
+func scanNPCMoves(rows *sql.Rows) ([]npcqueue.Job, error) {
+	defer rows.Close()
+
+	var jobs []npcqueue.Job
+	for rows.Next() {
+		var job []byte
+		err := rows.Scan(&job)
+		if err != nil {
+			return nil, err
+		}
+
+		var j npcqueue.Job
+		err = json.Unmarshal(job, &j)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, j)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+func (ns *npcMoveService) Update(ctx context.Context, j npcqueue.Job) error {
+	job, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+
+	_, err = ns.db.ExecContext(ctx, updateNPCMove,
+		j.NextAttempt, job, j.GameID, j.PlayerID, j.NumActions,
+	)
+	return convertMysqlError(err)
+}
+
+func (ns *npcMoveService) Remove(ctx context.Context, k npcqueue.Key) error {
+	_, err := ns.db.ExecContext(ctx, removeNPCMove, k.GameID, k.PlayerID, k.NumActions)
+	return convertMysqlError(err)
+}
@@ -1,6 +1,7 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/joshprzybyszewski/cribbage/model"
@@ -10,10 +11,12 @@ import (
 const (
 	// Players stores info about Players that we need to keep.
 	// The default PreferredInteractionMode should be equal to int(interaction.UnsetMode)
+	// AutoPlay is whether the server plays the player's forced moves for them
 	createPlayersTable = `CREATE TABLE IF NOT EXISTS Players (
 		PlayerID VARCHAR(` + maxPlayerUUIDLenStr + `) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_as_cs,
 		Name VARCHAR(` + maxPlayerNameLenStr + `) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_as_cs,
 		PreferredInteractionMode INT DEFAULT 0,
+		AutoPlay BOOL DEFAULT FALSE,
 		PRIMARY KEY (PlayerID)
 	) ENGINE = INNODB;`
 
@@ -70,6 +73,13 @@ const (
 	WHERE
 		PlayerID = ?
 	;`
+
+	updateAutoPlay = `UPDATE Players
+	SET
+		AutoPlay = ?
+	WHERE
+		PlayerID = ?
+	;`
 )
 
 var (
@@ -94,8 +104,8 @@ func getPlayerService(
 	}
 }
 
-func (ps *playerService) Get(id model.PlayerID) (model.Player, error) {
-	r := ps.db.QueryRow(getPlayerName, id)
+func (ps *playerService) Get(ctx context.Context, id model.PlayerID) (model.Player, error) {
+	r := ps.db.QueryRowContext(ctx, getPlayerName, id)
 	var name string
 	err := r.Scan(
 		&name,
@@ -107,7 +117,7 @@ func (ps *playerService) Get(id model.PlayerID) (model.Player, error) {
 		return model.Player{}, err
 	}
 
-	rows, err := ps.db.Query(getPlayerGames, id)
+	rows, err := ps.db.QueryContext(ctx, getPlayerGames, id)
 	if err != nil {
 		return model.Player{}, err
 	}
@@ -136,7 +146,7 @@ func (ps *playerService) Get(id model.PlayerID) (model.Player, error) {
 	}, nil
 }
 
-func (ps *playerService) Create(p model.Player) error {
+func (ps *playerService) Create(ctx context.Context, p model.Player) error {
 	if len(p.ID) > maxPlayerUUIDLen {
 		return persistence.ErrInvalidPlayerID
 	}
@@ -145,7 +155,7 @@ func (ps *playerService) Create(p model.Player) error {
 		return persistence.ErrInvalidPlayerName
 	}
 
-	_, err := ps.db.Exec(createPlayer, p.ID, p.Name)
+	_, err := ps.db.ExecContext(ctx, createPlayer, p.ID, p.Name)
 	err = convertMysqlError(err)
 	if err != nil {
 		if err == errDuplicateEntry {
@@ -157,13 +167,13 @@ func (ps *playerService) Create(p model.Player) error {
 	return nil
 }
 
-func (ps *playerService) BeginGame(gID model.GameID, players []model.Player) error {
+func (ps *playerService) BeginGame(ctx context.Context, gID model.GameID, players []model.Player) error {
 	for _, p := range players {
 		if len(p.ID) > maxPlayerUUIDLen {
 			return persistence.ErrInvalidPlayerID
 		}
 
-		_, err := ps.db.Exec(addPlayerToGamePlayerColors, gID, p.ID)
+		_, err := ps.db.ExecContext(ctx, addPlayerToGamePlayerColors, gID, p.ID)
 		if err != nil {
 			return err
 		}
@@ -171,8 +181,13 @@ func (ps *playerService) BeginGame(gID model.GameID, players []model.Player) err
 	return nil
 }
 
-func (ps *playerService) UpdateGameColor(pID model.PlayerID, gID model.GameID, color model.PlayerColor) error {
-	_, err := ps.db.Exec(updatePlayerColor, color, pID, gID)
+func (ps *playerService) UpdateGameColor(
+	ctx context.Context,
+	pID model.PlayerID,
+	gID model.GameID,
+	color model.PlayerColor,
+) error {
+	_, err := ps.db.ExecContext(ctx, updatePlayerColor, color, pID, gID)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,116 @@
+package mysql
+
+import (
+	"context"
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+const (
+	// Spectators stores the players who are watching a game.
+	// The columns act as follows:
+	// GameID is the game being watched
+	// PlayerID is the player watching the game
+	// Shoulder is the player whose hand the spectator is allowed to see, if any
+	createSpectatorsTable = `CREATE TABLE IF NOT EXISTS Spectators (
+		GameID INT UNSIGNED,
+		PlayerID VARCHAR(` + maxPlayerUUIDLenStr + `) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_as_cs,
+		Shoulder VARCHAR(` + maxPlayerUUIDLenStr + `) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_as_cs,
+		PRIMARY KEY (GameID, PlayerID)
+	) ENGINE = INNODB;`
+
+	getSpectatorsForGame = `SELECT
+		PlayerID, Shoulder
+	FROM Spectators
+	WHERE GameID = ?
+	ORDER BY
+		PlayerID ASC
+	;`
+
+	saveSpectator = `INSERT INTO Spectators
+		(GameID, PlayerID, Shoulder)
+	VALUES
+		(?, ?, ?)
+	ON DUPLICATE KEY UPDATE
+		Shoulder = ?
+	;`
+
+	removeSpectator = `DELETE FROM Spectators
+	WHERE GameID = ? AND
+		PlayerID = ?
+	;`
+)
+
+var (
+	spectatorsCreateStmts = []string{
+		createSpectatorsTable,
+	}
+)
+
+var _ persistence.SpectatorService = (*spectatorService)(nil)
+
+type spectatorService struct {
+	db *txWrapper
+}
+
+func getSpectatorService(
+	db *txWrapper,
+) persistence.SpectatorService {
+
+	return &spectatorService{
+		db: db,
+	}
+}
+
+func (s *spectatorService) Get(ctx context.Context, gID model.GameID) ([]model.Spectator, error) {
+	rows, err := s.db.QueryContext(ctx, getSpectatorsForGame, gID)
+	if err != nil {
+		return nil, err
+	}
+
+	specs := []model.Spectator{}
+	var pID, shoulder string
+	for rows.Next() {
+		err = rows.Scan(&pID, &shoulder)
+		if err != nil {
+			return nil, err
+		}
+
+		specs = append(specs, model.Spectator{
+			GameID:   gID,
+			PlayerID: model.PlayerID(pID),
+			Shoulder: model.PlayerID(shoulder),
+		})
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return specs, nil
+}
+
+func (s *spectatorService) Save(ctx context.Context, spec model.Spectator) error {
+	if len(spec.PlayerID) > maxPlayerUUIDLen || len(spec.Shoulder) > maxPlayerUUIDLen {
+		return persistence.ErrInvalidPlayerID
+	}
+
+	_, err := s.db.ExecContext(ctx, saveSpectator, spec.GameID, spec.PlayerID, spec.Shoulder, spec.Shoulder)
+	return convertMysqlError(err)
+}
+
+func (s *spectatorService) Remove(ctx context.Context, gID model.GameID, pID model.PlayerID) error {
+	res, err := s.db.ExecContext(ctx, removeSpectator, gID, pID)
+	if err != nil {
+		return convertMysqlError(err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return persistence.ErrSpectatorNotFound
+	}
+	return nil
+}
@@ -62,16 +62,6 @@ func (t *txWrapper) rollback() error {
 	return t.tx.Rollback()
 }
 
-func (t *txWrapper) Exec(query string, ifs ...interface{}) (sql.Result, error) {
-	t.lock.Lock()
-	defer t.lock.Unlock()
-
-	if t.tx != nil {
-		return t.tx.Exec(query, ifs...)
-	}
-	return t.db.Exec(query, ifs...)
-}
-
 func (t *txWrapper) ExecContext(ctx context.Context, query string, ifs ...interface{}) (sql.Result, error) {
 	t.lock.Lock()
 	defer t.lock.Unlock()
@@ -82,22 +72,22 @@ func (t *txWrapper) ExecContext(ctx context.Context, query string, ifs ...interf
 	return t.db.ExecContext(ctx, query, ifs...)
 }
 
-func (t *txWrapper) QueryRow(query string, ifs ...interface{}) *sql.Row {
+func (t *txWrapper) QueryRowContext(ctx context.Context, query string, ifs ...interface{}) *sql.Row {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
 	if t.tx != nil {
-		return t.tx.QueryRow(query, ifs...)
+		return t.tx.QueryRowContext(ctx, query, ifs...)
 	}
-	return t.db.QueryRow(query, ifs...)
+	return t.db.QueryRowContext(ctx, query, ifs...)
 }
 
-func (t *txWrapper) Query(query string, ifs ...interface{}) (*sql.Rows, error) {
+func (t *txWrapper) QueryContext(ctx context.Context, query string, ifs ...interface{}) (*sql.Rows, error) {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
 	if t.tx != nil {
-		return t.tx.Query(query, ifs...)
+		return t.tx.QueryContext(ctx, query, ifs...)
 	}
-	return t.db.Query(query, ifs...)
+	return t.db.QueryContext(ctx, query, ifs...)
 }
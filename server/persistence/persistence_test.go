@@ -8,8 +8,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/joshprzybyszewski/cribbage/logic/scorer"
 	"github.com/joshprzybyszewski/cribbage/model"
 	"github.com/joshprzybyszewski/cribbage/server/interaction"
+	"github.com/joshprzybyszewski/cribbage/server/npcqueue"
 	"github.com/joshprzybyszewski/cribbage/server/persistence"
 	"github.com/joshprzybyszewski/cribbage/server/persistence/dynamo"
 	"github.com/joshprzybyszewski/cribbage/server/persistence/memory"
@@ -39,6 +41,13 @@ var (
 		`saveGameMissingAction`:         testSaveGameWithMissingAction,
 		`saveInteraction`:               testSaveInteraction,
 		`addColorToGame`:                testAddPlayerColorToGame,
+		`compactFinishedGame`:           testCompactFinishedGame,
+		`moveDeadline`:                  testMoveDeadline,
+		`saveLobby`:                     testSaveLobby,
+		`saveSpectator`:                 testSaveSpectator,
+		`addChatMessage`:                testAddChatMessage,
+		`npcMoves`:                      testNPCMoves,
+		`cancelledWrites`:               testCancelledWrites,
 	}
 )
 
@@ -78,6 +87,16 @@ func persistenceGameCopy(dst *model.Game, src model.Game) {
 	dst.Crib = make([]model.Card, len(src.Crib))
 	_ = copy(dst.Crib, src.Crib)
 
+	if src.DeckOrder != nil {
+		dst.DeckOrder = make([]model.Card, len(src.DeckOrder))
+		_ = copy(dst.DeckOrder, src.DeckOrder)
+	}
+
+	if src.Shuffles != nil {
+		dst.Shuffles = make([]model.ShuffleProof, len(src.Shuffles))
+		_ = copy(dst.Shuffles, src.Shuffles)
+	}
+
 	dst.PeggedCards = make([]model.PeggedCard, len(src.PeggedCards))
 	_ = copy(dst.PeggedCards, src.PeggedCards)
 
@@ -86,7 +105,7 @@ func persistenceGameCopy(dst *model.Game, src model.Game) {
 }
 
 func checkPersistedGame(t *testing.T, name dbName, db persistence.DB, expGame model.Game) {
-	actGame, err := db.GetGame(expGame.ID)
+	actGame, err := db.GetGame(context.Background(), expGame.ID)
 	require.NoError(t, err, `expected to find game with id "%d"`, expGame.ID)
 	checkPersistedGameCompare(t, name, expGame, actGame)
 }
@@ -103,7 +122,7 @@ func checkPersistedGameAt(
 		return
 	}
 
-	actGame, err := db.GetGameAction(expGame.ID, numPrevActions)
+	actGame, err := db.GetGameAction(context.Background(), expGame.ID, numPrevActions)
 	require.NoError(t, err, `expected to find game with id "%d"`, expGame.ID)
 	checkPersistedGameCompare(t, name, expGame, actGame)
 }
@@ -179,38 +198,40 @@ func checkPersistedGameCompare(t *testing.T, name dbName, expGame, actGame model
 
 func TestDB(t *testing.T) {
 	dbfs := map[dbName]persistence.DBFactory{
-		memoryDB: memory.NewFactory(),
+		memoryDB: memory.NewFactory(play.Replay),
 	}
 
 	if !testing.Short() {
 		// We assume you have mongodb stood up locally when running without -short
 		// we change the uri because github actions set up a different mongodb replica set than run-rs does
-		mongo, err := mongodb.NewFactory(`mongodb://127.0.0.1:27017,127.0.0.1:27018/?replicaSet=testReplSet`)
+		mongo, err := mongodb.NewFactory(`mongodb://127.0.0.1:27017,127.0.0.1:27018/?replicaSet=testReplSet`, play.Replay)
 		require.NoError(t, err)
 
 		dbfs[mongoDB] = mongo
 
 		// We further assume you have mysql stood up locally when running without -short
 		cfg := mysql.GetTestConfig()
-		mySQLDB, err := mysql.NewFactory(context.Background(), cfg)
+		mySQLDB, err := mysql.NewFactory(context.Background(), cfg, play.Replay)
 		if err != nil {
 			t.Logf("Expected to connect, but got error: %q. This is expected when running locally.", err.Error())
 			// if we got an error trying to connect, let's fallback to trying to connect to localhost's mysql
 			cfg = mysql.GetTestConfigForLocal()
-			mySQLDB, err = mysql.NewFactory(context.Background(), cfg)
+			mySQLDB, err = mysql.NewFactory(context.Background(), cfg, play.Replay)
 		}
 		require.NoError(t, err)
 
 		dbfs[mysqlDB] = mySQLDB
 
 		// We assume you have dynamodb stood up locally when running without -short
-		dynamodb, err := dynamo.NewFactory(`http://localhost:18079`)
+		dynamodb, err := dynamo.NewFactory(`http://localhost:18079`, play.Replay)
 		require.NoError(t, err)
 
 		dbfs[dynamoDB] = dynamodb
 	}
 
 	for dbName, dbf := range dbfs {
+		require.NoError(t, dbf.Ping(context.Background()), dbName)
+
 		for testName, testFn := range tests {
 			db, err := dbf.New(context.Background())
 			require.NoError(t, err, string(dbName)+`:`+testName)
@@ -219,6 +240,25 @@ func TestDB(t *testing.T) {
 	}
 }
 
+func testCancelledWrites(t *testing.T, name dbName, db persistence.DB) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	p1 := model.Player{
+		ID:    model.PlayerID(rand.String(50)),
+		Name:  `player 1`,
+		Games: map[model.GameID]model.PlayerColor{},
+	}
+	assert.Error(t, db.CreatePlayer(ctx, p1), name)
+	assert.Error(t, db.SaveInteraction(ctx, interaction.New(p1.ID, interaction.Means{
+		Mode: interaction.Localhost,
+		Info: `8484`,
+	})), name)
+
+	_, err := db.GetPlayer(context.Background(), p1.ID)
+	assert.EqualError(t, err, persistence.ErrPlayerNotFound.Error(), name)
+}
+
 func testCreatePlayersWithSimilarNames(t *testing.T, name dbName, db persistence.DB) {
 	suffix := rand.String(50)
 	p1Str := `alice` + suffix
@@ -229,7 +269,7 @@ func testCreatePlayersWithSimilarNames(t *testing.T, name dbName, db persistence
 		Games: map[model.GameID]model.PlayerColor{},
 	}
 
-	assert.NoError(t, db.CreatePlayer(p1))
+	assert.NoError(t, db.CreatePlayer(context.Background(), p1))
 
 	p2 := model.Player{
 		ID:    model.PlayerID(p2Str),
@@ -238,7 +278,7 @@ func testCreatePlayersWithSimilarNames(t *testing.T, name dbName, db persistence
 	}
 
 	assert.NotEqual(t, p1.ID, p2.ID)
-	assert.NoError(t, db.CreatePlayer(p2))
+	assert.NoError(t, db.CreatePlayer(context.Background(), p2))
 }
 
 func testCreatePlayer(t *testing.T, name dbName, db persistence.DB) {
@@ -248,9 +288,9 @@ func testCreatePlayer(t *testing.T, name dbName, db persistence.DB) {
 		Games: map[model.GameID]model.PlayerColor{},
 	}
 
-	assert.NoError(t, db.CreatePlayer(p1))
+	assert.NoError(t, db.CreatePlayer(context.Background(), p1))
 
-	err := db.CreatePlayer(p1)
+	err := db.CreatePlayer(context.Background(), p1)
 	assert.EqualError(t, err, persistence.ErrPlayerAlreadyExists.Error())
 
 	p1conflict := model.Player{
@@ -260,7 +300,7 @@ func testCreatePlayer(t *testing.T, name dbName, db persistence.DB) {
 			model.GameID(4): model.Blue,
 		},
 	}
-	err = db.CreatePlayer(p1conflict)
+	err = db.CreatePlayer(context.Background(), p1conflict)
 	assert.EqualError(t, err, persistence.ErrPlayerAlreadyExists.Error())
 
 	p2 := model.Player{
@@ -272,14 +312,14 @@ func testCreatePlayer(t *testing.T, name dbName, db persistence.DB) {
 	// Don't keep the same memory space for the games copy
 	expP2.Games = map[model.GameID]model.PlayerColor{}
 
-	assert.NoError(t, db.CreatePlayer(p2))
+	assert.NoError(t, db.CreatePlayer(context.Background(), p2))
 
-	actP2, err := db.GetPlayer(p2.ID)
+	actP2, err := db.GetPlayer(context.Background(), p2.ID)
 	require.NoError(t, err)
 	assert.Equal(t, expP2, actP2)
 
 	alice, _, _ := testutils.EmptyAliceAndBob()
-	assert.NoError(t, db.CreatePlayer(alice))
+	assert.NoError(t, db.CreatePlayer(context.Background(), alice))
 
 	// this is just a stub to allow us to add colors for the game
 	g1 := model.Game{
@@ -297,26 +337,26 @@ func testCreatePlayer(t *testing.T, name dbName, db persistence.DB) {
 		PeggedCards:     make([]model.PeggedCard, 0, 8),
 		Actions:         []model.PlayerAction{},
 	}
-	require.NoError(t, db.CreateGame(g1))
+	require.NoError(t, db.CreateGame(context.Background(), g1))
 
-	require.NoError(t, db.AddPlayerColorToGame(p2.ID, model.Blue, g1.ID))
+	require.NoError(t, db.AddPlayerColorToGame(context.Background(), p2.ID, model.Blue, g1.ID))
 
 	expP2.Games = map[model.GameID]model.PlayerColor{
 		g1.ID: model.Blue,
 	}
-	actP2, err = db.GetPlayer(p2.ID)
+	actP2, err = db.GetPlayer(context.Background(), p2.ID)
 	require.NoError(t, err)
 	assert.Equal(t, expP2, actP2)
 
 	g2 := g1
 	g2.PlayerColors = nil
 	g2.ID = model.GameID(rand.Intn(1000))
-	require.NoError(t, db.CreateGame(g2))
+	require.NoError(t, db.CreateGame(context.Background(), g2))
 
-	require.NoError(t, db.AddPlayerColorToGame(p2.ID, model.Red, g2.ID))
+	require.NoError(t, db.AddPlayerColorToGame(context.Background(), p2.ID, model.Red, g2.ID))
 
 	expP2.Games[g2.ID] = model.Red
-	actP2, err = db.GetPlayer(p2.ID)
+	actP2, err = db.GetPlayer(context.Background(), p2.ID)
 	require.NoError(t, err)
 	assert.Equal(t, expP2, actP2)
 }
@@ -357,7 +397,7 @@ func testCreateGame(t *testing.T, name dbName, db persistence.DB) {
 	}
 
 	for i, p := range g1.Players {
-		require.NoError(t, db.CreatePlayer(p))
+		require.NoError(t, db.CreatePlayer(context.Background(), p))
 		if c, ok := g1.PlayerColors[p.ID]; ok {
 			g1.Players[i].Games = map[model.GameID]model.PlayerColor{
 				g1.ID: c,
@@ -372,7 +412,7 @@ func testCreateGame(t *testing.T, name dbName, db persistence.DB) {
 	var g1Copy model.Game
 	persistenceGameCopy(&g1Copy, g1)
 
-	require.NoError(t, db.CreateGame(g1))
+	require.NoError(t, db.CreateGame(context.Background(), g1))
 
 	checkPersistedGame(t, name, db, g1Copy)
 }
@@ -384,7 +424,7 @@ func testSaveGameMultipleTimes(t *testing.T, name dbName, db persistence.DB) {
 	require.NoError(t, err)
 
 	for i, p := range g.Players {
-		require.NoError(t, db.CreatePlayer(p))
+		require.NoError(t, db.CreatePlayer(context.Background(), p))
 		if c, ok := g.PlayerColors[p.ID]; ok {
 			g.Players[i].Games = map[model.GameID]model.PlayerColor{
 				g.ID: c,
@@ -392,18 +432,18 @@ func testSaveGameMultipleTimes(t *testing.T, name dbName, db persistence.DB) {
 		}
 	}
 
-	_, err = db.GetGame(g.ID)
+	_, err = db.GetGame(context.Background(), g.ID)
 	require.Error(t, err)
 	assert.EqualError(t, err, persistence.ErrGameNotFound.Error())
 
 	var g0, g1, g2, g3 model.Game
 	persistenceGameCopy(&g0, g)
 
-	require.NoError(t, db.CreateGame(g))
+	require.NoError(t, db.CreateGame(context.Background(), g))
 
 	checkPersistedGame(t, name, db, g0)
 
-	require.NoError(t, play.HandleAction(&g, model.PlayerAction{
+	require.NoError(t, play.HandleAction(context.Background(), &g, model.PlayerAction{
 		ID:           alice.ID,
 		GameID:       g.ID,
 		Overcomes:    model.DealCards,
@@ -412,10 +452,10 @@ func testSaveGameMultipleTimes(t *testing.T, name dbName, db persistence.DB) {
 	}, abAPIs))
 	persistenceGameCopy(&g1, g)
 
-	require.NoError(t, db.SaveGame(g))
+	require.NoError(t, db.SaveGame(context.Background(), g))
 	checkPersistedGame(t, name, db, g1)
 
-	require.NoError(t, play.HandleAction(&g, model.PlayerAction{
+	require.NoError(t, play.HandleAction(context.Background(), &g, model.PlayerAction{
 		ID:           alice.ID,
 		GameID:       g.ID,
 		Overcomes:    model.CribCard,
@@ -424,10 +464,10 @@ func testSaveGameMultipleTimes(t *testing.T, name dbName, db persistence.DB) {
 	}, abAPIs))
 	persistenceGameCopy(&g2, g)
 
-	require.NoError(t, db.SaveGame(g))
+	require.NoError(t, db.SaveGame(context.Background(), g))
 	checkPersistedGame(t, name, db, g2)
 
-	require.NoError(t, play.HandleAction(&g, model.PlayerAction{
+	require.NoError(t, play.HandleAction(context.Background(), &g, model.PlayerAction{
 		ID:           bob.ID,
 		GameID:       g.ID,
 		Overcomes:    model.CribCard,
@@ -436,7 +476,7 @@ func testSaveGameMultipleTimes(t *testing.T, name dbName, db persistence.DB) {
 	}, abAPIs))
 	persistenceGameCopy(&g3, g)
 
-	require.NoError(t, db.SaveGame(g))
+	require.NoError(t, db.SaveGame(context.Background(), g))
 	checkPersistedGame(t, name, db, g3)
 
 	checkPersistedGameAt(t, name, db, g0, 0)
@@ -456,18 +496,18 @@ func testSaveInteraction(t *testing.T, name dbName, db persistence.DB) {
 	}
 	p1Copy := p1
 
-	require.NoError(t, db.CreatePlayer(model.Player{
+	require.NoError(t, db.CreatePlayer(context.Background(), model.Player{
 		ID:   p1.PlayerID,
 		Name: `testSaveInteractionStubPlayer`,
 	}))
 
-	assert.NoError(t, db.SaveInteraction(p1))
+	assert.NoError(t, db.SaveInteraction(context.Background(), p1))
 
-	actPM, err := db.GetInteraction(p1.PlayerID)
+	actPM, err := db.GetInteraction(context.Background(), p1.PlayerID)
 	require.NoError(t, err, `expected to find player with id "%s"`, p1.PlayerID)
 	assert.Equal(t, p1Copy, actPM)
 
-	assert.NoError(t, db.SaveInteraction(p1))
+	assert.NoError(t, db.SaveInteraction(context.Background(), p1))
 
 	p1update := interaction.PlayerMeans{
 		PlayerID:      p1.PlayerID,
@@ -477,11 +517,291 @@ func testSaveInteraction(t *testing.T, name dbName, db persistence.DB) {
 			Info: `8484`,
 		}},
 	}
-	assert.NoError(t, db.SaveInteraction(p1update))
+	assert.NoError(t, db.SaveInteraction(context.Background(), p1update))
 
-	actPM, err = db.GetInteraction(p1.PlayerID)
+	actPM, err = db.GetInteraction(context.Background(), p1.PlayerID)
 	assert.NoError(t, err)
 	assert.NotEqual(t, p1Copy, actPM)
+
+	webhook := interaction.PlayerMeans{
+		PlayerID:      p1.PlayerID,
+		PreferredMode: interaction.Webhook,
+		Interactions: []interaction.Means{{
+			Mode: interaction.Webhook,
+			Info: interaction.WebhookInfo{
+				URL:    `https://example.com/cribbage`,
+				Secret: `a-secret-that-is-long-enough`,
+			},
+		}},
+		AutoPlay: true,
+	}
+	assert.NoError(t, db.SaveInteraction(context.Background(), webhook))
+
+	actPM, err = db.GetInteraction(context.Background(), p1.PlayerID)
+	require.NoError(t, err)
+	assert.Equal(t, webhook, actPM)
+}
+
+func testSaveLobby(t *testing.T, name dbName, db persistence.DB) {
+	alice, bob, _ := testutils.EmptyAliceAndBob()
+
+	// some DBs only keep times to the millisecond
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	l := model.Lobby{
+		ID:         model.NewLobbyID(),
+		Host:       alice.ID,
+		NumPlayers: 3,
+		Seated:     []model.PlayerID{alice.ID},
+		Status:     model.LobbyOpen,
+		NPCFill:    interaction.Calc,
+		FillAt:     now.Add(time.Minute),
+		Settings: model.GameSettings{
+			MoveTimeout: time.Hour,
+			OnTimeout:   model.ForfeitOnTimeout,
+		},
+		Created: now,
+	}
+
+	_, err := db.GetLobby(context.Background(), l.ID)
+	assert.Equal(t, persistence.ErrLobbyNotFound, err)
+	assert.Equal(t, persistence.ErrLobbyNotFound, db.SaveLobby(context.Background(), l))
+
+	require.NoError(t, db.CreateLobby(context.Background(), l))
+	assert.Equal(t, persistence.ErrLobbyAlreadyExists, db.CreateLobby(context.Background(), l))
+
+	actL, err := db.GetLobby(context.Background(), l.ID)
+	require.NoError(t, err)
+	assert.Equal(t, l, actL)
+	assert.Contains(t, getOpenLobbyIDs(t, db), l.ID)
+
+	stale := l
+	require.NoError(t, l.Sit(bob.ID))
+	require.NoError(t, db.SaveLobby(context.Background(), l))
+	l.Version++
+
+	actL, err = db.GetLobby(context.Background(), l.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []model.PlayerID{alice.ID, bob.ID}, actL.Seated)
+	assert.Equal(t, uint64(1), actL.Version)
+	assert.Contains(t, getOpenLobbyIDs(t, db), l.ID)
+
+	// somebody who read the lobby before bob sat down can't save over him
+	require.NoError(t, stale.Sit(model.PlayerID(`charlie`)))
+	assert.Equal(t, persistence.ErrLobbyConflict, db.SaveLobby(context.Background(), stale))
+
+	actL, err = db.GetLobby(context.Background(), l.ID)
+	require.NoError(t, err)
+	assert.Equal(t, l, actL)
+
+	l.Status = model.LobbyStarted
+	l.GameID = model.NewGameID()
+	require.NoError(t, db.SaveLobby(context.Background(), l))
+	l.Version++
+
+	actL, err = db.GetLobby(context.Background(), l.ID)
+	require.NoError(t, err)
+	assert.Equal(t, l, actL)
+	assert.NotContains(t, getOpenLobbyIDs(t, db), l.ID)
+
+	inv := model.Lobby{
+		ID:         model.NewLobbyID(),
+		Host:       alice.ID,
+		NumPlayers: 2,
+		Seated:     []model.PlayerID{alice.ID},
+		Invited:    []model.PlayerID{bob.ID},
+		Status:     model.LobbyOpen,
+		Created:    now,
+	}
+	require.NoError(t, db.CreateLobby(context.Background(), inv))
+	assert.Contains(t, getOpenLobbyIDs(t, db), inv.ID)
+
+	require.NoError(t, inv.Decline(bob.ID))
+	require.NoError(t, db.SaveLobby(context.Background(), inv))
+	inv.Version++
+
+	actL, err = db.GetLobby(context.Background(), inv.ID)
+	require.NoError(t, err)
+	assert.Equal(t, inv, actL)
+	assert.NotContains(t, getOpenLobbyIDs(t, db), inv.ID)
+}
+
+func testSaveSpectator(t *testing.T, name dbName, db persistence.DB) {
+	alice, bob, _ := testutils.EmptyAliceAndBob()
+	gID := model.NewGameID()
+
+	ss, err := db.GetSpectators(context.Background(), gID)
+	require.NoError(t, err)
+	assert.Empty(t, ss)
+	assert.Equal(t, persistence.ErrSpectatorNotFound, db.RemoveSpectator(context.Background(), gID, alice.ID))
+
+	aliceWatching := model.Spectator{
+		GameID:   gID,
+		PlayerID: alice.ID,
+	}
+	bobWatching := model.Spectator{
+		GameID:   gID,
+		PlayerID: bob.ID,
+	}
+	require.NoError(t, db.SaveSpectator(context.Background(), bobWatching))
+	require.NoError(t, db.SaveSpectator(context.Background(), aliceWatching))
+
+	exp := []model.Spectator{aliceWatching, bobWatching}
+	if bob.ID < alice.ID {
+		exp = []model.Spectator{bobWatching, aliceWatching}
+	}
+	ss, err = db.GetSpectators(context.Background(), gID)
+	require.NoError(t, err)
+	assert.Equal(t, exp, ss)
+
+	// saving again updates the spectator
+	aliceWatching.Shoulder = model.PlayerID(`charlie`)
+	require.NoError(t, db.SaveSpectator(context.Background(), aliceWatching))
+	ss, err = db.GetSpectators(context.Background(), gID)
+	require.NoError(t, err)
+	assert.Len(t, ss, 2)
+	assert.Contains(t, ss, aliceWatching)
+
+	require.NoError(t, db.RemoveSpectator(context.Background(), gID, bob.ID))
+	ss, err = db.GetSpectators(context.Background(), gID)
+	require.NoError(t, err)
+	assert.Equal(t, []model.Spectator{aliceWatching}, ss)
+
+	assert.Equal(t, persistence.ErrInvalidGameID, db.SaveSpectator(context.Background(), model.Spectator{
+		PlayerID: alice.ID,
+	}))
+}
+
+func testAddChatMessage(t *testing.T, name dbName, db persistence.DB) {
+	alice, bob, _ := testutils.EmptyAliceAndBob()
+	gID := model.NewGameID()
+
+	msgs, err := db.GetChatMessages(context.Background(), gID)
+	require.NoError(t, err)
+	assert.Empty(t, msgs)
+
+	// some DBs only keep times to the millisecond
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	exp := []model.ChatMessage{{
+		GameID:   gID,
+		PlayerID: alice.ID,
+		Message:  `good luck`,
+		Sent:     now,
+	}, {
+		GameID:   gID,
+		PlayerID: bob.ID,
+		Message:  `you too!`,
+		Sent:     now.Add(time.Second),
+	}, {
+		GameID:   gID,
+		PlayerID: alice.ID,
+		Message:  `♣♥♠♦`,
+		Sent:     now.Add(2 * time.Second),
+	}}
+	for _, cm := range exp {
+		require.NoError(t, db.AddChatMessage(context.Background(), cm))
+	}
+
+	msgs, err = db.GetChatMessages(context.Background(), gID)
+	require.NoError(t, err)
+	assert.Equal(t, exp, msgs)
+
+	assert.Equal(t, model.ErrEmptyChatMessage, db.AddChatMessage(context.Background(), model.ChatMessage{
+		GameID:   gID,
+		PlayerID: alice.ID,
+		Sent:     now,
+	}))
+	assert.Equal(t, persistence.ErrInvalidGameID, db.AddChatMessage(context.Background(), model.ChatMessage{
+		PlayerID: alice.ID,
+		Message:  `hello?`,
+		Sent:     now,
+	}))
+}
+
+func testNPCMoves(t *testing.T, name dbName, db persistence.DB) {
+	alice, _, _ := testutils.EmptyAliceAndBob()
+	gID := model.NewGameID()
+
+	// some DBs only keep times to the millisecond
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	newJob := func(numActions int) npcqueue.Job {
+		j := npcqueue.NewJob(model.PlayerAction{
+			GameID:    gID,
+			ID:        alice.ID,
+			Overcomes: model.DealCards,
+			Action:    model.DealAction{NumShuffles: 3},
+		}, numActions)
+		j.Queued = now
+		j.NextAttempt = now
+		return j
+	}
+
+	j := newJob(4)
+	added, err := db.AddNPCMove(context.Background(), j)
+	require.NoError(t, err)
+	assert.True(t, added)
+	assert.Equal(t, []npcqueue.Job{j}, getDueNPCMoves(t, db, gID, now))
+	assert.Empty(t, getDueNPCMoves(t, db, gID, now.Add(-time.Second)))
+
+	// the NPC only has one move for each game
+	for _, numActions := range []int{3, 4} {
+		added, err = db.AddNPCMove(context.Background(), newJob(numActions))
+		require.NoError(t, err)
+		assert.False(t, added)
+	}
+
+	j.Attempts = 1
+	j.LastError = `db is down`
+	j.NextAttempt = now.Add(time.Minute)
+	require.NoError(t, db.UpdateNPCMove(context.Background(), j))
+	assert.Empty(t, getDueNPCMoves(t, db, gID, now))
+	assert.Equal(t, []npcqueue.Job{j}, getDueNPCMoves(t, db, gID, now.Add(time.Minute)))
+
+	// a move for a later state of the game replaces it
+	later := newJob(6)
+	added, err = db.AddNPCMove(context.Background(), later)
+	require.NoError(t, err)
+	assert.True(t, added)
+	assert.Equal(t, []npcqueue.Job{later}, getDueNPCMoves(t, db, gID, now.Add(time.Minute)))
+
+	// the replaced move can't change the later one
+	require.NoError(t, db.UpdateNPCMove(context.Background(), j))
+	require.NoError(t, db.RemoveNPCMove(context.Background(), j.Key))
+	assert.Equal(t, []npcqueue.Job{later}, getDueNPCMoves(t, db, gID, now.Add(time.Minute)))
+
+	require.NoError(t, db.RemoveNPCMove(context.Background(), later.Key))
+	assert.Empty(t, getDueNPCMoves(t, db, gID, now.Add(time.Minute)))
+
+	_, err = db.AddNPCMove(context.Background(), npcqueue.NewJob(model.PlayerAction{
+		ID: alice.ID,
+	}, 1))
+	assert.Equal(t, persistence.ErrInvalidGameID, err)
+}
+
+// getDueNPCMoves returns the due moves for the game, since the
+// other tests (and other runs) leave their moves in the DB
+func getDueNPCMoves(t *testing.T, db persistence.DB, gID model.GameID, now time.Time) []npcqueue.Job {
+	due, err := db.GetDueNPCMoves(context.Background(), now)
+	require.NoError(t, err)
+
+	var jobs []npcqueue.Job
+	for _, j := range due {
+		if j.GameID == gID {
+			jobs = append(jobs, j)
+		}
+	}
+	return jobs
+}
+
+func getOpenLobbyIDs(t *testing.T, db persistence.DB) []model.LobbyID {
+	open, err := db.GetOpenLobbies(context.Background())
+	require.NoError(t, err)
+
+	lIDs := make([]model.LobbyID, 0, len(open))
+	for _, l := range open {
+		assert.Equal(t, model.LobbyOpen, l.Status)
+		lIDs = append(lIDs, l.ID)
+	}
+	return lIDs
 }
 
 func testAddPlayerColorToGame(t *testing.T, name dbName, db persistence.DB) {
@@ -490,7 +810,7 @@ func testAddPlayerColorToGame(t *testing.T, name dbName, db persistence.DB) {
 	g, err := play.CreateGame([]model.Player{alice, bob}, abAPIs)
 	require.NoError(t, err)
 	for _, p := range g.Players {
-		require.NoError(t, db.CreatePlayer(p))
+		require.NoError(t, db.CreatePlayer(context.Background(), p))
 	}
 
 	// Right now, CreateGame assigns colors to players, but we may
@@ -499,23 +819,23 @@ func testAddPlayerColorToGame(t *testing.T, name dbName, db persistence.DB) {
 	playerColors := g.PlayerColors
 	g.PlayerColors = nil
 
-	require.NoError(t, db.CreateGame(g))
+	require.NoError(t, db.CreateGame(context.Background(), g))
 
 	for _, pID := range []model.PlayerID{alice.ID, bob.ID} {
-		require.NoError(t, db.AddPlayerColorToGame(pID, playerColors[pID], g.ID))
+		require.NoError(t, db.AddPlayerColorToGame(context.Background(), pID, playerColors[pID], g.ID))
 	}
 
-	a2, err := db.GetPlayer(alice.ID)
+	a2, err := db.GetPlayer(context.Background(), alice.ID)
 	require.NoError(t, err)
 	assert.NotEqual(t, alice, a2)
 	assert.Equal(t, playerColors[alice.ID], a2.Games[g.ID])
 
-	b2, err := db.GetPlayer(bob.ID)
+	b2, err := db.GetPlayer(context.Background(), bob.ID)
 	require.NoError(t, err)
 	assert.NotEqual(t, bob, b2)
 	assert.Equal(t, playerColors[bob.ID], b2.Games[g.ID])
 
-	g2, err := db.GetGame(g.ID)
+	g2, err := db.GetGame(context.Background(), g.ID)
 	require.NoError(t, err)
 	assert.NotEqual(t, g, g2)
 	assert.Equal(t, g2.PlayerColors[alice.ID], a2.Games[g.ID])
@@ -528,7 +848,7 @@ func testSaveGameWithMissingAction(t *testing.T, name dbName, db persistence.DB)
 	g, err := play.CreateGame([]model.Player{alice, bob}, abAPIs)
 	require.NoError(t, err)
 	for i, p := range g.Players {
-		require.NoError(t, db.CreatePlayer(p))
+		require.NoError(t, db.CreatePlayer(context.Background(), p))
 		if c, ok := g.PlayerColors[p.ID]; ok {
 			g.Players[i].Games = map[model.GameID]model.PlayerColor{
 				g.ID: c,
@@ -536,18 +856,18 @@ func testSaveGameWithMissingAction(t *testing.T, name dbName, db persistence.DB)
 		}
 	}
 
-	_, err = db.GetGame(g.ID)
+	_, err = db.GetGame(context.Background(), g.ID)
 	require.Error(t, err)
 	assert.EqualError(t, err, persistence.ErrGameNotFound.Error())
 
 	var gCopy model.Game
 	persistenceGameCopy(&gCopy, g)
 
-	require.NoError(t, db.CreateGame(g))
+	require.NoError(t, db.CreateGame(context.Background(), g))
 
 	checkPersistedGame(t, name, db, gCopy)
 
-	require.NoError(t, play.HandleAction(&g, model.PlayerAction{
+	require.NoError(t, play.HandleAction(context.Background(), &g, model.PlayerAction{
 		ID:           alice.ID,
 		GameID:       g.ID,
 		Overcomes:    model.DealCards,
@@ -556,10 +876,10 @@ func testSaveGameWithMissingAction(t *testing.T, name dbName, db persistence.DB)
 	}, abAPIs))
 	persistenceGameCopy(&gCopy, g)
 
-	require.NoError(t, db.SaveGame(g))
+	require.NoError(t, db.SaveGame(context.Background(), g))
 	checkPersistedGame(t, name, db, gCopy)
 
-	require.NoError(t, play.HandleAction(&g, model.PlayerAction{
+	require.NoError(t, play.HandleAction(context.Background(), &g, model.PlayerAction{
 		ID:           alice.ID,
 		GameID:       g.ID,
 		Overcomes:    model.CribCard,
@@ -568,10 +888,10 @@ func testSaveGameWithMissingAction(t *testing.T, name dbName, db persistence.DB)
 	}, abAPIs))
 	persistenceGameCopy(&gCopy, g)
 
-	require.NoError(t, db.SaveGame(g))
+	require.NoError(t, db.SaveGame(context.Background(), g))
 	checkPersistedGame(t, name, db, gCopy)
 
-	require.NoError(t, play.HandleAction(&g, model.PlayerAction{
+	require.NoError(t, play.HandleAction(context.Background(), &g, model.PlayerAction{
 		ID:           bob.ID,
 		GameID:       g.ID,
 		Overcomes:    model.CribCard,
@@ -580,10 +900,10 @@ func testSaveGameWithMissingAction(t *testing.T, name dbName, db persistence.DB)
 	}, abAPIs))
 	persistenceGameCopy(&gCopy, g)
 
-	require.NoError(t, db.SaveGame(g))
+	require.NoError(t, db.SaveGame(context.Background(), g))
 	checkPersistedGame(t, name, db, gCopy)
 
-	require.NoError(t, play.HandleAction(&g, model.PlayerAction{
+	require.NoError(t, play.HandleAction(context.Background(), &g, model.PlayerAction{
 		ID:           bob.ID,
 		GameID:       g.ID,
 		Overcomes:    model.CutCard,
@@ -598,19 +918,19 @@ func testSaveGameWithMissingAction(t *testing.T, name dbName, db persistence.DB)
 	badAction := prevAction
 	badAction.ID = model.PlayerID(`nefario`)
 	g.Actions[i] = badAction
-	require.Error(t, db.SaveGame(g), `saving a game with an action by a player outside of it is a :badtime:`)
+	require.Error(t, db.SaveGame(context.Background(), g), `saving a game with an action by a player outside of it is a :badtime:`)
 
 	badAction = prevAction
 	badAction.GameID = g.ID + 1
 	g.Actions[i] = badAction
-	require.Error(t, db.SaveGame(g), `saving a game with an action on a different game is a :badtime:`)
+	require.Error(t, db.SaveGame(context.Background(), g), `saving a game with an action on a different game is a :badtime:`)
 	// set the latest action back to what it's supposed to be
 	g.Actions[i] = prevAction
 
 	// splice out an action
 	prevActionSlice := g.Actions
 	g.Actions = append(g.Actions[:1], g.Actions[2:]...)
-	require.Error(t, db.SaveGame(g), `saving a game with a missing action is a :badtime:`)
+	require.Error(t, db.SaveGame(context.Background(), g), `saving a game with a missing action is a :badtime:`)
 	// set the action slice back to what it was
 	g.Actions = prevActionSlice
 
@@ -622,13 +942,173 @@ func testSaveGameWithMissingAction(t *testing.T, name dbName, db persistence.DB)
 	if name == mysqlDB {
 		// mysql is just storing one action per save. the previous ones can be corrupt as all get out
 		// but as long as the latest one is fine, so are we
-		assert.NoError(t, db.SaveGame(g), `saving a game with a corrupted action is a :badtime:`)
+		assert.NoError(t, db.SaveGame(context.Background(), g), `saving a game with a corrupted action is a :badtime:`)
 	} else {
 		// this is because the noSQL databases are persisting ALL of the actions _every_ time
-		assert.Error(t, db.SaveGame(g), `saving a game with a corrupted action is a :badtime:`)
+		assert.Error(t, db.SaveGame(context.Background(), g), `saving a game with a corrupted action is a :badtime:`)
+	}
+}
+
+func testCompactFinishedGame(t *testing.T, name dbName, db persistence.DB) {
+	alice, bob, abAPIs := testutils.EmptyAliceAndBob()
+
+	g, err := play.CreateGame([]model.Player{alice, bob}, abAPIs)
+	require.NoError(t, err)
+	for i, p := range g.Players {
+		require.NoError(t, db.CreatePlayer(context.Background(), p))
+		if c, ok := g.PlayerColors[p.ID]; ok {
+			g.Players[i].Games = map[model.GameID]model.PlayerColor{
+				g.ID: c,
+			}
+		}
+	}
+
+	// get everyone close to the end so the game finishes in the first hand
+	for c := range g.CurrentScores {
+		g.CurrentScores[c] = 118
+	}
+	numActive, err := db.CountActiveGames(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, db.CreateGame(context.Background(), g))
+	actActive, err := db.CountActiveGames(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, numActive+1, actActive, name)
+
+	snapshots := make([]model.Game, 1, 32)
+	persistenceGameCopy(&snapshots[0], g)
+
+	rp := persistence.RetentionPolicy{
+		CheckpointInterval: 3,
+	}
+	for !g.IsOver() {
+		for pID, b := range g.BlockingPlayers {
+			require.NoError(t, play.HandleAction(context.Background(), &g, legalAction(g, pID, b), abAPIs))
+			break
+		}
+
+		var gCopy model.Game
+		persistenceGameCopy(&gCopy, g)
+		snapshots = append(snapshots, gCopy)
+		require.NoError(t, db.SaveGame(context.Background(), g))
+
+		if !g.IsOver() {
+			assert.EqualError(t, db.CompactGame(context.Background(), g.ID, rp), persistence.ErrGameNotOver.Error())
+		}
+	}
+
+	actActive, err = db.CountActiveGames(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, numActive, actActive, `the finished game is no longer active: %s`, name)
+
+	require.NotEmpty(t, rp.SnapshotsToCompact(g), `expected to throw away some snapshots`)
+	toCompact, err := db.GetGamesToCompact(context.Background())
+	require.NoError(t, err)
+	assert.Contains(t, toCompact, g.ID)
+
+	require.NoError(t, db.CompactGame(context.Background(), g.ID, rp))
+	toCompact, err = db.GetGamesToCompact(context.Background())
+	require.NoError(t, err)
+	assert.NotContains(t, toCompact, g.ID)
+
+	checkPersistedGame(t, name, db, snapshots[len(snapshots)-1])
+	for i := range snapshots {
+		actGame, err := db.GetGameAction(context.Background(), g.ID, uint(i))
+		require.NoError(t, err, `expected to rebuild snapshot %d`, i)
+		checkPersistedGameCompare(t, name, snapshots[i], actGame)
 	}
 }
 
+func testMoveDeadline(t *testing.T, name dbName, db persistence.DB) {
+	alice, bob, abAPIs := testutils.EmptyAliceAndBob()
+
+	g, err := play.CreateGame([]model.Player{alice, bob}, abAPIs)
+	require.NoError(t, err)
+	require.NoError(t, db.CreatePlayer(context.Background(), alice))
+	require.NoError(t, db.CreatePlayer(context.Background(), bob))
+	require.NoError(t, db.CreateGame(context.Background(), g))
+
+	actDeadline, err := db.GetMoveDeadline(context.Background(), g.ID)
+	require.NoError(t, err)
+	assert.True(t, actDeadline.IsZero(), name)
+
+	// the databases don't all keep nanoseconds
+	deadline := time.Now().Add(time.Minute).Truncate(time.Millisecond)
+	require.NoError(t, db.SetMoveDeadline(context.Background(), g.ID, deadline))
+
+	actDeadline, err = db.GetMoveDeadline(context.Background(), g.ID)
+	require.NoError(t, err)
+	assert.True(t, deadline.Equal(actDeadline), name)
+
+	overdue, err := db.GetOverdueGames(context.Background(), deadline.Add(-time.Second))
+	require.NoError(t, err)
+	assert.NotContains(t, overdue, g.ID, name)
+
+	overdue, err = db.GetOverdueGames(context.Background(), deadline.Add(time.Second))
+	require.NoError(t, err)
+	assert.Contains(t, overdue, g.ID, name)
+
+	// a later deadline replaces the first one
+	require.NoError(t, db.SetMoveDeadline(context.Background(), g.ID, deadline.Add(time.Hour)))
+	overdue, err = db.GetOverdueGames(context.Background(), deadline.Add(time.Second))
+	require.NoError(t, err)
+	assert.NotContains(t, overdue, g.ID, name)
+
+	// and clearing it means it's never overdue
+	require.NoError(t, db.SetMoveDeadline(context.Background(), g.ID, time.Time{}))
+	overdue, err = db.GetOverdueGames(context.Background(), deadline.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.NotContains(t, overdue, g.ID, name)
+
+	actDeadline, err = db.GetMoveDeadline(context.Background(), g.ID)
+	require.NoError(t, err)
+	assert.True(t, actDeadline.IsZero(), name)
+}
+
+// legalAction returns an action for the blocking player that the game will accept
+func legalAction(g model.Game, pID model.PlayerID, b model.Blocker) model.PlayerAction {
+	pa := model.PlayerAction{
+		GameID:       g.ID,
+		ID:           pID,
+		Overcomes:    b,
+		TimestampStr: time.Now().Format(time.RFC3339),
+	}
+
+	hand := g.Hands[pID]
+	switch b {
+	case model.DealCards:
+		pa.Action = model.DealAction{NumShuffles: 1}
+	case model.CribCard:
+		pa.Action = model.BuildCribAction{Cards: hand[:len(hand)-4]}
+	case model.CutCard:
+		pa.Action = model.CutDeckAction{Percentage: 0.5}
+	case model.PegCard:
+		peg := model.PegAction{SayGo: true}
+		for _, c := range hand {
+			if hasBeenPegged(g, c) || g.CurrentPeg()+c.PegValue() > model.MaxPeggingValue {
+				continue
+			}
+			peg = model.PegAction{Card: c}
+			break
+		}
+		pa.Action = peg
+	case model.CountHand:
+		pa.Action = model.CountHandAction{Pts: scorer.HandPoints(g.CutCard, hand)}
+	case model.CountCrib:
+		pa.Action = model.CountCribAction{Pts: scorer.CribPoints(g.CutCard, g.Crib)}
+	}
+
+	return pa
+}
+
+func hasBeenPegged(g model.Game, c model.Card) bool {
+	for _, pc := range g.PeggedCards {
+		if pc.Card == c {
+			return true
+		}
+	}
+	return false
+}
+
 func TestTransactionality(t *testing.T) {
 	// Right now (and probably ever) the memory persistence isn't transactional
 	dbfs := map[dbName]persistence.DBFactory{
@@ -638,19 +1118,19 @@ func TestTransactionality(t *testing.T) {
 	if !testing.Short() {
 		// We assume you have mongodb stood up locally when running without -short
 		// we change the uri because github actions set up a different mongodb replica set than run-rs does
-		mongo, err := mongodb.NewFactory(`mongodb://127.0.0.1:27017,127.0.0.1:27018/?replicaSet=testReplSet`)
+		mongo, err := mongodb.NewFactory(`mongodb://127.0.0.1:27017,127.0.0.1:27018/?replicaSet=testReplSet`, play.Replay)
 		require.NoError(t, err)
 
 		dbfs[mongoDB] = mongo
 
 		// We further assume you have mysql stood up locally when running without -short
 		cfg := mysql.GetTestConfig()
-		mySQLDB, err := mysql.NewFactory(context.Background(), cfg)
+		mySQLDB, err := mysql.NewFactory(context.Background(), cfg, play.Replay)
 		if err != nil {
 			t.Logf("Expected to connect, but got error: %q. This is expected when running locally.", err.Error())
 			// if we got an error trying to connect, let's fallback to trying to connect to localhost's mysql
 			cfg = mysql.GetTestConfigForLocal()
-			mySQLDB, err = mysql.NewFactory(context.Background(), cfg)
+			mySQLDB, err = mysql.NewFactory(context.Background(), cfg, play.Replay)
 		}
 		require.NoError(t, err)
 
@@ -663,6 +1143,7 @@ func TestTransactionality(t *testing.T) {
 		`player service`:              playerTxTest,
 		`game service`:                gameTxTest,
 		`rollback the player service`: rollbackPlayerTxTest,
+		`cancel the player service`:   cancelledPlayerTxTest,
 	}
 
 	for databaseName, dbf := range dbfs {
@@ -684,8 +1165,8 @@ func TestTransactionality(t *testing.T) {
 type txTest func(t *testing.T, databaseName dbName, db1, db2, postCommitDB persistence.DB)
 
 func playerTxTest(t *testing.T, databaseName dbName, db1, db2, postCommitDB persistence.DB) {
-	require.NoError(t, db1.Start())
-	require.NoError(t, db2.Start())
+	require.NoError(t, db1.Start(context.Background()))
+	require.NoError(t, db2.Start(context.Background()))
 
 	p1 := model.Player{
 		ID:    model.PlayerID(rand.String(50)),
@@ -693,10 +1174,10 @@ func playerTxTest(t *testing.T, databaseName dbName, db1, db2, postCommitDB pers
 		Games: map[model.GameID]model.PlayerColor{},
 	}
 
-	assert.NoError(t, db1.CreatePlayer(p1))
+	assert.NoError(t, db1.CreatePlayer(context.Background(), p1))
 	p1Mod := p1
 	p1Mod.Name = `different player 1 name`
-	err := db2.CreatePlayer(p1Mod)
+	err := db2.CreatePlayer(context.Background(), p1Mod)
 	if databaseName == mysqlDB {
 		assert.Error(t, err)
 		assert.True(t, mysql.IsLockWaitTimeout(err))
@@ -704,14 +1185,14 @@ func playerTxTest(t *testing.T, databaseName dbName, db1, db2, postCommitDB pers
 		assert.NoError(t, err)
 	}
 
-	err = db1.CreatePlayer(p1)
+	err = db1.CreatePlayer(context.Background(), p1)
 	assert.EqualError(t, err, persistence.ErrPlayerAlreadyExists.Error())
 
-	savedP1, err := db1.GetPlayer(p1.ID)
+	savedP1, err := db1.GetPlayer(context.Background(), p1.ID)
 	require.NoError(t, err)
 	assert.Equal(t, p1, savedP1)
 
-	savedP1Mod, err := db2.GetPlayer(p1.ID)
+	savedP1Mod, err := db2.GetPlayer(context.Background(), p1.ID)
 	if databaseName == mysqlDB {
 		assert.Error(t, err)
 		assert.EqualError(t, err, persistence.ErrPlayerNotFound.Error())
@@ -722,15 +1203,15 @@ func playerTxTest(t *testing.T, databaseName dbName, db1, db2, postCommitDB pers
 
 	assert.NoError(t, db1.Commit())
 
-	postCommitP1, err := postCommitDB.GetPlayer(p1.ID)
+	postCommitP1, err := postCommitDB.GetPlayer(context.Background(), p1.ID)
 	require.NoError(t, err)
 	assert.Equal(t, p1, postCommitP1)
 	assert.NotEqual(t, p1Mod, postCommitP1)
 }
 
 func rollbackPlayerTxTest(t *testing.T, databaseName dbName, db1, db2, postCommitDB persistence.DB) {
-	require.NoError(t, db1.Start())
-	require.NoError(t, db2.Start())
+	require.NoError(t, db1.Start(context.Background()))
+	require.NoError(t, db2.Start(context.Background()))
 
 	p1 := model.Player{
 		ID:    model.PlayerID(rand.String(50)),
@@ -738,51 +1219,84 @@ func rollbackPlayerTxTest(t *testing.T, databaseName dbName, db1, db2, postCommi
 		Games: map[model.GameID]model.PlayerColor{},
 	}
 
-	assert.NoError(t, db1.CreatePlayer(p1))
+	assert.NoError(t, db1.CreatePlayer(context.Background(), p1))
 	p2 := model.Player{
 		ID:    model.PlayerID(rand.String(50)),
 		Name:  `player 2`,
 		Games: map[model.GameID]model.PlayerColor{},
 	}
-	assert.NoError(t, db2.CreatePlayer(p2))
+	assert.NoError(t, db2.CreatePlayer(context.Background(), p2))
 
-	savedP1, err := db1.GetPlayer(p1.ID)
+	savedP1, err := db1.GetPlayer(context.Background(), p1.ID)
 	require.NoError(t, err)
 	assert.Equal(t, p1, savedP1)
 
-	savedP2, err := db1.GetPlayer(p2.ID)
+	savedP2, err := db1.GetPlayer(context.Background(), p2.ID)
 	assert.Error(t, err)
 	assert.NotEqual(t, p2, savedP2)
 
-	savedP1, err = db2.GetPlayer(p1.ID)
+	savedP1, err = db2.GetPlayer(context.Background(), p1.ID)
 	assert.Error(t, err)
 	assert.NotEqual(t, p1, savedP1)
 
-	savedP2, err = db2.GetPlayer(p2.ID)
+	savedP2, err = db2.GetPlayer(context.Background(), p2.ID)
 	require.NoError(t, err)
 	assert.Equal(t, p2, savedP2)
 
 	assert.NoError(t, db1.Rollback())
 	assert.NoError(t, db2.Rollback())
 
-	postCommitP1, err := postCommitDB.GetPlayer(p1.ID)
+	postCommitP1, err := postCommitDB.GetPlayer(context.Background(), p1.ID)
 	assert.Error(t, err)
 	assert.NotEqual(t, p1, postCommitP1)
 
-	postCommitP2, err := postCommitDB.GetPlayer(p2.ID)
+	postCommitP2, err := postCommitDB.GetPlayer(context.Background(), p2.ID)
 	assert.Error(t, err)
 	assert.NotEqual(t, p2, postCommitP2)
 
 }
 
+// cancelledPlayerTxTest checks that a transaction whose context is cancelled before
+// it commits (such as when the client goes away) never saves its changes
+func cancelledPlayerTxTest(t *testing.T, databaseName dbName, db1, _, postCommitDB persistence.DB) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, db1.Start(ctx))
+
+	p1 := model.Player{
+		ID:    model.PlayerID(rand.String(50)),
+		Name:  `player 1`,
+		Games: map[model.GameID]model.PlayerColor{},
+	}
+	assert.NoError(t, db1.CreatePlayer(ctx, p1))
+
+	cancel()
+
+	p2 := model.Player{
+		ID:    model.PlayerID(rand.String(50)),
+		Name:  `player 2`,
+		Games: map[model.GameID]model.PlayerColor{},
+	}
+	assert.Error(t, db1.CreatePlayer(ctx, p2), databaseName)
+
+	// mysql rolls back the transaction as soon as its context is cancelled,
+	// so this can say that the transaction is already done
+	_ = db1.Rollback()
+
+	_, err := postCommitDB.GetPlayer(context.Background(), p1.ID)
+	assert.EqualError(t, err, persistence.ErrPlayerNotFound.Error(), databaseName)
+	_, err = postCommitDB.GetPlayer(context.Background(), p2.ID)
+	assert.EqualError(t, err, persistence.ErrPlayerNotFound.Error(), databaseName)
+}
+
 func gameTxTest(t *testing.T, databaseName dbName, db1, db2, postCommitDB persistence.DB) {
 	alice, bob, _ := testutils.EmptyAliceAndBob()
 
-	assert.NoError(t, db1.CreatePlayer(alice))
-	assert.NoError(t, db1.CreatePlayer(bob))
+	assert.NoError(t, db1.CreatePlayer(context.Background(), alice))
+	assert.NoError(t, db1.CreatePlayer(context.Background(), bob))
 
-	require.NoError(t, db1.Start())
-	require.NoError(t, db2.Start())
+	require.NoError(t, db1.Start(context.Background()))
+	require.NoError(t, db2.Start(context.Background()))
 
 	g1 := model.Game{
 		ID:              model.NewGameID(),
@@ -826,11 +1340,11 @@ func gameTxTest(t *testing.T, databaseName dbName, db1, db2, postCommitDB persis
 
 	persistenceGameCopy(&g1Copy, g1)
 
-	require.NoError(t, db1.CreateGame(g1))
+	require.NoError(t, db1.CreateGame(context.Background(), g1))
 
 	checkPersistedGame(t, databaseName, db1, g1Copy)
 
-	actGame, err := db2.GetGame(g1.ID)
+	actGame, err := db2.GetGame(context.Background(), g1.ID)
 	assert.Error(t, err)
 	assert.NotEqual(t, g1Copy, actGame)
 
@@ -0,0 +1,148 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+)
+
+// RetentionPolicy describes which snapshots of a finished game we keep around.
+// Active games always keep every snapshot.
+type RetentionPolicy struct {
+	// CheckpointInterval says to keep every nth snapshot of a finished game.
+	// Zero means we only keep the snapshots we cannot rebuild through replay.
+	CheckpointInterval uint
+}
+
+// SnapshotsToCompact returns the number of actions for each snapshot of the
+// finished game that can be thrown away. The first and last snapshots are always
+// kept, as is every snapshot following an action that cannot be replayed.
+func (rp RetentionPolicy) SnapshotsToCompact(g model.Game) []uint {
+	var toCompact []uint
+	for i := 1; i < g.NumActions(); i++ {
+		if !isReplayable(g, g.Actions[i-1]) {
+			continue
+		}
+		if rp.CheckpointInterval > 0 && uint(i)%rp.CheckpointInterval == 0 {
+			continue
+		}
+		toCompact = append(toCompact, uint(i))
+	}
+	return toCompact
+}
+
+// isReplayable returns true if handling the action on the previous snapshot of the
+// game will always result in the same game state. Dealing shuffles a new deck, so it
+// can never be replayed. Games from before we kept the deck order cut from a random
+// deck, so we don't replay cuts either. Takebacks restore an earlier snapshot, so
+// we keep the snapshots after them too. In provably fair games, counting the crib
+// starts the next round with a new secret seed, which cannot be replayed.
+func isReplayable(g model.Game, pa model.PlayerAction) bool {
+	switch pa.Overcomes {
+	case model.DealCards, model.CutCard, model.RequestTakeback, model.ApproveTakeback:
+		return false
+	case model.CountCrib:
+		return !g.Settings.ProvablyFair
+	}
+	return true
+}
+
+// Replayer handles each of the actions on the game, in order. persistence doesn't
+// know how to play the game, so the factories are given one to rebuild the snapshots
+// that have been compacted. Without one, GetGameAction returns ErrGameSnapshotCompacted
+// for them.
+type Replayer func(ctx context.Context, g *model.Game, actions []model.PlayerAction) error
+
+// replay handles each of the actions on a copy of the checkpoint game
+func (r Replayer) replay(ctx context.Context, checkpoint model.Game, actions []model.PlayerAction) (model.Game, error) {
+	if r == nil {
+		return model.Game{}, ErrGameSnapshotCompacted
+	}
+
+	g := CopyGame(checkpoint)
+	err := r(ctx, &g, actions)
+	if err != nil {
+		return model.Game{}, err
+	}
+
+	return g, nil
+}
+
+// CopyGame returns a copy of the game which does not share any memory with the input
+func CopyGame(src model.Game) model.Game {
+	dst := src
+
+	if src.Players != nil {
+		dst.Players = make([]model.Player, len(src.Players))
+		for i, p := range src.Players {
+			dst.Players[i] = p
+			if p.Games != nil {
+				dst.Players[i].Games = make(map[model.GameID]model.PlayerColor, len(p.Games))
+				for k, v := range p.Games {
+					dst.Players[i].Games[k] = v
+				}
+			}
+		}
+	}
+
+	if src.BlockingPlayers != nil {
+		dst.BlockingPlayers = make(map[model.PlayerID]model.Blocker, len(src.BlockingPlayers))
+		for k, v := range src.BlockingPlayers {
+			dst.BlockingPlayers[k] = v
+		}
+	}
+
+	if src.PlayerColors != nil {
+		dst.PlayerColors = make(map[model.PlayerID]model.PlayerColor, len(src.PlayerColors))
+		for k, v := range src.PlayerColors {
+			dst.PlayerColors[k] = v
+		}
+	}
+
+	if src.CurrentScores != nil {
+		dst.CurrentScores = make(map[model.PlayerColor]int, len(src.CurrentScores))
+		for k, v := range src.CurrentScores {
+			dst.CurrentScores[k] = v
+		}
+	}
+
+	if src.LagScores != nil {
+		dst.LagScores = make(map[model.PlayerColor]int, len(src.LagScores))
+		for k, v := range src.LagScores {
+			dst.LagScores[k] = v
+		}
+	}
+
+	if src.Hands != nil {
+		dst.Hands = make(map[model.PlayerID][]model.Card, len(src.Hands))
+		for k, v := range src.Hands {
+			dst.Hands[k] = append(make([]model.Card, 0, len(v)), v...)
+		}
+	}
+
+	if src.Crib != nil {
+		dst.Crib = append(make([]model.Card, 0, len(src.Crib)), src.Crib...)
+	}
+
+	if src.DeckOrder != nil {
+		dst.DeckOrder = append(make([]model.Card, 0, len(src.DeckOrder)), src.DeckOrder...)
+	}
+
+	if src.Shuffles != nil {
+		dst.Shuffles = append(make([]model.ShuffleProof, 0, len(src.Shuffles)), src.Shuffles...)
+	}
+
+	if src.PeggedCards != nil {
+		dst.PeggedCards = append(make([]model.PeggedCard, 0, len(src.PeggedCards)), src.PeggedCards...)
+	}
+
+	if src.Pegging.Goes != nil {
+		dst.Pegging.Goes = append(make([]model.PlayerID, 0, len(src.Pegging.Goes)), src.Pegging.Goes...)
+	}
+
+	if src.Actions != nil {
+		dst.Actions = append(make([]model.PlayerAction, 0, len(src.Actions)), src.Actions...)
+	}
+
+	return dst
+}
@@ -0,0 +1,84 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+)
+
+func TestSnapshotsToCompact(t *testing.T) {
+	blockers := []model.Blocker{
+		model.DealCards,
+		model.CribCard,
+		model.CribCard,
+		model.CutCard,
+		model.PegCard,
+		model.PegCard,
+		model.PegCard,
+		model.PegCard,
+		model.PegCard,
+		model.CountHand,
+		model.CountHand,
+		model.CountCrib,
+		model.DealCards,
+		model.CribCard,
+	}
+	g := model.Game{}
+	for _, b := range blockers {
+		g.AddAction(model.PlayerAction{
+			Overcomes: b,
+		})
+	}
+
+	testCases := []struct {
+		msg string
+		rp  RetentionPolicy
+		exp []uint
+	}{{
+		msg: `no checkpoint interval`,
+		rp:  RetentionPolicy{},
+		exp: []uint{2, 3, 5, 6, 7, 8, 9, 10, 11, 12},
+	}, {
+		msg: `checkpoint every third`,
+		rp:  RetentionPolicy{CheckpointInterval: 3},
+		exp: []uint{2, 5, 7, 8, 10, 11},
+	}, {
+		msg: `checkpoint every snapshot`,
+		rp:  RetentionPolicy{CheckpointInterval: 1},
+		exp: nil,
+	}}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.exp, tc.rp.SnapshotsToCompact(g), tc.msg)
+	}
+
+	// counting the crib commits to a new secret seed in provably fair games
+	g.Settings.ProvablyFair = true
+	assert.Equal(t, []uint{2, 3, 5, 6, 7, 8, 9, 10, 11}, RetentionPolicy{}.SnapshotsToCompact(g))
+}
+
+func TestReplayerReplaysOnACopy(t *testing.T) {
+	checkpoint := model.Game{
+		ID: model.GameID(3),
+	}
+	actions := []model.PlayerAction{{
+		GameID:    checkpoint.ID,
+		Overcomes: model.PegCard,
+	}}
+
+	_, err := Replayer(nil).replay(context.Background(), checkpoint, actions)
+	assert.Equal(t, ErrGameSnapshotCompacted, err, `a factory without a replayer can't rebuild snapshots`)
+
+	var r Replayer = func(_ context.Context, g *model.Game, pas []model.PlayerAction) error {
+		g.Actions = append(g.Actions, pas...)
+		return nil
+	}
+	g, err := r.replay(context.Background(), checkpoint, actions)
+	require.NoError(t, err)
+	assert.Equal(t, actions, g.Actions)
+	assert.Empty(t, checkpoint.Actions)
+}
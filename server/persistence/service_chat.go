@@ -0,0 +1,14 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+)
+
+type ChatService interface {
+	// Get returns the chat history of the game, oldest first
+	Get(ctx context.Context, gID model.GameID) ([]model.ChatMessage, error)
+
+	Add(ctx context.Context, cm model.ChatMessage) error
+}
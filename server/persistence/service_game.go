@@ -1,14 +1,35 @@
 package persistence
 
 import (
+	"context"
+	"time"
+
 	"github.com/joshprzybyszewski/cribbage/model"
 )
 
 type GameService interface {
-	Get(id model.GameID) (model.Game, error)
-	GetAt(id model.GameID, numActions uint) (model.Game, error)
+	Get(ctx context.Context, id model.GameID) (model.Game, error)
+	GetAt(ctx context.Context, id model.GameID, numActions uint) (model.Game, error)
+
+	UpdatePlayerColor(ctx context.Context, id model.GameID, pID model.PlayerID, color model.PlayerColor) error
+	Begin(ctx context.Context, g model.Game) error
+	Save(ctx context.Context, g model.Game) error
+
+	// Compact throws away the snapshots of the game at each of the given numbers of actions.
+	// Afterwards, GetAt should return ErrGameSnapshotCompacted for those snapshots, and
+	// GetUncompacted should no longer return the game.
+	Compact(ctx context.Context, id model.GameID, numActions []uint) error
+	// GetUncompacted returns the IDs of the games which are over, but have not been compacted
+	GetUncompacted(ctx context.Context) ([]model.GameID, error)
+	// CountActive returns how many of the games are not over yet
+	CountActive(ctx context.Context) (int, error)
 
-	UpdatePlayerColor(id model.GameID, pID model.PlayerID, color model.PlayerColor) error
-	Begin(g model.Game) error
-	Save(g model.Game) error
+	// SetMoveDeadline saves when the game's blocking players run out of time to act.
+	// The zero time clears the deadline.
+	SetMoveDeadline(ctx context.Context, id model.GameID, deadline time.Time) error
+	// GetMoveDeadline returns when the game's blocking players run out of time to act.
+	// It is the zero time when they have all the time they want.
+	GetMoveDeadline(ctx context.Context, id model.GameID) (time.Time, error)
+	// GetOverdue returns the IDs of the games whose deadline is before now
+	GetOverdue(ctx context.Context, now time.Time) ([]model.GameID, error)
 }
@@ -1,13 +1,15 @@
 package persistence
 
 import (
+	"context"
+
 	"github.com/joshprzybyszewski/cribbage/model"
 	"github.com/joshprzybyszewski/cribbage/server/interaction"
 )
 
 type InteractionService interface {
-	Get(id model.PlayerID) (interaction.PlayerMeans, error)
+	Get(ctx context.Context, id model.PlayerID) (interaction.PlayerMeans, error)
 
-	Create(pm interaction.PlayerMeans) error
-	Update(pm interaction.PlayerMeans) error
+	Create(ctx context.Context, pm interaction.PlayerMeans) error
+	Update(ctx context.Context, pm interaction.PlayerMeans) error
 }
@@ -0,0 +1,19 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+)
+
+type LobbyService interface {
+	Get(ctx context.Context, id model.LobbyID) (model.Lobby, error)
+	// GetOpen returns all of the lobbies that are waiting for players
+	GetOpen(ctx context.Context) ([]model.Lobby, error)
+
+	Create(ctx context.Context, l model.Lobby) error
+	// Update saves the lobby at l.Version+1, as long as the saved lobby is still at
+	// l.Version. Otherwise, it returns ErrLobbyConflict, and the caller should read
+	// the lobby again before retrying.
+	Update(ctx context.Context, l model.Lobby) error
+}
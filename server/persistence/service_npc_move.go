@@ -0,0 +1,20 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/joshprzybyszewski/cribbage/server/npcqueue"
+)
+
+type NPCMoveService interface {
+	// Add stores the move. It returns false, and stores nothing, when there is already
+	// a move for the NPC in that game at the same (or a later) number of actions.
+	Add(ctx context.Context, j npcqueue.Job) (bool, error)
+	// GetDue returns the moves whose next attempt is at or before now, oldest first
+	GetDue(ctx context.Context, now time.Time) ([]npcqueue.Job, error)
+	// Update replaces the move with the same key. It does nothing if that move is gone.
+	Update(ctx context.Context, j npcqueue.Job) error
+	// Remove drops the move with the key. It does nothing if that move is gone.
+	Remove(ctx context.Context, k npcqueue.Key) error
+}
@@ -1,14 +1,16 @@
 package persistence
 
 import (
+	"context"
+
 	"github.com/joshprzybyszewski/cribbage/model"
 )
 
 type PlayerService interface {
-	Get(id model.PlayerID) (model.Player, error)
+	Get(ctx context.Context, id model.PlayerID) (model.Player, error)
 
-	Create(p model.Player) error
-	UpdateGameColor(id model.PlayerID, gID model.GameID, color model.PlayerColor) error
+	Create(ctx context.Context, p model.Player) error
+	UpdateGameColor(ctx context.Context, id model.PlayerID, gID model.GameID, color model.PlayerColor) error
 
-	BeginGame(gID model.GameID, ps []model.Player) error
+	BeginGame(ctx context.Context, gID model.GameID, ps []model.Player) error
 }
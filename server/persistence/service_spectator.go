@@ -0,0 +1,16 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+)
+
+type SpectatorService interface {
+	// Get returns everyone who is watching the game, sorted by their PlayerID
+	Get(ctx context.Context, gID model.GameID) ([]model.Spectator, error)
+
+	// Save starts watching the game, or updates the spectator if they were already watching
+	Save(ctx context.Context, s model.Spectator) error
+	Remove(ctx context.Context, gID model.GameID, pID model.PlayerID) error
+}
@@ -0,0 +1,70 @@
+package play
+
+import (
+	"github.com/joshprzybyszewski/cribbage/logic/scorer"
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/interaction"
+)
+
+// ForcedAction returns the next action that the server should play on behalf of a
+// blocking player who has opted into auto-play. It only returns true when that
+// action is the single legal move the player has.
+func ForcedAction(
+	g *model.Game,
+	pAPIs map[model.PlayerID]interaction.Player,
+) (model.PlayerAction, bool) {
+
+	if g.IsOver() || isTakebackPending(g) {
+		return model.PlayerAction{}, false
+	}
+
+	// go in seat order so that we always pick the same player first
+	for _, p := range g.Players {
+		b, ok := g.BlockingPlayers[p.ID]
+		if !ok || !interaction.AutoPlays(pAPIs[p.ID]) {
+			continue
+		}
+
+		a, ok := forcedMove(g, p.ID, b)
+		if !ok {
+			continue
+		}
+
+		return model.PlayerAction{
+			GameID:    g.ID,
+			ID:        p.ID,
+			Overcomes: b,
+			Action:    a,
+			Auto:      true,
+		}, true
+	}
+
+	return model.PlayerAction{}, false
+}
+
+// forcedMove returns the action for the blocker if the player has no other choice
+func forcedMove(g *model.Game, pID model.PlayerID, b model.Blocker) (interface{}, bool) {
+	switch b {
+	case model.PegCard:
+		playable := playableCards(g, pID)
+		switch len(playable) {
+		case 0:
+			return model.PegAction{SayGo: true}, true
+		case 1:
+			return model.PegAction{Card: playable[0]}, true
+		}
+	case model.CountHand:
+		return model.CountHandAction{
+			Pts: scorer.HandPoints(g.CutCard, g.Hands[pID]),
+		}, true
+	case model.CountCrib:
+		if pID != g.CurrentDealer {
+			return nil, false
+		}
+		return model.CountCribAction{
+			Pts: scorer.CribPoints(g.CutCard, g.Crib),
+		}, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,125 @@
+package play
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/interaction"
+	"github.com/joshprzybyszewski/cribbage/utils/testutils"
+)
+
+func TestForcedAction_Pegging(t *testing.T) {
+	alice, bob, _, _ := testutils.AliceBobCharlieDiane()
+	g, pAPIs := newPeggingGame(t, []model.Player{alice, bob}, [][]string{
+		{`ks`, `qs`, `9d`, `4c`},
+		{`kh`, `qh`, `8c`, `3d`},
+	})
+	peg := func(pID model.PlayerID, c string) {
+		require.NoError(t, HandleAction(context.Background(), &g, model.PlayerAction{
+			GameID:    g.ID,
+			ID:        pID,
+			Overcomes: model.PegCard,
+			Action:    model.PegAction{Card: model.NewCardFromString(c)},
+		}, pAPIs))
+	}
+
+	pAPIs[alice.ID] = interaction.WithAutoPlay(pAPIs[alice.ID])
+	_, ok := ForcedAction(&g, pAPIs)
+	assert.False(t, ok, `alice can play any of her cards`)
+
+	peg(alice.ID, `ks`)
+	peg(bob.ID, `kh`)
+	peg(alice.ID, `qs`)
+
+	_, ok = ForcedAction(&g, pAPIs)
+	assert.False(t, ok, `bob has to say go, but he plays his own moves`)
+
+	pAPIs[bob.ID] = interaction.WithAutoPlay(pAPIs[bob.ID])
+	pa, ok := ForcedAction(&g, pAPIs)
+	require.True(t, ok)
+	assert.Equal(t, model.PlayerAction{
+		GameID:    g.ID,
+		ID:        bob.ID,
+		Overcomes: model.PegCard,
+		Action:    model.PegAction{SayGo: true},
+		Auto:      true,
+	}, pa)
+	require.NoError(t, HandleAction(context.Background(), &g, pa, pAPIs))
+
+	pa, ok = ForcedAction(&g, pAPIs)
+	require.True(t, ok)
+	assert.Equal(t, alice.ID, pa.ID)
+	assert.Equal(t, model.PegAction{SayGo: true}, pa.Action)
+	require.NoError(t, HandleAction(context.Background(), &g, pa, pAPIs))
+
+	_, ok = ForcedAction(&g, pAPIs)
+	assert.False(t, ok, `bob leads the next series, and can play any of his cards`)
+
+	peg(bob.ID, `qh`)
+	peg(alice.ID, `9d`)
+	peg(bob.ID, `8c`)
+
+	pa, ok = ForcedAction(&g, pAPIs)
+	require.True(t, ok)
+	assert.Equal(t, model.PegAction{Card: model.NewCardFromString(`4c`)}, pa.Action, `alice has one card left`)
+}
+
+func TestForcedAction_Counting(t *testing.T) {
+	alice, bob, _, _ := testutils.AliceBobCharlieDiane()
+	g, pAPIs := newPeggingGame(t, []model.Player{alice, bob}, [][]string{
+		{`5s`, `5h`, `jd`, `4c`},
+		{`kh`, `qh`, `8c`, `3d`},
+	})
+	g.Crib = []model.Card{
+		model.NewCardFromString(`5c`),
+		model.NewCardFromString(`6c`),
+		model.NewCardFromString(`as`),
+		model.NewCardFromString(`2d`),
+	}
+	pAPIs[alice.ID] = interaction.WithAutoPlay(pAPIs[alice.ID])
+	pAPIs[bob.ID] = interaction.WithAutoPlay(pAPIs[bob.ID])
+
+	for g.Phase < model.CountingReady {
+		pa, ok := ForcedAction(&g, pAPIs)
+		if !ok {
+			for pID := range g.BlockingPlayers {
+				pa = model.PlayerAction{
+					GameID:    g.ID,
+					ID:        pID,
+					Overcomes: model.PegCard,
+					Action:    model.PegAction{Card: playableCards(&g, pID)[0]},
+				}
+			}
+		}
+		require.NoError(t, HandleAction(context.Background(), &g, pa, pAPIs))
+	}
+	numPegs := g.NumActions()
+
+	for {
+		pa, ok := ForcedAction(&g, pAPIs)
+		if !ok {
+			break
+		}
+		require.NoError(t, HandleAction(context.Background(), &g, pa, pAPIs))
+	}
+
+	assert.Equal(t, model.Deal, g.Phase, `both hands and the crib were counted`)
+	require.Equal(t, numPegs+3, g.NumActions())
+	counts := g.Actions[numPegs:]
+	assert.Equal(t, alice.ID, counts[0].ID)
+	assert.Equal(t, model.CountHandAction{Pts: 7}, counts[0].Action)
+	assert.Equal(t, bob.ID, counts[1].ID)
+	assert.Equal(t, model.CountHandAction{Pts: 2}, counts[1].Action)
+	assert.Equal(t, bob.ID, counts[2].ID)
+	assert.Equal(t, model.CountCribAction{Pts: 7}, counts[2].Action)
+	for _, pa := range counts {
+		assert.True(t, pa.Auto)
+	}
+
+	_, ok := ForcedAction(&g, pAPIs)
+	assert.False(t, ok, `the dealer chooses how to shuffle`)
+}
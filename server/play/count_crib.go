@@ -48,7 +48,7 @@ func (*cribCountingHandler) HandleAction(
 
 	if cca.Pts != pts {
 		addPlayerToBlocker(g, pID, model.CountCrib, pAPIs, `you did not submit the correct number of points for the crib`)
-		return errors.New(`wrong number of points`)
+		return ErrWrongNumberOfPoints
 	}
 
 	addPoints(g, pID, pts, pAPIs, `crib (`+leadCard.String()+`: `+handString(crib)+`)`)
@@ -45,7 +45,7 @@ func (*handCountingHandler) HandleAction(g *model.Game,
 
 	if cha.Pts != pts {
 		addPlayerToBlocker(g, pID, model.CountHand, pAPIs, `you did not submit the correct number of points for your hand`)
-		return errors.New(`wrong number of points`)
+		return ErrWrongNumberOfPoints
 	}
 
 	addPoints(g, pID, pts, pAPIs, `hand (`+leadCard.String()+`: `+handString(hand)+`)`)
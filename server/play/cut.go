@@ -2,10 +2,12 @@ package play
 
 import (
 	"errors"
-	"log"
+
+	"go.uber.org/zap"
 
 	"github.com/joshprzybyszewski/cribbage/model"
 	"github.com/joshprzybyszewski/cribbage/server/interaction"
+	"github.com/joshprzybyszewski/cribbage/server/logging"
 )
 
 var _ PhaseHandler = (*cuttingHandler)(nil)
@@ -51,6 +53,7 @@ func (*cuttingHandler) HandleAction(
 	}
 
 	g.CutCard = c
+	g.RecordCut(cutPercent)
 
 	for _, pAPI := range pAPIs {
 		_ = pAPI.NotifyMessage(*g, "Cut card "+g.CutCard.String())
@@ -83,7 +86,10 @@ func cutActionValidation(
 	}
 
 	if len(g.BlockingPlayers) != 1 {
-		log.Printf("Expected one blocker for cut, but had: %+v\n", g.BlockingPlayers)
+		logging.L().Warn(`expected one blocker for cut`,
+			logging.GameID(g.ID),
+			zap.Any(`blockingPlayers`, g.BlockingPlayers),
+		)
 	}
 	removePlayerFromBlockers(g, action)
 
@@ -2,10 +2,12 @@ package play
 
 import (
 	"errors"
-	"log"
+
+	"go.uber.org/zap"
 
 	"github.com/joshprzybyszewski/cribbage/model"
 	"github.com/joshprzybyszewski/cribbage/server/interaction"
+	"github.com/joshprzybyszewski/cribbage/server/logging"
 )
 
 var _ PhaseHandler = (*dealingHandler)(nil)
@@ -18,9 +20,16 @@ func (*dealingHandler) Start(g *model.Game, pAPIs map[model.PlayerID]interaction
 		g.Hands[pID] = g.Hands[pID][:0]
 	}
 	g.Crib = g.Crib[:0]
+	// the last round's deck order is kept until the next deal shuffles a new one,
+	// so that the snapshot from the end of the round shows how it was dealt
 	g.CutCard = model.Card{}
 	g.PeggedCards = g.PeggedCards[:0]
 
+	if g.Settings.ProvablyFair {
+		// commit to the shuffle before anyone can ask the dealer to deal
+		g.Shuffles = append(g.Shuffles, model.NewShuffleProof())
+	}
+
 	addPlayerToBlocker(g, g.CurrentDealer, model.DealCards, pAPIs, ``)
 
 	return nil
@@ -50,18 +59,23 @@ func (*dealingHandler) HandleAction(g *model.Game,
 	}
 
 	if len(g.BlockingPlayers) != 1 {
-		log.Printf("Expected one blocker for deal, but had: %+v\n", g.BlockingPlayers)
+		logging.L().Warn(`expected one blocker for deal`,
+			logging.GameID(g.ID),
+			zap.Any(`blockingPlayers`, g.BlockingPlayers),
+		)
 	}
 	removePlayerFromBlockers(g, action)
 
-	deck, err := g.GetDeck()
-	if err != nil {
-		return err
-	}
-
-	for i := 0; i < da.NumShuffles; i++ {
-		// shuffle
-		deck.Shuffle()
+	// shuffle a new deck, and keep its order so that the deal can be checked later
+	var deck model.Deck
+	if g.Settings.ProvablyFair {
+		var err error
+		deck, err = g.ShuffleProvablyFairDeck(da.Entropy)
+		if err != nil {
+			return err
+		}
+	} else {
+		deck = g.ShuffleDeck(da.NumShuffles)
 	}
 
 	// deal
@@ -76,13 +90,8 @@ func deal(g *model.Game, deck model.Deck, pAPIs map[model.PlayerID]interaction.P
 	// Get the order of players we need to deal to
 	pIDs := playersToDealTo(g)
 
-	// Define how many cards we need to deal and the hand size
-	handSize := 6
-	switch len(pIDs) {
-	case 3, 4:
-		handSize = 5
-	}
-	numCardsToDeal := handSize * len(pIDs)
+	// Define how many cards we need to deal
+	numCardsToDeal := model.HandSize(len(pIDs)) * len(pIDs)
 
 	for numDealt := 0; numDealt < numCardsToDeal; {
 		for _, pID := range pIDs {
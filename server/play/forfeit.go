@@ -0,0 +1,50 @@
+package play
+
+import (
+	"errors"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/interaction"
+)
+
+// handleForfeit ends the game on behalf of the player who took the action.
+// A player can forfeit at any time, regardless of who the game is waiting on.
+func handleForfeit(
+	g *model.Game,
+	action model.PlayerAction,
+	pAPIs map[model.PlayerID]interaction.Player,
+) error {
+
+	fa, ok := action.Action.(model.ForfeitAction)
+	if !ok {
+		return errors.New(`tried forfeiting with a different action`)
+	}
+
+	name := string(action.ID)
+	for _, p := range g.Players {
+		if p.ID == action.ID {
+			name = p.Name
+			break
+		}
+	}
+
+	reason := model.Forfeited
+	msg := name + ` forfeited`
+	if fa.TimedOut {
+		reason = model.TimedOut
+		msg = name + ` took too long and forfeited`
+	}
+
+	g.Outcome = model.GameOutcome{
+		Reason:   reason,
+		PlayerID: action.ID,
+	}
+	g.BlockingPlayers = map[model.PlayerID]model.Blocker{}
+	g.AddAction(action)
+
+	for _, pAPI := range pAPIs {
+		_ = pAPI.NotifyMessage(*g, msg)
+	}
+
+	return nil
+}
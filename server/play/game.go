@@ -1,19 +1,36 @@
 package play
 
 import (
+	"context"
 	"errors"
 
 	"github.com/joshprzybyszewski/cribbage/model"
 	"github.com/joshprzybyszewski/cribbage/server/interaction"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+	"github.com/joshprzybyszewski/cribbage/server/tracing"
 )
 
 var (
 	ErrActionNotForGame error = errors.New(`action not for game`)
 	ErrPlayerNotInGame  error = errors.New(`player is not in this game`)
 	ErrGameAlreadyOver  error = errors.New(`game is already over`)
+
+	ErrNotBlockedByPlayer  error = errors.New(`Game is not blocked by this player`)
+	ErrWrongBlocker        error = errors.New(`action overcomes the wrong blocker`)
+	ErrWrongNumberOfPoints error = errors.New(`wrong number of points`)
 )
 
 func CreateGame(players []model.Player, pAPIs map[model.PlayerID]interaction.Player) (model.Game, error) {
+	return CreateGameWithSettings(context.Background(), players, pAPIs, model.GameSettings{})
+}
+
+// CreateGameWithSettings starts a game which uses the given settings from its first deal
+func CreateGameWithSettings(
+	ctx context.Context,
+	players []model.Player,
+	pAPIs map[model.PlayerID]interaction.Player,
+	settings model.GameSettings,
+) (model.Game, error) {
 	playersCopy := make([]model.Player, len(players))
 	colorsByID := make(map[model.PlayerID]model.PlayerColor, len(players))
 	curScores := make(map[model.PlayerColor]int, len(players))
@@ -50,9 +67,10 @@ func CreateGame(players []model.Player, pAPIs map[model.PlayerID]interaction.Pla
 		CutCard:         model.Card{},
 		Crib:            make([]model.Card, 0, 4),
 		PeggedCards:     make([]model.PeggedCard, 0, 4*len(players)),
+		Settings:        settings,
 	}
 
-	err := runStartHandlers(&g, pAPIs)
+	err := runStartHandlers(ctx, &g, pAPIs)
 	if err != nil {
 		return model.Game{}, err
 	}
@@ -77,26 +95,32 @@ var (
 	}
 )
 
-func HandleAction(g *model.Game,
+func HandleAction(
+	ctx context.Context,
+	g *model.Game,
 	action model.PlayerAction,
 	pAPIs map[model.PlayerID]interaction.Player,
-) error {
+) (err error) {
+	ctx, span := tracing.Start(ctx, `play.HandleAction`,
+		tracing.GameID(g.ID),
+		tracing.PlayerID(action.ID),
+		tracing.Blocker(action.Overcomes),
+	)
+	defer func() { tracing.End(span, err) }()
 
-	if g.ID != action.GameID {
-		return ErrActionNotForGame
+	err = validatePlayerAction(g, action)
+	if err != nil {
+		return err
 	}
-	playerIsInGame := false
-	for i := range g.Players {
-		if g.Players[i].ID == action.ID {
-			playerIsInGame = true
-			break
-		}
+	if IsTakeback(action) {
+		return ErrTakebackNeedsSnapshots
 	}
-	if !playerIsInGame {
-		return ErrPlayerNotInGame
+	if action.Overcomes == model.Forfeit {
+		return handleForfeit(g, action, withSpans(ctx, pAPIs))
 	}
-	if g.IsOver() {
-		return ErrGameAlreadyOver
+	if isTakebackPending(g) {
+		// nobody can play on until the takeback has been answered
+		return ErrTakebackPending
 	}
 	switch p := g.Phase; p {
 	case model.Deal,
@@ -105,7 +129,7 @@ func HandleAction(g *model.Game,
 		model.Pegging,
 		model.Counting,
 		model.CribCounting:
-		err := handlers[p].HandleAction(g, action, pAPIs)
+		err = handlePhaseAction(ctx, handlers[p], g, action, pAPIs)
 		if err != nil {
 			return err
 		}
@@ -120,10 +144,72 @@ func HandleAction(g *model.Game,
 		g.Phase++
 	}
 
-	return runStartHandlers(g, pAPIs)
+	return runStartHandlers(ctx, g, pAPIs)
 }
 
-func runStartHandlers(g *model.Game, pAPIs map[model.PlayerID]interaction.Player) error {
+// Replay handles each of the actions on the game, in order. The actions have already
+// happened, so none of the players are told about them again. Takebacks go back to
+// the snapshots from earlier in the replay, so they can't undo an action from before
+// the game that the replay started with.
+func Replay(ctx context.Context, g *model.Game, actions []model.PlayerAction) error {
+	pAPIs := make(map[model.PlayerID]interaction.Player, len(g.Players))
+	for _, p := range g.Players {
+		pAPIs[p.ID] = interaction.Empty(p.ID)
+	}
+
+	replayed := map[uint]model.Game{
+		uint(g.NumActions()): persistence.CopyGame(*g),
+	}
+	snapshots := func(numActions uint) (model.Game, error) {
+		s, ok := replayed[numActions]
+		if !ok {
+			return model.Game{}, ErrSnapshotNotReplayed
+		}
+		return persistence.CopyGame(s), nil
+	}
+
+	for _, pa := range actions {
+		var err error
+		if IsTakeback(pa) {
+			err = HandleTakeback(ctx, g, pa, pAPIs, snapshots)
+		} else {
+			err = HandleAction(ctx, g, pa, pAPIs)
+		}
+		if err != nil {
+			return err
+		}
+		replayed[uint(g.NumActions())] = persistence.CopyGame(*g)
+	}
+
+	return nil
+}
+
+// validatePlayerAction checks that the player can act in this game at all
+func validatePlayerAction(g *model.Game, action model.PlayerAction) error {
+	if g.ID != action.GameID {
+		return ErrActionNotForGame
+	}
+	playerIsInGame := false
+	for i := range g.Players {
+		if g.Players[i].ID == action.ID {
+			playerIsInGame = true
+			break
+		}
+	}
+	if !playerIsInGame {
+		return ErrPlayerNotInGame
+	}
+	if g.IsOver() {
+		return ErrGameAlreadyOver
+	}
+	return nil
+}
+
+func runStartHandlers(
+	ctx context.Context,
+	g *model.Game,
+	pAPIs map[model.PlayerID]interaction.Player,
+) error {
 	switch p := g.Phase; p {
 	case model.BuildCribReady,
 		model.CutReady,
@@ -131,7 +217,7 @@ func runStartHandlers(g *model.Game, pAPIs map[model.PlayerID]interaction.Player
 		model.CountingReady,
 		model.CribCountingReady,
 		model.DealingReady:
-		err := handlers[p].Start(g, pAPIs)
+		err := startPhase(ctx, handlers[p], g, pAPIs)
 		if err != nil {
 			return err
 		}
@@ -143,3 +229,43 @@ func runStartHandlers(g *model.Game, pAPIs map[model.PlayerID]interaction.Player
 
 	return nil
 }
+
+// handlePhaseAction has the phase's handler take the action, in a span of its own
+func handlePhaseAction(
+	ctx context.Context,
+	ph PhaseHandler,
+	g *model.Game,
+	action model.PlayerAction,
+	pAPIs map[model.PlayerID]interaction.Player,
+) error {
+	ctx, span := tracing.Start(ctx, `play.PhaseHandler.HandleAction`, tracing.Phase(g.Phase))
+	err := ph.HandleAction(g, action, withSpans(ctx, pAPIs))
+	tracing.End(span, err)
+	return err
+}
+
+// startPhase has the phase's handler start it, in a span of its own
+func startPhase(
+	ctx context.Context,
+	ph PhaseHandler,
+	g *model.Game,
+	pAPIs map[model.PlayerID]interaction.Player,
+) error {
+	ctx, span := tracing.Start(ctx, `play.PhaseHandler.Start`, tracing.Phase(g.Phase))
+	err := ph.Start(g, withSpans(ctx, pAPIs))
+	tracing.End(span, err)
+	return err
+}
+
+// withSpans returns the players, whose notifications are traced as children of
+// the span in the context
+func withSpans(
+	ctx context.Context,
+	pAPIs map[model.PlayerID]interaction.Player,
+) map[model.PlayerID]interaction.Player {
+	spanned := make(map[model.PlayerID]interaction.Player, len(pAPIs))
+	for pID, pAPI := range pAPIs {
+		spanned[pID] = interaction.WithSpans(ctx, pAPI, tracing.PlayerID(pID))
+	}
+	return spanned
+}
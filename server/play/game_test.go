@@ -1,6 +1,7 @@
 package play
 
 import (
+	"context"
 	"strings"
 	"testing"
 
@@ -38,19 +39,19 @@ func TestHandleAction_InvalidINputs(t *testing.T) {
 		},
 	}
 
-	err := HandleAction(&g, action, abAPIs)
+	err := HandleAction(context.Background(), &g, action, abAPIs)
 	assert.Equal(t, ErrActionNotForGame, err)
 
 	action.GameID = g.ID
 	action.ID = model.PlayerID(`dne`)
 
-	err = HandleAction(&g, action, abAPIs)
+	err = HandleAction(context.Background(), &g, action, abAPIs)
 	assert.Equal(t, ErrPlayerNotInGame, err)
 
 	action.ID = alice.ID
 	g.CurrentScores[model.Blue] = 121
 
-	err = HandleAction(&g, action, abAPIs)
+	err = HandleAction(context.Background(), &g, action, abAPIs)
 	assert.Equal(t, ErrGameAlreadyOver, err)
 }
 
@@ -84,7 +85,7 @@ func TestHandleAction_Deal(t *testing.T) {
 	bobAPI.On(`NotifyMessage`, mock.AnythingOfType(`model.Game`), mock.MatchedBy(func(s string) bool { return strings.HasPrefix(s, `Received Hand `) })).Return(nil).Once()
 	bobAPI.On(`NotifyBlocking`, model.CribCard, mock.AnythingOfType(`model.Game`), `needs to cut 2 cards`).Return(nil).Once()
 
-	err := HandleAction(&g, action, abAPIs)
+	err := HandleAction(context.Background(), &g, action, abAPIs)
 	assert.Nil(t, err)
 	assert.Equal(t, model.BuildCrib, g.Phase)
 	assert.Equal(t, g.NumActions(), 1)
@@ -95,6 +96,12 @@ func TestHandleAction_Deal(t *testing.T) {
 	// the players should have 6 card hands
 	assert.Len(t, g.Hands[alice.ID], 6)
 	assert.Len(t, g.Hands[bob.ID], 6)
+	// the cards are dealt off of the top of the deck, starting with bob
+	require.Len(t, g.DeckOrder, model.NumCardsPerDeck)
+	for i := 0; i < 6; i++ {
+		assert.Equal(t, g.DeckOrder[2*i], g.Hands[bob.ID][i])
+		assert.Equal(t, g.DeckOrder[2*i+1], g.Hands[alice.ID][i])
+	}
 	// assert that entering the build crib phase has cleared out the crib
 	assert.Empty(t, g.Crib)
 
@@ -102,6 +109,49 @@ func TestHandleAction_Deal(t *testing.T) {
 	bobAPI.AssertExpectations(t)
 }
 
+func TestHandleAction_DealProvablyFair(t *testing.T) {
+	alice, bob, aliceAPI, bobAPI, abAPIs := testutils.AliceAndBob()
+
+	aliceAPI.On(`NotifyBlocking`, model.DealCards, mock.AnythingOfType(`model.Game`), ``).Return(nil).Once()
+	g, err := CreateGameWithSettings(context.Background(), []model.Player{alice, bob}, abAPIs, model.GameSettings{
+		ProvablyFair: true,
+	})
+	require.NoError(t, err)
+	// the server commits to the shuffle before the dealer deals
+	require.Len(t, g.Shuffles, 1)
+	assert.NotEmpty(t, g.Shuffles[0].Commitment)
+	assert.Empty(t, g.DeckOrder)
+
+	action := model.PlayerAction{
+		GameID:    g.ID,
+		ID:        alice.ID,
+		Overcomes: model.DealCards,
+		Action: model.DealAction{
+			NumShuffles: 1,
+			Entropy:     `alice's lucky socks`,
+		},
+	}
+	aliceAPI.On(`NotifyMessage`, mock.AnythingOfType(`model.Game`), mock.MatchedBy(func(s string) bool { return strings.HasPrefix(s, `Received Hand `) })).Return(nil).Once()
+	aliceAPI.On(`NotifyBlocking`, model.CribCard, mock.AnythingOfType(`model.Game`), `needs to cut 2 cards`).Return(nil).Once()
+	bobAPI.On(`NotifyMessage`, mock.AnythingOfType(`model.Game`), mock.MatchedBy(func(s string) bool { return strings.HasPrefix(s, `Received Hand `) })).Return(nil).Once()
+	bobAPI.On(`NotifyBlocking`, model.CribCard, mock.AnythingOfType(`model.Game`), `needs to cut 2 cards`).Return(nil).Once()
+
+	err = HandleAction(context.Background(), &g, action, abAPIs)
+	require.NoError(t, err)
+	require.Len(t, g.Shuffles, 1)
+	assert.Equal(t, `alice's lucky socks`, g.Shuffles[0].ClientEntropy)
+
+	// anyone with the revealed seed can recompute the deal
+	order, err := model.VerifyShuffle(g.Shuffles[0])
+	require.NoError(t, err)
+	assert.Equal(t, order, g.DeckOrder)
+	assert.Equal(t, model.DealtCards(order, 2, 0), g.Hands[bob.ID])
+	assert.Equal(t, model.DealtCards(order, 2, 1), g.Hands[alice.ID])
+
+	aliceAPI.AssertExpectations(t)
+	bobAPI.AssertExpectations(t)
+}
+
 func TestHandleAction_Crib(t *testing.T) {
 	alice, bob, aliceAPI, bobAPI, abAPIs := testutils.AliceAndBob()
 
@@ -149,7 +199,7 @@ func TestHandleAction_Crib(t *testing.T) {
 		},
 	}
 
-	err := HandleAction(&g, action, abAPIs)
+	err := HandleAction(context.Background(), &g, action, abAPIs)
 	assert.Nil(t, err)
 	assert.Equal(t, model.BuildCrib, g.Phase)
 	assert.Equal(t, g.NumActions(), 1)
@@ -175,7 +225,7 @@ func TestHandleAction_Crib(t *testing.T) {
 	}
 	bobAPI.On(`NotifyBlocking`, model.CutCard, mock.AnythingOfType(`model.Game`), ``).Return(nil).Once()
 
-	err = HandleAction(&g, action, abAPIs)
+	err = HandleAction(context.Background(), &g, action, abAPIs)
 	assert.Nil(t, err)
 	assert.Equal(t, model.Cut, g.Phase)
 	assert.Equal(t, g.NumActions(), 2)
@@ -241,6 +291,15 @@ func TestHandleAction_Cut(t *testing.T) {
 		},
 		PeggedCards: make([]model.PeggedCard, 0, 8),
 	}
+	// the dealt cards came off the top of the deck, and the rest are still in it
+	dealt := append(append(append([]model.Card{}, g.Hands[bob.ID]...), g.Hands[alice.ID]...), g.Crib...)
+	g.DeckOrder = append(g.DeckOrder, dealt...)
+	for i := 0; i < model.NumCardsPerDeck; i++ {
+		if c := model.NewCardFromNumber(i); !isSuperSet(dealt, []model.Card{c}) {
+			g.DeckOrder = append(g.DeckOrder, c)
+		}
+	}
+	g.Shuffles = []model.ShuffleProof{model.NewShuffleProof()}
 
 	action := model.PlayerAction{
 		GameID:    g.ID,
@@ -257,7 +316,7 @@ func TestHandleAction_Cut(t *testing.T) {
 	aliceAPI.On(`NotifyScoreUpdate`, mock.AnythingOfType(`model.Game`), []string{`his nibs`}).Return(nil).Maybe()
 	bobAPI.On(`NotifyScoreUpdate`, mock.AnythingOfType(`model.Game`), []string{`his nibs`}).Return(nil).Maybe()
 
-	err := HandleAction(&g, action, abAPIs)
+	err := HandleAction(context.Background(), &g, action, abAPIs)
 	assert.Nil(t, err)
 	assert.Equal(t, model.Pegging, g.Phase)
 	assert.Equal(t, g.NumActions(), 1)
@@ -283,6 +342,13 @@ func TestHandleAction_Cut(t *testing.T) {
 	assert.Contains(t, g.Crib, model.NewCardFromString(`6h`))
 	// verify that entering the cutting phase clears out the cut card until it _is_ cut
 	assert.NotEqual(t, model.Card{}, g.CutCard)
+	// the cut is 31.4% of the way through the 40 cards that were left in the deck
+	assert.Equal(t, g.DeckOrder[12+12], g.CutCard)
+	// and the proof says where it was cut, so the players can find the same card
+	assert.Equal(t, 0.314, g.Shuffles[0].CutPercentage)
+	c, err := model.CutCardFrom(g.DeckOrder, 2, g.Shuffles[0].CutPercentage)
+	require.NoError(t, err)
+	assert.Equal(t, g.CutCard, c)
 
 	aliceAPI.AssertExpectations(t)
 	bobAPI.AssertExpectations(t)
@@ -334,7 +400,7 @@ func TestHandleAction_Pegging(t *testing.T) {
 		},
 	}
 	aliceAPI.On(`NotifyBlocking`, model.PegCard, mock.AnythingOfType(`model.Game`), ``).Return(nil).Once()
-	err := HandleAction(&g, action, abAPIs)
+	err := HandleAction(context.Background(), &g, action, abAPIs)
 	assert.Nil(t, err)
 	assert.Len(t, g.PeggedCards, 1)
 	assert.Contains(t, g.PeggedCards, model.NewPeggedCardFromString(bob.ID, `7c`, g.NumActions()))
@@ -352,7 +418,7 @@ func TestHandleAction_Pegging(t *testing.T) {
 	aliceAPI.On(`NotifyScoreUpdate`, mock.AnythingOfType(`model.Game`), []string{`pegging`}).Return(nil).Once()
 	bobAPI.On(`NotifyScoreUpdate`, mock.AnythingOfType(`model.Game`), []string{`pegging`}).Return(nil).Once()
 	bobAPI.On(`NotifyBlocking`, model.PegCard, mock.AnythingOfType(`model.Game`), ``).Return(nil).Once()
-	err = HandleAction(&g, action, abAPIs)
+	err = HandleAction(context.Background(), &g, action, abAPIs)
 	assert.Nil(t, err)
 	assert.Len(t, g.PeggedCards, 2)
 	assert.Contains(t, g.PeggedCards, model.NewPeggedCardFromString(alice.ID, `7s`, g.NumActions()))
@@ -368,7 +434,7 @@ func TestHandleAction_Pegging(t *testing.T) {
 	}
 
 	aliceAPI.On(`NotifyBlocking`, model.PegCard, mock.AnythingOfType(`model.Game`), ``).Return(nil).Once()
-	err = HandleAction(&g, action, abAPIs)
+	err = HandleAction(context.Background(), &g, action, abAPIs)
 	assert.Nil(t, err)
 	assert.Len(t, g.PeggedCards, 3)
 	assert.Contains(t, g.PeggedCards, model.NewPeggedCardFromString(bob.ID, `9c`, g.NumActions()))
@@ -384,7 +450,7 @@ func TestHandleAction_Pegging(t *testing.T) {
 		},
 	}
 	aliceAPI.On(`NotifyBlocking`, model.PegCard, mock.AnythingOfType(`model.Game`), `Cannot peg same card twice`).Return(nil).Once()
-	err = HandleAction(&g, action, abAPIs)
+	err = HandleAction(context.Background(), &g, action, abAPIs)
 	assert.Nil(t, err)
 	assert.Len(t, g.PeggedCards, 3)
 
@@ -401,7 +467,7 @@ func TestHandleAction_Pegging(t *testing.T) {
 	aliceAPI.On(`NotifyScoreUpdate`, mock.AnythingOfType(`model.Game`), []string{`pegging`}).Return(nil).Once()
 	bobAPI.On(`NotifyScoreUpdate`, mock.AnythingOfType(`model.Game`), []string{`pegging`}).Return(nil).Once()
 	bobAPI.On(`NotifyBlocking`, model.PegCard, mock.AnythingOfType(`model.Game`), ``).Return(nil).Once()
-	err = HandleAction(&g, action, abAPIs)
+	err = HandleAction(context.Background(), &g, action, abAPIs)
 	assert.Nil(t, err)
 	assert.Len(t, g.PeggedCards, 4)
 	assert.Contains(t, g.PeggedCards, model.NewPeggedCardFromString(alice.ID, `8s`, g.NumActions()))
@@ -418,7 +484,7 @@ func TestHandleAction_Pegging(t *testing.T) {
 		},
 	}
 	aliceAPI.On(`NotifyBlocking`, model.PegCard, mock.AnythingOfType(`model.Game`), ``).Return(nil).Once()
-	err = HandleAction(&g, action, abAPIs)
+	err = HandleAction(context.Background(), &g, action, abAPIs)
 	assert.Nil(t, err)
 	assert.Len(t, g.PeggedCards, 5)
 	assert.Contains(t, g.PeggedCards, model.NewPeggedCardFromString(bob.ID, `10c`, g.NumActions()))
@@ -436,7 +502,7 @@ func TestHandleAction_Pegging(t *testing.T) {
 	aliceAPI.On(`NotifyScoreUpdate`, mock.AnythingOfType(`model.Game`), []string{`pegging`}).Return(nil).Once()
 	bobAPI.On(`NotifyScoreUpdate`, mock.AnythingOfType(`model.Game`), []string{`pegging`}).Return(nil).Once()
 	bobAPI.On(`NotifyBlocking`, model.PegCard, mock.AnythingOfType(`model.Game`), ``).Return(nil).Once()
-	err = HandleAction(&g, action, abAPIs)
+	err = HandleAction(context.Background(), &g, action, abAPIs)
 	assert.Nil(t, err)
 	assert.Len(t, g.PeggedCards, 6)
 	assert.Contains(t, g.PeggedCards, model.NewPeggedCardFromString(alice.ID, `10s`, g.NumActions()))
@@ -453,7 +519,7 @@ func TestHandleAction_Pegging(t *testing.T) {
 	}
 
 	aliceAPI.On(`NotifyBlocking`, model.PegCard, mock.AnythingOfType(`model.Game`), ``).Return(nil).Once()
-	err = HandleAction(&g, action, abAPIs)
+	err = HandleAction(context.Background(), &g, action, abAPIs)
 	assert.Nil(t, err)
 	assert.Len(t, g.PeggedCards, 7)
 	assert.Contains(t, g.PeggedCards, model.NewPeggedCardFromString(bob.ID, `jc`, g.NumActions()))
@@ -469,7 +535,7 @@ func TestHandleAction_Pegging(t *testing.T) {
 		},
 	}
 	aliceAPI.On(`NotifyBlocking`, model.PegCard, mock.AnythingOfType(`model.Game`), `Cannot peg card with this value`).Return(nil).Once()
-	err = HandleAction(&g, action, abAPIs)
+	err = HandleAction(context.Background(), &g, action, abAPIs)
 	assert.Nil(t, err)
 	assert.Len(t, g.PeggedCards, 7)
 
@@ -483,11 +549,11 @@ func TestHandleAction_Pegging(t *testing.T) {
 		},
 	}
 	aliceAPI.On(`NotifyBlocking`, model.PegCard, mock.AnythingOfType(`model.Game`), `Cannot peg card you don't have`).Return(nil).Once()
-	err = HandleAction(&g, action, abAPIs)
+	err = HandleAction(context.Background(), &g, action, abAPIs)
 	assert.Nil(t, err)
 	assert.Len(t, g.PeggedCards, 7)
 
-	// alice says go
+	// alice says go, and since bob is out of cards, he scores the go
 	action = model.PlayerAction{
 		GameID:    g.ID,
 		ID:        alice.ID,
@@ -496,25 +562,10 @@ func TestHandleAction_Pegging(t *testing.T) {
 			SayGo: true,
 		},
 	}
-	bobAPI.On(`NotifyBlocking`, model.PegCard, mock.AnythingOfType(`model.Game`), ``).Return(nil).Once()
-	err = HandleAction(&g, action, abAPIs)
-	assert.Nil(t, err)
-	assert.Len(t, g.PeggedCards, 7)
-
-	// bob says go
-	action = model.PlayerAction{
-		GameID:    g.ID,
-		ID:        bob.ID,
-		Overcomes: model.PegCard,
-		Action: model.PegAction{
-			SayGo: true,
-		},
-	}
-	// bob scores a go
 	aliceAPI.On(`NotifyScoreUpdate`, mock.AnythingOfType(`model.Game`), []string{`the go`}).Return(nil).Once()
 	bobAPI.On(`NotifyScoreUpdate`, mock.AnythingOfType(`model.Game`), []string{`the go`}).Return(nil).Once()
 	aliceAPI.On(`NotifyBlocking`, model.PegCard, mock.AnythingOfType(`model.Game`), ``).Return(nil).Once()
-	err = HandleAction(&g, action, abAPIs)
+	err = HandleAction(context.Background(), &g, action, abAPIs)
 	assert.Nil(t, err)
 	assert.Len(t, g.PeggedCards, 7)
 	assert.Equal(t, g.CurrentScores[g.PlayerColors[bob.ID]], 1)
@@ -535,7 +586,7 @@ func TestHandleAction_Pegging(t *testing.T) {
 	bobAPI.On(`NotifyScoreUpdate`, mock.AnythingOfType(`model.Game`), []string{`last card`}).Return(nil).Once()
 	// bob will be up to count his hand
 	bobAPI.On(`NotifyBlocking`, model.CountHand, mock.AnythingOfType(`model.Game`), ``).Return(nil).Once()
-	err = HandleAction(&g, action, abAPIs)
+	err = HandleAction(context.Background(), &g, action, abAPIs)
 	assert.Nil(t, err)
 	assert.Len(t, g.PeggedCards, 8)
 	assert.Contains(t, g.PeggedCards, model.NewPeggedCardFromString(alice.ID, `js`, g.NumActions()))
@@ -591,7 +642,7 @@ func TestHandleAction_Counting(t *testing.T) {
 		},
 	}
 	bobAPI.On(`NotifyBlocking`, model.CountHand, mock.AnythingOfType(`model.Game`), `you did not submit the correct number of points for your hand`).Return(nil).Once()
-	err := HandleAction(&g, action, abAPIs)
+	err := HandleAction(context.Background(), &g, action, abAPIs)
 	assert.Error(t, err)
 	assert.EqualError(t, err, `wrong number of points`)
 	assert.Equal(t, 0, g.CurrentScores[g.PlayerColors[bob.ID]])
@@ -609,7 +660,7 @@ func TestHandleAction_Counting(t *testing.T) {
 	bobAPI.On(`NotifyScoreUpdate`, mock.AnythingOfType(`model.Game`), []string{`hand (7H: 7C, 8C, 9C, 10C)`}).Return(nil).Once()
 	aliceAPI.On(`NotifyScoreUpdate`, mock.AnythingOfType(`model.Game`), []string{`hand (7H: 7C, 8C, 9C, 10C)`}).Return(nil).Once()
 	aliceAPI.On(`NotifyBlocking`, model.CountHand, mock.AnythingOfType(`model.Game`), ``).Return(nil).Once()
-	err = HandleAction(&g, action, abAPIs)
+	err = HandleAction(context.Background(), &g, action, abAPIs)
 	assert.Nil(t, err)
 	assert.Equal(t, 18, g.CurrentScores[g.PlayerColors[bob.ID]])
 	assert.Contains(t, g.BlockingPlayers, alice.ID)
@@ -626,7 +677,7 @@ func TestHandleAction_Counting(t *testing.T) {
 	bobAPI.On(`NotifyScoreUpdate`, mock.AnythingOfType(`model.Game`), []string{`hand (7H: 7S, 8S, 9S, 10S)`}).Return(nil).Once()
 	aliceAPI.On(`NotifyScoreUpdate`, mock.AnythingOfType(`model.Game`), []string{`hand (7H: 7S, 8S, 9S, 10S)`}).Return(nil).Once()
 	aliceAPI.On(`NotifyBlocking`, model.CountCrib, mock.AnythingOfType(`model.Game`), ``).Return(nil).Once()
-	err = HandleAction(&g, action, abAPIs)
+	err = HandleAction(context.Background(), &g, action, abAPIs)
 	assert.Nil(t, err)
 	assert.Equal(t, 18, g.CurrentScores[g.PlayerColors[alice.ID]])
 
@@ -660,7 +711,9 @@ func TestHandleAction_CribCounting(t *testing.T) {
 			model.NewCardFromString(`10s`),
 		},
 		PeggedCards: make([]model.PeggedCard, 0, 8),
+		DeckOrder:   model.NewShuffledDeckOrder(1),
 	}
+	order := append([]model.Card(nil), g.DeckOrder...)
 
 	action := model.PlayerAction{
 		GameID:    g.ID,
@@ -671,7 +724,7 @@ func TestHandleAction_CribCounting(t *testing.T) {
 		},
 	}
 	aliceAPI.On(`NotifyBlocking`, model.CountCrib, mock.AnythingOfType(`model.Game`), `you did not submit the correct number of points for the crib`).Return(nil).Once()
-	err := HandleAction(&g, action, abAPIs)
+	err := HandleAction(context.Background(), &g, action, abAPIs)
 	assert.Error(t, err)
 	assert.EqualError(t, err, `wrong number of points`)
 	assert.Equal(t, 0, g.CurrentScores[g.PlayerColors[alice.ID]])
@@ -689,11 +742,14 @@ func TestHandleAction_CribCounting(t *testing.T) {
 	bobAPI.On(`NotifyScoreUpdate`, mock.AnythingOfType(`model.Game`), []string{`crib (7H: 7S, 8S, 9S, 10S)`}).Return(nil).Once()
 	aliceAPI.On(`NotifyScoreUpdate`, mock.AnythingOfType(`model.Game`), []string{`crib (7H: 7S, 8S, 9S, 10S)`}).Return(nil).Once()
 	bobAPI.On(`NotifyBlocking`, model.DealCards, mock.AnythingOfType(`model.Game`), ``).Return(nil).Once()
-	err = HandleAction(&g, action, abAPIs)
+	err = HandleAction(context.Background(), &g, action, abAPIs)
 	assert.Nil(t, err)
 	assert.Equal(t, 14, g.CurrentScores[g.PlayerColors[alice.ID]])
 	assert.Contains(t, g.BlockingPlayers, bob.ID)
 	assert.NotContains(t, g.BlockingPlayers, alice.ID)
+	// the round is over, but we keep its deck until the next deal
+	assert.Equal(t, model.Deal, g.Phase)
+	assert.Equal(t, order, g.DeckOrder)
 
 	aliceAPI.AssertExpectations(t)
 	bobAPI.AssertExpectations(t)
@@ -731,7 +787,7 @@ func TestHandleNineteenCribCounting(t *testing.T) {
 	}
 
 	bobAPI.On(`NotifyBlocking`, model.DealCards, mock.AnythingOfType(`model.Game`), ``).Return(nil).Once()
-	err := HandleAction(&g, action, abAPIs)
+	err := HandleAction(context.Background(), &g, action, abAPIs)
 
 	assert.Nil(t, err)
 	assert.Equal(t, 0, g.CurrentScores[g.PlayerColors[alice.ID]])
@@ -777,7 +833,7 @@ func TestHandleNineteenHandCounting(t *testing.T) {
 	}
 
 	aliceAPI.On(`NotifyBlocking`, model.CountCrib, mock.AnythingOfType(`model.Game`), ``).Return(nil).Once()
-	err := HandleAction(&g, action, abAPIs)
+	err := HandleAction(context.Background(), &g, action, abAPIs)
 
 	assert.Nil(t, err)
 	assert.Equal(t, 0, g.CurrentScores[g.PlayerColors[alice.ID]])
@@ -847,7 +903,7 @@ func TestHandleAction_DealAgain(t *testing.T) {
 	bobAPI.On(`NotifyScoreUpdate`, mock.AnythingOfType(`model.Game`), []string{`crib (7H: 7D, 8D, 9D, 10D)`}).Return(nil).Once()
 	aliceAPI.On(`NotifyScoreUpdate`, mock.AnythingOfType(`model.Game`), []string{`crib (7H: 7D, 8D, 9D, 10D)`}).Return(nil).Once()
 	bobAPI.On(`NotifyBlocking`, model.DealCards, mock.AnythingOfType(`model.Game`), ``).Return(nil).Once()
-	err := HandleAction(&g, action, abAPIs)
+	err := HandleAction(context.Background(), &g, action, abAPIs)
 	require.Nil(t, err)
 	assert.Empty(t, g.PeggedCards)
 
@@ -864,7 +920,7 @@ func TestHandleAction_DealAgain(t *testing.T) {
 	bobAPI.On(`NotifyMessage`, mock.AnythingOfType(`model.Game`), mock.MatchedBy(func(s string) bool { return strings.HasPrefix(s, `Received Hand `) })).Return(nil).Once()
 	bobAPI.On(`NotifyBlocking`, model.CribCard, mock.AnythingOfType(`model.Game`), `needs to cut 2 cards`).Return(nil).Once()
 
-	err = HandleAction(&g, action, abAPIs)
+	err = HandleAction(context.Background(), &g, action, abAPIs)
 	assert.Nil(t, err)
 	assert.Len(t, g.Hands[alice.ID], 6)
 	assert.Len(t, g.Hands[bob.ID], 6)
@@ -873,3 +929,64 @@ func TestHandleAction_DealAgain(t *testing.T) {
 	aliceAPI.AssertExpectations(t)
 	bobAPI.AssertExpectations(t)
 }
+
+func TestHandleAction_Forfeit(t *testing.T) {
+	testCases := []struct {
+		msg       string
+		action    model.ForfeitAction
+		expReason model.OutcomeReason
+		expMsg    string
+	}{{
+		msg:       `player gives up`,
+		action:    model.ForfeitAction{},
+		expReason: model.Forfeited,
+		expMsg:    `bob forfeited`,
+	}, {
+		msg: `player took too long`,
+		action: model.ForfeitAction{
+			TimedOut: true,
+		},
+		expReason: model.TimedOut,
+		expMsg:    `bob took too long and forfeited`,
+	}}
+
+	for _, tc := range testCases {
+		alice, bob, aliceAPI, bobAPI, abAPIs := testutils.AliceAndBob()
+
+		g := model.Game{
+			ID:              model.GameID(5),
+			Players:         []model.Player{alice, bob},
+			BlockingPlayers: map[model.PlayerID]model.Blocker{alice.ID: model.CountCrib},
+			CurrentDealer:   alice.ID,
+			PlayerColors:    map[model.PlayerID]model.PlayerColor{alice.ID: model.Blue, bob.ID: model.Red},
+			CurrentScores:   map[model.PlayerColor]int{model.Blue: 10, model.Red: 20},
+			LagScores:       map[model.PlayerColor]int{model.Blue: 0, model.Red: 0},
+			Phase:           model.CribCounting,
+		}
+		// bob can forfeit even though the game is not waiting on him
+		action := model.PlayerAction{
+			GameID:    g.ID,
+			ID:        bob.ID,
+			Overcomes: model.Forfeit,
+			Action:    tc.action,
+		}
+		aliceAPI.On(`NotifyMessage`, mock.AnythingOfType(`model.Game`), tc.expMsg).Return(nil).Once()
+		bobAPI.On(`NotifyMessage`, mock.AnythingOfType(`model.Game`), tc.expMsg).Return(nil).Once()
+
+		err := HandleAction(context.Background(), &g, action, abAPIs)
+		require.NoError(t, err, tc.msg)
+		assert.True(t, g.IsOver(), tc.msg)
+		assert.Equal(t, tc.expReason, g.Outcome.Reason, tc.msg)
+		assert.Equal(t, bob.ID, g.Outcome.PlayerID, tc.msg)
+		assert.Empty(t, g.BlockingPlayers, tc.msg)
+		assert.Equal(t, 1, g.NumActions(), tc.msg)
+		assert.Equal(t, []model.PlayerColor{model.Blue}, g.Winners(), tc.msg)
+
+		// nobody can do anything after a forfeit
+		err = HandleAction(context.Background(), &g, action, abAPIs)
+		assert.Equal(t, ErrGameAlreadyOver, err, tc.msg)
+
+		aliceAPI.AssertExpectations(t)
+		bobAPI.AssertExpectations(t)
+	}
+}
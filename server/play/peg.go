@@ -2,11 +2,13 @@ package play
 
 import (
 	"errors"
-	"log"
+
+	"go.uber.org/zap"
 
 	"github.com/joshprzybyszewski/cribbage/logic/pegging"
 	"github.com/joshprzybyszewski/cribbage/model"
 	"github.com/joshprzybyszewski/cribbage/server/interaction"
+	"github.com/joshprzybyszewski/cribbage/server/logging"
 )
 
 var _ PhaseHandler = (*peggingHandler)(nil)
@@ -15,6 +17,7 @@ type peggingHandler struct{}
 
 func (*peggingHandler) Start(g *model.Game, pAPIs map[model.PlayerID]interaction.Player) error {
 	g.PeggedCards = g.PeggedCards[:0]
+	g.Pegging = model.PeggingState{}
 
 	// put the player after the dealer as the blocking player
 	pIDs := playersToDealTo(g)
@@ -29,6 +32,9 @@ func (*peggingHandler) HandleAction(g *model.Game,
 	pAPIs map[model.PlayerID]interaction.Player,
 ) error {
 
+	// games saved before we kept the pegging state need it before they can peg
+	g.FillLegacyPegging()
+
 	// VALIDATE: check the action, then check the peg/go
 	if err := validateAction(g, action, model.PegCard); err != nil {
 		return err
@@ -47,13 +53,16 @@ func (*peggingHandler) HandleAction(g *model.Game,
 
 	// CLEAN: remove this player from the blockers
 	if len(g.BlockingPlayers) != 1 {
-		log.Printf("Expected one blocker for pegging, but had: %+v\n", g.BlockingPlayers)
+		logging.L().Warn(`expected one blocker for pegging`,
+			logging.GameID(g.ID),
+			zap.Any(`blockingPlayers`, g.BlockingPlayers),
+		)
 	}
 	removePlayerFromBlockers(g, action)
 
 	// ACT: do the "say go" or peg
 	if pa.SayGo {
-		doSayGo(g, action, pAPIs)
+		g.Pegging.Goes = append(g.Pegging.Goes, pID)
 	} else if err := doPeg(g, action, pa, pAPIs); err != nil {
 		return err
 	}
@@ -96,7 +105,8 @@ func doPeg(
 	pAPIs map[model.PlayerID]interaction.Player,
 ) error {
 
-	pts, err := pegging.PointsForCard(g.PeggedCards, pa.Card)
+	// only the cards in this series count towards the points
+	pts, err := pegging.PointsForCard(g.PeggedCards[g.Pegging.SeriesStart:], pa.Card)
 	if err != nil {
 		return err
 	}
@@ -108,54 +118,88 @@ func doPeg(
 		PlayerID: action.ID,
 		Action:   g.NumActions() + 1,
 	})
+	g.Pegging.Count += pa.Card.PegValue()
+	g.Pegging.LastPegger = action.ID
 
 	return nil
 }
 
-func doSayGo(g *model.Game,
+// progressAfterPeg blocks on the next player in the series, or ends the series if
+// there is nobody left who can peg in it
+func progressAfterPeg(
+	g *model.Game,
 	action model.PlayerAction,
 	pAPIs map[model.PlayerID]interaction.Player,
 ) {
 
-	if len(g.PeggedCards) == 0 {
+	if g.IsOver() {
+		// we shouldn't do anything if the game is over
 		return
 	}
 
-	lastPeggerID := g.PeggedCards[len(g.PeggedCards)-1].PlayerID
-	if lastPeggerID == action.ID {
-		// The go's went all the way around. Take a point
-		addPoints(g, action.ID, 1, pAPIs, `the go`)
+	if g.Pegging.Count < model.MaxPeggingValue {
+		if nextID, ok := nextPegger(g, action.ID); ok {
+			addPlayerToBlocker(g, nextID, model.PegCard, pAPIs, ``)
+			return
+		}
 	}
+
+	endSeries(g, pAPIs)
 }
 
-func progressAfterPeg(
+// endSeries gives out the point for the go (or last card), and then starts the next
+// series with the player after the one who pegged the last card
+func endSeries(
 	g *model.Game,
-	action model.PlayerAction,
 	pAPIs map[model.PlayerID]interaction.Player,
 ) {
 
+	lastPegger := g.Pegging.LastPegger
+	if g.Pegging.Count < model.MaxPeggingValue {
+		// hitting 31 already scored when the card was pegged
+		reason := `the go`
+		if len(g.PeggedCards) == 4*len(g.Players) {
+			reason = `last card`
+		}
+		addPoints(g, lastPegger, 1, pAPIs, reason)
+	}
+
+	g.Pegging = model.PeggingState{
+		SeriesStart: len(g.PeggedCards),
+	}
+
 	if g.IsOver() {
-		// we shouldn't do anything if the game is over
 		return
 	}
 
-	if len(g.PeggedCards) == 4*len(g.Players) {
-		// This was the last card: give one point to this player.
-		addPoints(g, action.ID, 1, pAPIs, `last card`)
-		return
+	if nextID, ok := nextPegger(g, lastPegger); ok {
+		addPlayerToBlocker(g, nextID, model.PegCard, pAPIs, ``)
 	}
+	// otherwise, every card has been pegged and we're done
+}
 
-	// Set the next player to peg as the blocker
-	// If we don't require everyone to say go, then we'll need to change the logic
-	// in game.CurrentPeg
-	nextPlayerIndex := -1
+// nextPegger returns the first player after the given one who is still in the current
+// series. The given player is considered last, since they keep pegging when everybody
+// else has said go.
+func nextPegger(g *model.Game, after model.PlayerID) (model.PlayerID, bool) {
+	afterIndex := 0
 	for i, p := range g.Players {
-		if p.ID == action.ID {
-			nextPlayerIndex = (i + 1) % len(g.Players)
+		if p.ID == after {
+			afterIndex = i
 			break
 		}
 	}
 
-	bp := g.Players[nextPlayerIndex]
-	addPlayerToBlocker(g, bp.ID, model.PegCard, pAPIs, ``)
+	for i := 1; i <= len(g.Players); i++ {
+		p := g.Players[(afterIndex+i)%len(g.Players)]
+		if g.Pegging.HasSaidGo(p.ID) {
+			continue
+		}
+		if !hasUnpeggedCards(g.Hands[p.ID], g.PeggedCards) {
+			continue
+		}
+		return p.ID, true
+	}
+
+	return model.InvalidPlayerID, false
 }
@@ -0,0 +1,341 @@
+package play
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/interaction"
+	"github.com/joshprzybyszewski/cribbage/utils/testutils"
+)
+
+const peggingIsOver = -1
+
+// pegStep is one action during pegging. The players are referred to by their
+// index at the table, and the last player is always the dealer.
+type pegStep struct {
+	player int
+	// card is the card to peg. Empty means the player says go.
+	card string
+
+	expCount int
+	// expNext is the player who has to peg next
+	expNext int
+}
+
+func newPeggingGame(
+	t *testing.T,
+	players []model.Player,
+	hands [][]string,
+) (model.Game, map[model.PlayerID]interaction.Player) {
+
+	colors := []model.PlayerColor{model.Blue, model.Red, model.Green}
+	if len(players) == 4 {
+		colors = []model.PlayerColor{model.Blue, model.Red, model.Blue, model.Red}
+	}
+
+	g := model.Game{
+		ID:              model.GameID(5),
+		Players:         players,
+		BlockingPlayers: map[model.PlayerID]model.Blocker{},
+		CurrentDealer:   players[len(players)-1].ID,
+		PlayerColors:    map[model.PlayerID]model.PlayerColor{},
+		CurrentScores:   map[model.PlayerColor]int{},
+		LagScores:       map[model.PlayerColor]int{},
+		Phase:           model.PeggingReady,
+		Hands:           map[model.PlayerID][]model.Card{},
+		CutCard:         model.NewCardFromString(`7d`),
+		PeggedCards:     make([]model.PeggedCard, 0, 4*len(players)),
+	}
+	pAPIs := map[model.PlayerID]interaction.Player{}
+	for i, p := range players {
+		g.PlayerColors[p.ID] = colors[i]
+		g.CurrentScores[colors[i]] = 0
+		g.LagScores[colors[i]] = 0
+		pAPIs[p.ID] = interaction.Empty(p.ID)
+		for _, c := range hands[i] {
+			g.Hands[p.ID] = append(g.Hands[p.ID], model.NewCardFromString(c))
+		}
+	}
+
+	require.NoError(t, runStartHandlers(context.Background(), &g, pAPIs))
+	require.Equal(t, model.Pegging, g.Phase)
+
+	return g, pAPIs
+}
+
+func TestPeggingRules(t *testing.T) {
+	alice, bob, charlie, diane := testutils.AliceBobCharlieDiane()
+
+	testCases := []struct {
+		msg       string
+		players   []model.Player
+		hands     [][]string
+		steps     []pegStep
+		expScores map[model.PlayerColor]int
+	}{{
+		msg:     `two players, both say go`,
+		players: []model.Player{alice, bob},
+		hands: [][]string{
+			{`ks`, `qs`, `9d`, `4c`},
+			{`kh`, `qh`, `8c`, `3d`},
+		},
+		steps: []pegStep{
+			{player: 0, card: `ks`, expCount: 10, expNext: 1},
+			{player: 1, card: `kh`, expCount: 20, expNext: 0},
+			{player: 0, card: `qs`, expCount: 30, expNext: 1},
+			// bob can't play, so alice keeps going
+			{player: 1, card: ``, expCount: 30, expNext: 0},
+			// alice can't play either, so she scores the go, and bob leads
+			{player: 0, card: ``, expCount: 0, expNext: 1},
+			{player: 1, card: `qh`, expCount: 10, expNext: 0},
+			{player: 0, card: `9d`, expCount: 19, expNext: 1},
+			{player: 1, card: `8c`, expCount: 27, expNext: 0},
+			// 31 ends the series, and the next player leads
+			{player: 0, card: `4c`, expCount: 0, expNext: 1},
+			{player: 1, card: `3d`, expCount: 0, expNext: peggingIsOver},
+		},
+		expScores: map[model.PlayerColor]int{
+			model.Blue: 1 + 2,
+			model.Red:  2 + 1,
+		},
+	}, {
+		msg:     `two players, one keeps pegging after the other says go`,
+		players: []model.Player{alice, bob},
+		hands: [][]string{
+			{`10s`, `js`, `qs`, `ks`},
+			{`as`, `2h`, `3h`, `4h`},
+		},
+		steps: []pegStep{
+			{player: 0, card: `10s`, expCount: 10, expNext: 1},
+			{player: 1, card: `as`, expCount: 11, expNext: 0},
+			{player: 0, card: `js`, expCount: 21, expNext: 1},
+			{player: 1, card: `2h`, expCount: 23, expNext: 0},
+			{player: 0, card: ``, expCount: 23, expNext: 1},
+			{player: 1, card: `3h`, expCount: 26, expNext: 1},
+			// bob is out of cards, so he scores the go along with his run
+			{player: 1, card: `4h`, expCount: 0, expNext: 0},
+			// alice is the only one left with cards
+			{player: 0, card: `qs`, expCount: 10, expNext: 0},
+			{player: 0, card: `ks`, expCount: 0, expNext: peggingIsOver},
+		},
+		expScores: map[model.PlayerColor]int{
+			model.Blue: 1,
+			model.Red:  3 + 1,
+		},
+	}, {
+		msg:     `two players, the last card makes 31`,
+		players: []model.Player{alice, bob},
+		hands: [][]string{
+			{`ks`, `qs`, `kd`, `qd`},
+			{`5h`, `6h`, `5d`, `6d`},
+		},
+		steps: []pegStep{
+			{player: 0, card: `ks`, expCount: 10, expNext: 1},
+			{player: 1, card: `5h`, expCount: 15, expNext: 0},
+			{player: 0, card: `qs`, expCount: 25, expNext: 1},
+			{player: 1, card: `6h`, expCount: 0, expNext: 0},
+			{player: 0, card: `kd`, expCount: 10, expNext: 1},
+			{player: 1, card: `5d`, expCount: 15, expNext: 0},
+			{player: 0, card: `qd`, expCount: 25, expNext: 1},
+			// 31 scores two, and there is no extra point for last card
+			{player: 1, card: `6d`, expCount: 0, expNext: peggingIsOver},
+		},
+		expScores: map[model.PlayerColor]int{
+			model.Blue: 0,
+			model.Red:  2 + 2 + 2 + 2,
+		},
+	}, {
+		msg:     `three players, with consecutive goes and a player who runs out`,
+		players: []model.Player{alice, bob, charlie},
+		hands: [][]string{
+			{`ks`, `qs`, `js`, `10s`},
+			{`kh`, `qh`, `jh`, `10h`},
+			{`ac`, `2c`, `3c`, `4c`},
+		},
+		steps: []pegStep{
+			{player: 0, card: `ks`, expCount: 10, expNext: 1},
+			{player: 1, card: `kh`, expCount: 20, expNext: 2},
+			{player: 2, card: `ac`, expCount: 21, expNext: 0},
+			{player: 0, card: `qs`, expCount: 0, expNext: 1},
+			{player: 1, card: `qh`, expCount: 10, expNext: 2},
+			{player: 2, card: `2c`, expCount: 12, expNext: 0},
+			{player: 0, card: `js`, expCount: 22, expNext: 1},
+			{player: 1, card: ``, expCount: 22, expNext: 2},
+			{player: 2, card: `3c`, expCount: 25, expNext: 0},
+			// alice says go, and charlie is the only one left in the series
+			{player: 0, card: ``, expCount: 25, expNext: 2},
+			// charlie pegs his last card, which ends the series
+			{player: 2, card: `4c`, expCount: 0, expNext: 0},
+			{player: 0, card: `10s`, expCount: 10, expNext: 1},
+			// charlie and alice are out of cards, so bob plays out his hand
+			{player: 1, card: `jh`, expCount: 20, expNext: 1},
+			{player: 1, card: `10h`, expCount: 0, expNext: peggingIsOver},
+		},
+		expScores: map[model.PlayerColor]int{
+			model.Blue:  2,
+			model.Red:   2 + 1,
+			model.Green: 1,
+		},
+	}, {
+		msg:     `four players, where partners share their points`,
+		players: []model.Player{alice, bob, charlie, diane},
+		hands: [][]string{
+			{`ks`, `qs`, `js`, `10s`},
+			{`kh`, `qh`, `jh`, `10h`},
+			{`kd`, `qd`, `jd`, `10d`},
+			{`2c`, `3c`, `4c`, `5c`},
+		},
+		steps: []pegStep{
+			{player: 0, card: `ks`, expCount: 10, expNext: 1},
+			{player: 1, card: `kh`, expCount: 20, expNext: 2},
+			{player: 2, card: `kd`, expCount: 30, expNext: 3},
+			{player: 3, card: ``, expCount: 30, expNext: 0},
+			{player: 0, card: ``, expCount: 30, expNext: 1},
+			{player: 1, card: ``, expCount: 30, expNext: 2},
+			// the go went all the way around to charlie
+			{player: 2, card: ``, expCount: 0, expNext: 3},
+			{player: 3, card: `2c`, expCount: 2, expNext: 0},
+			{player: 0, card: `qs`, expCount: 12, expNext: 1},
+			{player: 1, card: `qh`, expCount: 22, expNext: 2},
+			{player: 2, card: ``, expCount: 22, expNext: 3},
+			{player: 3, card: `3c`, expCount: 25, expNext: 0},
+			{player: 0, card: ``, expCount: 25, expNext: 1},
+			{player: 1, card: ``, expCount: 25, expNext: 3},
+			// diane is the only one left in the series
+			{player: 3, card: `4c`, expCount: 29, expNext: 3},
+			{player: 3, card: ``, expCount: 0, expNext: 0},
+			{player: 0, card: `js`, expCount: 10, expNext: 1},
+			{player: 1, card: `jh`, expCount: 20, expNext: 2},
+			{player: 2, card: `jd`, expCount: 30, expNext: 3},
+			{player: 3, card: ``, expCount: 30, expNext: 0},
+			{player: 0, card: ``, expCount: 30, expNext: 1},
+			{player: 1, card: ``, expCount: 30, expNext: 2},
+			{player: 2, card: ``, expCount: 0, expNext: 3},
+			{player: 3, card: `5c`, expCount: 5, expNext: 0},
+			{player: 0, card: `10s`, expCount: 15, expNext: 1},
+			{player: 1, card: `10h`, expCount: 25, expNext: 2},
+			// everyone else is out of cards, so bob scores the go
+			{player: 2, card: ``, expCount: 0, expNext: 2},
+			// charlie is the only one who still has cards
+			{player: 2, card: `10d`, expCount: 10, expNext: 2},
+			{player: 2, card: `qd`, expCount: 0, expNext: peggingIsOver},
+		},
+		expScores: map[model.PlayerColor]int{
+			model.Blue: 6 + 1 + 6 + 1 + 2 + 1,
+			model.Red:  2 + 2 + 1 + 2 + 2 + 1,
+		},
+	}}
+
+	for _, tc := range testCases {
+		g, pAPIs := newPeggingGame(t, tc.players, tc.hands)
+
+		for i, s := range tc.steps {
+			pID := tc.players[s.player].ID
+			pa := model.PegAction{SayGo: true}
+			if s.card != `` {
+				pa = model.PegAction{Card: model.NewCardFromString(s.card)}
+			}
+			require.Equal(t, map[model.PlayerID]model.Blocker{pID: model.PegCard}, g.BlockingPlayers,
+				`%s: step %d is for the wrong player`, tc.msg, i)
+
+			err := HandleAction(context.Background(), &g, model.PlayerAction{
+				GameID:    g.ID,
+				ID:        pID,
+				Overcomes: model.PegCard,
+				Action:    pa,
+			}, pAPIs)
+			require.NoError(t, err, `%s: step %d`, tc.msg, i)
+
+			if s.expNext == peggingIsOver {
+				assert.Equal(t, model.Counting, g.Phase, `%s: step %d`, tc.msg, i)
+				assert.Len(t, g.PeggedCards, 4*len(tc.players), `%s: step %d`, tc.msg, i)
+				continue
+			}
+			assert.Equal(t, model.Pegging, g.Phase, `%s: step %d`, tc.msg, i)
+			assert.Equal(t, s.expCount, g.CurrentPeg(), `%s: step %d`, tc.msg, i)
+			assert.Equal(t, map[model.PlayerID]model.Blocker{tc.players[s.expNext].ID: model.PegCard}, g.BlockingPlayers,
+				`%s: step %d`, tc.msg, i)
+		}
+
+		assert.Equal(t, tc.expScores, g.CurrentScores, tc.msg)
+	}
+}
+
+func TestPeggingRules_CannotSayGoWithAPlayableCard(t *testing.T) {
+	alice, bob, _, _ := testutils.AliceBobCharlieDiane()
+	g, pAPIs := newPeggingGame(t, []model.Player{alice, bob}, [][]string{
+		{`ks`, `qs`, `9d`, `4c`},
+		{`kh`, `qh`, `8c`, `3d`},
+	})
+
+	err := HandleAction(context.Background(), &g, model.PlayerAction{
+		GameID:    g.ID,
+		ID:        alice.ID,
+		Overcomes: model.PegCard,
+		Action:    model.PegAction{SayGo: true},
+	}, pAPIs)
+	require.NoError(t, err)
+	assert.Equal(t, map[model.PlayerID]model.Blocker{alice.ID: model.PegCard}, g.BlockingPlayers)
+	assert.Empty(t, g.Pegging.Goes)
+}
+
+func TestPeggingRules_GameSavedWithoutAPeggingState(t *testing.T) {
+	alice, bob, _, _ := testutils.AliceBobCharlieDiane()
+	players := []model.Player{alice, bob}
+	hands := [][]string{
+		{`ks`, `qs`, `9d`, `4c`},
+		{`kh`, `qh`, `8c`, `3d`},
+	}
+	steps := []pegStep{
+		{player: 0, card: `ks`, expCount: 10, expNext: 1},
+		{player: 1, card: `kh`, expCount: 20, expNext: 0},
+		{player: 0, card: `qs`, expCount: 30, expNext: 1},
+		{player: 1, card: ``, expCount: 30, expNext: 0},
+		{player: 0, card: ``, expCount: 0, expNext: 1},
+		{player: 1, card: `qh`, expCount: 10, expNext: 0},
+		{player: 0, card: `9d`, expCount: 19, expNext: 1},
+		{player: 1, card: `8c`, expCount: 27, expNext: 0},
+		{player: 0, card: `4c`, expCount: 0, expNext: 1},
+		{player: 1, card: `3d`, expCount: 0, expNext: peggingIsOver},
+	}
+
+	// the game is saved in the old format before each of the steps
+	for saved := 1; saved < len(steps); saved++ {
+		g, pAPIs := newPeggingGame(t, players, hands)
+
+		for i, s := range steps {
+			if i == saved {
+				g.Pegging = model.PeggingState{}
+			}
+
+			pa := model.PegAction{SayGo: true}
+			if s.card != `` {
+				pa = model.PegAction{Card: model.NewCardFromString(s.card)}
+			}
+			err := HandleAction(context.Background(), &g, model.PlayerAction{
+				GameID:    g.ID,
+				ID:        players[s.player].ID,
+				Overcomes: model.PegCard,
+				Action:    pa,
+			}, pAPIs)
+			require.NoError(t, err, `saved before step %d: step %d`, saved, i)
+
+			if s.expNext == peggingIsOver {
+				assert.Equal(t, model.Counting, g.Phase, `saved before step %d: step %d`, saved, i)
+				continue
+			}
+			assert.Equal(t, s.expCount, g.CurrentPeg(), `saved before step %d: step %d`, saved, i)
+			assert.Equal(t, map[model.PlayerID]model.Blocker{players[s.expNext].ID: model.PegCard}, g.BlockingPlayers,
+				`saved before step %d: step %d`, saved, i)
+		}
+
+		assert.Equal(t, map[model.PlayerColor]int{
+			model.Blue: 1 + 2,
+			model.Red:  2 + 1,
+		}, g.CurrentScores, `saved before step %d`, saved)
+	}
+}
@@ -1,8 +1,6 @@
 package play
 
 import (
-	"fmt"
-
 	"github.com/joshprzybyszewski/cribbage/model"
 	"github.com/joshprzybyszewski/cribbage/server/interaction"
 )
@@ -19,7 +17,10 @@ type PhaseHandler interface {
 
 func validateAction(g *model.Game, action model.PlayerAction, blocker model.Blocker) error {
 	if action.Overcomes != blocker {
-		return fmt.Errorf(`Should overcome %v, but overcomes %v`, blocker, action.Overcomes)
+		return wrongBlockerError{
+			want: blocker,
+			got:  action.Overcomes,
+		}
 	}
 	if err := isWaitingForPlayer(g, action); err != nil {
 		return err
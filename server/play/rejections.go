@@ -0,0 +1,46 @@
+package play
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+)
+
+// wrongBlockerError is an ErrWrongBlocker that says which blocker the action should overcome
+type wrongBlockerError struct {
+	want model.Blocker
+	got  model.Blocker
+}
+
+func (e wrongBlockerError) Error() string {
+	return fmt.Sprintf(`Should overcome %v, but overcomes %v`, e.want, e.got)
+}
+
+func (e wrongBlockerError) Is(target error) bool {
+	return target == ErrWrongBlocker
+}
+
+// RejectionReason describes why HandleAction (or HandleTakeback) returned the error,
+// in a word or two that won't change with the details of the action
+func RejectionReason(err error) string {
+	switch {
+	case errors.Is(err, ErrActionNotForGame):
+		return `not_for_game`
+	case errors.Is(err, ErrPlayerNotInGame):
+		return `not_in_game`
+	case errors.Is(err, ErrGameAlreadyOver):
+		return `game_over`
+	case errors.Is(err, ErrNotBlockedByPlayer):
+		return `not_blocking`
+	case errors.Is(err, ErrWrongBlocker):
+		return `wrong_blocker`
+	case errors.Is(err, ErrWrongNumberOfPoints):
+		return `wrong_points`
+	case errors.Is(err, ErrTakebackNotAllowed),
+		errors.Is(err, ErrTakebackPending),
+		errors.Is(err, ErrTakebackNeedsSnapshots):
+		return `takeback`
+	}
+	return `invalid_action`
+}
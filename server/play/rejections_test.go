@@ -0,0 +1,72 @@
+package play
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+)
+
+func TestRejectionReason(t *testing.T) {
+	testCases := []struct {
+		msg string
+		err error
+		exp string
+	}{{
+		msg: `action for another game`,
+		err: ErrActionNotForGame,
+		exp: `not_for_game`,
+	}, {
+		msg: `player not in the game`,
+		err: ErrPlayerNotInGame,
+		exp: `not_in_game`,
+	}, {
+		msg: `game is over`,
+		err: ErrGameAlreadyOver,
+		exp: `game_over`,
+	}, {
+		msg: `player is not blocking`,
+		err: ErrNotBlockedByPlayer,
+		exp: `not_blocking`,
+	}, {
+		msg: `overcomes the wrong blocker`,
+		err: wrongBlockerError{want: model.PegCard, got: model.CountHand},
+		exp: `wrong_blocker`,
+	}, {
+		msg: `wrapped wrong number of points`,
+		err: fmt.Errorf(`counting: %w`, ErrWrongNumberOfPoints),
+		exp: `wrong_points`,
+	}, {
+		msg: `takeback not allowed`,
+		err: ErrTakebackNotAllowed,
+		exp: `takeback`,
+	}, {
+		msg: `takeback pending`,
+		err: ErrTakebackPending,
+		exp: `takeback`,
+	}, {
+		msg: `anything else`,
+		err: errors.New(`cannot peg that card`),
+		exp: `invalid_action`,
+	}}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.exp, RejectionReason(tc.err), tc.msg)
+	}
+}
+
+func TestValidateActionWrongBlocker(t *testing.T) {
+	g := model.Game{
+		BlockingPlayers: map[model.PlayerID]model.Blocker{`alice`: model.PegCard},
+	}
+	err := validateAction(&g, model.PlayerAction{
+		ID:        `alice`,
+		Overcomes: model.CountHand,
+	}, model.PegCard)
+
+	assert.True(t, errors.Is(err, ErrWrongBlocker))
+	assert.EqualError(t, err, `Should overcome `+model.PegCard.String()+`, but overcomes `+model.CountHand.String())
+}
@@ -0,0 +1,219 @@
+package play
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/interaction"
+	"github.com/joshprzybyszewski/cribbage/server/tracing"
+)
+
+var (
+	ErrTakebackNotAllowed     error = errors.New(`cannot take back the last action`)
+	ErrTakebackPending        error = errors.New(`a takeback is already waiting for an answer`)
+	ErrTakebackNeedsSnapshots error = errors.New(`takebacks must be handled with HandleTakeback`)
+	ErrSnapshotNotReplayed    error = errors.New(`the takeback goes back to before the replay started`)
+)
+
+// Snapshots returns the state of the game after the given number of actions
+type Snapshots func(numActions uint) (model.Game, error)
+
+// IsTakeback returns true if the action needs to go through HandleTakeback
+func IsTakeback(action model.PlayerAction) bool {
+	switch action.Overcomes {
+	case model.RequestTakeback, model.ApproveTakeback:
+		return true
+	}
+	return false
+}
+
+// HandleTakeback lets a player ask to undo their last action, and lets their opponents
+// answer that request. When every opponent approves, the game goes back to the snapshot
+// from before the undone action. The request and the answers stay in the game's actions
+// so that the history shows every takeback.
+func HandleTakeback(
+	ctx context.Context,
+	g *model.Game,
+	action model.PlayerAction,
+	pAPIs map[model.PlayerID]interaction.Player,
+	snapshots Snapshots,
+) (err error) {
+	ctx, span := tracing.Start(ctx, `play.HandleTakeback`,
+		tracing.GameID(g.ID),
+		tracing.PlayerID(action.ID),
+		tracing.Blocker(action.Overcomes),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	err = validatePlayerAction(g, action)
+	if err != nil {
+		return err
+	}
+
+	pAPIs = withSpans(ctx, pAPIs)
+	switch action.Overcomes {
+	case model.RequestTakeback:
+		return requestTakeback(g, action, pAPIs)
+	case model.ApproveTakeback:
+		return answerTakeback(g, action, pAPIs, snapshots)
+	}
+	return ErrTakebackNeedsSnapshots
+}
+
+func requestTakeback(g *model.Game,
+	action model.PlayerAction,
+	pAPIs map[model.PlayerID]interaction.Player,
+) error {
+
+	if _, ok := action.Action.(model.RequestTakebackAction); !ok {
+		return errors.New(`tried requesting a takeback with a different action`)
+	}
+	if isTakebackPending(g) {
+		return ErrTakebackPending
+	}
+	err := validateTakebackTarget(g, action.ID)
+	if err != nil {
+		return err
+	}
+
+	g.AddAction(action)
+
+	myColor := g.PlayerColors[action.ID]
+	msg := fmt.Sprintf(`%s wants to take back their last action`, playerName(g, action.ID))
+	g.BlockingPlayers = make(map[model.PlayerID]model.Blocker, len(g.Players))
+	for _, p := range g.Players {
+		if g.PlayerColors[p.ID] == myColor {
+			continue
+		}
+		addPlayerToBlocker(g, p.ID, model.ApproveTakeback, pAPIs, msg)
+	}
+
+	return nil
+}
+
+// validateTakebackTarget makes sure that the last action in the game belongs to the player,
+// and that nobody has learned anything new since they took it.
+func validateTakebackTarget(g *model.Game, pID model.PlayerID) error {
+	switch g.Phase {
+	case model.BuildCrib, model.Cut, model.Pegging:
+	default:
+		// once the hands are being counted, everything has been revealed
+		return ErrTakebackNotAllowed
+	}
+
+	if g.NumActions() == 0 {
+		return ErrTakebackNotAllowed
+	}
+	last := g.Actions[g.NumActions()-1]
+	if last.ID != pID {
+		return ErrTakebackNotAllowed
+	}
+	switch last.Overcomes {
+	case model.CribCard, model.PegCard:
+		return nil
+	}
+	// the cut card (and any deal) has already been seen by everyone
+	return ErrTakebackNotAllowed
+}
+
+func answerTakeback(g *model.Game,
+	action model.PlayerAction,
+	pAPIs map[model.PlayerID]interaction.Player,
+	snapshots Snapshots,
+) error {
+
+	ata, ok := action.Action.(model.ApproveTakebackAction)
+	if !ok {
+		return errors.New(`tried answering a takeback with a different action`)
+	}
+	err := validateAction(g, action, model.ApproveTakeback)
+	if err != nil {
+		return err
+	}
+
+	reqIndex := -1
+	for i := g.NumActions() - 1; i >= 0; i-- {
+		if g.Actions[i].Overcomes == model.RequestTakeback {
+			reqIndex = i
+			break
+		}
+	}
+	if reqIndex < 1 {
+		return errors.New(`could not find the takeback request`)
+	}
+	requester := g.Actions[reqIndex].ID
+
+	removePlayerFromBlockers(g, action)
+	g.AddAction(action)
+
+	if !ata.Approve {
+		// put the game back to how it was before the request
+		err = revertTo(g, uint(reqIndex), snapshots)
+		if err != nil {
+			return err
+		}
+		notifyTakebackResult(g, pAPIs, fmt.Sprintf(`%s declined the takeback`, playerName(g, action.ID)))
+		return nil
+	}
+
+	if isTakebackPending(g) {
+		// wait for the rest of the opponents to answer
+		return nil
+	}
+
+	err = revertTo(g, uint(reqIndex-1), snapshots)
+	if err != nil {
+		return err
+	}
+
+	notifyTakebackResult(g, pAPIs, fmt.Sprintf(`%s took back their last action`, playerName(g, requester)))
+	return nil
+}
+
+// notifyTakebackResult tells everyone how the takeback went, and then lets the players
+// that the game is waiting on again know that it's their turn
+func notifyTakebackResult(g *model.Game, pAPIs map[model.PlayerID]interaction.Player, msg string) {
+	for _, pAPI := range pAPIs {
+		_ = pAPI.NotifyMessage(*g, msg)
+	}
+	for pID, b := range g.BlockingPlayers {
+		addPlayerToBlocker(g, pID, b, pAPIs, ``)
+	}
+}
+
+// revertTo restores the game to the snapshot after numActions, but keeps every action
+// that has been taken so far. The snapshots are saved by the number of actions, so
+// dropping the undone ones would have the next action overwrite a snapshot. Anything
+// that replays the actions needs to go back to the snapshot too, like Replay does.
+func revertTo(g *model.Game, numActions uint, snapshots Snapshots) error {
+	snapshot, err := snapshots(numActions)
+	if err != nil {
+		return err
+	}
+
+	actions := g.Actions
+	*g = snapshot
+	g.Actions = actions
+
+	return nil
+}
+
+func isTakebackPending(g *model.Game) bool {
+	for _, b := range g.BlockingPlayers {
+		if b == model.ApproveTakeback {
+			return true
+		}
+	}
+	return false
+}
+
+func playerName(g *model.Game, pID model.PlayerID) string {
+	for _, p := range g.Players {
+		if p.ID == pID {
+			return p.Name
+		}
+	}
+	return string(pID)
+}
@@ -0,0 +1,183 @@
+package play
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/utils/testutils"
+)
+
+// takebackGame is a game where bob has just cut and alice is about to peg.
+// Every call returns a new copy, so it can be used as the game's snapshots.
+func takebackGame(alice, bob model.Player) model.Game {
+	return model.Game{
+		ID:              model.GameID(5),
+		Players:         []model.Player{alice, bob},
+		BlockingPlayers: map[model.PlayerID]model.Blocker{alice.ID: model.PegCard},
+		CurrentDealer:   bob.ID,
+		PlayerColors:    map[model.PlayerID]model.PlayerColor{alice.ID: model.Blue, bob.ID: model.Red},
+		CurrentScores:   map[model.PlayerColor]int{model.Blue: 0, model.Red: 0},
+		LagScores:       map[model.PlayerColor]int{model.Blue: 0, model.Red: 0},
+		Phase:           model.Pegging,
+		Hands: map[model.PlayerID][]model.Card{
+			alice.ID: {
+				model.NewCardFromString(`7s`),
+				model.NewCardFromString(`8s`),
+				model.NewCardFromString(`10s`),
+				model.NewCardFromString(`js`),
+			},
+			bob.ID: {
+				model.NewCardFromString(`7c`),
+				model.NewCardFromString(`9c`),
+				model.NewCardFromString(`10c`),
+				model.NewCardFromString(`jc`),
+			},
+		},
+		CutCard: model.NewCardFromString(`KH`),
+		Crib: []model.Card{
+			model.NewCardFromString(`as`),
+			model.NewCardFromString(`ah`),
+			model.NewCardFromString(`ac`),
+			model.NewCardFromString(`ad`),
+		},
+		PeggedCards: make([]model.PeggedCard, 0, 8),
+		Actions: []model.PlayerAction{{
+			GameID:    model.GameID(5),
+			ID:        bob.ID,
+			Overcomes: model.CutCard,
+			Action:    model.CutDeckAction{Percentage: 0.5},
+		}},
+	}
+}
+
+func takebackAction(g model.Game, pID model.PlayerID, b model.Blocker, a interface{}) model.PlayerAction {
+	return model.PlayerAction{
+		GameID:    g.ID,
+		ID:        pID,
+		Overcomes: b,
+		Action:    a,
+	}
+}
+
+func TestHandleTakeback(t *testing.T) {
+	alice, bob, abAPIs := testutils.EmptyAliceAndBob()
+
+	misclick := func(g *model.Game) {
+		err := HandleAction(context.Background(), g, takebackAction(*g, alice.ID, model.PegCard, model.PegAction{
+			Card: model.NewCardFromString(`js`),
+		}), abAPIs)
+		require.NoError(t, err)
+	}
+	snapshots := func(numActions uint) (model.Game, error) {
+		g := takebackGame(alice, bob)
+		switch numActions {
+		case 1:
+			return g, nil
+		case 2:
+			misclick(&g)
+			return g, nil
+		}
+		return model.Game{}, errors.New(`no snapshot`)
+	}
+
+	testCases := []struct {
+		msg        string
+		approve    bool
+		expPegged  int
+		expBlocker map[model.PlayerID]model.Blocker
+	}{{
+		msg:        `approved`,
+		approve:    true,
+		expPegged:  0,
+		expBlocker: map[model.PlayerID]model.Blocker{alice.ID: model.PegCard},
+	}, {
+		msg:        `declined`,
+		approve:    false,
+		expPegged:  1,
+		expBlocker: map[model.PlayerID]model.Blocker{bob.ID: model.PegCard},
+	}}
+
+	for _, tc := range testCases {
+		g := takebackGame(alice, bob)
+		misclick(&g)
+
+		err := HandleAction(context.Background(), &g, takebackAction(g, alice.ID, model.RequestTakeback, model.RequestTakebackAction{}), abAPIs)
+		assert.Equal(t, ErrTakebackNeedsSnapshots, err, tc.msg)
+
+		err = HandleTakeback(context.Background(), &g,
+			takebackAction(g, bob.ID, model.RequestTakeback, model.RequestTakebackAction{}),
+			abAPIs, snapshots)
+		assert.Equal(t, ErrTakebackNotAllowed, err, `bob's last action was the cut`)
+
+		err = HandleTakeback(context.Background(), &g,
+			takebackAction(g, alice.ID, model.RequestTakeback, model.RequestTakebackAction{}),
+			abAPIs, snapshots)
+		require.NoError(t, err, tc.msg)
+		assert.Equal(t, map[model.PlayerID]model.Blocker{bob.ID: model.ApproveTakeback}, g.BlockingPlayers, tc.msg)
+		assert.Equal(t, 3, g.NumActions(), tc.msg)
+
+		err = HandleTakeback(context.Background(), &g,
+			takebackAction(g, alice.ID, model.RequestTakeback, model.RequestTakebackAction{}),
+			abAPIs, snapshots)
+		assert.Equal(t, ErrTakebackPending, err, tc.msg)
+
+		err = HandleAction(context.Background(), &g, takebackAction(g, bob.ID, model.PegCard, model.PegAction{
+			Card: model.NewCardFromString(`7c`),
+		}), abAPIs)
+		assert.Equal(t, ErrTakebackPending, err, `bob cannot peg until he answers the takeback`)
+
+		err = HandleTakeback(context.Background(), &g,
+			takebackAction(g, bob.ID, model.ApproveTakeback, model.ApproveTakebackAction{Approve: tc.approve}),
+			abAPIs, snapshots)
+		require.NoError(t, err, tc.msg)
+
+		assert.Equal(t, tc.expBlocker, g.BlockingPlayers, tc.msg)
+		assert.Len(t, g.PeggedCards, tc.expPegged, tc.msg)
+		require.Equal(t, 4, g.NumActions(), `the takeback stays in the history`)
+		assert.Equal(t, model.PegCard, g.Actions[1].Overcomes, tc.msg)
+		assert.Equal(t, model.RequestTakeback, g.Actions[2].Overcomes, tc.msg)
+		assert.Equal(t, model.ApproveTakeback, g.Actions[3].Overcomes, tc.msg)
+
+		err = HandleTakeback(context.Background(), &g,
+			takebackAction(g, alice.ID, model.RequestTakeback, model.RequestTakebackAction{}),
+			abAPIs, snapshots)
+		assert.Equal(t, ErrTakebackNotAllowed, err, `only one takeback at a time`)
+	}
+}
+
+func TestHandleTakeback_NotAllowed(t *testing.T) {
+	alice, bob, abAPIs := testutils.EmptyAliceAndBob()
+	snapshots := func(numActions uint) (model.Game, error) {
+		return model.Game{}, errors.New(`no snapshot`)
+	}
+
+	g := takebackGame(alice, bob)
+	err := HandleTakeback(context.Background(), &g,
+		takebackAction(g, bob.ID, model.RequestTakeback, model.RequestTakebackAction{}),
+		abAPIs, snapshots)
+	assert.Equal(t, ErrTakebackNotAllowed, err, `cannot take back the cut`)
+
+	g.Actions[0].Overcomes = model.PegCard
+	g.Phase = model.Counting
+	err = HandleTakeback(context.Background(), &g,
+		takebackAction(g, bob.ID, model.RequestTakeback, model.RequestTakebackAction{}),
+		abAPIs, snapshots)
+	assert.Equal(t, ErrTakebackNotAllowed, err, `cannot take back once the hands are shown`)
+
+	g.Phase = model.Pegging
+	err = HandleTakeback(context.Background(), &g,
+		takebackAction(g, bob.ID, model.ApproveTakeback, model.ApproveTakebackAction{Approve: true}),
+		abAPIs, snapshots)
+	assert.Error(t, err, `there is nothing to approve`)
+
+	err = HandleTakeback(context.Background(), &g,
+		takebackAction(g, bob.ID, model.RequestTakeback, model.RequestTakebackAction{}),
+		abAPIs, snapshots)
+	require.NoError(t, err)
+	assert.Equal(t, map[model.PlayerID]model.Blocker{alice.ID: model.ApproveTakeback}, g.BlockingPlayers)
+}
@@ -1,11 +1,11 @@
 package play
 
 import (
-	"errors"
-	"log"
+	"go.uber.org/zap"
 
 	"github.com/joshprzybyszewski/cribbage/model"
 	"github.com/joshprzybyszewski/cribbage/server/interaction"
+	"github.com/joshprzybyszewski/cribbage/server/logging"
 )
 
 func playersToDealTo(g *model.Game) []model.PlayerID {
@@ -35,7 +35,7 @@ func isWaitingForPlayer(g *model.Game, action model.PlayerAction) error {
 		}
 	}
 	if !isWaitingForThisPlayer {
-		return errors.New(`Game is not blocked by this player`)
+		return ErrNotBlockedByPlayer
 	}
 
 	return nil
@@ -50,10 +50,21 @@ func addPlayerToBlocker(
 ) {
 
 	if br, ok := g.BlockingPlayers[pID]; ok && br != reason {
-		log.Printf("Same player (%s) blocking for new reason (%v vs. %v)", pID, br, reason)
+		logging.L().Warn(`same player blocking for a new reason`,
+			logging.GameID(g.ID),
+			logging.PlayerID(pID),
+			logging.Blocker(reason),
+			zap.Stringer(`previousBlocker`, br),
+		)
 	}
 	g.BlockingPlayers[pID] = reason
 	pAPI := pAPIs[pID]
+	if interaction.AutoPlays(pAPI) {
+		if _, ok := forcedMove(g, pID, reason); ok {
+			// the server is going to play this move for them
+			return
+		}
+	}
 	_ = pAPI.NotifyBlocking(reason, *g, msg)
 }
 
@@ -61,9 +72,18 @@ func removePlayerFromBlockers(g *model.Game, action model.PlayerAction) {
 	if br, ok := g.BlockingPlayers[action.ID]; ok && br == action.Overcomes {
 		delete(g.BlockingPlayers, action.ID)
 	} else if !ok {
-		log.Printf(`Did not find player "%+v" in list of blocking players (%+v)`, action.ID, g.BlockingPlayers)
+		logging.L().Warn(`did not find player in the blocking players`,
+			logging.GameID(g.ID),
+			logging.PlayerID(action.ID),
+			zap.Any(`blockingPlayers`, g.BlockingPlayers),
+		)
 	} else {
-		log.Printf(`Player was not blocked by "%v", but rather "%v"`, action.Overcomes, br)
+		logging.L().Warn(`player was blocked by something else`,
+			logging.GameID(g.ID),
+			logging.PlayerID(action.ID),
+			logging.Blocker(br),
+			zap.Stringer(`overcomes`, action.Overcomes),
+		)
 	}
 }
 
@@ -83,7 +103,11 @@ func addPoints(
 	if pts == 0 {
 		return
 	} else if pts < 0 {
-		log.Printf(`Attempted to score %d points for player %v`, pts, pID)
+		logging.L().Warn(`attempted to score negative points`,
+			logging.GameID(g.ID),
+			logging.PlayerID(pID),
+			zap.Int(`points`, pts),
+		)
 		return
 	}
 
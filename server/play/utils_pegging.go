@@ -39,3 +39,28 @@ func minUnpeggedValue(hand []model.Card, pegged []model.PeggedCard) int {
 	}
 	return min
 }
+
+func hasUnpeggedCards(hand []model.Card, pegged []model.PeggedCard) bool {
+	for _, hc := range hand {
+		if !hasBeenPegged(pegged, hc) {
+			return true
+		}
+	}
+	return false
+}
+
+// playableCards returns the unpegged cards in the player's hand which can be
+// pegged without going over 31
+func playableCards(g *model.Game, pID model.PlayerID) []model.Card {
+	var playable []model.Card
+	for _, hc := range g.Hands[pID] {
+		if hasBeenPegged(g.PeggedCards, hc) {
+			continue
+		}
+		if g.CurrentPeg()+hc.PegValue() > model.MaxPeggingValue {
+			continue
+		}
+		playable = append(playable, hc)
+	}
+	return playable
+}
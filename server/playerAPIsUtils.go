@@ -1,22 +1,27 @@
 package server
 
 import (
+	"context"
+
 	"github.com/joshprzybyszewski/cribbage/model"
 	"github.com/joshprzybyszewski/cribbage/server/interaction"
 	"github.com/joshprzybyszewski/cribbage/server/persistence"
 )
 
-var actionHandler = &npcActionHandler{}
-
-func getPlayerAPIs(db persistence.DB, players []model.Player) (map[model.PlayerID]interaction.Player, error) {
+func getPlayerAPIs(
+	ctx context.Context,
+	db persistence.DB,
+	players []model.Player,
+) (map[model.PlayerID]interaction.Player, error) {
 	pAPIs := make(map[model.PlayerID]interaction.Player, len(players))
 	for _, p := range players {
 		var pAPI interaction.Player
-		pm, err := db.GetInteraction(p.ID)
+		pm, err := db.GetInteraction(ctx, p.ID)
 
 		for i, m := range pm.Interactions {
 			if m.Mode == interaction.NPC {
-				m.Info = actionHandler
+				// the NPCs' actions wait for this transaction to commit
+				m.Info = &npcActionQueue{db: db}
 				pm.Interactions[i] = m
 			}
 		}
@@ -27,7 +32,7 @@ func getPlayerAPIs(db persistence.DB, players []model.Player) (map[model.PlayerI
 			}
 			pAPI = interaction.Empty(p.ID)
 		} else {
-			pAPI, err = interaction.FromPlayerMeans(pm)
+			pAPI, err = interaction.FromPlayerMeansWithOutbox(pm, notifications.outbox(db))
 			if err != nil {
 				return nil, err
 			}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/joshprzybyszewski/cribbage/server/logging"
+	"github.com/joshprzybyszewski/cribbage/server/metrics"
+)
+
+const (
+	requestIDHeader = `X-Request-ID`
+
+	// unmatchedRoute is the route that we observe for requests that didn't match one,
+	// so that scanners can't make a new label for each path that they try
+	unmatchedRoute = `unmatched`
+)
+
+// observeRequests gives every request an ID and a logger that includes it (and the
+// trace, if there is one), and records how long the server took to respond to it
+func observeRequests() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		reqID := c.GetHeader(requestIDHeader)
+		if reqID == `` {
+			reqID = uuid.New().String()
+		}
+		c.Header(requestIDHeader, reqID)
+
+		l := logging.L().With(logging.RequestID(reqID))
+		if sc := trace.SpanContextFromContext(c.Request.Context()); sc.IsValid() {
+			// so that the logs can be found from the trace, and the other way around
+			l = l.With(logging.TraceID(sc.TraceID().String()))
+		}
+		c.Request = c.Request.WithContext(logging.WithLogger(c.Request.Context(), l))
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == `` {
+			route = unmatchedRoute
+		}
+		d := time.Since(start)
+		status := c.Writer.Status()
+
+		metrics.ObserveRequest(c.Request.Method, route, status, d)
+		l.Info(`Handled request`,
+			zap.String(`method`, c.Request.Method),
+			zap.String(`route`, route),
+			zap.String(`path`, c.Request.URL.Path),
+			zap.Int(`status`, status),
+			zap.Duration(`duration`, d),
+		)
+	}
+}
+
+// timeoutRequests cancels the context of each request that takes longer than the
+// timeout, so that its database work stops and its transaction is rolled back.
+// A timeout of zero lets requests take as long as they need.
+func timeoutRequests(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// drainNPCMoves makes the NPCs' moves, and delivers the notifications, before the
+// request is finished. A lambda can be frozen as soon as it has responded, so it
+// can't leave them to the background.
+func drainNPCMoves() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		npcMoves.drain(c.Request.Context())
+		notifications.wait(c.Request.Context())
+	}
+}
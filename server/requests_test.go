@@ -0,0 +1,90 @@
+package server
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserveRequestsSetsRequestID(t *testing.T) {
+	router := gin.New()
+	router.Use(observeRequests())
+	router.GET(`/api/health`, func(c *gin.Context) {
+		c.String(http.StatusOK, `Good to go`)
+	})
+
+	w, err := performRequest(router, `GET`, `/api/health`, nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEmpty(t, w.Header().Get(requestIDHeader))
+
+	req, err := http.NewRequest(`GET`, `/api/health`, nil)
+	require.NoError(t, err)
+	req.Header.Set(requestIDHeader, `my-request`)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, `my-request`, w.Header().Get(requestIDHeader))
+}
+
+func TestObserveRequestsRecordsRoutes(t *testing.T) {
+	router := gin.New()
+	router.Use(observeRequests())
+	_, restRouter := newServerAndRouter(t)
+	router.GET(`/player/:username`, gin.WrapH(restRouter))
+	router.GET(`/metrics`, gin.WrapH(restRouter))
+
+	_, err := performRequest(router, `GET`, `/player/alice`, nil)
+	require.NoError(t, err)
+	_, err = performRequest(router, `GET`, `/not/a/route`, nil)
+	require.NoError(t, err)
+
+	w, err := performRequest(router, `GET`, `/metrics`, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, w.Code)
+	bs, err := ioutil.ReadAll(w.Body)
+	require.NoError(t, err)
+	metrics := string(bs)
+
+	assert.Contains(t, metrics,
+		`cribbage_http_request_duration_seconds_count{method="GET",route="/player/:username",status="404"} 1`)
+	assert.Contains(t, metrics,
+		`cribbage_http_request_duration_seconds_count{method="GET",route="unmatched",status="404"} 1`)
+	assert.NotContains(t, metrics, `/not/a/route`)
+}
+
+func TestTimeoutRequests(t *testing.T) {
+	testCases := []struct {
+		msg         string
+		timeout     time.Duration
+		expDeadline bool
+	}{{
+		msg:         `limited`,
+		timeout:     time.Minute,
+		expDeadline: true,
+	}, {
+		msg:         `no limit`,
+		timeout:     0,
+		expDeadline: false,
+	}}
+
+	for _, tc := range testCases {
+		router := gin.New()
+		router.Use(timeoutRequests(tc.timeout))
+		router.GET(`/deadline`, func(c *gin.Context) {
+			_, ok := c.Request.Context().Deadline()
+			c.String(http.StatusOK, strconv.FormatBool(ok))
+		})
+
+		w, err := performRequest(router, `GET`, `/deadline`, nil)
+		require.NoError(t, err, tc.msg)
+		require.Equal(t, http.StatusOK, w.Code, tc.msg)
+		assert.Equal(t, strconv.FormatBool(tc.expDeadline), w.Body.String(), tc.msg)
+	}
+}
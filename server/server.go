@@ -3,41 +3,74 @@ package server
 import (
 	"context"
 	"errors"
+	"expvar"
 	"io/ioutil"
 	"net/http"
 	"os"
 	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/apex/gateway"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/contrib/static"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.uber.org/zap"
 
 	"github.com/joshprzybyszewski/cribbage/jsonutils"
 	"github.com/joshprzybyszewski/cribbage/logic/suggestions"
 	"github.com/joshprzybyszewski/cribbage/model"
 	"github.com/joshprzybyszewski/cribbage/network"
 	"github.com/joshprzybyszewski/cribbage/server/interaction"
+	"github.com/joshprzybyszewski/cribbage/server/logging"
+	"github.com/joshprzybyszewski/cribbage/server/metrics"
 	"github.com/joshprzybyszewski/cribbage/server/persistence"
+	"github.com/joshprzybyszewski/cribbage/server/tracing"
+)
+
+const (
+	// eventsPollWait is how long a poll for a player's events waits for one. It is
+	// shorter than the timeouts that proxies usually put on requests.
+	eventsPollWait = 20 * time.Second
+
+	// readyPingTimeout is how long the readiness check waits for the database to answer
+	readyPingTimeout = 5 * time.Second
 )
 
 type cribbageServer struct {
 	dbFactory persistence.DBFactory
+	pollWait  time.Duration
+
+	// draining is set to 1 (atomically) once the server is shutting down
+	draining int32
 }
 
 func newCribbageServer(dbFactory persistence.DBFactory) *cribbageServer {
 	return &cribbageServer{
 		dbFactory: dbFactory,
+		pollWait:  eventsPollWait,
 	}
 }
 
 func (cs *cribbageServer) addRESTRoutes(router *gin.Engine) {
-	// health check route
+	// liveness check route: the process is up, even if it can't do anything useful
 	router.GET(`/api/health`, func(c *gin.Context) {
 		c.String(http.StatusOK, `Healthy!`)
 	})
+	// readiness check route: the server can take requests right now
+	router.GET(`/api/health/ready`, cs.ginGetReady)
+
+	// the documentation for these routes
+	router.GET(`/api/openapi.json`, cs.ginGetOpenAPI)
+
+	// exported variables, such as the hit rates of the persistence cache
+	router.GET(`/debug/vars`, gin.WrapH(expvar.Handler()))
+
+	// metrics for Prometheus to scrape
+	router.GET(`/metrics`, gin.WrapH(metrics.Handler()))
 
 	// Simple group: create
 	create := router.Group(`/create`)
@@ -45,9 +78,36 @@ func (cs *cribbageServer) addRESTRoutes(router *gin.Engine) {
 		create.POST(`/game`, cs.ginPostCreateGame)
 		create.POST(`/player`, cs.ginPostCreatePlayer)
 		create.POST(`/interaction`, cs.ginPostCreateInteraction)
+		create.POST(`/lobby`, cs.ginPostCreateLobby)
+		create.POST(`/invitation`, cs.ginPostCreateInvitation)
+	}
+
+	// Simple group: lobby
+	lobby := router.Group(`/lobby/:lobbyID`)
+	{
+		lobby.GET(``, cs.ginGetLobby)
+		lobby.POST(`/join`, cs.ginPostJoinLobby)
+		lobby.POST(`/leave`, cs.ginPostLeaveLobby)
+		lobby.POST(`/accept`, cs.ginPostAcceptInvitation)
+		lobby.POST(`/decline`, cs.ginPostDeclineInvitation)
+	}
+
+	// Simple group: lobbies
+	lobbies := router.Group(`/lobbies`)
+	{
+		lobbies.GET(`/open`, cs.ginGetOpenLobbies)
+		lobbies.POST(`/match`, cs.ginPostMatchLobby)
+		lobbies.GET(`/invitations`, cs.ginGetInvitations)
 	}
 
 	router.GET(`/game/:gameID`, cs.ginGetGame)
+	router.GET(`/game/:gameID/shuffles`, cs.ginGetShuffles)
+	router.GET(`/game/:gameID/spectators`, cs.ginGetSpectators)
+	router.POST(`/game/:gameID/spectate`, cs.ginPostSpectate)
+	router.POST(`/game/:gameID/unspectate`, cs.ginPostUnspectate)
+	router.POST(`/game/:gameID/shoulder`, cs.ginPostShoulder)
+	router.GET(`/game/:gameID/chat`, cs.ginGetChat)
+	router.POST(`/game/:gameID/chat`, cs.ginPostChat)
 
 	// Simple group: games
 	game := router.Group(`/games`)
@@ -59,6 +119,7 @@ func (cs *cribbageServer) addRESTRoutes(router *gin.Engine) {
 	player := router.Group(`/player`)
 	{
 		player.GET(`/:username`, cs.ginGetPlayer)
+		player.GET(`/:username/events`, cs.ginGetPlayerEvents)
 	}
 
 	router.POST(`/action`, cs.ginPostAction)
@@ -98,9 +159,18 @@ func isLambda() bool {
 	return ok && val == `true`
 }
 
-func (cs *cribbageServer) serve() error {
-	router := gin.Default()
+func (cs *cribbageServer) serve(gs *grpcServer, jobs *backgroundJobs) error {
+	router := gin.New()
+	router.Use(
+		gin.Recovery(),
+		otelgin.Middleware(tracing.ServiceName),
+		observeRequests(),
+		timeoutRequests(*requestTimeout),
+	)
 	router.Use(cors.New(getCORSConfig()))
+	if isLambda() {
+		router.Use(drainNPCMoves())
+	}
 
 	cs.addRESTRoutes(router)
 
@@ -116,7 +186,35 @@ func (cs *cribbageServer) serve() error {
 
 	cs.addWasmHandlers(router)
 	cs.addReactHandlers(router)
-	return router.Run(`:` + strconv.Itoa(*restPort))
+	return cs.serveUntilStopped(&http.Server{
+		Addr:    `:` + strconv.Itoa(*restPort),
+		Handler: router,
+	}, gs, jobs)
+}
+
+// ginGetReady says whether the server should be sent requests. It isn't ready when
+// it can't reach its database, or once it has started shutting down.
+func (cs *cribbageServer) ginGetReady(c *gin.Context) {
+	if cs.isDraining() {
+		c.String(http.StatusServiceUnavailable, `Shutting down`)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readyPingTimeout)
+	defer cancel()
+
+	err := cs.dbFactory.Ping(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Warn(`Not ready: could not reach the database`, zap.Error(err))
+		c.String(http.StatusServiceUnavailable, `Error: could not reach the database: %s`, err)
+		return
+	}
+
+	c.String(http.StatusOK, `Ready!`)
+}
+
+func (cs *cribbageServer) isDraining() bool {
+	return atomic.LoadInt32(&cs.draining) == 1
 }
 
 func (cs *cribbageServer) ginPostCreateGame(c *gin.Context) {
@@ -140,7 +238,21 @@ func (cs *cribbageServer) ginPostCreateGame(c *gin.Context) {
 		return
 	}
 
-	ctx := context.Background()
+	settings, err := network.ConvertFromGameSettings(gameReq.Settings)
+	if err != nil {
+		c.String(http.StatusBadRequest, `Invalid settings: %s`, err)
+		return
+	}
+	if settings.OnTimeout == model.AutoPlayOnTimeout {
+		switch settings.AutoPlayNPC {
+		case interaction.Simple, interaction.Calc, interaction.Dumb:
+		default:
+			c.String(http.StatusBadRequest, `Invalid settings: unsupported autoplay NPC`)
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
 	db, err := cs.dbFactory.New(ctx)
 	if err != nil {
 		c.String(http.StatusInternalServerError, `dbFactory.New() error: %s`, err)
@@ -148,7 +260,7 @@ func (cs *cribbageServer) ginPostCreateGame(c *gin.Context) {
 	}
 	defer db.Close()
 
-	g, err := createGame(ctx, db, pIDs)
+	g, err := createGame(ctx, db, pIDs, settings)
 	if err != nil {
 		c.String(http.StatusInternalServerError, `createGame error: %s`, err)
 		return
@@ -178,7 +290,7 @@ func (cs *cribbageServer) ginPostCreatePlayer(c *gin.Context) {
 		return
 	}
 
-	ctx := context.Background()
+	ctx := c.Request.Context()
 	db, err := cs.dbFactory.New(ctx)
 	if err != nil {
 		c.String(http.StatusInternalServerError, `dbFactory.New() error: %s`, err)
@@ -234,12 +346,31 @@ func (cs *cribbageServer) ginPostCreateInteraction(c *gin.Context) {
 			Mode: interaction.NPC,
 			Info: cir.NPCType,
 		})
+	case cir.Polling:
+		pm = interaction.New(pID, interaction.Means{
+			Mode: interaction.Polling,
+		})
+	case len(cir.WebhookURL) > 0:
+		wi := interaction.WebhookInfo{
+			URL:    cir.WebhookURL,
+			Secret: cir.WebhookSecret,
+		}
+		err = wi.Validate()
+		if err != nil {
+			c.String(http.StatusBadRequest, `Error: %s`, err)
+			return
+		}
+		pm = interaction.New(pID, interaction.Means{
+			Mode: interaction.Webhook,
+			Info: wi,
+		})
 	default:
 		c.String(http.StatusBadRequest, `unsupported interaction mode`)
 		return
 	}
+	pm.AutoPlay = cir.AutoPlay
 
-	ctx := context.Background()
+	ctx := c.Request.Context()
 	db, err := cs.dbFactory.New(ctx)
 	if err != nil {
 		c.String(http.StatusInternalServerError, `dbFactory.New() error: %s`, err)
@@ -256,6 +387,7 @@ func (cs *cribbageServer) ginPostCreateInteraction(c *gin.Context) {
 }
 
 // GET /game/:gameID?player=<playerID>
+// GET /game/:gameID?spectator=<playerID>
 func (cs *cribbageServer) ginGetGame(c *gin.Context) {
 	gID, err := getGameIDFromContext(c)
 	if err != nil {
@@ -263,7 +395,7 @@ func (cs *cribbageServer) ginGetGame(c *gin.Context) {
 		return
 	}
 
-	ctx := context.Background()
+	ctx := c.Request.Context()
 	db, err := cs.dbFactory.New(ctx)
 	if err != nil {
 		c.String(http.StatusInternalServerError, `dbFactory.New() error: %s`, err)
@@ -281,6 +413,17 @@ func (cs *cribbageServer) ginGetGame(c *gin.Context) {
 		return
 	}
 
+	if sID := c.Query(`spectator`); sID != `` {
+		var s model.Spectator
+		s, err = getSpectator(ctx, db, gID, model.PlayerID(sID))
+		if err != nil {
+			writeSpectatorError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, network.ConvertToGetGameResponseForSpectator(g, s.Shoulder))
+		return
+	}
+
 	pID := c.Query(`player`)
 	if pID == `` {
 		resp := network.ConvertToGetGameResponse(g)
@@ -295,6 +438,35 @@ func (cs *cribbageServer) ginGetGame(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// GET /game/:gameID/shuffles
+func (cs *cribbageServer) ginGetShuffles(c *gin.Context) {
+	gID, err := getGameIDFromContext(c)
+	if err != nil {
+		c.String(http.StatusBadRequest, `Invalid GameID: %v`, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	db, err := cs.dbFactory.New(ctx)
+	if err != nil {
+		c.String(http.StatusInternalServerError, `dbFactory.New() error: %s`, err)
+		return
+	}
+	defer db.Close()
+
+	g, err := getGame(ctx, db, gID)
+	if err != nil {
+		if err == persistence.ErrGameNotFound {
+			c.String(http.StatusNotFound, `Game not found`)
+			return
+		}
+		c.String(http.StatusInternalServerError, `Error: %s`, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, network.ConvertToGetShufflesResponse(g))
+}
+
 func getGameIDFromContext(c *gin.Context) (model.GameID, error) {
 	gIDStr := c.Param(`gameID`)
 	n, err := strconv.Atoi(gIDStr)
@@ -308,7 +480,7 @@ func getGameIDFromContext(c *gin.Context) (model.GameID, error) {
 func (cs *cribbageServer) ginGetPlayer(c *gin.Context) {
 	pID := model.PlayerID(c.Param(`username`))
 
-	ctx := context.Background()
+	ctx := c.Request.Context()
 	db, err := cs.dbFactory.New(ctx)
 	if err != nil {
 		c.String(http.StatusInternalServerError, `dbFactory.New() error: %s`, err)
@@ -329,6 +501,44 @@ func (cs *cribbageServer) ginGetPlayer(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// GET /player/:username/events?after=<eventID>
+// This is a long poll: it waits until the player has an event, or until pollWait has passed.
+func (cs *cribbageServer) ginGetPlayerEvents(c *gin.Context) {
+	pID := model.PlayerID(c.Param(`username`))
+
+	var after uint64
+	if a := c.Query(`after`); a != `` {
+		var err error
+		after, err = strconv.ParseUint(a, 10, 64)
+		if err != nil {
+			c.String(http.StatusBadRequest, `Invalid after: %s`, a)
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	db, err := cs.dbFactory.New(ctx)
+	if err != nil {
+		c.String(http.StatusInternalServerError, `dbFactory.New() error: %s`, err)
+		return
+	}
+	_, err = getPlayer(ctx, db, pID)
+	// don't hold on to the connection while we wait
+	db.Close()
+	if err != nil {
+		if err == persistence.ErrPlayerNotFound {
+			c.String(http.StatusNotFound, `Player not found`)
+			return
+		}
+		c.String(http.StatusInternalServerError, `Error: %s`, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cs.pollWait)
+	defer cancel()
+	c.JSON(http.StatusOK, interaction.PollEvents(ctx, pID, after))
+}
+
 // GET /games/active?playerID=pID
 func (cs *cribbageServer) ginGetActiveGamesForPlayer(c *gin.Context) {
 	pID := model.PlayerID(c.Query(`playerID`))
@@ -337,7 +547,7 @@ func (cs *cribbageServer) ginGetActiveGamesForPlayer(c *gin.Context) {
 		return
 	}
 
-	ctx := context.Background()
+	ctx := c.Request.Context()
 	db, err := cs.dbFactory.New(ctx)
 	if err != nil {
 		c.String(http.StatusInternalServerError, `dbFactory.New() error: %s`, err)
@@ -381,7 +591,7 @@ func (cs *cribbageServer) ginPostAction(c *gin.Context) {
 		return
 	}
 
-	ctx := context.Background()
+	ctx := c.Request.Context()
 	db, err := cs.dbFactory.New(ctx)
 	if err != nil {
 		c.String(http.StatusInternalServerError, `dbFactory.New() error: %s`, err)
@@ -389,8 +599,14 @@ func (cs *cribbageServer) ginPostAction(c *gin.Context) {
 	}
 	defer db.Close()
 
-	err = handleAction(ctx, db, action)
+	err = handlePlayerAction(ctx, db, action)
 	if err != nil {
+		logging.FromContext(c.Request.Context()).Info(`Could not handle action`,
+			logging.GameID(action.GameID),
+			logging.PlayerID(action.ID),
+			logging.Blocker(action.Overcomes),
+			zap.Error(err),
+		)
 		c.String(http.StatusBadRequest, `Error: %s`, err)
 		return
 	}
@@ -407,7 +623,9 @@ func (cs *cribbageServer) ginGetSuggestHand(c *gin.Context) {
 		return
 	}
 
+	_, span := tracing.Start(c.Request.Context(), `suggestions.GetAllTosses`)
 	summaries, err := suggestions.GetAllTosses(hand)
+	tracing.End(span, err)
 	if err != nil {
 		c.String(http.StatusBadRequest, `Error: %s`, err)
 		return
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -17,8 +18,10 @@ import (
 
 	"github.com/joshprzybyszewski/cribbage/model"
 	"github.com/joshprzybyszewski/cribbage/network"
+	"github.com/joshprzybyszewski/cribbage/server/interaction"
 	"github.com/joshprzybyszewski/cribbage/server/persistence"
 	"github.com/joshprzybyszewski/cribbage/server/persistence/memory"
+	"github.com/joshprzybyszewski/cribbage/server/play"
 )
 
 func performRequest(r http.Handler, method, path string, body io.Reader) (*httptest.ResponseRecorder, error) {
@@ -52,7 +55,7 @@ func prepareBody(t *testing.T, v interface{}) io.Reader {
 
 func newServerAndRouter(_ *testing.T) (*cribbageServer, http.Handler) {
 	// first make sure the db is completely cleared
-	dbf := memory.NewFactory()
+	dbf := memory.NewFactory(play.Replay)
 	memory.Clear()
 	cs := newCribbageServer(dbf)
 	router := gin.Default()
@@ -67,7 +70,7 @@ func seedPlayers(t *testing.T, dbf persistence.DBFactory, n int) []model.PlayerI
 	pIDs := make([]model.PlayerID, n)
 	for i := range pIDs {
 		idStr := fmt.Sprintf(`p%d`, i+1)
-		err := db.CreatePlayer(model.Player{
+		err := db.CreatePlayer(context.Background(), model.Player{
 			ID:   model.PlayerID(idStr),
 			Name: `name`,
 		})
@@ -312,6 +315,41 @@ func TestGinPostCreateInteraction(t *testing.T) {
 		},
 		expCode: http.StatusOK,
 		expErr:  ``,
+	}, {
+		msg: `webhook`,
+		reqData: network.CreateInteractionRequest{
+			PlayerID:      `p2`,
+			WebhookURL:    `https://example.com/cribbage`,
+			WebhookSecret: `a-secret-that-is-long-enough`,
+		},
+		expCode: http.StatusOK,
+		expErr:  ``,
+	}, {
+		msg: `webhook without a scheme`,
+		reqData: network.CreateInteractionRequest{
+			PlayerID:      `p2`,
+			WebhookURL:    `example.com/cribbage`,
+			WebhookSecret: `a-secret-that-is-long-enough`,
+		},
+		expCode: http.StatusBadRequest,
+		expErr:  `Error: ` + network.ErrInvalidWebhookURL.Error(),
+	}, {
+		msg: `webhook with a short secret`,
+		reqData: network.CreateInteractionRequest{
+			PlayerID:      `p2`,
+			WebhookURL:    `https://example.com/cribbage`,
+			WebhookSecret: `shh`,
+		},
+		expCode: http.StatusBadRequest,
+		expErr:  `Error: ` + network.ErrInvalidWebhookSecret.Error(),
+	}, {
+		msg: `polling`,
+		reqData: network.CreateInteractionRequest{
+			PlayerID: `p3`,
+			Polling:  true,
+		},
+		expCode: http.StatusOK,
+		expErr:  ``,
 	}, {
 		msg: `unsupported interaction mode`,
 		reqData: network.CreateInteractionRequest{
@@ -347,7 +385,7 @@ func TestGinGetGame(t *testing.T) {
 		db, err := cs.dbFactory.New(ctx)
 		require.NoError(t, err)
 		defer db.Close()
-		g, err := createGame(ctx, db, pIDs)
+		g, err := createGame(ctx, db, pIDs, model.GameSettings{})
 		require.NoError(t, err)
 		return g
 	}
@@ -401,6 +439,77 @@ func TestGinGetGame(t *testing.T) {
 		assert.Equal(t, g.ID, gameResp.ID)
 	}
 }
+func TestGinGetShuffles(t *testing.T) {
+	cs, router := newServerAndRouter(t)
+	pIDs := seedPlayers(t, cs.dbFactory, 2)
+
+	ctx := context.Background()
+	db, err := cs.dbFactory.New(ctx)
+	require.NoError(t, err)
+	defer db.Close()
+	g, err := createGame(ctx, db, pIDs, model.GameSettings{
+		ProvablyFair: true,
+	})
+	require.NoError(t, err)
+
+	w, err := performRequest(router, `GET`, fmt.Sprintf(`/game/%d/shuffles`, g.ID), nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp network.GetShufflesResponse
+	readBody(t, w.Body, &resp)
+	assert.Equal(t, g.ID, resp.GameID)
+	require.Len(t, resp.Shuffles, 1)
+	assert.NotEmpty(t, resp.Shuffles[0].Commitment)
+	assert.Empty(t, resp.Shuffles[0].ServerSeed, `the seed is a secret before the deal`)
+
+	w, err = performRequest(router, `GET`, `/game/123/shuffles`, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, `Game not found`, readError(t, w))
+}
+
+func TestGinGetPlayerEvents(t *testing.T) {
+	cs, router := newServerAndRouter(t)
+	cs.pollWait = 10 * time.Millisecond
+	pIDs := seedPlayers(t, cs.dbFactory, 1)
+
+	p, err := interaction.FromPlayerMeans(interaction.New(pIDs[0], interaction.Means{
+		Mode: interaction.Polling,
+	}))
+	require.NoError(t, err)
+	require.NoError(t, p.NotifyMessage(model.Game{ID: 5}, `events test`))
+
+	w, err := performRequest(router, `GET`, `/player/p1/events`, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp network.GetPlayerEventsResponse
+	readBody(t, w.Body, &resp)
+	require.NotEmpty(t, resp.Events)
+	last := resp.Events[len(resp.Events)-1]
+	assert.Equal(t, resp.Next, last.ID)
+	assert.Equal(t, model.GameID(5), last.GameID)
+	assert.Equal(t, []string{`events test`}, last.Messages)
+
+	// there's nothing new, so it waits for the poll to time out
+	w, err = performRequest(router, `GET`, fmt.Sprintf(`/player/p1/events?after=%d`, resp.Next), nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, w.Code)
+	var empty network.GetPlayerEventsResponse
+	readBody(t, w.Body, &empty)
+	assert.Empty(t, empty.Events)
+	assert.Equal(t, resp.Next, empty.Next)
+
+	w, err = performRequest(router, `GET`, `/player/p1/events?after=last`, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, `Invalid after: last`, readError(t, w))
+
+	w, err = performRequest(router, `GET`, `/player/p9/events`, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, `Player not found`, readError(t, w))
+}
+
 func TestGinGetPlayer(t *testing.T) {
 	testCases := []struct {
 		msg      string
@@ -453,7 +562,6 @@ func TestGinPostAction(t *testing.T) {
 			action: model.PlayerAction{
 				ID:        `p1`,
 				Overcomes: 123,
-				Action:    ``,
 			},
 			expCode: http.StatusBadRequest,
 			expErr:  `Error: unknown action type`,
@@ -471,6 +579,19 @@ func TestGinPostAction(t *testing.T) {
 			expCode: http.StatusBadRequest,
 			expErr:  `Error: Should overcome DealCards, but overcomes CountCrib`,
 		}},
+	}, {
+		msg: `only the server can say a player timed out`,
+		reqs: []request{{
+			action: model.PlayerAction{
+				ID:        `p1`,
+				Overcomes: model.Forfeit,
+				Action: model.ForfeitAction{
+					TimedOut: true,
+				},
+			},
+			expCode: http.StatusBadRequest,
+			expErr:  `Error: only the server can forfeit a player for taking too long`,
+		}},
 	}, {
 		msg: `play a few actions`,
 		reqs: []request{{
@@ -528,7 +649,7 @@ func TestGinPostAction(t *testing.T) {
 		require.NoError(t, err)
 		defer db.Close()
 
-		game, err := createGame(ctx, db, pIDs)
+		game, err := createGame(ctx, db, pIDs, model.GameSettings{})
 		require.NoError(t, err)
 
 		actionsCompleted := 0
@@ -551,7 +672,7 @@ func TestGinPostAction(t *testing.T) {
 			msg := string(bs)
 			// verify the players are in the game
 			assert.Equal(t, `action handled`, msg)
-			g, err := db.GetGame(game.ID)
+			g, err := db.GetGame(context.Background(), game.ID)
 			require.NoError(t, err)
 			assert.Equal(t, actionsCompleted, len(g.Actions))
 		}
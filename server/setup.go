@@ -4,24 +4,57 @@ import (
 	"context"
 	"flag"
 	"fmt"
-	"log"
 	"time"
 
+	"go.uber.org/zap"
+
 	"github.com/joshprzybyszewski/cribbage/model"
 	"github.com/joshprzybyszewski/cribbage/server/interaction"
+	"github.com/joshprzybyszewski/cribbage/server/logging"
+	"github.com/joshprzybyszewski/cribbage/server/npcqueue"
 	"github.com/joshprzybyszewski/cribbage/server/persistence"
+	"github.com/joshprzybyszewski/cribbage/server/persistence/cache"
 	"github.com/joshprzybyszewski/cribbage/server/persistence/dynamo"
+	"github.com/joshprzybyszewski/cribbage/server/persistence/instrumented"
 	"github.com/joshprzybyszewski/cribbage/server/persistence/memory"
 	"github.com/joshprzybyszewski/cribbage/server/persistence/mongodb"
 	"github.com/joshprzybyszewski/cribbage/server/persistence/mysql"
+	"github.com/joshprzybyszewski/cribbage/server/play"
+	"github.com/joshprzybyszewski/cribbage/server/tracing"
 )
 
 var (
 	restPort = flag.Int(`restPort`, 8080, `The port where we start up our REST server`)
+	grpcPort = flag.Int(`grpcPort`, 0, `The port where we start up our gRPC server. It is off unless this is set`)
+
+	requestTimeout = flag.Duration(
+		`request_timeout`, 30*time.Second,
+		`How long the server works on a request before it is cancelled and its changes are rolled back. `+
+			`Set to 0 for no limit`,
+	)
+
+	shutdownTimeout = flag.Duration(
+		`shutdown_timeout`, 30*time.Second,
+		`How long the server waits for its requests and NPC moves to finish after it is told to stop`,
+	)
 
 	database = flag.String(`db`, `mysql`, `Set to the type of database to access. Options: "mysql", "mongo", "memory"`)
 	dbURI    = flag.String(`dbURI`, ``, `The uri to the database. default empty string uses whatever localhost is`)
 
+	developmentLogs = flag.Bool(
+		`log_development`, false,
+		`Set to true to write readable log lines instead of JSON.`,
+	)
+
+	traceExporter = flag.String(
+		`trace_exporter`, ``,
+		`Set to where the server should export its traces. Options: "otlp", "stdout". default empty string exports nowhere`, //nolint:lll
+	)
+	traceEndpoint = flag.String(
+		`trace_endpoint`, ``,
+		`The address of the OTLP collector. default empty string uses localhost:4317`,
+	)
+
 	dsnUser     = flag.String(`dsn_user`, `root`, `The DSN user for the MySQL DB`)
 	dsnPassword = flag.String(`dsn_password`, ``, `The password for the user for the MySQL DB`)
 	dsnHost     = flag.String(`dsn_host`, `127.0.0.1`, `The host for the MySQL DB`)
@@ -37,16 +70,104 @@ var (
 		`mysql_create_error_is_ok`, false,
 		`Set to true when you don't care if table creation fails on startup.`,
 	)
+
+	cacheDB = flag.Bool(
+		`db_cache`, false,
+		`Set to true to keep a read-through cache of players and games in front of the database.`,
+	)
+	cacheTTL = flag.Duration(
+		`db_cache_ttl`, time.Minute,
+		`How long the read-through cache keeps an entry before going back to the database`,
+	)
+
+	compactFinishedGames = flag.Bool(
+		`compact_finished_games`, false,
+		`Set to true to throw away the unneeded history of games once they are over.`,
+	)
+	compactionCheckpointInterval = flag.Uint(
+		`compaction_checkpoint_interval`, 10,
+		`When compacting a finished game, keep a snapshot every this many actions`,
+	)
+	compactionPeriod = flag.Duration(
+		`compaction_period`, time.Minute,
+		`How often the background job compacts the games that have finished`,
+	)
+
+	enforceMoveTimeouts = flag.Bool(
+		`enforce_move_timeouts`, true,
+		`Set to false to not act on the players who take longer than their game's move timeout, `+
+			`for example when EnforceMoveTimeouts is scheduled instead.`,
+	)
+	moveTimeoutPeriod = flag.Duration(
+		`move_timeout_period`, 10*time.Second,
+		`How often the background job checks for players who have taken too long`,
+	)
+
+	npcQueueFile = flag.String(
+		`npc_queue_file`, ``,
+		`The file where the NPCs' moves are queued, instead of the database. `+
+			`default empty string queues them in the database`,
+	)
+	npcQueuePeriod = flag.Duration(
+		`npc_queue_period`, 5*time.Second,
+		`How often the NPC move queue looks for moves that are due to be retried`,
+	)
+	npcRetryBackoff = flag.Duration(
+		`npc_retry_backoff`, time.Second,
+		`How long to wait before retrying an NPC move that failed. It doubles with each failure`,
+	)
+	npcRetryMaxBackoff = flag.Duration(
+		`npc_retry_max_backoff`, 5*time.Minute,
+		`The longest to wait before retrying an NPC move that keeps failing`,
+	)
+	npcAlertAfter = flag.Int(
+		`npc_alert_after`, 5,
+		`How many times an NPC move can fail before its failures are logged as errors and counted as alerts`,
+	)
+	npcMaxAttempts = flag.Int(
+		`npc_max_attempts`, 20,
+		`How many times an NPC move can fail before it is dropped. 0 retries it until it is made`,
+	)
+
+	activeGamesPeriod = flag.Duration(
+		`active_games_period`, time.Minute,
+		`How often the background job counts the games that are not over for the metrics`,
+	)
+
+	fillLobbies = flag.Bool(
+		`fill_lobbies`, true,
+		`Set to false to never seat NPCs in lobbies that have waited too long for players.`,
+	)
+	lobbyFillPeriod = flag.Duration(
+		`lobby_fill_period`, 10*time.Second,
+		`How often the background job fills the lobbies that are done waiting for players`,
+	)
 )
 
 // Setup connects to a database and starts serving requests
 func Setup() error {
 	loadConfig()
-	log.Printf("Using %s for persistence\n", *database)
+	if *developmentLogs {
+		if err := logging.UseDevelopment(); err != nil {
+			return err
+		}
+	}
+	logging.L().Info(`Using database for persistence`, zap.String(`db`, *database))
 
 	ctx, fn := context.WithTimeout(context.Background(), 4*time.Minute)
 	defer fn()
 
+	stopTracing, err := tracing.Setup(ctx, *traceExporter, *traceEndpoint)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// send off the spans that are still waiting to be exported
+		if stopErr := stopTracing(context.Background()); stopErr != nil {
+			logging.L().Error(`Could not stop tracing`, zap.Error(stopErr))
+		}
+	}()
+
 	dbFactory, err := getDBFactory(ctx, factoryConfig{
 		canRunCreateStmts: true,
 	})
@@ -54,14 +175,90 @@ func Setup() error {
 		return err
 	}
 
+	if *cacheDB {
+		logging.L().Info(`Caching players and games`, zap.Duration(`ttl`, *cacheTTL))
+		dbFactory = cache.NewFactory(dbFactory, *cacheTTL)
+	}
+
 	cs := newCribbageServer(dbFactory)
+	jobs := newBackgroundJobs()
+	err = startNPCMoves(jobs, dbFactory)
+	if err != nil {
+		return err
+	}
+	startBackgroundJobs(jobs, dbFactory)
 
 	err = seedNPCs(ctx, dbFactory)
 	if err != nil {
 		return err
 	}
 
-	return cs.serve()
+	return cs.serve(startGRPC(dbFactory), jobs)
+}
+
+// startGRPC starts serving the gRPC API, unless it has been turned off
+func startGRPC(dbFactory persistence.DBFactory) *grpcServer {
+	if *grpcPort <= 0 || isLambda() {
+		return nil
+	}
+
+	gs := newGRPCServer(dbFactory)
+	go func() {
+		err := gs.serve(*grpcPort)
+		if err != nil {
+			logging.L().Error(`gRPC server stopped`, zap.Error(err))
+		}
+	}()
+	return gs
+}
+
+// startNPCMoves starts the queue which makes the moves that the NPCs decide on
+func startNPCMoves(jobs *backgroundJobs, dbFactory persistence.DBFactory) error {
+	// the moves are kept in the database by default, so that they're still made after a
+	// restart, and so that a lambda can make the moves that another one queued
+	store := newDBNPCMoveStore(dbFactory)
+	if *npcQueueFile != `` {
+		logging.L().Info(`Queueing NPC moves in a file`, zap.String(`file`, *npcQueueFile))
+		var err error
+		store, err = npcqueue.NewFileStore(*npcQueueFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	npcMoves = newNPCMover(dbFactory, store, npcqueue.Config{
+		Period:      *npcQueuePeriod,
+		Backoff:     *npcRetryBackoff,
+		MaxBackoff:  *npcRetryMaxBackoff,
+		AlertAfter:  *npcAlertAfter,
+		MaxAttempts: *npcMaxAttempts,
+	})
+	jobs.start(npcMoves.run)
+	return nil
+}
+
+// startBackgroundJobs starts the jobs that the flags have turned on
+func startBackgroundJobs(jobs *backgroundJobs, dbFactory persistence.DBFactory) {
+	if *compactFinishedGames {
+		jobs.start(newCompactor(
+			dbFactory,
+			persistence.RetentionPolicy{
+				CheckpointInterval: *compactionCheckpointInterval,
+			},
+			*compactionPeriod,
+		).run)
+	}
+
+	if *enforceMoveTimeouts {
+		jobs.start(newTimeoutScheduler(dbFactory, *moveTimeoutPeriod).run)
+	}
+
+	if *fillLobbies {
+		lobbyFiller = newFiller(dbFactory, *lobbyFillPeriod)
+		jobs.start(lobbyFiller.run)
+	}
+
+	jobs.start(newActiveGamesCounter(dbFactory, *activeGamesPeriod).run)
 }
 
 type factoryConfig struct {
@@ -69,13 +266,22 @@ type factoryConfig struct {
 }
 
 func getDBFactory(ctx context.Context, cfg factoryConfig) (persistence.DBFactory, error) {
+	dbf, err := newDBFactory(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return instrumented.NewFactory(dbf, *database), nil
+}
+
+func newDBFactory(ctx context.Context, cfg factoryConfig) (persistence.DBFactory, error) {
 	switch *database {
 	case `mongo`:
-		log.Println("Creating mongodb factory")
-		return mongodb.NewFactory(*dbURI)
+		logging.L().Info(`Creating mongodb factory`)
+		return mongodb.NewFactory(*dbURI, play.Replay)
 	case `dynamodb`:
-		log.Println("Creating dynamodb factory")
-		return dynamo.NewFactory(*dbURI)
+		logging.L().Info(`Creating dynamodb factory`)
+		return dynamo.NewFactory(*dbURI, play.Replay)
 	case `mysql`:
 		cfg := mysql.Config{
 			DSNUser:         *dsnUser,
@@ -87,19 +293,20 @@ func getDBFactory(ctx context.Context, cfg factoryConfig) (persistence.DBFactory
 			RunCreateStmts:  cfg.canRunCreateStmts && *createTables,
 			CreateErrorIsOk: cfg.canRunCreateStmts && *createTablesErrorIsOk,
 		}
-		log.Println("Creating mysql factory")
-		log.Printf("  len(User): %d\n", len(cfg.DSNUser))
-		log.Printf("  empty Password: %v\n", cfg.DSNPassword == ``)
-		log.Printf("  len(Host): %d\n", len(cfg.DSNHost))
-		log.Printf("  Port: %d\n", cfg.DSNPort)
-		log.Printf("  DatabaseName: %s\n", cfg.DatabaseName)
-		log.Printf("  DSNParams: %s\n", cfg.DSNParams)
-		log.Printf("  RunCreateStmts: %v\n", cfg.RunCreateStmts)
-		log.Printf("  CreateErrorIsOk: %v\n", cfg.CreateErrorIsOk)
-		return mysql.NewFactory(ctx, cfg)
+		logging.L().Info(`Creating mysql factory`,
+			zap.Int(`lenUser`, len(cfg.DSNUser)),
+			zap.Bool(`emptyPassword`, cfg.DSNPassword == ``),
+			zap.Int(`lenHost`, len(cfg.DSNHost)),
+			zap.Int(`port`, cfg.DSNPort),
+			zap.String(`databaseName`, cfg.DatabaseName),
+			zap.String(`dsnParams`, cfg.DSNParams),
+			zap.Bool(`runCreateStmts`, cfg.RunCreateStmts),
+			zap.Bool(`createErrorIsOk`, cfg.CreateErrorIsOk),
+		)
+		return mysql.NewFactory(ctx, cfg, play.Replay)
 	case `memory`:
-		log.Println("Creating in-memory factory")
-		return memory.NewFactory(), nil
+		logging.L().Info(`Creating in-memory factory`)
+		return memory.NewFactory(play.Replay), nil
 	}
 
 	return nil, fmt.Errorf(
@@ -109,18 +316,18 @@ func getDBFactory(ctx context.Context, cfg factoryConfig) (persistence.DBFactory
 		*database)
 }
 
-func seedNPCs(ctx context.Context, dbFactory persistence.DBFactory) error {
+func seedNPCs(ctx context.Context, dbFactory persistence.DBFactory) (err error) {
 	db, err := dbFactory.New(ctx)
 	if err != nil {
 		return err
 	}
 	defer db.Close()
 
-	err = db.Start()
+	err = db.Start(ctx)
 	if err != nil {
 		return err
 	}
-	defer commitOrRollback(db, &err)
+	defer commitOrRollback(ctx, db, &err)
 
 	npcIDs := []model.PlayerID{
 		interaction.Dumb,
@@ -132,10 +339,10 @@ func seedNPCs(ctx context.Context, dbFactory persistence.DBFactory) error {
 			ID:   id,
 			Name: string(id),
 		}
-		_, err = db.GetPlayer(p.ID)
+		_, err = db.GetPlayer(ctx, p.ID)
 		if err != nil {
 			if err == persistence.ErrPlayerNotFound {
-				err = db.CreatePlayer(p)
+				err = db.CreatePlayer(ctx, p)
 				if err != nil {
 					return err
 				}
@@ -144,10 +351,10 @@ func seedNPCs(ctx context.Context, dbFactory persistence.DBFactory) error {
 			}
 		}
 		pm := interaction.New(id, interaction.Means{Mode: interaction.NPC})
-		_, err = db.GetInteraction(id)
+		_, err = db.GetInteraction(ctx, id)
 		if err != nil {
 			if err == persistence.ErrInteractionNotFound {
-				err = db.SaveInteraction(pm)
+				err = db.SaveInteraction(ctx, pm)
 				if err != nil {
 					return err
 				}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	"go.uber.org/zap"
+
+	"github.com/joshprzybyszewski/cribbage/server/logging"
+)
+
+// backgroundJobs are the jobs which run for as long as the server does
+type backgroundJobs struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newBackgroundJobs() *backgroundJobs {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &backgroundJobs{
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// start runs the job in a goroutine of its own until the jobs are stopped
+func (bj *backgroundJobs) start(run func(context.Context)) {
+	bj.wg.Add(1)
+	go func() {
+		defer bj.wg.Done()
+		run(bj.ctx)
+	}()
+}
+
+// stop tells the jobs to stop, and waits for them to finish what they're in the
+// middle of, unless the context is done first
+func (bj *backgroundJobs) stop(ctx context.Context) error {
+	bj.cancel()
+
+	stopped := make(chan struct{})
+	go func() {
+		bj.wg.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// serveUntilStopped serves the REST API until the process is told to stop,
+// and then shuts everything down gracefully
+func (cs *cribbageServer) serveUntilStopped(srv *http.Server, gs *grpcServer, jobs *backgroundJobs) error {
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGTERM, os.Interrupt)
+	defer signal.Stop(stop)
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- srv.ListenAndServe()
+	}()
+
+	logging.L().Info(`Serving REST`, zap.String(`addr`, srv.Addr))
+	select {
+	case err := <-errs:
+		// it couldn't start serving, so there's nothing in flight to wait for
+		return err
+	case sig := <-stop:
+		logging.L().Info(`Shutting down`, zap.Stringer(`signal`, sig))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+	defer cancel()
+
+	return cs.shutdown(ctx, srv, gs, jobs)
+}
+
+// shutdown lets the work that has already started finish before the process exits.
+// It stops taking requests and waits for the ones in flight (such as the players'
+// actions), makes the NPC moves that those left waiting, stops the background jobs,
+// and then closes the database.
+func (cs *cribbageServer) shutdown(
+	ctx context.Context,
+	srv *http.Server,
+	gs *grpcServer,
+	jobs *backgroundJobs,
+) error {
+	// the readiness check fails from now on, so that we're not sent any more requests
+	atomic.StoreInt32(&cs.draining, 1)
+
+	err := srv.Shutdown(ctx)
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logging.L().Error(`Could not finish the requests in flight`, zap.Error(err))
+	}
+	if gs != nil {
+		gs.stop(ctx)
+	}
+
+	npcMoves.drain(ctx)
+	notifications.wait(ctx)
+
+	err = jobs.stop(ctx)
+	if err != nil {
+		logging.L().Error(`Could not wait for the background jobs to stop`, zap.Error(err))
+	}
+
+	logging.L().Info(`Closing the database`)
+	return cs.dbFactory.Close()
+}
@@ -0,0 +1,175 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/interaction"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+// checkedDBFactory lets the tests decide if the database can be reached, and
+// records when it is closed
+type checkedDBFactory struct {
+	persistence.DBFactory
+
+	pingErr error
+	closed  bool
+}
+
+func (dbf *checkedDBFactory) Ping(ctx context.Context) error {
+	if dbf.pingErr != nil {
+		return dbf.pingErr
+	}
+	return dbf.DBFactory.Ping(ctx)
+}
+
+func (dbf *checkedDBFactory) Close() error {
+	dbf.closed = true
+	return dbf.DBFactory.Close()
+}
+
+func TestGinGetReady(t *testing.T) {
+	testCases := []struct {
+		msg      string
+		pingErr  error
+		draining bool
+		expCode  int
+		expBody  string
+	}{{
+		msg:     `ready`,
+		expCode: http.StatusOK,
+		expBody: `Ready!`,
+	}, {
+		msg:     `database is unreachable`,
+		pingErr: errors.New(`connection refused`),
+		expCode: http.StatusServiceUnavailable,
+		expBody: `Error: could not reach the database: connection refused`,
+	}, {
+		msg:      `shutting down`,
+		draining: true,
+		expCode:  http.StatusServiceUnavailable,
+		expBody:  `Shutting down`,
+	}}
+
+	for _, tc := range testCases {
+		cs, router := newServerAndRouter(t)
+		cs.dbFactory = &checkedDBFactory{
+			DBFactory: cs.dbFactory,
+			pingErr:   tc.pingErr,
+		}
+		if tc.draining {
+			cs.draining = 1
+		}
+
+		w, err := performRequest(router, `GET`, `/api/health/ready`, nil)
+		require.NoError(t, err, tc.msg)
+		assert.Equal(t, tc.expCode, w.Code, tc.msg)
+		assert.Equal(t, tc.expBody, readError(t, w), tc.msg)
+
+		// it's still alive, even when it isn't ready
+		w, err = performRequest(router, `GET`, `/api/health`, nil)
+		require.NoError(t, err, tc.msg)
+		assert.Equal(t, http.StatusOK, w.Code, tc.msg)
+	}
+}
+
+func TestShutdownLetsWorkFinish(t *testing.T) {
+	cs, _ := newServerAndRouter(t)
+	dbf := &checkedDBFactory{DBFactory: cs.dbFactory}
+	cs.dbFactory = dbf
+	store := useNPCMoves(t, dbf)
+	p1 := seedPlayers(t, dbf, 1)[0]
+	ctx := context.Background()
+	require.NoError(t, seedNPCs(ctx, dbf))
+
+	// a request is in flight when we're told to stop
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	var g model.Game
+	srv := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-finish
+
+			db, err := dbf.New(r.Context())
+			if !assert.NoError(t, err) {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			defer db.Close()
+
+			g, err = createGame(r.Context(), db, []model.PlayerID{p1, interaction.Calc}, model.GameSettings{})
+			if !assert.NoError(t, err) {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			// the NPC needs to toss once the dealer has dealt
+			assert.NoError(t, handleAction(r.Context(), db, model.PlayerAction{
+				GameID:    g.ID,
+				ID:        p1,
+				Overcomes: model.DealCards,
+				Action:    model.DealAction{NumShuffles: 3},
+			}))
+			w.WriteHeader(http.StatusOK)
+		}),
+	}
+	lis, err := net.Listen(`tcp`, `127.0.0.1:0`)
+	require.NoError(t, err)
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+
+	var jobStopped int32
+	jobs := newBackgroundJobs()
+	jobs.start(func(ctx context.Context) {
+		<-ctx.Done()
+		atomic.StoreInt32(&jobStopped, 1)
+	})
+
+	respCode := make(chan int, 1)
+	go func() {
+		resp, reqErr := http.Get(`http://` + lis.Addr().String())
+		if !assert.NoError(t, reqErr) {
+			respCode <- 0
+			return
+		}
+		_ = resp.Body.Close()
+		respCode <- resp.StatusCode
+	}()
+	<-started
+
+	shutdownErr := make(chan error, 1)
+	go func() {
+		shutdownCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		shutdownErr <- cs.shutdown(shutdownCtx, srv, nil, jobs)
+	}()
+
+	require.Eventually(t, cs.isDraining, time.Second, time.Millisecond)
+	assert.False(t, dbf.closed, `the request is still using the database`)
+	close(finish)
+
+	require.NoError(t, <-shutdownErr)
+	assert.Equal(t, http.StatusOK, <-respCode)
+	assert.True(t, dbf.closed)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&jobStopped))
+
+	// the NPC's move that the request left waiting was made before we stopped
+	assert.Empty(t, queuedNPCMoves(t, store))
+	db, err := dbf.New(ctx)
+	require.NoError(t, err)
+	defer db.Close()
+	g, err = getGame(ctx, db, g.ID)
+	require.NoError(t, err)
+	assert.Equal(t, map[model.PlayerID]model.Blocker{p1: model.CribCard}, g.BlockingPlayers)
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/network"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+// POST /game/:gameID/spectate
+func (cs *cribbageServer) ginPostSpectate(c *gin.Context) {
+	cs.handleSpectator(c, spectateGame, `spectating`)
+}
+
+// POST /game/:gameID/unspectate
+func (cs *cribbageServer) ginPostUnspectate(c *gin.Context) {
+	cs.handleSpectator(c, stopSpectating, `stopped spectating`)
+}
+
+func (cs *cribbageServer) handleSpectator(
+	c *gin.Context,
+	fn func(context.Context, persistence.DB, model.GameID, model.PlayerID) error,
+	okMsg string,
+) {
+	gID, err := getGameIDFromContext(c)
+	if err != nil {
+		c.String(http.StatusBadRequest, `Invalid GameID: %v`, err)
+		return
+	}
+
+	var sr network.SpectateRequest
+	err = c.ShouldBindJSON(&sr)
+	if err != nil {
+		c.String(http.StatusBadRequest, `Error: %s`, err)
+		return
+	}
+	if sr.PlayerID == model.InvalidPlayerID {
+		c.String(http.StatusBadRequest, `Requires playerID`)
+		return
+	}
+
+	ctx := c.Request.Context()
+	db, err := cs.dbFactory.New(ctx)
+	if err != nil {
+		c.String(http.StatusInternalServerError, `dbFactory.New() error: %s`, err)
+		return
+	}
+	defer db.Close()
+
+	err = fn(ctx, db, gID, sr.PlayerID)
+	if err != nil {
+		writeSpectatorError(c, err)
+		return
+	}
+
+	c.String(http.StatusOK, okMsg)
+}
+
+// POST /game/:gameID/shoulder
+func (cs *cribbageServer) ginPostShoulder(c *gin.Context) {
+	gID, err := getGameIDFromContext(c)
+	if err != nil {
+		c.String(http.StatusBadRequest, `Invalid GameID: %v`, err)
+		return
+	}
+
+	var sr network.ShoulderRequest
+	err = c.ShouldBindJSON(&sr)
+	if err != nil {
+		c.String(http.StatusBadRequest, `Error: %s`, err)
+		return
+	}
+	if sr.PlayerID == model.InvalidPlayerID || sr.SpectatorID == model.InvalidPlayerID {
+		c.String(http.StatusBadRequest, `Requires playerID and spectatorID`)
+		return
+	}
+
+	ctx := c.Request.Context()
+	db, err := cs.dbFactory.New(ctx)
+	if err != nil {
+		c.String(http.StatusInternalServerError, `dbFactory.New() error: %s`, err)
+		return
+	}
+	defer db.Close()
+
+	err = setShoulder(ctx, db, gID, sr.PlayerID, sr.SpectatorID, sr.Allow)
+	if err != nil {
+		writeSpectatorError(c, err)
+		return
+	}
+
+	c.String(http.StatusOK, `shoulder updated`)
+}
+
+// GET /game/:gameID/spectators
+func (cs *cribbageServer) ginGetSpectators(c *gin.Context) {
+	gID, err := getGameIDFromContext(c)
+	if err != nil {
+		c.String(http.StatusBadRequest, `Invalid GameID: %v`, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	db, err := cs.dbFactory.New(ctx)
+	if err != nil {
+		c.String(http.StatusInternalServerError, `dbFactory.New() error: %s`, err)
+		return
+	}
+	defer db.Close()
+
+	ss, err := getSpectators(ctx, db, gID)
+	if err != nil {
+		writeSpectatorError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, network.ConvertToGetSpectatorsResponse(gID, ss))
+}
+
+func writeSpectatorError(c *gin.Context, err error) {
+	switch err {
+	case persistence.ErrGameNotFound:
+		c.String(http.StatusNotFound, `Game not found`)
+	case persistence.ErrPlayerNotFound:
+		c.String(http.StatusNotFound, `Player not found`)
+	case persistence.ErrSpectatorNotFound:
+		c.String(http.StatusNotFound, `Spectator not found`)
+	case errPlayerInGame, errPlayerNotInGame:
+		c.String(http.StatusBadRequest, `Error: %s`, err)
+	default:
+		c.String(http.StatusInternalServerError, `Error: %s`, err)
+	}
+}
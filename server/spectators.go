@@ -0,0 +1,174 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+var (
+	errPlayerInGame    = errors.New(`players cannot spectate their own game`)
+	errPlayerNotInGame = errors.New(`player does not exist in game`)
+)
+
+func isInGame(g model.Game, pID model.PlayerID) bool {
+	for _, p := range g.Players {
+		if p.ID == pID {
+			return true
+		}
+	}
+	return false
+}
+
+// spectateGame lets the player watch a game that they are not playing in
+func spectateGame(ctx context.Context, db persistence.DB, gID model.GameID, pID model.PlayerID) (err error) {
+	err = db.Start(ctx)
+	if err != nil {
+		return err
+	}
+	defer commitOrRollback(ctx, db, &err)
+
+	_, err = db.GetPlayer(ctx, pID)
+	if err != nil {
+		return err
+	}
+
+	g, err := db.GetGame(ctx, gID)
+	if err != nil {
+		return err
+	}
+
+	if isInGame(g, pID) {
+		err = errPlayerInGame
+		return err
+	}
+
+	err = db.SaveSpectator(ctx, model.Spectator{
+		GameID:   gID,
+		PlayerID: pID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func stopSpectating(ctx context.Context, db persistence.DB, gID model.GameID, pID model.PlayerID) error {
+	return db.RemoveSpectator(ctx, gID, pID)
+}
+
+func getSpectators(ctx context.Context, db persistence.DB, gID model.GameID) ([]model.Spectator, error) {
+	_, err := db.GetGame(ctx, gID)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.GetSpectators(ctx, gID)
+}
+
+// getSpectator returns the spectator if they are watching the game
+func getSpectator(
+	ctx context.Context,
+	db persistence.DB,
+	gID model.GameID,
+	pID model.PlayerID,
+) (model.Spectator, error) {
+	ss, err := db.GetSpectators(ctx, gID)
+	if err != nil {
+		return model.Spectator{}, err
+	}
+	for _, s := range ss {
+		if s.PlayerID == pID {
+			return s, nil
+		}
+	}
+	return model.Spectator{}, persistence.ErrSpectatorNotFound
+}
+
+// setShoulder lets (or stops) the spectator see the player's hand. A spectator
+// can only look over one player's shoulder at a time.
+func setShoulder(
+	ctx context.Context,
+	db persistence.DB,
+	gID model.GameID,
+	pID, spectatorID model.PlayerID,
+	allow bool,
+) (err error) {
+	err = db.Start(ctx)
+	if err != nil {
+		return err
+	}
+	defer commitOrRollback(ctx, db, &err)
+
+	g, err := db.GetGame(ctx, gID)
+	if err != nil {
+		return err
+	}
+
+	if !isInGame(g, pID) {
+		err = errPlayerNotInGame
+		return err
+	}
+
+	s, err := getSpectator(ctx, db, gID, spectatorID)
+	if err != nil {
+		return err
+	}
+
+	if allow {
+		s.Shoulder = pID
+	} else if s.Shoulder == pID {
+		s.Shoulder = model.InvalidPlayerID
+	}
+
+	err = db.SaveSpectator(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// notifySpectators lets everyone watching the game know that it has changed so
+// that they can get the latest view of it
+func notifySpectators(ctx context.Context, db persistence.DB, g model.Game, action model.PlayerAction) error {
+	ss, err := db.GetSpectators(ctx, g.ID)
+	if err != nil {
+		return err
+	}
+	if len(ss) == 0 {
+		return nil
+	}
+
+	players := make([]model.Player, 0, len(ss))
+	var p model.Player
+	for _, s := range ss {
+		p, err = db.GetPlayer(ctx, s.PlayerID)
+		if err != nil {
+			return err
+		}
+		players = append(players, p)
+	}
+
+	pAPIs, err := getPlayerAPIs(ctx, db, players)
+	if err != nil {
+		return err
+	}
+
+	name := string(action.ID)
+	for _, gp := range g.Players {
+		if gp.ID == action.ID {
+			name = gp.Name
+			break
+		}
+	}
+	msg := fmt.Sprintf("%s: %s", name, action.Overcomes)
+	for _, p := range players {
+		_ = pAPIs[p.ID].NotifyMessage(g, msg)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/network"
+)
+
+func TestGinPostSpectate(t *testing.T) {
+	cs, router := newServerAndRouter(t)
+	pIDs := seedPlayers(t, cs.dbFactory, 3)
+
+	ctx := context.Background()
+	db, err := cs.dbFactory.New(ctx)
+	require.NoError(t, err)
+	defer db.Close()
+	g, err := createGame(ctx, db, pIDs[:2], model.GameSettings{})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		msg     string
+		url     string
+		pID     model.PlayerID
+		expCode int
+		expErr  string
+	}{{
+		msg:     `bad game ID`,
+		url:     `/game/123zzz/spectate`,
+		pID:     pIDs[2],
+		expCode: http.StatusBadRequest,
+		expErr:  `Invalid GameID: strconv.Atoi: parsing "123zzz": invalid syntax`,
+	}, {
+		msg:     `nonexistent game`,
+		url:     `/game/123/spectate`,
+		pID:     pIDs[2],
+		expCode: http.StatusNotFound,
+		expErr:  `Game not found`,
+	}, {
+		msg:     `missing player`,
+		url:     fmt.Sprintf(`/game/%d/spectate`, g.ID),
+		expCode: http.StatusBadRequest,
+		expErr:  `Requires playerID`,
+	}, {
+		msg:     `unknown player`,
+		url:     fmt.Sprintf(`/game/%d/spectate`, g.ID),
+		pID:     `p9`,
+		expCode: http.StatusNotFound,
+		expErr:  `Player not found`,
+	}, {
+		msg:     `players cannot spectate`,
+		url:     fmt.Sprintf(`/game/%d/spectate`, g.ID),
+		pID:     pIDs[0],
+		expCode: http.StatusBadRequest,
+		expErr:  `Error: players cannot spectate their own game`,
+	}, {
+		msg:     `spectating`,
+		url:     fmt.Sprintf(`/game/%d/spectate`, g.ID),
+		pID:     pIDs[2],
+		expCode: http.StatusOK,
+	}}
+
+	for _, tc := range testCases {
+		w, err := performRequest(router, `POST`, tc.url, prepareBody(t, network.SpectateRequest{
+			PlayerID: tc.pID,
+		}))
+		require.NoError(t, err, tc.msg)
+		assert.Equal(t, tc.expCode, w.Code, tc.msg)
+		if tc.expCode != http.StatusOK {
+			assert.Equal(t, tc.expErr, readError(t, w), tc.msg)
+		}
+	}
+
+	w, err := performRequest(router, `GET`, fmt.Sprintf(`/game/%d/spectators`, g.ID), nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp network.GetSpectatorsResponse
+	readBody(t, w.Body, &resp)
+	assert.Equal(t, network.GetSpectatorsResponse{
+		GameID: g.ID,
+		Spectators: []network.Spectator{{
+			PlayerID: pIDs[2],
+		}},
+	}, resp)
+}
+
+func TestSpectatorSeesHiddenHands(t *testing.T) {
+	cs, router := newServerAndRouter(t)
+	pIDs := seedPlayers(t, cs.dbFactory, 3)
+	spectatorHeard := listenAsLocalhost(t, cs, pIDs[2])
+
+	ctx := context.Background()
+	db, err := cs.dbFactory.New(ctx)
+	require.NoError(t, err)
+	defer db.Close()
+	g, err := createGame(ctx, db, pIDs[:2], model.GameSettings{})
+	require.NoError(t, err)
+	require.NoError(t, spectateGame(ctx, db, g.ID, pIDs[2]))
+
+	require.NoError(t, handleAction(ctx, db, model.PlayerAction{
+		GameID:    g.ID,
+		ID:        g.CurrentDealer,
+		Overcomes: model.DealCards,
+		Action: model.DealAction{
+			NumShuffles: 1,
+		},
+	}))
+	assert.Equal(t, []notification{{
+		path: fmt.Sprintf(`/message/%d`, g.ID),
+		msg:  `name: DealCards`,
+	}}, spectatorHeard())
+
+	getAsSpectator := func() network.GetGameResponse {
+		w, err := performRequest(router, `GET`, fmt.Sprintf(`/game/%d?spectator=%s`, g.ID, pIDs[2]), nil)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp network.GetGameResponse
+		readBody(t, w.Body, &resp)
+		return resp
+	}
+	shoulder := func(pID model.PlayerID, allow bool) int {
+		w, err := performRequest(
+			router, `POST`, fmt.Sprintf(`/game/%d/shoulder`, g.ID),
+			prepareBody(t, network.ShoulderRequest{
+				PlayerID:    pID,
+				SpectatorID: pIDs[2],
+				Allow:       allow,
+			}),
+		)
+		require.NoError(t, err)
+		return w.Code
+	}
+	numKnown := func(cards []network.Card) int {
+		known := 0
+		for _, c := range cards {
+			if c.Name != `unknown` {
+				known++
+			}
+		}
+		return known
+	}
+
+	resp := getAsSpectator()
+	require.Len(t, resp.Hands, 2)
+	for _, pID := range pIDs[:2] {
+		assert.Len(t, resp.Hands[pID], 6)
+		assert.Zero(t, numKnown(resp.Hands[pID]))
+	}
+
+	// only the players in the game can let the spectator see their hand
+	assert.Equal(t, http.StatusBadRequest, shoulder(pIDs[2], true))
+
+	require.Equal(t, http.StatusOK, shoulder(pIDs[0], true))
+	resp = getAsSpectator()
+	assert.Equal(t, 6, numKnown(resp.Hands[pIDs[0]]))
+	assert.Zero(t, numKnown(resp.Hands[pIDs[1]]))
+
+	require.Equal(t, http.StatusOK, shoulder(pIDs[0], false))
+	resp = getAsSpectator()
+	assert.Zero(t, numKnown(resp.Hands[pIDs[0]]))
+
+	w, err := performRequest(
+		router, `POST`, fmt.Sprintf(`/game/%d/unspectate`, g.ID),
+		prepareBody(t, network.SpectateRequest{PlayerID: pIDs[2]}),
+	)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w, err = performRequest(router, `GET`, fmt.Sprintf(`/game/%d?spectator=%s`, g.ID, pIDs[2]), nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, `Spectator not found`, readError(t, w))
+}
@@ -0,0 +1,275 @@
+package server
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/interaction"
+	"github.com/joshprzybyszewski/cribbage/server/logging"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+// timeoutScheduler is a background job which acts on the games whose players have
+// taken longer than the game's MoveTimeout. The deadlines are kept in the database,
+// so the games that were waiting before a restart (or on another process) are
+// acted on too.
+type timeoutScheduler struct {
+	dbFactory persistence.DBFactory
+	period    time.Duration
+}
+
+func newTimeoutScheduler(
+	dbFactory persistence.DBFactory,
+	period time.Duration,
+) *timeoutScheduler {
+	return &timeoutScheduler{
+		dbFactory: dbFactory,
+		period:    period,
+	}
+}
+
+// setMoveDeadline (re)starts the clock on the game's current blockers. It clears
+// the deadline of games which are over or which are not waiting on anybody.
+// It expects that the caller has already started a transaction.
+func setMoveDeadline(ctx context.Context, db persistence.DB, g model.Game, now time.Time) error {
+	if g.Settings.MoveTimeout <= 0 {
+		// this game never had a deadline
+		return nil
+	}
+
+	deadline := now.Add(g.Settings.MoveTimeout)
+	if g.IsOver() || len(g.BlockingPlayers) == 0 {
+		deadline = time.Time{}
+	}
+	return db.SetMoveDeadline(ctx, g.ID, deadline)
+}
+
+// run periodically checks for games that have timed out until the context is done.
+func (ts *timeoutScheduler) run(ctx context.Context) {
+	t := time.NewTicker(ts.period)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-t.C:
+			ts.handleExpired(ctx, now)
+		}
+	}
+}
+
+func (ts *timeoutScheduler) handleExpired(ctx context.Context, now time.Time) {
+	gIDs, err := ts.overdueGames(ctx, now)
+	if err != nil {
+		logging.L().Error(`Could not find the overdue games`, zap.Error(err))
+		return
+	}
+
+	for _, gID := range gIDs {
+		err = ts.handleTimeout(ctx, gID, now)
+		if err != nil {
+			logging.L().Error(`Could not handle timeout`, logging.GameID(gID), zap.Error(err))
+		}
+	}
+}
+
+func (ts *timeoutScheduler) overdueGames(ctx context.Context, now time.Time) ([]model.GameID, error) {
+	db, err := ts.dbFactory.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	return db.GetOverdueGames(ctx, now)
+}
+
+func (ts *timeoutScheduler) handleTimeout(ctx context.Context, gID model.GameID, now time.Time) error {
+	db, err := ts.dbFactory.New(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	g, err := getGame(ctx, db, gID)
+	if err != nil {
+		return err
+	}
+
+	if g.IsOver() || len(g.BlockingPlayers) == 0 {
+		// nobody is taking too long, so the deadline shouldn't have been left
+		return setMoveDeadline(ctx, db, g, now)
+	}
+
+	moved, err := playWaitingForcedMoves(ctx, db, gID, now)
+	if err != nil || moved {
+		// the game moved on, which set the next deadline
+		return err
+	}
+
+	switch g.Settings.OnTimeout {
+	case model.AutoPlayOnTimeout:
+		// each action sets the next deadline
+		return autoPlayBlockers(ctx, db, g)
+	case model.ForfeitOnTimeout:
+		// the game is over, which clears the deadline
+		return forfeitForBlocker(ctx, db, g, now)
+	}
+
+	err = remindBlockers(ctx, db, g)
+	if err != nil {
+		return err
+	}
+	// give them another MoveTimeout before we remind them again
+	return setMoveDeadline(ctx, db, g, now)
+}
+
+// playWaitingForcedMoves plays the forced moves that the game is waiting on. A player
+// can turn on auto-play while the game is waiting on them, and nothing else would
+// make those moves. It returns true if it moved the game on.
+func playWaitingForcedMoves(
+	ctx context.Context,
+	db persistence.DB,
+	gID model.GameID,
+	now time.Time,
+) (moved bool, err error) {
+	err = db.Start(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err == nil && moved {
+			gameWatchers.changed(gID)
+		}
+	}()
+	defer commitOrRollback(ctx, db, &err)
+
+	g, err := db.GetGame(ctx, gID)
+	if err != nil {
+		return false, err
+	}
+
+	pAPIs, err := getPlayerAPIs(ctx, db, g.Players)
+	if err != nil {
+		return false, err
+	}
+
+	numActions := g.NumActions()
+	err = playForcedMoves(ctx, db, &g, pAPIs, now)
+	if err != nil || g.NumActions() == numActions {
+		return false, err
+	}
+	return true, setMoveDeadline(ctx, db, g, now)
+}
+
+func remindBlockers(ctx context.Context, db persistence.DB, g model.Game) error {
+	pAPIs, err := getPlayerAPIs(ctx, db, g.Players)
+	if err != nil {
+		return err
+	}
+
+	for pID, b := range g.BlockingPlayers {
+		_ = pAPIs[pID].NotifyBlocking(b, g, `you are taking too long`)
+	}
+	// the game was already saved, so the NPCs don't need to wait for a transaction
+	npcMoves.release(ctx, db)
+	notifications.release(ctx, db)
+	return nil
+}
+
+func autoPlayBlockers(ctx context.Context, db persistence.DB, g model.Game) error {
+	for _, pID := range sortedBlockers(g) {
+		// each action changes the game, so we need the latest state
+		// before building the next one
+		cur, err := getGame(ctx, db, g.ID)
+		if err != nil {
+			return err
+		}
+		b, ok := cur.BlockingPlayers[pID]
+		if !ok || cur.IsOver() {
+			continue
+		}
+
+		pa, err := interaction.BuildNPCAction(g.Settings.AutoPlayNPC, pID, b, cur)
+		if err != nil {
+			return err
+		}
+
+		// the move was decided on the game as we read it, so we only make it if
+		// the game hasn't changed since then (for example, because the player moved)
+		err = handleActionWhen(ctx, db, pa, func(txG model.Game) (bool, error) {
+			txB, stillBlocked := txG.BlockingPlayers[pID]
+			return stillBlocked && txB == b && txG.NumActions() == cur.NumActions(), nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func forfeitForBlocker(ctx context.Context, db persistence.DB, g model.Game, now time.Time) error {
+	blockers := sortedBlockers(g)
+	if len(blockers) == 0 {
+		return nil
+	}
+	pID := blockers[0]
+	b := g.BlockingPlayers[pID]
+
+	return handleActionWhen(ctx, db, model.PlayerAction{
+		GameID:    g.ID,
+		ID:        pID,
+		Overcomes: model.Forfeit,
+		Action: model.ForfeitAction{
+			TimedOut: true,
+		},
+	}, func(txG model.Game) (bool, error) {
+		return stillTimedOut(ctx, db, txG, pID, b, now)
+	})
+}
+
+// stillTimedOut returns true if the player is still holding up the game on the
+// same blocker, and the game's deadline has passed. A player who moved after we
+// found the game (or another scheduler that acted on it) changes one of them.
+func stillTimedOut(
+	ctx context.Context,
+	db persistence.DB,
+	g model.Game,
+	pID model.PlayerID,
+	b model.Blocker,
+	now time.Time,
+) (bool, error) {
+	if g.IsOver() {
+		return false, nil
+	}
+	if cur, ok := g.BlockingPlayers[pID]; !ok || cur != b {
+		return false, nil
+	}
+
+	deadline, err := db.GetMoveDeadline(ctx, g.ID)
+	if err != nil {
+		return false, err
+	}
+	return !deadline.IsZero() && deadline.Before(now), nil
+}
+
+// sortedBlockers returns the IDs of the blocking players in the order they sit at the table
+func sortedBlockers(g model.Game) []model.PlayerID {
+	seat := make(map[model.PlayerID]int, len(g.Players))
+	for i, p := range g.Players {
+		seat[p.ID] = i
+	}
+
+	pIDs := make([]model.PlayerID, 0, len(g.BlockingPlayers))
+	for pID := range g.BlockingPlayers {
+		pIDs = append(pIDs, pID)
+	}
+	sort.Slice(pIDs, func(i, j int) bool {
+		return seat[pIDs[i]] < seat[pIDs[j]]
+	})
+	return pIDs
+}
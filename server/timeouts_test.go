@@ -0,0 +1,297 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/interaction"
+	"github.com/joshprzybyszewski/cribbage/server/persistence"
+)
+
+func TestTimeoutScheduler(t *testing.T) {
+	testCases := []struct {
+		msg      string
+		settings model.GameSettings
+		check    func(t *testing.T, g model.Game, overdue []model.GameID)
+	}{{
+		msg: `no timeout never has a deadline`,
+		settings: model.GameSettings{
+			OnTimeout: model.ForfeitOnTimeout,
+		},
+		check: func(t *testing.T, g model.Game, overdue []model.GameID) {
+			assert.False(t, g.IsOver())
+			assert.Equal(t, 0, g.NumActions())
+			assert.NotContains(t, overdue, g.ID)
+		},
+	}, {
+		msg: `notify restarts the clock`,
+		settings: model.GameSettings{
+			MoveTimeout: time.Minute,
+			OnTimeout:   model.NotifyOnTimeout,
+		},
+		check: func(t *testing.T, g model.Game, overdue []model.GameID) {
+			assert.False(t, g.IsOver())
+			assert.Equal(t, 0, g.NumActions())
+			assert.Contains(t, overdue, g.ID)
+		},
+	}, {
+		msg: `autoplay takes the action`,
+		settings: model.GameSettings{
+			MoveTimeout: time.Minute,
+			OnTimeout:   model.AutoPlayOnTimeout,
+			AutoPlayNPC: interaction.Calc,
+		},
+		check: func(t *testing.T, g model.Game, overdue []model.GameID) {
+			assert.False(t, g.IsOver())
+			require.Equal(t, 1, g.NumActions())
+			assert.Equal(t, g.Players[0].ID, g.Actions[0].ID)
+			assert.Equal(t, model.DealCards, g.Actions[0].Overcomes)
+			// now we're waiting on the crib, so the clock restarted
+			assert.Equal(t, model.BuildCrib, g.Phase)
+			assert.Contains(t, overdue, g.ID)
+		},
+	}, {
+		msg: `forfeit ends the game`,
+		settings: model.GameSettings{
+			MoveTimeout: time.Minute,
+			OnTimeout:   model.ForfeitOnTimeout,
+		},
+		check: func(t *testing.T, g model.Game, overdue []model.GameID) {
+			assert.True(t, g.IsOver())
+			assert.Equal(t, model.GameOutcome{
+				Reason:   model.TimedOut,
+				PlayerID: g.Players[0].ID,
+			}, g.Outcome)
+			assert.NotContains(t, overdue, g.ID)
+		},
+	}}
+
+	for _, tc := range testCases {
+		cs, _ := newServerAndRouter(t)
+		pIDs := seedPlayers(t, cs.dbFactory, 2)
+
+		ts := newTimeoutScheduler(cs.dbFactory, time.Hour)
+
+		ctx := context.Background()
+		db, err := cs.dbFactory.New(ctx)
+		require.NoError(t, err, tc.msg)
+
+		g, err := createGame(ctx, db, pIDs, tc.settings)
+		require.NoError(t, err, tc.msg)
+
+		// nothing has timed out yet
+		ts.handleExpired(ctx, time.Now())
+		actG, err := getGame(ctx, db, g.ID)
+		require.NoError(t, err, tc.msg)
+		assert.Equal(t, g.NumActions(), actG.NumActions(), tc.msg)
+
+		ts.handleExpired(ctx, time.Now().Add(2*time.Minute))
+		actG, err = getGame(ctx, db, g.ID)
+		require.NoError(t, err, tc.msg)
+
+		// the clock is running again on the games that are still waiting
+		overdue, err := db.GetOverdueGames(ctx, time.Now().Add(time.Hour))
+		require.NoError(t, err, tc.msg)
+		tc.check(t, actG, overdue)
+
+		db.Close()
+	}
+}
+
+func TestTimeoutSchedulerUsesTheDeadlineInTheDB(t *testing.T) {
+	cs, _ := newServerAndRouter(t)
+	pIDs := seedPlayers(t, cs.dbFactory, 2)
+
+	ctx := context.Background()
+	db, err := cs.dbFactory.New(ctx)
+	require.NoError(t, err)
+	defer db.Close()
+
+	g, err := createGame(ctx, db, pIDs, model.GameSettings{
+		MoveTimeout: time.Minute,
+		OnTimeout:   model.ForfeitOnTimeout,
+	})
+	require.NoError(t, err)
+
+	// a scheduler that has never seen the game, as if it was created before a restart
+	ts := newTimeoutScheduler(cs.dbFactory, time.Hour)
+	ts.handleExpired(ctx, time.Now().Add(2*time.Minute))
+	actG, err := getGame(ctx, db, g.ID)
+	require.NoError(t, err)
+	assert.True(t, actG.IsOver())
+
+	overdue, err := db.GetOverdueGames(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.NotContains(t, overdue, g.ID)
+}
+
+func TestTimeoutSchedulerRestartsAfterAction(t *testing.T) {
+	cs, _ := newServerAndRouter(t)
+	pIDs := seedPlayers(t, cs.dbFactory, 2)
+
+	ctx := context.Background()
+	db, err := cs.dbFactory.New(ctx)
+	require.NoError(t, err)
+	defer db.Close()
+
+	g, err := createGame(ctx, db, pIDs, model.GameSettings{
+		MoveTimeout: time.Minute,
+		OnTimeout:   model.ForfeitOnTimeout,
+	})
+	require.NoError(t, err)
+
+	// the dealer was about to time out
+	require.NoError(t, db.SetMoveDeadline(ctx, g.ID, time.Now().Add(-time.Second)))
+	overdue, err := db.GetOverdueGames(ctx, time.Now())
+	require.NoError(t, err)
+	require.Contains(t, overdue, g.ID)
+
+	// but they dealt in time (possibly through another process)
+	require.NoError(t, handleAction(ctx, db, model.PlayerAction{
+		GameID:    g.ID,
+		ID:        pIDs[0],
+		Overcomes: model.DealCards,
+		Action:    model.DealAction{NumShuffles: 3},
+	}))
+
+	ts := newTimeoutScheduler(cs.dbFactory, time.Hour)
+	ts.handleExpired(ctx, time.Now().Add(30*time.Second))
+	actG, err := getGame(ctx, db, g.ID)
+	require.NoError(t, err)
+	assert.False(t, actG.IsOver())
+
+	overdue, err = db.GetOverdueGames(ctx, time.Now().Add(2*time.Minute))
+	require.NoError(t, err)
+	assert.Contains(t, overdue, g.ID)
+}
+
+func TestTimeoutForfeitChecksTheGameInItsTransaction(t *testing.T) {
+	testCases := []struct {
+		msg string
+		// afterRead changes the game after the scheduler has read it
+		afterRead func(t *testing.T, db persistence.DB, g model.Game)
+		expOver   bool
+	}{{
+		msg:       `still overdue`,
+		afterRead: func(*testing.T, persistence.DB, model.Game) {},
+		expOver:   true,
+	}, {
+		msg: `the player moved`,
+		afterRead: func(t *testing.T, db persistence.DB, g model.Game) {
+			require.NoError(t, handleAction(context.Background(), db, model.PlayerAction{
+				GameID:    g.ID,
+				ID:        g.Players[0].ID,
+				Overcomes: model.DealCards,
+				Action:    model.DealAction{NumShuffles: 3},
+			}))
+		},
+		expOver: false,
+	}, {
+		msg: `the deadline moved`,
+		afterRead: func(t *testing.T, db persistence.DB, g model.Game) {
+			require.NoError(t, db.SetMoveDeadline(context.Background(), g.ID, time.Now().Add(time.Minute)))
+		},
+		expOver: false,
+	}}
+
+	for _, tc := range testCases {
+		cs, _ := newServerAndRouter(t)
+		pIDs := seedPlayers(t, cs.dbFactory, 2)
+
+		ctx := context.Background()
+		db, err := cs.dbFactory.New(ctx)
+		require.NoError(t, err, tc.msg)
+
+		g, err := createGame(ctx, db, pIDs, model.GameSettings{
+			MoveTimeout: time.Minute,
+			OnTimeout:   model.ForfeitOnTimeout,
+		})
+		require.NoError(t, err, tc.msg)
+		require.NoError(t, db.SetMoveDeadline(ctx, g.ID, time.Now().Add(-time.Second)), tc.msg)
+
+		stale, err := getGame(ctx, db, g.ID)
+		require.NoError(t, err, tc.msg)
+		tc.afterRead(t, db, stale)
+
+		require.NoError(t, forfeitForBlocker(ctx, db, stale, time.Now()), tc.msg)
+		actG, err := getGame(ctx, db, g.ID)
+		require.NoError(t, err, tc.msg)
+		assert.Equal(t, tc.expOver, actG.IsOver(), tc.msg)
+
+		db.Close()
+	}
+}
+
+func TestTimeoutSchedulerPlaysWaitingForcedMoves(t *testing.T) {
+	cs, _ := newServerAndRouter(t)
+	pIDs := seedPlayers(t, cs.dbFactory, 2)
+
+	ctx := context.Background()
+	db, err := cs.dbFactory.New(ctx)
+	require.NoError(t, err)
+	defer db.Close()
+
+	g, err := createGame(ctx, db, pIDs, model.GameSettings{
+		MoveTimeout: time.Minute,
+		OnTimeout:   model.ForfeitOnTimeout,
+	})
+	require.NoError(t, err)
+
+	act := func(pID model.PlayerID, b model.Blocker, a interface{}) {
+		require.NoError(t, handleAction(ctx, db, model.PlayerAction{
+			GameID:    g.ID,
+			ID:        pID,
+			Overcomes: b,
+			Action:    a,
+		}))
+	}
+	act(g.CurrentDealer, model.DealCards, model.DealAction{NumShuffles: 3})
+	for _, pID := range pIDs {
+		cur, err := getGame(ctx, db, g.ID)
+		require.NoError(t, err)
+		act(pID, model.CribCard, model.BuildCribAction{Cards: cur.Hands[pID][:2]})
+	}
+	act(pIDs[1], model.CutCard, model.CutDeckAction{Percentage: 0.5})
+	for {
+		cur, err := getGame(ctx, db, g.ID)
+		require.NoError(t, err)
+		if cur.Phase != model.Pegging {
+			break
+		}
+		for pID, b := range cur.BlockingPlayers {
+			c := firstPlayableCard(cur, pID)
+			act(pID, b, model.PegAction{Card: c, SayGo: c == model.Card{}})
+		}
+	}
+
+	// the player turns on auto-play while the game is waiting on them to count
+	cur, err := getGame(ctx, db, g.ID)
+	require.NoError(t, err)
+	require.Equal(t, model.Counting, cur.Phase)
+	require.Len(t, cur.BlockingPlayers, 1)
+	var counter model.PlayerID
+	for pID := range cur.BlockingPlayers {
+		counter = pID
+	}
+	pm := interaction.New(counter, interaction.Means{Mode: interaction.Unknown})
+	pm.AutoPlay = true
+	require.NoError(t, saveInteraction(ctx, db, pm))
+
+	// so their hand is counted for them instead of them forfeiting
+	ts := newTimeoutScheduler(cs.dbFactory, time.Hour)
+	ts.handleExpired(ctx, time.Now().Add(2*time.Minute))
+	actG, err := getGame(ctx, db, g.ID)
+	require.NoError(t, err)
+	assert.False(t, actG.IsOver())
+	require.Greater(t, actG.NumActions(), cur.NumActions())
+	next := actG.Actions[cur.NumActions()]
+	assert.Equal(t, counter, next.ID)
+	assert.Equal(t, model.CountHand, next.Overcomes)
+	assert.True(t, next.Auto)
+	assert.NotContains(t, actG.BlockingPlayers, counter)
+}
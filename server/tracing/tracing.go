@@ -0,0 +1,126 @@
+// Package tracing has the OpenTelemetry tracer that the server records its spans with.
+// Until Setup is called, the spans are started but never exported.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpgrpc"
+	"go.opentelemetry.io/otel/exporters/stdout"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+)
+
+const (
+	// ServiceName is what the server calls itself in its traces
+	ServiceName = `cribbage`
+
+	tracerName = `github.com/joshprzybyszewski/cribbage/server`
+)
+
+const (
+	// NoExporter doesn't export the spans anywhere
+	NoExporter = ``
+	// OTLPExporter sends the spans over gRPC to an OpenTelemetry collector
+	OTLPExporter = `otlp`
+	// StdoutExporter writes the spans to stdout, for looking at them locally
+	StdoutExporter = `stdout`
+)
+
+// Setup starts exporting the server's spans with the named exporter. The endpoint is
+// the collector's address for the OTLP exporter. The returned func flushes any spans
+// which haven't been exported yet, and should be called before the server exits.
+func Setup(ctx context.Context, exporter, endpoint string) (func(context.Context) error, error) {
+	var exp sdktrace.SpanExporter
+	switch exporter {
+	case NoExporter:
+		return func(context.Context) error { return nil }, nil
+	case OTLPExporter:
+		opts := []otlpgrpc.Option{otlpgrpc.WithInsecure()}
+		if endpoint != `` {
+			opts = append(opts, otlpgrpc.WithEndpoint(endpoint))
+		}
+		otlpExp, err := otlp.NewExporter(ctx, otlpgrpc.NewDriver(opts...))
+		if err != nil {
+			return nil, err
+		}
+		exp = otlpExp
+	case StdoutExporter:
+		stdoutExp, err := stdout.NewExporter(
+			stdout.WithWriter(os.Stdout),
+			stdout.WithPrettyPrint(),
+			stdout.WithoutMetricExport(),
+		)
+		if err != nil {
+			return nil, err
+		}
+		exp = stdoutExp
+	default:
+		return nil, fmt.Errorf(`trace exporter %q not supported. Currently supported: "otlp" and "stdout"`, exporter)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.ServiceNameKey.String(ServiceName),
+		)),
+	)
+	Use(tp)
+
+	return tp.Shutdown, nil
+}
+
+// Use makes the server record its spans with the TracerProvider
+func Use(tp trace.TracerProvider) {
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+}
+
+// Start starts a span as a child of the one in the context (if there is one).
+// The caller needs to End the span.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End marks the span as failed if there was an error, and then ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func GameID(gID model.GameID) attribute.KeyValue {
+	return attribute.Int64(`cribbage.game_id`, int64(gID))
+}
+
+func PlayerID(pID model.PlayerID) attribute.KeyValue {
+	return attribute.String(`cribbage.player_id`, string(pID))
+}
+
+func LobbyID(lID model.LobbyID) attribute.KeyValue {
+	return attribute.Int64(`cribbage.lobby_id`, int64(lID))
+}
+
+func Blocker(b model.Blocker) attribute.KeyValue {
+	return attribute.String(`cribbage.blocker`, b.String())
+}
+
+func Phase(p model.Phase) attribute.KeyValue {
+	return attribute.String(`cribbage.phase`, p.String())
+}
@@ -0,0 +1,56 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+)
+
+func TestSetup(t *testing.T) {
+	ctx := context.Background()
+
+	stop, err := Setup(ctx, NoExporter, ``)
+	require.NoError(t, err)
+	assert.NoError(t, stop(ctx))
+
+	_, err = Setup(ctx, `jaeger`, ``)
+	assert.EqualError(t, err, `trace exporter "jaeger" not supported. Currently supported: "otlp" and "stdout"`)
+}
+
+func TestStartAndEnd(t *testing.T) {
+	exp := tracetest.NewInMemoryExporter()
+	Use(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp)))
+	defer Use(trace.NewNoopTracerProvider())
+
+	ctx, parent := Start(context.Background(), `parent`, GameID(5))
+	_, ok := Start(ctx, `ok`, PlayerID(`alice`), Blocker(model.PegCard))
+	End(ok, nil)
+	_, failed := Start(ctx, `failed`, Phase(model.Pegging))
+	End(failed, errors.New(`could not peg`))
+	End(parent, nil)
+
+	spans := exp.GetSpans()
+	require.Len(t, spans, 3)
+
+	assert.Equal(t, `ok`, spans[0].Name)
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent.SpanID())
+	assert.Equal(t, codes.Unset, spans[0].StatusCode)
+	assert.Contains(t, spans[0].Attributes, PlayerID(`alice`))
+
+	assert.Equal(t, `failed`, spans[1].Name)
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[1].Parent.SpanID())
+	assert.Equal(t, codes.Error, spans[1].StatusCode)
+	assert.Equal(t, `could not peg`, spans[1].StatusMessage)
+
+	assert.Equal(t, `parent`, spans[2].Name)
+	assert.Contains(t, spans[2].Attributes, GameID(5))
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/server/persistence/instrumented"
+	"github.com/joshprzybyszewski/cribbage/server/tracing"
+)
+
+func recordSpans(t *testing.T) *tracetest.InMemoryExporter {
+	exp := tracetest.NewInMemoryExporter()
+	tracing.Use(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp)))
+	t.Cleanup(func() {
+		tracing.Use(trace.NewNoopTracerProvider())
+	})
+	return exp
+}
+
+// spansByName returns the spans, keyed by their names. When there are many spans
+// with the same name, it keeps the last one.
+func spansByName(exp *tracetest.InMemoryExporter) map[string]*sdktrace.SpanSnapshot {
+	spans := map[string]*sdktrace.SpanSnapshot{}
+	for _, s := range exp.GetSpans() {
+		spans[s.Name] = s
+	}
+	return spans
+}
+
+func TestHandleActionIsTraced(t *testing.T) {
+	cs, _ := newServerAndRouter(t)
+	pIDs := seedPlayers(t, cs.dbFactory, 2)
+	dbf := instrumented.NewFactory(cs.dbFactory, `memory`)
+
+	exp := recordSpans(t)
+	ctx, root := tracing.Start(context.Background(), `request`)
+	db, err := dbf.New(ctx)
+	require.NoError(t, err)
+	defer db.Close()
+
+	g, err := createGame(ctx, db, pIDs, model.GameSettings{})
+	require.NoError(t, err)
+	exp.Reset()
+
+	require.NoError(t, handleAction(ctx, db, model.PlayerAction{
+		GameID:    g.ID,
+		ID:        g.CurrentDealer,
+		Overcomes: model.DealCards,
+		Action:    model.DealAction{NumShuffles: 3},
+	}))
+	root.End()
+
+	spans := spansByName(exp)
+	parentOf := func(name string) string {
+		s, ok := spans[name]
+		require.True(t, ok, `missing span %q`, name)
+		for _, p := range exp.GetSpans() {
+			if p.SpanContext.SpanID() == s.Parent.SpanID() {
+				return p.Name
+			}
+		}
+		return ``
+	}
+
+	assert.Equal(t, `request`, parentOf(`handleAction`))
+	assert.Equal(t, `handleAction`, parentOf(`play.HandleAction`))
+	assert.Equal(t, `play.HandleAction`, parentOf(`play.PhaseHandler.HandleAction`))
+	assert.Equal(t, `play.HandleAction`, parentOf(`play.PhaseHandler.Start`))
+	// dealing starts the crib, which lets the players know that they need to toss
+	assert.Equal(t, `play.PhaseHandler.Start`, parentOf(`interaction.NotifyBlocking`))
+	assert.Equal(t, `handleAction`, parentOf(`persistence.games.GetGame`))
+	assert.Equal(t, `handleAction`, parentOf(`persistence.games.SaveGame`))
+	assert.Equal(t, `handleAction`, parentOf(`persistence.transactions.Start`))
+	assert.Equal(t, `handleAction`, parentOf(`persistence.transactions.Commit`))
+
+	for _, s := range exp.GetSpans() {
+		assert.Equal(t, root.SpanContext().TraceID(), s.SpanContext.TraceID(), s.Name)
+	}
+}
+
+func TestHandleActionTracesRejections(t *testing.T) {
+	cs, _ := newServerAndRouter(t)
+	pIDs := seedPlayers(t, cs.dbFactory, 2)
+
+	exp := recordSpans(t)
+	ctx := context.Background()
+	db, err := cs.dbFactory.New(ctx)
+	require.NoError(t, err)
+	defer db.Close()
+
+	g, err := createGame(ctx, db, pIDs, model.GameSettings{})
+	require.NoError(t, err)
+	exp.Reset()
+
+	require.Error(t, handleAction(ctx, db, model.PlayerAction{
+		GameID:    g.ID,
+		ID:        g.CurrentDealer,
+		Overcomes: model.CutCard,
+		Action:    model.CutDeckAction{Percentage: 0.5},
+	}))
+
+	spans := spansByName(exp)
+	for _, name := range []string{`handleAction`, `play.HandleAction`, `play.PhaseHandler.HandleAction`} {
+		require.Contains(t, spans, name)
+		assert.Equal(t, codes.Error, spans[name].StatusCode, name)
+		assert.Contains(t, spans[name].StatusMessage, `Should overcome`, name)
+	}
+}
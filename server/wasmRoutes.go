@@ -1,7 +1,6 @@
 package server
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 	"strings"
@@ -29,7 +28,7 @@ func handleWasmGetUser(c *gin.Context) {
 }
 
 func (cs *cribbageServer) handleWasmGetUsername(c *gin.Context) {
-	ctx := context.Background()
+	ctx := c.Request.Context()
 	// serve up a list of games this user is in
 	username := c.Param(`username`)
 	pID := model.PlayerID(username)
@@ -116,7 +115,7 @@ func (cs *cribbageServer) handleWasmGetUsernameGame(c *gin.Context) { //nolint:g
 		return
 	}
 
-	ctx := context.Background()
+	ctx := c.Request.Context()
 	db, err := cs.dbFactory.New(ctx)
 	if err != nil {
 		c.String(http.StatusInternalServerError, `dbFactory.New() error: %s`, err)
@@ -56,3 +56,28 @@ func TestString(t *testing.T) {
 		assert.Equal(t, randLen, len(s))
 	}
 }
+
+func TestSeeded(t *testing.T) {
+	a := NewSeeded(`some seed`)
+	b := NewSeeded(`some seed`)
+	c := NewSeeded(`another seed`)
+
+	same := true
+	for i := 0; i < 100; i++ {
+		an := a.Intn(52)
+		assert.GreaterOrEqual(t, an, 0)
+		assert.Less(t, an, 52)
+		assert.Equal(t, an, b.Intn(52))
+		if an != c.Intn(52) {
+			same = false
+		}
+	}
+	assert.False(t, same)
+	assert.Zero(t, a.Intn(0))
+}
+
+func TestNewSeed(t *testing.T) {
+	s := NewSeed()
+	assert.Len(t, s, seedLength)
+	assert.NotEqual(t, s, NewSeed())
+}
@@ -0,0 +1,60 @@
+package rand
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+)
+
+const (
+	// With 63 characters to choose from, this is just over 256 bits of randomness
+	seedLength = 43
+)
+
+// NewSeed returns a random string which is long enough to seed a Seeded
+func NewSeed() string {
+	return String(seedLength)
+}
+
+// Seeded is a deterministic source of random numbers. Anyone who knows the
+// seed gets the same numbers, which lets them recompute a shuffle that used it.
+type Seeded struct {
+	seed    []byte
+	counter uint64
+}
+
+func NewSeeded(seed string) *Seeded {
+	return &Seeded{
+		seed: []byte(seed),
+	}
+}
+
+// Intn returns a number between 0 and max, not including max
+func (s *Seeded) Intn(max int) int {
+	if max <= 0 {
+		return 0
+	}
+
+	// throw away the largest numbers so that the smaller ones aren't more likely
+	m := uint64(max)
+	limit := math.MaxUint64 - math.MaxUint64%m
+	for {
+		n := s.next()
+		if n < limit {
+			return int(n % m)
+		}
+	}
+}
+
+// next returns the first 8 bytes of the hash of the seed and how many
+// numbers have been made from it so far
+func (s *Seeded) next() uint64 {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], s.counter)
+	s.counter++
+
+	h := sha256.New()
+	_, _ = h.Write(s.seed)
+	_, _ = h.Write(counter[:])
+	return binary.BigEndian.Uint64(h.Sum(nil)[:8])
+}
@@ -3,31 +3,16 @@
 package actions
 
 import (
-	"bytes"
-	"encoding/json"
-	"errors"
-	"fmt"
-	"io"
-	"io/ioutil"
 	"net/http"
-	"strings"
 
 	"honnef.co/go/js/dom/v2"
 
 	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/network/client"
 )
 
 func Send(gID model.GameID, pa model.PlayerAction) error {
-	b, err := json.Marshal(pa)
-	if err != nil {
-		return err
-	}
-	buf := bytes.NewBuffer(b)
-
-	url := fmt.Sprintf("/action")
-
-	_, err = MakeRequest(`POST`, url, buf)
-	return err
+	return Client().SendAction(pa)
 }
 
 func getServerDomain() string {
@@ -35,32 +20,7 @@ func getServerDomain() string {
 	return loc.Protocol() + `//` + loc.Host()
 }
 
-func MakeRequest(method, apiURL string, data io.Reader) ([]byte, error) {
-	urlStr := getServerDomain() + apiURL
-	req, err := http.NewRequest(method, urlStr, data)
-	if err != nil {
-		return nil, err
-	}
-
-	server := &http.Client{}
-	response, err := server.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer response.Body.Close()
-
-	bytes, err := ioutil.ReadAll(response.Body)
-
-	if response.StatusCode != http.StatusOK {
-		contentType := response.Header.Get("Content-Type")
-		if strings.Contains(contentType, `text/plain`) {
-			return nil, errors.New(`bad response: "` + string(bytes) + `"`)
-		}
-
-		return nil, errors.New(`bad response from server`)
-	} else if err != nil {
-		return nil, err
-	}
-
-	return bytes, nil
+// Client returns a client for the server that served this page
+func Client() *client.Client {
+	return client.New(getServerDomain(), &http.Client{})
 }
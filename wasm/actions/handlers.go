@@ -13,6 +13,8 @@ import (
 func GetDealAction(gID model.GameID, pID model.PlayerID) model.PlayerAction {
 	da := model.DealAction{
 		NumShuffles: rand.Intn(10) + 1,
+		// provably fair games mix this into the shuffle, so the server can't pick the deck alone
+		Entropy: rand.String(16),
 	}
 
 	return model.PlayerAction{
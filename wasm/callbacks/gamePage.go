@@ -22,6 +22,8 @@ func SetupGamePage(g model.Game, myID model.PlayerID) []Releaser {
 	})
 	r = append(r, listener)
 
+	showShuffleProof(g, myID)
+
 	if _, ok := g.BlockingPlayers[myID]; !ok {
 		// Nothing to do when I'm not blocking
 		// TODO this may not always be true
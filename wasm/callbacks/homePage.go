@@ -3,8 +3,6 @@
 package callbacks
 
 import (
-	"bytes"
-	"encoding/json"
 
 	"honnef.co/go/js/dom/v2"
 
@@ -68,16 +66,9 @@ func getListenersForCreateUser() []Releaser {
 		}
 
 		go func() {
-			inputBytes, err := json.Marshal(cpr)
-			respBytes, err := actions.MakeRequest(`POST`, `/create/player`, bytes.NewBuffer(inputBytes))
+			me, err := actions.Client().CreatePlayer(cpr)
 			if err != nil {
-				println("Got error on MakeRequest: " + err.Error())
-				return
-			}
-			me := network.CreatePlayerResponse{}
-			err = json.Unmarshal(respBytes, &me)
-			if err != nil {
-				println("Got error on json.Unmarshal CreatePlayerResponse: " + err.Error())
+				println("Got error on CreatePlayer: " + err.Error())
 				return
 			}
 			myUsername := string(me.Player.ID)
@@ -0,0 +1,90 @@
+// +build js,wasm
+
+package callbacks
+
+import (
+	"errors"
+
+	"honnef.co/go/js/dom/v2"
+
+	"github.com/joshprzybyszewski/cribbage/model"
+	"github.com/joshprzybyszewski/cribbage/wasm/consts"
+)
+
+// showShuffleProof tells the player about the round's shuffle in provably fair games.
+// Once the server reveals its seed, we check the shuffle ourselves instead of trusting it.
+func showShuffleProof(g model.Game, myID model.PlayerID) {
+	if len(g.Shuffles) == 0 {
+		return
+	}
+
+	elem := dom.GetWindow().Document().GetElementByID(consts.ShuffleProofID)
+	if elem == nil {
+		return
+	}
+
+	sp := g.Shuffles[len(g.Shuffles)-1]
+	if !sp.IsRevealed() {
+		elem.SetTextContent(`The server committed to this round's shuffle: ` + sp.Commitment)
+		return
+	}
+
+	if err := verifyShuffle(g, myID, sp); err != nil {
+		elem.SetTextContent(`This round's shuffle could not be verified: ` + err.Error())
+		return
+	}
+	elem.SetTextContent(`This round's shuffle was verified: your hand and the cut card came from the deck that the server committed to`) //nolint:lll
+}
+
+func verifyShuffle(g model.Game, myID model.PlayerID, sp model.ShuffleProof) error {
+	order, err := model.VerifyShuffle(sp)
+	if err != nil {
+		return err
+	}
+
+	numPlayers := len(g.Players)
+	myIndex, dealerIndex := -1, -1
+	for i, p := range g.Players {
+		if p.ID == myID {
+			myIndex = i
+		}
+		if p.ID == g.CurrentDealer {
+			dealerIndex = i
+		}
+	}
+	if myIndex < 0 || dealerIndex < 0 {
+		return errors.New(`could not find your seat at the table`)
+	}
+
+	// the cards are dealt starting left of the dealer
+	seat := (myIndex - dealerIndex - 1 + numPlayers) % numPlayers
+	if !containsAll(model.DealtCards(order, numPlayers, seat), g.Hands[myID]) {
+		return errors.New(`your hand was not dealt from the committed deck`)
+	}
+
+	if g.CutCard == (model.Card{}) {
+		return nil
+	}
+	cut, err := model.CutCardFrom(order, numPlayers, sp.CutPercentage)
+	if err != nil {
+		return err
+	}
+	if cut != g.CutCard {
+		return errors.New(`the cut card was not where the deck was cut`)
+	}
+
+	return nil
+}
+
+func containsAll(cards, subset []model.Card) bool {
+	has := make(map[model.Card]struct{}, len(cards))
+	for _, c := range cards {
+		has[c] = struct{}{}
+	}
+	for _, c := range subset {
+		if _, ok := has[c]; !ok {
+			return false
+		}
+	}
+	return true
+}
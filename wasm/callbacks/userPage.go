@@ -3,8 +3,6 @@
 package callbacks
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
 
 	"honnef.co/go/js/dom/v2"
@@ -68,20 +66,9 @@ func getListenersForCreateGame(myID model.PlayerID) []Releaser {
 		}
 
 		go func() {
-			inputBytes, err := json.Marshal(createReq)
+			cgr, err := actions.Client().CreateGame(createReq)
 			if err != nil {
-				println("Got error on json.Marshal: " + err.Error())
-				return
-			}
-			respBytes, err := actions.MakeRequest(`POST`, `/create/game`, bytes.NewBuffer(inputBytes))
-			if err != nil {
-				println("Got error on MakeRequest: " + err.Error())
-				return
-			}
-			cgr := network.CreateGameResponse{}
-			err = json.Unmarshal(respBytes, &cgr)
-			if err != nil {
-				println("Got error on json.Unmarshal CreateGameResponse: " + err.Error())
+				println("Got error on CreateGame: " + err.Error())
 				return
 			}
 			gIDStr := fmt.Sprintf("%v", cgr.ID)
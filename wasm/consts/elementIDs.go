@@ -23,4 +23,6 @@ const (
 	PegButtonID         string = `pegButton`
 	CountHandPtsInputID string = `handPtsInput`
 	CountCribPtsInputID string = `cribPtsInput`
+
+	ShuffleProofID string = `shuffleProof`
 )
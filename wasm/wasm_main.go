@@ -3,8 +3,6 @@
 package main
 
 import (
-	"encoding/json"
-	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
@@ -111,14 +109,7 @@ func startGamePage(gID model.GameID, myID model.PlayerID) ([]callbacks.Releaser,
 }
 
 func requestGame(gID model.GameID, myID model.PlayerID) (model.Game, error) {
-	url := fmt.Sprintf("/game/%v?player=%s", gID, myID)
-	respBytes, err := actions.MakeRequest(`GET`, url, nil)
-	if err != nil {
-		return model.Game{}, err
-	}
-
-	ggr := network.GetGameResponse{}
-	err = json.Unmarshal(respBytes, &ggr)
+	ggr, err := actions.Client().GetGameForPlayer(gID, myID)
 	if err != nil {
 		return model.Game{}, err
 	}